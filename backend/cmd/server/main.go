@@ -10,11 +10,13 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/hotel-booking-system/backend/internal/events"
 	"github.com/hotel-booking-system/backend/internal/jobs"
 	"github.com/hotel-booking-system/backend/internal/router"
 	"github.com/hotel-booking-system/backend/pkg/cache"
 	"github.com/hotel-booking-system/backend/pkg/config"
 	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/hotel-booking-system/backend/pkg/storage"
 )
 
 func main() {
@@ -50,6 +52,15 @@ func main() {
 		log.Println("Redis not configured, running without cache")
 	}
 
+	// Initialize the blob store for room type images (optional - uploads are disabled without it)
+	blobs, err := storage.NewLocalBlobStore(cfg.Storage.UploadDir, cfg.Storage.URLPrefix)
+	if err != nil {
+		log.Printf("Warning: Failed to initialize blob store: %v (image uploads disabled)", err)
+		blobs = nil
+	} else {
+		log.Printf("Blob store ready in %s", cfg.Storage.UploadDir)
+	}
+
 	// Initialize and start night audit job
 	nightAudit := jobs.NewNightAuditJob(db)
 	if err := nightAudit.Start(); err != nil {
@@ -68,8 +79,57 @@ func main() {
 
 	log.Printf("Hold cleanup job scheduled (next run: %s)", holdCleanup.GetNextRunTime().Format("2006-01-02 15:04:05"))
 
+	// Initialize and start waitlist matcher (also woken when expired holds are released)
+	waitlistMatcher := jobs.NewWaitlistMatcherJob(db)
+	if err := waitlistMatcher.Start(); err != nil {
+		log.Fatalf("Failed to start waitlist matcher: %v", err)
+	}
+	defer waitlistMatcher.Stop()
+
+	log.Printf("Waitlist matcher scheduled (next run: %s)", waitlistMatcher.GetNextRunTime().Format("2006-01-02 15:04:05"))
+
+	// Initialize and start dynamic pricing job
+	dynamicPricing := jobs.NewDynamicPricingJob(db)
+	if err := dynamicPricing.Start(); err != nil {
+		log.Fatalf("Failed to start dynamic pricing job: %v", err)
+	}
+	defer dynamicPricing.Stop()
+
+	log.Printf("Dynamic pricing job scheduled (next run: %s)", dynamicPricing.GetNextRunTime().Format("2006-01-02 15:04:05"))
+
+	// Initialize and start out-of-order job (starts and ends out-of-order periods)
+	outOfOrder := jobs.NewOutOfOrderJob(db)
+	if err := outOfOrder.Start(); err != nil {
+		log.Fatalf("Failed to start out-of-order job: %v", err)
+	}
+	defer outOfOrder.Stop()
+
+	log.Printf("Out-of-order job scheduled (next run: %s)", outOfOrder.GetNextRunTime().Format("2006-01-02 15:04:05"))
+
+	// Initialize and start room pre-assignment job (picks rooms for the next day's arrivals)
+	roomPreAssignment := jobs.NewRoomPreAssignmentJob(db)
+	if err := roomPreAssignment.Start(); err != nil {
+		log.Fatalf("Failed to start room pre-assignment job: %v", err)
+	}
+	defer roomPreAssignment.Stop()
+
+	log.Printf("Room pre-assignment job scheduled (next run: %s)", roomPreAssignment.GetNextRunTime().Format("2006-01-02 15:04:05"))
+
+	// Initialize the room event bus (shared with the other instances through Redis when configured)
+	roomEvents := events.NewBus()
+	if redisCache != nil {
+		relay := events.NewRedisRelay(redisCache)
+		roomEvents.SetRelay(relay)
+
+		relayCtx, stopRelay := context.WithCancel(context.Background())
+		defer stopRelay()
+		go relay.Listen(relayCtx, roomEvents)
+
+		log.Println("Room events relayed through Redis")
+	}
+
 	// Setup router
-	r := router.Setup(cfg, db, redisCache, nightAudit, holdCleanup)
+	r := router.Setup(cfg, db, redisCache, blobs, nightAudit, holdCleanup, waitlistMatcher, dynamicPricing, outOfOrder, roomPreAssignment, roomEvents)
 
 	// Create HTTP server
 	addr := fmt.Sprintf("0.0.0.0:%s", cfg.Server.Port)
@@ -81,6 +141,9 @@ func main() {
 		MaxHeaderBytes: 1 << 20, // 1 MB
 	}
 
+	// End open room event streams so they do not hold up the shutdown
+	srv.RegisterOnShutdown(roomEvents.Close)
+
 	// Start server in a goroutine
 	go func() {
 		log.Printf("Starting server on %s (mode: %s)", addr, cfg.Server.GinMode)
@@ -0,0 +1,182 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// subscriberBuffer is the number of events a subscriber may fall behind by before
+// further events are dropped for it
+const subscriberBuffer = 64
+
+// Subscription receives the room events a staff role may see
+type Subscription struct {
+	Events <-chan models.RoomEvent
+
+	events chan models.RoomEvent
+	role   string
+	types  map[string]bool
+}
+
+// Bus fans room events out to subscribers in this process and, when a relay is
+// set, to the other instances of the API
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+	relay       Relay
+	origin      string
+	logger      *log.Logger
+}
+
+// Relay carries events between instances
+type Relay interface {
+	Publish(envelope Envelope) error
+}
+
+// Envelope is an event on its way between instances; Origin tells an instance its
+// own events apart
+type Envelope struct {
+	Origin string           `json:"origin"`
+	Event  models.RoomEvent `json:"event"`
+}
+
+// NewBus creates an event bus delivering events within this process
+func NewBus() *Bus {
+	origin := make([]byte, 8)
+	_, _ = rand.Read(origin)
+
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+		origin:      hex.EncodeToString(origin),
+		logger:      log.New(log.Writer(), "[EVENTS] ", log.LstdFlags),
+	}
+}
+
+// SetRelay makes published events reach the other instances through the relay
+func (b *Bus) SetRelay(relay Relay) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.relay = relay
+}
+
+// Subscribe starts receiving the events a role may see, optionally only events of
+// the given types. The subscription must be ended with Unsubscribe.
+func (b *Bus) Subscribe(role string, types []string) *Subscription {
+	ch := make(chan models.RoomEvent, subscriberBuffer)
+	sub := &Subscription{Events: ch, events: ch, role: role}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe stops a subscription and closes its channel
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Close ends every subscription, so open streams finish when the server shuts down
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// SubscriberCount returns the number of open subscriptions in this process
+func (b *Bus) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
+
+// Publish delivers an event to the subscribers in this process and hands it to the
+// relay for the other instances
+func (b *Bus) Publish(event models.RoomEvent) {
+	b.deliver(event)
+
+	b.mu.RLock()
+	relay := b.relay
+	b.mu.RUnlock()
+
+	if relay != nil {
+		if err := relay.Publish(Envelope{Origin: b.origin, Event: event}); err != nil {
+			b.logger.Printf("WARNING: failed to relay %s event of room %d: %v", event.Type, event.Room.RoomID, err)
+		}
+	}
+}
+
+// Receive delivers an event relayed from another instance; the bus's own events
+// are ignored since they were delivered when published
+func (b *Bus) Receive(envelope Envelope) {
+	if envelope.Origin == b.origin {
+		return
+	}
+	b.deliver(envelope.Event)
+}
+
+// deliver hands an event to every subscriber allowed to see it. A subscriber whose
+// buffer is full misses the event rather than holding up the others.
+func (b *Bus) deliver(event models.RoomEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if sub.types != nil && !sub.types[event.Type] {
+			continue
+		}
+		visible, ok := ForRole(event, sub.role)
+		if !ok {
+			continue
+		}
+		select {
+		case sub.events <- visible:
+		default:
+			b.logger.Printf("WARNING: dropped %s event of room %d for a slow %s subscriber",
+				event.Type, event.Room.RoomID, sub.role)
+		}
+	}
+}
+
+// ForRole returns the event as a staff role may see it. Front desk staff and managers
+// see every event with the guest's details; housekeepers see status changes,
+// arrivals and departures without guest details; engineers only see status changes.
+func ForRole(event models.RoomEvent, role string) (models.RoomEvent, bool) {
+	switch role {
+	case "RECEPTIONIST", "MANAGER":
+		return event, true
+	case "HOUSEKEEPER":
+		// Every event type, without guest details
+	case "ENGINEER":
+		if event.Type != models.RoomEventStatus {
+			return event, false
+		}
+	default:
+		return event, false
+	}
+
+	event.Room.GuestName = ""
+	event.Room.ExpectedCheckout = nil
+	return event, true
+}
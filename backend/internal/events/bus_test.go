@@ -0,0 +1,148 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roomEvent(eventType string) models.RoomEvent {
+	checkout := "2026-10-21"
+	return models.RoomEvent{
+		Type: eventType,
+		Room: models.RoomStatus{
+			RoomID:             7,
+			RoomNumber:         "301",
+			OccupancyStatus:    "Occupied",
+			HousekeepingStatus: "Dirty",
+			GuestName:          "Somchai Jaidee",
+			ExpectedCheckout:   &checkout,
+		},
+	}
+}
+
+// recordingRelay keeps the envelopes published through it
+type recordingRelay struct {
+	envelopes []Envelope
+	err       error
+}
+
+func (r *recordingRelay) Publish(envelope Envelope) error {
+	r.envelopes = append(r.envelopes, envelope)
+	return r.err
+}
+
+func TestForRole(t *testing.T) {
+	event, ok := ForRole(roomEvent(models.RoomEventArrival), "RECEPTIONIST")
+	require.True(t, ok)
+	assert.Equal(t, "Somchai Jaidee", event.Room.GuestName)
+
+	event, ok = ForRole(roomEvent(models.RoomEventDeparture), "HOUSEKEEPER")
+	require.True(t, ok)
+	assert.Empty(t, event.Room.GuestName)
+	assert.Nil(t, event.Room.ExpectedCheckout)
+
+	_, ok = ForRole(roomEvent(models.RoomEventArrival), "ENGINEER")
+	assert.False(t, ok)
+
+	event, ok = ForRole(roomEvent(models.RoomEventStatus), "ENGINEER")
+	require.True(t, ok)
+	assert.Empty(t, event.Room.GuestName)
+
+	_, ok = ForRole(roomEvent(models.RoomEventStatus), "GUEST")
+	assert.False(t, ok)
+}
+
+func TestBusFiltersByRoleAndType(t *testing.T) {
+	bus := NewBus()
+	frontDesk := bus.Subscribe("RECEPTIONIST", nil)
+	engineer := bus.Subscribe("ENGINEER", nil)
+	departures := bus.Subscribe("MANAGER", []string{models.RoomEventDeparture})
+	defer bus.Unsubscribe(frontDesk)
+	defer bus.Unsubscribe(engineer)
+	defer bus.Unsubscribe(departures)
+
+	bus.Publish(roomEvent(models.RoomEventArrival))
+	bus.Publish(roomEvent(models.RoomEventDeparture))
+
+	assert.Len(t, frontDesk.Events, 2)
+	assert.Len(t, engineer.Events, 0)
+	require.Len(t, departures.Events, 1)
+	assert.Equal(t, models.RoomEventDeparture, (<-departures.Events).Type)
+}
+
+func TestBusDropsEventsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	slow := bus.Subscribe("MANAGER", nil)
+	defer bus.Unsubscribe(slow)
+
+	for i := 0; i < subscriberBuffer+5; i++ {
+		bus.Publish(roomEvent(models.RoomEventStatus))
+	}
+
+	assert.Len(t, slow.Events, subscriberBuffer)
+}
+
+func TestBusUnsubscribeClosesChannel(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe("MANAGER", nil)
+	assert.Equal(t, 1, bus.SubscriberCount())
+
+	bus.Unsubscribe(sub)
+	bus.Unsubscribe(sub)
+	_, open := <-sub.Events
+	assert.False(t, open)
+	assert.Equal(t, 0, bus.SubscriberCount())
+
+	bus.Publish(roomEvent(models.RoomEventStatus))
+}
+
+func TestBusCloseEndsSubscriptions(t *testing.T) {
+	bus := NewBus()
+	first := bus.Subscribe("MANAGER", nil)
+	second := bus.Subscribe("HOUSEKEEPER", nil)
+
+	bus.Close()
+	bus.Unsubscribe(first)
+
+	_, open := <-first.Events
+	assert.False(t, open)
+	_, open = <-second.Events
+	assert.False(t, open)
+	assert.Equal(t, 0, bus.SubscriberCount())
+}
+
+func TestBusRelaysToOtherInstances(t *testing.T) {
+	relay := &recordingRelay{}
+	first, second := NewBus(), NewBus()
+	first.SetRelay(relay)
+
+	local := first.Subscribe("MANAGER", nil)
+	remote := second.Subscribe("MANAGER", nil)
+	defer first.Unsubscribe(local)
+	defer second.Unsubscribe(remote)
+
+	first.Publish(roomEvent(models.RoomEventStatus))
+	require.Len(t, relay.envelopes, 1)
+
+	// Relayed events are delivered by the other instances only
+	first.Receive(relay.envelopes[0])
+	second.Receive(relay.envelopes[0])
+
+	assert.Len(t, local.Events, 1)
+	assert.Len(t, remote.Events, 1)
+}
+
+func TestBusDeliversLocallyWhenRelayFails(t *testing.T) {
+	bus := NewBus()
+	bus.SetRelay(&recordingRelay{err: errors.New("connection refused")})
+	sub := bus.Subscribe("MANAGER", nil)
+	defer bus.Unsubscribe(sub)
+
+	bus.Publish(roomEvent(models.RoomEventStatus))
+
+	assert.Len(t, sub.Events, 1)
+}
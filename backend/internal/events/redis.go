@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hotel-booking-system/backend/pkg/cache"
+)
+
+// RoomEventsChannel is the Redis channel instances share room events on
+const RoomEventsChannel = "room_events"
+
+// redisResubscribeDelay is how long to wait before subscribing again after the
+// subscription to Redis failed
+const redisResubscribeDelay = 5 * time.Second
+
+// RedisRelay carries room events between instances over Redis pub/sub
+type RedisRelay struct {
+	cache *cache.RedisCache
+}
+
+// NewRedisRelay creates a relay publishing on RoomEventsChannel
+func NewRedisRelay(redisCache *cache.RedisCache) *RedisRelay {
+	return &RedisRelay{cache: redisCache}
+}
+
+// Publish sends an event to the other instances
+func (r *RedisRelay) Publish(envelope Envelope) error {
+	return r.cache.Publish(RoomEventsChannel, envelope)
+}
+
+// Listen delivers the events other instances publish to the bus until ctx is done,
+// subscribing again whenever the connection to Redis is lost
+func (r *RedisRelay) Listen(ctx context.Context, bus *Bus) {
+	for {
+		err := r.cache.Subscribe(ctx, RoomEventsChannel, func(payload []byte) {
+			var envelope Envelope
+			if err := json.Unmarshal(payload, &envelope); err != nil {
+				bus.logger.Printf("WARNING: ignored malformed room event: %v", err)
+				return
+			}
+			bus.Receive(envelope)
+		})
+		if err != nil {
+			bus.logger.Printf("WARNING: room event subscription lost: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(redisResubscribeDelay):
+		}
+	}
+}
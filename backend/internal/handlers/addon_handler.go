@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/internal/service"
+)
+
+// AddOnHandler handles add-on catalog HTTP requests
+type AddOnHandler struct {
+	addOnService *service.AddOnService
+}
+
+// NewAddOnHandler creates a new add-on handler
+func NewAddOnHandler(addOnService *service.AddOnService) *AddOnHandler {
+	return &AddOnHandler{
+		addOnService: addOnService,
+	}
+}
+
+// GetActiveAddOns retrieves the add-ons currently on sale
+// GET /api/addons
+func (h *AddOnHandler) GetActiveAddOns(c *gin.Context) {
+	h.listAddOns(c, true)
+}
+
+// GetAllAddOns retrieves the whole add-on catalog including inactive add-ons
+// GET /api/addons/all
+func (h *AddOnHandler) GetAllAddOns(c *gin.Context) {
+	h.listAddOns(c, false)
+}
+
+func (h *AddOnHandler) listAddOns(c *gin.Context, activeOnly bool) {
+	addons, err := h.addOnService.GetAllAddOns(c.Request.Context(), activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve add-ons",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    addons,
+	})
+}
+
+// GetAddOnByID retrieves an add-on by ID
+// GET /api/addons/:id
+func (h *AddOnHandler) GetAddOnByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid add-on ID",
+		})
+		return
+	}
+
+	addon, err := h.addOnService.GetAddOnByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Add-on not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    addon,
+	})
+}
+
+// CreateAddOn creates a new add-on
+// POST /api/addons
+func (h *AddOnHandler) CreateAddOn(c *gin.Context) {
+	var req models.CreateAddOnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	addon, err := h.addOnService.CreateAddOn(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to create add-on",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    addon,
+		"message": "Add-on created successfully",
+	})
+}
+
+// UpdateAddOn updates an add-on
+// PUT /api/addons/:id
+func (h *AddOnHandler) UpdateAddOn(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid add-on ID",
+		})
+		return
+	}
+
+	var req models.UpdateAddOnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	addon, err := h.addOnService.UpdateAddOn(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to update add-on",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    addon,
+		"message": "Add-on updated successfully",
+	})
+}
+
+// DeactivateAddOn stops selling an add-on
+// DELETE /api/addons/:id
+func (h *AddOnHandler) DeactivateAddOn(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid add-on ID",
+		})
+		return
+	}
+
+	if err := h.addOnService.DeactivateAddOn(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to deactivate add-on",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Add-on deactivated successfully",
+	})
+}
+
+// GetRatePlanInclusions retrieves the add-ons included in a rate plan
+// GET /api/pricing/plans/:id/inclusions
+func (h *AddOnHandler) GetRatePlanInclusions(c *gin.Context) {
+	ratePlanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid rate plan ID",
+		})
+		return
+	}
+
+	addons, err := h.addOnService.GetRatePlanInclusions(c.Request.Context(), ratePlanID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve rate plan inclusions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    addons,
+	})
+}
+
+// SetRatePlanInclusions replaces the add-ons included in a rate plan
+// PUT /api/pricing/plans/:id/inclusions
+func (h *AddOnHandler) SetRatePlanInclusions(c *gin.Context) {
+	ratePlanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid rate plan ID",
+		})
+		return
+	}
+
+	var req models.SetRatePlanInclusionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	addons, err := h.addOnService.SetRatePlanInclusions(c.Request.Context(), ratePlanID, req.AddOnIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to update rate plan inclusions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    addons,
+		"message": "Rate plan inclusions updated successfully",
+	})
+}
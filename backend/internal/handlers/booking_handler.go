@@ -1,14 +1,22 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/middleware"
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/internal/service"
+	"github.com/hotel-booking-system/backend/pkg/utils"
 )
 
+// maxRoomingListSize limits rooming list uploads to 5 MB
+const maxRoomingListSize = 5 << 20
+
 // BookingHandler handles booking-related HTTP requests
 type BookingHandler struct {
 	bookingService *service.BookingService
@@ -43,6 +51,10 @@ func (h *BookingHandler) CreateBookingHold(c *gin.Context) {
 	}
 
 	if !response.Success {
+		if len(response.Restrictions) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": response.Message, "restrictions": response.Restrictions})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": response.Message})
 		return
 	}
@@ -67,6 +79,44 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 
 	response, err := h.bookingService.CreateBooking(c.Request.Context(), guestID, &req)
 	if err != nil {
+		var restrictionErr *models.RestrictionError
+		if errors.As(err, &restrictionErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "restrictions": restrictionErr.Violations})
+			return
+		}
+		var ineligibleErr *models.RatePlanIneligibleError
+		if errors.As(err, &ineligibleErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "reasons": ineligibleErr.Reasons})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// CreateDayUseBooking handles POST /api/bookings/day-use
+// Works with or without authentication (guest booking)
+func (h *BookingHandler) CreateDayUseBooking(c *gin.Context) {
+	var req models.CreateDayUseBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get guest ID from context if authenticated (optional for guest bookings)
+	var guestID int
+	if userID, exists := c.Get("user_id"); exists {
+		guestID = userID.(int)
+	}
+
+	response, err := h.bookingService.CreateDayUseBooking(c.Request.Context(), guestID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrDayUseSoldOut) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -222,6 +272,235 @@ func (h *BookingHandler) GetBookingByID(c *gin.Context) {
 	c.JSON(http.StatusOK, booking)
 }
 
+// GetBookingFolio handles GET /api/bookings/:id/folio
+// Guests see their own bookings; staff can see any booking
+func (h *BookingHandler) GetBookingFolio(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	guestID := userID.(int)
+	if middleware.IsStaff(c) {
+		guestID = 0
+	}
+
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	folio, err := h.bookingService.GetBookingFolio(c.Request.Context(), bookingID, guestID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	if folio == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, folio)
+}
+
+// GetBillingSchedule handles GET /api/bookings/:id/billing-schedule
+// Splits the booking into invoices by the billing cycle of its rate plan (e.g. monthly for long stays)
+func (h *BookingHandler) GetBillingSchedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	guestID := userID.(int)
+	if middleware.IsStaff(c) {
+		guestID = 0
+	}
+
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	schedule, err := h.bookingService.GetBillingSchedule(c.Request.Context(), bookingID, guestID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	if schedule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// GetUpgradeOffers handles GET /api/bookings/:id/upgrade-offers
+// Lists the room upgrades the guest can buy, with prices, for rooms that have not arrived yet
+func (h *BookingHandler) GetUpgradeOffers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	guestID := userID.(int)
+	if middleware.IsStaff(c) {
+		guestID = 0
+	}
+
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	offers, err := h.bookingService.GetUpgradeOffers(c.Request.Context(), bookingID, guestID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if offers == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offers": offers})
+}
+
+// AcceptUpgradeOffer handles POST /api/bookings/:id/upgrade-offers/accept
+// Buys an upgrade offer at its current price; the charge is added to the booking total
+func (h *BookingHandler) AcceptUpgradeOffer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	guestID := userID.(int)
+	if middleware.IsStaff(c) {
+		guestID = 0
+	}
+
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var req models.AcceptUpgradeOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upgrade, err := h.bookingService.AcceptUpgradeOffer(c.Request.Context(), bookingID, guestID, &req)
+	if err != nil {
+		switch {
+		case err.Error() == "booking not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		case strings.HasPrefix(err.Error(), "unauthorized"):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "failed to"):
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, upgrade)
+}
+
+// RequestStayTime handles POST /api/bookings/:id/stay-time-requests
+// Asks to check in early or check out late; the request is quoted at the tier fee and
+// waits for the front desk to approve it
+func (h *BookingHandler) RequestStayTime(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	guestID := userID.(int)
+	if middleware.IsStaff(c) {
+		guestID = 0
+	}
+
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var req models.CreateStayTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.bookingService.RequestStayTime(c.Request.Context(), bookingID, guestID, &req)
+	if err != nil {
+		stayTimeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+// CancelStayTimeRequest handles DELETE /api/bookings/:id/stay-time-requests/:requestId
+// Withdraws an early check-in or late check-out request; an approved request's fee is
+// taken off the booking
+func (h *BookingHandler) CancelStayTimeRequest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	guestID := userID.(int)
+	if middleware.IsStaff(c) {
+		guestID = 0
+	}
+
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+	requestID, err := strconv.Atoi(c.Param("requestId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	request, err := h.bookingService.CancelStayTimeRequest(c.Request.Context(), bookingID, guestID, requestID)
+	if err != nil {
+		stayTimeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// stayTimeRequestError maps a stay-time request error to its status code
+func stayTimeRequestError(c *gin.Context, err error) {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "failed to"):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
 // SearchBookingsByPhone handles GET /api/bookings/search?phone=xxx
 func (h *BookingHandler) SearchBookingsByPhone(c *gin.Context) {
 	phone := c.Query("phone")
@@ -251,3 +530,63 @@ func (h *BookingHandler) SyncBookings(c *gin.Context) {
 		"message": "Bookings are accessible via phone search",
 	})
 }
+
+// ImportRoomingList handles POST /api/bookings/:id/rooming-list
+// Accepts a multipart "file" field with a CSV or XLSX rooming list.
+// Returns 422 with a per-row error report when validation fails; nothing is saved in that case.
+func (h *BookingHandler) ImportRoomingList(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rooming list file is required"})
+		return
+	}
+	if fileHeader.Size > maxRoomingListSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rooming list file must be 5 MB or smaller"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read rooming list file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxRoomingListSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read rooming list file"})
+		return
+	}
+
+	result, err := h.bookingService.ImportRoomingList(c.Request.Context(), bookingID, fileHeader.Filename, data)
+	if err != nil {
+		switch {
+		case err.Error() == "booking not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		case errors.Is(err, utils.ErrUnsupportedSpreadsheet),
+			strings.HasPrefix(err.Error(), "cannot import rooming list"),
+			strings.HasPrefix(err.Error(), "failed to read csv"),
+			strings.HasPrefix(err.Error(), "failed to open xlsx"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if !result.Applied {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  "Rooming list has validation errors",
+			"result": result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
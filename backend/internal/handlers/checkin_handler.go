@@ -5,8 +5,10 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/middleware"
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/internal/service"
+	"github.com/hotel-booking-system/backend/pkg/utils"
 )
 
 // CheckInHandler handles check-in/check-out related HTTP requests
@@ -21,7 +23,17 @@ func NewCheckInHandler(bookingService *service.BookingService) *CheckInHandler {
 	}
 }
 
+// actingStaffID returns the ID of the signed-in staff member, recorded with the room
+// status changes they make
+func actingStaffID(c *gin.Context) *int {
+	if staffID, ok := middleware.GetUserID(c); ok && middleware.IsStaff(c) {
+		return &staffID
+	}
+	return nil
+}
+
 // CheckIn handles POST /api/checkin
+// Without a room_id the guest is checked in to the room pre-assigned to the booking.
 func (h *CheckInHandler) CheckIn(c *gin.Context) {
 	var req models.CheckInRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -29,7 +41,7 @@ func (h *CheckInHandler) CheckIn(c *gin.Context) {
 		return
 	}
 
-	response, err := h.bookingService.CheckIn(c.Request.Context(), req.BookingDetailID, req.RoomID)
+	response, err := h.bookingService.CheckIn(c.Request.Context(), req.BookingDetailID, req.RoomID, actingStaffID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -51,7 +63,7 @@ func (h *CheckInHandler) CheckOut(c *gin.Context) {
 		return
 	}
 
-	response, err := h.bookingService.CheckOut(c.Request.Context(), req.BookingID)
+	response, err := h.bookingService.CheckOut(c.Request.Context(), req.BookingID, actingStaffID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -73,7 +85,7 @@ func (h *CheckInHandler) MoveRoom(c *gin.Context) {
 		return
 	}
 
-	response, err := h.bookingService.MoveRoom(c.Request.Context(), req.RoomAssignmentID, req.NewRoomID)
+	response, err := h.bookingService.MoveRoom(c.Request.Context(), req.RoomAssignmentID, req.NewRoomID, actingStaffID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -176,3 +188,349 @@ func (h *CheckInHandler) GetAvailableRooms(c *gin.Context) {
 		"count": len(rooms),
 	})
 }
+
+// SetPreAssignment picks the room of an arrival by hand
+// @Summary Pre-assign a room
+// @Description Pick the room of an arrival ahead of check-in. The room must be of the booked room type and free for the whole stay. The pre-assignment job keeps rooms picked by hand.
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking detail ID"
+// @Param request body models.SetPreAssignmentRequest true "Room to pre-assign"
+// @Success 200 {object} models.RoomPreAssignment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/pre-assignments/{id} [put]
+func (h *CheckInHandler) SetPreAssignment(c *gin.Context) {
+	bookingDetailID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid booking detail ID")
+		return
+	}
+
+	var req models.SetPreAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	assignment, err := h.bookingService.SetPreAssignment(c.Request.Context(), bookingDetailID, req.RoomID, actingStaffID(c))
+	if err != nil {
+		assignmentError(c, "Failed to pre-assign room", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, assignment)
+}
+
+// ClearPreAssignment removes the pre-assigned room of an arrival
+// @Summary Remove a pre-assigned room
+// @Tags checkin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking detail ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/pre-assignments/{id} [delete]
+func (h *CheckInHandler) ClearPreAssignment(c *gin.Context) {
+	bookingDetailID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid booking detail ID")
+		return
+	}
+
+	if err := h.bookingService.ClearPreAssignment(c.Request.Context(), bookingDetailID); err != nil {
+		assignmentError(c, "Failed to remove pre-assignment", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Pre-assignment removed"})
+}
+
+// SetRoomPreferences replaces the room preferences of a stay
+// @Summary Set room preferences
+// @Description Set the floor, view, accessibility and connecting room preferences the pre-assignment job honours
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking detail ID"
+// @Param request body models.SetRoomPreferencesRequest true "Room preferences"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/booking-details/{id}/preferences [put]
+func (h *CheckInHandler) SetRoomPreferences(c *gin.Context) {
+	bookingDetailID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid booking detail ID")
+		return
+	}
+
+	var req models.SetRoomPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	if err := h.bookingService.SetRoomPreferences(c.Request.Context(), bookingDetailID, &req); err != nil {
+		assignmentError(c, "Failed to set room preferences", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Room preferences updated"})
+}
+
+// SetGuestVIP sets or clears the VIP status of a guest
+// @Summary Set guest VIP status
+// @Description VIP guests get the first pick of rooms when rooms are pre-assigned
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Guest ID"
+// @Param request body models.SetGuestVIPRequest true "VIP status"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/guests/{id}/vip [put]
+func (h *CheckInHandler) SetGuestVIP(c *gin.Context) {
+	guestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid guest ID")
+		return
+	}
+
+	var req models.SetGuestVIPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	if err := h.bookingService.SetGuestVIP(c.Request.Context(), guestID, *req.IsVIP); err != nil {
+		assignmentError(c, "Failed to set VIP status", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "VIP status updated"})
+}
+
+// UpgradeRoom upgrades a booked room to another room type
+// @Summary Upgrade a booked room
+// @Description Move a confirmed or in-house booking to a higher room type for the rest of the stay, free or for a charge per night. The booking keeps its rate and one room moves between the room types in inventory from the arrival, or from today for a guest in house. An in-house guest is moved to room_id, a room of the new type.
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpgradeRoomRequest true "Upgrade"
+// @Success 200 {object} models.RoomUpgrade
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/upgrades [post]
+func (h *CheckInHandler) UpgradeRoom(c *gin.Context) {
+	var req models.UpgradeRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	upgrade, err := h.bookingService.UpgradeRoom(c.Request.Context(), &req, actingStaffID(c))
+	if err != nil {
+		assignmentError(c, "Failed to upgrade room", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, upgrade)
+}
+
+// GetStayTimeRequests lists the early check-ins and late check-outs of a day
+// @Summary List early check-in and late check-out requests
+// @Description List the requests to arrive early or leave late on a date, optionally of one status (Pending, Approved, Declined, Cancelled)
+// @Tags checkin
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Arrival or departure date (YYYY-MM-DD), defaults to today"
+// @Param status query string false "Request status"
+// @Success 200 {array} models.StayTimeRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/stay-time-requests [get]
+func (h *CheckInHandler) GetStayTimeRequests(c *gin.Context) {
+	requests, err := h.bookingService.GetStayTimeRequests(c.Request.Context(), c.Query("date"), c.Query("status"))
+	if err != nil {
+		assignmentError(c, "Failed to list stay-time requests", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, requests)
+}
+
+// GrantStayTime records an early check-in or late check-out arranged by staff
+// @Summary Grant an early check-in or late check-out
+// @Description Approve an early check-in or late check-out at once after checking the rooms of the type, the assigned or pre-assigned room and the time housekeeping needs to turn it around. The tier fee, or the fee given, is posted to the booking.
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.StaffStayTimeRequest true "Early check-in or late check-out"
+// @Success 201 {object} models.StayTimeRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/stay-time-requests [post]
+func (h *CheckInHandler) GrantStayTime(c *gin.Context) {
+	var req models.StaffStayTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	request, err := h.bookingService.GrantStayTime(c.Request.Context(), &req, actingStaffID(c))
+	if err != nil {
+		assignmentError(c, "Failed to grant stay-time request", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, request)
+}
+
+// ApproveStayTimeRequest approves a guest's early check-in or late check-out request
+// @Summary Approve an early check-in or late check-out request
+// @Description Check the request again against the rooms and post its fee, or the fee given, to the booking
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Request ID"
+// @Param request body models.ApproveStayTimeRequest false "Fee override"
+// @Success 200 {object} models.StayTimeRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/stay-time-requests/{id}/approve [post]
+func (h *CheckInHandler) ApproveStayTimeRequest(c *gin.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request ID")
+		return
+	}
+
+	var req models.ApproveStayTimeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err.Error())
+			return
+		}
+	}
+
+	request, err := h.bookingService.ApproveStayTimeRequest(c.Request.Context(), requestID, actingStaffID(c), &req)
+	if err != nil {
+		assignmentError(c, "Failed to approve stay-time request", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, request)
+}
+
+// DeclineStayTimeRequest declines a guest's early check-in or late check-out request
+// @Summary Decline an early check-in or late check-out request
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Request ID"
+// @Param request body models.DeclineStayTimeRequest true "Reason"
+// @Success 200 {object} models.StayTimeRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/stay-time-requests/{id}/decline [post]
+func (h *CheckInHandler) DeclineStayTimeRequest(c *gin.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request ID")
+		return
+	}
+
+	var req models.DeclineStayTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	request, err := h.bookingService.DeclineStayTimeRequest(c.Request.Context(), requestID, actingStaffID(c), &req)
+	if err != nil {
+		assignmentError(c, "Failed to decline stay-time request", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, request)
+}
+
+// GetWalks lists the guests walked to partner hotels on a day
+// @Summary List walked guests
+// @Description List the guests relocated to a partner hotel on a date because the hotel was oversold
+// @Tags checkin
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Walk date (YYYY-MM-DD), defaults to today"
+// @Success 200 {array} models.BookingWalk
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/walks [get]
+func (h *CheckInHandler) GetWalks(c *gin.Context) {
+	walks, err := h.bookingService.GetWalks(c.Request.Context(), c.Query("date"))
+	if err != nil {
+		assignmentError(c, "Failed to list walks", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, walks)
+}
+
+// WalkGuest relocates an arriving guest to a partner hotel
+// @Summary Walk a guest to a partner hotel
+// @Description Relocate a confirmed guest arriving today to a partner hotel for the first nights of the stay and record the compensation given. The walked nights go back to inventory and their room revenue is taken off the booking; walking every night of a single-room booking cancels it.
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.WalkGuestRequest true "Walk"
+// @Success 201 {object} models.BookingWalk
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/walks [post]
+func (h *CheckInHandler) WalkGuest(c *gin.Context) {
+	var req models.WalkGuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	walk, err := h.bookingService.WalkGuest(c.Request.Context(), &req, actingStaffID(c))
+	if err != nil {
+		assignmentError(c, "Failed to walk guest", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, walk)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/jobs"
+)
+
+// DynamicPricingHandler handles HTTP requests for dynamic pricing job operations
+type DynamicPricingHandler struct {
+	engine *jobs.DynamicPricingJob
+}
+
+// NewDynamicPricingHandler creates a new dynamic pricing handler
+func NewDynamicPricingHandler(engine *jobs.DynamicPricingJob) *DynamicPricingHandler {
+	return &DynamicPricingHandler{
+		engine: engine,
+	}
+}
+
+// TriggerManual runs the dynamic pricing rules and applies the changes
+// POST /api/admin/dynamic-pricing/trigger
+func (h *DynamicPricingHandler) TriggerManual(c *gin.Context) {
+	h.run(c, false)
+}
+
+// Preview runs the dynamic pricing rules without changing the pricing calendar
+// POST /api/pricing/rules/preview
+func (h *DynamicPricingHandler) Preview(c *gin.Context) {
+	h.run(c, true)
+}
+
+func (h *DynamicPricingHandler) run(c *gin.Context, dryRun bool) {
+	result, err := h.engine.RunManual(dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to execute dynamic pricing",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	message := "Dynamic pricing executed successfully"
+	if dryRun {
+		message = "Dynamic pricing preview generated (no changes applied)"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         result.Success,
+		"dry_run":         result.DryRun,
+		"rules_evaluated": result.RulesEvaluated,
+		"dates_evaluated": result.DatesEvaluated,
+		"changes":         result.Changes,
+		"timestamp":       result.Timestamp,
+		"execution_time":  result.ExecutionTime.String(),
+		"message":         message,
+	})
+}
+
+// GetStatus returns the current status of the dynamic pricing job
+// GET /api/admin/dynamic-pricing/status
+func (h *DynamicPricingHandler) GetStatus(c *gin.Context) {
+	stats := h.engine.GetStats()
+
+	c.JSON(http.StatusOK, gin.H{
+		"is_running":    stats["is_running"],
+		"next_run_time": stats["next_run_time"],
+		"schedule":      stats["schedule"],
+	})
+}
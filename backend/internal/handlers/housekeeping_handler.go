@@ -3,8 +3,11 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/middleware"
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/internal/service"
 	"github.com/hotel-booking-system/backend/pkg/utils"
@@ -22,19 +25,38 @@ func NewHousekeepingHandler(housekeepingService *service.HousekeepingService) *H
 	}
 }
 
-// GetTasks retrieves all housekeeping tasks
+// GetTasks retrieves housekeeping tasks
 // @Summary Get housekeeping tasks
-// @Description Get list of all rooms that need cleaning or inspection
+// @Description Get the rooms that need cleaning or inspection with progress per attendant. Housekeepers only get the rooms assigned to them today; managers may filter by attendant.
 // @Tags housekeeping
 // @Produce json
 // @Security BearerAuth
+// @Param staff_id query int false "Attendant staff ID (managers only)"
 // @Success 200 {object} models.HousekeepingTasksResponse
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/housekeeping/tasks [get]
 func (h *HousekeepingHandler) GetTasks(c *gin.Context) {
-	response, err := h.housekeepingService.GetHousekeepingTasks(c.Request.Context())
+	var attendantID *int
+	if role, _ := middleware.GetUserRole(c); role == "HOUSEKEEPER" {
+		staffID, ok := middleware.GetUserID(c)
+		if !ok {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+			return
+		}
+		attendantID = &staffID
+	} else if value := c.Query("staff_id"); value != "" {
+		staffID, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid staff ID")
+			return
+		}
+		attendantID = &staffID
+	}
+
+	response, err := h.housekeepingService.GetHousekeepingTasks(c.Request.Context(), attendantID)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get housekeeping tasks")
 		return
@@ -75,9 +97,10 @@ func (h *HousekeepingHandler) UpdateRoomStatus(c *gin.Context) {
 	}
 
 	// Update status
-	if err := h.housekeepingService.UpdateRoomStatus(c.Request.Context(), roomID, req.Status); err != nil {
+	if err := h.housekeepingService.UpdateRoomStatus(c.Request.Context(), roomID, req.Status, actingStaffID(c)); err != nil {
 		if err.Error() == "invalid housekeeping status" ||
-			err.Error() == "room must be in Clean status to be inspected" {
+			err.Error() == "room must be in Clean status to be inspected" ||
+			err.Error() == "room is marked do not disturb" {
 			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
@@ -133,7 +156,7 @@ func (h *HousekeepingHandler) InspectRoom(c *gin.Context) {
 	}
 
 	// Inspect room
-	if err := h.housekeepingService.InspectRoom(c.Request.Context(), roomID, req.Approved, req.Notes); err != nil {
+	if err := h.housekeepingService.InspectRoom(c.Request.Context(), roomID, req.Approved, req.Notes, actingStaffID(c)); err != nil {
 		if err.Error() == "room must be in Clean status to be inspected" {
 			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
@@ -181,7 +204,7 @@ func (h *HousekeepingHandler) GetRoomsForInspection(c *gin.Context) {
 
 // ReportMaintenance reports a maintenance issue for a room
 // @Summary Report maintenance issue
-// @Description Report a maintenance issue for a specific room
+// @Description Opens a maintenance work order for a specific room and marks the room as requiring maintenance
 // @Tags housekeeping
 // @Accept json
 // @Produce json
@@ -211,18 +234,362 @@ func (h *HousekeepingHandler) ReportMaintenance(c *gin.Context) {
 	}
 
 	// Report maintenance
-	if err := h.housekeepingService.ReportMaintenance(c.Request.Context(), roomID, req.Description); err != nil {
-		if err.Error() == "failed to get room: room not found" {
+	var reportedBy *int
+	if staffID, ok := middleware.GetUserID(c); ok {
+		reportedBy = &staffID
+	}
+	workOrder, err := h.housekeepingService.ReportMaintenance(c.Request.Context(), roomID, reportedBy, &req)
+	if err != nil {
+		if err.Error() == "room not found" {
 			utils.ErrorResponse(c, http.StatusNotFound, "Room not found")
 			return
 		}
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to report maintenance")
+		if strings.HasPrefix(err.Error(), "failed to") {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to report maintenance")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, gin.H{
 		"message":     "Maintenance issue reported successfully",
 		"room_id":     roomID,
-		"description": req.Description,
+		"description": workOrder.Description,
+		"work_order":  workOrder,
 	})
 }
+
+// AssignRoom assigns a room to an attendant for today
+// @Summary Assign a room to an attendant
+// @Description Supervisor assigns a room waiting to be cleaned to an attendant for today; a null staff_id takes the room off its attendant
+// @Tags housekeeping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Param request body models.AssignRoomRequest true "Assignment request"
+// @Success 200 {object} models.HousekeepingTask
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/housekeeping/rooms/{id}/assign [put]
+func (h *HousekeepingHandler) AssignRoom(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	var req models.AssignRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	var assignedBy *int
+	if staffID, ok := middleware.GetUserID(c); ok {
+		assignedBy = &staffID
+	}
+	task, err := h.housekeepingService.AssignRoom(c.Request.Context(), roomID, req.StaffID, assignedBy)
+	if err != nil {
+		assignmentError(c, "Failed to assign room", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, task)
+}
+
+// AutoAssign balances today's rooms across attendants
+// @Summary Auto-assign rooms to attendants
+// @Description Hands the dirty rooms nobody is cleaning yet to attendants, balancing credits per room type, checkout versus stayover, floors and arrivals
+// @Tags housekeeping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AutoAssignRequest false "Attendants and rebalancing"
+// @Success 200 {object} models.AutoAssignResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/housekeeping/assignments/auto [post]
+func (h *HousekeepingHandler) AutoAssign(c *gin.Context) {
+	var req models.AutoAssignRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err.Error())
+			return
+		}
+	}
+
+	var assignedBy *int
+	if staffID, ok := middleware.GetUserID(c); ok {
+		assignedBy = &staffID
+	}
+	result, err := h.housekeepingService.AutoAssign(c.Request.Context(), &req, assignedBy)
+	if err != nil {
+		assignmentError(c, "Failed to assign rooms", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, result)
+}
+
+// GetCredits retrieves the cleaning credits of every room type
+// @Summary Get housekeeping credits
+// @Description Get the workload credits of a checkout clean and a stayover service for each room type
+// @Tags housekeeping
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []models.HousekeepingCredits
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/housekeeping/credits [get]
+func (h *HousekeepingHandler) GetCredits(c *gin.Context) {
+	credits, err := h.housekeepingService.GetHousekeepingCredits(c.Request.Context())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get housekeeping credits")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, credits)
+}
+
+// UpdateCredits sets the cleaning credits of a room type
+// @Summary Update housekeeping credits
+// @Description Set the workload credits of a room type; rooms already assigned keep their credits
+// @Tags housekeeping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param roomTypeId path int true "Room type ID"
+// @Param request body models.UpdateHousekeepingCreditsRequest true "Credits"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/housekeeping/credits/{roomTypeId} [put]
+func (h *HousekeepingHandler) UpdateCredits(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("roomTypeId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+
+	var req models.UpdateHousekeepingCreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	if err := h.housekeepingService.UpdateHousekeepingCredits(c.Request.Context(), roomTypeID, &req); err != nil {
+		assignmentError(c, "Failed to update housekeeping credits", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{
+		"message":      "Housekeeping credits updated successfully",
+		"room_type_id": roomTypeID,
+	})
+}
+
+// GetRoomHistory retrieves the status history of a room
+// @Summary Get room status history
+// @Description Get the housekeeping and occupancy status changes of a room, newest first, with the staff member who made each change and any notes
+// @Tags housekeeping
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Param limit query int false "Number of changes (default 100)"
+// @Success 200 {object} []models.RoomStatusUpdate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/housekeeping/rooms/{id}/history [get]
+func (h *HousekeepingHandler) GetRoomHistory(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 1000 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "limit must be between 1 and 1000")
+			return
+		}
+	}
+
+	history, err := h.housekeepingService.GetRoomStatusHistory(c.Request.Context(), roomID, limit)
+	if err != nil {
+		assignmentError(c, "Failed to get room history", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, history)
+}
+
+// GetCleaningTimeReport reports cleaning durations per attendant
+// @Summary Get cleaning time report
+// @Description Covers rooms that went from Cleaning to Clean between the two dates, grouped by the attendant who cleaned them
+// @Tags housekeeping
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Success 200 {object} models.CleaningTimeReport
+// @Failure 400 {object} map[string]string
+// @Router /api/housekeeping/reports/cleaning-times [get]
+func (h *HousekeepingHandler) GetCleaningTimeReport(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+	if startDateStr == "" || endDateStr == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "start_date and end_date are required")
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	report, err := h.housekeepingService.GetCleaningTimeReport(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		assignmentError(c, "Failed to get cleaning time report", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, report)
+}
+
+// GetServicePreferences returns the DND flag and service preferences of an occupied room
+// @Summary Get room service preferences
+// @Description Returns the do-not-disturb flag of an occupied room, the green program choice and service frequency of the stay, and the service due
+// @Tags housekeeping
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} models.RoomServicePreferences
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/rooms/{id}/service [get]
+func (h *HousekeepingHandler) GetServicePreferences(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	prefs, err := h.housekeepingService.GetRoomServicePreferences(c.Request.Context(), roomID)
+	if err != nil {
+		assignmentError(c, "Failed to get room service preferences", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, prefs)
+}
+
+// SetDoNotDisturb sets or clears the DND flag of an occupied room
+// @Summary Set do not disturb
+// @Description Front desk sets or clears do-not-disturb on an occupied room; attendants skip the room until it is cleared. Flags are cleared by the night audit and at check-out.
+// @Tags housekeeping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Param request body models.SetDoNotDisturbRequest true "DND flag"
+// @Success 200 {object} models.RoomServicePreferences
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/rooms/{id}/dnd [put]
+func (h *HousekeepingHandler) SetDoNotDisturb(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	var req models.SetDoNotDisturbRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	prefs, err := h.housekeepingService.SetDoNotDisturb(c.Request.Context(), roomID, *req.DoNotDisturb, actingStaffID(c))
+	if err != nil {
+		assignmentError(c, "Failed to set do not disturb", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, prefs)
+}
+
+// SetGreenOptOut enrols the stay in a room in the green program or takes it out
+// @Summary Set green program opt-out
+// @Description Guests in the green program get a refresh (towels and trash) instead of a full stayover service when one is due
+// @Tags housekeeping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Param request body models.SetGreenOptOutRequest true "Green program choice"
+// @Success 200 {object} models.RoomServicePreferences
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/checkin/rooms/{id}/green-program [put]
+func (h *HousekeepingHandler) SetGreenOptOut(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	var req models.SetGreenOptOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	prefs, err := h.housekeepingService.SetGreenOptOut(c.Request.Context(), roomID, *req.GreenOptOut)
+	if err != nil {
+		assignmentError(c, "Failed to set green program opt-out", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, prefs)
+}
+
+// assignmentError responds with the status matching a housekeeping or room assignment
+// error
+func assignmentError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case strings.HasPrefix(err.Error(), "failed to"):
+		utils.ErrorResponse(c, http.StatusInternalServerError, message)
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hotel-booking-system/backend/internal/models"
@@ -191,3 +192,303 @@ func (h *InventoryHandler) BulkUpdateInventory(c *gin.Context) {
 		"message": "Inventory bulk updated successfully",
 	})
 }
+
+// outOfOrderError responds with the status matching an out-of-order service error
+func outOfOrderError(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "failed to"):
+		status = http.StatusInternalServerError
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   message,
+		"details": err.Error(),
+	})
+}
+
+// GetOutOfOrderPeriods lists current out-of-order periods
+// GET /api/inventory/out-of-order?room_id=101&include_ended=true
+func (h *InventoryHandler) GetOutOfOrderPeriods(c *gin.Context) {
+	var roomID *int
+	if value := c.Query("room_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid room_id",
+			})
+			return
+		}
+		roomID = &id
+	}
+	includeEnded := c.Query("include_ended") == "true"
+
+	periods, err := h.inventoryService.GetOutOfOrderPeriods(c.Request.Context(), roomID, includeEnded)
+	if err != nil {
+		outOfOrderError(c, "Failed to retrieve out-of-order periods", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    periods,
+	})
+}
+
+// ScheduleOutOfOrder takes a room out of order for a range of nights
+// POST /api/inventory/out-of-order
+func (h *InventoryHandler) ScheduleOutOfOrder(c *gin.Context) {
+	var req models.ScheduleOutOfOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	period, validationErrors, err := h.inventoryService.ScheduleOutOfOrder(c.Request.Context(), actingStaffID(c), &req)
+	if err != nil {
+		outOfOrderError(c, "Failed to schedule out-of-order period", err)
+		return
+	}
+
+	if len(validationErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":           false,
+			"error":             "Cannot take the room out of order for some dates due to existing bookings",
+			"validation_errors": validationErrors,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    period,
+	})
+}
+
+// UpdateOutOfOrder extends or shortens an out-of-order period
+// PUT /api/inventory/out-of-order/:id
+func (h *InventoryHandler) UpdateOutOfOrder(c *gin.Context) {
+	outOfOrderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid out-of-order ID",
+		})
+		return
+	}
+
+	var req models.UpdateOutOfOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	period, validationErrors, err := h.inventoryService.UpdateOutOfOrder(c.Request.Context(), outOfOrderID, &req)
+	if err != nil {
+		outOfOrderError(c, "Failed to update out-of-order period", err)
+		return
+	}
+
+	if len(validationErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":           false,
+			"error":             "Cannot extend the out-of-order period for some dates due to existing bookings",
+			"validation_errors": validationErrors,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    period,
+	})
+}
+
+// EndOutOfOrder cancels a scheduled period or returns the room to service today
+// DELETE /api/inventory/out-of-order/:id
+func (h *InventoryHandler) EndOutOfOrder(c *gin.Context) {
+	outOfOrderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid out-of-order ID",
+		})
+		return
+	}
+
+	period, err := h.inventoryService.EndOutOfOrder(c.Request.Context(), outOfOrderID, actingStaffID(c))
+	if err != nil {
+		outOfOrderError(c, "Failed to end out-of-order period", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    period,
+	})
+}
+
+// overbookingError responds with the status matching an overbooking or partner hotel
+// service error, as outOfOrderError does
+func overbookingError(c *gin.Context, message string, err error) {
+	outOfOrderError(c, message, err)
+}
+
+// GetOverbookingPositions shows how far each room type is sold beyond its allotment
+// GET /api/inventory/overbooking?start_date=2024-01-01&end_date=2024-01-31&room_type_id=1
+func (h *InventoryHandler) GetOverbookingPositions(c *gin.Context) {
+	var roomTypeID *int
+	if value := c.Query("room_type_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid room_type_id",
+			})
+			return
+		}
+		roomTypeID = &id
+	}
+
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "start_date and end_date are required",
+		})
+		return
+	}
+
+	positions, err := h.inventoryService.GetOverbookingPositions(c.Request.Context(), roomTypeID, startDate, endDate)
+	if err != nil {
+		overbookingError(c, "Failed to retrieve overbooking positions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    positions,
+	})
+}
+
+// SetOverbookingLimit sets the overbooking limit of a room type for a date range
+// PUT /api/inventory/overbooking
+func (h *InventoryHandler) SetOverbookingLimit(c *gin.Context) {
+	var req models.SetOverbookingLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	validationErrors, err := h.inventoryService.SetOverbookingLimit(c.Request.Context(), &req)
+	if err != nil {
+		overbookingError(c, "Failed to set overbooking limit", err)
+		return
+	}
+
+	if len(validationErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":           false,
+			"error":             "Cannot lower the overbooking limit for some dates due to existing bookings",
+			"validation_errors": validationErrors,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Overbooking limit updated successfully",
+	})
+}
+
+// GetPartnerHotels lists the partner hotels guests can be walked to
+// GET /api/inventory/partner-hotels?include_inactive=true
+func (h *InventoryHandler) GetPartnerHotels(c *gin.Context) {
+	hotels, err := h.inventoryService.GetPartnerHotels(c.Request.Context(), c.Query("include_inactive") == "true")
+	if err != nil {
+		overbookingError(c, "Failed to retrieve partner hotels", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    hotels,
+	})
+}
+
+// CreatePartnerHotel adds a partner hotel
+// POST /api/inventory/partner-hotels
+func (h *InventoryHandler) CreatePartnerHotel(c *gin.Context) {
+	var req models.PartnerHotelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	hotel, err := h.inventoryService.CreatePartnerHotel(c.Request.Context(), &req)
+	if err != nil {
+		overbookingError(c, "Failed to create partner hotel", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    hotel,
+	})
+}
+
+// UpdatePartnerHotel updates a partner hotel, or takes it off the list with
+// is_active false
+// PUT /api/inventory/partner-hotels/:id
+func (h *InventoryHandler) UpdatePartnerHotel(c *gin.Context) {
+	partnerHotelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid partner hotel ID",
+		})
+		return
+	}
+
+	var req models.PartnerHotelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	hotel, err := h.inventoryService.UpdatePartnerHotel(c.Request.Context(), partnerHotelID, &req)
+	if err != nil {
+		overbookingError(c, "Failed to update partner hotel", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    hotel,
+	})
+}
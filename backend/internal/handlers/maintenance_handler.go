@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/middleware"
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/internal/service"
+	"github.com/hotel-booking-system/backend/pkg/utils"
+)
+
+// MaintenanceHandler handles maintenance work order HTTP requests
+type MaintenanceHandler struct {
+	maintenanceService *service.MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceService *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// workOrderError responds to a failed work order request
+func workOrderError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		utils.ErrorResponse(c, http.StatusNotFound, message)
+	case message == "work order is assigned to another engineer":
+		utils.ErrorResponse(c, http.StatusForbidden, message)
+	case message == "image storage is not configured":
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, message)
+	case strings.HasPrefix(message, "failed to"):
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process work order")
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, message)
+	}
+}
+
+// workOrderIDParam reads the work order ID from the path
+func workOrderIDParam(c *gin.Context) (int, bool) {
+	workOrderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid work order ID")
+		return 0, false
+	}
+	return workOrderID, true
+}
+
+// CreateWorkOrder opens a maintenance work order
+// @Summary Create work order
+// @Description Opens a work order for a room and marks the room as requiring maintenance
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateWorkOrderRequest true "Work order"
+// @Success 201 {object} models.WorkOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/maintenance/work-orders [post]
+func (h *MaintenanceHandler) CreateWorkOrder(c *gin.Context) {
+	var req models.CreateWorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	var reportedBy *int
+	if staffID, ok := middleware.GetUserID(c); ok {
+		reportedBy = &staffID
+	}
+
+	workOrder, err := h.maintenanceService.CreateWorkOrder(c.Request.Context(), reportedBy, &req)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, workOrder)
+}
+
+// GetWorkOrders lists maintenance work orders
+// @Summary Get work orders
+// @Description Most urgent and then oldest first
+// @Tags maintenance
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Open, InProgress, OnHold or Resolved"
+// @Param room_id query int false "Room ID"
+// @Param assigned_to query int false "Assignee staff ID"
+// @Param open query bool false "Only unresolved work orders"
+// @Success 200 {array} models.WorkOrder
+// @Failure 400 {object} map[string]string
+// @Router /api/maintenance/work-orders [get]
+func (h *MaintenanceHandler) GetWorkOrders(c *gin.Context) {
+	filter := models.WorkOrderFilter{
+		Status:   c.Query("status"),
+		OpenOnly: c.Query("open") == "true",
+	}
+	if value := c.Query("room_id"); value != "" {
+		roomID, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room_id")
+			return
+		}
+		filter.RoomID = &roomID
+	}
+	if value := c.Query("assigned_to"); value != "" {
+		staffID, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid assigned_to")
+			return
+		}
+		filter.AssignedTo = &staffID
+	}
+
+	workOrders, err := h.maintenanceService.GetWorkOrders(c.Request.Context(), filter)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, workOrders)
+}
+
+// GetWorkOrder retrieves a maintenance work order
+// @Summary Get work order
+// @Tags maintenance
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Work Order ID"
+// @Success 200 {object} models.WorkOrder
+// @Failure 404 {object} map[string]string
+// @Router /api/maintenance/work-orders/{id} [get]
+func (h *MaintenanceHandler) GetWorkOrder(c *gin.Context) {
+	workOrderID, ok := workOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	workOrder, err := h.maintenanceService.GetWorkOrder(c.Request.Context(), workOrderID)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, workOrder)
+}
+
+// GetMyTasks lists the caller's unresolved work orders and the open ones nobody has taken
+// @Summary Get engineer task list
+// @Tags maintenance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.EngineerTaskList
+// @Failure 401 {object} map[string]string
+// @Router /api/maintenance/my-tasks [get]
+func (h *MaintenanceHandler) GetMyTasks(c *gin.Context) {
+	staffID, ok := middleware.GetUserID(c)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	tasks, err := h.maintenanceService.GetEngineerTasks(c.Request.Context(), staffID)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, tasks)
+}
+
+// UpdateWorkOrder corrects the category, priority or description of a work order
+// @Summary Update work order
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Work Order ID"
+// @Param request body models.UpdateWorkOrderRequest true "Fields to change"
+// @Success 200 {object} models.WorkOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/maintenance/work-orders/{id} [put]
+func (h *MaintenanceHandler) UpdateWorkOrder(c *gin.Context) {
+	workOrderID, ok := workOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workOrder, err := h.maintenanceService.UpdateWorkOrder(c.Request.Context(), workOrderID, &req)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, workOrder)
+}
+
+// AssignWorkOrder assigns a work order to an engineer
+// @Summary Assign work order
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Work Order ID"
+// @Param request body models.AssignWorkOrderRequest true "Assignee staff ID, or null to unassign"
+// @Success 200 {object} models.WorkOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/maintenance/work-orders/{id}/assign [put]
+func (h *MaintenanceHandler) AssignWorkOrder(c *gin.Context) {
+	workOrderID, ok := workOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssignWorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workOrder, err := h.maintenanceService.AssignWorkOrder(c.Request.Context(), workOrderID, &req)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, workOrder)
+}
+
+// UpdateWorkOrderStatus moves a work order to a new status
+// @Summary Update work order status
+// @Description Starting an unassigned work order assigns it to the caller. Resolving needs resolution notes and returns the room to Dirty once its last work order is resolved.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Work Order ID"
+// @Param request body models.UpdateWorkOrderStatusRequest true "New status"
+// @Success 200 {object} models.WorkOrder
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/maintenance/work-orders/{id}/status [put]
+func (h *MaintenanceHandler) UpdateWorkOrderStatus(c *gin.Context) {
+	workOrderID, ok := workOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWorkOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	staffID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+	workOrder, err := h.maintenanceService.UpdateWorkOrderStatus(c.Request.Context(), workOrderID, staffID, role, &req)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, workOrder)
+}
+
+// UploadWorkOrderPhoto attaches a photo to a work order
+// @Summary Upload work order photo
+// @Description The photo is stored as a JPEG without EXIF metadata
+// @Tags maintenance
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Work Order ID"
+// @Param file formData file true "JPEG, PNG or GIF image"
+// @Success 200 {object} models.WorkOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/maintenance/work-orders/{id}/photos [post]
+func (h *MaintenanceHandler) UploadWorkOrderPhoto(c *gin.Context) {
+	workOrderID, ok := workOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Image file is required")
+		return
+	}
+	if fileHeader.Size > models.MaxRoomTypeImageSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Image file must be 10 MB or smaller")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read image file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxRoomTypeImageSize))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read image file")
+		return
+	}
+
+	workOrder, err := h.maintenanceService.AddWorkOrderPhoto(c.Request.Context(), workOrderID, data)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, workOrder)
+}
+
+// GetRepairTimeReport reports the mean time to repair per room
+// @Summary Get mean time to repair report
+// @Description Covers work orders resolved between the two dates; rooms that take longest to repair come first
+// @Tags maintenance
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Success 200 {object} models.RepairTimeReport
+// @Failure 400 {object} map[string]string
+// @Router /api/maintenance/reports/mttr [get]
+func (h *MaintenanceHandler) GetRepairTimeReport(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+	if startDateStr == "" || endDateStr == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "start_date and end_date are required")
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	report, err := h.maintenanceService.GetRepairTimeReport(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		workOrderError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, report)
+}
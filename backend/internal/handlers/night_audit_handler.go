@@ -35,6 +35,10 @@ func (h *NightAuditHandler) TriggerManual(c *gin.Context) {
 		"message":        "Night audit completed successfully",
 		"timestamp":      result.Timestamp,
 		"rooms_updated":  result.RoomsUpdated,
+		"stayover":       result.StayoverServices,
+		"refresh":        result.RefreshServices,
+		"rooms_not_due":  result.RoomsNotDue,
+		"dnd_cleared":    result.DNDCleared,
 		"execution_time": result.ExecutionTime.String(),
 	})
 }
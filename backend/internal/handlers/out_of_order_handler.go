@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/jobs"
+)
+
+// OutOfOrderHandler handles HTTP requests for the out-of-order schedule job
+type OutOfOrderHandler struct {
+	outOfOrder *jobs.OutOfOrderJob
+}
+
+// NewOutOfOrderHandler creates a new out-of-order handler
+func NewOutOfOrderHandler(outOfOrder *jobs.OutOfOrderJob) *OutOfOrderHandler {
+	return &OutOfOrderHandler{
+		outOfOrder: outOfOrder,
+	}
+}
+
+// TriggerManual applies the out-of-order schedule immediately
+// POST /api/admin/out-of-order/trigger
+func (h *OutOfOrderHandler) TriggerManual(c *gin.Context) {
+	result, err := h.outOfOrder.RunManual()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to apply out-of-order schedule",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         result.Success,
+		"rooms_taken_out": result.RoomsTakenOut,
+		"rooms_returned":  result.RoomsReturned,
+		"timestamp":       result.Timestamp,
+		"execution_time":  result.ExecutionTime.String(),
+		"message":         "Out-of-order schedule applied successfully",
+	})
+}
+
+// GetStatus returns the current status of the out-of-order job
+// GET /api/admin/out-of-order/status
+func (h *OutOfOrderHandler) GetStatus(c *gin.Context) {
+	stats := h.outOfOrder.GetStats()
+
+	c.JSON(http.StatusOK, gin.H{
+		"is_running":    stats["is_running"],
+		"next_run_time": stats["next_run_time"],
+		"schedule":      stats["schedule"],
+	})
+}
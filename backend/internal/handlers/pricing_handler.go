@@ -329,3 +329,679 @@ func (h *PricingHandler) GetAllRatePlans(c *gin.Context) {
 		"data":    plans,
 	})
 }
+
+// CreateRatePlan creates a rate plan with eligibility rules
+// POST /api/pricing/plans
+func (h *PricingHandler) CreateRatePlan(c *gin.Context) {
+	var req models.CreateRatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	plan, err := h.pricingService.CreateRatePlan(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to create rate plan",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Rate plan created successfully",
+		"data":    plan,
+	})
+}
+
+// UpdateRatePlan updates a rate plan and its eligibility rules
+// PUT /api/pricing/plans/:id
+func (h *PricingHandler) UpdateRatePlan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid rate plan ID",
+		})
+		return
+	}
+
+	var req models.UpdateRatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	plan, err := h.pricingService.UpdateRatePlan(c.Request.Context(), id, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "rate plan not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   "Failed to update rate plan",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Rate plan updated successfully",
+		"data":    plan,
+	})
+}
+
+// ============================================================================
+// Rate Restriction Handlers
+// ============================================================================
+
+// GetRateRestrictions retrieves stay restrictions for a date range
+// GET /api/pricing/restrictions?start_date=2024-01-01&end_date=2024-01-31&room_type_id=1
+func (h *PricingHandler) GetRateRestrictions(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	if startDate == "" || endDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "start_date and end_date are required",
+		})
+		return
+	}
+
+	var roomTypeID *int
+	if roomTypeIDStr := c.Query("room_type_id"); roomTypeIDStr != "" {
+		id, err := strconv.Atoi(roomTypeIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid room type ID",
+			})
+			return
+		}
+		roomTypeID = &id
+	}
+
+	restrictions, err := h.pricingService.GetRateRestrictions(c.Request.Context(), startDate, endDate, roomTypeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve rate restrictions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    restrictions,
+	})
+}
+
+// BulkUpdateRestrictions sets or clears stay restrictions for a date range
+// POST /api/pricing/restrictions/bulk
+func (h *PricingHandler) BulkUpdateRestrictions(c *gin.Context) {
+	var req models.BulkUpdateRestrictionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	validationErrors, err := h.pricingService.BulkUpdateRestrictions(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to bulk update restrictions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if len(validationErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":           false,
+			"error":             "Cannot update restrictions for some dates",
+			"validation_errors": validationErrors,
+		})
+		return
+	}
+
+	message := "Restrictions bulk updated successfully"
+	if req.Clear {
+		message = "Restrictions cleared successfully"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+	})
+}
+
+// ============================================================================
+// Occupancy Surcharge Handlers
+// ============================================================================
+
+// GetOccupancySurcharges retrieves extra adult, extra child and extra bed charges
+// GET /api/pricing/surcharges?rate_tier_id=1
+func (h *PricingHandler) GetOccupancySurcharges(c *gin.Context) {
+	var rateTierID *int
+	if rateTierIDStr := c.Query("rate_tier_id"); rateTierIDStr != "" {
+		id, err := strconv.Atoi(rateTierIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid rate tier ID",
+			})
+			return
+		}
+		rateTierID = &id
+	}
+
+	surcharges, err := h.pricingService.GetOccupancySurcharges(c.Request.Context(), rateTierID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve occupancy surcharges",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    surcharges,
+	})
+}
+
+// UpsertOccupancySurcharge creates or updates an occupancy surcharge
+// PUT /api/pricing/surcharges
+func (h *PricingHandler) UpsertOccupancySurcharge(c *gin.Context) {
+	var req models.UpsertOccupancySurchargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	surcharge, err := h.pricingService.UpsertOccupancySurcharge(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to save occupancy surcharge",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    surcharge,
+		"message": "Occupancy surcharge saved successfully",
+	})
+}
+
+// DeleteOccupancySurcharge removes an occupancy surcharge
+// DELETE /api/pricing/surcharges/:id
+func (h *PricingHandler) DeleteOccupancySurcharge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid surcharge ID",
+		})
+		return
+	}
+
+	if err := h.pricingService.DeleteOccupancySurcharge(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Failed to delete occupancy surcharge",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Occupancy surcharge deleted successfully",
+	})
+}
+
+// UpdateRoomTypeOccupancy updates base occupancy and extra bed settings of a room type
+// PUT /api/pricing/occupancy/:roomTypeId
+func (h *PricingHandler) UpdateRoomTypeOccupancy(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("roomTypeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid room type ID",
+		})
+		return
+	}
+
+	var req models.UpdateRoomTypeOccupancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.pricingService.UpdateRoomTypeOccupancy(c.Request.Context(), roomTypeID, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to update room type occupancy",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Room type occupancy updated successfully",
+	})
+}
+
+// ============================================================================
+// Dynamic Pricing Rule Handlers
+// ============================================================================
+
+// GetAllPricingRules retrieves all dynamic pricing rules
+// GET /api/pricing/rules
+func (h *PricingHandler) GetAllPricingRules(c *gin.Context) {
+	rules, err := h.pricingService.GetAllPricingRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve pricing rules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rules,
+	})
+}
+
+// CreatePricingRule creates a dynamic pricing rule
+// POST /api/pricing/rules
+func (h *PricingHandler) CreatePricingRule(c *gin.Context) {
+	var req models.CreatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.pricingService.CreatePricingRule(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to create pricing rule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rule,
+		"message": "Pricing rule created successfully",
+	})
+}
+
+// UpdatePricingRule updates a dynamic pricing rule
+// PUT /api/pricing/rules/:id
+func (h *PricingHandler) UpdatePricingRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid pricing rule ID",
+		})
+		return
+	}
+
+	var req models.UpdatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.pricingService.UpdatePricingRule(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to update pricing rule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+		"message": "Pricing rule updated successfully",
+	})
+}
+
+// DeletePricingRule removes a dynamic pricing rule
+// DELETE /api/pricing/rules/:id
+func (h *PricingHandler) DeletePricingRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid pricing rule ID",
+		})
+		return
+	}
+
+	if err := h.pricingService.DeletePricingRule(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Failed to delete pricing rule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pricing rule deleted successfully",
+	})
+}
+
+// GetPricingRuleChanges retrieves the tier changes made by the dynamic pricing engine
+// GET /api/pricing/rules/changes?start_date=2024-01-01&end_date=2024-01-31
+func (h *PricingHandler) GetPricingRuleChanges(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	if startDate == "" || endDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "start_date and end_date are required",
+		})
+		return
+	}
+
+	changes, err := h.pricingService.GetPricingRuleChanges(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve pricing rule changes",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    changes,
+	})
+}
+
+// ============================================================================
+// Day-Use Rate Handlers
+// ============================================================================
+
+// GetAllDayUseRates retrieves every day-use rate
+// GET /api/pricing/day-use
+func (h *PricingHandler) GetAllDayUseRates(c *gin.Context) {
+	rates, err := h.pricingService.GetAllDayUseRates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve day-use rates",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rates,
+	})
+}
+
+// UpsertDayUseRate creates or updates the price of a day-use slot length
+// PUT /api/pricing/day-use
+func (h *PricingHandler) UpsertDayUseRate(c *gin.Context) {
+	var req models.UpsertDayUseRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.pricingService.UpsertDayUseRate(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to save day-use rate",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Day-use rate saved successfully",
+	})
+}
+
+// DeleteDayUseRate removes a day-use rate
+// DELETE /api/pricing/day-use/:id
+func (h *PricingHandler) DeleteDayUseRate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid day-use rate ID",
+		})
+		return
+	}
+
+	if err := h.pricingService.DeleteDayUseRate(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Failed to delete day-use rate",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Day-use rate deleted successfully",
+	})
+}
+
+// ============================================================================
+// Long-Stay Rate Handlers
+// ============================================================================
+
+// GetAllLongStayRates retrieves every long-stay rate
+// GET /api/pricing/long-stay
+func (h *PricingHandler) GetAllLongStayRates(c *gin.Context) {
+	rates, err := h.pricingService.GetAllLongStayRates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve long-stay rates",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rates,
+	})
+}
+
+// UpsertLongStayRate creates or updates the long-stay rate of a threshold
+// PUT /api/pricing/long-stay
+func (h *PricingHandler) UpsertLongStayRate(c *gin.Context) {
+	var req models.UpsertLongStayRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.pricingService.UpsertLongStayRate(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to save long-stay rate",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Long-stay rate saved successfully",
+	})
+}
+
+// DeleteLongStayRate removes a long-stay rate
+// DELETE /api/pricing/long-stay/:id
+func (h *PricingHandler) DeleteLongStayRate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid long-stay rate ID",
+		})
+		return
+	}
+
+	if err := h.pricingService.DeleteLongStayRate(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Failed to delete long-stay rate",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Long-stay rate deleted successfully",
+	})
+}
+
+// ============================================================================
+// Stay-Time Fee Handlers
+// ============================================================================
+
+// GetAllStayTimeFees retrieves every early check-in and late check-out fee tier
+// GET /api/pricing/stay-time-fees
+func (h *PricingHandler) GetAllStayTimeFees(c *gin.Context) {
+	fees, err := h.pricingService.GetAllStayTimeFees(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve stay-time fees",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    fees,
+	})
+}
+
+// UpsertStayTimeFee creates or updates the fee of an early check-in or late check-out tier
+// PUT /api/pricing/stay-time-fees
+func (h *PricingHandler) UpsertStayTimeFee(c *gin.Context) {
+	var req models.UpsertStayTimeFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.pricingService.UpsertStayTimeFee(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to save stay-time fee",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Stay-time fee saved successfully",
+	})
+}
+
+// DeleteStayTimeFee removes a stay-time fee tier
+// DELETE /api/pricing/stay-time-fees/:id
+func (h *PricingHandler) DeleteStayTimeFee(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid stay-time fee ID",
+		})
+		return
+	}
+
+	if err := h.pricingService.DeleteStayTimeFee(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Failed to delete stay-time fee",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Stay-time fee deleted successfully",
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/events"
+	"github.com/hotel-booking-system/backend/internal/middleware"
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/utils"
+)
+
+// roomEventHeartbeat is how often an idle stream sends a comment, so proxies keep
+// the connection open and clients notice when it drops
+const roomEventHeartbeat = 25 * time.Second
+
+// RoomEventHandler streams room events to the room status board
+type RoomEventHandler struct {
+	bus *events.Bus
+}
+
+// NewRoomEventHandler creates a new room event handler
+func NewRoomEventHandler(bus *events.Bus) *RoomEventHandler {
+	return &RoomEventHandler{
+		bus: bus,
+	}
+}
+
+// StreamRoomEvents streams room status changes, arrivals and departures
+// @Summary Stream room events
+// @Description Stream room status changes, arrivals and departures as Server-Sent Events. Each event is named after its type and carries the room's status after the change. Housekeepers and engineers do not receive guest details, and engineers only receive status changes. The stream needs the Authorization header, so clients read it with fetch rather than EventSource. Clients should reload the room status board after reconnecting, since events sent while disconnected are not replayed.
+// @Tags rooms
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param types query string false "Comma separated event types (room_status, arrival, departure)"
+// @Success 200 {object} models.RoomEvent
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/rooms/status/stream [get]
+func (h *RoomEventHandler) StreamRoomEvents(c *gin.Context) {
+	role, _ := middleware.GetUserRole(c)
+
+	var types []string
+	if value := c.Query("types"); value != "" {
+		for _, t := range strings.Split(value, ",") {
+			t = strings.TrimSpace(t)
+			switch t {
+			case models.RoomEventStatus, models.RoomEventArrival, models.RoomEventDeparture:
+				types = append(types, t)
+			default:
+				utils.ErrorResponse(c, http.StatusBadRequest, "Invalid event type: "+t)
+				return
+			}
+		}
+	}
+
+	// The server's write timeout would otherwise end the stream
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	sub := h.bus.Subscribe(role, types)
+	defer h.bus.Unsubscribe(sub)
+
+	c.SSEvent("ready", gin.H{"role": role})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(roomEventHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			c.SSEvent(event.Type, event)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/middleware"
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/internal/service"
 	"github.com/hotel-booking-system/backend/pkg/utils"
@@ -30,7 +33,19 @@ func NewRoomHandler(roomService *service.RoomService) *RoomHandler {
 // @Produce json
 // @Param checkIn query string true "Check-in date (YYYY-MM-DD)"
 // @Param checkOut query string true "Check-out date (YYYY-MM-DD)"
-// @Param guests query int true "Number of guests"
+// @Param guests query int false "Number of guests (required without occupancy)"
+// @Param rooms query int false "Number of rooms; more than one returns room combinations"
+// @Param occupancy query []int false "Guests per room, one value per room"
+// @Param minPrice query number false "Lowest price, per night or total by priceBasis"
+// @Param maxPrice query number false "Highest price, per night or total by priceBasis"
+// @Param priceBasis query string false "night (default) or total"
+// @Param amenities query []int false "Amenity IDs the room type must have"
+// @Param bedType query string false "Bed type, e.g. King"
+// @Param view query string false "View, e.g. City"
+// @Param accessible query bool false "Only accessible room types"
+// @Param sortBy query string false "price, size or popularity"
+// @Param sortOrder query string false "asc or desc"
+// @Param flexible query bool false "Add the cheapest price of check-in ±3 days"
 // @Success 200 {object} models.SearchRoomsResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -48,8 +63,13 @@ func (h *RoomHandler) SearchRooms(c *gin.Context) {
 		return
 	}
 
-	log.Printf("INFO [SearchRooms]: Request - CheckIn: %s, CheckOut: %s, Guests: %d", 
-		req.CheckIn, req.CheckOut, req.Guests)
+	// Signed-in guests may see members-only rate plans
+	if role, ok := middleware.GetUserRole(c); ok && role == "GUEST" {
+		req.IsMember = true
+	}
+
+	log.Printf("INFO [SearchRooms]: Request - CheckIn: %s, CheckOut: %s, Guests: %d, Rooms: %d", 
+		req.CheckIn, req.CheckOut, req.Guests, req.Rooms)
 
 	response, err := h.roomService.SearchAvailableRooms(c.Request.Context(), &req)
 	if err != nil {
@@ -61,7 +81,12 @@ func (h *RoomHandler) SearchRooms(c *gin.Context) {
 		if err.Error() == "รูปแบบวันที่ check-in ไม่ถูกต้อง" ||
 			err.Error() == "รูปแบบวันที่ check-out ไม่ถูกต้อง" ||
 			err.Error() == "วันที่ check-out ต้องอยู่หลังวันที่ check-in" ||
-			err.Error() == "วันที่ check-in ต้องไม่อยู่ในอดีต" {
+			err.Error() == "วันที่ check-in ต้องไม่อยู่ในอดีต" ||
+			err.Error() == "กรุณาระบุจำนวนผู้เข้าพัก" ||
+			err.Error() == "จำนวนห้องต้องไม่เกินจำนวนผู้เข้าพัก" ||
+			err.Error() == "at least one adult is required" ||
+			err.Error() == "each room requires at least one adult" ||
+			err.Error() == "ราคาต่ำสุดต้องไม่เกินราคาสูงสุด" {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
 				"error":   err.Error(),
@@ -179,6 +204,72 @@ func (h *RoomHandler) GetRoomTypePricing(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, roomType)
 }
 
+// SearchDayUse lists the day-use slots of a date
+// @Summary Search day-use slots
+// @Tags rooms
+// @Produce json
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Param guests query int true "Number of guests"
+// @Param room_type_id query int false "Room Type ID"
+// @Param duration query int false "Slot length in hours"
+// @Success 200 {object} models.DayUseSearchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/rooms/day-use [get]
+func (h *RoomHandler) SearchDayUse(c *gin.Context) {
+	var req models.DayUseSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.roomService.SearchDayUse(c.Request.Context(), &req)
+	if err != nil {
+		if err.Error() == "รูปแบบวันที่ไม่ถูกต้อง" ||
+			err.Error() == "วันที่ต้องไม่อยู่ในอดีต" {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("ERROR [SearchDayUse]: %v", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search day-use slots")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, response)
+}
+
+// GetAvailabilityCalendar returns the availability and lowest rate of each day of a month
+// @Summary Get availability calendar
+// @Tags rooms
+// @Produce json
+// @Param month query string true "Month (YYYY-MM)"
+// @Param room_type_id query int false "Room Type ID"
+// @Success 200 {object} models.AvailabilityCalendar
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/rooms/calendar [get]
+func (h *RoomHandler) GetAvailabilityCalendar(c *gin.Context) {
+	var req models.AvailabilityCalendarRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	calendar, err := h.roomService.GetAvailabilityCalendar(c.Request.Context(), &req)
+	if err != nil {
+		if err.Error() == "รูปแบบเดือนไม่ถูกต้อง" ||
+			strings.HasPrefix(err.Error(), "เดือนต้องอยู่ระหว่าง") {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("ERROR [GetAvailabilityCalendar]: %v", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get availability calendar")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, calendar)
+}
+
 // GetRoomStatusDashboard retrieves all rooms with their current status
 // @Summary Get room status dashboard
 // @Description Get all rooms with their current occupancy and housekeeping status
@@ -199,3 +290,541 @@ func (h *RoomHandler) GetRoomStatusDashboard(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, dashboard)
 }
+
+// managementError responds to a failed room management request: 404 when the room
+// type, room or amenity does not exist and 400 otherwise
+func managementError(c *gin.Context, err error) {
+	if strings.HasSuffix(err.Error(), "not found") {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+}
+
+// inventoryConflictResponse responds with the dates a change would overbook
+func inventoryConflictResponse(c *gin.Context, conflicts []models.InventoryValidationError) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success":           false,
+		"error":             "Cannot change rooms or allotment for some dates due to existing bookings",
+		"validation_errors": conflicts,
+	})
+}
+
+// CreateRoomType creates a room type
+// @Summary Create room type
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateRoomTypeRequest true "Room type"
+// @Success 201 {object} models.RoomType
+// @Failure 400 {object} map[string]string
+// @Router /api/rooms/types [post]
+func (h *RoomHandler) CreateRoomType(c *gin.Context) {
+	var req models.CreateRoomTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	roomType, err := h.roomService.CreateRoomType(c.Request.Context(), &req)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, roomType)
+}
+
+// UpdateRoomType updates a room type
+// @Summary Update room type
+// @Description A new default allotment is applied to future dates still at the previous default
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param request body models.UpdateRoomTypeRequest true "Fields to change"
+// @Success 200 {object} models.RoomType
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/types/{id} [put]
+func (h *RoomHandler) UpdateRoomType(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+
+	var req models.UpdateRoomTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	roomType, conflicts, err := h.roomService.UpdateRoomType(c.Request.Context(), roomTypeID, &req)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+	if len(conflicts) > 0 {
+		inventoryConflictResponse(c, conflicts)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, roomType)
+}
+
+// DeleteRoomType deletes a room type that has no rooms or bookings
+// @Summary Delete room type
+// @Tags rooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/types/{id} [delete]
+func (h *RoomHandler) DeleteRoomType(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+
+	if err := h.roomService.DeleteRoomType(c.Request.Context(), roomTypeID); err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Room type deleted successfully"})
+}
+
+// SetRoomTypeAmenities replaces the amenities of a room type
+// @Summary Set room type amenities
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param request body models.SetRoomTypeAmenitiesRequest true "Amenity IDs"
+// @Success 200 {array} models.Amenity
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/types/{id}/amenities [put]
+func (h *RoomHandler) SetRoomTypeAmenities(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+
+	var req models.SetRoomTypeAmenitiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	amenities, err := h.roomService.SetRoomTypeAmenities(c.Request.Context(), roomTypeID, req.AmenityIDs)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, amenities)
+}
+
+// GetRooms lists the physical rooms
+// @Summary Get rooms
+// @Tags rooms
+// @Produce json
+// @Security BearerAuth
+// @Param room_type_id query int false "Room Type ID"
+// @Param include_retired query bool false "Include retired rooms"
+// @Success 200 {array} models.Room
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/rooms/units [get]
+func (h *RoomHandler) GetRooms(c *gin.Context) {
+	var roomTypeID *int
+	if value := c.Query("room_type_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+			return
+		}
+		roomTypeID = &id
+	}
+	includeRetired := c.Query("include_retired") == "true"
+
+	rooms, err := h.roomService.GetRooms(c.Request.Context(), roomTypeID, includeRetired)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get rooms")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, rooms)
+}
+
+// CreateRoom adds a physical room
+// @Summary Create room
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateRoomRequest true "Room"
+// @Success 201 {object} models.Room
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/units [post]
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	var req models.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	room, err := h.roomService.CreateRoom(c.Request.Context(), &req)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, room)
+}
+
+// UpdateRoom updates a physical room, including moving it to another room type
+// @Summary Update room
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Param request body models.UpdateRoomRequest true "Fields to change"
+// @Success 200 {object} models.Room
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/units/{id} [put]
+func (h *RoomHandler) UpdateRoom(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	var req models.UpdateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	room, conflicts, err := h.roomService.UpdateRoom(c.Request.Context(), roomID, &req, actingStaffID(c))
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+	if len(conflicts) > 0 {
+		inventoryConflictResponse(c, conflicts)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, room)
+}
+
+// RetireRoom takes a room out of service; its history is kept
+// @Summary Retire room
+// @Tags rooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/units/{id} [delete]
+func (h *RoomHandler) RetireRoom(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	conflicts, err := h.roomService.RetireRoom(c.Request.Context(), roomID, actingStaffID(c))
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+	if len(conflicts) > 0 {
+		inventoryConflictResponse(c, conflicts)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Room retired successfully"})
+}
+
+// GetAllAmenities lists every amenity
+// @Summary Get amenities
+// @Tags rooms
+// @Produce json
+// @Success 200 {array} models.Amenity
+// @Failure 500 {object} map[string]string
+// @Router /api/rooms/amenities [get]
+func (h *RoomHandler) GetAllAmenities(c *gin.Context) {
+	amenities, err := h.roomService.GetAllAmenities(c.Request.Context())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get amenities")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, amenities)
+}
+
+// CreateAmenity creates an amenity
+// @Summary Create amenity
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateAmenityRequest true "Amenity"
+// @Success 201 {object} models.Amenity
+// @Failure 400 {object} map[string]string
+// @Router /api/rooms/amenities [post]
+func (h *RoomHandler) CreateAmenity(c *gin.Context) {
+	var req models.CreateAmenityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	amenity, err := h.roomService.CreateAmenity(c.Request.Context(), &req)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, amenity)
+}
+
+// UpdateAmenity updates an amenity
+// @Summary Update amenity
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Amenity ID"
+// @Param request body models.UpdateAmenityRequest true "Fields to change"
+// @Success 200 {object} models.Amenity
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/amenities/{id} [put]
+func (h *RoomHandler) UpdateAmenity(c *gin.Context) {
+	amenityID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid amenity ID")
+		return
+	}
+
+	var req models.UpdateAmenityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	amenity, err := h.roomService.UpdateAmenity(c.Request.Context(), amenityID, &req)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, amenity)
+}
+
+// DeleteAmenity deletes an amenity and removes it from every room type
+// @Summary Delete amenity
+// @Tags rooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Amenity ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/amenities/{id} [delete]
+func (h *RoomHandler) DeleteAmenity(c *gin.Context) {
+	amenityID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid amenity ID")
+		return
+	}
+
+	if err := h.roomService.DeleteAmenity(c.Request.Context(), amenityID); err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Amenity deleted successfully"})
+}
+
+// UploadRoomTypeImage adds an image to the end of a room type's gallery
+// @Summary Upload room type image
+// @Description The image is stored as thumbnail, medium and large JPEGs without EXIF metadata
+// @Tags rooms
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param file formData file true "JPEG, PNG or GIF image"
+// @Param caption formData string false "Caption"
+// @Success 201 {object} models.RoomTypeImage
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/rooms/types/{id}/images [post]
+func (h *RoomHandler) UploadRoomTypeImage(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Image file is required")
+		return
+	}
+	if fileHeader.Size > models.MaxRoomTypeImageSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Image file must be 10 MB or smaller")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read image file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxRoomTypeImageSize))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read image file")
+		return
+	}
+
+	var caption *string
+	if value := strings.TrimSpace(c.PostForm("caption")); value != "" {
+		caption = &value
+	}
+
+	image, err := h.roomService.UploadRoomTypeImage(c.Request.Context(), roomTypeID, data, caption)
+	if err != nil {
+		if err.Error() == "image storage is not configured" {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, image)
+}
+
+// UpdateRoomTypeImage changes the caption of a gallery image
+// @Summary Update room type image
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param imageId path int true "Image ID"
+// @Param request body models.UpdateRoomTypeImageRequest true "Caption"
+// @Success 200 {object} models.RoomTypeImage
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/types/{id}/images/{imageId} [put]
+func (h *RoomHandler) UpdateRoomTypeImage(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid image ID")
+		return
+	}
+
+	var req models.UpdateRoomTypeImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	image, err := h.roomService.UpdateRoomTypeImage(c.Request.Context(), roomTypeID, imageID, &req)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, image)
+}
+
+// ReorderRoomTypeImages sets the display order of a room type's gallery
+// @Summary Reorder room type images
+// @Description image_ids must list every image of the room type once, in the new order
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param request body models.ReorderRoomTypeImagesRequest true "Image IDs in display order"
+// @Success 200 {array} models.RoomTypeImage
+// @Failure 400 {object} map[string]string
+// @Router /api/rooms/types/{id}/images [put]
+func (h *RoomHandler) ReorderRoomTypeImages(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+
+	var req models.ReorderRoomTypeImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	images, err := h.roomService.ReorderRoomTypeImages(c.Request.Context(), roomTypeID, req.ImageIDs)
+	if err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, images)
+}
+
+// DeleteRoomTypeImage removes an image from a room type's gallery
+// @Summary Delete room type image
+// @Tags rooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/rooms/types/{id}/images/{imageId} [delete]
+func (h *RoomHandler) DeleteRoomTypeImage(c *gin.Context) {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid room type ID")
+		return
+	}
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid image ID")
+		return
+	}
+
+	if err := h.roomService.DeleteRoomTypeImage(c.Request.Context(), roomTypeID, imageID); err != nil {
+		managementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Image deleted successfully"})
+}
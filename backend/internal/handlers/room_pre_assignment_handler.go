@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/jobs"
+)
+
+// RoomPreAssignmentHandler handles HTTP requests for the room pre-assignment job
+type RoomPreAssignmentHandler struct {
+	preAssignment *jobs.RoomPreAssignmentJob
+}
+
+// NewRoomPreAssignmentHandler creates a new room pre-assignment handler
+func NewRoomPreAssignmentHandler(preAssignment *jobs.RoomPreAssignmentJob) *RoomPreAssignmentHandler {
+	return &RoomPreAssignmentHandler{
+		preAssignment: preAssignment,
+	}
+}
+
+// TriggerManual pre-assigns rooms to the arrivals of a date (default tomorrow) immediately
+// POST /api/admin/room-pre-assignment/trigger?date=YYYY-MM-DD
+func (h *RoomPreAssignmentHandler) TriggerManual(c *gin.Context) {
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	if value := c.Query("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		date = parsed
+	}
+
+	result, err := h.preAssignment.RunManual(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to pre-assign rooms",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           result.Success,
+		"arrival_date":      result.ArrivalDate.Format("2006-01-02"),
+		"rooms_assigned":    result.RoomsAssigned,
+		"manual_kept":       result.ManualKept,
+		"unassigned":        result.Unassigned,
+		"preferences_unmet": result.PreferencesUnmet,
+		"timestamp":         result.Timestamp,
+		"execution_time":    result.ExecutionTime.String(),
+		"message":           "Rooms pre-assigned successfully",
+	})
+}
+
+// GetStatus returns the current status of the room pre-assignment job
+// GET /api/admin/room-pre-assignment/status
+func (h *RoomPreAssignmentHandler) GetStatus(c *gin.Context) {
+	stats := h.preAssignment.GetStats()
+
+	c.JSON(http.StatusOK, gin.H{
+		"is_running":    stats["is_running"],
+		"next_run_time": stats["next_run_time"],
+		"schedule":      stats["schedule"],
+	})
+}
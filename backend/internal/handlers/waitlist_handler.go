@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/middleware"
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/internal/service"
+)
+
+// WaitlistHandler handles waitlist HTTP requests
+type WaitlistHandler struct {
+	waitlistService *service.WaitlistService
+}
+
+// NewWaitlistHandler creates a new waitlist handler
+func NewWaitlistHandler(waitlistService *service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: waitlistService,
+	}
+}
+
+// guestIDFromContext returns the guest ID when the caller is a signed-in guest
+func guestIDFromContext(c *gin.Context) *int {
+	role, _ := middleware.GetUserRole(c)
+	if role != "GUEST" {
+		return nil
+	}
+	if userID, ok := middleware.GetUserID(c); ok {
+		return &userID
+	}
+	return nil
+}
+
+// waitlistAccess returns the guest ID a signed-in guest's access is limited to, or nil for
+// the front desk, who may see every entry. ok is false for any other caller.
+func waitlistAccess(c *gin.Context) (guestID *int, ok bool) {
+	if middleware.IsGuest(c) {
+		guestID = guestIDFromContext(c)
+		return guestID, guestID != nil
+	}
+	return nil, middleware.HasAnyRole(c, "RECEPTIONIST", "MANAGER")
+}
+
+// JoinWaitlist handles POST /api/waitlist
+// Works with or without authentication (guest booking)
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	var req models.JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, ahead, err := h.waitlistService.JoinWaitlist(c.Request.Context(), guestIDFromContext(c), &req)
+	if err != nil {
+		if err.Error() == "room type not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "failed to") {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"entry":    entry,
+		"position": ahead + 1,
+		"message":  "Added to waitlist. We will hold a room and notify you when one becomes available.",
+	})
+}
+
+// GetMyWaitlist handles GET /api/waitlist/mine
+func (h *WaitlistHandler) GetMyWaitlist(c *gin.Context) {
+	guestID := guestIDFromContext(c)
+	if guestID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only guests have a waitlist"})
+		return
+	}
+
+	entries, err := h.waitlistService.GetGuestWaitlist(c.Request.Context(), *guestID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// GetWaitlistEntry handles GET /api/waitlist/:id
+func (h *WaitlistHandler) GetWaitlistEntry(c *gin.Context) {
+	waitlistID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid waitlist ID"})
+		return
+	}
+
+	guestID, ok := waitlistAccess(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "unauthorized to view this waitlist entry"})
+		return
+	}
+
+	entry, err := h.waitlistService.GetWaitlistEntry(c.Request.Context(), waitlistID, guestID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if entry == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Waitlist entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// CancelWaitlistEntry handles POST /api/waitlist/:id/cancel
+func (h *WaitlistHandler) CancelWaitlistEntry(c *gin.Context) {
+	waitlistID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid waitlist ID"})
+		return
+	}
+
+	guestID, ok := waitlistAccess(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "unauthorized to view this waitlist entry"})
+		return
+	}
+
+	err = h.waitlistService.CancelWaitlistEntry(c.Request.Context(), waitlistID, guestID)
+	if err != nil {
+		switch err.Error() {
+		case "waitlist entry not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "unauthorized to view this waitlist entry":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "waitlist entry cannot be cancelled":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Waitlist entry cancelled",
+	})
+}
+
+// GetWaitlist handles GET /api/waitlist?status=Waiting (Receptionist + Manager)
+func (h *WaitlistHandler) GetWaitlist(c *gin.Context) {
+	entries, err := h.waitlistService.GetWaitlist(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid waitlist status") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/jobs"
+)
+
+// WaitlistMatcherHandler handles HTTP requests for waitlist matcher operations
+type WaitlistMatcherHandler struct {
+	matcher *jobs.WaitlistMatcherJob
+}
+
+// NewWaitlistMatcherHandler creates a new waitlist matcher handler
+func NewWaitlistMatcherHandler(matcher *jobs.WaitlistMatcherJob) *WaitlistMatcherHandler {
+	return &WaitlistMatcherHandler{
+		matcher: matcher,
+	}
+}
+
+// TriggerManual triggers a manual waitlist matching run
+// POST /api/admin/waitlist-matcher/trigger
+func (h *WaitlistMatcherHandler) TriggerManual(c *gin.Context) {
+	result, err := h.matcher.RunManual()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to execute waitlist matching",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        result.Success,
+		"offers_made":    result.OffersMade,
+		"offers_expired": result.OffersExpired,
+		"entries_lapsed": result.EntriesLapsed,
+		"events":         result.Events,
+		"timestamp":      result.Timestamp,
+		"execution_time": result.ExecutionTime.String(),
+		"message":        "Waitlist matching executed successfully",
+	})
+}
+
+// GetStatus returns the current status of the waitlist matcher job
+// GET /api/admin/waitlist-matcher/status
+func (h *WaitlistMatcherHandler) GetStatus(c *gin.Context) {
+	stats := h.matcher.GetStats()
+
+	c.JSON(http.StatusOK, gin.H{
+		"is_running":    stats["is_running"],
+		"next_run_time": stats["next_run_time"],
+		"schedule":      stats["schedule"],
+		"offer_window":  stats["offer_window"],
+	})
+}
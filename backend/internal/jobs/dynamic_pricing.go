@@ -0,0 +1,602 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/robfig/cron/v3"
+)
+
+// DynamicPricingJob moves pricing calendar dates to higher rate tiers based on
+// forecast occupancy and booking pace
+type DynamicPricingJob struct {
+	db       *database.DB
+	cron     *cron.Cron
+	logger   *log.Logger
+	onChange func()
+	mu       sync.Mutex
+}
+
+// DynamicPricingResult contains the results of a dynamic pricing run
+type DynamicPricingResult struct {
+	Timestamp      time.Time
+	DryRun         bool
+	RulesEvaluated int
+	DatesEvaluated int
+	Changes        []models.PricingRuleChange
+	Success        bool
+	ErrorMessage   string
+	ExecutionTime  time.Duration
+}
+
+// pricingTier is a rate tier and its rank (rate_tiers.display_order)
+type pricingTier struct {
+	name string
+	rank int
+}
+
+// inventoryLevel is the forecast occupancy of one room type on one date
+type inventoryLevel struct {
+	allotment int
+	occupied  int
+}
+
+// pricingSnapshot is everything the engine reads before evaluating the rules
+type pricingSnapshot struct {
+	today     time.Time
+	tiers     map[int]pricingTier
+	prices    map[int]map[int]float64           // rate tier -> room type -> default plan price
+	calendar  map[string]int                    // date -> current rate tier
+	inventory map[string]map[int]inventoryLevel // date -> room type -> occupancy
+	pickup    map[int]map[string]map[int]int    // pickup days -> date -> room type -> rooms picked up
+}
+
+// NewDynamicPricingJob creates a new dynamic pricing job instance
+func NewDynamicPricingJob(db *database.DB) *DynamicPricingJob {
+	logger := log.New(log.Writer(), "[DYNAMIC-PRICING] ", log.LstdFlags|log.Lshortfile)
+
+	return &DynamicPricingJob{
+		db:     db,
+		cron:   cron.New(),
+		logger: logger,
+	}
+}
+
+// OnChange registers a callback invoked after a run changes the pricing calendar
+// (used to invalidate cached calendars)
+func (j *DynamicPricingJob) OnChange(fn func()) {
+	j.onChange = fn
+}
+
+// Start begins the scheduled dynamic pricing job
+// Runs daily at 03:00 AM, after the night audit
+func (j *DynamicPricingJob) Start() error {
+	j.logger.Println("Initializing dynamic pricing scheduler...")
+
+	_, err := j.cron.AddFunc("0 3 * * *", func() {
+		j.logger.Println("Starting scheduled dynamic pricing run...")
+		result := j.Run(false)
+		j.logResult(result)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule dynamic pricing: %w", err)
+	}
+
+	j.cron.Start()
+	j.logger.Println("Dynamic pricing scheduler started successfully (runs daily at 03:00 AM)")
+
+	return nil
+}
+
+// Stop gracefully stops the dynamic pricing scheduler
+func (j *DynamicPricingJob) Stop() {
+	j.logger.Println("Stopping dynamic pricing scheduler...")
+	ctx := j.cron.Stop()
+	<-ctx.Done()
+	j.logger.Println("Dynamic pricing scheduler stopped")
+}
+
+// Run evaluates the active pricing rules. In dry-run mode the changes are
+// returned without touching the pricing calendar or the change log.
+func (j *DynamicPricingJob) Run(dryRun bool) DynamicPricingResult {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	startTime := time.Now()
+	result := DynamicPricingResult{
+		Timestamp: startTime,
+		DryRun:    dryRun,
+		Changes:   []models.PricingRuleChange{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	rules, err := j.loadRules(ctx)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		j.logger.Printf("ERROR: %s", result.ErrorMessage)
+		result.ExecutionTime = time.Since(startTime)
+		return result
+	}
+	result.RulesEvaluated = len(rules)
+
+	if len(rules) > 0 {
+		snapshot, err := j.loadSnapshot(ctx, rules, startTime)
+		if err != nil {
+			result.ErrorMessage = err.Error()
+			j.logger.Printf("ERROR: %s", result.ErrorMessage)
+			result.ExecutionTime = time.Since(startTime)
+			return result
+		}
+		result.DatesEvaluated = len(snapshot.inventory)
+		result.Changes = planPricingChanges(rules, snapshot)
+	}
+
+	if !dryRun && len(result.Changes) > 0 {
+		if err := j.applyChanges(ctx, result.Changes); err != nil {
+			result.ErrorMessage = err.Error()
+			j.logger.Printf("ERROR: %s", result.ErrorMessage)
+			result.ExecutionTime = time.Since(startTime)
+			return result
+		}
+		if j.onChange != nil {
+			j.onChange()
+		}
+	}
+
+	result.Success = true
+	result.ExecutionTime = time.Since(startTime)
+	return result
+}
+
+// RunManual executes the dynamic pricing engine manually and returns the result
+func (j *DynamicPricingJob) RunManual(dryRun bool) (DynamicPricingResult, error) {
+	j.logger.Printf("Manual dynamic pricing triggered (dry run: %t)", dryRun)
+	result := j.Run(dryRun)
+
+	if !result.Success {
+		return result, fmt.Errorf("dynamic pricing failed: %s", result.ErrorMessage)
+	}
+
+	return result, nil
+}
+
+// planPricingChanges evaluates the rules for every date in the snapshot. A date moves to the
+// highest-ranked target tier of the rules it triggers, as long as that tier ranks above the
+// current one and its reference price stays within the rule's floor and ceiling.
+func planPricingChanges(rules []models.PricingRule, snapshot *pricingSnapshot) []models.PricingRuleChange {
+	dates := make([]string, 0, len(snapshot.inventory))
+	for date := range snapshot.inventory {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	changes := []models.PricingRuleChange{}
+	for _, dateKey := range dates {
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil {
+			continue
+		}
+		daysOut := int(date.Sub(snapshot.today).Hours() / 24)
+
+		currentRank := math.MinInt
+		var oldTierID *int
+		if tierID, ok := snapshot.calendar[dateKey]; ok {
+			tierID := tierID
+			oldTierID = &tierID
+			currentRank = snapshot.tiers[tierID].rank
+		}
+
+		var best *models.PricingRule
+		var bestOccupancy float64
+		var bestPickup int
+		bestRank := currentRank
+		for i := range rules {
+			rule := &rules[i]
+			if !rule.IsActive || daysOut < 0 || daysOut >= rule.LookaheadDays {
+				continue
+			}
+
+			target, ok := snapshot.tiers[rule.TargetRateTierID]
+			if !ok || target.rank <= bestRank {
+				continue
+			}
+
+			occupancy := snapshot.occupancyPercent(dateKey, rule.RoomTypeID)
+			pickup := 0
+			if rule.PickupDays != nil {
+				pickup = snapshot.pickupCount(*rule.PickupDays, dateKey, rule.RoomTypeID)
+			}
+
+			triggered := false
+			switch rule.RuleType {
+			case models.PricingRuleOccupancy:
+				triggered = rule.OccupancyThreshold != nil && occupancy >= *rule.OccupancyThreshold
+			case models.PricingRulePickup:
+				triggered = rule.PickupThreshold != nil && pickup >= *rule.PickupThreshold
+			}
+			if !triggered || !snapshot.withinPriceBand(rule) {
+				continue
+			}
+
+			best = rule
+			bestRank = target.rank
+			bestOccupancy = occupancy
+			bestPickup = pickup
+		}
+
+		if best == nil {
+			continue
+		}
+
+		ruleID := best.RuleID
+		change := models.PricingRuleChange{
+			Date:             date,
+			RuleID:           &ruleID,
+			RuleName:         best.Name,
+			OldRateTierID:    oldTierID,
+			NewRateTierID:    best.TargetRateTierID,
+			NewRateTierName:  snapshot.tiers[best.TargetRateTierID].name,
+			OccupancyPercent: bestOccupancy,
+			Pickup:           bestPickup,
+		}
+		if oldTierID != nil {
+			name := snapshot.tiers[*oldTierID].name
+			change.OldRateTierName = &name
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// occupancyPercent returns the forecast occupancy of a date for one room type or the whole hotel
+func (s *pricingSnapshot) occupancyPercent(date string, roomTypeID *int) float64 {
+	var allotment, occupied int
+	for id, level := range s.inventory[date] {
+		if roomTypeID != nil && id != *roomTypeID {
+			continue
+		}
+		allotment += level.allotment
+		occupied += level.occupied
+	}
+	if allotment == 0 {
+		return 0
+	}
+	return math.Round(float64(occupied)/float64(allotment)*10000) / 100
+}
+
+// pickupCount returns the rooms picked up for a date in the last pickupDays days
+func (s *pricingSnapshot) pickupCount(pickupDays int, date string, roomTypeID *int) int {
+	total := 0
+	for id, rooms := range s.pickup[pickupDays][date] {
+		if roomTypeID == nil || id == *roomTypeID {
+			total += rooms
+		}
+	}
+	return total
+}
+
+// withinPriceBand checks the reference price of the rule's target tier against its floor and
+// ceiling: the default plan price of the rule's room type, or the cheapest room type when the
+// rule covers the whole hotel
+func (s *pricingSnapshot) withinPriceBand(rule *models.PricingRule) bool {
+	if rule.FloorPrice == nil && rule.CeilingPrice == nil {
+		return true
+	}
+
+	prices := s.prices[rule.TargetRateTierID]
+	var price float64
+	found := false
+	for id, p := range prices {
+		if rule.RoomTypeID != nil {
+			if id == *rule.RoomTypeID {
+				price, found = p, true
+			}
+			continue
+		}
+		if !found || p < price {
+			price, found = p, true
+		}
+	}
+	if !found {
+		return false
+	}
+
+	if rule.FloorPrice != nil && price < *rule.FloorPrice {
+		return false
+	}
+	if rule.CeilingPrice != nil && price > *rule.CeilingPrice {
+		return false
+	}
+	return true
+}
+
+// loadRules loads the active pricing rules
+func (j *DynamicPricingJob) loadRules(ctx context.Context) ([]models.PricingRule, error) {
+	rows, err := j.db.Pool.Query(ctx, `
+		SELECT rule_id, name, rule_type, room_type_id, occupancy_threshold, pickup_days, pickup_threshold,
+		       target_rate_tier_id, floor_price, ceiling_price, lookahead_days, is_active
+		FROM pricing_rules
+		WHERE is_active = TRUE
+		ORDER BY rule_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pricing rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.PricingRule
+	for rows.Next() {
+		var rule models.PricingRule
+		if err := rows.Scan(
+			&rule.RuleID,
+			&rule.Name,
+			&rule.RuleType,
+			&rule.RoomTypeID,
+			&rule.OccupancyThreshold,
+			&rule.PickupDays,
+			&rule.PickupThreshold,
+			&rule.TargetRateTierID,
+			&rule.FloorPrice,
+			&rule.CeilingPrice,
+			&rule.LookaheadDays,
+			&rule.IsActive,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan pricing rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load pricing rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// loadSnapshot reads tiers, reference prices, the calendar, inventory and pickup
+// for the dates covered by the rules
+func (j *DynamicPricingJob) loadSnapshot(ctx context.Context, rules []models.PricingRule, now time.Time) (*pricingSnapshot, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lookahead := 0
+	pickupWindows := map[int]bool{}
+	for _, rule := range rules {
+		if rule.LookaheadDays > lookahead {
+			lookahead = rule.LookaheadDays
+		}
+		if rule.PickupDays != nil {
+			pickupWindows[*rule.PickupDays] = true
+		}
+	}
+	end := today.AddDate(0, 0, lookahead)
+
+	snapshot := &pricingSnapshot{
+		today:     today,
+		tiers:     map[int]pricingTier{},
+		prices:    map[int]map[int]float64{},
+		calendar:  map[string]int{},
+		inventory: map[string]map[int]inventoryLevel{},
+		pickup:    map[int]map[string]map[int]int{},
+	}
+
+	// Rate tiers and their rank
+	rows, err := j.db.Pool.Query(ctx, `SELECT rate_tier_id, name, display_order FROM rate_tiers WHERE is_active = TRUE`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rate tiers: %w", err)
+	}
+	for rows.Next() {
+		var id int
+		var tier pricingTier
+		if err := rows.Scan(&id, &tier.name, &tier.rank); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan rate tier: %w", err)
+		}
+		snapshot.tiers[id] = tier
+	}
+	rows.Close()
+
+	// Reference prices: the default (first non-derived) rate plan
+	rows, err = j.db.Pool.Query(ctx, `
+		SELECT rp.rate_tier_id, rp.room_type_id, rp.price
+		FROM rate_pricing rp
+		WHERE rp.rate_plan_id = (
+			SELECT rate_plan_id FROM rate_plans
+			WHERE is_active = TRUE AND base_rate_plan_id IS NULL
+			ORDER BY rate_plan_id
+			LIMIT 1
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reference prices: %w", err)
+	}
+	for rows.Next() {
+		var tierID, roomTypeID int
+		var price float64
+		if err := rows.Scan(&tierID, &roomTypeID, &price); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan reference price: %w", err)
+		}
+		if snapshot.prices[tierID] == nil {
+			snapshot.prices[tierID] = map[int]float64{}
+		}
+		snapshot.prices[tierID][roomTypeID] = price
+	}
+	rows.Close()
+
+	// Current calendar
+	rows, err = j.db.Pool.Query(ctx, `SELECT date, rate_tier_id FROM pricing_calendar WHERE date >= $1 AND date < $2`, today, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pricing calendar: %w", err)
+	}
+	for rows.Next() {
+		var date time.Time
+		var tierID int
+		if err := rows.Scan(&date, &tierID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan pricing calendar: %w", err)
+		}
+		snapshot.calendar[date.Format("2006-01-02")] = tierID
+	}
+	rows.Close()
+
+	// Forecast occupancy: confirmed and held rooms against the allotment
+	rows, err = j.db.Pool.Query(ctx, `
+		SELECT date, room_type_id, allotment, booked_count + tentative_count
+		FROM room_inventory
+		WHERE date >= $1 AND date < $2
+	`, today, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load inventory: %w", err)
+	}
+	for rows.Next() {
+		var date time.Time
+		var roomTypeID int
+		var level inventoryLevel
+		if err := rows.Scan(&date, &roomTypeID, &level.allotment, &level.occupied); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan inventory: %w", err)
+		}
+		key := date.Format("2006-01-02")
+		if snapshot.inventory[key] == nil {
+			snapshot.inventory[key] = map[int]inventoryLevel{}
+		}
+		snapshot.inventory[key][roomTypeID] = level
+	}
+	rows.Close()
+
+	// Pickup: rooms booked in the last N days, per stay date
+	for days := range pickupWindows {
+		rows, err = j.db.Pool.Query(ctx, `
+			SELECT d::date, bd.room_type_id, COUNT(*)::int
+			FROM booking_details bd
+			JOIN bookings b ON bd.booking_id = b.booking_id
+			CROSS JOIN LATERAL generate_series(
+				GREATEST(bd.check_in_date, $1::date),
+				LEAST(bd.check_out_date, $2::date) - interval '1 day',
+				interval '1 day'
+			) AS d
+			WHERE b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn')
+			  AND b.created_at >= NOW() - make_interval(days => $3)
+			  AND bd.check_out_date > $1
+			  AND bd.check_in_date < $2
+			GROUP BY 1, 2
+		`, today, end, days)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load pickup: %w", err)
+		}
+		byDate := map[string]map[int]int{}
+		for rows.Next() {
+			var date time.Time
+			var roomTypeID, rooms int
+			if err := rows.Scan(&date, &roomTypeID, &rooms); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan pickup: %w", err)
+			}
+			key := date.Format("2006-01-02")
+			if byDate[key] == nil {
+				byDate[key] = map[int]int{}
+			}
+			byDate[key][roomTypeID] = rooms
+		}
+		rows.Close()
+		snapshot.pickup[days] = byDate
+	}
+
+	return snapshot, nil
+}
+
+// applyChanges writes the new tiers to the pricing calendar and logs every change
+func (j *DynamicPricingJob) applyChanges(ctx context.Context, changes []models.PricingRuleChange) error {
+	tx, err := j.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for i := range changes {
+		change := &changes[i]
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO pricing_calendar (date, rate_tier_id)
+			VALUES ($1, $2)
+			ON CONFLICT (date)
+			DO UPDATE SET
+				rate_tier_id = EXCLUDED.rate_tier_id,
+				updated_at = CURRENT_TIMESTAMP
+		`, change.Date, change.NewRateTierID)
+		if err != nil {
+			return fmt.Errorf("failed to update pricing calendar for %s: %w", change.Date.Format("2006-01-02"), err)
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO pricing_rule_changes (date, rule_id, rule_name, old_rate_tier_id, new_rate_tier_id, occupancy_percent, pickup)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING change_id, created_at
+		`, change.Date, change.RuleID, change.RuleName, change.OldRateTierID, change.NewRateTierID,
+			change.OccupancyPercent, change.Pickup).Scan(&change.ChangeID, &change.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to log pricing change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit pricing changes: %w", err)
+	}
+
+	return nil
+}
+
+// logResult logs the dynamic pricing result in a structured format
+func (j *DynamicPricingJob) logResult(result DynamicPricingResult) {
+	if !result.Success {
+		j.logger.Printf("✗ Dynamic Pricing Failed | Time: %s | Error: %s | Duration: %v",
+			result.Timestamp.Format("2006-01-02 15:04:05"),
+			result.ErrorMessage,
+			result.ExecutionTime)
+		return
+	}
+
+	j.logger.Printf("✓ Dynamic Pricing Success | Time: %s | Rules: %d | Dates: %d | Changes: %d | Duration: %v",
+		result.Timestamp.Format("2006-01-02 15:04:05"),
+		result.RulesEvaluated,
+		result.DatesEvaluated,
+		len(result.Changes),
+		result.ExecutionTime)
+	for _, change := range result.Changes {
+		j.logger.Printf("  %s -> %s (rule: %s, occupancy: %.2f%%, pickup: %d)",
+			change.Date.Format("2006-01-02"), change.NewRateTierName, change.RuleName,
+			change.OccupancyPercent, change.Pickup)
+	}
+}
+
+// GetNextRunTime returns the next scheduled run time
+func (j *DynamicPricingJob) GetNextRunTime() time.Time {
+	entries := j.cron.Entries()
+	if len(entries) > 0 {
+		return entries[0].Next
+	}
+	return time.Time{}
+}
+
+// IsRunning returns whether the scheduler is running
+func (j *DynamicPricingJob) IsRunning() bool {
+	return len(j.cron.Entries()) > 0
+}
+
+// GetStats returns statistics about the dynamic pricing job
+func (j *DynamicPricingJob) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"is_running":    j.IsRunning(),
+		"next_run_time": j.GetNextRunTime(),
+		"schedule":      "Daily at 03:00 AM",
+	}
+}
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+func intPtr(v int) *int           { return &v }
+
+// pricingTestSnapshot has Low(1) < Standard(2) < High(3) < Peak(4) tiers and two room types
+// (1: 10 rooms, 2: 5 rooms) on 2025-07-10 and 2025-07-11
+func pricingTestSnapshot() *pricingSnapshot {
+	return &pricingSnapshot{
+		today: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		tiers: map[int]pricingTier{
+			1: {name: "Low Season", rank: 1},
+			2: {name: "Standard", rank: 2},
+			3: {name: "High Season", rank: 3},
+			4: {name: "Peak Season", rank: 4},
+		},
+		prices: map[int]map[int]float64{
+			2: {1: 1500, 2: 3000},
+			3: {1: 1800, 2: 3600},
+			4: {1: 2200, 2: 4400},
+		},
+		calendar: map[string]int{"2025-07-10": 2},
+		inventory: map[string]map[int]inventoryLevel{
+			"2025-07-10": {1: {allotment: 10, occupied: 9}, 2: {allotment: 5, occupied: 4}},
+			"2025-07-11": {1: {allotment: 10, occupied: 2}, 2: {allotment: 5, occupied: 1}},
+		},
+		pickup: map[int]map[string]map[int]int{
+			7: {"2025-07-11": {1: 6, 2: 5}},
+		},
+	}
+}
+
+func occupancyRule(id int, threshold float64, tierID int) models.PricingRule {
+	return models.PricingRule{
+		RuleID:             id,
+		Name:               "Occupancy",
+		RuleType:           models.PricingRuleOccupancy,
+		OccupancyThreshold: floatPtr(threshold),
+		TargetRateTierID:   tierID,
+		LookaheadDays:      90,
+		IsActive:           true,
+	}
+}
+
+func TestPlanPricingChanges_OccupancyLadder(t *testing.T) {
+	rules := []models.PricingRule{
+		occupancyRule(1, 70, 2),
+		occupancyRule(2, 85, 3),
+		occupancyRule(3, 95, 4),
+	}
+
+	changes := planPricingChanges(rules, pricingTestSnapshot())
+
+	// 2025-07-10 is 86.67% full: moves from Standard to High Season; 2025-07-11 is 20% full
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "2025-07-10", changes[0].Date.Format("2006-01-02"))
+	assert.Equal(t, 2, *changes[0].OldRateTierID)
+	assert.Equal(t, "Standard", *changes[0].OldRateTierName)
+	assert.Equal(t, 3, changes[0].NewRateTierID)
+	assert.Equal(t, 2, *changes[0].RuleID)
+	assert.Equal(t, 86.67, changes[0].OccupancyPercent)
+}
+
+func TestPlanPricingChanges_NeverLowersTier(t *testing.T) {
+	snapshot := pricingTestSnapshot()
+	snapshot.calendar["2025-07-10"] = 4
+
+	changes := planPricingChanges([]models.PricingRule{occupancyRule(2, 85, 3)}, snapshot)
+
+	assert.Empty(t, changes)
+}
+
+func TestPlanPricingChanges_PickupRoomTypeAndCeiling(t *testing.T) {
+	pickup := models.PricingRule{
+		RuleID:           4,
+		Name:             "Pickup 7 days",
+		RuleType:         models.PricingRulePickup,
+		RoomTypeID:       intPtr(2),
+		PickupDays:       intPtr(7),
+		PickupThreshold:  intPtr(5),
+		TargetRateTierID: 3,
+		CeilingPrice:     floatPtr(4000),
+		LookaheadDays:    60,
+		IsActive:         true,
+	}
+
+	changes := planPricingChanges([]models.PricingRule{pickup}, pricingTestSnapshot())
+
+	// Room type 2 picked up 5 rooms for 2025-07-11, which has no calendar entry yet
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "2025-07-11", changes[0].Date.Format("2006-01-02"))
+	assert.Nil(t, changes[0].OldRateTierID)
+	assert.Equal(t, 5, changes[0].Pickup)
+
+	// High Season prices room type 2 at 3600, above a 3500 ceiling
+	pickup.CeilingPrice = floatPtr(3500)
+	assert.Empty(t, planPricingChanges([]models.PricingRule{pickup}, pricingTestSnapshot()))
+}
+
+func TestPlanPricingChanges_LookaheadAndFloor(t *testing.T) {
+	rule := occupancyRule(1, 80, 3)
+	rule.LookaheadDays = 9 // 2025-07-10 is 9 days out
+	assert.Empty(t, planPricingChanges([]models.PricingRule{rule}, pricingTestSnapshot()))
+
+	// Whole-hotel rules compare the cheapest room type (1800 in High Season)
+	rule.LookaheadDays = 90
+	rule.FloorPrice = floatPtr(2000)
+	assert.Empty(t, planPricingChanges([]models.PricingRule{rule}, pricingTestSnapshot()))
+
+	rule.FloorPrice = floatPtr(1800)
+	assert.Len(t, planPricingChanges([]models.PricingRule{rule}, pricingTestSnapshot()), 1)
+}
+
+func TestNewDynamicPricingJob(t *testing.T) {
+	job := NewDynamicPricingJob(nil)
+
+	assert.NotNil(t, job)
+	assert.NotNil(t, job.cron)
+	assert.NotNil(t, job.logger)
+	assert.Equal(t, "Daily at 03:00 AM", job.GetStats()["schedule"])
+}
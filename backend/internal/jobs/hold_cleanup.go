@@ -12,9 +12,10 @@ import (
 
 // HoldCleanupJob handles the periodic cleanup of expired booking holds
 type HoldCleanupJob struct {
-	db     *database.DB
-	cron   *cron.Cron
-	logger *log.Logger
+	db        *database.DB
+	cron      *cron.Cron
+	logger    *log.Logger
+	onRelease func()
 }
 
 // HoldCleanupResult contains the results of a hold cleanup run
@@ -38,6 +39,12 @@ func NewHoldCleanupJob(db *database.DB) *HoldCleanupJob {
 	}
 }
 
+// OnRelease registers a callback invoked after a run releases expired holds
+// (used to wake the waitlist matcher)
+func (j *HoldCleanupJob) OnRelease(fn func()) {
+	j.onRelease = fn
+}
+
 // Start begins the scheduled hold cleanup job
 // Runs every 5 minutes
 func (j *HoldCleanupJob) Start() error {
@@ -113,6 +120,9 @@ func (j *HoldCleanupJob) Run() HoldCleanupResult {
 	if releasedCount > 0 {
 		j.logger.Printf("Hold cleanup completed successfully: %s (Duration: %v)", 
 			message, result.ExecutionTime)
+		if j.onRelease != nil {
+			j.onRelease()
+		}
 	} else {
 		j.logger.Printf("Hold cleanup completed: No expired holds found (Duration: %v)", 
 			result.ExecutionTime)
@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/pkg/database"
 	"github.com/robfig/cron/v3"
 )
@@ -15,15 +16,21 @@ type NightAuditJob struct {
 	db     *database.DB
 	cron   *cron.Cron
 	logger *log.Logger
+
+	onRoomsChanged func(roomIDs []int)
 }
 
 // NightAuditResult contains the results of a night audit run
 type NightAuditResult struct {
-	Timestamp      time.Time
-	RoomsUpdated   int
-	Success        bool
-	ErrorMessage   string
-	ExecutionTime  time.Duration
+	Timestamp        time.Time
+	RoomsUpdated     int // Occupied rooms due for a service
+	StayoverServices int
+	RefreshServices  int
+	RoomsNotDue      int // Occupied rooms whose next service is on a later night
+	DNDCleared       int
+	Success          bool
+	ErrorMessage     string
+	ExecutionTime    time.Duration
 }
 
 // NewNightAuditJob creates a new night audit job instance
@@ -37,6 +44,12 @@ func NewNightAuditJob(db *database.DB) *NightAuditJob {
 	}
 }
 
+// OnRoomsChanged registers a callback invoked with the rooms a run marked for service
+// (used to update the room status board)
+func (j *NightAuditJob) OnRoomsChanged(fn func(roomIDs []int)) {
+	j.onRoomsChanged = fn
+}
+
 // Start begins the scheduled night audit job
 // Runs daily at 02:00 AM
 func (j *NightAuditJob) Start() error {
@@ -83,58 +96,67 @@ func (j *NightAuditJob) Run() NightAuditResult {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Execute the night audit query
-	query := `
+	tx, err := j.db.Pool.Begin(ctx)
+	if err != nil {
+		return j.failed(result, fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Do-not-disturb only lasts for the day it was asked for
+	tag, err := tx.Exec(ctx, `
 		UPDATE rooms
-		SET housekeeping_status = 'Dirty'
-		WHERE occupancy_status = 'Occupied'
-		  AND housekeeping_status != 'Dirty'
-		RETURNING room_id
-	`
+		SET do_not_disturb = FALSE, dnd_set_at = NULL, dnd_set_by = NULL
+		WHERE do_not_disturb = TRUE
+	`)
+	if err != nil {
+		return j.failed(result, fmt.Sprintf("failed to clear do not disturb: %v", err))
+	}
+	result.DNDCleared = int(tag.RowsAffected())
 
-	rows, err := j.db.Pool.Query(ctx, query)
+	// Occupied rooms are serviced on the frequency of their stay
+	stays, err := getOccupiedStays(ctx, tx)
 	if err != nil {
-		result.ErrorMessage = fmt.Sprintf("failed to execute night audit query: %v", err)
-		j.logger.Printf("ERROR: %s", result.ErrorMessage)
-		result.ExecutionTime = time.Since(startTime)
-		return result
+		return j.failed(result, err.Error())
 	}
-	defer rows.Close()
-
-	// Count updated rooms
-	roomsUpdated := 0
-	var roomIDs []int
-	for rows.Next() {
-		var roomID int
-		if err := rows.Scan(&roomID); err != nil {
-			j.logger.Printf("WARNING: Failed to scan room ID: %v", err)
-			continue
-		}
-		roomIDs = append(roomIDs, roomID)
-		roomsUpdated++
+
+	scheduled, notDue, err := scheduleStayoverServices(ctx, tx, stays)
+	if err != nil {
+		return j.failed(result, err.Error())
 	}
 
-	if err := rows.Err(); err != nil {
-		result.ErrorMessage = fmt.Sprintf("error iterating rows: %v", err)
-		j.logger.Printf("ERROR: %s", result.ErrorMessage)
-		result.ExecutionTime = time.Since(startTime)
-		return result
+	if err := tx.Commit(ctx); err != nil {
+		return j.failed(result, fmt.Sprintf("failed to commit night audit: %v", err))
 	}
 
-	result.RoomsUpdated = roomsUpdated
+	result.StayoverServices = len(scheduled[models.TaskTypeStayover])
+	result.RefreshServices = len(scheduled[models.TaskTypeRefresh])
+	result.RoomsUpdated = result.StayoverServices + result.RefreshServices
+	result.RoomsNotDue = notDue
 	result.Success = true
 	result.ExecutionTime = time.Since(startTime)
 
-	j.logger.Printf("Night audit completed successfully: %d rooms updated in %v", 
-		roomsUpdated, result.ExecutionTime)
-	
-	if roomsUpdated > 0 {
-		j.logger.Printf("Updated room IDs: %v", roomIDs)
+	j.logger.Printf("Night audit completed successfully: %d rooms updated in %v",
+		result.RoomsUpdated, result.ExecutionTime)
+
+	if result.RoomsUpdated > 0 {
+		j.logger.Printf("Stayover room IDs: %v | Refresh room IDs: %v",
+			scheduled[models.TaskTypeStayover], scheduled[models.TaskTypeRefresh])
+		if j.onRoomsChanged != nil {
+			j.onRoomsChanged(append(scheduled[models.TaskTypeStayover], scheduled[models.TaskTypeRefresh]...))
+		}
 	}
 
 	return result
 }
 
+// failed records why a night audit run failed
+func (j *NightAuditJob) failed(result NightAuditResult, message string) NightAuditResult {
+	result.ErrorMessage = message
+	result.ExecutionTime = time.Since(result.Timestamp)
+	j.logger.Printf("ERROR: %s", message)
+	return result
+}
+
 // RunManual executes the night audit manually and returns the result
 // Useful for testing and manual triggers
 func (j *NightAuditJob) RunManual() (NightAuditResult, error) {
@@ -151,9 +173,13 @@ func (j *NightAuditJob) RunManual() (NightAuditResult, error) {
 // logResult logs the night audit result in a structured format
 func (j *NightAuditJob) logResult(result NightAuditResult) {
 	if result.Success {
-		j.logger.Printf("✓ Night Audit Success | Time: %s | Rooms Updated: %d | Duration: %v",
+		j.logger.Printf("✓ Night Audit Success | Time: %s | Rooms Updated: %d (stayover: %d, refresh: %d, not due: %d) | DND Cleared: %d | Duration: %v",
 			result.Timestamp.Format("2006-01-02 15:04:05"),
 			result.RoomsUpdated,
+			result.StayoverServices,
+			result.RefreshServices,
+			result.RoomsNotDue,
+			result.DNDCleared,
 			result.ExecutionTime)
 	} else {
 		j.logger.Printf("✗ Night Audit Failed | Time: %s | Error: %s | Duration: %v",
@@ -0,0 +1,146 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/robfig/cron/v3"
+)
+
+// OutOfOrderJob starts and ends scheduled out-of-order periods: rooms go out of
+// service on the first night of a period and come back the day after the last night
+type OutOfOrderJob struct {
+	db     *database.DB
+	cron   *cron.Cron
+	logger *log.Logger
+}
+
+// OutOfOrderResult contains the results of an out-of-order schedule run
+type OutOfOrderResult struct {
+	Timestamp     time.Time
+	RoomsTakenOut int
+	RoomsReturned int
+	Success       bool
+	ErrorMessage  string
+	ExecutionTime time.Duration
+}
+
+// NewOutOfOrderJob creates a new out-of-order schedule job instance
+func NewOutOfOrderJob(db *database.DB) *OutOfOrderJob {
+	logger := log.New(log.Writer(), "[OUT-OF-ORDER] ", log.LstdFlags|log.Lshortfile)
+
+	return &OutOfOrderJob{
+		db:     db,
+		cron:   cron.New(),
+		logger: logger,
+	}
+}
+
+// Start begins the scheduled out-of-order job
+// Runs every hour at 5 minutes past
+func (j *OutOfOrderJob) Start() error {
+	j.logger.Println("Initializing out-of-order scheduler...")
+
+	_, err := j.cron.AddFunc("5 * * * *", func() {
+		j.logger.Println("Starting scheduled out-of-order run...")
+		result := j.Run()
+		j.logResult(result)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to schedule out-of-order job: %w", err)
+	}
+
+	j.cron.Start()
+	j.logger.Println("Out-of-order scheduler started successfully (runs hourly)")
+
+	return nil
+}
+
+// Stop gracefully stops the out-of-order scheduler
+func (j *OutOfOrderJob) Stop() {
+	j.logger.Println("Stopping out-of-order scheduler...")
+	ctx := j.cron.Stop()
+	<-ctx.Done()
+	j.logger.Println("Out-of-order scheduler stopped")
+}
+
+// Run applies the out-of-order schedule immediately
+func (j *OutOfOrderJob) Run() OutOfOrderResult {
+	startTime := time.Now()
+	result := OutOfOrderResult{
+		Timestamp: startTime,
+		Success:   false,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := j.db.Pool.QueryRow(ctx, `SELECT * FROM apply_out_of_order_schedule()`).
+		Scan(&result.RoomsTakenOut, &result.RoomsReturned)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("failed to apply out-of-order schedule: %v", err)
+		j.logger.Printf("ERROR: %s", result.ErrorMessage)
+		result.ExecutionTime = time.Since(startTime)
+		return result
+	}
+
+	result.Success = true
+	result.ExecutionTime = time.Since(startTime)
+	return result
+}
+
+// RunManual applies the out-of-order schedule manually and returns the result
+func (j *OutOfOrderJob) RunManual() (OutOfOrderResult, error) {
+	j.logger.Println("Manual out-of-order run triggered")
+	result := j.Run()
+	j.logResult(result)
+
+	if !result.Success {
+		return result, fmt.Errorf("out-of-order run failed: %s", result.ErrorMessage)
+	}
+
+	return result, nil
+}
+
+// logResult logs the out-of-order result in a structured format
+func (j *OutOfOrderJob) logResult(result OutOfOrderResult) {
+	if result.Success {
+		j.logger.Printf("✓ Out-of-Order Success | Time: %s | Taken Out: %d | Returned: %d | Duration: %v",
+			result.Timestamp.Format("2006-01-02 15:04:05"),
+			result.RoomsTakenOut,
+			result.RoomsReturned,
+			result.ExecutionTime)
+	} else {
+		j.logger.Printf("✗ Out-of-Order Failed | Time: %s | Error: %s | Duration: %v",
+			result.Timestamp.Format("2006-01-02 15:04:05"),
+			result.ErrorMessage,
+			result.ExecutionTime)
+	}
+}
+
+// GetNextRunTime returns the next scheduled run time
+func (j *OutOfOrderJob) GetNextRunTime() time.Time {
+	entries := j.cron.Entries()
+	if len(entries) > 0 {
+		return entries[0].Next
+	}
+	return time.Time{}
+}
+
+// IsRunning returns whether the scheduler is running
+func (j *OutOfOrderJob) IsRunning() bool {
+	return len(j.cron.Entries()) > 0
+}
+
+// GetStats returns statistics about the out-of-order job
+func (j *OutOfOrderJob) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"is_running":    j.IsRunning(),
+		"next_run_time": j.GetNextRunTime(),
+		"schedule":      "Hourly at 5 minutes past",
+	}
+}
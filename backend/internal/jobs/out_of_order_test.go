@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOutOfOrderJob(t *testing.T) {
+	job := NewOutOfOrderJob(nil)
+
+	assert.NotNil(t, job)
+	assert.NotNil(t, job.cron)
+	assert.NotNil(t, job.logger)
+}
+
+func TestOutOfOrderJob_StartStop(t *testing.T) {
+	job := NewOutOfOrderJob(nil)
+
+	require.NoError(t, job.Start())
+	assert.True(t, job.IsRunning())
+	assert.True(t, job.GetNextRunTime().After(time.Now()))
+
+	job.Stop()
+}
+
+func TestOutOfOrderJob_GetStats(t *testing.T) {
+	job := NewOutOfOrderJob(nil)
+
+	stats := job.GetStats()
+
+	assert.Equal(t, false, stats["is_running"])
+	assert.Equal(t, "Hourly at 5 minutes past", stats["schedule"])
+}
@@ -0,0 +1,436 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Room scores for an arrival. A room free for the whole stay always beats one the
+// guest would have to move out of, and of those the one free for longest wins; then
+// preferences count before how ready the room is.
+const (
+	wholeStayScore   = 100.0
+	partialStayScore = 20.0
+	connectingScore  = 8.0
+	viewScore        = 4.0
+	floorScore       = 2.0
+)
+
+// stayRange is a run of nights, from the night of from up to the night before to
+type stayRange struct {
+	from time.Time
+	to   time.Time
+}
+
+func (r stayRange) overlaps(from, to time.Time) bool {
+	return r.from.Before(to) && from.Before(r.to)
+}
+
+// arrivalStay is a stay arriving on the pre-assignment date with the guest's preferences
+type arrivalStay struct {
+	bookingDetailID int
+	bookingID       int
+	roomTypeID      int
+	checkIn         time.Time
+	checkOut        time.Time
+	vip             bool
+	floorPreference string
+	viewPreference  string
+	accessibleRoom  bool
+	connectingRoom  bool
+	roomID          int // Room picked by staff, kept as is
+}
+
+func (a arrivalStay) nights() int {
+	return int(a.checkOut.Sub(a.checkIn).Hours() / 24)
+}
+
+// candidateRoom is a room that may be pre-assigned, with the nights it is not free
+type candidateRoom struct {
+	roomID             int
+	roomTypeID         int
+	roomNumber         string
+	floor              int
+	occupancyStatus    string
+	housekeepingStatus string
+	viewType           string
+	accessible         bool
+	features           []string
+	connectingRoomID   int
+	busy               []stayRange
+}
+
+// freeNights returns how many nights from the night of from, up to to, the room is
+// free for in a row
+func (r *candidateRoom) freeNights(from, to time.Time) int {
+	end := to
+	for _, busy := range r.busy {
+		if !busy.overlaps(from, to) {
+			continue
+		}
+		if !busy.from.After(from) {
+			return 0
+		}
+		if busy.from.Before(end) {
+			end = busy.from
+		}
+	}
+	return int(end.Sub(from).Hours() / 24)
+}
+
+// hasView reports whether the room's type or one of its features gives the view, so
+// 'Pool' matches a Pool view room type or a 'Pool View' room
+func (r *candidateRoom) hasView(view string) bool {
+	if strings.EqualFold(r.viewType, view) {
+		return true
+	}
+	for _, feature := range r.features {
+		if strings.EqualFold(feature, view) || strings.EqualFold(feature, view+" View") {
+			return true
+		}
+	}
+	return false
+}
+
+// readinessScore ranks rooms by how ready they will be for the arrival: inspected,
+// clean, waiting to be cleaned, still occupied by a departing guest, then waiting for
+// maintenance
+func readinessScore(room *candidateRoom) float64 {
+	if room.occupancyStatus == "Occupied" {
+		return 0.5
+	}
+	switch room.housekeepingStatus {
+	case "Inspected":
+		return 3
+	case "Clean":
+		return 2
+	case "MaintenanceRequired":
+		return 0
+	default:
+		return 1
+	}
+}
+
+// floorMatches reports whether a floor is in the half of the hotel the guest asked
+// for. In a hotel on a single floor every room matches.
+func floorMatches(preference string, floor, lowest, highest int) bool {
+	if lowest == highest {
+		return true
+	}
+	middle := float64(lowest+highest) / 2
+	if preference == models.FloorPreferenceHigh {
+		return float64(floor) > middle
+	}
+	return float64(floor) < middle
+}
+
+// preAssignmentOrder orders arrivals by who picks first: VIPs, guests needing an
+// accessible room, then longer stays, which are the hardest to fit without a move.
+// The other connecting rooms of a booking follow its first one.
+func preAssignmentOrder(arrivals []arrivalStay) []arrivalStay {
+	sorted := make([]arrivalStay, len(arrivals))
+	copy(sorted, arrivals)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.vip != b.vip {
+			return a.vip
+		}
+		if a.accessibleRoom != b.accessibleRoom {
+			return a.accessibleRoom
+		}
+		if a.nights() != b.nights() {
+			return a.nights() > b.nights()
+		}
+		if a.bookingID != b.bookingID {
+			return a.bookingID < b.bookingID
+		}
+		return a.bookingDetailID < b.bookingDetailID
+	})
+
+	ordered := make([]arrivalStay, 0, len(sorted))
+	placed := make(map[int]bool, len(sorted))
+	for i, a := range sorted {
+		if placed[a.bookingDetailID] {
+			continue
+		}
+		ordered = append(ordered, a)
+		placed[a.bookingDetailID] = true
+		if !a.connectingRoom {
+			continue
+		}
+		for _, b := range sorted[i+1:] {
+			if b.bookingID == a.bookingID && b.connectingRoom && !placed[b.bookingDetailID] {
+				ordered = append(ordered, b)
+				placed[b.bookingDetailID] = true
+			}
+		}
+	}
+	return ordered
+}
+
+// preAssignment is the room picked for an arrival
+type preAssignment struct {
+	bookingDetailID int
+	roomID          int
+	unmet           []string
+
+	bookingID  int
+	connecting bool // Asked for a room connecting to another room of the booking
+}
+
+// preAssignRooms picks a room of the booked type for each arrival without one, in
+// preAssignmentOrder. Accessible rooms are required when asked for; other preferences
+// and readiness are scored, and a room the guest would have to move out of is only
+// picked when no room is free for the whole stay. Rooms picked by staff are kept.
+// It returns the new pre-assignments and the booking details left without a room.
+func preAssignRooms(arrivals []arrivalStay, rooms []candidateRoom) ([]preAssignment, []int) {
+	byID := make(map[int]*candidateRoom, len(rooms))
+	lowest, highest := 0, 0
+	for i := range rooms {
+		room := &rooms[i]
+		byID[room.roomID] = room
+		if i == 0 || room.floor < lowest {
+			lowest = room.floor
+		}
+		if i == 0 || room.floor > highest {
+			highest = room.floor
+		}
+	}
+
+	taken := make(map[int]bool)
+	connecting := make(map[int]int)     // Arrivals asking for connecting rooms per booking
+	bookingRooms := make(map[int][]int) // Rooms of those arrivals per booking
+	for _, a := range arrivals {
+		if a.connectingRoom {
+			connecting[a.bookingID]++
+		}
+		if a.roomID != 0 {
+			taken[a.roomID] = true
+			if a.connectingRoom {
+				bookingRooms[a.bookingID] = append(bookingRooms[a.bookingID], a.roomID)
+			}
+		}
+	}
+	wantsConnecting := func(a arrivalStay) bool {
+		return a.connectingRoom && connecting[a.bookingID] > 1
+	}
+
+	var assigned []preAssignment
+	var unassigned []int
+	for _, a := range preAssignmentOrder(arrivals) {
+		if a.roomID != 0 {
+			continue
+		}
+
+		var best *candidateRoom
+		var bestScore float64
+		var bestUnmet []string
+		for i := range rooms {
+			room := &rooms[i]
+			if room.roomTypeID != a.roomTypeID || taken[room.roomID] {
+				continue
+			}
+			if a.accessibleRoom && !room.accessible {
+				continue
+			}
+			free := room.freeNights(a.checkIn, a.checkOut)
+			if free == 0 {
+				continue
+			}
+
+			score := readinessScore(room)
+			var unmet []string
+			if free >= a.nights() {
+				score += wholeStayScore
+			} else {
+				score += partialStayScore * float64(free) / float64(a.nights())
+				unmet = append(unmet, models.UnmetRoomMove)
+			}
+			if a.floorPreference != "" {
+				if floorMatches(a.floorPreference, room.floor, lowest, highest) {
+					score += floorScore
+				} else {
+					unmet = append(unmet, models.UnmetFloor)
+				}
+			}
+			if a.viewPreference != "" {
+				if room.hasView(a.viewPreference) {
+					score += viewScore
+				} else {
+					unmet = append(unmet, models.UnmetView)
+				}
+			}
+			if wantsConnecting(a) {
+				if others := bookingRooms[a.bookingID]; len(others) > 0 {
+					if containsRoom(others, room.connectingRoomID) {
+						score += connectingScore
+					}
+				} else if partner := byID[room.connectingRoomID]; partner != nil && !taken[partner.roomID] &&
+					partner.freeNights(a.checkIn, a.checkOut) > 0 {
+					// The first room of the booking leaves its partner for the next
+					score += connectingScore
+				}
+			}
+
+			if best == nil || score > bestScore {
+				best, bestScore, bestUnmet = room, score, unmet
+			}
+		}
+
+		if best == nil {
+			unassigned = append(unassigned, a.bookingDetailID)
+			continue
+		}
+		taken[best.roomID] = true
+		if a.connectingRoom {
+			bookingRooms[a.bookingID] = append(bookingRooms[a.bookingID], best.roomID)
+		}
+		assigned = append(assigned, preAssignment{
+			bookingDetailID: a.bookingDetailID,
+			roomID:          best.roomID,
+			unmet:           bestUnmet,
+			bookingID:       a.bookingID,
+			connecting:      wantsConnecting(a),
+		})
+	}
+
+	// A room connects when its partner went to another room of the same booking
+	for i := range assigned {
+		p := &assigned[i]
+		if p.connecting && !containsRoom(bookingRooms[p.bookingID], byID[p.roomID].connectingRoomID) {
+			p.unmet = append(p.unmet, models.UnmetConnecting)
+		}
+	}
+
+	return assigned, unassigned
+}
+
+func containsRoom(roomIDs []int, roomID int) bool {
+	if roomID == 0 {
+		return false
+	}
+	for _, id := range roomIDs {
+		if id == roomID {
+			return true
+		}
+	}
+	return false
+}
+
+// getArrivalStays reads the confirmed stays arriving on date that are not checked in,
+// with the rooms staff picked for them
+func getArrivalStays(ctx context.Context, tx pgx.Tx, date time.Time) ([]arrivalStay, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT bd.booking_detail_id, bd.booking_id, bd.room_type_id, bd.check_in_date, bd.check_out_date,
+		       COALESCE(g.is_vip, FALSE), COALESCE(bd.floor_preference, ''), COALESCE(bd.view_preference, ''),
+		       bd.accessible_room, bd.connecting_room, COALESCE(pa.room_id, 0)
+		FROM booking_details bd
+		JOIN bookings b ON b.booking_id = bd.booking_id
+		LEFT JOIN guests g ON g.guest_id = b.guest_id
+		LEFT JOIN room_pre_assignments pa ON pa.booking_detail_id = bd.booking_detail_id
+		WHERE bd.check_in_date = $1
+		  AND bd.stay_type = 'Overnight'
+		  AND b.status = 'Confirmed'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM room_assignments ra
+		      WHERE ra.booking_detail_id = bd.booking_detail_id AND ra.status = 'Active'
+		  )
+		ORDER BY bd.booking_detail_id
+	`, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query arrivals: %w", err)
+	}
+	defer rows.Close()
+
+	var arrivals []arrivalStay
+	for rows.Next() {
+		var a arrivalStay
+		if err := rows.Scan(&a.bookingDetailID, &a.bookingID, &a.roomTypeID, &a.checkIn, &a.checkOut,
+			&a.vip, &a.floorPreference, &a.viewPreference, &a.accessibleRoom, &a.connectingRoom, &a.roomID); err != nil {
+			return nil, fmt.Errorf("failed to scan arrival: %w", err)
+		}
+		arrivals = append(arrivals, a)
+	}
+
+	return arrivals, rows.Err()
+}
+
+// getCandidateRooms reads the active rooms that may be pre-assigned for date with the
+// nights each is out of order, taken by a guest staying on or held for another stay.
+// Rooms out of service are left out unless their out-of-order period ends before date.
+func getCandidateRooms(ctx context.Context, tx pgx.Tx, date time.Time) ([]candidateRoom, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT r.room_id, r.room_type_id, r.room_number, r.floor, r.occupancy_status, r.housekeeping_status,
+		       COALESCE(rt.view_type, ''), rt.is_accessible OR 'Accessible' = ANY(r.features),
+		       r.features, COALESCE(r.connecting_room_id, 0)
+		FROM rooms r
+		JOIN room_types rt ON rt.room_type_id = r.room_type_id
+		WHERE r.is_active = TRUE
+		  AND (r.housekeeping_status <> 'OutOfService' OR EXISTS (
+		      SELECT 1 FROM room_out_of_order o
+		      WHERE o.room_id = r.room_id AND o.status = 'Active' AND o.end_date < $1
+		  ))
+		ORDER BY r.room_number
+	`, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rooms: %w", err)
+	}
+
+	var rooms []candidateRoom
+	index := make(map[int]int)
+	for rows.Next() {
+		var room candidateRoom
+		if err := rows.Scan(&room.roomID, &room.roomTypeID, &room.roomNumber, &room.floor,
+			&room.occupancyStatus, &room.housekeepingStatus, &room.viewType, &room.accessible,
+			&room.features, &room.connectingRoomID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		index[room.roomID] = len(rooms)
+		rooms = append(rooms, room)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rooms: %w", err)
+	}
+
+	// Pre-assignments of stays arriving on date are the arrivals themselves
+	rows, err = tx.Query(ctx, `
+		SELECT room_id, start_date, end_date + 1
+		FROM room_out_of_order
+		WHERE status IN ('Scheduled', 'Active') AND end_date >= $1
+		UNION ALL
+		SELECT ra.room_id, bd.check_in_date, bd.check_out_date
+		FROM room_assignments ra
+		JOIN booking_details bd ON bd.booking_detail_id = ra.booking_detail_id
+		WHERE ra.status = 'Active' AND bd.check_out_date > $1
+		UNION ALL
+		SELECT pa.room_id, bd.check_in_date, bd.check_out_date
+		FROM room_pre_assignments pa
+		JOIN booking_details bd ON bd.booking_detail_id = pa.booking_detail_id
+		WHERE bd.check_in_date <> $1 AND bd.check_out_date > $1
+	`, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query room schedules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var roomID int
+		var busy stayRange
+		if err := rows.Scan(&roomID, &busy.from, &busy.to); err != nil {
+			return nil, fmt.Errorf("failed to scan room schedule: %w", err)
+		}
+		if i, ok := index[roomID]; ok {
+			rooms[i].busy = append(rooms[i].busy, busy)
+		}
+	}
+
+	return rooms, rows.Err()
+}
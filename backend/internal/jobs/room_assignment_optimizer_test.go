@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var arrivalDate = time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func arrival(detailID, nights int) arrivalStay {
+	return arrivalStay{
+		bookingDetailID: detailID,
+		bookingID:       detailID,
+		roomTypeID:      1,
+		checkIn:         arrivalDate,
+		checkOut:        arrivalDate.AddDate(0, 0, nights),
+	}
+}
+
+func room(roomID, floor int) candidateRoom {
+	return candidateRoom{
+		roomID:             roomID,
+		roomTypeID:         1,
+		floor:              floor,
+		occupancyStatus:    "Vacant",
+		housekeepingStatus: "Dirty",
+	}
+}
+
+func assignedRooms(assigned []preAssignment) map[int]int {
+	rooms := make(map[int]int, len(assigned))
+	for _, p := range assigned {
+		rooms[p.bookingDetailID] = p.roomID
+	}
+	return rooms
+}
+
+func TestPreAssignmentOrder(t *testing.T) {
+	short := arrival(1, 1)
+	long := arrival(2, 5)
+	vip := arrival(3, 1)
+	vip.vip = true
+	accessible := arrival(4, 1)
+	accessible.accessibleRoom = true
+	first := arrival(5, 2)
+	first.bookingID, first.connectingRoom = 9, true
+	second := arrival(6, 1)
+	second.bookingID, second.connectingRoom = 9, true
+
+	var order []int
+	for _, a := range preAssignmentOrder([]arrivalStay{short, second, long, accessible, first, vip}) {
+		order = append(order, a.bookingDetailID)
+	}
+
+	assert.Equal(t, []int{3, 4, 2, 5, 6, 1}, order)
+}
+
+func TestPreAssignRooms_PrefersInspectedRooms(t *testing.T) {
+	dirty := room(1, 1)
+	clean := room(2, 1)
+	clean.housekeepingStatus = "Clean"
+	inspected := room(3, 1)
+	inspected.housekeepingStatus = "Inspected"
+
+	assigned, unassigned := preAssignRooms([]arrivalStay{arrival(1, 1)}, []candidateRoom{dirty, clean, inspected})
+
+	require.Len(t, assigned, 1)
+	assert.Empty(t, unassigned)
+	assert.Equal(t, 3, assigned[0].roomID)
+	assert.Empty(t, assigned[0].unmet)
+}
+
+func TestPreAssignRooms_VIPPicksFirst(t *testing.T) {
+	guest := arrival(1, 1)
+	vip := arrival(2, 1)
+	vip.vip = true
+	inspected := room(1, 1)
+	inspected.housekeepingStatus = "Inspected"
+
+	assigned, _ := preAssignRooms([]arrivalStay{guest, vip}, []candidateRoom{room(2, 1), inspected})
+
+	assert.Equal(t, map[int]int{2: 1, 1: 2}, assignedRooms(assigned))
+}
+
+func TestPreAssignRooms_RequiresAccessibleRoom(t *testing.T) {
+	guest := arrival(1, 1)
+	guest.accessibleRoom = true
+	accessible := room(2, 1)
+	accessible.accessible = true
+
+	assigned, unassigned := preAssignRooms([]arrivalStay{guest}, []candidateRoom{room(1, 1), accessible})
+	require.Len(t, assigned, 1)
+	assert.Empty(t, unassigned)
+	assert.Equal(t, 2, assigned[0].roomID)
+
+	assigned, unassigned = preAssignRooms([]arrivalStay{guest}, []candidateRoom{room(1, 1)})
+	assert.Empty(t, assigned)
+	assert.Equal(t, []int{1}, unassigned)
+}
+
+func TestPreAssignRooms_AvoidsRoomMoves(t *testing.T) {
+	inspected := room(1, 1)
+	inspected.housekeepingStatus = "Inspected"
+	inspected.busy = []stayRange{{from: arrivalDate.AddDate(0, 0, 2), to: arrivalDate.AddDate(0, 0, 4)}}
+	wholeStay := room(2, 1)
+
+	assigned, _ := preAssignRooms([]arrivalStay{arrival(1, 3)}, []candidateRoom{inspected, wholeStay})
+	require.Len(t, assigned, 1)
+	assert.Equal(t, 2, assigned[0].roomID)
+	assert.Empty(t, assigned[0].unmet)
+
+	assigned, _ = preAssignRooms([]arrivalStay{arrival(1, 3)}, []candidateRoom{inspected})
+	require.Len(t, assigned, 1)
+	assert.Equal(t, 1, assigned[0].roomID)
+	assert.Equal(t, []string{models.UnmetRoomMove}, assigned[0].unmet)
+}
+
+func TestPreAssignRooms_SkipsRoomsBusyOnArrival(t *testing.T) {
+	busy := room(1, 1)
+	busy.busy = []stayRange{{from: arrivalDate.AddDate(0, 0, -1), to: arrivalDate.AddDate(0, 0, 1)}}
+	otherType := room(2, 1)
+	otherType.roomTypeID = 2
+
+	assigned, unassigned := preAssignRooms([]arrivalStay{arrival(1, 2)}, []candidateRoom{busy, otherType})
+
+	assert.Empty(t, assigned)
+	assert.Equal(t, []int{1}, unassigned)
+}
+
+func TestPreAssignRooms_FloorAndView(t *testing.T) {
+	guest := arrival(1, 1)
+	guest.floorPreference = models.FloorPreferenceHigh
+	guest.viewPreference = "Pool"
+	low := room(1, 1)
+	high := room(2, 5)
+	poolLow := room(3, 1)
+	poolLow.features = []string{"Pool View"}
+
+	assigned, _ := preAssignRooms([]arrivalStay{guest}, []candidateRoom{low, high, poolLow})
+	require.Len(t, assigned, 1)
+	assert.Equal(t, 3, assigned[0].roomID, "the view outweighs the floor")
+	assert.Equal(t, []string{models.UnmetFloor}, assigned[0].unmet)
+
+	high.roomTypeID = 2
+	assigned, _ = preAssignRooms([]arrivalStay{guest}, []candidateRoom{low, high})
+	require.Len(t, assigned, 1)
+	assert.Equal(t, []string{models.UnmetFloor, models.UnmetView}, assigned[0].unmet)
+}
+
+func TestPreAssignRooms_ConnectingRooms(t *testing.T) {
+	first := arrival(1, 2)
+	first.bookingID, first.connectingRoom = 7, true
+	second := arrival(2, 2)
+	second.bookingID, second.connectingRoom = 7, true
+
+	single := room(1, 1)
+	single.housekeepingStatus = "Inspected"
+	left := room(2, 1)
+	left.connectingRoomID = 3
+	right := room(3, 1)
+	right.connectingRoomID = 2
+
+	assigned, _ := preAssignRooms([]arrivalStay{first, second}, []candidateRoom{single, left, right})
+	assert.Equal(t, map[int]int{1: 2, 2: 3}, assignedRooms(assigned))
+	for _, p := range assigned {
+		assert.Empty(t, p.unmet)
+	}
+
+	assigned, _ = preAssignRooms([]arrivalStay{first, second}, []candidateRoom{single, room(4, 1)})
+	require.Len(t, assigned, 2)
+	for _, p := range assigned {
+		assert.Equal(t, []string{models.UnmetConnecting}, p.unmet)
+	}
+}
+
+func TestPreAssignRooms_KeepsManualRooms(t *testing.T) {
+	manual := arrival(1, 1)
+	manual.roomID = 1
+	manual.vip = true
+	guest := arrival(2, 1)
+	inspected := room(1, 1)
+	inspected.housekeepingStatus = "Inspected"
+
+	assigned, unassigned := preAssignRooms([]arrivalStay{manual, guest}, []candidateRoom{inspected, room(2, 1)})
+
+	assert.Empty(t, unassigned)
+	assert.Equal(t, map[int]int{2: 2}, assignedRooms(assigned))
+}
@@ -0,0 +1,218 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/robfig/cron/v3"
+)
+
+// RoomPreAssignmentJob picks rooms for the next day's arrivals so the front desk can
+// check guests in without choosing one. Rooms picked by staff are kept; the others
+// are picked again on every run.
+type RoomPreAssignmentJob struct {
+	db     *database.DB
+	cron   *cron.Cron
+	logger *log.Logger
+	mu     sync.Mutex
+}
+
+// RoomPreAssignmentResult contains the results of a room pre-assignment run
+type RoomPreAssignmentResult struct {
+	Timestamp        time.Time
+	ArrivalDate      time.Time
+	RoomsAssigned    int
+	ManualKept       int // Arrivals keeping the room staff picked
+	Unassigned       int // Arrivals left for the front desk without a room
+	PreferencesUnmet int // Rooms assigned without meeting every preference
+	Success          bool
+	ErrorMessage     string
+	ExecutionTime    time.Duration
+}
+
+// NewRoomPreAssignmentJob creates a new room pre-assignment job instance
+func NewRoomPreAssignmentJob(db *database.DB) *RoomPreAssignmentJob {
+	logger := log.New(log.Writer(), "[PRE-ASSIGNMENT] ", log.LstdFlags|log.Lshortfile)
+
+	return &RoomPreAssignmentJob{
+		db:     db,
+		cron:   cron.New(),
+		logger: logger,
+	}
+}
+
+// Start begins the scheduled room pre-assignment job
+// Runs daily at 21:00 for the next day's arrivals
+func (j *RoomPreAssignmentJob) Start() error {
+	j.logger.Println("Initializing room pre-assignment scheduler...")
+
+	_, err := j.cron.AddFunc("0 21 * * *", func() {
+		j.logger.Println("Starting scheduled room pre-assignment...")
+		result := j.Run()
+		j.logResult(result)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to schedule room pre-assignment job: %w", err)
+	}
+
+	j.cron.Start()
+	j.logger.Println("Room pre-assignment scheduler started successfully (runs daily at 21:00)")
+
+	return nil
+}
+
+// Stop gracefully stops the room pre-assignment scheduler
+func (j *RoomPreAssignmentJob) Stop() {
+	j.logger.Println("Stopping room pre-assignment scheduler...")
+	ctx := j.cron.Stop()
+	<-ctx.Done()
+	j.logger.Println("Room pre-assignment scheduler stopped")
+}
+
+// Run pre-assigns rooms to tomorrow's arrivals immediately
+func (j *RoomPreAssignmentJob) Run() RoomPreAssignmentResult {
+	now := time.Now()
+	return j.RunForDate(time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC))
+}
+
+// RunForDate pre-assigns rooms to the arrivals of date
+func (j *RoomPreAssignmentJob) RunForDate(date time.Time) RoomPreAssignmentResult {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	result := RoomPreAssignmentResult{
+		Timestamp:   time.Now(),
+		ArrivalDate: date,
+		Success:     false,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := j.db.Pool.Begin(ctx)
+	if err != nil {
+		return j.failed(result, fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Rooms picked by the job are picked again with what is known now
+	_, err = tx.Exec(ctx, `
+		DELETE FROM room_pre_assignments pa
+		USING booking_details bd
+		WHERE pa.booking_detail_id = bd.booking_detail_id
+		  AND bd.check_in_date = $1
+		  AND pa.is_manual = FALSE
+	`, date)
+	if err != nil {
+		return j.failed(result, fmt.Sprintf("failed to clear pre-assignments: %v", err))
+	}
+
+	arrivals, err := getArrivalStays(ctx, tx, date)
+	if err != nil {
+		return j.failed(result, err.Error())
+	}
+	rooms, err := getCandidateRooms(ctx, tx, date)
+	if err != nil {
+		return j.failed(result, err.Error())
+	}
+
+	assigned, unassigned := preAssignRooms(arrivals, rooms)
+	for _, p := range assigned {
+		unmet := p.unmet
+		if unmet == nil {
+			unmet = []string{}
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO room_pre_assignments (booking_detail_id, room_id, unmet_preferences)
+			VALUES ($1, $2, $3)
+		`, p.bookingDetailID, p.roomID, unmet)
+		if err != nil {
+			return j.failed(result, fmt.Sprintf("failed to pre-assign room %d: %v", p.roomID, err))
+		}
+		if len(p.unmet) > 0 {
+			result.PreferencesUnmet++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return j.failed(result, fmt.Sprintf("failed to commit pre-assignments: %v", err))
+	}
+
+	for _, a := range arrivals {
+		if a.roomID != 0 {
+			result.ManualKept++
+		}
+	}
+	result.RoomsAssigned = len(assigned)
+	result.Unassigned = len(unassigned)
+	result.Success = true
+	result.ExecutionTime = time.Since(result.Timestamp)
+	return result
+}
+
+// failed records why a run failed and logs it
+func (j *RoomPreAssignmentJob) failed(result RoomPreAssignmentResult, message string) RoomPreAssignmentResult {
+	result.ErrorMessage = message
+	result.ExecutionTime = time.Since(result.Timestamp)
+	j.logger.Printf("ERROR: %s", message)
+	return result
+}
+
+// RunManual pre-assigns rooms to the arrivals of date manually and returns the result
+func (j *RoomPreAssignmentJob) RunManual(date time.Time) (RoomPreAssignmentResult, error) {
+	j.logger.Printf("Manual room pre-assignment triggered for %s", date.Format("2006-01-02"))
+	result := j.RunForDate(date)
+	j.logResult(result)
+
+	if !result.Success {
+		return result, fmt.Errorf("room pre-assignment failed: %s", result.ErrorMessage)
+	}
+
+	return result, nil
+}
+
+// logResult logs the room pre-assignment result in a structured format
+func (j *RoomPreAssignmentJob) logResult(result RoomPreAssignmentResult) {
+	if result.Success {
+		j.logger.Printf("✓ Pre-Assignment Success | Arrivals: %s | Assigned: %d (preferences unmet: %d) | Kept: %d | Unassigned: %d | Duration: %v",
+			result.ArrivalDate.Format("2006-01-02"),
+			result.RoomsAssigned,
+			result.PreferencesUnmet,
+			result.ManualKept,
+			result.Unassigned,
+			result.ExecutionTime)
+	} else {
+		j.logger.Printf("✗ Pre-Assignment Failed | Arrivals: %s | Error: %s | Duration: %v",
+			result.ArrivalDate.Format("2006-01-02"),
+			result.ErrorMessage,
+			result.ExecutionTime)
+	}
+}
+
+// GetNextRunTime returns the next scheduled run time
+func (j *RoomPreAssignmentJob) GetNextRunTime() time.Time {
+	entries := j.cron.Entries()
+	if len(entries) > 0 {
+		return entries[0].Next
+	}
+	return time.Time{}
+}
+
+// IsRunning returns whether the scheduler is running
+func (j *RoomPreAssignmentJob) IsRunning() bool {
+	return len(j.cron.Entries()) > 0
+}
+
+// GetStats returns statistics about the room pre-assignment job
+func (j *RoomPreAssignmentJob) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"is_running":    j.IsRunning(),
+		"next_run_time": j.GetNextRunTime(),
+		"schedule":      "Daily at 21:00 for the next day's arrivals",
+	}
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRoomPreAssignmentJob(t *testing.T) {
+	job := NewRoomPreAssignmentJob(nil)
+
+	assert.NotNil(t, job)
+	assert.NotNil(t, job.cron)
+	assert.NotNil(t, job.logger)
+}
+
+func TestRoomPreAssignmentJob_StartStop(t *testing.T) {
+	job := NewRoomPreAssignmentJob(nil)
+
+	require.NoError(t, job.Start())
+	assert.True(t, job.IsRunning())
+	assert.True(t, job.GetNextRunTime().After(time.Now()))
+
+	job.Stop()
+}
+
+func TestRoomPreAssignmentJob_GetStats(t *testing.T) {
+	job := NewRoomPreAssignmentJob(nil)
+
+	stats := job.GetStats()
+
+	assert.Equal(t, false, stats["is_running"])
+	assert.Equal(t, "Daily at 21:00 for the next day's arrivals", stats["schedule"])
+}
@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// stayoverService returns the service due in an occupied room after nightsStayed
+// nights, or "" when none is due. A full stayover service is due every
+// frequencyDays nights; guests in the green program get a refresh instead.
+func stayoverService(nightsStayed, frequencyDays int, greenOptOut bool) string {
+	if frequencyDays < 1 {
+		frequencyDays = 1
+	}
+	if nightsStayed < 1 || nightsStayed%frequencyDays != 0 {
+		return ""
+	}
+	if greenOptOut {
+		return models.TaskTypeRefresh
+	}
+	return models.TaskTypeStayover
+}
+
+// occupiedStay is the stay in an occupied room as the night audit sees it
+type occupiedStay struct {
+	roomID        int
+	nightsStayed  int
+	frequencyDays int
+	greenOptOut   bool
+}
+
+// getOccupiedStays reads the stay in every occupied room. Rooms occupied without an
+// active assignment are treated as due for service every night.
+func getOccupiedStays(ctx context.Context, tx pgx.Tx) ([]occupiedStay, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT r.room_id,
+		       COALESCE(CURRENT_DATE - bd.check_in_date, 1),
+		       COALESCE(stay_housekeeping_frequency(bd.booking_detail_id), 1),
+		       COALESCE(bd.green_opt_out, FALSE)
+		FROM rooms r
+		LEFT JOIN room_assignments ra ON ra.room_id = r.room_id AND ra.status = 'Active'
+		LEFT JOIN booking_details bd ON bd.booking_detail_id = ra.booking_detail_id
+		WHERE r.occupancy_status = 'Occupied'
+		ORDER BY r.room_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query occupied rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var stays []occupiedStay
+	for rows.Next() {
+		var stay occupiedStay
+		if err := rows.Scan(&stay.roomID, &stay.nightsStayed, &stay.frequencyDays, &stay.greenOptOut); err != nil {
+			return nil, fmt.Errorf("failed to scan occupied room: %w", err)
+		}
+		stays = append(stays, stay)
+	}
+
+	return stays, rows.Err()
+}
+
+// scheduleStayoverServices marks the rooms whose service is due Dirty with the service
+// to do. A room still waiting for a full service keeps it when only a refresh is due;
+// rooms under maintenance or out of service are left alone. It returns the rooms
+// scheduled per service and the number of stays with no service due.
+func scheduleStayoverServices(ctx context.Context, tx pgx.Tx, stays []occupiedStay) (map[string][]int, int, error) {
+	var roomIDs []int
+	var services []string
+	for _, stay := range stays {
+		if service := stayoverService(stay.nightsStayed, stay.frequencyDays, stay.greenOptOut); service != "" {
+			roomIDs = append(roomIDs, stay.roomID)
+			services = append(services, service)
+		}
+	}
+
+	scheduled := make(map[string][]int)
+	notDue := len(stays) - len(roomIDs)
+	if len(roomIDs) == 0 {
+		return scheduled, notDue, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE rooms r
+		SET housekeeping_status = 'Dirty',
+		    stayover_service = CASE
+		        WHEN r.housekeeping_status = 'Dirty' AND r.stayover_service = 'Stayover' THEN 'Stayover'
+		        ELSE due.service
+		    END
+		FROM unnest($1::int[], $2::text[]) AS due(room_id, service)
+		WHERE r.room_id = due.room_id
+		  AND r.housekeeping_status IN ('Clean', 'Inspected', 'Dirty')
+		RETURNING r.room_id, r.stayover_service
+	`, roomIDs, services)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to schedule stayover services: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var roomID int
+		var service string
+		if err := rows.Scan(&roomID, &service); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan scheduled room: %w", err)
+		}
+		scheduled[service] = append(scheduled[service], roomID)
+	}
+
+	return scheduled, notDue, rows.Err()
+}
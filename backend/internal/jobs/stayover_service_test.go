@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStayoverService(t *testing.T) {
+	tests := []struct {
+		name          string
+		nightsStayed  int
+		frequencyDays int
+		greenOptOut   bool
+		want          string
+	}{
+		{"daily service", 1, 1, false, models.TaskTypeStayover},
+		{"arrived today", 0, 1, false, ""},
+		{"between services", 2, 3, false, ""},
+		{"every third night", 3, 3, false, models.TaskTypeStayover},
+		{"sixth night of a three-night cycle", 6, 3, false, models.TaskTypeStayover},
+		{"weekly long stay", 7, 7, false, models.TaskTypeStayover},
+		{"green program guest", 1, 1, true, models.TaskTypeRefresh},
+		{"green program between services", 1, 2, true, ""},
+		{"missing frequency serves daily", 4, 0, false, models.TaskTypeStayover},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stayoverService(tt.nightsStayed, tt.frequencyDays, tt.greenOptOut))
+		})
+	}
+}
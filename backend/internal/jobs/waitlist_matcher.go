@@ -0,0 +1,306 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/robfig/cron/v3"
+)
+
+// DefaultWaitlistOfferWindow is how long a waitlisted guest has to claim an offered hold
+const DefaultWaitlistOfferWindow = 60 * time.Minute
+
+// WaitlistNotifier delivers waitlist offers and expiries to guests
+type WaitlistNotifier interface {
+	NotifyOffer(entry *models.WaitlistEntry) error
+	NotifyOfferExpired(entry *models.WaitlistEntry) error
+}
+
+// logWaitlistNotifier writes notifications to the job log until a mail/SMS provider is configured
+type logWaitlistNotifier struct {
+	logger *log.Logger
+}
+
+func (n *logWaitlistNotifier) NotifyOffer(entry *models.WaitlistEntry) error {
+	n.logger.Printf("NOTIFY %s: room available for waitlist #%d (%s, %s - %s), claim with session %s before %s",
+		entry.Email, entry.WaitlistID, entry.RoomTypeName,
+		entry.CheckInDate.Format("2006-01-02"), entry.CheckOutDate.Format("2006-01-02"),
+		derefString(entry.SessionID), entry.OfferExpiresAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+func (n *logWaitlistNotifier) NotifyOfferExpired(entry *models.WaitlistEntry) error {
+	n.logger.Printf("NOTIFY %s: waitlist offer #%d expired without booking", entry.Email, entry.WaitlistID)
+	return nil
+}
+
+// WaitlistMatcherJob offers freed-up inventory to waitlisted guests
+type WaitlistMatcherJob struct {
+	db          *database.DB
+	cron        *cron.Cron
+	logger      *log.Logger
+	notifier    WaitlistNotifier
+	offerWindow time.Duration
+	trigger     chan struct{}
+	done        chan struct{}
+	mu          sync.Mutex
+	onHolds     func()
+}
+
+// WaitlistMatchResult contains the results of a waitlist matching run
+type WaitlistMatchResult struct {
+	Timestamp     time.Time
+	OffersMade    int
+	OffersExpired int
+	EntriesLapsed int
+	Events        []models.WaitlistMatchEvent
+	Success       bool
+	ErrorMessage  string
+	ExecutionTime time.Duration
+}
+
+// NewWaitlistMatcherJob creates a new waitlist matcher job instance
+func NewWaitlistMatcherJob(db *database.DB) *WaitlistMatcherJob {
+	logger := log.New(log.Writer(), "[WAITLIST] ", log.LstdFlags|log.Lshortfile)
+
+	return &WaitlistMatcherJob{
+		db:          db,
+		cron:        cron.New(),
+		logger:      logger,
+		notifier:    &logWaitlistNotifier{logger: logger},
+		offerWindow: DefaultWaitlistOfferWindow,
+		trigger:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+}
+
+// OnHoldsChanged registers a callback invoked after a run makes or expires offers, which
+// hold and release rooms (used to drop cached availability)
+func (j *WaitlistMatcherJob) OnHoldsChanged(fn func()) {
+	j.onHolds = fn
+}
+
+// SetNotifier replaces the default log notifier
+func (j *WaitlistMatcherJob) SetNotifier(notifier WaitlistNotifier) {
+	j.notifier = notifier
+}
+
+// Start begins the scheduled matcher and listens for inventory release signals
+// Runs every minute as a safety net for expiring offers
+func (j *WaitlistMatcherJob) Start() error {
+	j.logger.Println("Initializing waitlist matcher...")
+
+	_, err := j.cron.AddFunc("* * * * *", func() {
+		result := j.Run()
+		j.logResult(result)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule waitlist matcher: %w", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-j.trigger:
+				result := j.Run()
+				j.logResult(result)
+			case <-j.done:
+				return
+			}
+		}
+	}()
+
+	j.cron.Start()
+	j.logger.Println("Waitlist matcher started successfully (runs every minute and on inventory release)")
+
+	return nil
+}
+
+// Stop gracefully stops the waitlist matcher
+func (j *WaitlistMatcherJob) Stop() {
+	j.logger.Println("Stopping waitlist matcher...")
+	ctx := j.cron.Stop()
+	<-ctx.Done()
+	close(j.done)
+	j.logger.Println("Waitlist matcher stopped")
+}
+
+// InventoryReleased signals that inventory may have become available
+// (cancellation, expired holds or allotment increase). It never blocks;
+// signals arriving while a run is pending are coalesced.
+func (j *WaitlistMatcherJob) InventoryReleased() {
+	select {
+	case j.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run executes the waitlist matching process immediately
+func (j *WaitlistMatcherJob) Run() WaitlistMatchResult {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	startTime := time.Now()
+	result := WaitlistMatchResult{
+		Timestamp: startTime,
+		Events:    []models.WaitlistMatchEvent{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rows, err := j.db.Pool.Query(ctx, `SELECT * FROM match_waitlist($1)`, int(j.offerWindow.Minutes()))
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("failed to execute waitlist matching: %v", err)
+		j.logger.Printf("ERROR: %s", result.ErrorMessage)
+		result.ExecutionTime = time.Since(startTime)
+		return result
+	}
+
+	for rows.Next() {
+		var event models.WaitlistMatchEvent
+		if err := rows.Scan(&event.Event, &event.WaitlistID, &event.SessionID, &event.OfferExpiresAt); err != nil {
+			rows.Close()
+			result.ErrorMessage = fmt.Sprintf("failed to scan waitlist event: %v", err)
+			j.logger.Printf("ERROR: %s", result.ErrorMessage)
+			result.ExecutionTime = time.Since(startTime)
+			return result
+		}
+		result.Events = append(result.Events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		result.ErrorMessage = fmt.Sprintf("failed to execute waitlist matching: %v", err)
+		j.logger.Printf("ERROR: %s", result.ErrorMessage)
+		result.ExecutionTime = time.Since(startTime)
+		return result
+	}
+
+	for _, event := range result.Events {
+		switch event.Event {
+		case models.WaitlistStatusOffered:
+			result.OffersMade++
+		case models.WaitlistStatusExpired:
+			result.OffersExpired++
+		default:
+			result.EntriesLapsed++
+		}
+		j.notify(ctx, event)
+	}
+	if result.OffersMade+result.OffersExpired > 0 && j.onHolds != nil {
+		j.onHolds()
+	}
+
+	result.Success = true
+	result.ExecutionTime = time.Since(startTime)
+	return result
+}
+
+// RunManual executes the waitlist matcher manually and returns the result
+func (j *WaitlistMatcherJob) RunManual() (WaitlistMatchResult, error) {
+	j.logger.Println("Manual waitlist matching triggered")
+	result := j.Run()
+
+	if !result.Success {
+		return result, fmt.Errorf("waitlist matching failed: %s", result.ErrorMessage)
+	}
+
+	return result, nil
+}
+
+// notify loads the entry and sends the matching notification; failures are logged only
+func (j *WaitlistMatcherJob) notify(ctx context.Context, event models.WaitlistMatchEvent) {
+	if event.Event != models.WaitlistStatusOffered && event.Event != models.WaitlistStatusExpired {
+		return
+	}
+
+	var entry models.WaitlistEntry
+	err := j.db.Pool.QueryRow(ctx, `
+		SELECT w.waitlist_id, w.first_name, w.last_name, w.email, w.room_type_id, rt.name,
+		       w.check_in_date, w.check_out_date, w.status, w.session_id, w.offer_expires_at
+		FROM waitlist_entries w
+		JOIN room_types rt ON w.room_type_id = rt.room_type_id
+		WHERE w.waitlist_id = $1
+	`, event.WaitlistID).Scan(
+		&entry.WaitlistID,
+		&entry.FirstName,
+		&entry.LastName,
+		&entry.Email,
+		&entry.RoomTypeID,
+		&entry.RoomTypeName,
+		&entry.CheckInDate,
+		&entry.CheckOutDate,
+		&entry.Status,
+		&entry.SessionID,
+		&entry.OfferExpiresAt,
+	)
+	if err != nil {
+		j.logger.Printf("WARNING: failed to load waitlist entry %d for notification: %v", event.WaitlistID, err)
+		return
+	}
+
+	if event.Event == models.WaitlistStatusOffered {
+		err = j.notifier.NotifyOffer(&entry)
+	} else {
+		err = j.notifier.NotifyOfferExpired(&entry)
+	}
+	if err != nil {
+		j.logger.Printf("WARNING: failed to notify waitlist entry %d: %v", event.WaitlistID, err)
+	}
+}
+
+// logResult logs the matcher result; quiet runs are not logged
+func (j *WaitlistMatcherJob) logResult(result WaitlistMatchResult) {
+	if !result.Success {
+		j.logger.Printf("✗ Waitlist Matching Failed | Time: %s | Error: %s | Duration: %v",
+			result.Timestamp.Format("2006-01-02 15:04:05"),
+			result.ErrorMessage,
+			result.ExecutionTime)
+		return
+	}
+
+	if len(result.Events) > 0 {
+		j.logger.Printf("✓ Waitlist Matching Success | Time: %s | Offers: %d | Expired: %d | Lapsed: %d | Duration: %v",
+			result.Timestamp.Format("2006-01-02 15:04:05"),
+			result.OffersMade,
+			result.OffersExpired,
+			result.EntriesLapsed,
+			result.ExecutionTime)
+	}
+}
+
+// GetNextRunTime returns the next scheduled run time
+func (j *WaitlistMatcherJob) GetNextRunTime() time.Time {
+	entries := j.cron.Entries()
+	if len(entries) > 0 {
+		return entries[0].Next
+	}
+	return time.Time{}
+}
+
+// IsRunning returns whether the scheduler is running
+func (j *WaitlistMatcherJob) IsRunning() bool {
+	return len(j.cron.Entries()) > 0
+}
+
+// GetStats returns statistics about the waitlist matcher job
+func (j *WaitlistMatcherJob) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"is_running":    j.IsRunning(),
+		"next_run_time": j.GetNextRunTime(),
+		"schedule":      "Every minute and on inventory release",
+		"offer_window":  j.offerWindow.String(),
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
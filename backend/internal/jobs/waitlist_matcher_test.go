@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWaitlistMatcherJob(t *testing.T) {
+	job := NewWaitlistMatcherJob(nil)
+
+	assert.NotNil(t, job)
+	assert.NotNil(t, job.cron)
+	assert.NotNil(t, job.logger)
+	assert.NotNil(t, job.notifier)
+	assert.Equal(t, DefaultWaitlistOfferWindow, job.offerWindow)
+}
+
+func TestWaitlistMatcherJob_InventoryReleasedCoalesces(t *testing.T) {
+	job := NewWaitlistMatcherJob(nil)
+
+	// Signals must never block, even when nobody is consuming them
+	job.InventoryReleased()
+	job.InventoryReleased()
+	job.InventoryReleased()
+
+	assert.Len(t, job.trigger, 1)
+}
+
+func TestWaitlistMatcherJob_GetStats(t *testing.T) {
+	job := NewWaitlistMatcherJob(nil)
+
+	stats := job.GetStats()
+
+	assert.Equal(t, false, stats["is_running"])
+	assert.Equal(t, "Every minute and on inventory release", stats["schedule"])
+	assert.Equal(t, "1h0m0s", stats["offer_window"])
+}
@@ -57,7 +57,7 @@ func RequireGuest() gin.HandlerFunc {
 
 // RequireStaff middleware - any staff can access
 func RequireStaff() gin.HandlerFunc {
-	return RequireRole("RECEPTIONIST", "HOUSEKEEPER", "MANAGER")
+	return RequireRole("RECEPTIONIST", "HOUSEKEEPER", "ENGINEER", "MANAGER")
 }
 
 // RequireReceptionist middleware - only receptionists and managers
@@ -70,6 +70,11 @@ func RequireHousekeeper() gin.HandlerFunc {
 	return RequireRole("HOUSEKEEPER", "MANAGER")
 }
 
+// RequireEngineer middleware - only engineers and managers
+func RequireEngineer() gin.HandlerFunc {
+	return RequireRole("ENGINEER", "MANAGER")
+}
+
 // RequireManager middleware - only managers
 func RequireManager() gin.HandlerFunc {
 	return RequireRole("MANAGER")
@@ -77,7 +82,7 @@ func RequireManager() gin.HandlerFunc {
 
 // RequireGuestOrStaff middleware - guests or any staff
 func RequireGuestOrStaff() gin.HandlerFunc {
-	return RequireRole("GUEST", "RECEPTIONIST", "HOUSEKEEPER", "MANAGER")
+	return RequireRole("GUEST", "RECEPTIONIST", "HOUSEKEEPER", "ENGINEER", "MANAGER")
 }
 
 // RoleBasedAccess provides more flexible role checking
@@ -158,7 +163,7 @@ func IsStaff(c *gin.Context) bool {
 	if !exists {
 		return false
 	}
-	return role == "RECEPTIONIST" || role == "HOUSEKEEPER" || role == "MANAGER"
+	return role == "RECEPTIONIST" || role == "HOUSEKEEPER" || role == "ENGINEER" || role == "MANAGER"
 }
 
 // IsManager checks if current user is a manager
@@ -260,9 +265,10 @@ func ValidateResourceOwnership() gin.HandlerFunc {
 // RoleRouteMap defines which roles can access which route patterns
 var RoleRouteMap = map[string][]string{
 	"/api/bookings":           {"GUEST", "RECEPTIONIST", "MANAGER"},
-	"/api/staff":              {"RECEPTIONIST", "HOUSEKEEPER", "MANAGER"},
+	"/api/staff":              {"RECEPTIONIST", "HOUSEKEEPER", "ENGINEER", "MANAGER"},
 	"/api/admin":              {"MANAGER"},
 	"/api/housekeeping":       {"HOUSEKEEPER", "MANAGER"},
+	"/api/maintenance":        {"RECEPTIONIST", "HOUSEKEEPER", "ENGINEER", "MANAGER"},
 	"/api/reports":            {"MANAGER"},
 	"/api/pricing":            {"MANAGER"},
 	"/api/inventory":          {"MANAGER"},
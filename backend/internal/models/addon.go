@@ -0,0 +1,110 @@
+package models
+
+import "time"
+
+// Add-on pricing units
+const (
+	AddOnPerStay       = "PerStay"
+	AddOnPerNight      = "PerNight"
+	AddOnPerGuest      = "PerGuest"
+	AddOnPerGuestNight = "PerGuestNight"
+	AddOnPerUnit       = "PerUnit"
+)
+
+// AddOn is an extra sold with a booking, e.g. breakfast, airport transfer or spa
+type AddOn struct {
+	AddOnID     int       `json:"addon_id" db:"addon_id"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	Category    string    `json:"category" db:"category"`
+	PricingUnit string    `json:"pricing_unit" db:"pricing_unit"`
+	Price       float64   `json:"price" db:"price"`
+	DailyLimit  *int      `json:"daily_limit,omitempty" db:"daily_limit"` // nil = unlimited
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateAddOnRequest represents the request to create an add-on
+type CreateAddOnRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description"`
+	Category    string  `json:"category"`
+	PricingUnit string  `json:"pricing_unit" binding:"required,oneof=PerStay PerNight PerGuest PerGuestNight PerUnit"`
+	Price       float64 `json:"price" binding:"min=0"`
+	DailyLimit  *int    `json:"daily_limit" binding:"omitempty,min=0"`
+}
+
+// UpdateAddOnRequest represents the request to update an add-on
+type UpdateAddOnRequest struct {
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Category    *string  `json:"category"`
+	PricingUnit *string  `json:"pricing_unit" binding:"omitempty,oneof=PerStay PerNight PerGuest PerGuestNight PerUnit"`
+	Price       *float64 `json:"price" binding:"omitempty,min=0"`
+	DailyLimit  *int     `json:"daily_limit" binding:"omitempty,min=0"`
+	Unlimited   bool     `json:"unlimited"` // Clears the daily limit
+	IsActive    *bool    `json:"is_active"`
+}
+
+// SetRatePlanInclusionsRequest replaces the add-ons included in a rate plan
+type SetRatePlanInclusionsRequest struct {
+	AddOnIDs []int `json:"addon_ids"`
+}
+
+// BookingAddOnRequest is an add-on ordered with a booking detail.
+// Quantity defaults to the number of guests for per-guest add-ons and 1 otherwise;
+// ServiceDate (YYYY-MM-DD) defaults to the check-in date and is ignored for per-night add-ons.
+type BookingAddOnRequest struct {
+	AddOnID     int     `json:"addon_id" binding:"required"`
+	Quantity    int     `json:"quantity" binding:"min=0"`
+	ServiceDate *string `json:"service_date,omitempty"`
+}
+
+// BookingAddOn is an add-on booked on a booking detail
+type BookingAddOn struct {
+	BookingAddOnID   int       `json:"booking_addon_id" db:"booking_addon_id"`
+	BookingDetailID  int       `json:"booking_detail_id" db:"booking_detail_id"`
+	AddOnID          int       `json:"addon_id" db:"addon_id"`
+	Name             string    `json:"name"`
+	Category         string    `json:"category"`
+	PricingUnit      string    `json:"pricing_unit"`
+	Quantity         int       `json:"quantity" db:"quantity"`
+	UnitPrice        float64   `json:"unit_price" db:"unit_price"`
+	TotalPrice       float64   `json:"total_price" db:"total_price"`
+	ServiceStartDate time.Time `json:"service_start_date" db:"service_start_date"`
+	ServiceEndDate   time.Time `json:"service_end_date" db:"service_end_date"`
+	IsIncluded       bool      `json:"is_included" db:"is_included"`
+}
+
+// Folio line categories
+const (
+	FolioCategoryRoom     = "Room"
+	FolioCategoryAddOn    = "AddOn"
+	FolioCategoryUpgrade  = "Upgrade"
+	FolioCategoryStayTime = "StayTime"
+	FolioCategoryDiscount = "Discount"
+)
+
+// FolioLine is one charge or credit on a booking folio
+type FolioLine struct {
+	Date        *time.Time `json:"date,omitempty"`
+	Category    string     `json:"category"`
+	Description string     `json:"description"`
+	Quantity    int        `json:"quantity"`
+	UnitPrice   float64    `json:"unit_price"`
+	Amount      float64    `json:"amount"`
+}
+
+// BookingFolio itemizes the charges of a booking by revenue category
+type BookingFolio struct {
+	BookingID     int         `json:"booking_id"`
+	Status        string      `json:"status"`
+	Lines         []FolioLine `json:"lines"`
+	RoomTotal     float64     `json:"room_total"`
+	AddOnTotal    float64     `json:"addon_total"`
+	UpgradeTotal  float64     `json:"upgrade_total"`
+	StayTimeTotal float64     `json:"stay_time_total"`
+	DiscountTotal float64     `json:"discount_total"`
+	Total         float64     `json:"total"`
+}
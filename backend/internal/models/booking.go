@@ -17,25 +17,34 @@ type Booking struct {
 
 // BookingDetail represents details of a booking
 type BookingDetail struct {
-	BookingDetailID int       `json:"booking_detail_id" db:"booking_detail_id"`
-	BookingID       int       `json:"booking_id" db:"booking_id"`
-	RoomTypeID      int       `json:"room_type_id" db:"room_type_id"`
-	RatePlanID      int       `json:"rate_plan_id" db:"rate_plan_id"`
-	CheckInDate     time.Time `json:"check_in_date" db:"check_in_date"`
-	CheckOutDate    time.Time `json:"check_out_date" db:"check_out_date"`
-	NumGuests       int       `json:"num_guests" db:"num_guests"`
+	BookingDetailID int        `json:"booking_detail_id" db:"booking_detail_id"`
+	BookingID       int        `json:"booking_id" db:"booking_id"`
+	RoomTypeID      int        `json:"room_type_id" db:"room_type_id"`
+	RatePlanID      int        `json:"rate_plan_id" db:"rate_plan_id"`
+	CheckInDate     time.Time  `json:"check_in_date" db:"check_in_date"`
+	CheckOutDate    time.Time  `json:"check_out_date" db:"check_out_date"`
+	NumGuests       int        `json:"num_guests" db:"num_guests"`
+	NumAdults       *int       `json:"num_adults,omitempty" db:"num_adults"`
+	ChildAges       []int      `json:"child_ages,omitempty" db:"child_ages"`
+	ExtraBeds       int        `json:"extra_beds" db:"extra_beds"`
+	StayType        string     `json:"stay_type" db:"stay_type"`
+	DayUseStart     *time.Time `json:"day_use_start,omitempty"` // Slot of a day-use stay
+	DayUseEnd       *time.Time `json:"day_use_end,omitempty"`
 }
 
 // BookingGuest represents a guest in a booking
 type BookingGuest struct {
-	BookingGuestID  int     `json:"booking_guest_id" db:"booking_guest_id"`
-	BookingDetailID int     `json:"booking_detail_id" db:"booking_detail_id"`
-	FirstName       string  `json:"first_name" db:"first_name"`
-	LastName        string  `json:"last_name" db:"last_name"`
-	Phone           *string `json:"phone,omitempty" db:"phone"`
-	Email           *string `json:"email,omitempty" db:"email"`
-	Type            string  `json:"type" db:"type"`
-	IsPrimary       bool    `json:"is_primary" db:"is_primary"`
+	BookingGuestID  int        `json:"booking_guest_id" db:"booking_guest_id"`
+	BookingDetailID int        `json:"booking_detail_id" db:"booking_detail_id"`
+	FirstName       string     `json:"first_name" db:"first_name"`
+	LastName        string     `json:"last_name" db:"last_name"`
+	Phone           *string    `json:"phone,omitempty" db:"phone"`
+	Email           *string    `json:"email,omitempty" db:"email"`
+	Type            string     `json:"type" db:"type"`
+	IsPrimary       bool       `json:"is_primary" db:"is_primary"`
+	RoomingListRef  *string    `json:"rooming_list_ref,omitempty" db:"rooming_list_ref"`
+	ArrivalDate     *time.Time `json:"arrival_date,omitempty" db:"arrival_date"`
+	DepartureDate   *time.Time `json:"departure_date,omitempty" db:"departure_date"`
 }
 
 // BookingNightlyLog represents the nightly pricing log
@@ -71,16 +80,19 @@ type CreateBookingHoldRequest struct {
 	SessionID      string `json:"session_id" binding:"required"`
 	GuestAccountID *int   `json:"guest_account_id,omitempty"`
 	RoomTypeID     int    `json:"room_type_id" binding:"required"`
+	RatePlanID     *int   `json:"rate_plan_id,omitempty"` // Applies plan-specific restrictions when set
 	CheckIn        string `json:"check_in" binding:"required"`
 	CheckOut       string `json:"check_out" binding:"required"`
+	Rooms          int    `json:"rooms,omitempty" binding:"omitempty,min=1,max=5"` // Rooms of the room type to hold, 1 by default
 }
 
 // CreateBookingHoldResponse represents the response from creating a hold
 type CreateBookingHoldResponse struct {
-	HoldID     int       `json:"hold_id"`
-	Success    bool      `json:"success"`
-	Message    string    `json:"message"`
-	HoldExpiry time.Time `json:"hold_expiry,omitempty"`
+	HoldID       int                    `json:"hold_id"`
+	Success      bool                   `json:"success"`
+	Message      string                 `json:"message"`
+	HoldExpiry   time.Time              `json:"hold_expiry,omitempty"`
+	Restrictions []RestrictionViolation `json:"restrictions,omitempty"`
 }
 
 // CreateBookingRequest represents the request to create a booking
@@ -92,12 +104,14 @@ type CreateBookingRequest struct {
 
 // CreateBookingDetailRequest represents details for a single room booking
 type CreateBookingDetailRequest struct {
-	RoomTypeID int                  `json:"room_type_id" binding:"required"`
-	RatePlanID int                  `json:"rate_plan_id" binding:"required"`
-	CheckIn    string               `json:"check_in" binding:"required"`
-	CheckOut   string               `json:"check_out" binding:"required"`
-	NumGuests  int                  `json:"num_guests" binding:"required,min=1"`
-	Guests     []CreateGuestRequest `json:"guests" binding:"required,min=1,dive"`
+	RoomTypeID int                   `json:"room_type_id" binding:"required"`
+	RatePlanID int                   `json:"rate_plan_id" binding:"required"`
+	CheckIn    string                `json:"check_in" binding:"required"`
+	CheckOut   string                `json:"check_out" binding:"required"`
+	NumGuests  int                   `json:"num_guests" binding:"required,min=1"`
+	ChildAges  []int                 `json:"child_ages,omitempty"` // Ages of the children included in NumGuests; defaults to the Child guests' ages
+	Guests     []CreateGuestRequest  `json:"guests" binding:"required,min=1,dive"`
+	AddOns     []BookingAddOnRequest `json:"addons,omitempty" binding:"omitempty,dive"`
 }
 
 // CreateGuestRequest represents a guest in the booking
@@ -107,6 +121,7 @@ type CreateGuestRequest struct {
 	Phone     *string `json:"phone,omitempty"`
 	Email     *string `json:"email,omitempty"`
 	Type      string  `json:"type" binding:"required,oneof=Adult Child"`
+	Age       *int    `json:"age,omitempty" binding:"omitempty,min=0,max=17"` // Required for Child guests when child_ages is not given
 	IsPrimary bool    `json:"is_primary"`
 }
 
@@ -154,10 +169,16 @@ type BookingWithDetails struct {
 // BookingDetailWithGuests represents a booking detail with guests
 type BookingDetailWithGuests struct {
 	BookingDetail
-	RoomTypeName  string              `json:"room_type_name"`
-	Guests        []BookingGuest      `json:"guests"`
-	NightlyPrices []BookingNightlyLog `json:"nightly_prices,omitempty"`
-	RoomNumber    *string             `json:"room_number,omitempty"`
+	RoomTypeName          string              `json:"room_type_name"`
+	BillingCycle          string              `json:"billing_cycle"`               // From the rate plan
+	HousekeepingFrequency int                 `json:"housekeeping_frequency_days"` // From the rate plan
+	Guests                []BookingGuest      `json:"guests"`
+	NightlyPrices         []BookingNightlyLog `json:"nightly_prices,omitempty"`
+	AddOns                []BookingAddOn      `json:"addons,omitempty"`
+	Upgrades              []RoomUpgrade       `json:"upgrades,omitempty"`
+	StayTimeRequests      []StayTimeRequest   `json:"stay_time_requests,omitempty"`
+	Walks                 []BookingWalk       `json:"walks,omitempty"`
+	RoomNumber            *string             `json:"room_number,omitempty"`
 }
 
 // GetBookingsRequest represents query parameters for getting bookings
@@ -180,7 +201,7 @@ type GetBookingsResponse struct {
 // CheckInRequest represents the request to check in a guest
 type CheckInRequest struct {
 	BookingDetailID int `json:"booking_detail_id" binding:"required"`
-	RoomID          int `json:"room_id" binding:"required"`
+	RoomID          int `json:"room_id"` // Omit to check in to the pre-assigned room
 }
 
 // CheckInResponse represents the response from check-in
@@ -253,22 +274,38 @@ type ArrivalInfo struct {
 	PaymentStatus   string    `json:"payment_status" db:"payment_status"`
 	PaymentProofURL *string   `json:"payment_proof_url,omitempty" db:"payment_proof_url"`
 	PaymentProofID  *int      `json:"payment_proof_id,omitempty" db:"payment_proof_id"`
+
+	// Room preferences and the room picked ahead of check-in, if any
+	IsVIP                 bool     `json:"is_vip" db:"is_vip"`
+	FloorPreference       *string  `json:"floor_preference,omitempty" db:"floor_preference"`
+	ViewPreference        *string  `json:"view_preference,omitempty" db:"view_preference"`
+	AccessibleRoom        bool     `json:"accessible_room" db:"accessible_room"`
+	ConnectingRoom        bool     `json:"connecting_room" db:"connecting_room"`
+	PreAssignedRoomID     *int     `json:"pre_assigned_room_id,omitempty" db:"pre_assigned_room_id"`
+	PreAssignedRoomNumber *string  `json:"pre_assigned_room_number,omitempty" db:"pre_assigned_room_number"`
+	UnmetPreferences      []string `json:"unmet_preferences,omitempty" db:"unmet_preferences"`
+
+	// Approved early check-in, HH:MM
+	EarlyCheckInTime *string `json:"early_check_in_time,omitempty" db:"early_check_in_time"`
 }
 
 // DepartureInfo represents information about a departing guest
 type DepartureInfo struct {
-	BookingID    int       `json:"booking_id" db:"booking_id"`
-	GuestName    string    `json:"guest_name" db:"guest_name"`
-	RoomNumber   string    `json:"room_number" db:"room_number"`
-	CheckOutDate time.Time `json:"check_out_date" db:"check_out_date"`
-	TotalAmount  float64   `json:"total_amount" db:"total_amount"`
-	Status       string    `json:"status" db:"status"`
+	BookingID        int       `json:"booking_id" db:"booking_id"`
+	BookingDetailID  int       `json:"booking_detail_id" db:"booking_detail_id"`
+	GuestName        string    `json:"guest_name" db:"guest_name"`
+	RoomNumber       string    `json:"room_number" db:"room_number"`
+	CheckOutDate     time.Time `json:"check_out_date" db:"check_out_date"`
+	TotalAmount      float64   `json:"total_amount" db:"total_amount"`
+	Status           string    `json:"status" db:"status"`
+	LateCheckOutTime *string   `json:"late_check_out_time,omitempty" db:"late_check_out_time"` // Approved late check-out, HH:MM
 }
 
 // AvailableRoomForCheckIn represents a room available for check-in
 type AvailableRoomForCheckIn struct {
-	RoomID              int    `json:"room_id" db:"room_id"`
-	RoomNumber          string `json:"room_number" db:"room_number"`
-	OccupancyStatus     string `json:"occupancy_status" db:"occupancy_status"`
-	HousekeepingStatus  string `json:"housekeeping_status" db:"housekeeping_status"`
+	RoomID             int    `json:"room_id" db:"room_id"`
+	RoomNumber         string `json:"room_number" db:"room_number"`
+	OccupancyStatus    string `json:"occupancy_status" db:"occupancy_status"`
+	HousekeepingStatus string `json:"housekeeping_status" db:"housekeeping_status"`
+	PreAssignedTo      *int   `json:"pre_assigned_booking_detail_id,omitempty" db:"pre_assigned_booking_detail_id"` // Held for another arrival today
 }
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Booking detail stay types
+const (
+	StayTypeOvernight = "Overnight"
+	StayTypeDayUse    = "DayUse"
+)
+
+// Day-use slots must start and end within these hours of the day
+const (
+	DayUseOpenHour  = 8
+	DayUseCloseHour = 20
+)
+
+// ErrDayUseSoldOut is returned when no physical room can take a day-use slot
+var ErrDayUseSoldOut = errors.New("no room available for this day-use slot")
+
+// DayUseRate is the price of a day-use slot of a given length for a room type
+type DayUseRate struct {
+	DayUseRateID  int       `json:"day_use_rate_id" db:"day_use_rate_id"`
+	RatePlanID    int       `json:"rate_plan_id" db:"rate_plan_id"`
+	RatePlanName  string    `json:"rate_plan_name"`
+	RoomTypeID    int       `json:"room_type_id" db:"room_type_id"`
+	RoomTypeName  string    `json:"room_type_name"`
+	DurationHours int       `json:"duration_hours" db:"duration_hours"`
+	Price         float64   `json:"price" db:"price"`
+	IsActive      bool      `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpsertDayUseRateRequest creates or updates the price of a slot length
+type UpsertDayUseRateRequest struct {
+	RatePlanID    int     `json:"rate_plan_id" binding:"required"`
+	RoomTypeID    int     `json:"room_type_id" binding:"required"`
+	DurationHours int     `json:"duration_hours" binding:"required,min=1,max=12"`
+	Price         float64 `json:"price" binding:"min=0"`
+	IsActive      *bool   `json:"is_active"`
+}
+
+// DayUseSearchRequest represents the day-use availability query
+type DayUseSearchRequest struct {
+	Date          string `form:"date" binding:"required"`
+	Guests        int    `form:"guests" binding:"required,min=1"`
+	RoomTypeID    *int   `form:"room_type_id"`
+	DurationHours int    `form:"duration" binding:"omitempty,min=1,max=12"`
+}
+
+// DayUseSlot is a bookable slot with the rooms still free for it
+type DayUseSlot struct {
+	StartTime      string  `json:"start_time"` // HH:MM
+	EndTime        string  `json:"end_time"`   // HH:MM
+	DurationHours  int     `json:"duration_hours"`
+	Price          float64 `json:"price"`
+	AvailableRooms int     `json:"available_rooms"`
+}
+
+// DayUseAvailability lists the slots of a room type on a date
+type DayUseAvailability struct {
+	RoomTypeID   int          `json:"room_type_id"`
+	RoomTypeName string       `json:"room_type_name"`
+	MaxOccupancy int          `json:"max_occupancy"`
+	RatePlanID   int          `json:"rate_plan_id"`
+	RatePlanName string       `json:"rate_plan_name"`
+	Slots        []DayUseSlot `json:"slots"`
+}
+
+// DayUseSearchResponse represents the day-use search results
+type DayUseSearchResponse struct {
+	Date      string               `json:"date"`
+	Guests    int                  `json:"guests"`
+	RoomTypes []DayUseAvailability `json:"room_types"`
+}
+
+// CreateDayUseBookingRequest books a day-use slot. RatePlanID defaults to the first
+// active day-use plan with a rate for the room type and slot length.
+type CreateDayUseBookingRequest struct {
+	RoomTypeID    int                  `json:"room_type_id" binding:"required"`
+	RatePlanID    int                  `json:"rate_plan_id"`
+	Date          string               `json:"date" binding:"required"`
+	StartTime     string               `json:"start_time" binding:"required"` // HH:MM
+	DurationHours int                  `json:"duration_hours" binding:"required,min=1,max=12"`
+	NumGuests     int                  `json:"num_guests" binding:"required,min=1"`
+	Guests        []CreateGuestRequest `json:"guests" binding:"required,min=1,dive"`
+	VoucherCode   *string              `json:"voucher_code,omitempty"`
+}
+
+// CreateDayUseBookingResponse represents the response after booking a day-use slot
+type CreateDayUseBookingResponse struct {
+	CreateBookingResponse
+	RoomNumber string    `json:"room_number"`
+	StartAt    time.Time `json:"start_at"`
+	EndAt      time.Time `json:"end_at"`
+}
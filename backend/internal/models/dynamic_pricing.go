@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Pricing rule types
+const (
+	PricingRuleOccupancy = "Occupancy"
+	PricingRulePickup    = "Pickup"
+)
+
+// PricingRule moves dates to a higher rate tier when forecast occupancy or recent pickup
+// crosses a threshold. A nil RoomTypeID measures the whole hotel.
+type PricingRule struct {
+	RuleID             int       `json:"rule_id" db:"rule_id"`
+	Name               string    `json:"name" db:"name"`
+	RuleType           string    `json:"rule_type" db:"rule_type"`
+	RoomTypeID         *int      `json:"room_type_id,omitempty" db:"room_type_id"`
+	RoomTypeName       *string   `json:"room_type_name,omitempty"`
+	OccupancyThreshold *float64  `json:"occupancy_threshold,omitempty" db:"occupancy_threshold"`
+	PickupDays         *int      `json:"pickup_days,omitempty" db:"pickup_days"`
+	PickupThreshold    *int      `json:"pickup_threshold,omitempty" db:"pickup_threshold"`
+	TargetRateTierID   int       `json:"target_rate_tier_id" db:"target_rate_tier_id"`
+	TargetRateTierName string    `json:"target_rate_tier_name"`
+	FloorPrice         *float64  `json:"floor_price,omitempty" db:"floor_price"`
+	CeilingPrice       *float64  `json:"ceiling_price,omitempty" db:"ceiling_price"`
+	LookaheadDays      int       `json:"lookahead_days" db:"lookahead_days"`
+	IsActive           bool      `json:"is_active" db:"is_active"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreatePricingRuleRequest represents the request to create a pricing rule
+type CreatePricingRuleRequest struct {
+	Name               string   `json:"name" binding:"required"`
+	RuleType           string   `json:"rule_type" binding:"required,oneof=Occupancy Pickup"`
+	RoomTypeID         *int     `json:"room_type_id"`
+	OccupancyThreshold *float64 `json:"occupancy_threshold"`
+	PickupDays         *int     `json:"pickup_days"`
+	PickupThreshold    *int     `json:"pickup_threshold"`
+	TargetRateTierID   int      `json:"target_rate_tier_id" binding:"required"`
+	FloorPrice         *float64 `json:"floor_price"`
+	CeilingPrice       *float64 `json:"ceiling_price"`
+	LookaheadDays      int      `json:"lookahead_days"`
+}
+
+// UpdatePricingRuleRequest replaces the settings of a pricing rule; IsActive is kept when omitted
+type UpdatePricingRuleRequest struct {
+	CreatePricingRuleRequest
+	IsActive *bool `json:"is_active"`
+}
+
+// PricingRuleChange is a rate tier change made (or previewed) by the dynamic pricing engine
+type PricingRuleChange struct {
+	ChangeID         int       `json:"change_id,omitempty" db:"change_id"`
+	Date             time.Time `json:"date" db:"date"`
+	RuleID           *int      `json:"rule_id" db:"rule_id"`
+	RuleName         string    `json:"rule_name" db:"rule_name"`
+	OldRateTierID    *int      `json:"old_rate_tier_id" db:"old_rate_tier_id"`
+	OldRateTierName  *string   `json:"old_rate_tier_name"`
+	NewRateTierID    int       `json:"new_rate_tier_id" db:"new_rate_tier_id"`
+	NewRateTierName  string    `json:"new_rate_tier_name"`
+	OccupancyPercent float64   `json:"occupancy_percent" db:"occupancy_percent"`
+	Pickup           int       `json:"pickup" db:"pickup"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
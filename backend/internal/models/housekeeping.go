@@ -2,17 +2,33 @@ package models
 
 import "time"
 
+// Housekeeping task types
+const (
+	TaskTypeCheckout = "Checkout" // Vacant room to turn around for the next guest
+	TaskTypeStayover = "Stayover" // Service of a room the guest is still staying in
+	TaskTypeRefresh  = "Refresh"  // Towels and trash only, for green program guests
+)
+
 // HousekeepingTask represents a room that needs cleaning
 type HousekeepingTask struct {
-	RoomID             int       `json:"room_id" db:"room_id"`
-	RoomNumber         string    `json:"room_number" db:"room_number"`
-	RoomTypeID         int       `json:"room_type_id" db:"room_type_id"`
-	RoomTypeName       string    `json:"room_type_name" db:"room_type_name"`
-	OccupancyStatus    string    `json:"occupancy_status" db:"occupancy_status"`
-	HousekeepingStatus string    `json:"housekeeping_status" db:"housekeeping_status"`
-	Priority           int       `json:"priority" db:"priority"`
-	LastUpdated        time.Time `json:"last_updated" db:"last_updated"`
-	EstimatedTime      *int      `json:"estimated_time,omitempty" db:"estimated_time"` // in minutes
+	RoomID             int        `json:"room_id" db:"room_id"`
+	RoomNumber         string     `json:"room_number" db:"room_number"`
+	RoomTypeID         int        `json:"room_type_id" db:"room_type_id"`
+	RoomTypeName       string     `json:"room_type_name" db:"room_type_name"`
+	Floor              int        `json:"floor" db:"floor"`
+	OccupancyStatus    string     `json:"occupancy_status" db:"occupancy_status"`
+	HousekeepingStatus string     `json:"housekeeping_status" db:"housekeeping_status"`
+	TaskType           string     `json:"task_type" db:"task_type"`
+	Credits            float64    `json:"credits" db:"credits"`
+	ArrivalPending     bool       `json:"arrival_pending" db:"arrival_pending"` // Guests of this room type arrive today
+	DoNotDisturb       bool       `json:"do_not_disturb" db:"do_not_disturb"`
+	Priority           int        `json:"priority" db:"priority"`
+	AssignedTo         *int       `json:"assigned_to,omitempty" db:"assigned_to"`
+	AssigneeName       *string    `json:"assignee_name,omitempty" db:"assignee_name"`
+	StartedAt          *time.Time `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	LastUpdated        time.Time  `json:"last_updated" db:"last_updated"`
+	EstimatedTime      *int       `json:"estimated_time,omitempty" db:"estimated_time"` // in minutes
 }
 
 // UpdateRoomStatusRequest represents a request to update room status
@@ -20,8 +36,11 @@ type UpdateRoomStatusRequest struct {
 	Status string `json:"status" binding:"required"`
 }
 
-// ReportMaintenanceRequest represents a maintenance issue report
+// ReportMaintenanceRequest represents a maintenance issue report; category and
+// priority default to Other and Medium
 type ReportMaintenanceRequest struct {
+	Category    string `json:"category,omitempty"`
+	Priority    string `json:"priority,omitempty"`
 	Description string `json:"description" binding:"required"`
 }
 
@@ -33,9 +52,98 @@ type InspectRoomRequest struct {
 
 // HousekeepingTasksResponse represents the response for task list
 type HousekeepingTasksResponse struct {
-	Tasks      []HousekeepingTask `json:"tasks"`
-	TotalTasks int                `json:"total_tasks"`
-	Summary    TaskSummary        `json:"summary"`
+	Tasks      []HousekeepingTask  `json:"tasks"`
+	TotalTasks int                 `json:"total_tasks"`
+	Summary    TaskSummary         `json:"summary"`
+	Attendants []AttendantProgress `json:"attendants"`
+}
+
+// AttendantProgress represents a room attendant's rooms and credits for today
+type AttendantProgress struct {
+	StaffID          int     `json:"staff_id" db:"staff_id"`
+	Name             string  `json:"name" db:"name"`
+	AssignedRooms    int     `json:"assigned_rooms" db:"assigned_rooms"`
+	CompletedRooms   int     `json:"completed_rooms" db:"completed_rooms"`
+	AssignedCredits  float64 `json:"assigned_credits" db:"assigned_credits"`
+	CompletedCredits float64 `json:"completed_credits" db:"completed_credits"`
+	ProgressPercent  float64 `json:"progress_percent"` // Share of assigned credits completed
+}
+
+// HousekeepingAssignment represents a room handed to an attendant for today
+type HousekeepingAssignment struct {
+	RoomID   int     `json:"room_id" db:"room_id"`
+	StaffID  int     `json:"staff_id" db:"staff_id"`
+	TaskType string  `json:"task_type" db:"task_type"`
+	Credits  float64 `json:"credits" db:"credits"`
+}
+
+// AssignRoomRequest represents a request to assign a room to an attendant for today;
+// a null staff_id takes the room off its attendant
+type AssignRoomRequest struct {
+	StaffID *int `json:"staff_id"`
+}
+
+// AutoAssignRequest represents a request to balance today's rooms across attendants.
+// Attendants default to every active housekeeper; with rebalance, rooms assigned but
+// not started yet are handed out again as well.
+type AutoAssignRequest struct {
+	AttendantIDs []int `json:"attendant_ids,omitempty"`
+	Rebalance    bool  `json:"rebalance"`
+}
+
+// AutoAssignResult represents the outcome of an auto-assignment
+type AutoAssignResult struct {
+	Assignments []HousekeepingAssignment `json:"assignments"`
+	Attendants  []AttendantProgress      `json:"attendants"`
+}
+
+// HousekeepingCredits represents the workload credits and estimated minutes of each
+// service of a room type
+type HousekeepingCredits struct {
+	RoomTypeID      int     `json:"room_type_id" db:"room_type_id"`
+	RoomTypeName    string  `json:"room_type_name" db:"room_type_name"`
+	CheckoutCredits float64 `json:"checkout_credits" db:"checkout_credits"`
+	StayoverCredits float64 `json:"stayover_credits" db:"stayover_credits"`
+	RefreshCredits  float64 `json:"refresh_credits" db:"refresh_credits"`
+	CheckoutMinutes int     `json:"checkout_minutes" db:"checkout_minutes"`
+	StayoverMinutes int     `json:"stayover_minutes" db:"stayover_minutes"`
+	RefreshMinutes  int     `json:"refresh_minutes" db:"refresh_minutes"`
+}
+
+// UpdateHousekeepingCreditsRequest represents a request to set the credits and
+// estimated minutes of a room type
+type UpdateHousekeepingCreditsRequest struct {
+	CheckoutCredits float64 `json:"checkout_credits" binding:"required,gt=0,lte=99"`
+	StayoverCredits float64 `json:"stayover_credits" binding:"required,gt=0,lte=99"`
+	RefreshCredits  float64 `json:"refresh_credits" binding:"required,gt=0,lte=99"`
+	CheckoutMinutes int     `json:"checkout_minutes" binding:"required,min=1,max=480"`
+	StayoverMinutes int     `json:"stayover_minutes" binding:"required,min=1,max=480"`
+	RefreshMinutes  int     `json:"refresh_minutes" binding:"required,min=1,max=480"`
+}
+
+// RoomServicePreferences represents the do-not-disturb flag of a room and the service
+// preferences of the stay in it
+type RoomServicePreferences struct {
+	RoomID                int        `json:"room_id" db:"room_id"`
+	RoomNumber            string     `json:"room_number" db:"room_number"`
+	BookingDetailID       int        `json:"booking_detail_id" db:"booking_detail_id"`
+	DoNotDisturb          bool       `json:"do_not_disturb" db:"do_not_disturb"`
+	DNDSetAt              *time.Time `json:"dnd_set_at,omitempty" db:"dnd_set_at"`
+	DNDSetBy              *int       `json:"dnd_set_by,omitempty" db:"dnd_set_by"`
+	GreenOptOut           bool       `json:"green_opt_out" db:"green_opt_out"`
+	HousekeepingFrequency int        `json:"housekeeping_frequency_days" db:"housekeeping_frequency_days"` // From the rate plan or long-stay rate
+	StayoverService       *string    `json:"stayover_service,omitempty" db:"stayover_service"`             // Service due now, if any
+}
+
+// SetDoNotDisturbRequest represents a request to set or clear the DND flag of a room
+type SetDoNotDisturbRequest struct {
+	DoNotDisturb *bool `json:"do_not_disturb" binding:"required"`
+}
+
+// SetGreenOptOutRequest represents a request to enrol the stay in a room in the green
+// program or take it out
+type SetGreenOptOutRequest struct {
+	GreenOptOut *bool `json:"green_opt_out" binding:"required"`
 }
 
 // TaskSummary provides a summary of tasks by status
@@ -47,12 +155,46 @@ type TaskSummary struct {
 	MaintenanceRequired int `json:"maintenance_required"`
 }
 
-// RoomStatusUpdate represents a room status update log
+// RoomStatusUpdate represents a room status change in a room's history; UpdatedBy
+// is empty for changes made by scheduled jobs
 type RoomStatusUpdate struct {
-	RoomID    int       `json:"room_id" db:"room_id"`
-	OldStatus string    `json:"old_status" db:"old_status"`
-	NewStatus string    `json:"new_status" db:"new_status"`
-	UpdatedBy int       `json:"updated_by" db:"updated_by"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-	Notes     string    `json:"notes,omitempty" db:"notes"`
+	HistoryID     int64     `json:"history_id" db:"history_id"`
+	RoomID        int       `json:"room_id" db:"room_id"`
+	StatusType    string    `json:"status_type" db:"status_type"` // Housekeeping or Occupancy
+	OldStatus     string    `json:"old_status" db:"old_status"`
+	NewStatus     string    `json:"new_status" db:"new_status"`
+	UpdatedBy     *int      `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedByName *string   `json:"updated_by_name,omitempty" db:"updated_by_name"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	Notes         string    `json:"notes,omitempty" db:"notes"`
+}
+
+// CleaningTime is a room's stretch from Cleaning to Clean
+type CleaningTime struct {
+	StaffID     *int      `json:"staff_id,omitempty" db:"staff_id"`
+	StaffName   *string   `json:"staff_name,omitempty" db:"staff_name"`
+	RoomID      int       `json:"room_id" db:"room_id"`
+	RoomNumber  string    `json:"room_number" db:"room_number"`
+	StartedAt   time.Time `json:"started_at" db:"started_at"`
+	CompletedAt time.Time `json:"completed_at" db:"completed_at"`
+}
+
+// AttendantCleaningTimes represents the cleaning durations of one attendant
+type AttendantCleaningTimes struct {
+	StaffID            *int    `json:"staff_id,omitempty"` // Empty for rooms cleaned without a recorded attendant
+	StaffName          string  `json:"staff_name"`
+	RoomsCleaned       int     `json:"rooms_cleaned"`
+	MeanMinutes        float64 `json:"mean_minutes"`
+	ShortestMinutes    float64 `json:"shortest_minutes"`
+	LongestMinutes     float64 `json:"longest_minutes"`
+	TotalCleaningHours float64 `json:"total_cleaning_hours"`
+}
+
+// CleaningTimeReport represents cleaning durations per attendant over a period
+type CleaningTimeReport struct {
+	StartDate    string                   `json:"start_date"`
+	EndDate      string                   `json:"end_date"`
+	RoomsCleaned int                      `json:"rooms_cleaned"`
+	MeanMinutes  float64                  `json:"mean_minutes"`
+	Attendants   []AttendantCleaningTimes `json:"attendants"`
 }
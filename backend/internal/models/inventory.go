@@ -47,3 +47,44 @@ type InventoryValidationError struct {
 	Date    string `json:"date"`
 	Message string `json:"message"`
 }
+
+// Out-of-order period statuses
+const (
+	OutOfOrderScheduled = "Scheduled"
+	OutOfOrderActive    = "Active"
+	OutOfOrderCompleted = "Completed"
+	OutOfOrderCancelled = "Cancelled"
+)
+
+// OutOfOrderPeriod represents nights in which a physical room is taken out of service.
+// Each night takes one room off the room type's allotment.
+type OutOfOrderPeriod struct {
+	OutOfOrderID int        `json:"out_of_order_id" db:"out_of_order_id"`
+	RoomID       int        `json:"room_id" db:"room_id"`
+	RoomNumber   string     `json:"room_number" db:"room_number"`
+	RoomTypeID   int        `json:"room_type_id" db:"room_type_id"`
+	StartDate    time.Time  `json:"start_date" db:"start_date"`
+	EndDate      time.Time  `json:"end_date" db:"end_date"` // Last night out of order
+	Reason       string     `json:"reason" db:"reason"`
+	Status       string     `json:"status" db:"status"`
+	WorkOrderID  *int       `json:"work_order_id,omitempty" db:"work_order_id"`
+	CreatedBy    *int       `json:"created_by,omitempty" db:"created_by"`
+	EndedAt      *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ScheduleOutOfOrderRequest represents the request to take a room out of order;
+// end_date is the last night the room is out of order
+type ScheduleOutOfOrderRequest struct {
+	RoomID      int    `json:"room_id" binding:"required"`
+	StartDate   string `json:"start_date" binding:"required"`
+	EndDate     string `json:"end_date" binding:"required"`
+	Reason      string `json:"reason" binding:"required"`
+	WorkOrderID *int   `json:"work_order_id,omitempty"`
+}
+
+// UpdateOutOfOrderRequest represents the request to extend or shorten an out-of-order period
+type UpdateOutOfOrderRequest struct {
+	EndDate string `json:"end_date" binding:"required"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Rate plan billing cycles
+const (
+	BillingCyclePerStay = "PerStay"
+	BillingCycleWeekly  = "Weekly"
+	BillingCycleMonthly = "Monthly"
+)
+
+// LongStayMonthNights is the number of nights a monthly price covers
+const LongStayMonthNights = 30
+
+// LongStayRate prices stays of at least MinNights nights under a rate plan, either
+// as a percentage off the nightly rate or as a fixed monthly price.
+// A nil RoomTypeID applies to every room type.
+type LongStayRate struct {
+	LongStayRateID        int       `json:"long_stay_rate_id" db:"long_stay_rate_id"`
+	RatePlanID            int       `json:"rate_plan_id" db:"rate_plan_id"`
+	RatePlanName          string    `json:"rate_plan_name"`
+	RoomTypeID            *int      `json:"room_type_id" db:"room_type_id"`
+	RoomTypeName          *string   `json:"room_type_name"`
+	MinNights             int       `json:"min_nights" db:"min_nights"`
+	DiscountPercent       *float64  `json:"discount_percent,omitempty" db:"discount_percent"`
+	MonthlyPrice          *float64  `json:"monthly_price,omitempty" db:"monthly_price"`
+	HousekeepingFrequency *int      `json:"housekeeping_frequency_days,omitempty" db:"housekeeping_frequency_days"` // Overrides the rate plan
+	IsActive              bool      `json:"is_active" db:"is_active"`
+	CreatedAt             time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpsertLongStayRateRequest creates or updates the long-stay rate of a threshold.
+// Exactly one of DiscountPercent and MonthlyPrice must be set.
+type UpsertLongStayRateRequest struct {
+	RatePlanID            int      `json:"rate_plan_id" binding:"required"`
+	RoomTypeID            *int     `json:"room_type_id"`
+	MinNights             int      `json:"min_nights" binding:"required,min=2"`
+	DiscountPercent       *float64 `json:"discount_percent" binding:"omitempty,gt=0,lt=100"`
+	MonthlyPrice          *float64 `json:"monthly_price" binding:"omitempty,gt=0"`
+	HousekeepingFrequency *int     `json:"housekeeping_frequency_days" binding:"omitempty,min=1,max=30"`
+	IsActive              *bool    `json:"is_active"`
+}
+
+// BillingInvoice is one scheduled invoice of a booking
+type BillingInvoice struct {
+	InvoiceNumber int         `json:"invoice_number"`
+	PeriodStart   time.Time   `json:"period_start"`
+	PeriodEnd     time.Time   `json:"period_end"` // Exclusive, like a check-out date
+	DueDate       time.Time   `json:"due_date"`
+	Nights        int         `json:"nights"`
+	Lines         []FolioLine `json:"lines"`
+	Amount        float64     `json:"amount"`
+}
+
+// BillingSchedule splits the charges of a booking into invoices by billing cycle
+type BillingSchedule struct {
+	BookingID    int              `json:"booking_id"`
+	BillingCycle string           `json:"billing_cycle"`
+	Invoices     []BillingInvoice `json:"invoices"`
+	Total        float64          `json:"total"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import "time"
+
+// Work order statuses
+const (
+	WorkOrderOpen       = "Open"
+	WorkOrderInProgress = "InProgress"
+	WorkOrderOnHold     = "OnHold"
+	WorkOrderResolved   = "Resolved"
+)
+
+// WorkOrderCategories are the kinds of maintenance work
+var WorkOrderCategories = []string{"Plumbing", "Electrical", "HVAC", "Furniture", "Appliance", "Structural", "Other"}
+
+// WorkOrderPriorities are the work order priorities, least urgent first
+var WorkOrderPriorities = []string{"Low", "Medium", "High", "Urgent"}
+
+// MaxWorkOrderPhotos limits the photos attached to one work order
+const MaxWorkOrderPhotos = 10
+
+// WorkOrder represents a maintenance issue reported for a room
+type WorkOrder struct {
+	WorkOrderID     int        `json:"work_order_id" db:"work_order_id"`
+	RoomID          int        `json:"room_id" db:"room_id"`
+	RoomNumber      string     `json:"room_number" db:"room_number"`
+	Category        string     `json:"category" db:"category"`
+	Priority        string     `json:"priority" db:"priority"`
+	Status          string     `json:"status" db:"status"`
+	Description     string     `json:"description" db:"description"`
+	PhotoKeys       []string   `json:"-" db:"photo_keys"`
+	PhotoURLs       []string   `json:"photo_urls"`
+	ReportedBy      *int       `json:"reported_by,omitempty" db:"reported_by"`
+	AssignedTo      *int       `json:"assigned_to,omitempty" db:"assigned_to"`
+	AssigneeName    *string    `json:"assignee_name,omitempty" db:"assignee_name"`
+	ResolutionNotes *string    `json:"resolution_notes,omitempty" db:"resolution_notes"`
+	ReportedAt      time.Time  `json:"reported_at" db:"reported_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty" db:"started_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// WorkOrderFilter narrows a work order list; zero values match everything
+type WorkOrderFilter struct {
+	Status     string
+	RoomID     *int
+	AssignedTo *int
+	Unassigned bool
+	OpenOnly   bool
+}
+
+// CreateWorkOrderRequest represents a request to open a work order
+type CreateWorkOrderRequest struct {
+	RoomID      int    `json:"room_id" binding:"required"`
+	Category    string `json:"category,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	Description string `json:"description" binding:"required"`
+}
+
+// UpdateWorkOrderRequest represents a request to correct the details of a work order
+type UpdateWorkOrderRequest struct {
+	Category    *string `json:"category,omitempty"`
+	Priority    *string `json:"priority,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// AssignWorkOrderRequest represents a request to assign a work order; a null
+// assigned_to takes the work order off its assignee
+type AssignWorkOrderRequest struct {
+	AssignedTo *int `json:"assigned_to"`
+}
+
+// UpdateWorkOrderStatusRequest represents a work order status change; resolution
+// notes are required when resolving
+type UpdateWorkOrderStatusRequest struct {
+	Status          string  `json:"status" binding:"required"`
+	ResolutionNotes *string `json:"resolution_notes,omitempty"`
+}
+
+// EngineerTaskList represents an engineer's unresolved work orders and the open
+// work orders nobody has taken yet
+type EngineerTaskList struct {
+	Assigned   []WorkOrder `json:"assigned"`
+	Unassigned []WorkOrder `json:"unassigned"`
+}
+
+// RepairTime is the reported and resolved time of a resolved work order
+type RepairTime struct {
+	RoomID     int       `json:"room_id" db:"room_id"`
+	RoomNumber string    `json:"room_number" db:"room_number"`
+	ReportedAt time.Time `json:"reported_at" db:"reported_at"`
+	ResolvedAt time.Time `json:"resolved_at" db:"resolved_at"`
+}
+
+// RoomRepairTimeReport represents the repair times of one room
+type RoomRepairTimeReport struct {
+	RoomID               int     `json:"room_id"`
+	RoomNumber           string  `json:"room_number"`
+	ResolvedOrders       int     `json:"resolved_orders"`
+	MeanHoursToRepair    float64 `json:"mean_hours_to_repair"`
+	LongestHoursToRepair float64 `json:"longest_hours_to_repair"`
+}
+
+// RepairTimeReport represents the mean time to repair of work orders resolved in a period
+type RepairTimeReport struct {
+	StartDate         string                 `json:"start_date"`
+	EndDate           string                 `json:"end_date"`
+	ResolvedOrders    int                    `json:"resolved_orders"`
+	MeanHoursToRepair float64                `json:"mean_hours_to_repair"`
+	Rooms             []RoomRepairTimeReport `json:"rooms"`
+}
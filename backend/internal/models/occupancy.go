@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Occupancy surcharge charge types
+const (
+	ChargeTypeExtraAdult = "ExtraAdult"
+	ChargeTypeExtraChild = "ExtraChild"
+	ChargeTypeExtraBed   = "ExtraBed"
+)
+
+// MaxChildAge is the oldest age still priced as a child
+const MaxChildAge = 17
+
+// Occupancy is the party staying in one room
+type Occupancy struct {
+	Adults    int   `json:"adults"`
+	ChildAges []int `json:"child_ages,omitempty"`
+	ExtraBeds int   `json:"extra_beds"`
+}
+
+// OccupancySurcharge is a per-night charge for guests beyond the base occupancy.
+// A nil RoomTypeID applies to every room type; MinAge/MaxAge only matter for ExtraChild.
+type OccupancySurcharge struct {
+	SurchargeID  int       `json:"surcharge_id" db:"surcharge_id"`
+	RoomTypeID   *int      `json:"room_type_id,omitempty" db:"room_type_id"`
+	RoomTypeName *string   `json:"room_type_name,omitempty"`
+	RateTierID   int       `json:"rate_tier_id" db:"rate_tier_id"`
+	RateTierName string    `json:"rate_tier_name"`
+	ChargeType   string    `json:"charge_type" db:"charge_type"`
+	MinAge       int       `json:"min_age" db:"min_age"`
+	MaxAge       int       `json:"max_age" db:"max_age"`
+	Amount       float64   `json:"amount" db:"amount"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpsertOccupancySurchargeRequest creates or updates a surcharge
+type UpsertOccupancySurchargeRequest struct {
+	RoomTypeID *int    `json:"room_type_id"`
+	RateTierID int     `json:"rate_tier_id" binding:"required"`
+	ChargeType string  `json:"charge_type" binding:"required,oneof=ExtraAdult ExtraChild ExtraBed"`
+	MinAge     int     `json:"min_age" binding:"min=0"`
+	MaxAge     int     `json:"max_age" binding:"min=0"`
+	Amount     float64 `json:"amount" binding:"min=0"`
+}
+
+// UpdateRoomTypeOccupancyRequest updates the occupancy settings of a room type
+type UpdateRoomTypeOccupancyRequest struct {
+	BaseOccupancy     int `json:"base_occupancy" binding:"required,min=1"`
+	MaxExtraBeds      int `json:"max_extra_beds" binding:"min=0"`
+	ExtraBedAllotment int `json:"extra_bed_allotment" binding:"min=0"`
+}
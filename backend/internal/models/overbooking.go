@@ -0,0 +1,108 @@
+package models
+
+import "time"
+
+// Overbooking limit types
+const (
+	OverbookingAbsolute   = "Absolute"   // A number of rooms beyond the allotment
+	OverbookingPercentage = "Percentage" // A share of the allotment, rounded down
+)
+
+// SetOverbookingLimitRequest sets the overbooking limit of a room type for a date
+// range. An empty limit type stops overbooking on those dates.
+type SetOverbookingLimitRequest struct {
+	RoomTypeID int     `json:"room_type_id" binding:"required"`
+	StartDate  string  `json:"start_date" binding:"required"`
+	EndDate    string  `json:"end_date" binding:"required"`
+	LimitType  string  `json:"limit_type" binding:"omitempty,oneof=Absolute Percentage"`
+	LimitValue float64 `json:"limit_value" binding:"min=0"`
+}
+
+// OverbookingPosition is how far a room type is sold beyond its allotment on a date
+type OverbookingPosition struct {
+	RoomTypeID       int       `json:"room_type_id"`
+	RoomTypeName     string    `json:"room_type_name"`
+	Date             time.Time `json:"date"`
+	Allotment        int       `json:"allotment"`
+	LimitType        *string   `json:"limit_type,omitempty"`
+	LimitValue       float64   `json:"limit_value"`
+	OverbookingRooms int       `json:"overbooking_rooms"` // Rooms that may be sold beyond the allotment
+	Capacity         int       `json:"capacity"`          // Allotment plus overbooking rooms
+	BookedCount      int       `json:"booked_count"`
+	TentativeCount   int       `json:"tentative_count"`
+	Oversold         int       `json:"oversold"`         // Rooms booked or held beyond the allotment
+	Available        int       `json:"available"`        // Rooms left to sell up to the capacity
+	RoomsInService   int       `json:"rooms_in_service"` // Physical rooms not out of order
+	Walks            int       `json:"walks"`            // Guests walked on the date
+}
+
+// PartnerHotel is a hotel guests are walked to when the hotel is oversold
+type PartnerHotel struct {
+	PartnerHotelID int       `json:"partner_hotel_id" db:"partner_hotel_id"`
+	Name           string    `json:"name" db:"name"`
+	Address        *string   `json:"address,omitempty" db:"address"`
+	Phone          *string   `json:"phone,omitempty" db:"phone"`
+	ContactName    *string   `json:"contact_name,omitempty" db:"contact_name"`
+	Notes          *string   `json:"notes,omitempty" db:"notes"`
+	IsActive       bool      `json:"is_active" db:"is_active"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PartnerHotelRequest creates or updates a partner hotel
+type PartnerHotelRequest struct {
+	Name        string  `json:"name" binding:"required,max=200"`
+	Address     *string `json:"address"`
+	Phone       *string `json:"phone" binding:"omitempty,max=50"`
+	ContactName *string `json:"contact_name" binding:"omitempty,max=100"`
+	Notes       *string `json:"notes"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// BookingWalk is a booked room whose guest was relocated to a partner hotel for the
+// first nights of the stay, or for all of it
+type BookingWalk struct {
+	WalkID             int       `json:"walk_id" db:"walk_id"`
+	BookingDetailID    int       `json:"booking_detail_id" db:"booking_detail_id"`
+	BookingID          int       `json:"booking_id" db:"booking_id"`
+	GuestName          string    `json:"guest_name,omitempty" db:"guest_name"`
+	RoomTypeName       string    `json:"room_type_name" db:"room_type_name"`
+	PartnerHotelID     int       `json:"partner_hotel_id" db:"partner_hotel_id"`
+	PartnerHotelName   string    `json:"partner_hotel_name" db:"partner_hotel_name"`
+	WalkDate           time.Time `json:"walk_date" db:"walk_date"` // First night at the partner hotel
+	Nights             int       `json:"nights" db:"nights"`
+	FullStay           bool      `json:"full_stay" db:"full_stay"`         // The booking was cancelled
+	WaivedAmount       float64   `json:"waived_amount" db:"waived_amount"` // Room revenue taken off the booking
+	CompensationAmount float64   `json:"compensation_amount" db:"compensation_amount"`
+	CompensationNotes  *string   `json:"compensation_notes,omitempty" db:"compensation_notes"`
+	TransportProvided  bool      `json:"transport_provided" db:"transport_provided"`
+	Notes              *string   `json:"notes,omitempty" db:"notes"`
+	WalkedBy           *int      `json:"walked_by,omitempty" db:"walked_by"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+}
+
+// WalkGuestRequest represents staff walking an arriving guest to a partner hotel for
+// the first nights of the stay. Compensation is what the hotel pays for the walk, such
+// as the partner hotel's rate and transport.
+type WalkGuestRequest struct {
+	BookingDetailID    int     `json:"booking_detail_id" binding:"required"`
+	PartnerHotelID     int     `json:"partner_hotel_id" binding:"required"`
+	Nights             int     `json:"nights" binding:"required,min=1"`
+	CompensationAmount float64 `json:"compensation_amount" binding:"min=0"`
+	CompensationNotes  string  `json:"compensation_notes" binding:"max=500"`
+	TransportProvided  bool    `json:"transport_provided"`
+	Notes              string  `json:"notes" binding:"max=500"`
+}
+
+// WalkStay is the booked room a walk applies to
+type WalkStay struct {
+	BookingDetailID int       `db:"booking_detail_id"`
+	BookingID       int       `db:"booking_id"`
+	RoomTypeID      int       `db:"room_type_id"`
+	CheckInDate     time.Time `db:"check_in_date"`
+	CheckOutDate    time.Time `db:"check_out_date"`
+	StayType        string    `db:"stay_type"`
+	BookingStatus   string    `db:"booking_status"`
+	CheckedIn       bool      `db:"checked_in"`
+	BookedRooms     int       `db:"booked_rooms"` // Rooms of the booking
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // CancellationPolicy represents a cancellation policy
 type CancellationPolicy struct {
@@ -80,13 +84,92 @@ type ValidateVoucherResponse struct {
 	DiscountAmount  float64 `json:"discount_amount,omitempty"`
 	FinalAmount     float64 `json:"final_amount,omitempty"`
 }
-// RatePlan represents a rate plan
+// RatePlan represents a rate plan with its booking eligibility rules.
+// Nil rules do not restrict; a derived plan prices from BaseRatePlanID.
 type RatePlan struct {
-	RatePlanID  int       `json:"rate_plan_id" db:"rate_plan_id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description" db:"description"`
-	PolicyID    int       `json:"policy_id" db:"policy_id"`
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+	RatePlanID             int        `json:"rate_plan_id" db:"rate_plan_id"`
+	Name                   string     `json:"name" db:"name"`
+	Description            *string    `json:"description" db:"description"`
+	PolicyID               int        `json:"policy_id" db:"policy_id"`
+	IsActive               bool       `json:"is_active" db:"is_active"`
+	MinAdvanceDays         *int       `json:"min_advance_days,omitempty" db:"min_advance_days"`
+	MaxAdvanceDays         *int       `json:"max_advance_days,omitempty" db:"max_advance_days"`
+	StayStartDate          *time.Time `json:"stay_start_date,omitempty" db:"stay_start_date"`
+	StayEndDate            *time.Time `json:"stay_end_date,omitempty" db:"stay_end_date"`
+	BookingStartDate       *time.Time `json:"booking_start_date,omitempty" db:"booking_start_date"`
+	BookingEndDate         *time.Time `json:"booking_end_date,omitempty" db:"booking_end_date"`
+	ArrivalDaysOfWeek      []int      `json:"arrival_days_of_week,omitempty" db:"arrival_days_of_week"`
+	MinGuests              *int       `json:"min_guests,omitempty" db:"min_guests"`
+	MinNights              *int       `json:"min_nights,omitempty" db:"min_nights"`
+	MembersOnly            bool       `json:"members_only" db:"members_only"`
+	IsDayUse               bool       `json:"is_day_use" db:"is_day_use"`
+	BillingCycle           string     `json:"billing_cycle" db:"billing_cycle"`
+	HousekeepingFrequency  int        `json:"housekeeping_frequency_days" db:"housekeeping_frequency_days"`
+	BaseRatePlanID         *int       `json:"base_rate_plan_id,omitempty" db:"base_rate_plan_id"`
+	DerivedAdjustmentType  *string    `json:"derived_adjustment_type,omitempty" db:"derived_adjustment_type"`
+	DerivedAdjustmentValue *float64   `json:"derived_adjustment_value,omitempty" db:"derived_adjustment_value"`
+	CreatedAt              time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// RatePlanRulesRequest carries the eligibility rules and derived pricing of a rate plan.
+// Dates use YYYY-MM-DD; empty strings clear a date.
+type RatePlanRulesRequest struct {
+	MinAdvanceDays         *int     `json:"min_advance_days"`
+	MaxAdvanceDays         *int     `json:"max_advance_days"`
+	StayStartDate          *string  `json:"stay_start_date"`
+	StayEndDate            *string  `json:"stay_end_date"`
+	BookingStartDate       *string  `json:"booking_start_date"`
+	BookingEndDate         *string  `json:"booking_end_date"`
+	ArrivalDaysOfWeek      []int    `json:"arrival_days_of_week"`
+	MinGuests              *int     `json:"min_guests"`
+	MinNights              *int     `json:"min_nights"`
+	MembersOnly            bool     `json:"members_only"`
+	BaseRatePlanID         *int     `json:"base_rate_plan_id"`
+	DerivedAdjustmentType  *string  `json:"derived_adjustment_type" binding:"omitempty,oneof=percentage fixed"`
+	DerivedAdjustmentValue *float64 `json:"derived_adjustment_value"`
+}
+
+// CreateRatePlanRequest represents the request to create a rate plan.
+// Billing defaults to one invoice per stay and housekeeping to daily service.
+type CreateRatePlanRequest struct {
+	Name                  string                `json:"name" binding:"required"`
+	Description           *string               `json:"description"`
+	PolicyID              int                   `json:"policy_id" binding:"required"`
+	IsDayUse              bool                  `json:"is_day_use"` // fixed once the plan is created
+	BillingCycle          string                `json:"billing_cycle" binding:"omitempty,oneof=PerStay Weekly Monthly"`
+	HousekeepingFrequency int                   `json:"housekeeping_frequency_days" binding:"omitempty,min=1,max=30"`
+	Rules                 *RatePlanRulesRequest `json:"rules"`
+}
+
+// UpdateRatePlanRequest represents the request to update a rate plan.
+// When Rules is provided the rules are replaced as a whole.
+type UpdateRatePlanRequest struct {
+	Name                  *string               `json:"name"`
+	Description           *string               `json:"description"`
+	PolicyID              *int                  `json:"policy_id"`
+	IsActive              *bool                 `json:"is_active"`
+	BillingCycle          *string               `json:"billing_cycle" binding:"omitempty,oneof=PerStay Weekly Monthly"`
+	HousekeepingFrequency *int                  `json:"housekeeping_frequency_days" binding:"omitempty,min=1,max=30"`
+	Rules                 *RatePlanRulesRequest `json:"rules"`
+}
+
+// RatePlanEligibility is the stay a rate plan is checked against
+type RatePlanEligibility struct {
+	BookingDate time.Time
+	CheckIn     time.Time
+	CheckOut    time.Time
+	NumGuests   int // 0 skips the minimum guests rule
+	IsMember    bool
+}
+
+// RatePlanIneligibleError is returned when a stay does not qualify for a rate plan
+type RatePlanIneligibleError struct {
+	RatePlanID   int      `json:"rate_plan_id"`
+	RatePlanName string   `json:"rate_plan_name"`
+	Reasons      []string `json:"reasons"`
+}
+
+func (e *RatePlanIneligibleError) Error() string {
+	return fmt.Sprintf("rate plan %s is not available for this stay: %s", e.RatePlanName, strings.Join(e.Reasons, "; "))
+}
@@ -26,6 +26,16 @@ type RevenueReport struct {
 	ADR          float64   `json:"adr"` // Average Daily Rate
 }
 
+// AddOnRevenueReport represents add-on revenue for a service date, reported apart from room revenue
+type AddOnRevenueReport struct {
+	Date      time.Time `json:"date"`
+	AddOnID   int       `json:"addon_id"`
+	AddOnName string    `json:"addon_name"`
+	Category  string    `json:"category"`
+	Quantity  int       `json:"quantity"`
+	Revenue   float64   `json:"revenue"`
+}
+
 // VoucherReport represents voucher usage statistics
 type VoucherReport struct {
 	VoucherID      int       `json:"voucher_id"`
@@ -58,6 +68,7 @@ type ReportSummary struct {
 	StartDate      time.Time `json:"start_date"`
 	EndDate        time.Time `json:"end_date"`
 	TotalRevenue   float64   `json:"total_revenue"`
+	AddOnRevenue   float64   `json:"addon_revenue"`
 	TotalBookings  int       `json:"total_bookings"`
 	TotalRoomNights int      `json:"total_room_nights"`
 	AvgOccupancy   float64   `json:"avg_occupancy"`
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Restriction violation codes
+const (
+	RestrictionStopSell = "STOP_SELL"
+	RestrictionCTA      = "CLOSED_TO_ARRIVAL"
+	RestrictionCTD      = "CLOSED_TO_DEPARTURE"
+	RestrictionMinLOS   = "MIN_LOS"
+	RestrictionMaxLOS   = "MAX_LOS"
+)
+
+// RateRestriction represents the stay restrictions of a room type (and optionally a rate plan) on a date.
+// A nil RatePlanID applies to every rate plan.
+type RateRestriction struct {
+	RestrictionID     int       `json:"restriction_id"`
+	RoomTypeID        int       `json:"room_type_id"`
+	RoomTypeName      string    `json:"room_type_name,omitempty"`
+	RatePlanID        *int      `json:"rate_plan_id,omitempty"`
+	RatePlanName      *string   `json:"rate_plan_name,omitempty"`
+	Date              time.Time `json:"date"`
+	MinLOS            *int      `json:"min_los,omitempty"`
+	MaxLOS            *int      `json:"max_los,omitempty"`
+	ClosedToArrival   bool      `json:"closed_to_arrival"`
+	ClosedToDeparture bool      `json:"closed_to_departure"`
+	StopSell          bool      `json:"stop_sell"`
+	Notes             *string   `json:"notes,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// BulkUpdateRestrictionsRequest sets restrictions for a date range across room types.
+// Only the fields that are provided are changed; MinLOS/MaxLOS of 0 clears the limit.
+// Clear removes every restriction in the range instead.
+type BulkUpdateRestrictionsRequest struct {
+	RoomTypeIDs       []int   `json:"room_type_ids" binding:"required,min=1"`
+	RatePlanID        *int    `json:"rate_plan_id,omitempty"`
+	StartDate         string  `json:"start_date" binding:"required"`
+	EndDate           string  `json:"end_date" binding:"required"`
+	DaysOfWeek        []int   `json:"days_of_week,omitempty"` // 0 = Sunday ... 6 = Saturday; empty = every day
+	MinLOS            *int    `json:"min_los,omitempty"`
+	MaxLOS            *int    `json:"max_los,omitempty"`
+	ClosedToArrival   *bool   `json:"closed_to_arrival,omitempty"`
+	ClosedToDeparture *bool   `json:"closed_to_departure,omitempty"`
+	StopSell          *bool   `json:"stop_sell,omitempty"`
+	Notes             *string `json:"notes,omitempty"`
+	Clear             bool    `json:"clear"`
+}
+
+// RestrictionValidationError represents a date that cannot be updated
+type RestrictionValidationError struct {
+	Date       string `json:"date"`
+	RoomTypeID int    `json:"room_type_id"`
+	Message    string `json:"message"`
+}
+
+// RestrictionViolation explains why a stay cannot be sold
+type RestrictionViolation struct {
+	Date    string `json:"date"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RestrictedRoomType is a room type with inventory that was left out of search results by restrictions
+type RestrictedRoomType struct {
+	RoomTypeID   int                    `json:"room_type_id"`
+	RoomTypeName string                 `json:"room_type_name"`
+	Reasons      []RestrictionViolation `json:"reasons"`
+}
+
+// RestrictionError is returned when a stay violates one or more restrictions
+type RestrictionError struct {
+	RoomTypeID int                    `json:"room_type_id"`
+	Violations []RestrictionViolation `json:"violations"`
+}
+
+func (e *RestrictionError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return "stay not available: " + strings.Join(messages, "; ")
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // RoomType represents a type of room
 type RoomType struct {
 	RoomTypeID        int       `json:"room_type_id" db:"room_type_id"`
@@ -7,44 +9,245 @@ type RoomType struct {
 	Description       string    `json:"description" db:"description"`
 	MaxOccupancy      int       `json:"max_occupancy" db:"max_occupancy"`
 	DefaultAllotment  int       `json:"default_allotment" db:"default_allotment"`
+	BaseOccupancy     int       `json:"base_occupancy" db:"base_occupancy"`
+	MaxExtraBeds      int       `json:"max_extra_beds" db:"max_extra_beds"`
+	ExtraBedAllotment int       `json:"extra_bed_allotment" db:"extra_bed_allotment"`
 	BasePrice         *float64  `json:"base_price,omitempty" db:"base_price"`
 	ImageURL          *string   `json:"image_url,omitempty" db:"image_url"`
+	SizeSqm           *float64  `json:"size_sqm,omitempty" db:"size_sqm"`
+	BedType           *string   `json:"bed_type,omitempty" db:"bed_type"`
+	ViewType          *string   `json:"view_type,omitempty" db:"view_type"`
+	IsAccessible      bool      `json:"is_accessible,omitempty" db:"is_accessible"`
+	Popularity        int       `json:"popularity,omitempty"` // Rooms booked over the last PopularityWindowDays days
 	Amenities         []Amenity `json:"amenities,omitempty"`
+	Images            []RoomTypeImage `json:"images,omitempty"` // Gallery in display order; returned by GET /api/rooms/types/:id
 	AvailableRooms    *int      `json:"available_rooms"` // Remove omitempty to always include this field
 	TotalPrice        *float64  `json:"total_price,omitempty"`
 	PricePerNight     *float64  `json:"price_per_night,omitempty"`
 	NightlyPrices     []NightlyPrice `json:"nightly_prices,omitempty"`
+	RatePlans         []RatePlanOffer `json:"rate_plans,omitempty"` // Rate plans the searched stay qualifies for
+}
+
+// RatePlanOffer is the price of a room type under one eligible rate plan
+type RatePlanOffer struct {
+	RatePlanID    int            `json:"rate_plan_id"`
+	Name          string         `json:"name"`
+	Description   *string        `json:"description,omitempty"`
+	PolicyID      int            `json:"policy_id"`
+	MembersOnly   bool           `json:"members_only"`
+	BillingCycle  string         `json:"billing_cycle"`
+	LongStayRate  *LongStayRate  `json:"long_stay_rate,omitempty"` // Applied to every night of the stay
+	TotalPrice    float64        `json:"total_price"`
+	PricePerNight float64        `json:"price_per_night"`
+	NightlyPrices []NightlyPrice `json:"nightly_prices"`
 }
 
 // Room represents a physical room
 type Room struct {
-	RoomID              int       `json:"room_id" db:"room_id"`
-	RoomTypeID          int       `json:"room_type_id" db:"room_type_id"`
-	RoomNumber          string    `json:"room_number" db:"room_number"`
-	OccupancyStatus     string    `json:"occupancy_status" db:"occupancy_status"`
-	HousekeepingStatus  string    `json:"housekeeping_status" db:"housekeeping_status"`
-	RoomTypeName        *string   `json:"room_type_name,omitempty" db:"room_type_name"`
+	RoomID             int      `json:"room_id" db:"room_id"`
+	RoomTypeID         int      `json:"room_type_id" db:"room_type_id"`
+	RoomNumber         string   `json:"room_number" db:"room_number"`
+	Floor              int      `json:"floor,omitempty" db:"floor"`
+	Features           []string `json:"features,omitempty" db:"features"` // e.g. Corner, Connecting
+	Notes              *string  `json:"notes,omitempty" db:"notes"`
+	OccupancyStatus    string   `json:"occupancy_status" db:"occupancy_status"`
+	HousekeepingStatus string   `json:"housekeeping_status" db:"housekeeping_status"`
+	IsActive           bool     `json:"is_active" db:"is_active"` // false once the room is retired
+	ConnectingRoomID   *int     `json:"connecting_room_id,omitempty" db:"connecting_room_id"`
+	RoomTypeName       *string  `json:"room_type_name,omitempty" db:"room_type_name"`
 }
 
 // Amenity represents a room amenity
 type Amenity struct {
-	AmenityID int    `json:"amenity_id" db:"amenity_id"`
-	Name      string `json:"name" db:"name"`
+	AmenityID   int     `json:"amenity_id" db:"amenity_id"`
+	Name        string  `json:"name" db:"name"`
+	Description *string `json:"description,omitempty" db:"description"`
+	Icon        *string `json:"icon,omitempty" db:"icon"`
+	Category    *string `json:"category,omitempty" db:"category"`
+}
+
+// CreateRoomTypeRequest represents the request to create a room type.
+// BaseOccupancy defaults to the smaller of 2 and MaxOccupancy. DefaultAllotment cannot
+// exceed the active rooms of the type, so a new room type starts at 0 until rooms are added.
+type CreateRoomTypeRequest struct {
+	Name              string   `json:"name" binding:"required,max=100"`
+	Description       string   `json:"description"`
+	MaxOccupancy      int      `json:"max_occupancy" binding:"required,min=1"`
+	BaseOccupancy     int      `json:"base_occupancy" binding:"min=0"`
+	MaxExtraBeds      int      `json:"max_extra_beds" binding:"min=0"`
+	ExtraBedAllotment int      `json:"extra_bed_allotment" binding:"min=0"`
+	DefaultAllotment  int      `json:"default_allotment" binding:"min=0"`
+	BasePrice         *float64 `json:"base_price" binding:"omitempty,min=0"`
+	ImageURL          *string  `json:"image_url"`
+	SizeSqm           *float64 `json:"size_sqm" binding:"omitempty,min=0"`
+	BedType           *string  `json:"bed_type"`
+	ViewType          *string  `json:"view_type"`
+	IsAccessible      bool     `json:"is_accessible"`
+}
+
+// UpdateRoomTypeRequest represents the request to update a room type.
+// A new DefaultAllotment is applied to future inventory still at the previous default.
+type UpdateRoomTypeRequest struct {
+	Name              *string  `json:"name" binding:"omitempty,max=100"`
+	Description       *string  `json:"description"`
+	MaxOccupancy      *int     `json:"max_occupancy" binding:"omitempty,min=1"`
+	BaseOccupancy     *int     `json:"base_occupancy" binding:"omitempty,min=1"`
+	MaxExtraBeds      *int     `json:"max_extra_beds" binding:"omitempty,min=0"`
+	ExtraBedAllotment *int     `json:"extra_bed_allotment" binding:"omitempty,min=0"`
+	DefaultAllotment  *int     `json:"default_allotment" binding:"omitempty,min=0"`
+	BasePrice         *float64 `json:"base_price" binding:"omitempty,min=0"`
+	ImageURL          *string  `json:"image_url"`
+	SizeSqm           *float64 `json:"size_sqm" binding:"omitempty,min=0"`
+	BedType           *string  `json:"bed_type"`
+	ViewType          *string  `json:"view_type"`
+	IsAccessible      *bool    `json:"is_accessible"`
+}
+
+// CreateRoomRequest represents the request to add a physical room
+type CreateRoomRequest struct {
+	RoomTypeID int      `json:"room_type_id" binding:"required"`
+	RoomNumber string   `json:"room_number" binding:"required,max=10"`
+	Floor      int      `json:"floor" binding:"required,min=1"`
+	Features   []string `json:"features"`
+	Notes      *string  `json:"notes"`
+}
+
+// UpdateRoomRequest represents the request to update a physical room.
+// Features replace the current features when given. IsActive false retires the room;
+// true brings it back as Dirty so it is cleaned before it is assigned.
+// ConnectingRoomID pairs the room with the room behind its connecting door; 0 removes
+// the pairing.
+type UpdateRoomRequest struct {
+	RoomTypeID       *int     `json:"room_type_id"`
+	RoomNumber       *string  `json:"room_number" binding:"omitempty,max=10"`
+	Floor            *int     `json:"floor" binding:"omitempty,min=1"`
+	Features         []string `json:"features"`
+	Notes            *string  `json:"notes"`
+	IsActive         *bool    `json:"is_active"`
+	ConnectingRoomID *int     `json:"connecting_room_id" binding:"omitempty,min=0"`
+}
+
+// CreateAmenityRequest represents the request to create an amenity
+type CreateAmenityRequest struct {
+	Name        string  `json:"name" binding:"required,max=100"`
+	Description *string `json:"description"`
+	Icon        *string `json:"icon" binding:"omitempty,max=50"`
+	Category    *string `json:"category" binding:"omitempty,max=50"`
+}
+
+// UpdateAmenityRequest represents the request to update an amenity
+type UpdateAmenityRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description"`
+	Icon        *string `json:"icon" binding:"omitempty,max=50"`
+	Category    *string `json:"category" binding:"omitempty,max=50"`
+}
+
+// MaxRoomTypeImageSize limits room type image uploads to 10 MB
+const MaxRoomTypeImageSize = 10 << 20
+
+// RoomTypeImage is an image in the gallery of a room type. The URLs contain a hash of
+// the uploaded file, so they never change and can be cached for good.
+type RoomTypeImage struct {
+	ImageID      int       `json:"image_id" db:"image_id"`
+	RoomTypeID   int       `json:"room_type_id" db:"room_type_id"`
+	StorageKey   string    `json:"-" db:"storage_key"`
+	Caption      *string   `json:"caption,omitempty" db:"caption"`
+	SortOrder    int       `json:"sort_order" db:"sort_order"`
+	Width        int       `json:"width" db:"width"` // Of the uploaded image
+	Height       int       `json:"height" db:"height"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	MediumURL    string    `json:"medium_url"`
+	LargeURL     string    `json:"large_url"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 }
 
+// UpdateRoomTypeImageRequest represents the request to change an image caption
+type UpdateRoomTypeImageRequest struct {
+	Caption *string `json:"caption" binding:"omitempty,max=255"`
+}
+
+// ReorderRoomTypeImagesRequest sets the display order of a room type's gallery; it
+// must list every image of the room type once
+type ReorderRoomTypeImagesRequest struct {
+	ImageIDs []int `json:"image_ids" binding:"required"`
+}
 
+// SetRoomTypeAmenitiesRequest replaces the amenities of a room type
+type SetRoomTypeAmenitiesRequest struct {
+	AmenityIDs []int `json:"amenity_ids"`
+}
 
 // NightlyPrice represents the price for a specific night
 type NightlyPrice struct {
-	Date  string  `json:"date"`
-	Price float64 `json:"price"`
+	Date      string  `json:"date"`
+	Price     float64 `json:"price"`
+	RoomRate  float64 `json:"room_rate"`           // Rate for the base occupancy
+	Surcharge float64 `json:"surcharge,omitempty"` // Extra adult, child and extra bed charges
+	Discount  float64 `json:"discount,omitempty"`  // Taken off the room rate by a long-stay rate
 }
 
-// SearchRoomsRequest represents room search parameters
+// MaxSearchRooms is the most rooms a search can combine for one party
+const MaxSearchRooms = 5
+
+// Room search sorting and price filter bases
+const (
+	SearchSortPrice      = "price"
+	SearchSortSize       = "size"
+	SearchSortPopularity = "popularity"
+
+	PriceBasisNight = "night"
+	PriceBasisTotal = "total"
+)
+
+// PopularityWindowDays is how far back booked rooms count towards popularity
+const PopularityWindowDays = 90
+
+// FlexibleDateRange is how many days a flexible search moves check-in either way
+const FlexibleDateRange = 3
+
+// SearchRoomsRequest represents room search parameters. A party is either Guests
+// (optionally split over Rooms rooms) or the guests of each room in Occupancy.
+// The price range applies to every rate plan offer, per night or for the stay; the price
+// range, price sort and flexible dates apply to single-room results, not combinations.
 type SearchRoomsRequest struct {
-	CheckIn  string `form:"checkIn" binding:"required"`
-	CheckOut string `form:"checkOut" binding:"required"`
-	Guests   int    `form:"guests" binding:"required,min=1"`
+	CheckIn    string   `form:"checkIn" binding:"required"`
+	CheckOut   string   `form:"checkOut" binding:"required"`
+	Guests     int      `form:"guests" binding:"omitempty,min=1"`
+	Rooms      int      `form:"rooms" binding:"omitempty,min=1,max=5"`          // More than one searches room combinations
+	Occupancy  []int    `form:"occupancy" binding:"omitempty,max=5,dive,min=1"` // Guests per room, e.g. occupancy=2&occupancy=4
+	ChildAges  []int    `form:"childAges"`                                      // Ages of the children included in Guests
+	MinPrice   *float64 `form:"minPrice" binding:"omitempty,min=0"`
+	MaxPrice   *float64 `form:"maxPrice" binding:"omitempty,min=0"`
+	PriceBasis string   `form:"priceBasis" binding:"omitempty,oneof=night total"` // Default night
+	Amenities  []int    `form:"amenities"`                                        // Amenity IDs the room type must all have
+	BedType    string   `form:"bedType"`                                          // Matches part of the bed type, e.g. King
+	View       string   `form:"view"`
+	Accessible bool     `form:"accessible"`
+	SortBy     string   `form:"sortBy" binding:"omitempty,oneof=price size popularity"` // Default by name
+	SortOrder  string   `form:"sortOrder" binding:"omitempty,oneof=asc desc"`           // Default asc for price, desc otherwise
+	Flexible   bool     `form:"flexible"`                                               // Adds the cheapest price of check-in ±3 days
+	IsMember   bool     `form:"-"`                                                      // Set from the JWT, not the query string
+}
+
+// RoomSearchFilter narrows the room types of an availability search and orders them.
+// SortBy is size or popularity; prices are sorted once the rooms are priced.
+type RoomSearchFilter struct {
+	AmenityIDs []int
+	BedType    string
+	View       string
+	Accessible bool
+	SortBy     string
+	Descending bool
+}
+
+// FlexibleDatePrice is the cheapest stay of the same length starting on another date
+type FlexibleDatePrice struct {
+	CheckIn       string   `json:"check_in"`
+	CheckOut      string   `json:"check_out"`
+	CheapestPrice *float64 `json:"cheapest_price"` // Nil when nothing matching is available
+	RoomTypeID    *int     `json:"room_type_id,omitempty"`
+	IsSelected    bool     `json:"is_selected"` // The searched dates
 }
 
 // SearchRoomsResponse represents the search results
@@ -54,7 +257,93 @@ type SearchRoomsResponse struct {
 	CheckOut       string     `json:"check_out"`
 	Guests         int        `json:"guests"`
 	TotalNights    int        `json:"total_nights"`
-	AlternativeDates []string `json:"alternative_dates,omitempty"`
+	AlternativeDates []string `json:"alternative_dates,omitempty"` // Check-in dates of the earlier and later alternatives
+	WaitlistAvailable bool    `json:"waitlist_available"` // Sold out: guest can join POST /api/waitlist
+	RestrictedRoomTypes []RestrictedRoomType `json:"restricted_room_types,omitempty"` // Available but closed by stay restrictions
+	Rooms               int                  `json:"rooms,omitempty"`
+	Combinations        []RoomCombination    `json:"combinations,omitempty"` // Multi-room options for the party, cheapest first
+	FlexibleDates       []FlexibleDatePrice  `json:"flexible_dates,omitempty"`
+	Alternatives        []AlternativeStay    `json:"alternatives,omitempty"` // Bookable stays when the search finds nothing
+}
+
+// Alternative stay kinds
+const (
+	AlternativeEarlier   = "Earlier"   // Same length, nearest available dates before
+	AlternativeLater     = "Later"     // Same length, nearest available dates after
+	AlternativeSplitStay = "SplitStay" // Searched dates, moving room type part way
+)
+
+// AlternativeSearchDays is how far either side of the searched dates alternatives are looked for
+const AlternativeSearchDays = 14
+
+// AlternativeRoom is one room of an alternative stay and maps to one detail of a
+// CreateBookingRequest
+type AlternativeRoom struct {
+	RoomTypeID   int     `json:"room_type_id"`
+	RoomTypeName string  `json:"room_type_name"`
+	RatePlanID   int     `json:"rate_plan_id"`
+	RatePlanName string  `json:"rate_plan_name"`
+	CheckIn      string  `json:"check_in"`
+	CheckOut     string  `json:"check_out"`
+	NumGuests    int     `json:"num_guests"`
+	TotalPrice   float64 `json:"total_price"`
+}
+
+// AlternativeStay is a bookable alternative to a search that found nothing
+type AlternativeStay struct {
+	Kind       string            `json:"kind"`
+	CheckIn    string            `json:"check_in"`
+	CheckOut   string            `json:"check_out"`
+	Rooms      []AlternativeRoom `json:"rooms"`
+	TotalPrice float64           `json:"total_price"`
+}
+
+// CombinationRoom is one room of a room combination and maps to one detail of a
+// CreateBookingRequest
+type CombinationRoom struct {
+	RoomTypeID   int     `json:"room_type_id"`
+	RoomTypeName string  `json:"room_type_name"`
+	RatePlanID   int     `json:"rate_plan_id"`
+	RatePlanName string  `json:"rate_plan_name"`
+	NumGuests    int     `json:"num_guests"`
+	ExtraBeds    int     `json:"extra_beds,omitempty"`
+	TotalPrice   float64 `json:"total_price"`
+}
+
+// RoomCombination is a set of rooms, available on every night of the stay, that
+// together fit the party. Rooms of the same room type are held with one hold request
+// for that many rooms.
+type RoomCombination struct {
+	Rooms       []CombinationRoom `json:"rooms"`
+	TotalRooms  int               `json:"total_rooms"`
+	TotalGuests int               `json:"total_guests"`
+	TotalPrice  float64           `json:"total_price"`
+}
+
+// CalendarMaxMonthsAhead is the furthest month the availability calendar shows
+const CalendarMaxMonthsAhead = 12
+
+// AvailabilityCalendarRequest asks for the availability of every day of a month
+type AvailabilityCalendarRequest struct {
+	Month      string `form:"month" binding:"required"` // YYYY-MM
+	RoomTypeID *int   `form:"room_type_id"`             // All room types when empty
+}
+
+// CalendarDay is the availability of one night and its lowest public nightly rate.
+// The rate is the cheapest room type with a room free that night under a public rate
+// plan sellable today; stay length and party rules are applied when searching.
+type CalendarDay struct {
+	Date           string   `json:"date"`
+	Available      bool     `json:"available"`
+	AvailableRooms int      `json:"available_rooms"`
+	LowestRate     *float64 `json:"lowest_rate"` // Nil when no room can be sold
+}
+
+// AvailabilityCalendar is the month view of availability and lowest rates
+type AvailabilityCalendar struct {
+	Month      string        `json:"month"`
+	RoomTypeID *int          `json:"room_type_id,omitempty"`
+	Days       []CalendarDay `json:"days"`
 }
 
 // RoomTypeDetailResponse represents detailed room type information
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Room event types streamed to the room status board
+const (
+	RoomEventStatus    = "room_status" // Occupancy or housekeeping status of a room changed
+	RoomEventArrival   = "arrival"     // A guest checked in to a room
+	RoomEventDeparture = "departure"   // A guest checked out of a room
+)
+
+// RoomEvent is a change to a room, carrying the room's status after the change
+type RoomEvent struct {
+	Type       string     `json:"type"`
+	Room       RoomStatus `json:"room"`
+	OccurredAt time.Time  `json:"occurred_at"`
+}
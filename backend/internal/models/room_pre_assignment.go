@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Floor preferences of a stay
+const (
+	FloorPreferenceHigh = "High"
+	FloorPreferenceLow  = "Low"
+)
+
+// Preferences a pre-assigned room may not meet
+const (
+	UnmetFloor      = "floor"      // Not on the floor the guest asked for
+	UnmetView       = "view"       // Without the view the guest asked for
+	UnmetConnecting = "connecting" // Not connecting to another room of the booking
+	UnmetRoomMove   = "room_move"  // Not free for the whole stay, so the guest has to move
+)
+
+// RoomPreAssignment is the room picked for an arrival ahead of check-in
+type RoomPreAssignment struct {
+	BookingDetailID  int       `json:"booking_detail_id" db:"booking_detail_id"`
+	RoomID           int       `json:"room_id" db:"room_id"`
+	RoomNumber       string    `json:"room_number" db:"room_number"`
+	UnmetPreferences []string  `json:"unmet_preferences" db:"unmet_preferences"`
+	IsManual         bool      `json:"is_manual" db:"is_manual"` // Picked by staff; kept when the job runs again
+	AssignedBy       *int      `json:"assigned_by,omitempty" db:"assigned_by"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PreAssignmentStay is the stay a room is pre-assigned to
+type PreAssignmentStay struct {
+	BookingDetailID int       `db:"booking_detail_id"`
+	RoomTypeID      int       `db:"room_type_id"`
+	CheckInDate     time.Time `db:"check_in_date"`
+	CheckOutDate    time.Time `db:"check_out_date"`
+	BookingStatus   string    `db:"booking_status"`
+	CheckedIn       bool      `db:"checked_in"`
+}
+
+// RoomStayConflicts tells why a room cannot be held for a stay
+type RoomStayConflicts struct {
+	OutOfOrder  bool // Out of order on a night of the stay
+	Occupied    bool // The guest in the room stays past the arrival date
+	PreAssigned bool // Held for another stay on a night of the stay
+}
+
+// SetPreAssignmentRequest represents a request to pick the room of an arrival by hand
+type SetPreAssignmentRequest struct {
+	RoomID int `json:"room_id" binding:"required"`
+}
+
+// SetRoomPreferencesRequest represents the room preferences of a stay; they replace
+// the current preferences
+type SetRoomPreferencesRequest struct {
+	FloorPreference string `json:"floor_preference" binding:"omitempty,oneof=High Low"`
+	ViewPreference  string `json:"view_preference" binding:"max=50"`
+	AccessibleRoom  bool   `json:"accessible_room"`
+	ConnectingRoom  bool   `json:"connecting_room"` // Connecting to another room of the booking
+}
+
+// SetGuestVIPRequest represents a request to set or clear the VIP status of a guest
+type SetGuestVIPRequest struct {
+	IsVIP *bool `json:"is_vip" binding:"required"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// Room upgrade types
+const (
+	UpgradeTypeComplimentary = "Complimentary"
+	UpgradeTypePaid          = "Paid"
+)
+
+// Where a room upgrade came from
+const (
+	UpgradeSourceStaff  = "Staff"  // Given by staff
+	UpgradeSourceUpsell = "Upsell" // Bought by the guest from an upgrade offer
+)
+
+// RoomUpgrade is an upgrade of a booked room to another room type. The booking keeps
+// its rate; a paid upgrade adds ChargePerNight for every upgraded night.
+type RoomUpgrade struct {
+	UpgradeID        int       `json:"upgrade_id" db:"upgrade_id"`
+	BookingDetailID  int       `json:"booking_detail_id" db:"booking_detail_id"`
+	FromRoomTypeID   int       `json:"from_room_type_id" db:"from_room_type_id"`
+	FromRoomTypeName string    `json:"from_room_type_name" db:"from_room_type_name"`
+	ToRoomTypeID     int       `json:"to_room_type_id" db:"to_room_type_id"`
+	ToRoomTypeName   string    `json:"to_room_type_name" db:"to_room_type_name"`
+	UpgradeType      string    `json:"upgrade_type" db:"upgrade_type"`
+	Source           string    `json:"source" db:"source"`
+	ChargePerNight   float64   `json:"charge_per_night" db:"charge_per_night"`
+	StartDate        time.Time `json:"start_date" db:"start_date"` // First upgraded night
+	EndDate          time.Time `json:"end_date" db:"end_date"`     // Check-out date when upgraded
+	Nights           int       `json:"nights"`
+	TotalCharge      float64   `json:"total_charge" db:"total_charge"`
+	Reason           *string   `json:"reason,omitempty" db:"reason"`
+	UpgradedBy       *int      `json:"upgraded_by,omitempty" db:"upgraded_by"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// UpgradeStay is the booked room an upgrade applies to
+type UpgradeStay struct {
+	BookingDetailID int       `db:"booking_detail_id"`
+	BookingID       int       `db:"booking_id"`
+	RoomTypeID      int       `db:"room_type_id"`
+	RoomTypeName    string    `db:"room_type_name"`
+	RatePlanID      int       `db:"rate_plan_id"`
+	CheckInDate     time.Time `db:"check_in_date"`
+	CheckOutDate    time.Time `db:"check_out_date"`
+	NumGuests       int       `db:"num_guests"`
+	StayType        string    `db:"stay_type"`
+	BookingStatus   string    `db:"booking_status"`
+	CheckedIn       bool      `db:"checked_in"`
+}
+
+// UpgradeRoomRequest represents a request from staff to upgrade a booked room
+type UpgradeRoomRequest struct {
+	BookingDetailID int     `json:"booking_detail_id" binding:"required"`
+	RoomTypeID      int     `json:"room_type_id" binding:"required"`
+	UpgradeType     string  `json:"upgrade_type" binding:"required,oneof=Complimentary Paid"`
+	ChargePerNight  float64 `json:"charge_per_night" binding:"min=0"` // Required for a paid upgrade
+	RoomID          *int    `json:"room_id"`                          // Room of the new type an in-house guest moves to
+	Reason          string  `json:"reason" binding:"max=255"`
+}
+
+// UpgradeOffer is an upgrade a guest can buy for a booked room before arrival
+type UpgradeOffer struct {
+	BookingDetailID  int     `json:"booking_detail_id"`
+	FromRoomTypeID   int     `json:"from_room_type_id"`
+	FromRoomTypeName string  `json:"from_room_type_name"`
+	RoomTypeID       int     `json:"room_type_id"`
+	RoomTypeName     string  `json:"room_type_name"`
+	Description      string  `json:"description,omitempty"`
+	ImageURL         *string `json:"image_url,omitempty"`
+	MaxOccupancy     int     `json:"max_occupancy"`
+	AvailableRooms   int     `json:"available_rooms"` // Fewest rooms left on a night of the stay
+	Nights           int     `json:"nights"`
+	PricePerNight    float64 `json:"price_per_night"`
+	TotalPrice       float64 `json:"total_price"`
+}
+
+// AcceptUpgradeOfferRequest represents a guest buying an upgrade offer
+type AcceptUpgradeOfferRequest struct {
+	BookingDetailID int `json:"booking_detail_id" binding:"required"`
+	RoomTypeID      int `json:"room_type_id" binding:"required"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// RoomingListRow represents one guest row parsed from an uploaded rooming list
+type RoomingListRow struct {
+	RowNumber       int        `json:"row"`
+	Ref             string     `json:"ref,omitempty"`
+	Room            int        `json:"room,omitempty"`              // 1-based position of the booking detail
+	BookingDetailID int        `json:"booking_detail_id,omitempty"` // Alternative to Room
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Type            string     `json:"type"`
+	IsPrimary       bool       `json:"is_primary"`
+	Phone           *string    `json:"phone,omitempty"`
+	Email           *string    `json:"email,omitempty"`
+	ArrivalDate     *time.Time `json:"arrival_date,omitempty"`
+	DepartureDate   *time.Time `json:"departure_date,omitempty"`
+}
+
+// RoomingListRowError represents a validation error for a single rooming list row
+type RoomingListRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// RoomingListBlock describes a booking detail (room block) that rooming list rows can map to
+type RoomingListBlock struct {
+	BookingDetailID int       `json:"booking_detail_id"`
+	RoomTypeName    string    `json:"room_type_name"`
+	MaxOccupancy    int       `json:"max_occupancy"`
+	CheckInDate     time.Time `json:"check_in_date"`
+	CheckOutDate    time.Time `json:"check_out_date"`
+}
+
+// RoomingListImportResult represents the outcome of a rooming list upload
+type RoomingListImportResult struct {
+	BookingID int                   `json:"booking_id"`
+	TotalRows int                   `json:"total_rows"`
+	Created   int                   `json:"created"`
+	Updated   int                   `json:"updated"`
+	Applied   bool                  `json:"applied"`
+	Errors    []RoomingListRowError `json:"errors"`
+	// Guests imported by an earlier upload that the list leaves out; they stay on the booking
+	MissingGuests []BookingGuest `json:"missing_guests"`
+}
@@ -0,0 +1,131 @@
+package models
+
+import "time"
+
+// Standard arrival and departure hours of an overnight stay
+const (
+	StandardCheckInHour  = 14
+	StandardCheckOutHour = 12
+)
+
+// Stay-time request types
+const (
+	StayTimeEarlyCheckIn = "EarlyCheckIn"
+	StayTimeLateCheckOut = "LateCheckOut"
+)
+
+// Stay-time request statuses
+const (
+	StayTimeStatusPending   = "Pending"
+	StayTimeStatusApproved  = "Approved"
+	StayTimeStatusDeclined  = "Declined"
+	StayTimeStatusCancelled = "Cancelled"
+)
+
+// Who made a stay-time request
+const (
+	StayTimeSourceGuest = "Guest" // Waits for staff to approve it
+	StayTimeSourceStaff = "Staff" // Approved when made
+)
+
+// StayTimeFee is a fee tier for early check-in (arriving from TierHour) or late
+// check-out (leaving until TierHour)
+type StayTimeFee struct {
+	FeeID       int       `json:"fee_id" db:"fee_id"`
+	RequestType string    `json:"request_type" db:"request_type"`
+	TierHour    int       `json:"tier_hour" db:"tier_hour"`
+	Fee         float64   `json:"fee" db:"fee"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpsertStayTimeFeeRequest creates or updates the fee of a tier
+type UpsertStayTimeFeeRequest struct {
+	RequestType string  `json:"request_type" binding:"required,oneof=EarlyCheckIn LateCheckOut"`
+	TierHour    int     `json:"tier_hour" binding:"min=0,max=23"`
+	Fee         float64 `json:"fee" binding:"min=0"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// StayTimeRequest is a request to check in before or check out after the standard time
+type StayTimeRequest struct {
+	RequestID       int        `json:"request_id" db:"request_id"`
+	BookingDetailID int        `json:"booking_detail_id" db:"booking_detail_id"`
+	BookingID       int        `json:"booking_id" db:"booking_id"`
+	GuestName       string     `json:"guest_name,omitempty" db:"guest_name"`
+	RoomTypeName    string     `json:"room_type_name" db:"room_type_name"`
+	RoomNumber      *string    `json:"room_number,omitempty" db:"room_number"` // Assigned or pre-assigned room
+	RequestType     string     `json:"request_type" db:"request_type"`
+	RequestedAt     time.Time  `json:"requested_at" db:"requested_at"` // On the check-in or check-out date
+	Fee             float64    `json:"fee" db:"fee"`
+	Status          string     `json:"status" db:"status"`
+	Source          string     `json:"source" db:"source"`
+	Note            *string    `json:"note,omitempty" db:"note"`
+	DeclineReason   *string    `json:"decline_reason,omitempty" db:"decline_reason"`
+	DecidedBy       *int       `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty" db:"decided_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateStayTimeRequest represents a guest asking to check in early or check out late
+type CreateStayTimeRequest struct {
+	BookingDetailID int    `json:"booking_detail_id" binding:"required"`
+	RequestType     string `json:"request_type" binding:"required,oneof=EarlyCheckIn LateCheckOut"`
+	Time            string `json:"time" binding:"required"` // HH:MM
+	Note            string `json:"note" binding:"max=255"`
+}
+
+// StaffStayTimeRequest represents staff granting an early check-in or late check-out.
+// Fee overrides the tier fee, for example to waive it.
+type StaffStayTimeRequest struct {
+	CreateStayTimeRequest
+	Fee *float64 `json:"fee" binding:"omitempty,min=0"`
+}
+
+// ApproveStayTimeRequest represents staff approving a guest's request. Fee overrides
+// the quoted fee.
+type ApproveStayTimeRequest struct {
+	Fee *float64 `json:"fee" binding:"omitempty,min=0"`
+}
+
+// DeclineStayTimeRequest represents staff declining a guest's request
+type DeclineStayTimeRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// StayTimeStay is the booked room a stay-time request applies to
+type StayTimeStay struct {
+	BookingDetailID int       `db:"booking_detail_id"`
+	BookingID       int       `db:"booking_id"`
+	GuestID         *int      `db:"guest_id"`
+	RoomTypeID      int       `db:"room_type_id"`
+	CheckInDate     time.Time `db:"check_in_date"`
+	CheckOutDate    time.Time `db:"check_out_date"`
+	StayType        string    `db:"stay_type"`
+	BookingStatus   string    `db:"booking_status"`
+	CheckedIn       bool      `db:"checked_in"`
+}
+
+// StayTimeDay is what the rooms of a room type are doing on a day, in minutes after
+// midnight, leaving out the stay a request is checked for
+type StayTimeDay struct {
+	Rooms           int   // Rooms in service on the day
+	Stayovers       int   // Stays holding a room all day
+	Departures      []int // When each departing stay leaves
+	DayUseEnds      []int // When each day-use slot on a room of the type ends
+	Arrivals        []int // When each arriving stay needs its room (0 once checked in)
+	CleaningMinutes int   // Time to turn a room around after check-out
+}
+
+// StayTimeRoom is the room a stay is assigned or pre-assigned to, in minutes after
+// midnight on the day of the request
+type StayTimeRoom struct {
+	RoomID            int
+	RoomNumber        string
+	PreviousDeparture int  // When the stay before leaves the room (0 if free, 1440 if it stays on)
+	NextArrival       int  // When the next stay pre-assigned to the room arrives (1440 if none)
+	DayUseConflict    bool // A day-use slot holds the room in the requested window
+	OutOfOrder        bool // The room is out of order on the day
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Waitlist entry statuses
+const (
+	WaitlistStatusWaiting   = "Waiting"
+	WaitlistStatusOffered   = "Offered"
+	WaitlistStatusClaimed   = "Claimed"
+	WaitlistStatusExpired   = "Expired"
+	WaitlistStatusCancelled = "Cancelled"
+)
+
+// WaitlistEntry represents a guest waiting for a sold-out room type and date range
+type WaitlistEntry struct {
+	WaitlistID     int        `json:"waitlist_id" db:"waitlist_id"`
+	GuestID        *int       `json:"guest_id,omitempty" db:"guest_id"`
+	FirstName      string     `json:"first_name" db:"first_name"`
+	LastName       string     `json:"last_name" db:"last_name"`
+	Email          string     `json:"email" db:"email"`
+	Phone          *string    `json:"phone,omitempty" db:"phone"`
+	RoomTypeID     int        `json:"room_type_id" db:"room_type_id"`
+	RoomTypeName   string     `json:"room_type_name,omitempty" db:"room_type_name"`
+	CheckInDate    time.Time  `json:"check_in_date" db:"check_in_date"`
+	CheckOutDate   time.Time  `json:"check_out_date" db:"check_out_date"`
+	NumGuests      int        `json:"num_guests" db:"num_guests"`
+	Status         string     `json:"status" db:"status"`
+	SessionID      *string    `json:"session_id,omitempty" db:"session_id"`
+	OfferedAt      *time.Time `json:"offered_at,omitempty" db:"offered_at"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty" db:"offer_expires_at"`
+	OfferCount     int        `json:"offer_count" db:"offer_count"`
+	BookingID      *int       `json:"booking_id,omitempty" db:"booking_id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// JoinWaitlistRequest represents a request to join the waitlist
+type JoinWaitlistRequest struct {
+	RoomTypeID int     `json:"room_type_id" binding:"required"`
+	CheckIn    string  `json:"check_in" binding:"required"`
+	CheckOut   string  `json:"check_out" binding:"required"`
+	NumGuests  int     `json:"num_guests" binding:"required,min=1"`
+	FirstName  string  `json:"first_name" binding:"required"`
+	LastName   string  `json:"last_name" binding:"required"`
+	Email      string  `json:"email" binding:"required,email"`
+	Phone      *string `json:"phone,omitempty"`
+}
+
+// WaitlistMatchEvent represents one change made by a waitlist matching run
+type WaitlistMatchEvent struct {
+	Event          string     `json:"event"` // Offered, Expired or Lapsed
+	WaitlistID     int        `json:"waitlist_id"`
+	SessionID      *string    `json:"session_id,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddOnRepository handles add-on catalog data access
+type AddOnRepository struct {
+	db *database.DB
+}
+
+// NewAddOnRepository creates a new add-on repository
+func NewAddOnRepository(db *database.DB) *AddOnRepository {
+	return &AddOnRepository{db: db}
+}
+
+// addOnColumns is the column list read by scanAddOn
+const addOnColumns = `addon_id, name, description, category, pricing_unit, price, daily_limit, is_active, created_at, updated_at`
+
+func scanAddOn(row pgx.Row, addon *models.AddOn) error {
+	return row.Scan(
+		&addon.AddOnID,
+		&addon.Name,
+		&addon.Description,
+		&addon.Category,
+		&addon.PricingUnit,
+		&addon.Price,
+		&addon.DailyLimit,
+		&addon.IsActive,
+		&addon.CreatedAt,
+		&addon.UpdatedAt,
+	)
+}
+
+func collectAddOns(rows pgx.Rows) ([]models.AddOn, error) {
+	defer rows.Close()
+
+	var addons []models.AddOn
+	for rows.Next() {
+		var addon models.AddOn
+		if err := scanAddOn(rows, &addon); err != nil {
+			return nil, fmt.Errorf("failed to scan add-on: %w", err)
+		}
+		addons = append(addons, addon)
+	}
+
+	return addons, rows.Err()
+}
+
+// GetAllAddOns retrieves the add-on catalog, optionally only active add-ons
+func (r *AddOnRepository) GetAllAddOns(ctx context.Context, activeOnly bool) ([]models.AddOn, error) {
+	query := `SELECT ` + addOnColumns + ` FROM addons WHERE $1 = FALSE OR is_active = TRUE ORDER BY category, name`
+
+	rows, err := r.db.Pool.Query(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query add-ons: %w", err)
+	}
+
+	return collectAddOns(rows)
+}
+
+// GetAddOnByID retrieves an add-on by ID
+func (r *AddOnRepository) GetAddOnByID(ctx context.Context, id int) (*models.AddOn, error) {
+	query := `SELECT ` + addOnColumns + ` FROM addons WHERE addon_id = $1`
+
+	var addon models.AddOn
+	if err := scanAddOn(r.db.Pool.QueryRow(ctx, query, id), &addon); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get add-on: %w", err)
+	}
+
+	return &addon, nil
+}
+
+// CreateAddOn creates a new add-on
+func (r *AddOnRepository) CreateAddOn(ctx context.Context, addon *models.AddOn) (*models.AddOn, error) {
+	query := `
+		INSERT INTO addons (name, description, category, pricing_unit, price, daily_limit, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + addOnColumns
+
+	var created models.AddOn
+	err := scanAddOn(r.db.Pool.QueryRow(ctx, query,
+		addon.Name,
+		addon.Description,
+		addon.Category,
+		addon.PricingUnit,
+		addon.Price,
+		addon.DailyLimit,
+		addon.IsActive,
+	), &created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create add-on: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateAddOn updates an add-on
+func (r *AddOnRepository) UpdateAddOn(ctx context.Context, addon *models.AddOn) (*models.AddOn, error) {
+	query := `
+		UPDATE addons
+		SET name = $2,
+			description = $3,
+			category = $4,
+			pricing_unit = $5,
+			price = $6,
+			daily_limit = $7,
+			is_active = $8,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE addon_id = $1
+		RETURNING ` + addOnColumns
+
+	var updated models.AddOn
+	err := scanAddOn(r.db.Pool.QueryRow(ctx, query,
+		addon.AddOnID,
+		addon.Name,
+		addon.Description,
+		addon.Category,
+		addon.PricingUnit,
+		addon.Price,
+		addon.DailyLimit,
+		addon.IsActive,
+	), &updated)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update add-on: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// GetRatePlanInclusions retrieves the add-ons included in a rate plan
+func (r *AddOnRepository) GetRatePlanInclusions(ctx context.Context, ratePlanID int) ([]models.AddOn, error) {
+	return queryRatePlanInclusions(ctx, r.db, ratePlanID)
+}
+
+// queryRatePlanInclusions is shared with BookingRepository, which prices inclusions at booking time
+func queryRatePlanInclusions(ctx context.Context, db *database.DB, ratePlanID int) ([]models.AddOn, error) {
+	query := `
+		SELECT ` + addOnColumns + `
+		FROM addons
+		WHERE addon_id IN (SELECT addon_id FROM rate_plan_addons WHERE rate_plan_id = $1)
+		ORDER BY category, name
+	`
+
+	rows, err := db.Pool.Query(ctx, query, ratePlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rate plan inclusions: %w", err)
+	}
+
+	return collectAddOns(rows)
+}
+
+// SetRatePlanInclusions replaces the add-ons included in a rate plan
+func (r *AddOnRepository) SetRatePlanInclusions(ctx context.Context, ratePlanID int, addOnIDs []int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM rate_plan_addons WHERE rate_plan_id = $1`, ratePlanID); err != nil {
+		return fmt.Errorf("failed to clear rate plan inclusions: %w", err)
+	}
+
+	if len(addOnIDs) > 0 {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO rate_plan_addons (rate_plan_id, addon_id)
+			SELECT $1, unnest($2::int[])
+			ON CONFLICT DO NOTHING
+		`, ratePlanID, addOnIDs)
+		if err != nil {
+			return fmt.Errorf("failed to set rate plan inclusions: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
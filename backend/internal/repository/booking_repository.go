@@ -24,9 +24,14 @@ func NewBookingRepository(db *database.DB) *BookingRepository {
 // CreateBookingHold calls the PostgreSQL function to create a booking hold
 func (r *BookingRepository) CreateBookingHold(ctx context.Context, req *models.CreateBookingHoldRequest) (*models.CreateBookingHoldResponse, error) {
 	query := `
-		SELECT success, message, expiry_time FROM create_booking_hold($1, $2, $3, $4::date, $5::date)
+		SELECT success, message, expiry_time FROM create_booking_hold($1, $2, $3, $4::date, $5::date, $6)
 	`
 
+	rooms := req.Rooms
+	if rooms == 0 {
+		rooms = 1
+	}
+
 	var success bool
 	var message string
 	var expiryTime *time.Time
@@ -37,6 +42,7 @@ func (r *BookingRepository) CreateBookingHold(ctx context.Context, req *models.C
 		req.RoomTypeID,
 		req.CheckIn,
 		req.CheckOut,
+		rooms,
 	).Scan(&success, &message, &expiryTime)
 
 	if err != nil {
@@ -55,14 +61,28 @@ func (r *BookingRepository) CreateBookingHold(ctx context.Context, req *models.C
 	return response, nil
 }
 
-// CreateBooking creates a new booking with details
-func (r *BookingRepository) CreateBooking(ctx context.Context, guestID int, voucherID *int, totalAmount float64, policyName, policyDescription string) (*models.Booking, error) {
-	query := `
+// Inserts shared by the single-statement methods and CreateDayUseBooking's transaction
+const (
+	insertBookingQuery = `
 		INSERT INTO bookings (guest_id, voucher_id, total_amount, status, policy_name, policy_description)
 		VALUES ($1, $2, $3, 'PendingPayment', $4, $5)
 		RETURNING booking_id, guest_id, voucher_id, total_amount, status, created_at, updated_at, policy_name, policy_description
 	`
+	insertBookingDetailQuery = `
+		INSERT INTO booking_details (booking_id, room_type_id, rate_plan_id, check_in_date, check_out_date, num_guests,
+			num_adults, child_ages, extra_beds, stay_type)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE(NULLIF($10, ''), 'Overnight'))
+		RETURNING booking_detail_id
+	`
+	insertBookingGuestQuery = `
+		INSERT INTO booking_guests (booking_detail_id, first_name, last_name, phone, email, type, is_primary)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING booking_guest_id
+	`
+)
 
+// CreateBooking creates a new booking with details
+func (r *BookingRepository) CreateBooking(ctx context.Context, guestID int, voucherID *int, totalAmount float64, policyName, policyDescription string) (*models.Booking, error) {
 	// Convert guestID to *int for NULL support
 	var guestIDPtr *int
 	if guestID > 0 {
@@ -70,7 +90,7 @@ func (r *BookingRepository) CreateBooking(ctx context.Context, guestID int, vouc
 	}
 
 	var booking models.Booking
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.Pool.QueryRow(ctx, insertBookingQuery,
 		guestIDPtr,
 		voucherID,
 		totalAmount,
@@ -97,31 +117,23 @@ func (r *BookingRepository) CreateBooking(ctx context.Context, guestID int, vouc
 
 // CreateBookingDetail creates a booking detail
 func (r *BookingRepository) CreateBookingDetail(ctx context.Context, detail *models.BookingDetail) error {
-	query := `
-		INSERT INTO booking_details (booking_id, room_type_id, rate_plan_id, check_in_date, check_out_date, num_guests)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING booking_detail_id
-	`
-
-	return r.db.Pool.QueryRow(ctx, query,
+	return r.db.Pool.QueryRow(ctx, insertBookingDetailQuery,
 		detail.BookingID,
 		detail.RoomTypeID,
 		detail.RatePlanID,
 		detail.CheckInDate,
 		detail.CheckOutDate,
 		detail.NumGuests,
+		detail.NumAdults,
+		detail.ChildAges,
+		detail.ExtraBeds,
+		detail.StayType,
 	).Scan(&detail.BookingDetailID)
 }
 
 // CreateBookingGuest creates a booking guest
 func (r *BookingRepository) CreateBookingGuest(ctx context.Context, guest *models.BookingGuest) error {
-	query := `
-		INSERT INTO booking_guests (booking_detail_id, first_name, last_name, phone, email, type, is_primary)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING booking_guest_id
-	`
-
-	return r.db.Pool.QueryRow(ctx, query,
+	return r.db.Pool.QueryRow(ctx, insertBookingGuestQuery,
 		guest.BookingDetailID,
 		guest.FirstName,
 		guest.LastName,
@@ -132,6 +144,329 @@ func (r *BookingRepository) CreateBookingGuest(ctx context.Context, guest *model
 	).Scan(&guest.BookingGuestID)
 }
 
+// CreateBookingWithDetails creates a booking with its details, their add-ons and guests in one
+// transaction; addons and guests are indexed like details. Limited add-ons are locked while their
+// lines are added so that concurrent bookings cannot both take the last units of a day, and the
+// booking is not created when a daily limit would be exceeded.
+func (r *BookingRepository) CreateBookingWithDetails(ctx context.Context, booking *models.Booking, details []*models.BookingDetail, addons [][]*models.BookingAddOn, guests [][]*models.BookingGuest) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var addOnIDs []int
+	for _, lines := range addons {
+		for _, line := range lines {
+			addOnIDs = append(addOnIDs, line.AddOnID)
+		}
+	}
+	if len(addOnIDs) > 0 {
+		_, err = tx.Exec(ctx, `
+			SELECT addon_id FROM addons
+			WHERE addon_id = ANY($1) AND daily_limit IS NOT NULL
+			ORDER BY addon_id
+			FOR UPDATE
+		`, addOnIDs)
+		if err != nil {
+			return fmt.Errorf("failed to lock add-ons: %w", err)
+		}
+	}
+
+	err = tx.QueryRow(ctx, insertBookingQuery,
+		booking.GuestID,
+		booking.VoucherID,
+		booking.TotalAmount,
+		booking.PolicyName,
+		booking.PolicyDescription,
+	).Scan(
+		&booking.BookingID,
+		&booking.GuestID,
+		&booking.VoucherID,
+		&booking.TotalAmount,
+		&booking.Status,
+		&booking.CreatedAt,
+		&booking.UpdatedAt,
+		&booking.PolicyName,
+		&booking.PolicyDescription,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	detailIDs := make([]int, 0, len(details))
+	for i, detail := range details {
+		detail.BookingID = booking.BookingID
+		err = tx.QueryRow(ctx, insertBookingDetailQuery,
+			detail.BookingID,
+			detail.RoomTypeID,
+			detail.RatePlanID,
+			detail.CheckInDate,
+			detail.CheckOutDate,
+			detail.NumGuests,
+			detail.NumAdults,
+			detail.ChildAges,
+			detail.ExtraBeds,
+			detail.StayType,
+		).Scan(&detail.BookingDetailID)
+		if err != nil {
+			return fmt.Errorf("failed to create booking detail: %w", err)
+		}
+		detailIDs = append(detailIDs, detail.BookingDetailID)
+
+		for _, addon := range addons[i] {
+			addon.BookingDetailID = detail.BookingDetailID
+			err = tx.QueryRow(ctx, `
+				INSERT INTO booking_addons (booking_detail_id, addon_id, quantity, unit_price, total_price,
+					service_start_date, service_end_date, is_included)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING booking_addon_id
+			`,
+				addon.BookingDetailID,
+				addon.AddOnID,
+				addon.Quantity,
+				addon.UnitPrice,
+				addon.TotalPrice,
+				addon.ServiceStartDate,
+				addon.ServiceEndDate,
+				addon.IsIncluded,
+			).Scan(&addon.BookingAddOnID)
+			if err != nil {
+				return fmt.Errorf("failed to create booking add-on: %w", err)
+			}
+		}
+
+		for _, guest := range guests[i] {
+			guest.BookingDetailID = detail.BookingDetailID
+			err = tx.QueryRow(ctx, insertBookingGuestQuery,
+				guest.BookingDetailID,
+				guest.FirstName,
+				guest.LastName,
+				guest.Phone,
+				guest.Email,
+				guest.Type,
+				guest.IsPrimary,
+			).Scan(&guest.BookingGuestID)
+			if err != nil {
+				return fmt.Errorf("failed to create booking guest: %w", err)
+			}
+		}
+	}
+
+	// With the add-ons locked, the units booked now include every other committed booking
+	if len(addOnIDs) > 0 {
+		var name string
+		var date time.Time
+		err = tx.QueryRow(ctx, `
+			SELECT a.name, d::date
+			FROM booking_addons ba
+			JOIN addons a ON ba.addon_id = a.addon_id
+			CROSS JOIN generate_series(ba.service_start_date, ba.service_end_date, interval '1 day') AS d
+			WHERE ba.booking_detail_id = ANY($1)
+			  AND a.daily_limit IS NOT NULL
+			  AND addon_quantity_booked(a.addon_id, d::date) > a.daily_limit
+			ORDER BY d, a.addon_id
+			LIMIT 1
+		`, detailIDs).Scan(&name, &date)
+		if err == nil {
+			return fmt.Errorf("add-on %s is sold out on %s", name, date.Format("2006-01-02"))
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to check add-on limits: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// CreateDayUseBooking creates a day-use booking, its detail and guests and blocks a physical
+// room for the slot, all in one transaction. Returns models.ErrDayUseSoldOut when no room is free.
+func (r *BookingRepository) CreateDayUseBooking(ctx context.Context, booking *models.Booking, detail *models.BookingDetail, guests []models.BookingGuest, start, end time.Time) (string, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, insertBookingQuery,
+		booking.GuestID,
+		booking.VoucherID,
+		booking.TotalAmount,
+		booking.PolicyName,
+		booking.PolicyDescription,
+	).Scan(
+		&booking.BookingID,
+		&booking.GuestID,
+		&booking.VoucherID,
+		&booking.TotalAmount,
+		&booking.Status,
+		&booking.CreatedAt,
+		&booking.UpdatedAt,
+		&booking.PolicyName,
+		&booking.PolicyDescription,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	detail.BookingID = booking.BookingID
+	err = tx.QueryRow(ctx, insertBookingDetailQuery,
+		detail.BookingID,
+		detail.RoomTypeID,
+		detail.RatePlanID,
+		detail.CheckInDate,
+		detail.CheckOutDate,
+		detail.NumGuests,
+		detail.NumAdults,
+		detail.ChildAges,
+		detail.ExtraBeds,
+		detail.StayType,
+	).Scan(&detail.BookingDetailID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create booking detail: %w", err)
+	}
+
+	for i := range guests {
+		guest := &guests[i]
+		guest.BookingDetailID = detail.BookingDetailID
+		err := tx.QueryRow(ctx, insertBookingGuestQuery,
+			guest.BookingDetailID,
+			guest.FirstName,
+			guest.LastName,
+			guest.Phone,
+			guest.Email,
+			guest.Type,
+			guest.IsPrimary,
+		).Scan(&guest.BookingGuestID)
+		if err != nil {
+			return "", fmt.Errorf("failed to create booking guest: %w", err)
+		}
+	}
+
+	var roomID *int
+	err = tx.QueryRow(ctx, `SELECT block_day_use_room($1, $2, $3, $4)`,
+		detail.BookingDetailID, detail.RoomTypeID, start, end).Scan(&roomID)
+	if err != nil {
+		return "", fmt.Errorf("failed to block day-use room: %w", err)
+	}
+	if roomID == nil {
+		return "", models.ErrDayUseSoldOut
+	}
+
+	var roomNumber string
+	if err := tx.QueryRow(ctx, `SELECT room_number FROM rooms WHERE room_id = $1`, *roomID).Scan(&roomNumber); err != nil {
+		return "", fmt.Errorf("failed to get room number: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return roomNumber, nil
+}
+
+// ApplyRoomingList inserts and updates booking guests from a rooming list in a single transaction.
+// When a guest is marked primary, other guests in the same booking detail lose the primary flag.
+func (r *BookingRepository) ApplyRoomingList(ctx context.Context, creates, updates []models.BookingGuest) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for i := range updates {
+		guest := &updates[i]
+		_, err := tx.Exec(ctx, `
+			UPDATE booking_guests
+			SET booking_detail_id = $2, first_name = $3, last_name = $4, phone = $5, email = $6,
+			    type = $7, is_primary = $8, rooming_list_ref = $9, arrival_date = $10, departure_date = $11
+			WHERE booking_guest_id = $1
+		`,
+			guest.BookingGuestID,
+			guest.BookingDetailID,
+			guest.FirstName,
+			guest.LastName,
+			guest.Phone,
+			guest.Email,
+			guest.Type,
+			guest.IsPrimary,
+			guest.RoomingListRef,
+			guest.ArrivalDate,
+			guest.DepartureDate,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update booking guest %d: %w", guest.BookingGuestID, err)
+		}
+	}
+
+	for i := range creates {
+		guest := &creates[i]
+		err := tx.QueryRow(ctx, `
+			INSERT INTO booking_guests (booking_detail_id, first_name, last_name, phone, email, type, is_primary,
+			                            rooming_list_ref, arrival_date, departure_date)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING booking_guest_id
+		`,
+			guest.BookingDetailID,
+			guest.FirstName,
+			guest.LastName,
+			guest.Phone,
+			guest.Email,
+			guest.Type,
+			guest.IsPrimary,
+			guest.RoomingListRef,
+			guest.ArrivalDate,
+			guest.DepartureDate,
+		).Scan(&guest.BookingGuestID)
+		if err != nil {
+			return fmt.Errorf("failed to create booking guest: %w", err)
+		}
+	}
+
+	for _, guests := range [][]models.BookingGuest{updates, creates} {
+		for _, guest := range guests {
+			if !guest.IsPrimary {
+				continue
+			}
+			_, err := tx.Exec(ctx, `
+				UPDATE booking_guests
+				SET is_primary = false
+				WHERE booking_detail_id = $1 AND booking_guest_id <> $2 AND is_primary = true
+			`, guest.BookingDetailID, guest.BookingGuestID)
+			if err != nil {
+				return fmt.Errorf("failed to update primary guest: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit rooming list: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimWaitlistOffer marks an unexpired waitlist offer as claimed by the booking created with its hold session.
+// Only the waitlisted guest claims the offer: the signed-in guest who joined, or the primary guest's email for
+// entries made without an account.
+func (r *BookingRepository) ClaimWaitlistOffer(ctx context.Context, sessionID string, bookingID, guestID int, email string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'Claimed', booking_id = $2
+		WHERE session_id = $1
+		  AND status = 'Offered'
+		  AND offer_expires_at > NOW()
+		  AND (guest_id = $3 OR (guest_id IS NULL AND LOWER(email) = LOWER($4)))
+	`, sessionID, bookingID, guestID, email)
+	if err != nil {
+		return fmt.Errorf("failed to claim waitlist offer: %w", err)
+	}
+	return nil
+}
+
 // CreateBookingNightlyLog creates a nightly log entry
 func (r *BookingRepository) CreateBookingNightlyLog(ctx context.Context, log *models.BookingNightlyLog) error {
 	query := `
@@ -263,9 +598,13 @@ func (r *BookingRepository) GetBookingByID(ctx context.Context, bookingID int) (
 func (r *BookingRepository) getBookingDetails(ctx context.Context, bookingID int) ([]models.BookingDetailWithGuests, error) {
 	detailsQuery := `
 		SELECT bd.booking_detail_id, bd.booking_id, bd.room_type_id, bd.rate_plan_id,
-		       bd.check_in_date, bd.check_out_date, bd.num_guests, rt.name as room_type_name
+		       bd.check_in_date, bd.check_out_date, bd.num_guests, rt.name as room_type_name,
+		       bd.stay_type, dub.start_at, dub.end_at,
+		       rp.billing_cycle, rp.housekeeping_frequency_days
 		FROM booking_details bd
 		JOIN room_types rt ON bd.room_type_id = rt.room_type_id
+		JOIN rate_plans rp ON bd.rate_plan_id = rp.rate_plan_id
+		LEFT JOIN day_use_blocks dub ON bd.booking_detail_id = dub.booking_detail_id
 		WHERE bd.booking_id = $1
 		ORDER BY bd.booking_detail_id
 	`
@@ -288,6 +627,11 @@ func (r *BookingRepository) getBookingDetails(ctx context.Context, bookingID int
 			&detail.CheckOutDate,
 			&detail.NumGuests,
 			&detail.RoomTypeName,
+			&detail.StayType,
+			&detail.DayUseStart,
+			&detail.DayUseEnd,
+			&detail.BillingCycle,
+			&detail.HousekeepingFrequency,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan booking detail: %w", err)
@@ -307,6 +651,34 @@ func (r *BookingRepository) getBookingDetails(ctx context.Context, bookingID int
 		}
 		detail.NightlyPrices = nightlyPrices
 
+		// Get add-ons
+		addons, err := r.getBookingAddOns(ctx, detail.BookingDetailID)
+		if err != nil {
+			return nil, err
+		}
+		detail.AddOns = addons
+
+		// Get room upgrades
+		upgrades, err := r.getBookingUpgrades(ctx, detail.BookingDetailID)
+		if err != nil {
+			return nil, err
+		}
+		detail.Upgrades = upgrades
+
+		// Get early check-in and late check-out requests
+		stayTimeRequests, err := r.getStayTimeRequests(ctx, detail.BookingDetailID)
+		if err != nil {
+			return nil, err
+		}
+		detail.StayTimeRequests = stayTimeRequests
+
+		// Get walks to partner hotels
+		walks, err := r.getBookingWalks(ctx, detail.BookingDetailID)
+		if err != nil {
+			return nil, err
+		}
+		detail.Walks = walks
+
 		// Get room number if assigned
 		roomNumber, err := r.getAssignedRoomNumber(ctx, detail.BookingDetailID)
 		if err == nil && roomNumber != "" {
@@ -322,7 +694,8 @@ func (r *BookingRepository) getBookingDetails(ctx context.Context, bookingID int
 // getBookingGuests retrieves guests for a booking detail
 func (r *BookingRepository) getBookingGuests(ctx context.Context, bookingDetailID int) ([]models.BookingGuest, error) {
 	query := `
-		SELECT booking_guest_id, booking_detail_id, first_name, last_name, phone, type, is_primary
+		SELECT booking_guest_id, booking_detail_id, first_name, last_name, phone, email, type, is_primary,
+		       rooming_list_ref, arrival_date, departure_date
 		FROM booking_guests
 		WHERE booking_detail_id = $1
 		ORDER BY is_primary DESC, booking_guest_id
@@ -343,8 +716,12 @@ func (r *BookingRepository) getBookingGuests(ctx context.Context, bookingDetailI
 			&guest.FirstName,
 			&guest.LastName,
 			&guest.Phone,
+			&guest.Email,
 			&guest.Type,
 			&guest.IsPrimary,
+			&guest.RoomingListRef,
+			&guest.ArrivalDate,
+			&guest.DepartureDate,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan booking guest: %w", err)
@@ -565,19 +942,10 @@ func (r *BookingRepository) GetCancellationPolicy(ctx context.Context, policyID
 
 // GetRatePlan retrieves a rate plan by ID
 func (r *BookingRepository) GetRatePlan(ctx context.Context, ratePlanID int) (*models.RatePlan, error) {
-	query := `
-		SELECT rate_plan_id, name, description, policy_id
-		FROM rate_plans
-		WHERE rate_plan_id = $1
-	`
+	query := `SELECT ` + ratePlanColumns + ` FROM rate_plans WHERE rate_plan_id = $1`
 
 	var ratePlan models.RatePlan
-	err := r.db.Pool.QueryRow(ctx, query, ratePlanID).Scan(
-		&ratePlan.RatePlanID,
-		&ratePlan.Name,
-		&ratePlan.Description,
-		&ratePlan.PolicyID,
-	)
+	err := scanRatePlan(r.db.Pool.QueryRow(ctx, query, ratePlanID), &ratePlan)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -589,16 +957,130 @@ func (r *BookingRepository) GetRatePlan(ctx context.Context, ratePlanID int) (*m
 	return &ratePlan, nil
 }
 
-// CheckIn calls the PostgreSQL function to check in a guest
-func (r *BookingRepository) CheckIn(ctx context.Context, bookingDetailID, roomID int) (*models.CheckInResponse, error) {
+// GetAddOn retrieves an add-on by ID
+func (r *BookingRepository) GetAddOn(ctx context.Context, addOnID int) (*models.AddOn, error) {
+	query := `SELECT ` + addOnColumns + ` FROM addons WHERE addon_id = $1`
+
+	var addon models.AddOn
+	if err := scanAddOn(r.db.Pool.QueryRow(ctx, query, addOnID), &addon); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get add-on: %w", err)
+	}
+
+	return &addon, nil
+}
+
+// GetRatePlanInclusions retrieves the add-ons included in a rate plan
+func (r *BookingRepository) GetRatePlanInclusions(ctx context.Context, ratePlanID int) ([]models.AddOn, error) {
+	return queryRatePlanInclusions(ctx, r.db, ratePlanID)
+}
+
+// GetAddOnRemaining returns the units of a limited add-on still available on each date
+// of the range (inclusive), keyed by date (YYYY-MM-DD)
+func (r *BookingRepository) GetAddOnRemaining(ctx context.Context, addOnID int, startDate, endDate time.Time) (map[string]int, error) {
 	query := `
-		SELECT * FROM check_in($1, $2)
+		SELECT d::date, a.daily_limit - addon_quantity_booked(a.addon_id, d::date)
+		FROM addons a
+		CROSS JOIN generate_series($2::date, $3::date, interval '1 day') AS d
+		WHERE a.addon_id = $1 AND a.daily_limit IS NOT NULL
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, addOnID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get add-on availability: %w", err)
+	}
+	defer rows.Close()
+
+	remaining := make(map[string]int)
+	for rows.Next() {
+		var date time.Time
+		var units int
+		if err := rows.Scan(&date, &units); err != nil {
+			return nil, fmt.Errorf("failed to scan add-on availability: %w", err)
+		}
+		remaining[date.Format("2006-01-02")] = units
+	}
+
+	return remaining, rows.Err()
+}
+
+// getBookingAddOns retrieves the add-ons of a booking detail
+func (r *BookingRepository) getBookingAddOns(ctx context.Context, bookingDetailID int) ([]models.BookingAddOn, error) {
+	query := `
+		SELECT ba.booking_addon_id, ba.booking_detail_id, ba.addon_id, a.name, a.category, a.pricing_unit,
+		       ba.quantity, ba.unit_price, ba.total_price, ba.service_start_date, ba.service_end_date, ba.is_included
+		FROM booking_addons ba
+		JOIN addons a ON ba.addon_id = a.addon_id
+		WHERE ba.booking_detail_id = $1
+		ORDER BY ba.is_included DESC, ba.service_start_date, ba.booking_addon_id
 	`
 
+	rows, err := r.db.Pool.Query(ctx, query, bookingDetailID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking add-ons: %w", err)
+	}
+	defer rows.Close()
+
+	var addons []models.BookingAddOn
+	for rows.Next() {
+		var addon models.BookingAddOn
+		err := rows.Scan(
+			&addon.BookingAddOnID,
+			&addon.BookingDetailID,
+			&addon.AddOnID,
+			&addon.Name,
+			&addon.Category,
+			&addon.PricingUnit,
+			&addon.Quantity,
+			&addon.UnitPrice,
+			&addon.TotalPrice,
+			&addon.ServiceStartDate,
+			&addon.ServiceEndDate,
+			&addon.IsIncluded,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking add-on: %w", err)
+		}
+		addons = append(addons, addon)
+	}
+
+	return addons, nil
+}
+
+// callRoomFunction calls a PostgreSQL function returning (success, message) that
+// changes room statuses, on behalf of a staff member
+func (r *BookingRepository) callRoomFunction(ctx context.Context, staffID *int, query string, args ...interface{}) (bool, string, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setStatusActor(ctx, tx, staffID, ""); err != nil {
+		return false, "", err
+	}
+
 	var success bool
 	var message string
+	if err := tx.QueryRow(ctx, query, args...).Scan(&success, &message); err != nil {
+		return false, "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return success, message, nil
+}
+
+// CheckIn calls the PostgreSQL function to check in a guest
+func (r *BookingRepository) CheckIn(ctx context.Context, bookingDetailID, roomID int, staffID *int) (*models.CheckInResponse, error) {
+	query := `
+		SELECT * FROM check_in($1, $2)
+	`
 
-	err := r.db.Pool.QueryRow(ctx, query, bookingDetailID, roomID).Scan(&success, &message)
+	success, message, err := r.callRoomFunction(ctx, staffID, query, bookingDetailID, roomID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check in: %w", err)
 	}
@@ -620,15 +1102,12 @@ func (r *BookingRepository) CheckIn(ctx context.Context, bookingDetailID, roomID
 }
 
 // CheckOut calls the PostgreSQL function to check out a guest
-func (r *BookingRepository) CheckOut(ctx context.Context, bookingID int) (*models.CheckOutResponse, error) {
+func (r *BookingRepository) CheckOut(ctx context.Context, bookingID int, staffID *int) (*models.CheckOutResponse, error) {
 	query := `
 		SELECT * FROM check_out($1)
 	`
 
-	var success bool
-	var message string
-
-	err := r.db.Pool.QueryRow(ctx, query, bookingID).Scan(&success, &message)
+	success, message, err := r.callRoomFunction(ctx, staffID, query, bookingID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check out: %w", err)
 	}
@@ -649,16 +1128,54 @@ func (r *BookingRepository) CheckOut(ctx context.Context, bookingID int) (*model
 	return response, nil
 }
 
+// GetAssignedRoomIDs retrieves the rooms a booking's guests are staying in
+func (r *BookingRepository) GetAssignedRoomIDs(ctx context.Context, bookingID int) ([]int, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT ra.room_id
+		FROM room_assignments ra
+		INNER JOIN booking_details bd ON bd.booking_detail_id = ra.booking_detail_id
+		WHERE bd.booking_id = $1 AND ra.status = 'Active'
+	`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var roomIDs []int
+	for rows.Next() {
+		var roomID int
+		if err := rows.Scan(&roomID); err != nil {
+			return nil, fmt.Errorf("failed to scan assigned room: %w", err)
+		}
+		roomIDs = append(roomIDs, roomID)
+	}
+
+	return roomIDs, rows.Err()
+}
+
+// GetAssignmentRoomID retrieves the room of a room assignment, or 0 when there is no
+// such assignment
+func (r *BookingRepository) GetAssignmentRoomID(ctx context.Context, assignmentID int) (int, error) {
+	var roomID int
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT room_id FROM room_assignments WHERE room_assignment_id = $1
+	`, assignmentID).Scan(&roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get room assignment: %w", err)
+	}
+	return roomID, nil
+}
+
 // MoveRoom calls the PostgreSQL function to move a guest to another room
-func (r *BookingRepository) MoveRoom(ctx context.Context, assignmentID, newRoomID int) (*models.MoveRoomResponse, error) {
+func (r *BookingRepository) MoveRoom(ctx context.Context, assignmentID, newRoomID int, staffID *int) (*models.MoveRoomResponse, error) {
 	query := `
 		SELECT * FROM move_room($1, $2)
 	`
 
-	var success bool
-	var message string
-
-	err := r.db.Pool.QueryRow(ctx, query, assignmentID, newRoomID).Scan(&success, &message)
+	success, message, err := r.callRoomFunction(ctx, staffID, query, assignmentID, newRoomID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to move room: %w", err)
 	}
@@ -744,7 +1261,16 @@ func (r *BookingRepository) GetArrivals(ctx context.Context, date time.Time) ([]
 				ELSE 'none'
 			END as payment_status,
 			pp.proof_url as payment_proof_url,
-			pp.payment_proof_id
+			pp.payment_proof_id,
+			COALESCE(g.is_vip, FALSE) as is_vip,
+			bd.floor_preference,
+			bd.view_preference,
+			bd.accessible_room,
+			bd.connecting_room,
+			pa.room_id as pre_assigned_room_id,
+			par.room_number as pre_assigned_room_number,
+			pa.unmet_preferences,
+			TO_CHAR(str.requested_at, 'HH24:MI') as early_check_in_time
 		FROM bookings b
 		LEFT JOIN guests g ON b.guest_id = g.guest_id
 		JOIN booking_details bd ON b.booking_id = bd.booking_id
@@ -753,6 +1279,10 @@ func (r *BookingRepository) GetArrivals(ctx context.Context, date time.Time) ([]
 		LEFT JOIN room_assignments ra ON bd.booking_detail_id = ra.booking_detail_id AND ra.status = 'Active'
 		LEFT JOIN rooms r ON ra.room_id = r.room_id
 		LEFT JOIN payment_proofs pp ON b.booking_id = pp.booking_id
+		LEFT JOIN room_pre_assignments pa ON bd.booking_detail_id = pa.booking_detail_id
+		LEFT JOIN rooms par ON pa.room_id = par.room_id
+		LEFT JOIN stay_time_requests str ON bd.booking_detail_id = str.booking_detail_id
+			AND str.request_type = 'EarlyCheckIn' AND str.status = 'Approved'
 		WHERE bd.check_in_date = $1
 		  AND b.status IN ('Confirmed', 'CheckedIn')
 		ORDER BY b.status DESC, bd.check_in_date
@@ -781,6 +1311,15 @@ func (r *BookingRepository) GetArrivals(ctx context.Context, date time.Time) ([]
 			&arrival.PaymentStatus,
 			&arrival.PaymentProofURL,
 			&arrival.PaymentProofID,
+			&arrival.IsVIP,
+			&arrival.FloorPreference,
+			&arrival.ViewPreference,
+			&arrival.AccessibleRoom,
+			&arrival.ConnectingRoom,
+			&arrival.PreAssignedRoomID,
+			&arrival.PreAssignedRoomNumber,
+			&arrival.UnmetPreferences,
+			&arrival.EarlyCheckInTime,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan arrival: %w", err)
@@ -796,19 +1335,23 @@ func (r *BookingRepository) GetDepartures(ctx context.Context, date time.Time) (
 	query := `
 		SELECT 
 			b.booking_id,
+			bd.booking_detail_id,
 			CONCAT(g.first_name, ' ', g.last_name) as guest_name,
 			r.room_number,
 			bd.check_out_date,
 			b.total_amount,
-			b.status
+			b.status,
+			TO_CHAR(str.requested_at, 'HH24:MI') as late_check_out_time
 		FROM bookings b
 		JOIN guests g ON b.guest_id = g.guest_id
 		JOIN booking_details bd ON b.booking_id = bd.booking_id
 		JOIN room_assignments ra ON bd.booking_detail_id = ra.booking_detail_id AND ra.status = 'Active'
 		JOIN rooms r ON ra.room_id = r.room_id
+		LEFT JOIN stay_time_requests str ON bd.booking_detail_id = str.booking_detail_id
+			AND str.request_type = 'LateCheckOut' AND str.status = 'Approved'
 		WHERE bd.check_out_date = $1
 		  AND b.status = 'CheckedIn'
-		ORDER BY bd.check_out_date
+		ORDER BY str.requested_at NULLS FIRST, bd.check_out_date
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, date)
@@ -822,11 +1365,13 @@ func (r *BookingRepository) GetDepartures(ctx context.Context, date time.Time) (
 		var departure models.DepartureInfo
 		err := rows.Scan(
 			&departure.BookingID,
+			&departure.BookingDetailID,
 			&departure.GuestName,
 			&departure.RoomNumber,
 			&departure.CheckOutDate,
 			&departure.TotalAmount,
 			&departure.Status,
+			&departure.LateCheckOutTime,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan departure: %w", err)
@@ -841,20 +1386,31 @@ func (r *BookingRepository) GetDepartures(ctx context.Context, date time.Time) (
 func (r *BookingRepository) GetAvailableRoomsForCheckIn(ctx context.Context, roomTypeID int) ([]models.AvailableRoomForCheckIn, error) {
 	query := `
 		SELECT 
-			room_id,
-			room_number,
-			occupancy_status,
-			housekeeping_status
-		FROM rooms
-		WHERE room_type_id = $1
-		  AND occupancy_status = 'Vacant'
-		  AND housekeeping_status IN ('Clean', 'Inspected')
+			r.room_id,
+			r.room_number,
+			r.occupancy_status,
+			r.housekeeping_status,
+			held.booking_detail_id
+		FROM rooms r
+		-- Rooms pre-assigned to another arrival today are listed last
+		LEFT JOIN LATERAL (
+			SELECT pa.booking_detail_id
+			FROM room_pre_assignments pa
+			JOIN booking_details bd ON pa.booking_detail_id = bd.booking_detail_id
+			WHERE pa.room_id = r.room_id
+			  AND bd.check_in_date = CURRENT_DATE
+			LIMIT 1
+		) held ON TRUE
+		WHERE r.room_type_id = $1
+		  AND r.occupancy_status = 'Vacant'
+		  AND r.housekeeping_status IN ('Clean', 'Inspected')
 		ORDER BY 
-			CASE housekeeping_status
+			held.booking_detail_id IS NOT NULL,
+			CASE r.housekeeping_status
 				WHEN 'Inspected' THEN 1
 				WHEN 'Clean' THEN 2
 			END,
-			room_number
+			r.room_number
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, roomTypeID)
@@ -871,6 +1427,7 @@ func (r *BookingRepository) GetAvailableRoomsForCheckIn(ctx context.Context, roo
 			&room.RoomNumber,
 			&room.OccupancyStatus,
 			&room.HousekeepingStatus,
+			&room.PreAssignedTo,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan room: %w", err)
@@ -1017,3 +1574,1211 @@ func (r *BookingRepository) GetGuestByID(ctx context.Context, guestID int) (*mod
 
 	return &guest, nil
 }
+
+// GetPreAssignedRoomID retrieves the room pre-assigned to a stay, or 0 when there is none
+func (r *BookingRepository) GetPreAssignedRoomID(ctx context.Context, bookingDetailID int) (int, error) {
+	var roomID int
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT room_id FROM room_pre_assignments WHERE booking_detail_id = $1
+	`, bookingDetailID).Scan(&roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pre-assigned room: %w", err)
+	}
+	return roomID, nil
+}
+
+// GetPreAssignmentStay retrieves the stay of a booking detail for pre-assigning a room
+func (r *BookingRepository) GetPreAssignmentStay(ctx context.Context, bookingDetailID int) (*models.PreAssignmentStay, error) {
+	var stay models.PreAssignmentStay
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			bd.booking_detail_id,
+			bd.room_type_id,
+			bd.check_in_date,
+			bd.check_out_date,
+			b.status,
+			EXISTS (
+				SELECT 1 FROM room_assignments ra
+				WHERE ra.booking_detail_id = bd.booking_detail_id AND ra.status = 'Active'
+			)
+		FROM booking_details bd
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		WHERE bd.booking_detail_id = $1
+	`, bookingDetailID).Scan(
+		&stay.BookingDetailID,
+		&stay.RoomTypeID,
+		&stay.CheckInDate,
+		&stay.CheckOutDate,
+		&stay.BookingStatus,
+		&stay.CheckedIn,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking detail: %w", err)
+	}
+	return &stay, nil
+}
+
+// GetRoomStayConflicts checks whether a room can be held for the nights from checkIn
+// to checkOut, leaving out the stay's own pre-assignment
+func (r *BookingRepository) GetRoomStayConflicts(ctx context.Context, roomID, bookingDetailID int, checkIn, checkOut time.Time) (*models.RoomStayConflicts, error) {
+	var conflicts models.RoomStayConflicts
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM room_out_of_order o
+				WHERE o.room_id = $1
+				  AND o.status IN ('Scheduled', 'Active')
+				  AND o.start_date < $4
+				  AND o.end_date >= $3
+			),
+			EXISTS (
+				SELECT 1 FROM room_assignments ra
+				JOIN booking_details bd ON ra.booking_detail_id = bd.booking_detail_id
+				WHERE ra.room_id = $1
+				  AND ra.status = 'Active'
+				  AND bd.check_out_date > $3
+			),
+			EXISTS (
+				SELECT 1 FROM room_pre_assignments pa
+				JOIN booking_details bd ON pa.booking_detail_id = bd.booking_detail_id
+				WHERE pa.room_id = $1
+				  AND pa.booking_detail_id <> $2
+				  AND bd.check_in_date < $4
+				  AND bd.check_out_date > $3
+			)
+	`, roomID, bookingDetailID, checkIn, checkOut).Scan(
+		&conflicts.OutOfOrder,
+		&conflicts.Occupied,
+		&conflicts.PreAssigned,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check room availability: %w", err)
+	}
+	return &conflicts, nil
+}
+
+// SetPreAssignment pre-assigns a room to a stay by hand on behalf of assignedBy
+func (r *BookingRepository) SetPreAssignment(ctx context.Context, bookingDetailID, roomID int, assignedBy *int) (*models.RoomPreAssignment, error) {
+	var assignment models.RoomPreAssignment
+	err := r.db.Pool.QueryRow(ctx, `
+		WITH upserted AS (
+			INSERT INTO room_pre_assignments (booking_detail_id, room_id, is_manual, assigned_by)
+			VALUES ($1, $2, TRUE, $3)
+			ON CONFLICT (booking_detail_id) DO UPDATE
+			SET room_id = EXCLUDED.room_id,
+				unmet_preferences = '{}',
+				is_manual = TRUE,
+				assigned_by = EXCLUDED.assigned_by
+			RETURNING booking_detail_id, room_id, unmet_preferences, is_manual, assigned_by, updated_at
+		)
+		SELECT u.booking_detail_id, u.room_id, r.room_number, u.unmet_preferences,
+			u.is_manual, u.assigned_by, u.updated_at
+		FROM upserted u
+		JOIN rooms r ON u.room_id = r.room_id
+	`, bookingDetailID, roomID, assignedBy).Scan(
+		&assignment.BookingDetailID,
+		&assignment.RoomID,
+		&assignment.RoomNumber,
+		&assignment.UnmetPreferences,
+		&assignment.IsManual,
+		&assignment.AssignedBy,
+		&assignment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set pre-assignment: %w", err)
+	}
+	return &assignment, nil
+}
+
+// DeletePreAssignment removes the pre-assigned room of a stay. It returns false when
+// no room is pre-assigned.
+func (r *BookingRepository) DeletePreAssignment(ctx context.Context, bookingDetailID int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM room_pre_assignments WHERE booking_detail_id = $1
+	`, bookingDetailID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete pre-assignment: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetRoomPreferences replaces the room preferences of a stay. It returns false when
+// the booking detail does not exist.
+func (r *BookingRepository) SetRoomPreferences(ctx context.Context, bookingDetailID int, req *models.SetRoomPreferencesRequest) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE booking_details
+		SET floor_preference = NULLIF($2, ''),
+			view_preference = NULLIF($3, ''),
+			accessible_room = $4,
+			connecting_room = $5
+		WHERE booking_detail_id = $1
+	`, bookingDetailID, req.FloorPreference, req.ViewPreference, req.AccessibleRoom, req.ConnectingRoom)
+	if err != nil {
+		return false, fmt.Errorf("failed to set room preferences: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetGuestVIP sets or clears the VIP status of a guest. It returns false when the guest
+// does not exist.
+func (r *BookingRepository) SetGuestVIP(ctx context.Context, guestID int, vip bool) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE guests SET is_vip = $2, updated_at = NOW() WHERE guest_id = $1
+	`, guestID, vip)
+	if err != nil {
+		return false, fmt.Errorf("failed to set guest VIP status: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetUpgradeStay retrieves the booked room an upgrade applies to. It returns nil when
+// the booking detail does not exist.
+func (r *BookingRepository) GetUpgradeStay(ctx context.Context, bookingDetailID int) (*models.UpgradeStay, error) {
+	var stay models.UpgradeStay
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			bd.booking_detail_id,
+			bd.booking_id,
+			bd.room_type_id,
+			rt.name,
+			bd.rate_plan_id,
+			bd.check_in_date,
+			bd.check_out_date,
+			bd.num_guests,
+			bd.stay_type,
+			b.status,
+			EXISTS (
+				SELECT 1 FROM room_assignments ra
+				WHERE ra.booking_detail_id = bd.booking_detail_id AND ra.status = 'Active'
+			)
+		FROM booking_details bd
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		JOIN room_types rt ON bd.room_type_id = rt.room_type_id
+		WHERE bd.booking_detail_id = $1
+	`, bookingDetailID).Scan(
+		&stay.BookingDetailID,
+		&stay.BookingID,
+		&stay.RoomTypeID,
+		&stay.RoomTypeName,
+		&stay.RatePlanID,
+		&stay.CheckInDate,
+		&stay.CheckOutDate,
+		&stay.NumGuests,
+		&stay.StayType,
+		&stay.BookingStatus,
+		&stay.CheckedIn,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking detail: %w", err)
+	}
+	return &stay, nil
+}
+
+// UpgradeRoomType moves a booked room to upgrade.ToRoomTypeID. One booked room moves
+// from the old room type to the new one in inventory for the nights from StartDate to
+// EndDate, the room pre-assigned to the stay is released and TotalCharge is added to
+// the booking total. A guest already in house is moved to roomID, a room of the new
+// room type, with move_room.
+func (r *BookingRepository) UpgradeRoomType(ctx context.Context, upgrade *models.RoomUpgrade, bookingID int, roomID *int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var roomTypeID int
+	err = tx.QueryRow(ctx, `
+		SELECT room_type_id FROM booking_details WHERE booking_detail_id = $1 FOR UPDATE
+	`, upgrade.BookingDetailID).Scan(&roomTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to lock booking detail: %w", err)
+	}
+	if roomTypeID != upgrade.FromRoomTypeID {
+		return errors.New("the booked room type changed; try the upgrade again")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO room_inventory (room_type_id, date, allotment)
+		SELECT rt.room_type_id, d::date, rt.default_allotment
+		FROM room_types rt
+		CROSS JOIN generate_series($2::date, $3::date - interval '1 day', interval '1 day') AS d
+		WHERE rt.room_type_id = $1
+		ON CONFLICT (room_type_id, date) DO NOTHING
+	`, upgrade.ToRoomTypeID, upgrade.StartDate, upgrade.EndDate)
+	if err != nil {
+		return fmt.Errorf("failed to create inventory: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE room_inventory
+		SET booked_count = booked_count + 1, updated_at = NOW()
+		WHERE room_type_id = $1
+		  AND date >= $2 AND date < $3
+		  AND booked_count + tentative_count < allotment
+	`, upgrade.ToRoomTypeID, upgrade.StartDate, upgrade.EndDate)
+	if err != nil {
+		return fmt.Errorf("failed to book upgraded inventory: %w", err)
+	}
+	if int(tag.RowsAffected()) != upgrade.Nights {
+		return errors.New("no room of the new room type is left on every night of the stay")
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE room_inventory
+		SET booked_count = GREATEST(0, booked_count - 1), updated_at = NOW()
+		WHERE room_type_id = $1 AND date >= $2 AND date < $3
+	`, upgrade.FromRoomTypeID, upgrade.StartDate, upgrade.EndDate)
+	if err != nil {
+		return fmt.Errorf("failed to release booked inventory: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE booking_details SET room_type_id = $2 WHERE booking_detail_id = $1
+	`, upgrade.BookingDetailID, upgrade.ToRoomTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to update booking detail: %w", err)
+	}
+
+	// The pre-assigned room is of the old room type
+	_, err = tx.Exec(ctx, `
+		DELETE FROM room_pre_assignments WHERE booking_detail_id = $1
+	`, upgrade.BookingDetailID)
+	if err != nil {
+		return fmt.Errorf("failed to release pre-assignment: %w", err)
+	}
+
+	if roomID != nil {
+		if err := moveUpgradedGuest(ctx, tx, upgrade, *roomID); err != nil {
+			return err
+		}
+	}
+
+	if upgrade.TotalCharge > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE bookings SET total_amount = total_amount + $2, updated_at = NOW()
+			WHERE booking_id = $1
+		`, bookingID, upgrade.TotalCharge)
+		if err != nil {
+			return fmt.Errorf("failed to update booking total: %w", err)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO room_upgrades (
+			booking_detail_id, from_room_type_id, to_room_type_id, upgrade_type, source,
+			charge_per_night, start_date, end_date, total_charge, reason, upgraded_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING upgrade_id, created_at
+	`,
+		upgrade.BookingDetailID,
+		upgrade.FromRoomTypeID,
+		upgrade.ToRoomTypeID,
+		upgrade.UpgradeType,
+		upgrade.Source,
+		upgrade.ChargePerNight,
+		upgrade.StartDate,
+		upgrade.EndDate,
+		upgrade.TotalCharge,
+		upgrade.Reason,
+		upgrade.UpgradedBy,
+	).Scan(&upgrade.UpgradeID, &upgrade.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record room upgrade: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit room upgrade: %w", err)
+	}
+	return nil
+}
+
+// moveUpgradedGuest moves the in-house guest of an upgraded booking detail from their
+// room to roomID, which must be of the new room type
+func moveUpgradedGuest(ctx context.Context, tx pgx.Tx, upgrade *models.RoomUpgrade, roomID int) error {
+	var assignmentID int64
+	err := tx.QueryRow(ctx, `
+		SELECT room_assignment_id FROM room_assignments
+		WHERE booking_detail_id = $1 AND status = 'Active'
+		FOR UPDATE
+	`, upgrade.BookingDetailID).Scan(&assignmentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("the guest is no longer in house")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room assignment: %w", err)
+	}
+
+	var roomTypeID int
+	err = tx.QueryRow(ctx, `SELECT room_type_id FROM rooms WHERE room_id = $1`, roomID).Scan(&roomTypeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("room not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+	if roomTypeID != upgrade.ToRoomTypeID {
+		return fmt.Errorf("the room is not a %s", upgrade.ToRoomTypeName)
+	}
+
+	if err := setStatusActor(ctx, tx, upgrade.UpgradedBy, ""); err != nil {
+		return err
+	}
+
+	var success bool
+	var message string
+	err = tx.QueryRow(ctx, `
+		SELECT success, message FROM move_room($1, $2)
+	`, assignmentID, roomID).Scan(&success, &message)
+	if err != nil {
+		return fmt.Errorf("failed to move room: %w", err)
+	}
+	if !success {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// getBookingUpgrades retrieves the room upgrades of a booking detail
+func (r *BookingRepository) getBookingUpgrades(ctx context.Context, bookingDetailID int) ([]models.RoomUpgrade, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT u.upgrade_id, u.booking_detail_id, u.from_room_type_id, f.name, u.to_room_type_id, t.name,
+		       u.upgrade_type, u.source, u.charge_per_night, u.start_date, u.end_date,
+		       u.end_date - u.start_date, u.total_charge, u.reason, u.upgraded_by, u.created_at
+		FROM room_upgrades u
+		JOIN room_types f ON u.from_room_type_id = f.room_type_id
+		JOIN room_types t ON u.to_room_type_id = t.room_type_id
+		WHERE u.booking_detail_id = $1
+		ORDER BY u.created_at, u.upgrade_id
+	`, bookingDetailID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room upgrades: %w", err)
+	}
+	defer rows.Close()
+
+	var upgrades []models.RoomUpgrade
+	for rows.Next() {
+		var upgrade models.RoomUpgrade
+		err := rows.Scan(
+			&upgrade.UpgradeID,
+			&upgrade.BookingDetailID,
+			&upgrade.FromRoomTypeID,
+			&upgrade.FromRoomTypeName,
+			&upgrade.ToRoomTypeID,
+			&upgrade.ToRoomTypeName,
+			&upgrade.UpgradeType,
+			&upgrade.Source,
+			&upgrade.ChargePerNight,
+			&upgrade.StartDate,
+			&upgrade.EndDate,
+			&upgrade.Nights,
+			&upgrade.TotalCharge,
+			&upgrade.Reason,
+			&upgrade.UpgradedBy,
+			&upgrade.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan room upgrade: %w", err)
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+	return upgrades, rows.Err()
+}
+
+// GetStayTimeStay retrieves the booked room an early check-in or late check-out
+// applies to. It returns nil when the booking detail does not exist.
+func (r *BookingRepository) GetStayTimeStay(ctx context.Context, bookingDetailID int) (*models.StayTimeStay, error) {
+	var stay models.StayTimeStay
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			bd.booking_detail_id,
+			bd.booking_id,
+			b.guest_id,
+			bd.room_type_id,
+			bd.check_in_date,
+			bd.check_out_date,
+			bd.stay_type,
+			b.status,
+			EXISTS (
+				SELECT 1 FROM room_assignments ra
+				WHERE ra.booking_detail_id = bd.booking_detail_id AND ra.status = 'Active'
+			)
+		FROM booking_details bd
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		WHERE bd.booking_detail_id = $1
+	`, bookingDetailID).Scan(
+		&stay.BookingDetailID,
+		&stay.BookingID,
+		&stay.GuestID,
+		&stay.RoomTypeID,
+		&stay.CheckInDate,
+		&stay.CheckOutDate,
+		&stay.StayType,
+		&stay.BookingStatus,
+		&stay.CheckedIn,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking detail: %w", err)
+	}
+	return &stay, nil
+}
+
+// GetStayTimeFees retrieves the active fee tiers of an early check-in or late check-out
+func (r *BookingRepository) GetStayTimeFees(ctx context.Context, requestType string) ([]models.StayTimeFee, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT fee_id, request_type, tier_hour, fee, is_active, created_at, updated_at
+		FROM stay_time_fees
+		WHERE request_type = $1 AND is_active = TRUE
+		ORDER BY tier_hour
+	`, requestType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stay-time fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []models.StayTimeFee
+	for rows.Next() {
+		var fee models.StayTimeFee
+		err := rows.Scan(
+			&fee.FeeID,
+			&fee.RequestType,
+			&fee.TierHour,
+			&fee.Fee,
+			&fee.IsActive,
+			&fee.CreatedAt,
+			&fee.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stay-time fee: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
+
+// GetStayTimeDay retrieves what the rooms of a room type are doing on date, leaving
+// out the stay of bookingDetailID. Times are minutes after midnight; departures leave
+// at 12:00 and arrivals need their room at 14:00 unless a request was approved.
+func (r *BookingRepository) GetStayTimeDay(ctx context.Context, roomTypeID int, date time.Time, bookingDetailID int) (*models.StayTimeDay, error) {
+	var day models.StayTimeDay
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			(
+				SELECT COUNT(*) FROM rooms r
+				WHERE r.room_type_id = $1
+				  AND r.housekeeping_status NOT IN ('MaintenanceRequired', 'OutOfService')
+				  AND NOT EXISTS (
+					  SELECT 1 FROM room_out_of_order o
+					  WHERE o.room_id = r.room_id
+					    AND o.status IN ('Scheduled', 'Active')
+					    AND o.start_date <= $2
+					    AND o.end_date >= $2
+				  )
+			),
+			(
+				SELECT COUNT(*) FROM booking_details bd
+				JOIN bookings b ON bd.booking_id = b.booking_id
+				WHERE bd.room_type_id = $1
+				  AND bd.stay_type = 'Overnight'
+				  AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn')
+				  AND bd.check_in_date < $2
+				  AND bd.check_out_date > $2
+				  AND bd.booking_detail_id <> $3
+			),
+			COALESCE((SELECT checkout_minutes FROM housekeeping_credits WHERE room_type_id = $1), 25)
+	`, roomTypeID, date, bookingDetailID).Scan(&day.Rooms, &day.Stayovers, &day.CleaningMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rooms of the day: %w", err)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT 'Departure',
+			COALESCE((EXTRACT(EPOCH FROM s.requested_at::TIME) / 60)::INT, 720)
+		FROM booking_details bd
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		LEFT JOIN stay_time_requests s ON bd.booking_detail_id = s.booking_detail_id
+			AND s.request_type = 'LateCheckOut' AND s.status = 'Approved'
+		WHERE bd.room_type_id = $1
+		  AND bd.stay_type = 'Overnight'
+		  AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn')
+		  AND bd.check_out_date = $2
+		  AND bd.booking_detail_id <> $3
+		UNION ALL
+		SELECT 'Arrival',
+			CASE
+				WHEN EXISTS (
+					SELECT 1 FROM room_assignments ra
+					WHERE ra.booking_detail_id = bd.booking_detail_id AND ra.status = 'Active'
+				) THEN 0
+				ELSE COALESCE((EXTRACT(EPOCH FROM s.requested_at::TIME) / 60)::INT, 840)
+			END
+		FROM booking_details bd
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		LEFT JOIN stay_time_requests s ON bd.booking_detail_id = s.booking_detail_id
+			AND s.request_type = 'EarlyCheckIn' AND s.status = 'Approved'
+		WHERE bd.room_type_id = $1
+		  AND bd.stay_type = 'Overnight'
+		  AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn')
+		  AND bd.check_in_date = $2
+		  AND bd.booking_detail_id <> $3
+		UNION ALL
+		SELECT 'DayUse', (EXTRACT(EPOCH FROM dub.end_at::TIME) / 60)::INT
+		FROM day_use_blocks dub
+		JOIN rooms r ON dub.room_id = r.room_id
+		JOIN booking_details bd ON dub.booking_detail_id = bd.booking_detail_id
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		WHERE r.room_type_id = $1
+		  AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn')
+		  AND dub.start_at::DATE = $2
+	`, roomTypeID, date, bookingDetailID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stays of the day: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var minute int
+		if err := rows.Scan(&kind, &minute); err != nil {
+			return nil, fmt.Errorf("failed to scan stay of the day: %w", err)
+		}
+		switch kind {
+		case "Departure":
+			day.Departures = append(day.Departures, minute)
+		case "Arrival":
+			day.Arrivals = append(day.Arrivals, minute)
+		case "DayUse":
+			day.DayUseEnds = append(day.DayUseEnds, minute)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get stays of the day: %w", err)
+	}
+	return &day, nil
+}
+
+// GetStayTimeRoom retrieves the room a stay is checked in to, or else pre-assigned to,
+// as it stands on date. from and to are the extra hours the request holds the room.
+// It returns nil when the stay has no room yet.
+func (r *BookingRepository) GetStayTimeRoom(ctx context.Context, bookingDetailID int, date, from, to time.Time) (*models.StayTimeRoom, error) {
+	var room models.StayTimeRoom
+	err := r.db.Pool.QueryRow(ctx, `
+		WITH stay_room AS (
+			SELECT room_id FROM (
+				SELECT ra.room_id, 1 AS priority FROM room_assignments ra
+				WHERE ra.booking_detail_id = $1 AND ra.status = 'Active'
+				UNION ALL
+				SELECT pa.room_id, 2 FROM room_pre_assignments pa
+				WHERE pa.booking_detail_id = $1
+			) rooms
+			ORDER BY priority
+			LIMIT 1
+		)
+		SELECT
+			r.room_id,
+			r.room_number,
+			COALESCE((
+				SELECT MAX(CASE
+					WHEN bd.check_out_date > $2 THEN 1440
+					WHEN bd.check_out_date = $2
+						THEN COALESCE((EXTRACT(EPOCH FROM s.requested_at::TIME) / 60)::INT, 720)
+					ELSE 0
+				END)
+				FROM room_assignments ra
+				JOIN booking_details bd ON ra.booking_detail_id = bd.booking_detail_id
+				LEFT JOIN stay_time_requests s ON bd.booking_detail_id = s.booking_detail_id
+					AND s.request_type = 'LateCheckOut' AND s.status = 'Approved'
+				WHERE ra.room_id = r.room_id
+				  AND ra.status = 'Active'
+				  AND ra.booking_detail_id <> $1
+				  AND bd.stay_type = 'Overnight'
+			), 0),
+			COALESCE((
+				SELECT MIN(COALESCE((EXTRACT(EPOCH FROM s.requested_at::TIME) / 60)::INT, 840))
+				FROM room_pre_assignments pa
+				JOIN booking_details bd ON pa.booking_detail_id = bd.booking_detail_id
+				LEFT JOIN stay_time_requests s ON bd.booking_detail_id = s.booking_detail_id
+					AND s.request_type = 'EarlyCheckIn' AND s.status = 'Approved'
+				WHERE pa.room_id = r.room_id
+				  AND pa.booking_detail_id <> $1
+				  AND bd.check_in_date = $2
+			), 1440),
+			day_use_block_conflict(r.room_id, $3, $4, $1),
+			EXISTS (
+				SELECT 1 FROM room_out_of_order o
+				WHERE o.room_id = r.room_id
+				  AND o.status IN ('Scheduled', 'Active')
+				  AND o.start_date <= $2
+				  AND o.end_date >= $2
+			)
+		FROM stay_room sr
+		JOIN rooms r ON sr.room_id = r.room_id
+	`, bookingDetailID, date, from, to).Scan(
+		&room.RoomID,
+		&room.RoomNumber,
+		&room.PreviousDeparture,
+		&room.NextArrival,
+		&room.DayUseConflict,
+		&room.OutOfOrder,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room of the stay: %w", err)
+	}
+	return &room, nil
+}
+
+// stayTimeRequestQuery selects stay-time requests with the guest, room type and the
+// room the stay is checked in to or pre-assigned to
+const stayTimeRequestQuery = `
+	SELECT
+		s.request_id,
+		s.booking_detail_id,
+		bd.booking_id,
+		COALESCE(
+			g.first_name || ' ' || g.last_name,
+			(SELECT bg.first_name || ' ' || bg.last_name FROM booking_guests bg
+			 WHERE bg.booking_detail_id = bd.booking_detail_id AND bg.is_primary = TRUE
+			 LIMIT 1),
+			'Guest'
+		),
+		rt.name,
+		COALESCE(ar.room_number, pr.room_number),
+		s.request_type,
+		s.requested_at,
+		s.fee,
+		s.status,
+		s.source,
+		s.note,
+		s.decline_reason,
+		s.decided_by,
+		s.decided_at,
+		s.created_at,
+		s.updated_at
+	FROM stay_time_requests s
+	JOIN booking_details bd ON s.booking_detail_id = bd.booking_detail_id
+	JOIN bookings b ON bd.booking_id = b.booking_id
+	LEFT JOIN guests g ON b.guest_id = g.guest_id
+	JOIN room_types rt ON bd.room_type_id = rt.room_type_id
+	LEFT JOIN room_assignments ra ON bd.booking_detail_id = ra.booking_detail_id AND ra.status = 'Active'
+	LEFT JOIN rooms ar ON ra.room_id = ar.room_id
+	LEFT JOIN room_pre_assignments pa ON bd.booking_detail_id = pa.booking_detail_id
+	LEFT JOIN rooms pr ON pa.room_id = pr.room_id
+`
+
+// collectStayTimeRequests scans rows selected with stayTimeRequestQuery
+func collectStayTimeRequests(rows pgx.Rows) ([]models.StayTimeRequest, error) {
+	defer rows.Close()
+
+	var requests []models.StayTimeRequest
+	for rows.Next() {
+		var request models.StayTimeRequest
+		err := rows.Scan(
+			&request.RequestID,
+			&request.BookingDetailID,
+			&request.BookingID,
+			&request.GuestName,
+			&request.RoomTypeName,
+			&request.RoomNumber,
+			&request.RequestType,
+			&request.RequestedAt,
+			&request.Fee,
+			&request.Status,
+			&request.Source,
+			&request.Note,
+			&request.DeclineReason,
+			&request.DecidedBy,
+			&request.DecidedAt,
+			&request.CreatedAt,
+			&request.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stay-time request: %w", err)
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+// getStayTimeRequests retrieves the early check-in and late check-out requests of a
+// booking detail
+func (r *BookingRepository) getStayTimeRequests(ctx context.Context, bookingDetailID int) ([]models.StayTimeRequest, error) {
+	rows, err := r.db.Pool.Query(ctx, stayTimeRequestQuery+`
+		WHERE s.booking_detail_id = $1
+		ORDER BY s.created_at, s.request_id
+	`, bookingDetailID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stay-time requests: %w", err)
+	}
+	return collectStayTimeRequests(rows)
+}
+
+// GetStayTimeRequests retrieves the requests for date, optionally of one status
+func (r *BookingRepository) GetStayTimeRequests(ctx context.Context, date time.Time, status string) ([]models.StayTimeRequest, error) {
+	rows, err := r.db.Pool.Query(ctx, stayTimeRequestQuery+`
+		WHERE s.requested_at::DATE = $1
+		  AND ($2 = '' OR s.status = $2)
+		ORDER BY s.requested_at, s.request_id
+	`, date, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stay-time requests: %w", err)
+	}
+	return collectStayTimeRequests(rows)
+}
+
+// GetStayTimeRequestByID retrieves a stay-time request. It returns nil when it does
+// not exist.
+func (r *BookingRepository) GetStayTimeRequestByID(ctx context.Context, requestID int) (*models.StayTimeRequest, error) {
+	rows, err := r.db.Pool.Query(ctx, stayTimeRequestQuery+`WHERE s.request_id = $1`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stay-time request: %w", err)
+	}
+	requests, err := collectStayTimeRequests(rows)
+	if err != nil || len(requests) == 0 {
+		return nil, err
+	}
+	return &requests[0], nil
+}
+
+// HasOpenStayTimeRequest reports whether a stay has a pending or approved request of
+// requestType
+func (r *BookingRepository) HasOpenStayTimeRequest(ctx context.Context, bookingDetailID int, requestType string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM stay_time_requests
+			WHERE booking_detail_id = $1
+			  AND request_type = $2
+			  AND status IN ('Pending', 'Approved')
+		)
+	`, bookingDetailID, requestType).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check stay-time requests: %w", err)
+	}
+	return exists, nil
+}
+
+// CreateStayTimeRequest records a stay-time request. The fee of a request approved
+// when made is added to the booking total.
+func (r *BookingRepository) CreateStayTimeRequest(ctx context.Context, request *models.StayTimeRequest) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO stay_time_requests (
+			booking_detail_id, request_type, requested_at, fee, status, source, note,
+			decided_by, decided_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $5 = 'Approved' THEN NOW() END)
+		RETURNING request_id, decided_at, created_at, updated_at
+	`,
+		request.BookingDetailID,
+		request.RequestType,
+		request.RequestedAt,
+		request.Fee,
+		request.Status,
+		request.Source,
+		request.Note,
+		request.DecidedBy,
+	).Scan(&request.RequestID, &request.DecidedAt, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stay-time request: %w", err)
+	}
+
+	if request.Status == models.StayTimeStatusApproved {
+		if err := addStayTimeFee(ctx, tx, request.BookingID, request.Fee); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit stay-time request: %w", err)
+	}
+	return nil
+}
+
+// DecideStayTimeRequest approves or declines a pending request. The fee of an approved
+// request is added to the booking total.
+func (r *BookingRepository) DecideStayTimeRequest(ctx context.Context, request *models.StayTimeRequest, decidedBy *int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		UPDATE stay_time_requests
+		SET status = $2, fee = $3, decline_reason = $4, decided_by = $5, decided_at = NOW()
+		WHERE request_id = $1 AND status = 'Pending'
+		RETURNING decided_at, updated_at
+	`, request.RequestID, request.Status, request.Fee, request.DeclineReason, decidedBy).Scan(&request.DecidedAt, &request.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("the request is no longer pending")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update stay-time request: %w", err)
+	}
+	request.DecidedBy = decidedBy
+
+	if request.Status == models.StayTimeStatusApproved {
+		if err := addStayTimeFee(ctx, tx, request.BookingID, request.Fee); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit stay-time request: %w", err)
+	}
+	return nil
+}
+
+// CancelStayTimeRequest cancels a pending or approved request. The fee of an approved
+// request is taken off the booking total.
+func (r *BookingRepository) CancelStayTimeRequest(ctx context.Context, request *models.StayTimeRequest) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		UPDATE stay_time_requests
+		SET status = 'Cancelled'
+		WHERE request_id = $1 AND status = $2
+		RETURNING updated_at
+	`, request.RequestID, request.Status).Scan(&request.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("the request changed; try again")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel stay-time request: %w", err)
+	}
+
+	if request.Status == models.StayTimeStatusApproved {
+		if err := addStayTimeFee(ctx, tx, request.BookingID, -request.Fee); err != nil {
+			return err
+		}
+	}
+	request.Status = models.StayTimeStatusCancelled
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit stay-time request: %w", err)
+	}
+	return nil
+}
+
+// addStayTimeFee adds fee to the booking total, or takes it off when negative
+func addStayTimeFee(ctx context.Context, tx pgx.Tx, bookingID int, fee float64) error {
+	if fee == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		UPDATE bookings SET total_amount = total_amount + $2, updated_at = NOW()
+		WHERE booking_id = $1
+	`, bookingID, fee)
+	if err != nil {
+		return fmt.Errorf("failed to update booking total: %w", err)
+	}
+	return nil
+}
+
+// GetWalkStay retrieves the booked room a walk applies to. It returns nil when the
+// booking detail does not exist.
+func (r *BookingRepository) GetWalkStay(ctx context.Context, bookingDetailID int) (*models.WalkStay, error) {
+	var stay models.WalkStay
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			bd.booking_detail_id,
+			bd.booking_id,
+			bd.room_type_id,
+			bd.check_in_date,
+			bd.check_out_date,
+			bd.stay_type,
+			b.status,
+			EXISTS (
+				SELECT 1 FROM room_assignments ra
+				WHERE ra.booking_detail_id = bd.booking_detail_id AND ra.status = 'Active'
+			),
+			(SELECT COUNT(*) FROM booking_details o WHERE o.booking_id = bd.booking_id)
+		FROM booking_details bd
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		WHERE bd.booking_detail_id = $1
+	`, bookingDetailID).Scan(
+		&stay.BookingDetailID,
+		&stay.BookingID,
+		&stay.RoomTypeID,
+		&stay.CheckInDate,
+		&stay.CheckOutDate,
+		&stay.StayType,
+		&stay.BookingStatus,
+		&stay.CheckedIn,
+		&stay.BookedRooms,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking detail: %w", err)
+	}
+	return &stay, nil
+}
+
+// WalkGuest relocates the guest of a booked room to a partner hotel for walk.Nights
+// nights from walk.WalkDate. The walked nights are released in inventory and removed
+// from the stay with their nightly prices, which are taken off the booking total as
+// walk.WaivedAmount; open early check-in requests are cancelled and the pre-assigned
+// room is released. A full-stay walk cancels the booking and its open requests.
+func (r *BookingRepository) WalkGuest(ctx context.Context, walk *models.BookingWalk) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var roomTypeID int
+	var checkIn time.Time
+	var checkedIn bool
+	err = tx.QueryRow(ctx, `
+		SELECT bd.room_type_id, bd.check_in_date,
+		       EXISTS (
+				SELECT 1 FROM room_assignments ra
+				WHERE ra.booking_detail_id = bd.booking_detail_id AND ra.status = 'Active'
+		       )
+		FROM booking_details bd
+		WHERE bd.booking_detail_id = $1
+		FOR UPDATE
+	`, walk.BookingDetailID).Scan(&roomTypeID, &checkIn, &checkedIn)
+	if err != nil {
+		return fmt.Errorf("failed to lock booking detail: %w", err)
+	}
+	if checkedIn {
+		return errors.New("the guest has already checked in")
+	}
+	if !checkIn.Equal(walk.WalkDate) {
+		return errors.New("the stay changed; try the walk again")
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT name FROM partner_hotels WHERE partner_hotel_id = $1 AND is_active = TRUE
+	`, walk.PartnerHotelID).Scan(&walk.PartnerHotelName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("partner hotel not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get partner hotel: %w", err)
+	}
+
+	until := walk.WalkDate.AddDate(0, 0, walk.Nights)
+	_, err = tx.Exec(ctx, `
+		UPDATE room_inventory
+		SET booked_count = GREATEST(0, booked_count - 1), updated_at = NOW()
+		WHERE room_type_id = $1 AND date >= $2 AND date < $3
+	`, roomTypeID, walk.WalkDate, until)
+	if err != nil {
+		return fmt.Errorf("failed to release booked inventory: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		WITH walked AS (
+			DELETE FROM booking_nightly_log
+			WHERE booking_detail_id = $1 AND date >= $2 AND date < $3
+			RETURNING quoted_price
+		)
+		SELECT COALESCE(SUM(quoted_price), 0) FROM walked
+	`, walk.BookingDetailID, walk.WalkDate, until).Scan(&walk.WaivedAmount)
+	if err != nil {
+		return fmt.Errorf("failed to remove walked nights: %w", err)
+	}
+
+	// Early check-in no longer applies; a full-stay walk leaves no stay to check out of
+	var cancelledFees float64
+	err = tx.QueryRow(ctx, `
+		WITH open AS (
+			SELECT request_id, status
+			FROM stay_time_requests
+			WHERE booking_detail_id = $1
+			  AND status IN ('Pending', 'Approved')
+			  AND (request_type = 'EarlyCheckIn' OR $2)
+			FOR UPDATE
+		),
+		cancelled AS (
+			UPDATE stay_time_requests s
+			SET status = 'Cancelled'
+			FROM open
+			WHERE s.request_id = open.request_id
+			RETURNING open.status, s.fee
+		)
+		SELECT COALESCE(SUM(fee) FILTER (WHERE status = 'Approved'), 0) FROM cancelled
+	`, walk.BookingDetailID, walk.FullStay).Scan(&cancelledFees)
+	if err != nil {
+		return fmt.Errorf("failed to cancel stay-time requests: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM room_pre_assignments WHERE booking_detail_id = $1
+	`, walk.BookingDetailID)
+	if err != nil {
+		return fmt.Errorf("failed to release pre-assignment: %w", err)
+	}
+
+	if walk.FullStay {
+		_, err = tx.Exec(ctx, `
+			UPDATE bookings SET status = 'Cancelled', updated_at = NOW() WHERE booking_id = $1
+		`, walk.BookingID)
+		if err != nil {
+			return fmt.Errorf("failed to cancel booking: %w", err)
+		}
+	} else {
+		_, err = tx.Exec(ctx, `
+			UPDATE booking_details SET check_in_date = $2 WHERE booking_detail_id = $1
+		`, walk.BookingDetailID, until)
+		if err != nil {
+			return fmt.Errorf("failed to update booking detail: %w", err)
+		}
+	}
+
+	if refund := walk.WaivedAmount + cancelledFees; refund > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE bookings SET total_amount = total_amount - $2, updated_at = NOW()
+			WHERE booking_id = $1
+		`, walk.BookingID, refund)
+		if err != nil {
+			return fmt.Errorf("failed to update booking total: %w", err)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO booking_walks (
+			booking_detail_id, partner_hotel_id, walk_date, nights, full_stay, waived_amount,
+			compensation_amount, compensation_notes, transport_provided, notes, walked_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING walk_id, created_at
+	`,
+		walk.BookingDetailID,
+		walk.PartnerHotelID,
+		walk.WalkDate,
+		walk.Nights,
+		walk.FullStay,
+		walk.WaivedAmount,
+		walk.CompensationAmount,
+		walk.CompensationNotes,
+		walk.TransportProvided,
+		walk.Notes,
+		walk.WalkedBy,
+	).Scan(&walk.WalkID, &walk.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record walk: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit walk: %w", err)
+	}
+	return nil
+}
+
+// walkQuery selects walks with the guest, room type and partner hotel
+const walkQuery = `
+	SELECT
+		w.walk_id,
+		w.booking_detail_id,
+		bd.booking_id,
+		COALESCE(
+			g.first_name || ' ' || g.last_name,
+			(SELECT bg.first_name || ' ' || bg.last_name FROM booking_guests bg
+			 WHERE bg.booking_detail_id = bd.booking_detail_id AND bg.is_primary = TRUE
+			 LIMIT 1),
+			'Guest'
+		),
+		rt.name,
+		w.partner_hotel_id,
+		ph.name,
+		w.walk_date,
+		w.nights,
+		w.full_stay,
+		w.waived_amount,
+		w.compensation_amount,
+		w.compensation_notes,
+		w.transport_provided,
+		w.notes,
+		w.walked_by,
+		w.created_at
+	FROM booking_walks w
+	JOIN booking_details bd ON w.booking_detail_id = bd.booking_detail_id
+	JOIN bookings b ON bd.booking_id = b.booking_id
+	LEFT JOIN guests g ON b.guest_id = g.guest_id
+	JOIN room_types rt ON bd.room_type_id = rt.room_type_id
+	JOIN partner_hotels ph ON w.partner_hotel_id = ph.partner_hotel_id
+`
+
+// collectWalks scans rows selected with walkQuery
+func collectWalks(rows pgx.Rows) ([]models.BookingWalk, error) {
+	defer rows.Close()
+
+	var walks []models.BookingWalk
+	for rows.Next() {
+		var walk models.BookingWalk
+		err := rows.Scan(
+			&walk.WalkID,
+			&walk.BookingDetailID,
+			&walk.BookingID,
+			&walk.GuestName,
+			&walk.RoomTypeName,
+			&walk.PartnerHotelID,
+			&walk.PartnerHotelName,
+			&walk.WalkDate,
+			&walk.Nights,
+			&walk.FullStay,
+			&walk.WaivedAmount,
+			&walk.CompensationAmount,
+			&walk.CompensationNotes,
+			&walk.TransportProvided,
+			&walk.Notes,
+			&walk.WalkedBy,
+			&walk.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan walk: %w", err)
+		}
+		walks = append(walks, walk)
+	}
+	return walks, rows.Err()
+}
+
+// getBookingWalks retrieves the walks of a booking detail
+func (r *BookingRepository) getBookingWalks(ctx context.Context, bookingDetailID int) ([]models.BookingWalk, error) {
+	rows, err := r.db.Pool.Query(ctx, walkQuery+`
+		WHERE w.booking_detail_id = $1
+		ORDER BY w.walk_date, w.walk_id
+	`, bookingDetailID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get walks: %w", err)
+	}
+	return collectWalks(rows)
+}
+
+// GetWalks retrieves the guests walked on date
+func (r *BookingRepository) GetWalks(ctx context.Context, date time.Time) ([]models.BookingWalk, error) {
+	rows, err := r.db.Pool.Query(ctx, walkQuery+`
+		WHERE w.walk_date = $1
+		ORDER BY w.created_at, w.walk_id
+	`, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get walks: %w", err)
+	}
+	return collectWalks(rows)
+}
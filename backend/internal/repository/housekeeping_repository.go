@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
 )
 
 // HousekeepingRepository handles housekeeping database operations
@@ -18,36 +22,91 @@ func NewHousekeepingRepository(db *database.DB) *HousekeepingRepository {
 	return &HousekeepingRepository{db: db}
 }
 
-// GetHousekeepingTasks retrieves all rooms that need cleaning or inspection
-func (r *HousekeepingRepository) GetHousekeepingTasks(ctx context.Context) ([]models.HousekeepingTask, error) {
-	query := `
-		SELECT 
-			r.room_id,
-			r.room_number,
-			r.room_type_id,
-			rt.name as room_type_name,
-			r.occupancy_status,
-			r.housekeeping_status,
-			CASE 
-				WHEN r.housekeeping_status = 'Dirty' AND r.occupancy_status = 'Vacant' THEN 1
-				WHEN r.housekeeping_status = 'Dirty' AND r.occupancy_status = 'Occupied' THEN 2
-				WHEN r.housekeeping_status = 'Cleaning' THEN 3
-				WHEN r.housekeeping_status = 'Clean' THEN 4
-				ELSE 5
-			END as priority,
-			CURRENT_TIMESTAMP as last_updated,
-			CASE 
-				WHEN rt.name ILIKE '%suite%' THEN 45
-				WHEN rt.name ILIKE '%deluxe%' THEN 35
-				ELSE 25
-			END as estimated_time
-		FROM rooms r
-		INNER JOIN room_types rt ON r.room_type_id = rt.room_type_id
+// housekeepingTaskSelect reads the columns scanned by scanHousekeepingTask. The
+// service of a room is a checkout clean once the guest has left, otherwise the
+// service the night audit found due; credits and estimated minutes follow the
+// service and room type. Rooms assigned for today carry their attendant and the
+// credits they were assigned for.
+const housekeepingTaskSelect = `
+	SELECT
+		r.room_id,
+		r.room_number,
+		r.room_type_id,
+		rt.name as room_type_name,
+		r.floor,
+		r.occupancy_status,
+		r.housekeeping_status,
+		COALESCE(ha.task_type, svc.service) as task_type,
+		COALESCE(ha.credits,
+			CASE svc.service
+				WHEN 'Stayover' THEN COALESCE(hc.stayover_credits, 0.5)
+				WHEN 'Refresh' THEN COALESCE(hc.refresh_credits, 0.25)
+				ELSE COALESCE(hc.checkout_credits, 1.0)
+			END)::float8 as credits,
+		(r.occupancy_status = 'Vacant' AND EXISTS (
+			SELECT 1
+			FROM booking_details bd
+			INNER JOIN bookings b ON b.booking_id = bd.booking_id
+			WHERE bd.room_type_id = r.room_type_id
+			  AND bd.check_in_date = CURRENT_DATE
+			  AND b.status = 'Confirmed'
+		)) as arrival_pending,
+		r.do_not_disturb,
+		ha.staff_id as assigned_to,
+		s.first_name || ' ' || s.last_name as assignee_name,
+		ha.started_at,
+		ha.completed_at,
+		CURRENT_TIMESTAMP as last_updated,
+		CASE COALESCE(ha.task_type, svc.service)
+			WHEN 'Stayover' THEN COALESCE(hc.stayover_minutes, 15)
+			WHEN 'Refresh' THEN COALESCE(hc.refresh_minutes, 5)
+			ELSE COALESCE(hc.checkout_minutes, 25)
+		END as estimated_time
+	FROM rooms r
+	INNER JOIN room_types rt ON r.room_type_id = rt.room_type_id
+	CROSS JOIN LATERAL (
+		SELECT CASE WHEN r.occupancy_status = 'Occupied'
+			THEN COALESCE(r.stayover_service, 'Stayover')
+			ELSE 'Checkout'
+		END as service
+	) svc
+	LEFT JOIN housekeeping_credits hc ON hc.room_type_id = r.room_type_id
+	LEFT JOIN housekeeping_assignments ha ON ha.room_id = r.room_id AND ha.task_date = CURRENT_DATE
+	LEFT JOIN staff s ON s.staff_id = ha.staff_id`
+
+func scanHousekeepingTask(row pgx.Row, task *models.HousekeepingTask) error {
+	return row.Scan(
+		&task.RoomID,
+		&task.RoomNumber,
+		&task.RoomTypeID,
+		&task.RoomTypeName,
+		&task.Floor,
+		&task.OccupancyStatus,
+		&task.HousekeepingStatus,
+		&task.TaskType,
+		&task.Credits,
+		&task.ArrivalPending,
+		&task.DoNotDisturb,
+		&task.AssignedTo,
+		&task.AssigneeName,
+		&task.StartedAt,
+		&task.CompletedAt,
+		&task.LastUpdated,
+		&task.EstimatedTime,
+	)
+}
+
+// GetHousekeepingTasks retrieves all rooms that need cleaning or inspection, or only
+// the rooms assigned to one attendant for today
+func (r *HousekeepingRepository) GetHousekeepingTasks(ctx context.Context, attendantID *int) ([]models.HousekeepingTask, error) {
+	query := housekeepingTaskSelect + `
 		WHERE r.housekeeping_status IN ('Dirty', 'Cleaning', 'Clean')
-		ORDER BY priority ASC, r.room_number ASC
+		  AND r.is_active = TRUE
+		  AND ($1::int IS NULL OR ha.staff_id = $1)
+		ORDER BY r.room_number ASC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Pool.Query(ctx, query, attendantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get housekeeping tasks: %w", err)
 	}
@@ -56,17 +115,7 @@ func (r *HousekeepingRepository) GetHousekeepingTasks(ctx context.Context) ([]mo
 	var tasks []models.HousekeepingTask
 	for rows.Next() {
 		var task models.HousekeepingTask
-		if err := rows.Scan(
-			&task.RoomID,
-			&task.RoomNumber,
-			&task.RoomTypeID,
-			&task.RoomTypeName,
-			&task.OccupancyStatus,
-			&task.HousekeepingStatus,
-			&task.Priority,
-			&task.LastUpdated,
-			&task.EstimatedTime,
-		); err != nil {
+		if err := scanHousekeepingTask(rows, &task); err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
@@ -79,6 +128,244 @@ func (r *HousekeepingRepository) GetHousekeepingTasks(ctx context.Context) ([]mo
 	return tasks, nil
 }
 
+// GetHousekeepingTask retrieves the task of one active room, or nil when there is no
+// such room
+func (r *HousekeepingRepository) GetHousekeepingTask(ctx context.Context, roomID int) (*models.HousekeepingTask, error) {
+	var task models.HousekeepingTask
+	err := scanHousekeepingTask(r.db.Pool.QueryRow(ctx, housekeepingTaskSelect+`
+		WHERE r.room_id = $1 AND r.is_active = TRUE
+	`, roomID), &task)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get housekeeping task: %w", err)
+	}
+	return &task, nil
+}
+
+// GetAttendants retrieves the active housekeepers, optionally only the given ones,
+// with their assignments for today
+func (r *HousekeepingRepository) GetAttendants(ctx context.Context, staffIDs []int) ([]models.AttendantProgress, error) {
+	query := `
+		SELECT
+			s.staff_id,
+			s.first_name || ' ' || s.last_name,
+			COUNT(ha.assignment_id),
+			COUNT(ha.completed_at),
+			COALESCE(SUM(ha.credits), 0)::float8,
+			COALESCE(SUM(ha.credits) FILTER (WHERE ha.completed_at IS NOT NULL), 0)::float8
+		FROM staff s
+		INNER JOIN roles ro ON ro.role_id = s.role_id
+		LEFT JOIN housekeeping_assignments ha ON ha.staff_id = s.staff_id AND ha.task_date = CURRENT_DATE
+		WHERE ro.role_code = 'HOUSEKEEPER'
+		  AND s.is_active = TRUE
+		  AND (cardinality($1::int[]) = 0 OR s.staff_id = ANY($1))
+		GROUP BY s.staff_id, s.first_name, s.last_name
+		ORDER BY s.staff_id
+	`
+
+	if staffIDs == nil {
+		staffIDs = []int{}
+	}
+	rows, err := r.db.Pool.Query(ctx, query, staffIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendants: %w", err)
+	}
+	defer rows.Close()
+
+	attendants := []models.AttendantProgress{}
+	for rows.Next() {
+		var a models.AttendantProgress
+		if err := rows.Scan(
+			&a.StaffID,
+			&a.Name,
+			&a.AssignedRooms,
+			&a.CompletedRooms,
+			&a.AssignedCredits,
+			&a.CompletedCredits,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan attendant: %w", err)
+		}
+		attendants = append(attendants, a)
+	}
+
+	return attendants, rows.Err()
+}
+
+// SaveAssignments assigns rooms to attendants for today, replacing any assignment
+// of those rooms that has not been completed
+func (r *HousekeepingRepository) SaveAssignments(ctx context.Context, assignments []models.HousekeepingAssignment, assignedBy *int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, a := range assignments {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO housekeeping_assignments (room_id, staff_id, task_type, credits, assigned_by)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (room_id, task_date)
+			DO UPDATE SET
+				staff_id = EXCLUDED.staff_id,
+				task_type = EXCLUDED.task_type,
+				credits = EXCLUDED.credits,
+				assigned_by = EXCLUDED.assigned_by,
+				assigned_at = CURRENT_TIMESTAMP
+			WHERE housekeeping_assignments.completed_at IS NULL
+		`, a.RoomID, a.StaffID, a.TaskType, a.Credits, assignedBy)
+		if err != nil {
+			return fmt.Errorf("failed to assign room %d: %w", a.RoomID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UnassignRoom takes a room off its attendant for today unless it has been completed
+func (r *HousekeepingRepository) UnassignRoom(ctx context.Context, roomID int) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM housekeeping_assignments
+		WHERE room_id = $1 AND task_date = CURRENT_DATE AND completed_at IS NULL
+	`, roomID)
+	if err != nil {
+		return fmt.Errorf("failed to unassign room: %w", err)
+	}
+	return nil
+}
+
+// GetHousekeepingCredits retrieves the cleaning credits and estimated minutes of
+// every room type
+func (r *HousekeepingRepository) GetHousekeepingCredits(ctx context.Context) ([]models.HousekeepingCredits, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			rt.room_type_id,
+			rt.name,
+			COALESCE(hc.checkout_credits, 1.0)::float8,
+			COALESCE(hc.stayover_credits, 0.5)::float8,
+			COALESCE(hc.refresh_credits, 0.25)::float8,
+			COALESCE(hc.checkout_minutes, 25),
+			COALESCE(hc.stayover_minutes, 15),
+			COALESCE(hc.refresh_minutes, 5)
+		FROM room_types rt
+		LEFT JOIN housekeeping_credits hc ON hc.room_type_id = rt.room_type_id
+		ORDER BY rt.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get housekeeping credits: %w", err)
+	}
+	defer rows.Close()
+
+	credits := []models.HousekeepingCredits{}
+	for rows.Next() {
+		var c models.HousekeepingCredits
+		err := rows.Scan(&c.RoomTypeID, &c.RoomTypeName, &c.CheckoutCredits, &c.StayoverCredits,
+			&c.RefreshCredits, &c.CheckoutMinutes, &c.StayoverMinutes, &c.RefreshMinutes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan housekeeping credits: %w", err)
+		}
+		credits = append(credits, c)
+	}
+
+	return credits, rows.Err()
+}
+
+// UpsertHousekeepingCredits sets the cleaning credits and estimated minutes of a room
+// type. It returns false when the room type does not exist.
+func (r *HousekeepingRepository) UpsertHousekeepingCredits(ctx context.Context, roomTypeID int, req *models.UpdateHousekeepingCreditsRequest) (bool, error) {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO housekeeping_credits (room_type_id, checkout_credits, stayover_credits, refresh_credits,
+		                                  checkout_minutes, stayover_minutes, refresh_minutes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (room_type_id)
+		DO UPDATE SET
+			checkout_credits = EXCLUDED.checkout_credits,
+			stayover_credits = EXCLUDED.stayover_credits,
+			refresh_credits = EXCLUDED.refresh_credits,
+			checkout_minutes = EXCLUDED.checkout_minutes,
+			stayover_minutes = EXCLUDED.stayover_minutes,
+			refresh_minutes = EXCLUDED.refresh_minutes
+	`, roomTypeID, req.CheckoutCredits, req.StayoverCredits, req.RefreshCredits,
+		req.CheckoutMinutes, req.StayoverMinutes, req.RefreshMinutes)
+	if pgErrorCode(err) == pgForeignKeyViolation {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update housekeeping credits: %w", err)
+	}
+	return true, nil
+}
+
+// GetRoomServicePreferences retrieves the DND flag of an occupied room and the
+// service preferences of the stay in it, or nil when no guest is staying in the room
+func (r *HousekeepingRepository) GetRoomServicePreferences(ctx context.Context, roomID int) (*models.RoomServicePreferences, error) {
+	query := `
+		SELECT r.room_id, r.room_number, bd.booking_detail_id, r.do_not_disturb, r.dnd_set_at, r.dnd_set_by,
+		       bd.green_opt_out, stay_housekeeping_frequency(bd.booking_detail_id), r.stayover_service
+		FROM rooms r
+		INNER JOIN room_assignments ra ON ra.room_id = r.room_id AND ra.status = 'Active'
+		INNER JOIN booking_details bd ON bd.booking_detail_id = ra.booking_detail_id
+		WHERE r.room_id = $1 AND r.occupancy_status = 'Occupied'
+	`
+
+	var prefs models.RoomServicePreferences
+	err := r.db.Pool.QueryRow(ctx, query, roomID).Scan(
+		&prefs.RoomID,
+		&prefs.RoomNumber,
+		&prefs.BookingDetailID,
+		&prefs.DoNotDisturb,
+		&prefs.DNDSetAt,
+		&prefs.DNDSetBy,
+		&prefs.GreenOptOut,
+		&prefs.HousekeepingFrequency,
+		&prefs.StayoverService,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room service preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// SetDoNotDisturb sets or clears the DND flag of an occupied room. It returns false
+// when no guest is staying in the room.
+func (r *HousekeepingRepository) SetDoNotDisturb(ctx context.Context, roomID int, doNotDisturb bool, staffID *int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE rooms
+		SET do_not_disturb = $2,
+		    dnd_set_at = CASE WHEN $2 THEN CURRENT_TIMESTAMP END,
+		    dnd_set_by = CASE WHEN $2 THEN $3::int END
+		WHERE room_id = $1 AND occupancy_status = 'Occupied'
+	`, roomID, doNotDisturb, staffID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set do not disturb: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetGreenOptOut enrols the stay in a room in the green program or takes it out. It
+// returns false when no guest is staying in the room.
+func (r *HousekeepingRepository) SetGreenOptOut(ctx context.Context, roomID int, optOut bool) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE booking_details bd
+		SET green_opt_out = $2
+		FROM room_assignments ra
+		WHERE ra.booking_detail_id = bd.booking_detail_id
+		  AND ra.room_id = $1
+		  AND ra.status = 'Active'
+	`, roomID, optOut)
+	if err != nil {
+		return false, fmt.Errorf("failed to set green program opt-out: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 // GetTaskSummary retrieves a summary of tasks by status
 func (r *HousekeepingRepository) GetTaskSummary(ctx context.Context) (*models.TaskSummary, error) {
 	query := `
@@ -89,6 +376,7 @@ func (r *HousekeepingRepository) GetTaskSummary(ctx context.Context) (*models.Ta
 			COUNT(*) FILTER (WHERE housekeeping_status = 'Inspected') as inspected,
 			COUNT(*) FILTER (WHERE housekeeping_status = 'MaintenanceRequired') as maintenance_required
 		FROM rooms
+		WHERE is_active = TRUE
 	`
 
 	var summary models.TaskSummary
@@ -107,15 +395,44 @@ func (r *HousekeepingRepository) GetTaskSummary(ctx context.Context) (*models.Ta
 	return &summary, nil
 }
 
-// UpdateRoomStatus updates the housekeeping status of a room
-func (r *HousekeepingRepository) UpdateRoomStatus(ctx context.Context, roomID int, status string) error {
+// setStatusActor records the staff member changing room statuses in a transaction,
+// and their notes, for the room status history trigger
+func setStatusActor(ctx context.Context, tx pgx.Tx, staffID *int, notes string) error {
+	var actor string
+	if staffID != nil {
+		actor = strconv.Itoa(*staffID)
+	}
+	_, err := tx.Exec(ctx, `
+		SELECT set_config('app.staff_id', $1, true), set_config('app.status_notes', $2, true)
+	`, actor, notes)
+	if err != nil {
+		return fmt.Errorf("failed to set status actor: %w", err)
+	}
+	return nil
+}
+
+// UpdateRoomStatus updates the housekeeping status of a room on behalf of a staff
+// member. Today's assignment of the room is started when cleaning begins, completed
+// when the room is clean and reopened when the room turns dirty again.
+func (r *HousekeepingRepository) UpdateRoomStatus(ctx context.Context, roomID int, status string, staffID *int, notes string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setStatusActor(ctx, tx, staffID, notes); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE rooms
 		SET housekeeping_status = $1
 		WHERE room_id = $2
+		  AND is_active = TRUE
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query, status, roomID)
+	result, err := tx.Exec(ctx, query, status, roomID)
 	if err != nil {
 		return fmt.Errorf("failed to update room status: %w", err)
 	}
@@ -124,6 +441,28 @@ func (r *HousekeepingRepository) UpdateRoomStatus(ctx context.Context, roomID in
 		return fmt.Errorf("room not found")
 	}
 
+	_, err = tx.Exec(ctx, `
+		UPDATE housekeeping_assignments
+		SET started_at = CASE
+				WHEN $2 = 'Cleaning' THEN COALESCE(started_at, CURRENT_TIMESTAMP)
+				WHEN $2 = 'Dirty' THEN NULL
+				ELSE started_at
+			END,
+			completed_at = CASE
+				WHEN $2 = 'Clean' THEN COALESCE(completed_at, CURRENT_TIMESTAMP)
+				WHEN $2 IN ('Dirty', 'Cleaning') THEN NULL
+				ELSE completed_at
+			END
+		WHERE room_id = $1 AND task_date = CURRENT_DATE
+	`, roomID, status)
+	if err != nil {
+		return fmt.Errorf("failed to update housekeeping assignment: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -172,6 +511,7 @@ func (r *HousekeepingRepository) GetRoomsForInspection(ctx context.Context) ([]m
 		FROM rooms r
 		INNER JOIN room_types rt ON r.room_type_id = rt.room_type_id
 		WHERE r.housekeeping_status = 'Clean'
+		  AND r.is_active = TRUE
 		ORDER BY r.room_number ASC
 	`
 
@@ -202,3 +542,102 @@ func (r *HousekeepingRepository) GetRoomsForInspection(ctx context.Context) ([]m
 
 	return tasks, nil
 }
+
+// GetRoomStatusHistory retrieves the latest status changes of a room, newest first
+func (r *HousekeepingRepository) GetRoomStatusHistory(ctx context.Context, roomID, limit int) ([]models.RoomStatusUpdate, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			h.history_id,
+			h.room_id,
+			h.status_type,
+			h.old_status,
+			h.new_status,
+			h.updated_by,
+			s.first_name || ' ' || s.last_name,
+			h.updated_at,
+			COALESCE(h.notes, '')
+		FROM room_status_history h
+		LEFT JOIN staff s ON s.staff_id = h.updated_by
+		WHERE h.room_id = $1
+		ORDER BY h.updated_at DESC, h.history_id DESC
+		LIMIT $2
+	`, roomID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.RoomStatusUpdate{}
+	for rows.Next() {
+		var u models.RoomStatusUpdate
+		if err := rows.Scan(
+			&u.HistoryID,
+			&u.RoomID,
+			&u.StatusType,
+			&u.OldStatus,
+			&u.NewStatus,
+			&u.UpdatedBy,
+			&u.UpdatedByName,
+			&u.UpdatedAt,
+			&u.Notes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan room status history: %w", err)
+		}
+		history = append(history, u)
+	}
+
+	return history, rows.Err()
+}
+
+// GetCleaningTimes retrieves the rooms that went from Cleaning to Clean between two
+// dates (inclusive), with the staff member who finished them, or else the one who
+// started them
+func (r *HousekeepingRepository) GetCleaningTimes(ctx context.Context, startDate, endDate time.Time) ([]models.CleaningTime, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH transitions AS (
+			SELECT
+				h.room_id,
+				h.old_status,
+				h.new_status,
+				COALESCE(h.updated_by, LAG(h.updated_by) OVER w) as staff_id,
+				LAG(h.new_status) OVER w as previous_status,
+				LAG(h.updated_at) OVER w as started_at,
+				h.updated_at as completed_at
+			FROM room_status_history h
+			WHERE h.status_type = 'Housekeeping'
+			  AND h.updated_at >= $1::date - 1
+			  AND h.updated_at < $2::date + 1
+			WINDOW w AS (PARTITION BY h.room_id ORDER BY h.updated_at, h.history_id)
+		)
+		SELECT
+			t.staff_id,
+			s.first_name || ' ' || s.last_name,
+			t.room_id,
+			r.room_number,
+			t.started_at,
+			t.completed_at
+		FROM transitions t
+		INNER JOIN rooms r ON r.room_id = t.room_id
+		LEFT JOIN staff s ON s.staff_id = t.staff_id
+		WHERE t.old_status = 'Cleaning'
+		  AND t.new_status = 'Clean'
+		  AND t.previous_status = 'Cleaning'
+		  AND t.completed_at >= $1
+		ORDER BY t.completed_at
+	`, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaning times: %w", err)
+	}
+	defer rows.Close()
+
+	times := []models.CleaningTime{}
+	for rows.Next() {
+		var t models.CleaningTime
+		if err := rows.Scan(&t.StaffID, &t.StaffName, &t.RoomID, &t.RoomNumber, &t.StartedAt, &t.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cleaning time: %w", err)
+		}
+		times = append(times, t)
+	}
+
+	return times, rows.Err()
+}
@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
 )
 
 type InventoryRepository struct {
@@ -158,7 +160,8 @@ func (r *InventoryRepository) UpdateInventory(ctx context.Context, roomTypeID in
 			allotment = EXCLUDED.allotment,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE room_inventory.allotment >= room_inventory.booked_count + room_inventory.tentative_count
-		  OR EXCLUDED.allotment >= room_inventory.booked_count + room_inventory.tentative_count
+		  OR EXCLUDED.allotment + overbooking_rooms(EXCLUDED.allotment, room_inventory.overbooking_type, room_inventory.overbooking_value)
+		     >= room_inventory.booked_count + room_inventory.tentative_count
 	`
 
 	result, err := r.db.Pool.Exec(ctx, query, roomTypeID, date, allotment)
@@ -176,14 +179,15 @@ func (r *InventoryRepository) UpdateInventory(ctx context.Context, roomTypeID in
 
 // BulkUpdateInventory updates inventory for a date range
 func (r *InventoryRepository) BulkUpdateInventory(ctx context.Context, roomTypeID int, startDate, endDate time.Time, allotment int) ([]models.InventoryValidationError, error) {
-	// First, check which dates would violate the constraint
+	// First, check which dates would violate the constraint; rooms may still be sold up
+	// to the overbooking limit of each date
 	checkQuery := `
 		SELECT date, booked_count, tentative_count
 		FROM room_inventory
 		WHERE room_type_id = $1 
 		  AND date >= $2 
 		  AND date <= $3
-		  AND (booked_count + tentative_count) > $4
+		  AND (booked_count + tentative_count) > $4 + overbooking_rooms($4, overbooking_type, overbooking_value)
 	`
 
 	rows, err := r.db.Pool.Query(ctx, checkQuery, roomTypeID, startDate, endDate, allotment)
@@ -235,23 +239,587 @@ func (r *InventoryRepository) BulkUpdateInventory(ctx context.Context, roomTypeI
 // ValidateInventoryUpdate checks if an inventory update is valid
 func (r *InventoryRepository) ValidateInventoryUpdate(ctx context.Context, roomTypeID int, date time.Time, newAllotment int) error {
 	query := `
-		SELECT booked_count, tentative_count
+		SELECT booked_count, tentative_count, overbooking_rooms($3, overbooking_type, overbooking_value)
 		FROM room_inventory
 		WHERE room_type_id = $1 AND date = $2
 	`
 
-	var bookedCount, tentativeCount int
-	err := r.db.Pool.QueryRow(ctx, query, roomTypeID, date).Scan(&bookedCount, &tentativeCount)
+	var bookedCount, tentativeCount, overbookingRooms int
+	err := r.db.Pool.QueryRow(ctx, query, roomTypeID, date, newAllotment).Scan(&bookedCount, &tentativeCount, &overbookingRooms)
 	if err != nil {
 		// If no record exists, it's valid
 		return nil
 	}
 
 	currentBookings := bookedCount + tentativeCount
-	if newAllotment < currentBookings {
+	if newAllotment+overbookingRooms < currentBookings {
 		return fmt.Errorf("cannot reduce allotment to %d, current bookings: %d (booked: %d, tentative: %d)",
 			newAllotment, currentBookings, bookedCount, tentativeCount)
 	}
 
 	return nil
 }
+
+// outOfOrderSelect reads the columns scanned by scanOutOfOrder
+const outOfOrderSelect = `
+	SELECT
+		o.out_of_order_id, o.room_id, r.room_number, r.room_type_id, o.start_date, o.end_date,
+		o.reason, o.status, o.work_order_id, o.created_by, o.ended_at, o.created_at, o.updated_at
+	FROM room_out_of_order o
+	INNER JOIN rooms r ON r.room_id = o.room_id`
+
+func scanOutOfOrder(row pgx.Row, p *models.OutOfOrderPeriod) error {
+	return row.Scan(
+		&p.OutOfOrderID,
+		&p.RoomID,
+		&p.RoomNumber,
+		&p.RoomTypeID,
+		&p.StartDate,
+		&p.EndDate,
+		&p.Reason,
+		&p.Status,
+		&p.WorkOrderID,
+		&p.CreatedBy,
+		&p.EndedAt,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
+
+// errOutOfOrderConflict is returned when bookings taken in the meantime leave no room
+// to take off the allotment (the chk_inventory_capacity constraint)
+var errOutOfOrderConflict = errors.New("rooms were booked on some of these dates in the meantime, not enough unsold rooms are left")
+
+// GetOutOfOrderPeriods lists out-of-order periods by start date, optionally of one room.
+// Completed and cancelled periods are left out unless includeEnded is set.
+func (r *InventoryRepository) GetOutOfOrderPeriods(ctx context.Context, roomID *int, includeEnded bool) ([]models.OutOfOrderPeriod, error) {
+	query := outOfOrderSelect + `
+		WHERE ($1::int IS NULL OR o.room_id = $1)
+		  AND ($2 OR o.status IN ('Scheduled', 'Active'))
+		ORDER BY o.start_date, r.room_number
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomID, includeEnded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get out-of-order periods: %w", err)
+	}
+	defer rows.Close()
+
+	periods := []models.OutOfOrderPeriod{}
+	for rows.Next() {
+		var p models.OutOfOrderPeriod
+		if err := scanOutOfOrder(rows, &p); err != nil {
+			return nil, fmt.Errorf("failed to scan out-of-order period: %w", err)
+		}
+		periods = append(periods, p)
+	}
+
+	return periods, rows.Err()
+}
+
+// GetOutOfOrderByID retrieves an out-of-order period by ID
+func (r *InventoryRepository) GetOutOfOrderByID(ctx context.Context, outOfOrderID int) (*models.OutOfOrderPeriod, error) {
+	var p models.OutOfOrderPeriod
+	err := scanOutOfOrder(r.db.Pool.QueryRow(ctx, outOfOrderSelect+` WHERE o.out_of_order_id = $1`, outOfOrderID), &p)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get out-of-order period: %w", err)
+	}
+	return &p, nil
+}
+
+// GetInventoryForDates returns the inventory of a room type for every date of a range,
+// using the default allotment for dates that have no inventory yet
+func (r *InventoryRepository) GetInventoryForDates(ctx context.Context, roomTypeID int, startDate, endDate time.Time) ([]models.RoomInventory, error) {
+	query := `
+		SELECT
+			rt.room_type_id,
+			d::date,
+			COALESCE(ri.allotment, rt.default_allotment),
+			COALESCE(ri.booked_count, 0),
+			COALESCE(ri.tentative_count, 0)
+		FROM room_types rt
+		CROSS JOIN generate_series($2::date, $3::date, '1 day'::interval) AS d
+		LEFT JOIN room_inventory ri ON ri.room_type_id = rt.room_type_id AND ri.date = d::date
+		WHERE rt.room_type_id = $1
+		ORDER BY d
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+	defer rows.Close()
+
+	var inventory []models.RoomInventory
+	for rows.Next() {
+		var inv models.RoomInventory
+		if err := rows.Scan(&inv.RoomTypeID, &inv.Date, &inv.Allotment, &inv.BookedCount, &inv.TentativeCount); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory: %w", err)
+		}
+		inv.Available = inv.Allotment - inv.BookedCount - inv.TentativeCount
+		inventory = append(inventory, inv)
+	}
+
+	return inventory, rows.Err()
+}
+
+// adjustOutOfOrderInventory takes one room off (delta -1) or gives one room back to
+// (delta 1) the allotment of a room type on each date of a range
+func adjustOutOfOrderInventory(ctx context.Context, tx pgx.Tx, roomTypeID int, startDate, endDate time.Time, delta int) error {
+	if endDate.Before(startDate) {
+		return nil
+	}
+
+	var err error
+	if delta < 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO room_inventory (room_type_id, date, allotment)
+			SELECT rt.room_type_id, d::date, rt.default_allotment + $4
+			FROM room_types rt
+			CROSS JOIN generate_series($2::date, $3::date, '1 day'::interval) AS d
+			WHERE rt.room_type_id = $1
+			ON CONFLICT (room_type_id, date)
+			DO UPDATE SET
+				allotment = room_inventory.allotment + $4,
+				updated_at = CURRENT_TIMESTAMP
+		`, roomTypeID, startDate, endDate, delta)
+	} else {
+		_, err = tx.Exec(ctx, `
+			UPDATE room_inventory
+			SET allotment = allotment + $4, updated_at = CURRENT_TIMESTAMP
+			WHERE room_type_id = $1 AND date >= $2 AND date <= $3
+		`, roomTypeID, startDate, endDate, delta)
+	}
+	if pgErrorCode(err) == pgCheckViolation {
+		return errOutOfOrderConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+	return nil
+}
+
+// lockOutOfOrder locks a scheduled or active out-of-order period
+func lockOutOfOrder(ctx context.Context, tx pgx.Tx, outOfOrderID int) (*models.OutOfOrderPeriod, error) {
+	var p models.OutOfOrderPeriod
+	err := scanOutOfOrder(tx.QueryRow(ctx, outOfOrderSelect+`
+		WHERE o.out_of_order_id = $1 AND o.status IN ('Scheduled', 'Active')
+		FOR UPDATE OF o
+	`, outOfOrderID), &p)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock out-of-order period: %w", err)
+	}
+	return &p, nil
+}
+
+// checkOutOfOrderOverlap fails when another scheduled or active period of the room
+// covers any night of a range
+func checkOutOfOrderOverlap(ctx context.Context, tx pgx.Tx, roomID, exceptID int, startDate, endDate time.Time) error {
+	var overlaps bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM room_out_of_order
+			WHERE room_id = $1
+			  AND out_of_order_id <> $2
+			  AND status IN ('Scheduled', 'Active')
+			  AND start_date <= $4
+			  AND end_date >= $3
+		)
+	`, roomID, exceptID, startDate, endDate).Scan(&overlaps)
+	if err != nil {
+		return fmt.Errorf("failed to check out-of-order periods: %w", err)
+	}
+	if overlaps {
+		return errors.New("room already has an out-of-order period on some of these dates")
+	}
+	return nil
+}
+
+// CreateOutOfOrder schedules an out-of-order period and takes the room off the room
+// type's allotment for each of its nights. A period starting today takes the room out
+// of service at once. It returns nil when the room does not exist or is retired.
+func (r *InventoryRepository) CreateOutOfOrder(ctx context.Context, p *models.OutOfOrderPeriod) (*models.OutOfOrderPeriod, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var roomTypeID int
+	err = tx.QueryRow(ctx, `
+		SELECT room_type_id FROM rooms WHERE room_id = $1 AND is_active = TRUE FOR UPDATE
+	`, p.RoomID).Scan(&roomTypeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock room: %w", err)
+	}
+
+	if err := checkOutOfOrderOverlap(ctx, tx, p.RoomID, 0, p.StartDate, p.EndDate); err != nil {
+		return nil, err
+	}
+
+	// A guest in the room must have left before the first night
+	var checkOut time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT bd.check_out_date
+		FROM room_assignments ra
+		INNER JOIN booking_details bd ON bd.booking_detail_id = ra.booking_detail_id
+		WHERE ra.room_id = $1 AND ra.status = 'Active' AND bd.check_out_date > $2
+		ORDER BY bd.check_out_date DESC
+		LIMIT 1
+	`, p.RoomID, p.StartDate).Scan(&checkOut)
+	if err == nil {
+		return nil, fmt.Errorf("room is occupied until %s", checkOut.Format("2006-01-02"))
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check room occupancy: %w", err)
+	}
+
+	var outOfOrderID int
+	var status string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO room_out_of_order (room_id, start_date, end_date, reason, status, work_order_id, created_by)
+		VALUES ($1, $2, $3, $4,
+			CASE WHEN $2::date <= CURRENT_DATE THEN 'Active' ELSE 'Scheduled' END,
+			$5, $6)
+		RETURNING out_of_order_id, status
+	`, p.RoomID, p.StartDate, p.EndDate, p.Reason, p.WorkOrderID, p.CreatedBy).Scan(&outOfOrderID, &status)
+	if pgErrorCode(err) == pgForeignKeyViolation {
+		return nil, errors.New("work order not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create out-of-order period: %w", err)
+	}
+
+	if err := adjustOutOfOrderInventory(ctx, tx, roomTypeID, p.StartDate, p.EndDate, -1); err != nil {
+		return nil, err
+	}
+
+	if status == models.OutOfOrderActive {
+		if err := setStatusActor(ctx, tx, p.CreatedBy, "Out of order: "+p.Reason); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE rooms SET housekeeping_status = 'OutOfService' WHERE room_id = $1
+		`, p.RoomID); err != nil {
+			return nil, fmt.Errorf("failed to update room status: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetOutOfOrderByID(ctx, outOfOrderID)
+}
+
+// UpdateOutOfOrderEndDate extends or shortens a scheduled or active period, taking
+// rooms off or giving them back to the allotment for the nights added or removed.
+// It returns false when the period has already ended.
+func (r *InventoryRepository) UpdateOutOfOrderEndDate(ctx context.Context, outOfOrderID int, endDate time.Time) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	p, err := lockOutOfOrder(ctx, tx, outOfOrderID)
+	if err != nil {
+		return false, err
+	}
+	if p == nil {
+		return false, nil
+	}
+
+	switch {
+	case endDate.After(p.EndDate):
+		if err := checkOutOfOrderOverlap(ctx, tx, p.RoomID, p.OutOfOrderID, p.EndDate.AddDate(0, 0, 1), endDate); err != nil {
+			return false, err
+		}
+		if err := adjustOutOfOrderInventory(ctx, tx, p.RoomTypeID, p.EndDate.AddDate(0, 0, 1), endDate, -1); err != nil {
+			return false, err
+		}
+	case endDate.Before(p.EndDate):
+		if err := adjustOutOfOrderInventory(ctx, tx, p.RoomTypeID, endDate.AddDate(0, 0, 1), p.EndDate, 1); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE room_out_of_order SET end_date = $2 WHERE out_of_order_id = $1
+	`, outOfOrderID, endDate); err != nil {
+		return false, fmt.Errorf("failed to update out-of-order period: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// EndOutOfOrder cancels a scheduled period or returns the room of an active one to
+// service today, giving the remaining nights back to the allotment. It returns false
+// when the period has already ended.
+func (r *InventoryRepository) EndOutOfOrder(ctx context.Context, outOfOrderID int, today time.Time, endedBy *int) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	p, err := lockOutOfOrder(ctx, tx, outOfOrderID)
+	if err != nil {
+		return false, err
+	}
+	if p == nil {
+		return false, nil
+	}
+
+	from := p.StartDate
+	if from.Before(today) {
+		from = today
+	}
+	if err := adjustOutOfOrderInventory(ctx, tx, p.RoomTypeID, from, p.EndDate, 1); err != nil {
+		return false, err
+	}
+
+	// An active period ends with its last night yesterday, or on its first night when
+	// it started today
+	if _, err := tx.Exec(ctx, `
+		UPDATE room_out_of_order
+		SET status = CASE WHEN status = 'Scheduled' THEN 'Cancelled' ELSE 'Completed' END,
+		    end_date = CASE WHEN status = 'Scheduled' THEN end_date ELSE GREATEST(start_date, $2::date - 1) END,
+		    ended_at = CURRENT_TIMESTAMP
+		WHERE out_of_order_id = $1
+	`, outOfOrderID, today); err != nil {
+		return false, fmt.Errorf("failed to end out-of-order period: %w", err)
+	}
+
+	if err := setStatusActor(ctx, tx, endedBy, "Out of order ended"); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `SELECT return_room_from_out_of_order($1)`, p.RoomID); err != nil {
+		return false, fmt.Errorf("failed to return room to service: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetOverbookingPositions returns, for every date of a range, the allotment, overbooking
+// limit and rooms sold of each room type (or one room type), with the rooms in service
+// and the guests walked on the date. Dates without inventory use the default allotment.
+func (r *InventoryRepository) GetOverbookingPositions(ctx context.Context, roomTypeID *int, startDate, endDate time.Time) ([]models.OverbookingPosition, error) {
+	query := `
+		SELECT
+			rt.room_type_id,
+			rt.name,
+			d::date,
+			COALESCE(ri.allotment, rt.default_allotment),
+			ri.overbooking_type,
+			COALESCE(ri.overbooking_value, 0),
+			COALESCE(overbooking_rooms(ri.allotment, ri.overbooking_type, ri.overbooking_value), 0),
+			COALESCE(ri.booked_count, 0),
+			COALESCE(ri.tentative_count, 0),
+			(
+				SELECT COUNT(*) FROM rooms r
+				WHERE r.room_type_id = rt.room_type_id
+				  AND r.is_active = TRUE
+				  AND NOT EXISTS (
+					SELECT 1 FROM room_out_of_order o
+					WHERE o.room_id = r.room_id
+					  AND o.status IN ('Scheduled', 'Active')
+					  AND d::date BETWEEN o.start_date AND o.end_date
+				  )
+			),
+			(
+				SELECT COUNT(*) FROM booking_walks w
+				JOIN booking_details bd ON w.booking_detail_id = bd.booking_detail_id
+				WHERE bd.room_type_id = rt.room_type_id AND w.walk_date = d::date
+			)
+		FROM room_types rt
+		CROSS JOIN generate_series($2::date, $3::date, '1 day'::interval) AS d
+		LEFT JOIN room_inventory ri ON ri.room_type_id = rt.room_type_id AND ri.date = d::date
+		WHERE ($1::int IS NULL OR rt.room_type_id = $1)
+		ORDER BY d, rt.name
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overbooking positions: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []models.OverbookingPosition
+	for rows.Next() {
+		var p models.OverbookingPosition
+		err := rows.Scan(
+			&p.RoomTypeID,
+			&p.RoomTypeName,
+			&p.Date,
+			&p.Allotment,
+			&p.LimitType,
+			&p.LimitValue,
+			&p.OverbookingRooms,
+			&p.BookedCount,
+			&p.TentativeCount,
+			&p.RoomsInService,
+			&p.Walks,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan overbooking position: %w", err)
+		}
+		positions = append(positions, p)
+	}
+
+	return positions, rows.Err()
+}
+
+// SetOverbookingLimit sets the overbooking limit of a room type on each date of a range;
+// a nil limitType stops overbooking. When rooms already sold beyond the new limit on some
+// dates nothing is changed and those dates are returned.
+func (r *InventoryRepository) SetOverbookingLimit(ctx context.Context, roomTypeID int, startDate, endDate time.Time, limitType *string, limitValue float64) ([]models.InventoryValidationError, error) {
+	checkQuery := `
+		SELECT date, allotment, booked_count, tentative_count,
+		       allotment + overbooking_rooms(allotment, $4::varchar, $5::decimal)
+		FROM room_inventory
+		WHERE room_type_id = $1
+		  AND date >= $2
+		  AND date <= $3
+		  AND booked_count + tentative_count > allotment + overbooking_rooms(allotment, $4::varchar, $5::decimal)
+		ORDER BY date
+	`
+
+	rows, err := r.db.Pool.Query(ctx, checkQuery, roomTypeID, startDate, endDate, limitType, limitValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check inventory constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var validationErrors []models.InventoryValidationError
+	for rows.Next() {
+		var date time.Time
+		var allotment, bookedCount, tentativeCount, capacity int
+		if err := rows.Scan(&date, &allotment, &bookedCount, &tentativeCount, &capacity); err != nil {
+			return nil, fmt.Errorf("failed to scan validation error: %w", err)
+		}
+
+		validationErrors = append(validationErrors, models.InventoryValidationError{
+			Date: date.Format("2006-01-02"),
+			Message: fmt.Sprintf("Cannot lower the limit to %d rooms (allotment %d). Current bookings: %d (booked: %d, tentative: %d)",
+				capacity, allotment, bookedCount+tentativeCount, bookedCount, tentativeCount),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check inventory constraints: %w", err)
+	}
+	if len(validationErrors) > 0 {
+		return validationErrors, nil
+	}
+
+	_, err = r.db.Pool.Exec(ctx, `
+		INSERT INTO room_inventory (room_type_id, date, allotment, overbooking_type, overbooking_value)
+		SELECT rt.room_type_id, d::date, rt.default_allotment, $4::varchar, $5::decimal
+		FROM room_types rt
+		CROSS JOIN generate_series($2::date, $3::date, '1 day'::interval) AS d
+		WHERE rt.room_type_id = $1
+		ON CONFLICT (room_type_id, date)
+		DO UPDATE SET
+			overbooking_type = EXCLUDED.overbooking_type,
+			overbooking_value = EXCLUDED.overbooking_value,
+			updated_at = CURRENT_TIMESTAMP
+	`, roomTypeID, startDate, endDate, limitType, limitValue)
+	if pgErrorCode(err) == pgCheckViolation {
+		return nil, errors.New("rooms were booked on some of these dates in the meantime; try again")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set overbooking limit: %w", err)
+	}
+
+	return nil, nil
+}
+
+// partnerHotelSelect reads the columns scanned by scanPartnerHotel
+const partnerHotelSelect = `
+	SELECT partner_hotel_id, name, address, phone, contact_name, notes, is_active, created_at, updated_at
+	FROM partner_hotels`
+
+func scanPartnerHotel(row pgx.Row, h *models.PartnerHotel) error {
+	return row.Scan(
+		&h.PartnerHotelID,
+		&h.Name,
+		&h.Address,
+		&h.Phone,
+		&h.ContactName,
+		&h.Notes,
+		&h.IsActive,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+}
+
+// GetPartnerHotels lists partner hotels by name; inactive ones are left out unless
+// includeInactive is set
+func (r *InventoryRepository) GetPartnerHotels(ctx context.Context, includeInactive bool) ([]models.PartnerHotel, error) {
+	rows, err := r.db.Pool.Query(ctx, partnerHotelSelect+`
+		WHERE $1 OR is_active = TRUE
+		ORDER BY name
+	`, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner hotels: %w", err)
+	}
+	defer rows.Close()
+
+	var hotels []models.PartnerHotel
+	for rows.Next() {
+		var h models.PartnerHotel
+		if err := scanPartnerHotel(rows, &h); err != nil {
+			return nil, fmt.Errorf("failed to scan partner hotel: %w", err)
+		}
+		hotels = append(hotels, h)
+	}
+	return hotels, rows.Err()
+}
+
+// CreatePartnerHotel creates a partner hotel
+func (r *InventoryRepository) CreatePartnerHotel(ctx context.Context, h *models.PartnerHotel) (*models.PartnerHotel, error) {
+	var created models.PartnerHotel
+	err := scanPartnerHotel(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO partner_hotels (name, address, phone, contact_name, notes, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING partner_hotel_id, name, address, phone, contact_name, notes, is_active, created_at, updated_at
+	`, h.Name, h.Address, h.Phone, h.ContactName, h.Notes, h.IsActive), &created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create partner hotel: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdatePartnerHotel updates a partner hotel. It returns nil when the hotel does not
+// exist.
+func (r *InventoryRepository) UpdatePartnerHotel(ctx context.Context, h *models.PartnerHotel) (*models.PartnerHotel, error) {
+	var updated models.PartnerHotel
+	err := scanPartnerHotel(r.db.Pool.QueryRow(ctx, `
+		UPDATE partner_hotels
+		SET name = $2, address = $3, phone = $4, contact_name = $5, notes = $6, is_active = $7
+		WHERE partner_hotel_id = $1
+		RETURNING partner_hotel_id, name, address, phone, contact_name, notes, is_active, created_at, updated_at
+	`, h.PartnerHotelID, h.Name, h.Address, h.Phone, h.ContactName, h.Notes, h.IsActive), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update partner hotel: %w", err)
+	}
+	return &updated, nil
+}
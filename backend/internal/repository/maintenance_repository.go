@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// MaintenanceRepository handles maintenance work order database operations
+type MaintenanceRepository struct {
+	db *database.DB
+}
+
+// NewMaintenanceRepository creates a new maintenance repository
+func NewMaintenanceRepository(db *database.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+// workOrderSelect reads the columns scanned by scanWorkOrder
+const workOrderSelect = `
+	SELECT
+		wo.work_order_id, wo.room_id, r.room_number, wo.category, wo.priority, wo.status,
+		wo.description, wo.photo_keys, wo.reported_by, wo.assigned_to,
+		CASE WHEN s.staff_id IS NULL THEN NULL ELSE s.first_name || ' ' || s.last_name END,
+		wo.resolution_notes, wo.reported_at, wo.started_at, wo.resolved_at, wo.updated_at
+	FROM maintenance_work_orders wo
+	INNER JOIN rooms r ON r.room_id = wo.room_id
+	LEFT JOIN staff s ON s.staff_id = wo.assigned_to`
+
+func scanWorkOrder(row pgx.Row, wo *models.WorkOrder) error {
+	return row.Scan(
+		&wo.WorkOrderID,
+		&wo.RoomID,
+		&wo.RoomNumber,
+		&wo.Category,
+		&wo.Priority,
+		&wo.Status,
+		&wo.Description,
+		&wo.PhotoKeys,
+		&wo.ReportedBy,
+		&wo.AssignedTo,
+		&wo.AssigneeName,
+		&wo.ResolutionNotes,
+		&wo.ReportedAt,
+		&wo.StartedAt,
+		&wo.ResolvedAt,
+		&wo.UpdatedAt,
+	)
+}
+
+// lockRoom locks a room row so work order changes for the room apply one at a time.
+// It returns the room's housekeeping status, or "" when there is no such active room.
+func lockRoom(ctx context.Context, tx pgx.Tx, roomID int) (string, error) {
+	var status string
+	err := tx.QueryRow(ctx, `
+		SELECT housekeeping_status FROM rooms WHERE room_id = $1 AND is_active = TRUE FOR UPDATE
+	`, roomID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to lock room: %w", err)
+	}
+	return status, nil
+}
+
+// CreateWorkOrder opens a work order and marks its room MaintenanceRequired unless the
+// room is out of service. It returns nil when the room does not exist.
+func (r *MaintenanceRepository) CreateWorkOrder(ctx context.Context, wo *models.WorkOrder) (*models.WorkOrder, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	roomStatus, err := lockRoom(ctx, tx, wo.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if roomStatus == "" {
+		return nil, nil
+	}
+
+	var workOrderID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO maintenance_work_orders (room_id, category, priority, description, reported_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING work_order_id
+	`, wo.RoomID, wo.Category, wo.Priority, wo.Description, wo.ReportedBy).Scan(&workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work order: %w", err)
+	}
+
+	if roomStatus != "MaintenanceRequired" && roomStatus != "OutOfService" {
+		if err := setStatusActor(ctx, tx, wo.ReportedBy, fmt.Sprintf("Work order #%d: %s", workOrderID, wo.Description)); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE rooms SET housekeeping_status = 'MaintenanceRequired' WHERE room_id = $1
+		`, wo.RoomID); err != nil {
+			return nil, fmt.Errorf("failed to update room status: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetWorkOrderByID(ctx, workOrderID)
+}
+
+// GetWorkOrderByID retrieves a work order by ID
+func (r *MaintenanceRepository) GetWorkOrderByID(ctx context.Context, workOrderID int) (*models.WorkOrder, error) {
+	var wo models.WorkOrder
+	err := scanWorkOrder(r.db.Pool.QueryRow(ctx, workOrderSelect+` WHERE wo.work_order_id = $1`, workOrderID), &wo)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work order: %w", err)
+	}
+	return &wo, nil
+}
+
+// GetWorkOrders lists work orders, most urgent and then oldest first
+func (r *MaintenanceRepository) GetWorkOrders(ctx context.Context, filter models.WorkOrderFilter) ([]models.WorkOrder, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Status != "" {
+		addCondition("wo.status = $%d", filter.Status)
+	}
+	if filter.RoomID != nil {
+		addCondition("wo.room_id = $%d", *filter.RoomID)
+	}
+	if filter.AssignedTo != nil {
+		addCondition("wo.assigned_to = $%d", *filter.AssignedTo)
+	}
+	if filter.Unassigned {
+		conditions = append(conditions, "wo.assigned_to IS NULL")
+	}
+	if filter.OpenOnly {
+		conditions = append(conditions, "wo.status <> 'Resolved'")
+	}
+
+	query := workOrderSelect
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += `
+		ORDER BY
+			CASE wo.priority WHEN 'Urgent' THEN 1 WHEN 'High' THEN 2 WHEN 'Medium' THEN 3 ELSE 4 END,
+			wo.reported_at, wo.work_order_id`
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work orders: %w", err)
+	}
+	defer rows.Close()
+
+	workOrders := []models.WorkOrder{}
+	for rows.Next() {
+		var wo models.WorkOrder
+		if err := scanWorkOrder(rows, &wo); err != nil {
+			return nil, fmt.Errorf("failed to scan work order: %w", err)
+		}
+		workOrders = append(workOrders, wo)
+	}
+
+	return workOrders, rows.Err()
+}
+
+// UpdateWorkOrder saves the category, priority, description and assignee of a work order
+func (r *MaintenanceRepository) UpdateWorkOrder(ctx context.Context, wo *models.WorkOrder) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `
+		UPDATE maintenance_work_orders
+		SET category = $2, priority = $3, description = $4, assigned_to = $5
+		WHERE work_order_id = $1
+	`, wo.WorkOrderID, wo.Category, wo.Priority, wo.Description, wo.AssignedTo)
+	if err != nil {
+		return false, fmt.Errorf("failed to update work order: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// UpdateWorkOrderStatus moves a work order from previousStatus to wo.Status, saving its
+// assignee and resolution notes. Resolving the room's last unresolved work order returns
+// a MaintenanceRequired room to Dirty for cleaning; reopening a resolved work order marks
+// the room MaintenanceRequired again unless it is out of service. It returns false when
+// the work order is no longer in previousStatus. Room status changes are recorded as
+// made by changedBy.
+func (r *MaintenanceRepository) UpdateWorkOrderStatus(ctx context.Context, wo *models.WorkOrder, previousStatus string, changedBy *int) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the room first so two work orders of one room are never resolved side by side
+	if _, err := lockRoom(ctx, tx, wo.RoomID); err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE maintenance_work_orders
+		SET status = $2,
+		    assigned_to = $3,
+		    resolution_notes = $4,
+		    started_at = CASE WHEN $2 = 'InProgress' THEN COALESCE(started_at, CURRENT_TIMESTAMP) ELSE started_at END,
+		    resolved_at = CASE WHEN $2 = 'Resolved' THEN CURRENT_TIMESTAMP ELSE NULL END
+		WHERE work_order_id = $1 AND status = $5
+	`, wo.WorkOrderID, wo.Status, wo.AssignedTo, wo.ResolutionNotes, previousStatus)
+	if err != nil {
+		return false, fmt.Errorf("failed to update work order status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := setStatusActor(ctx, tx, changedBy, fmt.Sprintf("Work order #%d %s", wo.WorkOrderID, strings.ToLower(wo.Status))); err != nil {
+		return false, err
+	}
+
+	switch {
+	case wo.Status == models.WorkOrderResolved:
+		_, err = tx.Exec(ctx, `
+			UPDATE rooms
+			SET housekeeping_status = 'Dirty'
+			WHERE room_id = $1
+			  AND housekeeping_status = 'MaintenanceRequired'
+			  AND NOT EXISTS (
+			      SELECT 1 FROM maintenance_work_orders
+			      WHERE room_id = $1 AND status <> 'Resolved'
+			  )
+		`, wo.RoomID)
+	case previousStatus == models.WorkOrderResolved:
+		_, err = tx.Exec(ctx, `
+			UPDATE rooms
+			SET housekeeping_status = 'MaintenanceRequired'
+			WHERE room_id = $1 AND housekeeping_status <> 'OutOfService'
+		`, wo.RoomID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update room status: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// AddWorkOrderPhoto attaches a stored photo to a work order
+func (r *MaintenanceRepository) AddWorkOrderPhoto(ctx context.Context, workOrderID int, key string) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `
+		UPDATE maintenance_work_orders
+		SET photo_keys = CASE WHEN $2 = ANY(photo_keys) THEN photo_keys ELSE array_append(photo_keys, $2) END
+		WHERE work_order_id = $1
+	`, workOrderID, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to add work order photo: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// GetActiveStaffRole returns the role code of an active staff member, or "" when
+// there is no such staff member
+func (r *MaintenanceRepository) GetActiveStaffRole(ctx context.Context, staffID int) (string, error) {
+	var roleCode string
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT ro.role_code
+		FROM staff s
+		INNER JOIN roles ro ON ro.role_id = s.role_id
+		WHERE s.staff_id = $1 AND s.is_active = TRUE
+	`, staffID).Scan(&roleCode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get staff role: %w", err)
+	}
+	return roleCode, nil
+}
+
+// GetRepairTimes retrieves the work orders resolved between two dates (inclusive)
+func (r *MaintenanceRepository) GetRepairTimes(ctx context.Context, startDate, endDate time.Time) ([]models.RepairTime, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT wo.room_id, r.room_number, wo.reported_at, wo.resolved_at
+		FROM maintenance_work_orders wo
+		INNER JOIN rooms r ON r.room_id = wo.room_id
+		WHERE wo.status = 'Resolved'
+		  AND wo.resolved_at >= $1
+		  AND wo.resolved_at < $2::date + 1
+		ORDER BY r.room_number, wo.resolved_at
+	`, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair times: %w", err)
+	}
+	defer rows.Close()
+
+	var times []models.RepairTime
+	for rows.Next() {
+		var rt models.RepairTime
+		if err := rows.Scan(&rt.RoomID, &rt.RoomNumber, &rt.ReportedAt, &rt.ResolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan repair time: %w", err)
+		}
+		times = append(times, rt)
+	}
+
+	return times, rows.Err()
+}
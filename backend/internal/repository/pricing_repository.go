@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
 )
 
 type PricingRepository struct {
@@ -401,13 +403,47 @@ func (r *PricingRepository) BulkUpdateRatePricing(ctx context.Context, req *mode
 	return nil
 }
 
+// ratePlanColumns lists the rate plan columns read by scanRatePlan
+const ratePlanColumns = `
+	rate_plan_id, name, description, policy_id, is_active,
+	min_advance_days, max_advance_days, stay_start_date, stay_end_date,
+	booking_start_date, booking_end_date, arrival_days_of_week, min_guests, min_nights, members_only,
+	base_rate_plan_id, derived_adjustment_type, derived_adjustment_value,
+	is_day_use, billing_cycle, housekeeping_frequency_days, created_at, updated_at
+`
+
+// scanRatePlan scans a row selected with ratePlanColumns
+func scanRatePlan(row pgx.Row, plan *models.RatePlan) error {
+	return row.Scan(
+		&plan.RatePlanID,
+		&plan.Name,
+		&plan.Description,
+		&plan.PolicyID,
+		&plan.IsActive,
+		&plan.MinAdvanceDays,
+		&plan.MaxAdvanceDays,
+		&plan.StayStartDate,
+		&plan.StayEndDate,
+		&plan.BookingStartDate,
+		&plan.BookingEndDate,
+		&plan.ArrivalDaysOfWeek,
+		&plan.MinGuests,
+		&plan.MinNights,
+		&plan.MembersOnly,
+		&plan.BaseRatePlanID,
+		&plan.DerivedAdjustmentType,
+		&plan.DerivedAdjustmentValue,
+		&plan.IsDayUse,
+		&plan.BillingCycle,
+		&plan.HousekeepingFrequency,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	)
+}
+
 // GetAllRatePlans retrieves all rate plans
 func (r *PricingRepository) GetAllRatePlans(ctx context.Context) ([]models.RatePlan, error) {
-	query := `
-		SELECT rate_plan_id, name, description, policy_id, is_active, created_at, updated_at
-		FROM rate_plans
-		ORDER BY name
-	`
+	query := `SELECT ` + ratePlanColumns + ` FROM rate_plans ORDER BY name`
 
 	rows, err := r.db.Pool.Query(ctx, query)
 	if err != nil {
@@ -418,16 +454,7 @@ func (r *PricingRepository) GetAllRatePlans(ctx context.Context) ([]models.RateP
 	var plans []models.RatePlan
 	for rows.Next() {
 		var plan models.RatePlan
-		err := rows.Scan(
-			&plan.RatePlanID,
-			&plan.Name,
-			&plan.Description,
-			&plan.PolicyID,
-			&plan.IsActive,
-			&plan.CreatedAt,
-			&plan.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanRatePlan(rows, &plan); err != nil {
 			return nil, fmt.Errorf("failed to scan rate plan: %w", err)
 		}
 		plans = append(plans, plan)
@@ -435,3 +462,877 @@ func (r *PricingRepository) GetAllRatePlans(ctx context.Context) ([]models.RateP
 
 	return plans, nil
 }
+
+// GetRatePlanByID retrieves a rate plan by ID
+func (r *PricingRepository) GetRatePlanByID(ctx context.Context, id int) (*models.RatePlan, error) {
+	query := `SELECT ` + ratePlanColumns + ` FROM rate_plans WHERE rate_plan_id = $1`
+
+	var plan models.RatePlan
+	if err := scanRatePlan(r.db.Pool.QueryRow(ctx, query, id), &plan); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get rate plan: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// CreateRatePlan creates a rate plan; derived prices are generated by trigger
+func (r *PricingRepository) CreateRatePlan(ctx context.Context, plan *models.RatePlan) (*models.RatePlan, error) {
+	query := `
+		INSERT INTO rate_plans (name, description, policy_id, is_active,
+			min_advance_days, max_advance_days, stay_start_date, stay_end_date,
+			booking_start_date, booking_end_date, arrival_days_of_week, min_guests, members_only,
+			base_rate_plan_id, derived_adjustment_type, derived_adjustment_value, is_day_use,
+			min_nights, billing_cycle, housekeeping_frequency_days)
+		VALUES ($1, $2, $3, TRUE, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING ` + ratePlanColumns
+
+	var created models.RatePlan
+	err := scanRatePlan(r.db.Pool.QueryRow(ctx, query,
+		plan.Name,
+		plan.Description,
+		plan.PolicyID,
+		plan.MinAdvanceDays,
+		plan.MaxAdvanceDays,
+		plan.StayStartDate,
+		plan.StayEndDate,
+		plan.BookingStartDate,
+		plan.BookingEndDate,
+		plan.ArrivalDaysOfWeek,
+		plan.MinGuests,
+		plan.MembersOnly,
+		plan.BaseRatePlanID,
+		plan.DerivedAdjustmentType,
+		plan.DerivedAdjustmentValue,
+		plan.IsDayUse,
+		plan.MinNights,
+		plan.BillingCycle,
+		plan.HousekeepingFrequency,
+	), &created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate plan: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateRatePlan saves every column of a rate plan
+func (r *PricingRepository) UpdateRatePlan(ctx context.Context, plan *models.RatePlan) (*models.RatePlan, error) {
+	query := `
+		UPDATE rate_plans SET
+			name = $2,
+			description = $3,
+			policy_id = $4,
+			is_active = $5,
+			min_advance_days = $6,
+			max_advance_days = $7,
+			stay_start_date = $8,
+			stay_end_date = $9,
+			booking_start_date = $10,
+			booking_end_date = $11,
+			arrival_days_of_week = $12,
+			min_guests = $13,
+			members_only = $14,
+			base_rate_plan_id = $15,
+			derived_adjustment_type = $16,
+			derived_adjustment_value = $17,
+			min_nights = $18,
+			billing_cycle = $19,
+			housekeeping_frequency_days = $20,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE rate_plan_id = $1
+		RETURNING ` + ratePlanColumns
+
+	var updated models.RatePlan
+	err := scanRatePlan(r.db.Pool.QueryRow(ctx, query,
+		plan.RatePlanID,
+		plan.Name,
+		plan.Description,
+		plan.PolicyID,
+		plan.IsActive,
+		plan.MinAdvanceDays,
+		plan.MaxAdvanceDays,
+		plan.StayStartDate,
+		plan.StayEndDate,
+		plan.BookingStartDate,
+		plan.BookingEndDate,
+		plan.ArrivalDaysOfWeek,
+		plan.MinGuests,
+		plan.MembersOnly,
+		plan.BaseRatePlanID,
+		plan.DerivedAdjustmentType,
+		plan.DerivedAdjustmentValue,
+		plan.MinNights,
+		plan.BillingCycle,
+		plan.HousekeepingFrequency,
+	), &updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rate plan: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// CountDerivedRatePlans counts the plans priced from the given base plan
+func (r *PricingRepository) CountDerivedRatePlans(ctx context.Context, baseRatePlanID int) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM rate_plans WHERE base_rate_plan_id = $1`, baseRatePlanID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count derived rate plans: %w", err)
+	}
+
+	return count, nil
+}
+
+// ============================================================================
+// Rate Restriction Methods
+// ============================================================================
+
+// restrictionTargetCTE expands room types x dates (filtered by day of week) for bulk restriction updates.
+// Parameters: $1 room type IDs, $2 start date, $3 end date, $4 days of week (NULL = every day)
+const restrictionTargetCTE = `
+	WITH target AS (
+		SELECT rt_id AS room_type_id, d::date AS date
+		FROM unnest($1::int[]) AS rt_id
+		CROSS JOIN generate_series($2::date, $3::date, '1 day'::interval) AS d
+		WHERE $4::int[] IS NULL OR EXTRACT(DOW FROM d)::int = ANY($4::int[])
+	)
+`
+
+// GetRateRestrictions retrieves restrictions for a date range, optionally for one room type
+func (r *PricingRepository) GetRateRestrictions(ctx context.Context, startDate, endDate time.Time, roomTypeID *int) ([]models.RateRestriction, error) {
+	query := `
+		SELECT rr.restriction_id, rr.room_type_id, rt.name, rr.rate_plan_id, rp.name, rr.date,
+		       rr.min_los, rr.max_los, rr.closed_to_arrival, rr.closed_to_departure, rr.stop_sell,
+		       rr.notes, rr.created_at, rr.updated_at
+		FROM rate_restrictions rr
+		JOIN room_types rt ON rr.room_type_id = rt.room_type_id
+		LEFT JOIN rate_plans rp ON rr.rate_plan_id = rp.rate_plan_id
+		WHERE rr.date >= $1 AND rr.date <= $2
+		  AND ($3::int IS NULL OR rr.room_type_id = $3)
+		ORDER BY rr.date, rt.name, rr.rate_plan_id NULLS FIRST
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, startDate, endDate, roomTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rate restrictions: %w", err)
+	}
+	defer rows.Close()
+
+	var restrictions []models.RateRestriction
+	for rows.Next() {
+		var rr models.RateRestriction
+		err := rows.Scan(
+			&rr.RestrictionID,
+			&rr.RoomTypeID,
+			&rr.RoomTypeName,
+			&rr.RatePlanID,
+			&rr.RatePlanName,
+			&rr.Date,
+			&rr.MinLOS,
+			&rr.MaxLOS,
+			&rr.ClosedToArrival,
+			&rr.ClosedToDeparture,
+			&rr.StopSell,
+			&rr.Notes,
+			&rr.CreatedAt,
+			&rr.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rate restriction: %w", err)
+		}
+		restrictions = append(restrictions, rr)
+	}
+
+	return restrictions, nil
+}
+
+// BulkUpdateRestrictions merges the provided restriction fields into every room type and date
+// in the range. Dates where the merged min stay would exceed the max stay are returned as
+// validation errors and nothing is written. Rows left without any restriction are removed.
+func (r *PricingRepository) BulkUpdateRestrictions(ctx context.Context, req *models.BulkUpdateRestrictionsRequest, startDate, endDate time.Time) ([]models.RestrictionValidationError, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var daysOfWeek []int
+	if len(req.DaysOfWeek) > 0 {
+		daysOfWeek = req.DaysOfWeek
+	}
+
+	checkQuery := restrictionTargetCTE + `
+		SELECT room_type_id, date, min_los, max_los
+		FROM (
+			SELECT t.room_type_id, t.date,
+			       CASE WHEN $6::int IS NULL THEN rr.min_los ELSE NULLIF($6::int, 0) END AS min_los,
+			       CASE WHEN $7::int IS NULL THEN rr.max_los ELSE NULLIF($7::int, 0) END AS max_los
+			FROM target t
+			LEFT JOIN rate_restrictions rr ON rr.room_type_id = t.room_type_id
+				AND rr.date = t.date
+				AND COALESCE(rr.rate_plan_id, 0) = COALESCE($5::int, 0)
+		) merged
+		WHERE min_los IS NOT NULL AND max_los IS NOT NULL AND min_los > max_los
+		ORDER BY date, room_type_id
+	`
+
+	rows, err := tx.Query(ctx, checkQuery, req.RoomTypeIDs, startDate, endDate, daysOfWeek, req.RatePlanID, req.MinLOS, req.MaxLOS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check restriction constraints: %w", err)
+	}
+
+	var validationErrors []models.RestrictionValidationError
+	for rows.Next() {
+		var roomTypeID, minLOS, maxLOS int
+		var date time.Time
+		if err := rows.Scan(&roomTypeID, &date, &minLOS, &maxLOS); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan validation error: %w", err)
+		}
+		validationErrors = append(validationErrors, models.RestrictionValidationError{
+			Date:       date.Format("2006-01-02"),
+			RoomTypeID: roomTypeID,
+			Message:    fmt.Sprintf("Minimum stay (%d) cannot exceed maximum stay (%d)", minLOS, maxLOS),
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check restriction constraints: %w", err)
+	}
+
+	// If there are validation errors, return them without updating
+	if len(validationErrors) > 0 {
+		return validationErrors, nil
+	}
+
+	upsertQuery := restrictionTargetCTE + `
+		INSERT INTO rate_restrictions (room_type_id, rate_plan_id, date, min_los, max_los,
+			closed_to_arrival, closed_to_departure, stop_sell, notes)
+		SELECT t.room_type_id, $5::int, t.date, NULLIF($6::int, 0), NULLIF($7::int, 0),
+		       COALESCE($8::boolean, FALSE), COALESCE($9::boolean, FALSE), COALESCE($10::boolean, FALSE), $11::text
+		FROM target t
+		ON CONFLICT (room_type_id, (COALESCE(rate_plan_id, 0)), date)
+		DO UPDATE SET
+			min_los = CASE WHEN $6::int IS NULL THEN rate_restrictions.min_los ELSE EXCLUDED.min_los END,
+			max_los = CASE WHEN $7::int IS NULL THEN rate_restrictions.max_los ELSE EXCLUDED.max_los END,
+			closed_to_arrival = COALESCE($8::boolean, rate_restrictions.closed_to_arrival),
+			closed_to_departure = COALESCE($9::boolean, rate_restrictions.closed_to_departure),
+			stop_sell = COALESCE($10::boolean, rate_restrictions.stop_sell),
+			notes = COALESCE($11::text, rate_restrictions.notes),
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err = tx.Exec(ctx, upsertQuery, req.RoomTypeIDs, startDate, endDate, daysOfWeek, req.RatePlanID,
+		req.MinLOS, req.MaxLOS, req.ClosedToArrival, req.ClosedToDeparture, req.StopSell, req.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bulk update restrictions: %w", err)
+	}
+
+	// Remove rows that no longer restrict anything
+	cleanupQuery := `
+		DELETE FROM rate_restrictions
+		WHERE room_type_id = ANY($1::int[])
+		  AND date BETWEEN $2::date AND $3::date
+		  AND min_los IS NULL AND max_los IS NULL
+		  AND NOT closed_to_arrival AND NOT closed_to_departure AND NOT stop_sell
+	`
+
+	if _, err := tx.Exec(ctx, cleanupQuery, req.RoomTypeIDs, startDate, endDate); err != nil {
+		return nil, fmt.Errorf("failed to clean up restrictions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil, nil
+}
+
+// ClearRestrictions removes restrictions of the rate plan (or the all-plans rows when nil)
+// for the room types and dates in the range. Returns the number of rows removed.
+func (r *PricingRepository) ClearRestrictions(ctx context.Context, req *models.BulkUpdateRestrictionsRequest, startDate, endDate time.Time) (int64, error) {
+	var daysOfWeek []int
+	if len(req.DaysOfWeek) > 0 {
+		daysOfWeek = req.DaysOfWeek
+	}
+
+	query := restrictionTargetCTE + `
+		DELETE FROM rate_restrictions rr
+		USING target t
+		WHERE rr.room_type_id = t.room_type_id
+		  AND rr.date = t.date
+		  AND COALESCE(rr.rate_plan_id, 0) = COALESCE($5::int, 0)
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, req.RoomTypeIDs, startDate, endDate, daysOfWeek, req.RatePlanID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear restrictions: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ============================================================================
+// Occupancy Surcharge Methods
+// ============================================================================
+
+// GetOccupancySurcharges retrieves all occupancy surcharges, optionally for one rate tier
+func (r *PricingRepository) GetOccupancySurcharges(ctx context.Context, rateTierID *int) ([]models.OccupancySurcharge, error) {
+	query := `
+		SELECT os.surcharge_id, os.room_type_id, rt.name, os.rate_tier_id, t.name,
+		       os.charge_type, os.min_age, os.max_age, os.amount, os.created_at, os.updated_at
+		FROM occupancy_surcharges os
+		JOIN rate_tiers t ON os.rate_tier_id = t.rate_tier_id
+		LEFT JOIN room_types rt ON os.room_type_id = rt.room_type_id
+		WHERE $1::int IS NULL OR os.rate_tier_id = $1
+		ORDER BY t.display_order, os.room_type_id NULLS FIRST, os.charge_type, os.min_age
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, rateTierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query occupancy surcharges: %w", err)
+	}
+	defer rows.Close()
+
+	var surcharges []models.OccupancySurcharge
+	for rows.Next() {
+		var surcharge models.OccupancySurcharge
+		err := rows.Scan(
+			&surcharge.SurchargeID,
+			&surcharge.RoomTypeID,
+			&surcharge.RoomTypeName,
+			&surcharge.RateTierID,
+			&surcharge.RateTierName,
+			&surcharge.ChargeType,
+			&surcharge.MinAge,
+			&surcharge.MaxAge,
+			&surcharge.Amount,
+			&surcharge.CreatedAt,
+			&surcharge.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan occupancy surcharge: %w", err)
+		}
+		surcharges = append(surcharges, surcharge)
+	}
+
+	return surcharges, nil
+}
+
+// UpsertOccupancySurcharge creates or updates the surcharge of a tier, room type, charge type and age band
+func (r *PricingRepository) UpsertOccupancySurcharge(ctx context.Context, req *models.UpsertOccupancySurchargeRequest) (*models.OccupancySurcharge, error) {
+	query := `
+		INSERT INTO occupancy_surcharges (room_type_id, rate_tier_id, charge_type, min_age, max_age, amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (rate_tier_id, (COALESCE(room_type_id, 0)), charge_type, min_age)
+		DO UPDATE SET
+			max_age = EXCLUDED.max_age,
+			amount = EXCLUDED.amount,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING surcharge_id, room_type_id, rate_tier_id, charge_type, min_age, max_age, amount, created_at, updated_at
+	`
+
+	var surcharge models.OccupancySurcharge
+	err := r.db.Pool.QueryRow(ctx, query,
+		req.RoomTypeID,
+		req.RateTierID,
+		req.ChargeType,
+		req.MinAge,
+		req.MaxAge,
+		req.Amount,
+	).Scan(
+		&surcharge.SurchargeID,
+		&surcharge.RoomTypeID,
+		&surcharge.RateTierID,
+		&surcharge.ChargeType,
+		&surcharge.MinAge,
+		&surcharge.MaxAge,
+		&surcharge.Amount,
+		&surcharge.CreatedAt,
+		&surcharge.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert occupancy surcharge: %w", err)
+	}
+
+	return &surcharge, nil
+}
+
+// DeleteOccupancySurcharge removes a surcharge; returns false when it does not exist
+func (r *PricingRepository) DeleteOccupancySurcharge(ctx context.Context, id int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM occupancy_surcharges WHERE surcharge_id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete occupancy surcharge: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// UpdateRoomTypeOccupancy updates the base occupancy and extra bed settings of a room type;
+// returns false when the room type does not exist
+func (r *PricingRepository) UpdateRoomTypeOccupancy(ctx context.Context, roomTypeID int, req *models.UpdateRoomTypeOccupancyRequest) (bool, error) {
+	query := `
+		UPDATE room_types
+		SET base_occupancy = $2,
+			max_extra_beds = $3,
+			extra_bed_allotment = $4,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE room_type_id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, roomTypeID, req.BaseOccupancy, req.MaxExtraBeds, req.ExtraBedAllotment)
+	if err != nil {
+		return false, fmt.Errorf("failed to update room type occupancy: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ============================================================================
+// Dynamic Pricing Rule Methods
+// ============================================================================
+
+const pricingRuleColumns = `
+	pr.rule_id, pr.name, pr.rule_type, pr.room_type_id, rt.name, pr.occupancy_threshold,
+	pr.pickup_days, pr.pickup_threshold, pr.target_rate_tier_id, t.name, pr.floor_price,
+	pr.ceiling_price, pr.lookahead_days, pr.is_active, pr.created_at, pr.updated_at
+`
+
+const pricingRuleJoins = `
+	FROM pricing_rules pr
+	JOIN rate_tiers t ON pr.target_rate_tier_id = t.rate_tier_id
+	LEFT JOIN room_types rt ON pr.room_type_id = rt.room_type_id
+`
+
+func scanPricingRule(row pgx.Row, rule *models.PricingRule) error {
+	return row.Scan(
+		&rule.RuleID,
+		&rule.Name,
+		&rule.RuleType,
+		&rule.RoomTypeID,
+		&rule.RoomTypeName,
+		&rule.OccupancyThreshold,
+		&rule.PickupDays,
+		&rule.PickupThreshold,
+		&rule.TargetRateTierID,
+		&rule.TargetRateTierName,
+		&rule.FloorPrice,
+		&rule.CeilingPrice,
+		&rule.LookaheadDays,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+}
+
+// GetAllPricingRules retrieves all dynamic pricing rules
+func (r *PricingRepository) GetAllPricingRules(ctx context.Context) ([]models.PricingRule, error) {
+	query := `SELECT ` + pricingRuleColumns + pricingRuleJoins + ` ORDER BY pr.rule_type, t.display_order, pr.rule_id`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pricing rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.PricingRule
+	for rows.Next() {
+		var rule models.PricingRule
+		if err := scanPricingRule(rows, &rule); err != nil {
+			return nil, fmt.Errorf("failed to scan pricing rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// GetPricingRuleByID retrieves a dynamic pricing rule by ID
+func (r *PricingRepository) GetPricingRuleByID(ctx context.Context, id int) (*models.PricingRule, error) {
+	query := `SELECT ` + pricingRuleColumns + pricingRuleJoins + ` WHERE pr.rule_id = $1`
+
+	var rule models.PricingRule
+	if err := scanPricingRule(r.db.Pool.QueryRow(ctx, query, id), &rule); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pricing rule: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// CreatePricingRule creates a new dynamic pricing rule
+func (r *PricingRepository) CreatePricingRule(ctx context.Context, rule *models.PricingRule) (*models.PricingRule, error) {
+	query := `
+		INSERT INTO pricing_rules (name, rule_type, room_type_id, occupancy_threshold, pickup_days, pickup_threshold,
+			target_rate_tier_id, floor_price, ceiling_price, lookahead_days, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING rule_id
+	`
+
+	var id int
+	err := r.db.Pool.QueryRow(ctx, query,
+		rule.Name,
+		rule.RuleType,
+		rule.RoomTypeID,
+		rule.OccupancyThreshold,
+		rule.PickupDays,
+		rule.PickupThreshold,
+		rule.TargetRateTierID,
+		rule.FloorPrice,
+		rule.CeilingPrice,
+		rule.LookaheadDays,
+		rule.IsActive,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pricing rule: %w", err)
+	}
+
+	return r.GetPricingRuleByID(ctx, id)
+}
+
+// UpdatePricingRule updates a dynamic pricing rule
+func (r *PricingRepository) UpdatePricingRule(ctx context.Context, rule *models.PricingRule) (*models.PricingRule, error) {
+	query := `
+		UPDATE pricing_rules
+		SET name = $2,
+			rule_type = $3,
+			room_type_id = $4,
+			occupancy_threshold = $5,
+			pickup_days = $6,
+			pickup_threshold = $7,
+			target_rate_tier_id = $8,
+			floor_price = $9,
+			ceiling_price = $10,
+			lookahead_days = $11,
+			is_active = $12,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE rule_id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query,
+		rule.RuleID,
+		rule.Name,
+		rule.RuleType,
+		rule.RoomTypeID,
+		rule.OccupancyThreshold,
+		rule.PickupDays,
+		rule.PickupThreshold,
+		rule.TargetRateTierID,
+		rule.FloorPrice,
+		rule.CeilingPrice,
+		rule.LookaheadDays,
+		rule.IsActive,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pricing rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+
+	return r.GetPricingRuleByID(ctx, rule.RuleID)
+}
+
+// DeletePricingRule removes a dynamic pricing rule; returns false when it does not exist
+func (r *PricingRepository) DeletePricingRule(ctx context.Context, id int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM pricing_rules WHERE rule_id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete pricing rule: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetPricingRuleChanges retrieves the tier changes made by the engine for stay dates in a range
+func (r *PricingRepository) GetPricingRuleChanges(ctx context.Context, startDate, endDate time.Time) ([]models.PricingRuleChange, error) {
+	query := `
+		SELECT c.change_id, c.date, c.rule_id, c.rule_name, c.old_rate_tier_id, ot.name,
+		       c.new_rate_tier_id, nt.name, c.occupancy_percent, c.pickup, c.created_at
+		FROM pricing_rule_changes c
+		JOIN rate_tiers nt ON c.new_rate_tier_id = nt.rate_tier_id
+		LEFT JOIN rate_tiers ot ON c.old_rate_tier_id = ot.rate_tier_id
+		WHERE c.date >= $1 AND c.date <= $2
+		ORDER BY c.created_at DESC, c.date
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pricing rule changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.PricingRuleChange
+	for rows.Next() {
+		var change models.PricingRuleChange
+		err := rows.Scan(
+			&change.ChangeID,
+			&change.Date,
+			&change.RuleID,
+			&change.RuleName,
+			&change.OldRateTierID,
+			&change.OldRateTierName,
+			&change.NewRateTierID,
+			&change.NewRateTierName,
+			&change.OccupancyPercent,
+			&change.Pickup,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pricing rule change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// ============================================================================
+// Day-Use Rate Methods
+// ============================================================================
+
+// dayUseRateQuery selects day-use rates with their plan and room type names
+const dayUseRateQuery = `
+	SELECT d.day_use_rate_id, d.rate_plan_id, rp.name, d.room_type_id, rt.name,
+	       d.duration_hours, d.price, d.is_active, d.created_at, d.updated_at
+	FROM day_use_rates d
+	JOIN rate_plans rp ON d.rate_plan_id = rp.rate_plan_id
+	JOIN room_types rt ON d.room_type_id = rt.room_type_id
+`
+
+// collectDayUseRates scans rows selected with dayUseRateQuery
+func collectDayUseRates(rows pgx.Rows) ([]models.DayUseRate, error) {
+	defer rows.Close()
+
+	var rates []models.DayUseRate
+	for rows.Next() {
+		var rate models.DayUseRate
+		err := rows.Scan(
+			&rate.DayUseRateID,
+			&rate.RatePlanID,
+			&rate.RatePlanName,
+			&rate.RoomTypeID,
+			&rate.RoomTypeName,
+			&rate.DurationHours,
+			&rate.Price,
+			&rate.IsActive,
+			&rate.CreatedAt,
+			&rate.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan day-use rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// GetAllDayUseRates retrieves every day-use rate, including inactive ones
+func (r *PricingRepository) GetAllDayUseRates(ctx context.Context) ([]models.DayUseRate, error) {
+	rows, err := r.db.Pool.Query(ctx, dayUseRateQuery+` ORDER BY rp.name, rt.name, d.duration_hours`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query day-use rates: %w", err)
+	}
+
+	return collectDayUseRates(rows)
+}
+
+// UpsertDayUseRate creates or updates the price of a slot length for a room type
+func (r *PricingRepository) UpsertDayUseRate(ctx context.Context, req *models.UpsertDayUseRateRequest) error {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	query := `
+		INSERT INTO day_use_rates (rate_plan_id, room_type_id, duration_hours, price, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (rate_plan_id, room_type_id, duration_hours)
+		DO UPDATE SET price = EXCLUDED.price, is_active = EXCLUDED.is_active, updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query, req.RatePlanID, req.RoomTypeID, req.DurationHours, req.Price, isActive)
+	if err != nil {
+		return fmt.Errorf("failed to upsert day-use rate: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDayUseRate deletes a day-use rate; returns false when it does not exist
+func (r *PricingRepository) DeleteDayUseRate(ctx context.Context, id int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM day_use_rates WHERE day_use_rate_id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete day-use rate: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ============================================================================
+// Long-Stay Rate Methods
+// ============================================================================
+
+// longStayRateQuery selects long-stay rates with their plan and room type names
+const longStayRateQuery = `
+	SELECT l.long_stay_rate_id, l.rate_plan_id, rp.name, l.room_type_id, rt.name,
+	       l.min_nights, l.discount_percent, l.monthly_price, l.housekeeping_frequency_days,
+	       l.is_active, l.created_at, l.updated_at
+	FROM long_stay_rates l
+	JOIN rate_plans rp ON l.rate_plan_id = rp.rate_plan_id
+	LEFT JOIN room_types rt ON l.room_type_id = rt.room_type_id
+`
+
+// collectLongStayRates scans rows selected with longStayRateQuery
+func collectLongStayRates(rows pgx.Rows) ([]models.LongStayRate, error) {
+	defer rows.Close()
+
+	var rates []models.LongStayRate
+	for rows.Next() {
+		var rate models.LongStayRate
+		err := rows.Scan(
+			&rate.LongStayRateID,
+			&rate.RatePlanID,
+			&rate.RatePlanName,
+			&rate.RoomTypeID,
+			&rate.RoomTypeName,
+			&rate.MinNights,
+			&rate.DiscountPercent,
+			&rate.MonthlyPrice,
+			&rate.HousekeepingFrequency,
+			&rate.IsActive,
+			&rate.CreatedAt,
+			&rate.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan long-stay rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// GetAllLongStayRates retrieves every long-stay rate, including inactive ones
+func (r *PricingRepository) GetAllLongStayRates(ctx context.Context) ([]models.LongStayRate, error) {
+	rows, err := r.db.Pool.Query(ctx, longStayRateQuery+` ORDER BY rp.name, rt.name NULLS FIRST, l.min_nights`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query long-stay rates: %w", err)
+	}
+
+	return collectLongStayRates(rows)
+}
+
+// UpsertLongStayRate creates or updates the long-stay rate of a plan, room type and threshold
+func (r *PricingRepository) UpsertLongStayRate(ctx context.Context, req *models.UpsertLongStayRateRequest) error {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	query := `
+		INSERT INTO long_stay_rates (rate_plan_id, room_type_id, min_nights, discount_percent, monthly_price,
+		                             housekeeping_frequency_days, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (rate_plan_id, COALESCE(room_type_id, 0), min_nights)
+		DO UPDATE SET discount_percent = EXCLUDED.discount_percent,
+		              monthly_price = EXCLUDED.monthly_price,
+		              housekeeping_frequency_days = EXCLUDED.housekeeping_frequency_days,
+		              is_active = EXCLUDED.is_active,
+		              updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		req.RatePlanID, req.RoomTypeID, req.MinNights, req.DiscountPercent, req.MonthlyPrice,
+		req.HousekeepingFrequency, isActive)
+	if err != nil {
+		return fmt.Errorf("failed to upsert long-stay rate: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteLongStayRate deletes a long-stay rate; returns false when it does not exist
+func (r *PricingRepository) DeleteLongStayRate(ctx context.Context, id int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM long_stay_rates WHERE long_stay_rate_id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete long-stay rate: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ============================================================================
+// Stay-Time Fee Methods
+// ============================================================================
+
+// GetAllStayTimeFees retrieves every early check-in and late check-out fee tier,
+// including inactive ones
+func (r *PricingRepository) GetAllStayTimeFees(ctx context.Context) ([]models.StayTimeFee, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT fee_id, request_type, tier_hour, fee, is_active, created_at, updated_at
+		FROM stay_time_fees
+		ORDER BY request_type, tier_hour
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stay-time fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []models.StayTimeFee
+	for rows.Next() {
+		var fee models.StayTimeFee
+		err := rows.Scan(
+			&fee.FeeID,
+			&fee.RequestType,
+			&fee.TierHour,
+			&fee.Fee,
+			&fee.IsActive,
+			&fee.CreatedAt,
+			&fee.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stay-time fee: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+
+	return fees, rows.Err()
+}
+
+// UpsertStayTimeFee creates or updates the fee of an early check-in or late check-out tier
+func (r *PricingRepository) UpsertStayTimeFee(ctx context.Context, req *models.UpsertStayTimeFeeRequest) error {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	query := `
+		INSERT INTO stay_time_fees (request_type, tier_hour, fee, is_active)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (request_type, tier_hour)
+		DO UPDATE SET fee = EXCLUDED.fee, is_active = EXCLUDED.is_active, updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query, req.RequestType, req.TierHour, req.Fee, isActive)
+	if err != nil {
+		return fmt.Errorf("failed to upsert stay-time fee: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStayTimeFee deletes a stay-time fee tier; returns false when it does not exist
+func (r *PricingRepository) DeleteStayTimeFee(ctx context.Context, id int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM stay_time_fees WHERE fee_id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete stay-time fee: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
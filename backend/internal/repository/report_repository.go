@@ -271,6 +271,54 @@ func (r *ReportRepository) GetNoShowReport(ctx context.Context, startDate, endDa
 	return reports, nil
 }
 
+// GetAddOnRevenueReport retrieves add-on revenue per service date. Per-night add-ons
+// spread their total evenly over the nights they cover.
+func (r *ReportRepository) GetAddOnRevenueReport(ctx context.Context, startDate, endDate time.Time) ([]models.AddOnRevenueReport, error) {
+	query := `
+		SELECT 
+			d::date as date,
+			a.addon_id,
+			a.name,
+			a.category,
+			SUM(ba.quantity) as quantity,
+			SUM(ba.total_price / (ba.service_end_date - ba.service_start_date + 1)) as revenue
+		FROM booking_addons ba
+		JOIN addons a ON ba.addon_id = a.addon_id
+		JOIN booking_details bd ON ba.booking_detail_id = bd.booking_detail_id
+		JOIN bookings b ON bd.booking_id = b.booking_id
+		CROSS JOIN generate_series(ba.service_start_date, ba.service_end_date, interval '1 day') AS d
+		WHERE d::date >= $1 AND d::date <= $2
+		  AND b.status IN ('Confirmed', 'CheckedIn', 'Completed')
+		GROUP BY d::date, a.addon_id, a.name, a.category
+		ORDER BY d::date, a.category, a.name
+	`
+
+	rows, err := r.db.Query(ctx, query, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query add-on revenue report: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []models.AddOnRevenueReport
+	for rows.Next() {
+		var report models.AddOnRevenueReport
+		err := rows.Scan(
+			&report.Date,
+			&report.AddOnID,
+			&report.AddOnName,
+			&report.Category,
+			&report.Quantity,
+			&report.Revenue,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan add-on revenue report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
 // GetReportSummary retrieves aggregated statistics for a date range
 func (r *ReportRepository) GetReportSummary(ctx context.Context, startDate, endDate time.Time) (*models.ReportSummary, error) {
 	query := `
@@ -296,6 +344,15 @@ func (r *ReportRepository) GetReportSummary(ctx context.Context, startDate, endD
 			FROM room_inventory
 			WHERE date >= $1 AND date <= $2
 		),
+		addon_data AS (
+			SELECT SUM(ba.total_price / (ba.service_end_date - ba.service_start_date + 1)) as addon_revenue
+			FROM booking_addons ba
+			JOIN booking_details bd ON ba.booking_detail_id = bd.booking_detail_id
+			JOIN bookings b ON bd.booking_id = b.booking_id
+			CROSS JOIN generate_series(ba.service_start_date, ba.service_end_date, interval '1 day') AS d
+			WHERE d::date >= $1 AND d::date <= $2
+			  AND b.status IN ('Confirmed', 'CheckedIn', 'Completed')
+		),
 		noshow_data AS (
 			SELECT COUNT(*) as no_show_count
 			FROM bookings
@@ -304,6 +361,7 @@ func (r *ReportRepository) GetReportSummary(ctx context.Context, startDate, endD
 		)
 		SELECT 
 			COALESCE(rd.total_revenue, 0) as total_revenue,
+			COALESCE(ad.addon_revenue, 0) as addon_revenue,
 			COALESCE(rd.total_bookings, 0) as total_bookings,
 			COALESCE(rd.total_room_nights, 0) as total_room_nights,
 			COALESCE(od.avg_occupancy, 0) as avg_occupancy,
@@ -320,6 +378,7 @@ func (r *ReportRepository) GetReportSummary(ctx context.Context, startDate, endD
 			END as no_show_rate
 		FROM revenue_data rd
 		CROSS JOIN occupancy_data od
+		CROSS JOIN addon_data ad
 		CROSS JOIN noshow_data nd
 	`
 
@@ -329,6 +388,7 @@ func (r *ReportRepository) GetReportSummary(ctx context.Context, startDate, endD
 
 	err := r.db.QueryRow(ctx, query, startDate, endDate).Scan(
 		&summary.TotalRevenue,
+		&summary.AddOnRevenue,
 		&summary.TotalBookings,
 		&summary.TotalRoomNights,
 		&summary.AvgOccupancy,
@@ -9,6 +9,7 @@ import (
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/pkg/database"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // RoomRepository handles room database operations
@@ -21,14 +22,10 @@ func NewRoomRepository(db *database.DB) *RoomRepository {
 	return &RoomRepository{db: db}
 }
 
-// SearchAvailableRooms searches for available rooms based on criteria
-func (r *RoomRepository) SearchAvailableRooms(ctx context.Context, checkIn, checkOut time.Time, guests int) ([]models.RoomType, error) {
-	// First, ensure inventory exists for the date range
-	if err := r.ensureInventoryExists(ctx, checkIn, checkOut); err != nil {
-		return nil, fmt.Errorf("failed to ensure inventory exists: %w", err)
-	}
-
-	query := `
+// dailyAvailabilityCTE lists the rooms free per room type and night, up to the
+// overbooking limit, from $1 to $2 (exclusive) for room types that fit $3 guests and match the search filter: amenity
+// IDs $4, bed type $5, view $6 and accessibility $7
+const dailyAvailabilityCTE = `
 		WITH date_range AS (
 			SELECT generate_series($1::date, $2::date - interval '1 day', interval '1 day')::date AS date
 		),
@@ -39,14 +36,40 @@ func (r *RoomRepository) SearchAvailableRooms(ctx context.Context, checkIn, chec
 				COALESCE(ri.allotment, rt.default_allotment) as total_allotment,
 				COALESCE(ri.booked_count, 0) as booked,
 				COALESCE(ri.tentative_count, 0) as tentative,
-				COALESCE(ri.allotment, rt.default_allotment) - 
+				COALESCE(ri.allotment, rt.default_allotment) + 
+					COALESCE(overbooking_rooms(ri.allotment, ri.overbooking_type, ri.overbooking_value), 0) - 
 					COALESCE(ri.booked_count, 0) - 
-					COALESCE(ri.tentative_count, 0) as available
+					COALESCE(ri.tentative_count, 0) as available,
+				GREATEST(0, $3 - rt.max_occupancy) as extra_beds_needed,
+				CASE WHEN $3 > rt.max_occupancy
+					THEN rt.extra_bed_allotment - extra_beds_booked(rt.room_type_id, dr.date)
+					ELSE 0
+				END as extra_beds_available
 			FROM room_types rt
 			CROSS JOIN date_range dr
 			LEFT JOIN room_inventory ri ON rt.room_type_id = ri.room_type_id AND ri.date = dr.date
-			WHERE rt.max_occupancy >= $3
-		),
+			WHERE rt.max_occupancy + rt.max_extra_beds >= $3
+			  AND ($5 = '' OR rt.bed_type ILIKE '%' || $5 || '%')
+			  AND ($6 = '' OR LOWER(rt.view_type) = LOWER($6))
+			  AND (NOT $7 OR rt.is_accessible)
+			  AND NOT EXISTS (
+				SELECT UNNEST($4::int[])
+				EXCEPT
+				SELECT rta.amenity_id FROM room_type_amenities rta WHERE rta.room_type_id = rt.room_type_id
+			  )
+		)`
+
+// SearchAvailableRooms searches for available rooms based on criteria.
+// A nil filter returns every room type that fits, ordered by name.
+func (r *RoomRepository) SearchAvailableRooms(ctx context.Context, checkIn, checkOut time.Time, guests int, filter *models.RoomSearchFilter) ([]models.RoomType, error) {
+	filter, amenityIDs := searchFilterArgs(filter)
+
+	// First, ensure inventory exists for the date range
+	if err := r.ensureInventoryExists(ctx, checkIn, checkOut); err != nil {
+		return nil, fmt.Errorf("failed to ensure inventory exists: %w", err)
+	}
+
+	query := dailyAvailabilityCTE + `,
 		available_room_types AS (
 			SELECT 
 				room_type_id,
@@ -56,6 +79,16 @@ func (r *RoomRepository) SearchAvailableRooms(ctx context.Context, checkIn, chec
 			GROUP BY room_type_id
 			HAVING MIN(available) > 0
 			   AND COUNT(*) = ($2::date - $1::date)
+			   AND BOOL_AND(extra_beds_needed = 0 OR extra_beds_available >= extra_beds_needed)
+		),
+		popularity AS (
+			SELECT bd.room_type_id, COUNT(*) as booked_rooms
+			FROM booking_details bd
+			JOIN bookings b ON b.booking_id = bd.booking_id
+			WHERE b.status IN ('Confirmed', 'CheckedIn', 'Completed')
+			  AND b.created_at >= NOW() - make_interval(days => $8)
+			  AND bd.room_type_id IN (SELECT room_type_id FROM available_room_types)
+			GROUP BY bd.room_type_id
 		)
 		SELECT 
 			rt.room_type_id,
@@ -63,13 +96,22 @@ func (r *RoomRepository) SearchAvailableRooms(ctx context.Context, checkIn, chec
 			rt.description,
 			rt.max_occupancy,
 			rt.default_allotment,
+			rt.base_occupancy,
+			rt.max_extra_beds,
+			rt.extra_bed_allotment,
+			rt.size_sqm,
+			rt.bed_type,
+			rt.view_type,
+			rt.is_accessible,
+			COALESCE(p.booked_rooms, 0) as popularity,
 			art.min_available as available_rooms
 		FROM room_types rt
 		INNER JOIN available_room_types art ON rt.room_type_id = art.room_type_id
-		ORDER BY rt.name
-	`
+		LEFT JOIN popularity p ON p.room_type_id = rt.room_type_id
+		ORDER BY ` + searchRoomsOrder(filter)
 
-	rows, err := r.db.Pool.Query(ctx, query, checkIn, checkOut, guests)
+	rows, err := r.db.Pool.Query(ctx, query, checkIn, checkOut, guests,
+		amenityIDs, filter.BedType, filter.View, filter.Accessible, models.PopularityWindowDays)
 	if err != nil {
 		return nil, fmt.Errorf("database query failed (checkIn=%s, checkOut=%s, guests=%d): %w", 
 			checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"), guests, err)
@@ -86,6 +128,14 @@ func (r *RoomRepository) SearchAvailableRooms(ctx context.Context, checkIn, chec
 			&rt.Description,
 			&rt.MaxOccupancy,
 			&rt.DefaultAllotment,
+			&rt.BaseOccupancy,
+			&rt.MaxExtraBeds,
+			&rt.ExtraBedAllotment,
+			&rt.SizeSqm,
+			&rt.BedType,
+			&rt.ViewType,
+			&rt.IsAccessible,
+			&rt.Popularity,
 			&availableRooms,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan room type row: %w", err)
@@ -101,6 +151,154 @@ func (r *RoomRepository) SearchAvailableRooms(ctx context.Context, checkIn, chec
 	return roomTypes, nil
 }
 
+// GetDailyAvailability returns, per room type that fits the guests and matches the
+// filter, the rooms free on each night from `from` to `to` (exclusive). A night the room
+// type lacks the extra beds the guests need counts as sold out.
+func (r *RoomRepository) GetDailyAvailability(ctx context.Context, from, to time.Time, guests int, filter *models.RoomSearchFilter) (map[int][]int, error) {
+	filter, amenityIDs := searchFilterArgs(filter)
+
+	if err := r.ensureInventoryExists(ctx, from, to); err != nil {
+		return nil, fmt.Errorf("failed to ensure inventory exists: %w", err)
+	}
+
+	query := dailyAvailabilityCTE + `
+		SELECT room_type_id,
+		       CASE WHEN extra_beds_needed > 0 AND extra_beds_available < extra_beds_needed
+		            THEN 0
+		            ELSE GREATEST(available, 0)
+		       END
+		FROM daily_availability
+		ORDER BY room_type_id, date
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, from, to, guests,
+		amenityIDs, filter.BedType, filter.View, filter.Accessible)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily availability: %w", err)
+	}
+	defer rows.Close()
+
+	availability := make(map[int][]int)
+	for rows.Next() {
+		var roomTypeID, rooms int
+		if err := rows.Scan(&roomTypeID, &rooms); err != nil {
+			return nil, fmt.Errorf("failed to scan daily availability: %w", err)
+		}
+		availability[roomTypeID] = append(availability[roomTypeID], rooms)
+	}
+
+	return availability, rows.Err()
+}
+
+// GetAvailabilityCalendar returns, per night from `from` to `to` (exclusive), the rooms
+// free over all room types (or one) and the lowest nightly rate of a room type with a
+// room free, from rate_pricing by the night's pricing_calendar tier. Only active public
+// plans sellable today count, and a stop-sell closes its room type and plan.
+func (r *RoomRepository) GetAvailabilityCalendar(ctx context.Context, from, to time.Time, roomTypeID *int) ([]models.CalendarDay, error) {
+	if err := r.ensureInventoryExists(ctx, from, to); err != nil {
+		return nil, fmt.Errorf("failed to ensure inventory exists: %w", err)
+	}
+
+	query := `
+		WITH days AS (
+			SELECT generate_series($1::date, $2::date - interval '1 day', interval '1 day')::date AS date
+		),
+		room_days AS (
+			SELECT d.date, rt.room_type_id,
+			       GREATEST(COALESCE(ri.allotment, rt.default_allotment)
+			                + COALESCE(overbooking_rooms(ri.allotment, ri.overbooking_type, ri.overbooking_value), 0)
+			                - COALESCE(ri.booked_count, 0)
+			                - COALESCE(ri.tentative_count, 0), 0) AS available
+			FROM days d
+			CROSS JOIN room_types rt
+			LEFT JOIN room_inventory ri ON ri.room_type_id = rt.room_type_id AND ri.date = d.date
+			WHERE ($3::int IS NULL OR rt.room_type_id = $3)
+		),
+		rates AS (
+			SELECT d.date, rp.room_type_id, MIN(rp.price) AS price
+			FROM days d
+			JOIN pricing_calendar pc ON pc.date = d.date
+			JOIN rate_pricing rp ON rp.rate_tier_id = pc.rate_tier_id
+			JOIN rate_plans p ON p.rate_plan_id = rp.rate_plan_id
+			WHERE p.is_active = TRUE
+			  AND p.is_day_use = FALSE
+			  AND p.members_only = FALSE
+			  AND rp.price > 0
+			  AND ($3::int IS NULL OR rp.room_type_id = $3)
+			  AND (p.stay_start_date IS NULL OR d.date >= p.stay_start_date)
+			  AND (p.stay_end_date IS NULL OR d.date < p.stay_end_date)
+			  AND (p.booking_start_date IS NULL OR CURRENT_DATE >= p.booking_start_date)
+			  AND (p.booking_end_date IS NULL OR CURRENT_DATE <= p.booking_end_date)
+			  AND (p.min_advance_days IS NULL OR d.date - CURRENT_DATE >= p.min_advance_days)
+			  AND (p.max_advance_days IS NULL OR d.date - CURRENT_DATE <= p.max_advance_days)
+			  AND NOT EXISTS (
+				SELECT 1 FROM rate_restrictions rr
+				WHERE rr.room_type_id = rp.room_type_id
+				  AND rr.date = d.date
+				  AND rr.stop_sell = TRUE
+				  AND (rr.rate_plan_id IS NULL OR rr.rate_plan_id = p.rate_plan_id)
+			  )
+			GROUP BY d.date, rp.room_type_id
+		)
+		SELECT d.date,
+		       COALESCE(SUM(rd.available), 0) AS available_rooms,
+		       MIN(r.price) FILTER (WHERE rd.available > 0) AS lowest_rate
+		FROM days d
+		LEFT JOIN room_days rd ON rd.date = d.date
+		LEFT JOIN rates r ON r.date = d.date AND r.room_type_id = rd.room_type_id
+		GROUP BY d.date
+		ORDER BY d.date
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, from, to, roomTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability calendar: %w", err)
+	}
+	defer rows.Close()
+
+	var days []models.CalendarDay
+	for rows.Next() {
+		var date time.Time
+		var day models.CalendarDay
+		if err := rows.Scan(&date, &day.AvailableRooms, &day.LowestRate); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar day: %w", err)
+		}
+		day.Date = date.Format("2006-01-02")
+		day.Available = day.AvailableRooms > 0 && day.LowestRate != nil
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// searchFilterArgs returns a filter that is never nil and its amenity IDs as a
+// non-nil array parameter
+func searchFilterArgs(filter *models.RoomSearchFilter) (*models.RoomSearchFilter, []int) {
+	if filter == nil {
+		filter = &models.RoomSearchFilter{}
+	}
+	amenityIDs := filter.AmenityIDs
+	if amenityIDs == nil {
+		amenityIDs = []int{}
+	}
+	return filter, amenityIDs
+}
+
+// searchRoomsOrder returns the ORDER BY of a room search; the name breaks ties
+func searchRoomsOrder(filter *models.RoomSearchFilter) string {
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	switch filter.SortBy {
+	case models.SearchSortSize:
+		return "rt.size_sqm " + direction + " NULLS LAST, rt.name"
+	case models.SearchSortPopularity:
+		return "popularity " + direction + ", rt.name"
+	}
+	return "rt.name"
+}
+
 // ensureInventoryExists creates inventory records if they don't exist
 func (r *RoomRepository) ensureInventoryExists(ctx context.Context, checkIn, checkOut time.Time) error {
 	query := `
@@ -183,26 +381,42 @@ func (r *RoomRepository) GetNightlyPrices(ctx context.Context, roomTypeID int, r
 			return nil, fmt.Errorf("failed to scan price: %w", err)
 		}
 		prices = append(prices, models.NightlyPrice{
-			Date:  date.Format("2006-01-02"),
-			Price: price,
+			Date:     date.Format("2006-01-02"),
+			Price:    price,
+			RoomRate: price,
 		})
 	}
 
 	return prices, nil
 }
 
+// roomTypeColumns is the column list read by scanRoomType
+const roomTypeColumns = `room_type_id, name, COALESCE(description, ''), max_occupancy, default_allotment,
+	base_occupancy, max_extra_beds, extra_bed_allotment, base_price, image_url, size_sqm, bed_type,
+	view_type, is_accessible`
+
+func scanRoomType(row pgx.Row, rt *models.RoomType) error {
+	return row.Scan(
+		&rt.RoomTypeID,
+		&rt.Name,
+		&rt.Description,
+		&rt.MaxOccupancy,
+		&rt.DefaultAllotment,
+		&rt.BaseOccupancy,
+		&rt.MaxExtraBeds,
+		&rt.ExtraBedAllotment,
+		&rt.BasePrice,
+		&rt.ImageURL,
+		&rt.SizeSqm,
+		&rt.BedType,
+		&rt.ViewType,
+		&rt.IsAccessible,
+	)
+}
+
 // GetAllRoomTypes retrieves all room types
 func (r *RoomRepository) GetAllRoomTypes(ctx context.Context) ([]models.RoomType, error) {
-	query := `
-		SELECT 
-			room_type_id,
-			name,
-			description,
-			max_occupancy,
-			default_allotment
-		FROM room_types
-		ORDER BY name
-	`
+	query := `SELECT ` + roomTypeColumns + ` FROM room_types ORDER BY name`
 
 	rows, err := r.db.Pool.Query(ctx, query)
 	if err != nil {
@@ -213,13 +427,7 @@ func (r *RoomRepository) GetAllRoomTypes(ctx context.Context) ([]models.RoomType
 	var roomTypes []models.RoomType
 	for rows.Next() {
 		var rt models.RoomType
-		if err := rows.Scan(
-			&rt.RoomTypeID,
-			&rt.Name,
-			&rt.Description,
-			&rt.MaxOccupancy,
-			&rt.DefaultAllotment,
-		); err != nil {
+		if err := scanRoomType(rows, &rt); err != nil {
 			return nil, fmt.Errorf("failed to scan room type: %w", err)
 		}
 		roomTypes = append(roomTypes, rt)
@@ -230,26 +438,10 @@ func (r *RoomRepository) GetAllRoomTypes(ctx context.Context) ([]models.RoomType
 
 // GetRoomTypeByID retrieves a specific room type by ID
 func (r *RoomRepository) GetRoomTypeByID(ctx context.Context, roomTypeID int) (*models.RoomType, error) {
-	query := `
-		SELECT 
-			room_type_id,
-			name,
-			description,
-			max_occupancy,
-			default_allotment
-		FROM room_types
-		WHERE room_type_id = $1
-	`
+	query := `SELECT ` + roomTypeColumns + ` FROM room_types WHERE room_type_id = $1`
 
 	var rt models.RoomType
-	err := r.db.Pool.QueryRow(ctx, query, roomTypeID).Scan(
-		&rt.RoomTypeID,
-		&rt.Name,
-		&rt.Description,
-		&rt.MaxOccupancy,
-		&rt.DefaultAllotment,
-	)
-
+	err := scanRoomType(r.db.Pool.QueryRow(ctx, query, roomTypeID), &rt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -260,47 +452,55 @@ func (r *RoomRepository) GetRoomTypeByID(ctx context.Context, roomTypeID int) (*
 	return &rt, nil
 }
 
-// GetRoomsByRoomType retrieves all rooms for a specific room type
-func (r *RoomRepository) GetRoomsByRoomType(ctx context.Context, roomTypeID int) ([]models.Room, error) {
-	query := `
-		SELECT 
-			room_id,
-			room_type_id,
-			room_number,
-			occupancy_status,
-			housekeeping_status
-		FROM rooms
-		WHERE room_type_id = $1
-		ORDER BY room_number
-	`
+// roomColumns is the column list read by scanRoom
+const roomColumns = `room_id, room_type_id, room_number, floor, features, notes, occupancy_status,
+	housekeeping_status, is_active, connecting_room_id`
 
-	rows, err := r.db.Pool.Query(ctx, query, roomTypeID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get rooms: %w", err)
-	}
+func scanRoom(row pgx.Row, room *models.Room) error {
+	return row.Scan(
+		&room.RoomID,
+		&room.RoomTypeID,
+		&room.RoomNumber,
+		&room.Floor,
+		&room.Features,
+		&room.Notes,
+		&room.OccupancyStatus,
+		&room.HousekeepingStatus,
+		&room.IsActive,
+		&room.ConnectingRoomID,
+	)
+}
+
+func collectRooms(rows pgx.Rows) ([]models.Room, error) {
 	defer rows.Close()
 
 	var rooms []models.Room
 	for rows.Next() {
 		var room models.Room
-		if err := rows.Scan(
-			&room.RoomID,
-			&room.RoomTypeID,
-			&room.RoomNumber,
-			&room.OccupancyStatus,
-			&room.HousekeepingStatus,
-		); err != nil {
+		if err := scanRoom(rows, &room); err != nil {
 			return nil, fmt.Errorf("failed to scan room: %w", err)
 		}
 		rooms = append(rooms, room)
 	}
 
-	return rooms, nil
+	return rooms, rows.Err()
+}
+
+// GetRoomsByRoomType retrieves the active rooms of a specific room type
+func (r *RoomRepository) GetRoomsByRoomType(ctx context.Context, roomTypeID int) ([]models.Room, error) {
+	query := `SELECT ` + roomColumns + ` FROM rooms WHERE room_type_id = $1 AND is_active = TRUE ORDER BY room_number`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rooms: %w", err)
+	}
+
+	return collectRooms(rows)
 }
 
 // GetDefaultRatePlanID retrieves the default rate plan ID
 func (r *RoomRepository) GetDefaultRatePlanID(ctx context.Context) (int, error) {
-	query := `SELECT rate_plan_id FROM rate_plans ORDER BY rate_plan_id LIMIT 1`
+	query := `SELECT rate_plan_id FROM rate_plans WHERE is_day_use = FALSE ORDER BY rate_plan_id LIMIT 1`
 	
 	var ratePlanID int
 	err := r.db.Pool.QueryRow(ctx, query).Scan(&ratePlanID)
@@ -314,6 +514,172 @@ func (r *RoomRepository) GetDefaultRatePlanID(ctx context.Context) (int, error)
 	return ratePlanID, nil
 }
 
+// GetActiveRatePlans retrieves active overnight rate plans, default plan first
+func (r *RoomRepository) GetActiveRatePlans(ctx context.Context) ([]models.RatePlan, error) {
+	query := `SELECT ` + ratePlanColumns + ` FROM rate_plans WHERE is_active = TRUE AND is_day_use = FALSE ORDER BY rate_plan_id`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active rate plans: %w", err)
+	}
+	defer rows.Close()
+
+	var plans []models.RatePlan
+	for rows.Next() {
+		var plan models.RatePlan
+		if err := scanRatePlan(rows, &plan); err != nil {
+			return nil, fmt.Errorf("failed to scan rate plan: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rate plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+// GetDayUseRates retrieves the active rates of active day-use plans, optionally for
+// one room type (nil = all) and slot length (0 = all)
+func (r *RoomRepository) GetDayUseRates(ctx context.Context, roomTypeID *int, durationHours int) ([]models.DayUseRate, error) {
+	query := dayUseRateQuery + `
+		WHERE d.is_active = TRUE AND rp.is_active = TRUE AND rp.is_day_use = TRUE
+		  AND ($1::int IS NULL OR d.room_type_id = $1)
+		  AND ($2 = 0 OR d.duration_hours = $2)
+		ORDER BY rt.name, d.rate_plan_id, d.duration_hours
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID, durationHours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query day-use rates: %w", err)
+	}
+
+	return collectDayUseRates(rows)
+}
+
+// GetDayUseAvailability returns the rooms of a type free for each slot start, in the order given
+func (r *RoomRepository) GetDayUseAvailability(ctx context.Context, roomTypeID int, starts []time.Time, durationHours int) ([]int, error) {
+	query := `
+		SELECT day_use_rooms_available($1, s, s + make_interval(hours => $3))
+		FROM unnest($2::timestamp[]) WITH ORDINALITY AS t(s, n)
+		ORDER BY n
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID, starts, durationHours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get day-use availability: %w", err)
+	}
+	defer rows.Close()
+
+	var available []int
+	for rows.Next() {
+		var rooms int
+		if err := rows.Scan(&rooms); err != nil {
+			return nil, fmt.Errorf("failed to scan day-use availability: %w", err)
+		}
+		available = append(available, rooms)
+	}
+
+	return available, rows.Err()
+}
+
+// GetExtraBedAvailability returns, per room type, the extra beds free on every night of a stay
+func (r *RoomRepository) GetExtraBedAvailability(ctx context.Context, roomTypeIDs []int, checkIn, checkOut time.Time) (map[int]int, error) {
+	query := `
+		SELECT rt.room_type_id,
+		       MIN(rt.extra_bed_allotment - extra_beds_booked(rt.room_type_id, d::date)) AS available
+		FROM room_types rt
+		CROSS JOIN generate_series($2::date, $3::date - interval '1 day', interval '1 day') AS d
+		WHERE rt.room_type_id = ANY($1)
+		GROUP BY rt.room_type_id
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeIDs, checkIn, checkOut)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get extra bed availability: %w", err)
+	}
+	defer rows.Close()
+
+	available := make(map[int]int)
+	for rows.Next() {
+		var roomTypeID, beds int
+		if err := rows.Scan(&roomTypeID, &beds); err != nil {
+			return nil, fmt.Errorf("failed to scan extra bed availability: %w", err)
+		}
+		if beds < 0 {
+			beds = 0
+		}
+		available[roomTypeID] = beds
+	}
+
+	return available, rows.Err()
+}
+
+// GetLongStayRates retrieves the active long-stay rates of the given rate plans,
+// longest threshold first
+func (r *RoomRepository) GetLongStayRates(ctx context.Context, ratePlanIDs []int) ([]models.LongStayRate, error) {
+	query := longStayRateQuery + `
+		WHERE l.is_active = TRUE AND l.rate_plan_id = ANY($1)
+		ORDER BY l.rate_plan_id, l.min_nights DESC, l.room_type_id NULLS LAST
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, ratePlanIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query long-stay rates: %w", err)
+	}
+
+	return collectLongStayRates(rows)
+}
+
+// GetOccupancySurcharges returns the nightly surcharge of a party, keyed by date (YYYY-MM-DD)
+func (r *RoomRepository) GetOccupancySurcharges(ctx context.Context, roomTypeID int, checkIn, checkOut time.Time, occupancy models.Occupancy) (map[string]float64, error) {
+	query := `
+		SELECT d::date, calculate_occupancy_surcharge($3, d::date, $4, $5::int[], $6)
+		FROM generate_series($1::date, $2::date - interval '1 day', interval '1 day') AS d
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, checkIn, checkOut, roomTypeID,
+		occupancy.Adults, occupancy.ChildAges, occupancy.ExtraBeds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get occupancy surcharges: %w", err)
+	}
+	defer rows.Close()
+
+	surcharges := make(map[string]float64)
+	for rows.Next() {
+		var date time.Time
+		var amount float64
+		if err := rows.Scan(&date, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan occupancy surcharge: %w", err)
+		}
+		surcharges[date.Format("2006-01-02")] = amount
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating occupancy surcharges: %w", err)
+	}
+
+	return surcharges, nil
+}
+
+// GetExtraBedsAvailable returns the fewest extra beds free on any night of the stay
+func (r *RoomRepository) GetExtraBedsAvailable(ctx context.Context, roomTypeID int, checkIn, checkOut time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(MIN(rt.extra_bed_allotment - extra_beds_booked(rt.room_type_id, d::date)), 0)
+		FROM room_types rt
+		CROSS JOIN generate_series($2::date, $3::date - interval '1 day', interval '1 day') AS d
+		WHERE rt.room_type_id = $1
+	`
+
+	var available int
+	if err := r.db.Pool.QueryRow(ctx, query, roomTypeID, checkIn, checkOut).Scan(&available); err != nil {
+		return 0, fmt.Errorf("failed to get extra bed availability: %w", err)
+	}
+
+	return available, nil
+}
+
 // PricingDetail represents pricing for a specific date
 type PricingDetail struct {
 	Date  time.Time
@@ -355,31 +721,74 @@ func (r *RoomRepository) GetPricingForDateRange(ctx context.Context, roomTypeID
 	return pricing, nil
 }
 
-// GetAllRoomsWithStatus retrieves all rooms with their current status and guest information
-func (r *RoomRepository) GetAllRoomsWithStatus(ctx context.Context) ([]models.RoomStatus, error) {
+// GetStayRestrictions retrieves restrictions from check-in through check-out (inclusive,
+// so closed-to-departure on the check-out date is included) for the given room types.
+// A nil roomTypeIDs returns restrictions for every room type.
+func (r *RoomRepository) GetStayRestrictions(ctx context.Context, roomTypeIDs []int, checkIn, checkOut time.Time) ([]models.RateRestriction, error) {
 	query := `
-		SELECT 
-			r.room_id,
-			r.room_number,
-			r.occupancy_status,
-			r.housekeeping_status,
-			rt.room_type_id,
-			rt.name as room_type_name,
-			COALESCE(g.first_name || ' ' || g.last_name, '') as guest_name,
-			bd.check_out_date as expected_checkout
-		FROM rooms r
-		INNER JOIN room_types rt ON r.room_type_id = rt.room_type_id
-		LEFT JOIN room_assignments ra ON r.room_id = ra.room_id AND ra.status = 'Active'
-		LEFT JOIN booking_details bd ON ra.booking_detail_id = bd.booking_detail_id
-		LEFT JOIN bookings b ON bd.booking_id = b.booking_id
-		LEFT JOIN guests g ON b.guest_id = g.guest_id
-		ORDER BY r.room_number
+		SELECT restriction_id, room_type_id, rate_plan_id, date, min_los, max_los,
+		       closed_to_arrival, closed_to_departure, stop_sell, notes, created_at, updated_at
+		FROM rate_restrictions
+		WHERE date BETWEEN $1::date AND $2::date
+		  AND ($3::int[] IS NULL OR room_type_id = ANY($3))
+		ORDER BY room_type_id, date
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Pool.Query(ctx, query, checkIn, checkOut, roomTypeIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get room statuses: %w", err)
+		return nil, fmt.Errorf("failed to get stay restrictions: %w", err)
+	}
+	defer rows.Close()
+
+	var restrictions []models.RateRestriction
+	for rows.Next() {
+		var rr models.RateRestriction
+		if err := rows.Scan(
+			&rr.RestrictionID,
+			&rr.RoomTypeID,
+			&rr.RatePlanID,
+			&rr.Date,
+			&rr.MinLOS,
+			&rr.MaxLOS,
+			&rr.ClosedToArrival,
+			&rr.ClosedToDeparture,
+			&rr.StopSell,
+			&rr.Notes,
+			&rr.CreatedAt,
+			&rr.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan stay restriction: %w", err)
+		}
+		restrictions = append(restrictions, rr)
 	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stay restrictions: %w", err)
+	}
+
+	return restrictions, nil
+}
+
+// roomStatusSelect reads the columns scanned by collectRoomStatuses
+const roomStatusSelect = `
+	SELECT 
+		r.room_id,
+		r.room_number,
+		r.occupancy_status,
+		r.housekeeping_status,
+		rt.room_type_id,
+		rt.name as room_type_name,
+		COALESCE(g.first_name || ' ' || g.last_name, '') as guest_name,
+		bd.check_out_date as expected_checkout
+	FROM rooms r
+	INNER JOIN room_types rt ON r.room_type_id = rt.room_type_id
+	LEFT JOIN room_assignments ra ON r.room_id = ra.room_id AND ra.status = 'Active'
+	LEFT JOIN booking_details bd ON ra.booking_detail_id = bd.booking_detail_id
+	LEFT JOIN bookings b ON bd.booking_id = b.booking_id
+	LEFT JOIN guests g ON b.guest_id = g.guest_id`
+
+// collectRoomStatuses scans rows selected with roomStatusSelect
+func collectRoomStatuses(rows pgx.Rows) ([]models.RoomStatus, error) {
 	defer rows.Close()
 
 	var roomStatuses []models.RoomStatus
@@ -412,5 +821,650 @@ func (r *RoomRepository) GetAllRoomsWithStatus(ctx context.Context) ([]models.Ro
 		roomStatuses = append(roomStatuses, rs)
 	}
 
-	return roomStatuses, nil
+	return roomStatuses, rows.Err()
+}
+
+// GetAllRoomsWithStatus retrieves all rooms with their current status and guest information
+func (r *RoomRepository) GetAllRoomsWithStatus(ctx context.Context) ([]models.RoomStatus, error) {
+	query := roomStatusSelect + `
+		WHERE r.is_active = TRUE
+		ORDER BY r.room_number
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room statuses: %w", err)
+	}
+
+	return collectRoomStatuses(rows)
+}
+
+// GetRoomStatuses retrieves the current status of the given rooms, retired or not
+func (r *RoomRepository) GetRoomStatuses(ctx context.Context, roomIDs []int) ([]models.RoomStatus, error) {
+	query := roomStatusSelect + `
+		WHERE r.room_id = ANY($1)
+		ORDER BY r.room_number
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room statuses: %w", err)
+	}
+
+	return collectRoomStatuses(rows)
+}
+
+// pgErrorCode returns the PostgreSQL error code of err, or "" for other errors
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// PostgreSQL error codes for constraint violations
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+)
+
+// CreateRoomType creates a new room type
+func (r *RoomRepository) CreateRoomType(ctx context.Context, rt *models.RoomType) (*models.RoomType, error) {
+	query := `
+		INSERT INTO room_types (
+			name, description, max_occupancy, default_allotment, base_occupancy, max_extra_beds,
+			extra_bed_allotment, base_price, image_url, size_sqm, bed_type, view_type, is_accessible
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING ` + roomTypeColumns
+
+	var created models.RoomType
+	err := scanRoomType(r.db.Pool.QueryRow(ctx, query,
+		rt.Name,
+		rt.Description,
+		rt.MaxOccupancy,
+		rt.DefaultAllotment,
+		rt.BaseOccupancy,
+		rt.MaxExtraBeds,
+		rt.ExtraBedAllotment,
+		rt.BasePrice,
+		rt.ImageURL,
+		rt.SizeSqm,
+		rt.BedType,
+		rt.ViewType,
+		rt.IsAccessible,
+	), &created)
+	if err != nil {
+		if pgErrorCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("room type %q already exists", rt.Name)
+		}
+		return nil, fmt.Errorf("failed to create room type: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateRoomType updates a room type. When the default allotment changes, future
+// inventory still at previousAllotment moves to the new default; dates set by hand
+// keep their allotment.
+func (r *RoomRepository) UpdateRoomType(ctx context.Context, rt *models.RoomType, previousAllotment int) (*models.RoomType, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE room_types
+		SET name = $2,
+			description = $3,
+			max_occupancy = $4,
+			default_allotment = $5,
+			base_occupancy = $6,
+			max_extra_beds = $7,
+			extra_bed_allotment = $8,
+			base_price = $9,
+			image_url = $10,
+			size_sqm = $11,
+			bed_type = $12,
+			view_type = $13,
+			is_accessible = $14
+		WHERE room_type_id = $1
+		RETURNING ` + roomTypeColumns
+
+	var updated models.RoomType
+	err = scanRoomType(tx.QueryRow(ctx, query,
+		rt.RoomTypeID,
+		rt.Name,
+		rt.Description,
+		rt.MaxOccupancy,
+		rt.DefaultAllotment,
+		rt.BaseOccupancy,
+		rt.MaxExtraBeds,
+		rt.ExtraBedAllotment,
+		rt.BasePrice,
+		rt.ImageURL,
+		rt.SizeSqm,
+		rt.BedType,
+		rt.ViewType,
+		rt.IsAccessible,
+	), &updated)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if pgErrorCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("room type %q already exists", rt.Name)
+		}
+		return nil, fmt.Errorf("failed to update room type: %w", err)
+	}
+
+	if rt.DefaultAllotment != previousAllotment {
+		_, err := tx.Exec(ctx, `
+			UPDATE room_inventory
+			SET allotment = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE room_type_id = $1
+			  AND date >= CURRENT_DATE
+			  AND allotment = $3
+		`, rt.RoomTypeID, rt.DefaultAllotment, previousAllotment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update inventory allotment: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// DeleteRoomType deletes a room type without rooms or bookings; its inventory, pricing
+// and amenity assignments are removed with it. Returns false when it does not exist.
+func (r *RoomRepository) DeleteRoomType(ctx context.Context, roomTypeID int) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM room_types WHERE room_type_id = $1`, roomTypeID)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return false, errors.New("room type has rooms or bookings and cannot be deleted")
+		}
+		return false, fmt.Errorf("failed to delete room type: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetFutureInventory retrieves the inventory of a room type from today on
+func (r *RoomRepository) GetFutureInventory(ctx context.Context, roomTypeID int) ([]models.RoomInventory, error) {
+	query := `
+		SELECT room_type_id, date, allotment, booked_count, tentative_count
+		FROM room_inventory
+		WHERE room_type_id = $1 AND date >= CURRENT_DATE
+		ORDER BY date
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get future inventory: %w", err)
+	}
+	defer rows.Close()
+
+	var inventory []models.RoomInventory
+	for rows.Next() {
+		var inv models.RoomInventory
+		if err := rows.Scan(&inv.RoomTypeID, &inv.Date, &inv.Allotment, &inv.BookedCount, &inv.TentativeCount); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory: %w", err)
+		}
+		inv.Available = inv.Allotment - inv.BookedCount - inv.TentativeCount
+		inventory = append(inventory, inv)
+	}
+
+	return inventory, rows.Err()
+}
+
+// CountRooms counts the rooms of a room type, optionally only active rooms
+func (r *RoomRepository) CountRooms(ctx context.Context, roomTypeID int, activeOnly bool) (int, error) {
+	query := `SELECT COUNT(*) FROM rooms WHERE room_type_id = $1 AND ($2 = FALSE OR is_active = TRUE)`
+
+	var count int
+	if err := r.db.Pool.QueryRow(ctx, query, roomTypeID, activeOnly).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rooms: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetRooms retrieves physical rooms, optionally of one room type and only active rooms
+func (r *RoomRepository) GetRooms(ctx context.Context, roomTypeID *int, activeOnly bool) ([]models.Room, error) {
+	query := `
+		SELECT ` + roomColumns + `
+		FROM rooms
+		WHERE ($1::int IS NULL OR room_type_id = $1)
+		  AND ($2 = FALSE OR is_active = TRUE)
+		ORDER BY room_number
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rooms: %w", err)
+	}
+
+	return collectRooms(rows)
+}
+
+// GetRoomByID retrieves a physical room by ID
+func (r *RoomRepository) GetRoomByID(ctx context.Context, roomID int) (*models.Room, error) {
+	query := `SELECT ` + roomColumns + ` FROM rooms WHERE room_id = $1`
+
+	var room models.Room
+	if err := scanRoom(r.db.Pool.QueryRow(ctx, query, roomID), &room); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	return &room, nil
+}
+
+// CreateRoom adds a physical room; new rooms start Vacant and Dirty
+func (r *RoomRepository) CreateRoom(ctx context.Context, room *models.Room) (*models.Room, error) {
+	query := `
+		INSERT INTO rooms (room_type_id, room_number, floor, features, notes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + roomColumns
+
+	var created models.Room
+	err := scanRoom(r.db.Pool.QueryRow(ctx, query,
+		room.RoomTypeID,
+		room.RoomNumber,
+		room.Floor,
+		room.Features,
+		room.Notes,
+	), &created)
+	if err != nil {
+		if pgErrorCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("room %s already exists", room.RoomNumber)
+		}
+		return nil, fmt.Errorf("failed to create room: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateRoom updates a physical room, including its housekeeping status when it is
+// retired or brought back on behalf of updatedBy
+func (r *RoomRepository) UpdateRoom(ctx context.Context, room *models.Room, updatedBy *int) (*models.Room, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setStatusActor(ctx, tx, updatedBy, ""); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE rooms
+		SET room_type_id = $2,
+			room_number = $3,
+			floor = $4,
+			features = $5,
+			notes = $6,
+			housekeeping_status = $7,
+			is_active = $8,
+			connecting_room_id = $9
+		WHERE room_id = $1
+		RETURNING ` + roomColumns
+
+	var updated models.Room
+	err = scanRoom(tx.QueryRow(ctx, query,
+		room.RoomID,
+		room.RoomTypeID,
+		room.RoomNumber,
+		room.Floor,
+		room.Features,
+		room.Notes,
+		room.HousekeepingStatus,
+		room.IsActive,
+		room.ConnectingRoomID,
+	), &updated)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if pgErrorCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("room %s already exists", room.RoomNumber)
+		}
+		return nil, fmt.Errorf("failed to update room: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// amenityColumns is the column list read by scanAmenity
+const amenityColumns = `amenity_id, name, description, icon, category`
+
+func scanAmenity(row pgx.Row, amenity *models.Amenity) error {
+	return row.Scan(
+		&amenity.AmenityID,
+		&amenity.Name,
+		&amenity.Description,
+		&amenity.Icon,
+		&amenity.Category,
+	)
+}
+
+// GetAllAmenities retrieves every amenity
+func (r *RoomRepository) GetAllAmenities(ctx context.Context) ([]models.Amenity, error) {
+	query := `SELECT ` + amenityColumns + ` FROM amenities ORDER BY category NULLS LAST, name`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get amenities: %w", err)
+	}
+	defer rows.Close()
+
+	var amenities []models.Amenity
+	for rows.Next() {
+		var amenity models.Amenity
+		if err := scanAmenity(rows, &amenity); err != nil {
+			return nil, fmt.Errorf("failed to scan amenity: %w", err)
+		}
+		amenities = append(amenities, amenity)
+	}
+
+	return amenities, rows.Err()
+}
+
+// GetAmenityByID retrieves an amenity by ID
+func (r *RoomRepository) GetAmenityByID(ctx context.Context, amenityID int) (*models.Amenity, error) {
+	query := `SELECT ` + amenityColumns + ` FROM amenities WHERE amenity_id = $1`
+
+	var amenity models.Amenity
+	if err := scanAmenity(r.db.Pool.QueryRow(ctx, query, amenityID), &amenity); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get amenity: %w", err)
+	}
+
+	return &amenity, nil
+}
+
+// CreateAmenity creates a new amenity
+func (r *RoomRepository) CreateAmenity(ctx context.Context, amenity *models.Amenity) (*models.Amenity, error) {
+	query := `
+		INSERT INTO amenities (name, description, icon, category)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + amenityColumns
+
+	var created models.Amenity
+	err := scanAmenity(r.db.Pool.QueryRow(ctx, query,
+		amenity.Name,
+		amenity.Description,
+		amenity.Icon,
+		amenity.Category,
+	), &created)
+	if err != nil {
+		if pgErrorCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("amenity %q already exists", amenity.Name)
+		}
+		return nil, fmt.Errorf("failed to create amenity: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateAmenity updates an amenity
+func (r *RoomRepository) UpdateAmenity(ctx context.Context, amenity *models.Amenity) (*models.Amenity, error) {
+	query := `
+		UPDATE amenities
+		SET name = $2,
+			description = $3,
+			icon = $4,
+			category = $5
+		WHERE amenity_id = $1
+		RETURNING ` + amenityColumns
+
+	var updated models.Amenity
+	err := scanAmenity(r.db.Pool.QueryRow(ctx, query,
+		amenity.AmenityID,
+		amenity.Name,
+		amenity.Description,
+		amenity.Icon,
+		amenity.Category,
+	), &updated)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if pgErrorCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("amenity %q already exists", amenity.Name)
+		}
+		return nil, fmt.Errorf("failed to update amenity: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// DeleteAmenity deletes an amenity and removes it from every room type.
+// Returns false when it does not exist.
+func (r *RoomRepository) DeleteAmenity(ctx context.Context, amenityID int) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM amenities WHERE amenity_id = $1`, amenityID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete amenity: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// SetRoomTypeAmenities replaces the amenities of a room type
+func (r *RoomRepository) SetRoomTypeAmenities(ctx context.Context, roomTypeID int, amenityIDs []int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM room_type_amenities WHERE room_type_id = $1`, roomTypeID); err != nil {
+		return fmt.Errorf("failed to clear room type amenities: %w", err)
+	}
+
+	if len(amenityIDs) > 0 {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO room_type_amenities (room_type_id, amenity_id)
+			SELECT $1, unnest($2::int[])
+			ON CONFLICT DO NOTHING
+		`, roomTypeID, amenityIDs)
+		if err != nil {
+			return fmt.Errorf("failed to set room type amenities: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// roomTypeImageColumns is the column list read by scanRoomTypeImage
+const roomTypeImageColumns = `image_id, room_type_id, storage_key, caption, sort_order, width, height, created_at`
+
+func scanRoomTypeImage(row pgx.Row, img *models.RoomTypeImage) error {
+	return row.Scan(
+		&img.ImageID,
+		&img.RoomTypeID,
+		&img.StorageKey,
+		&img.Caption,
+		&img.SortOrder,
+		&img.Width,
+		&img.Height,
+		&img.CreatedAt,
+	)
+}
+
+// GetRoomTypeImages retrieves the gallery of a room type in display order
+func (r *RoomRepository) GetRoomTypeImages(ctx context.Context, roomTypeID int) ([]models.RoomTypeImage, error) {
+	query := `
+		SELECT ` + roomTypeImageColumns + `
+		FROM room_type_images
+		WHERE room_type_id = $1
+		ORDER BY sort_order, image_id
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, roomTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room type images: %w", err)
+	}
+	defer rows.Close()
+
+	var images []models.RoomTypeImage
+	for rows.Next() {
+		var img models.RoomTypeImage
+		if err := scanRoomTypeImage(rows, &img); err != nil {
+			return nil, fmt.Errorf("failed to scan room type image: %w", err)
+		}
+		images = append(images, img)
+	}
+
+	return images, rows.Err()
+}
+
+// GetRoomTypeImageByID retrieves an image of a room type
+func (r *RoomRepository) GetRoomTypeImageByID(ctx context.Context, roomTypeID, imageID int) (*models.RoomTypeImage, error) {
+	query := `SELECT ` + roomTypeImageColumns + ` FROM room_type_images WHERE room_type_id = $1 AND image_id = $2`
+
+	var img models.RoomTypeImage
+	if err := scanRoomTypeImage(r.db.Pool.QueryRow(ctx, query, roomTypeID, imageID), &img); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get room type image: %w", err)
+	}
+
+	return &img, nil
+}
+
+// CreateRoomTypeImage adds an image at the end of a room type's gallery
+func (r *RoomRepository) CreateRoomTypeImage(ctx context.Context, img *models.RoomTypeImage) (*models.RoomTypeImage, error) {
+	query := `
+		INSERT INTO room_type_images (room_type_id, storage_key, caption, sort_order, width, height)
+		SELECT $1, $2, $3, COALESCE(MAX(sort_order) + 1, 0), $4, $5
+		FROM room_type_images
+		WHERE room_type_id = $1
+		RETURNING ` + roomTypeImageColumns
+
+	var created models.RoomTypeImage
+	err := scanRoomTypeImage(r.db.Pool.QueryRow(ctx, query,
+		img.RoomTypeID,
+		img.StorageKey,
+		img.Caption,
+		img.Width,
+		img.Height,
+	), &created)
+	if err != nil {
+		if pgErrorCode(err) == pgUniqueViolation {
+			return nil, errors.New("image is already in the gallery")
+		}
+		return nil, fmt.Errorf("failed to create room type image: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateRoomTypeImageCaption changes the caption of an image
+func (r *RoomRepository) UpdateRoomTypeImageCaption(ctx context.Context, roomTypeID, imageID int, caption *string) (*models.RoomTypeImage, error) {
+	query := `
+		UPDATE room_type_images
+		SET caption = $3
+		WHERE room_type_id = $1 AND image_id = $2
+		RETURNING ` + roomTypeImageColumns
+
+	var updated models.RoomTypeImage
+	if err := scanRoomTypeImage(r.db.Pool.QueryRow(ctx, query, roomTypeID, imageID, caption), &updated); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update room type image: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// ReorderRoomTypeImages sets the display order of a room type's gallery to the order
+// of imageIDs
+func (r *RoomRepository) ReorderRoomTypeImages(ctx context.Context, roomTypeID int, imageIDs []int) error {
+	query := `
+		UPDATE room_type_images rti
+		SET sort_order = o.position - 1
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(image_id, position)
+		WHERE rti.room_type_id = $1 AND rti.image_id = o.image_id
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, roomTypeID, imageIDs); err != nil {
+		return fmt.Errorf("failed to reorder room type images: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRoomTypeImage removes an image from a room type's gallery.
+// Returns false when it does not exist.
+func (r *RoomRepository) DeleteRoomTypeImage(ctx context.Context, roomTypeID, imageID int) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM room_type_images WHERE room_type_id = $1 AND image_id = $2`, roomTypeID, imageID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete room type image: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetUpgradeRoomTypes lists the room types above roomTypeID by base price that sleep
+// numGuests and have a room left on every night from from to to (exclusive), with the
+// fewest rooms left on a night as AvailableRooms
+func (r *RoomRepository) GetUpgradeRoomTypes(ctx context.Context, roomTypeID, numGuests int, from, to time.Time) ([]models.RoomType, error) {
+	if err := r.ensureInventoryExists(ctx, from, to); err != nil {
+		return nil, fmt.Errorf("failed to ensure inventory exists: %w", err)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT rt.room_type_id, rt.name, COALESCE(rt.description, ''), rt.max_occupancy,
+			rt.base_price, rt.image_url,
+			MIN(ri.allotment - ri.booked_count - ri.tentative_count) AS available
+		FROM room_types rt
+		JOIN room_inventory ri ON ri.room_type_id = rt.room_type_id
+			AND ri.date >= $2 AND ri.date < $3
+		WHERE rt.room_type_id <> $1
+		  AND rt.max_occupancy >= $4
+		  AND rt.base_price > (SELECT base_price FROM room_types WHERE room_type_id = $1)
+		GROUP BY rt.room_type_id
+		HAVING COUNT(*) = ($3::date - $2::date)
+		   AND MIN(ri.allotment - ri.booked_count - ri.tentative_count) > 0
+		ORDER BY rt.base_price, rt.name
+	`, roomTypeID, from, to, numGuests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upgrade room types: %w", err)
+	}
+	defer rows.Close()
+
+	var roomTypes []models.RoomType
+	for rows.Next() {
+		var rt models.RoomType
+		var available int
+		if err := rows.Scan(&rt.RoomTypeID, &rt.Name, &rt.Description, &rt.MaxOccupancy,
+			&rt.BasePrice, &rt.ImageURL, &available); err != nil {
+			return nil, fmt.Errorf("failed to scan upgrade room type: %w", err)
+		}
+		rt.AvailableRooms = &available
+		roomTypes = append(roomTypes, rt)
+	}
+	return roomTypes, rows.Err()
 }
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type WaitlistRepository struct {
+	db *database.DB
+}
+
+func NewWaitlistRepository(db *database.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+const waitlistSelect = `
+	SELECT w.waitlist_id, w.guest_id, w.first_name, w.last_name, w.email, w.phone,
+	       w.room_type_id, rt.name, w.check_in_date, w.check_out_date, w.num_guests,
+	       w.status, w.session_id, w.offered_at, w.offer_expires_at, w.offer_count,
+	       w.booking_id, w.created_at, w.updated_at
+	FROM waitlist_entries w
+	JOIN room_types rt ON w.room_type_id = rt.room_type_id
+`
+
+func scanWaitlistEntry(row pgx.Row) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := row.Scan(
+		&entry.WaitlistID,
+		&entry.GuestID,
+		&entry.FirstName,
+		&entry.LastName,
+		&entry.Email,
+		&entry.Phone,
+		&entry.RoomTypeID,
+		&entry.RoomTypeName,
+		&entry.CheckInDate,
+		&entry.CheckOutDate,
+		&entry.NumGuests,
+		&entry.Status,
+		&entry.SessionID,
+		&entry.OfferedAt,
+		&entry.OfferExpiresAt,
+		&entry.OfferCount,
+		&entry.BookingID,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateEntry adds a guest to the waitlist
+func (r *WaitlistRepository) CreateEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	query := `
+		INSERT INTO waitlist_entries (guest_id, first_name, last_name, email, phone,
+		                              room_type_id, check_in_date, check_out_date, num_guests)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING waitlist_id, status, offer_count, created_at, updated_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		entry.GuestID,
+		entry.FirstName,
+		entry.LastName,
+		entry.Email,
+		entry.Phone,
+		entry.RoomTypeID,
+		entry.CheckInDate,
+		entry.CheckOutDate,
+		entry.NumGuests,
+	).Scan(&entry.WaitlistID, &entry.Status, &entry.OfferCount, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetEntryByID retrieves a waitlist entry by ID
+func (r *WaitlistRepository) GetEntryByID(ctx context.Context, waitlistID int) (*models.WaitlistEntry, error) {
+	entry, err := scanWaitlistEntry(r.db.Pool.QueryRow(ctx, waitlistSelect+` WHERE w.waitlist_id = $1`, waitlistID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+// GetEntries retrieves waitlist entries, optionally filtered by status and guest, in queue order
+func (r *WaitlistRepository) GetEntries(ctx context.Context, status string, guestID *int) ([]models.WaitlistEntry, error) {
+	query := waitlistSelect + ` WHERE 1=1`
+	args := []interface{}{}
+
+	if status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(" AND w.status = $%d", len(args))
+	}
+	if guestID != nil {
+		args = append(args, *guestID)
+		query += fmt.Sprintf(" AND w.guest_id = $%d", len(args))
+	}
+	query += " ORDER BY w.created_at, w.waitlist_id"
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.WaitlistEntry{}
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, nil
+}
+
+// CountAhead returns how many waiting entries for the same room type are ahead of the given entry
+func (r *WaitlistRepository) CountAhead(ctx context.Context, entry *models.WaitlistEntry) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM waitlist_entries
+		WHERE room_type_id = $1
+		  AND status = 'Waiting'
+		  AND (created_at, waitlist_id) < ($2, $3)
+		  AND check_in_date < $5
+		  AND check_out_date > $4
+	`
+
+	var count int
+	err := r.db.Pool.QueryRow(ctx, query,
+		entry.RoomTypeID, entry.CreatedAt, entry.WaitlistID, entry.CheckInDate, entry.CheckOutDate,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count waitlist position: %w", err)
+	}
+	return count, nil
+}
+
+// CancelEntry cancels a waiting or offered entry and releases any offered hold
+func (r *WaitlistRepository) CancelEntry(ctx context.Context, waitlistID int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var sessionID *string
+	err = tx.QueryRow(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'Cancelled'
+		WHERE waitlist_id = $1 AND status IN ('Waiting', 'Offered')
+		RETURNING session_id
+	`, waitlistID).Scan(&sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("waitlist entry cannot be cancelled")
+		}
+		return fmt.Errorf("failed to cancel waitlist entry: %w", err)
+	}
+
+	if sessionID != nil {
+		if err := releaseSessionHolds(ctx, tx, *sessionID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit waitlist cancellation: %w", err)
+	}
+	return nil
+}
+
+// releaseSessionHolds removes the holds of a session and returns their tentative count to inventory
+func releaseSessionHolds(ctx context.Context, tx pgx.Tx, sessionID string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE room_inventory ri
+		SET tentative_count = GREATEST(0, ri.tentative_count - h.hold_count),
+		    updated_at = NOW()
+		FROM (
+			SELECT room_type_id, date, COUNT(*) AS hold_count
+			FROM booking_holds
+			WHERE session_id = $1
+			GROUP BY room_type_id, date
+		) h
+		WHERE ri.room_type_id = h.room_type_id AND ri.date = h.date
+	`, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to release held inventory: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM booking_holds WHERE session_id = $1`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete holds: %w", err)
+	}
+	return nil
+}
@@ -1,19 +1,24 @@
 package router
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
+	"github.com/hotel-booking-system/backend/internal/events"
 	"github.com/hotel-booking-system/backend/internal/handlers"
 	"github.com/hotel-booking-system/backend/internal/jobs"
 	"github.com/hotel-booking-system/backend/internal/middleware"
+	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/internal/repository"
 	"github.com/hotel-booking-system/backend/internal/service"
 	"github.com/hotel-booking-system/backend/pkg/cache"
 	"github.com/hotel-booking-system/backend/pkg/config"
 	"github.com/hotel-booking-system/backend/pkg/database"
+	"github.com/hotel-booking-system/backend/pkg/storage"
 )
 
 // Setup creates and configures the Gin router
-func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, nightAudit *jobs.NightAuditJob, holdCleanup *jobs.HoldCleanupJob) *gin.Engine {
+func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, blobs *storage.LocalBlobStore, nightAudit *jobs.NightAuditJob, holdCleanup *jobs.HoldCleanupJob, waitlistMatcher *jobs.WaitlistMatcherJob, dynamicPricing *jobs.DynamicPricingJob, outOfOrder *jobs.OutOfOrderJob, roomPreAssignment *jobs.RoomPreAssignmentJob, roomEvents *events.Bus) *gin.Engine {
 	// Set Gin mode
 	gin.SetMode(cfg.Server.GinMode)
 
@@ -32,29 +37,74 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 	roomRepo := repository.NewRoomRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
 	housekeepingRepo := repository.NewHousekeepingRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
 	pricingRepo := repository.NewPricingRepository(db)
 	inventoryRepo := repository.NewInventoryRepository(db)
 	policyRepo := repository.NewPolicyRepository(db)
 	reportRepo := repository.NewReportRepository(db.Pool)
 	paymentProofRepo := repository.NewPaymentProofRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	addOnRepo := repository.NewAddOnRepository(db)
 
 	// Initialize services
 	authService := service.NewAuthService(authRepo, cfg.JWT.Secret)
 	roomService := service.NewRoomService(roomRepo, redisCache)
 	bookingService := service.NewBookingService(bookingRepo, roomRepo)
-	housekeepingService := service.NewHousekeepingService(housekeepingRepo)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepo)
+	housekeepingService := service.NewHousekeepingService(housekeepingRepo, maintenanceService)
 	pricingService := service.NewPricingService(pricingRepo, redisCache)
 	inventoryService := service.NewInventoryService(inventoryRepo, roomRepo)
 	policyService := service.NewPolicyService(policyRepo)
 	reportService := service.NewReportService(reportRepo)
 	paymentProofService := service.NewPaymentProofService(paymentProofRepo)
+	waitlistService := service.NewWaitlistService(waitlistRepo, roomRepo)
+	addOnService := service.NewAddOnService(addOnRepo, pricingRepo)
+
+	// Wake the waitlist matcher and drop cached availability calendars whenever
+	// inventory may have been freed up
+	inventoryListeners := service.InventoryListeners{waitlistMatcher, roomService}
+	bookingService.SetInventoryListener(inventoryListeners)
+	inventoryService.SetInventoryListener(inventoryListeners)
+	waitlistService.SetInventoryListener(inventoryListeners)
+	roomService.SetInventoryListener(waitlistMatcher) // Drops its own calendars
+	bookingService.SetAvailabilityCache(roomService)  // Holds and confirmations take rooms
+	holdCleanup.OnRelease(inventoryListeners.InventoryReleased)
+	waitlistMatcher.OnHoldsChanged(roomService.InventoryReleased) // Offers hold rooms
+	if blobs != nil {
+		roomService.SetBlobStore(blobs)
+		maintenanceService.SetBlobStore(blobs)
+	}
+
+	// Stream room changes to the room status board
+	roomEventService := service.NewRoomEventService(roomRepo, roomEvents)
+	bookingService.SetRoomChangeListener(roomEventService)
+	housekeepingService.SetRoomChangeListener(roomEventService)
+	maintenanceService.SetRoomChangeListener(roomEventService)
+	roomService.SetRoomChangeListener(roomEventService)
+	inventoryService.SetRoomChangeListener(roomEventService)
+	nightAudit.OnRoomsChanged(func(roomIDs []int) {
+		roomEventService.RoomsChanged(context.Background(), models.RoomEventStatus, roomIDs...)
+	})
+
+	// Drop cached calendars when the dynamic pricing engine moves dates to another tier
+	dynamicPricing.OnChange(func() {
+		_ = pricingService.InvalidatePricingCalendarCache()
+		_ = roomService.InvalidateAvailabilityCalendarCache()
+	})
+
+	// Drop cached availability calendars when rates, plans or restrictions change
+	pricingService.OnChange(func() {
+		_ = roomService.InvalidateAvailabilityCalendarCache()
+	})
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	roomHandler := handlers.NewRoomHandler(roomService)
+	roomEventHandler := handlers.NewRoomEventHandler(roomEvents)
 	bookingHandler := handlers.NewBookingHandler(bookingService)
 	checkInHandler := handlers.NewCheckInHandler(bookingService)
 	housekeepingHandler := handlers.NewHousekeepingHandler(housekeepingService)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
 	pricingHandler := handlers.NewPricingHandler(pricingService)
 	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
 	policyHandler := handlers.NewPolicyHandler(policyService)
@@ -62,11 +112,26 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 	nightAuditHandler := handlers.NewNightAuditHandler(nightAudit)
 	holdCleanupHandler := handlers.NewHoldCleanupHandler(holdCleanup)
 	paymentProofHandler := handlers.NewPaymentProofHandler(paymentProofService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
+	waitlistMatcherHandler := handlers.NewWaitlistMatcherHandler(waitlistMatcher)
+	dynamicPricingHandler := handlers.NewDynamicPricingHandler(dynamicPricing)
+	outOfOrderHandler := handlers.NewOutOfOrderHandler(outOfOrder)
+	roomPreAssignmentHandler := handlers.NewRoomPreAssignmentHandler(roomPreAssignment)
+	addOnHandler := handlers.NewAddOnHandler(addOnService)
 
 	// Serve API documentation
 	r.Static("/docs", "./backend/docs/swagger-ui")
 	r.StaticFile("/swagger.yaml", "./backend/docs/swagger.yaml")
 
+	// Serve uploaded images; their URLs change whenever the file does, so they are cached for good
+	if blobs != nil {
+		uploads := r.Group(cfg.Storage.ServePath)
+		uploads.Use(func(c *gin.Context) {
+			c.Header("Cache-Control", "public, max-age=31536000, immutable")
+		})
+		uploads.Static("/", blobs.Dir())
+	}
+
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -129,10 +194,13 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 		rooms := api.Group("/rooms")
 		rooms.Use(middleware.SearchRateLimiter.Middleware())
 		{
-			rooms.GET("/search", roomHandler.SearchRooms)
+			rooms.GET("/search", middleware.OptionalAuth(cfg.JWT.Secret), roomHandler.SearchRooms)
 			rooms.GET("/types", roomHandler.GetAllRoomTypes)
 			rooms.GET("/types/:id", roomHandler.GetRoomTypeByID)
 			rooms.GET("/types/:id/pricing", roomHandler.GetRoomTypePricing)
+			rooms.GET("/day-use", roomHandler.SearchDayUse)
+			rooms.GET("/calendar", roomHandler.GetAvailabilityCalendar)
+			rooms.GET("/amenities", roomHandler.GetAllAmenities)
 
 			// Protected endpoint for receptionist + manager
 			protected := rooms.Group("")
@@ -141,6 +209,38 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			{
 				protected.GET("/status", roomHandler.GetRoomStatusDashboard)
 			}
+
+			// Live room status board for all staff, filtered by role
+			board := rooms.Group("")
+			board.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+			board.Use(middleware.RequireStaff())
+			{
+				board.GET("/status/stream", roomEventHandler.StreamRoomEvents)
+			}
+
+			// Manager-only room type, room and amenity management
+			managed := rooms.Group("")
+			managed.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+			managed.Use(middleware.RequireManager()) // MANAGER only
+			{
+				managed.POST("/types", roomHandler.CreateRoomType)
+				managed.PUT("/types/:id", roomHandler.UpdateRoomType)
+				managed.DELETE("/types/:id", roomHandler.DeleteRoomType)
+				managed.PUT("/types/:id/amenities", roomHandler.SetRoomTypeAmenities)
+				managed.POST("/types/:id/images", roomHandler.UploadRoomTypeImage)
+				managed.PUT("/types/:id/images", roomHandler.ReorderRoomTypeImages)
+				managed.PUT("/types/:id/images/:imageId", roomHandler.UpdateRoomTypeImage)
+				managed.DELETE("/types/:id/images/:imageId", roomHandler.DeleteRoomTypeImage)
+
+				managed.GET("/units", roomHandler.GetRooms)
+				managed.POST("/units", roomHandler.CreateRoom)
+				managed.PUT("/units/:id", roomHandler.UpdateRoom)
+				managed.DELETE("/units/:id", roomHandler.RetireRoom)
+
+				managed.POST("/amenities", roomHandler.CreateAmenity)
+				managed.PUT("/amenities/:id", roomHandler.UpdateAmenity)
+				managed.DELETE("/amenities/:id", roomHandler.DeleteAmenity)
+			}
 		}
 
 		// Booking routes (with booking rate limiting)
@@ -156,6 +256,7 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			optionalAuth.Use(middleware.OptionalAuth(cfg.JWT.Secret))
 			{
 				optionalAuth.POST("/", bookingHandler.CreateBooking)
+				optionalAuth.POST("/day-use", bookingHandler.CreateDayUseBooking)
 				optionalAuth.POST("/:id/confirm", bookingHandler.ConfirmBooking)
 			}
 
@@ -165,6 +266,12 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			{
 				protected.GET("/", bookingHandler.GetBookings)
 				protected.GET("/:id", bookingHandler.GetBookingByID)
+				protected.GET("/:id/folio", bookingHandler.GetBookingFolio)
+				protected.GET("/:id/billing-schedule", bookingHandler.GetBillingSchedule)
+				protected.GET("/:id/upgrade-offers", bookingHandler.GetUpgradeOffers)
+				protected.POST("/:id/upgrade-offers/accept", bookingHandler.AcceptUpgradeOffer)
+				protected.POST("/:id/stay-time-requests", bookingHandler.RequestStayTime)
+				protected.DELETE("/:id/stay-time-requests/:requestId", bookingHandler.CancelStayTimeRequest)
 				protected.POST("/:id/cancel", bookingHandler.CancelBooking)
 				protected.POST("/sync", bookingHandler.SyncBookings)
 
@@ -173,6 +280,35 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 				receptionist.Use(middleware.RequireReceptionist()) // RECEPTIONIST or MANAGER
 				{
 					receptionist.POST("/:id/no-show", checkInHandler.MarkNoShow)
+					receptionist.POST("/:id/rooming-list", bookingHandler.ImportRoomingList)
+				}
+			}
+		}
+
+		// Waitlist routes (with booking rate limiting)
+		waitlist := api.Group("/waitlist")
+		waitlist.Use(middleware.BookingRateLimiter.Middleware())
+		{
+			// Optional auth endpoints - work with or without authentication
+			optionalAuth := waitlist.Group("")
+			optionalAuth.Use(middleware.OptionalAuth(cfg.JWT.Secret))
+			{
+				optionalAuth.POST("", waitlistHandler.JoinWaitlist)
+			}
+
+			// Protected endpoints - require authentication
+			protected := waitlist.Group("")
+			protected.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+			{
+				protected.GET("/mine", waitlistHandler.GetMyWaitlist)
+				protected.GET("/:id", waitlistHandler.GetWaitlistEntry)
+				protected.POST("/:id/cancel", waitlistHandler.CancelWaitlistEntry)
+
+				// Receptionist + Manager endpoints
+				receptionist := protected.Group("")
+				receptionist.Use(middleware.RequireReceptionist()) // RECEPTIONIST or MANAGER
+				{
+					receptionist.GET("", waitlistHandler.GetWaitlist)
 				}
 			}
 		}
@@ -186,6 +322,21 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			checkin.POST("/move-room", checkInHandler.MoveRoom)
 			checkin.GET("/arrivals", checkInHandler.GetArrivals)
 			checkin.GET("/available-rooms/:roomTypeId", checkInHandler.GetAvailableRooms)
+			checkin.PUT("/pre-assignments/:id", checkInHandler.SetPreAssignment)
+			checkin.DELETE("/pre-assignments/:id", checkInHandler.ClearPreAssignment)
+			checkin.PUT("/booking-details/:id/preferences", checkInHandler.SetRoomPreferences)
+			checkin.PUT("/guests/:id/vip", checkInHandler.SetGuestVIP)
+			checkin.POST("/upgrades", checkInHandler.UpgradeRoom)
+			checkin.GET("/stay-time-requests", checkInHandler.GetStayTimeRequests)
+			checkin.POST("/stay-time-requests", checkInHandler.GrantStayTime)
+			checkin.POST("/stay-time-requests/:id/approve", checkInHandler.ApproveStayTimeRequest)
+			checkin.POST("/stay-time-requests/:id/decline", checkInHandler.DeclineStayTimeRequest)
+			checkin.GET("/walks", checkInHandler.GetWalks)
+			checkin.POST("/walks", checkInHandler.WalkGuest)
+			checkin.GET("/partner-hotels", inventoryHandler.GetPartnerHotels)
+			checkin.GET("/rooms/:id/service", housekeepingHandler.GetServicePreferences)
+			checkin.PUT("/rooms/:id/dnd", housekeepingHandler.SetDoNotDisturb)
+			checkin.PUT("/rooms/:id/green-program", housekeepingHandler.SetGreenOptOut)
 		}
 
 		// Check-out routes (Receptionist + Manager)
@@ -207,6 +358,44 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			housekeeping.POST("/rooms/:id/inspect", housekeepingHandler.InspectRoom)
 			housekeeping.GET("/inspection", housekeepingHandler.GetRoomsForInspection)
 			housekeeping.POST("/rooms/:id/maintenance", housekeepingHandler.ReportMaintenance)
+			housekeeping.GET("/rooms/:id/history", housekeepingHandler.GetRoomHistory)
+
+			// Supervisors hand rooms out to attendants
+			supervision := housekeeping.Group("")
+			supervision.Use(middleware.RequireManager())
+			{
+				supervision.PUT("/rooms/:id/assign", housekeepingHandler.AssignRoom)
+				supervision.POST("/assignments/auto", housekeepingHandler.AutoAssign)
+				supervision.GET("/credits", housekeepingHandler.GetCredits)
+				supervision.PUT("/credits/:roomTypeId", housekeepingHandler.UpdateCredits)
+				supervision.GET("/reports/cleaning-times", housekeepingHandler.GetCleaningTimeReport)
+			}
+		}
+
+		// Maintenance routes (any staff can report; engineers work the orders)
+		maintenance := api.Group("/maintenance")
+		maintenance.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+		maintenance.Use(middleware.RequireStaff())
+		{
+			maintenance.POST("/work-orders", maintenanceHandler.CreateWorkOrder)
+			maintenance.GET("/work-orders", maintenanceHandler.GetWorkOrders)
+			maintenance.GET("/work-orders/:id", maintenanceHandler.GetWorkOrder)
+			maintenance.POST("/work-orders/:id/photos", maintenanceHandler.UploadWorkOrderPhoto)
+
+			engineering := maintenance.Group("")
+			engineering.Use(middleware.RequireEngineer()) // ENGINEER or MANAGER
+			{
+				engineering.GET("/my-tasks", maintenanceHandler.GetMyTasks)
+				engineering.PUT("/work-orders/:id/status", maintenanceHandler.UpdateWorkOrderStatus)
+			}
+
+			managed := maintenance.Group("")
+			managed.Use(middleware.RequireManager())
+			{
+				managed.PUT("/work-orders/:id", maintenanceHandler.UpdateWorkOrder)
+				managed.PUT("/work-orders/:id/assign", maintenanceHandler.AssignWorkOrder)
+				managed.GET("/reports/mttr", maintenanceHandler.GetRepairTimeReport)
+			}
 		}
 
 		// Pricing Management routes (Manager only)
@@ -232,6 +421,62 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 
 			// Rate Plans
 			pricing.GET("/plans", pricingHandler.GetAllRatePlans)
+			pricing.POST("/plans", pricingHandler.CreateRatePlan)
+			pricing.PUT("/plans/:id", pricingHandler.UpdateRatePlan)
+			pricing.GET("/plans/:id/inclusions", addOnHandler.GetRatePlanInclusions)
+			pricing.PUT("/plans/:id/inclusions", addOnHandler.SetRatePlanInclusions)
+
+			// Stay Restrictions (MinLOS/MaxLOS, CTA/CTD, Stop-sell)
+			pricing.GET("/restrictions", pricingHandler.GetRateRestrictions)
+			pricing.POST("/restrictions/bulk", pricingHandler.BulkUpdateRestrictions)
+
+			// Occupancy Surcharges (extra adult, child age bands, extra beds)
+			pricing.GET("/surcharges", pricingHandler.GetOccupancySurcharges)
+			pricing.PUT("/surcharges", pricingHandler.UpsertOccupancySurcharge)
+			pricing.DELETE("/surcharges/:id", pricingHandler.DeleteOccupancySurcharge)
+			pricing.PUT("/occupancy/:roomTypeId", pricingHandler.UpdateRoomTypeOccupancy)
+
+			// Day-use Rates (hourly slots)
+			pricing.GET("/day-use", pricingHandler.GetAllDayUseRates)
+			pricing.PUT("/day-use", pricingHandler.UpsertDayUseRate)
+			pricing.DELETE("/day-use/:id", pricingHandler.DeleteDayUseRate)
+
+			// Early Check-in and Late Check-out Fees (tiers by hour)
+			pricing.GET("/stay-time-fees", pricingHandler.GetAllStayTimeFees)
+			pricing.PUT("/stay-time-fees", pricingHandler.UpsertStayTimeFee)
+			pricing.DELETE("/stay-time-fees/:id", pricingHandler.DeleteStayTimeFee)
+
+			// Long-stay Rates (weekly and monthly thresholds)
+			pricing.GET("/long-stay", pricingHandler.GetAllLongStayRates)
+			pricing.PUT("/long-stay", pricingHandler.UpsertLongStayRate)
+			pricing.DELETE("/long-stay/:id", pricingHandler.DeleteLongStayRate)
+
+			// Dynamic Pricing Rules (occupancy thresholds, booking pace)
+			pricing.GET("/rules", pricingHandler.GetAllPricingRules)
+			pricing.POST("/rules", pricingHandler.CreatePricingRule)
+			pricing.PUT("/rules/:id", pricingHandler.UpdatePricingRule)
+			pricing.DELETE("/rules/:id", pricingHandler.DeletePricingRule)
+			pricing.GET("/rules/changes", pricingHandler.GetPricingRuleChanges)
+			pricing.POST("/rules/preview", dynamicPricingHandler.Preview)
+		}
+
+		// Add-on catalog routes
+		addons := api.Group("/addons")
+		{
+			// Public endpoints for the add-ons on sale
+			addons.GET("", addOnHandler.GetActiveAddOns)
+			addons.GET("/:id", addOnHandler.GetAddOnByID)
+
+			// Manager-only catalog management
+			managed := addons.Group("")
+			managed.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+			managed.Use(middleware.RequireManager()) // MANAGER only
+			{
+				managed.GET("/all", addOnHandler.GetAllAddOns)
+				managed.POST("", addOnHandler.CreateAddOn)
+				managed.PUT("/:id", addOnHandler.UpdateAddOn)
+				managed.DELETE("/:id", addOnHandler.DeactivateAddOn)
+			}
 		}
 
 		// Inventory Management routes (Manager only)
@@ -243,6 +488,19 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			inventory.GET("/:roomTypeId/:date", inventoryHandler.GetInventoryByDate)
 			inventory.PUT("", inventoryHandler.UpdateInventory)
 			inventory.POST("/bulk", inventoryHandler.BulkUpdateInventory)
+
+			// Out-of-order rooms
+			inventory.GET("/out-of-order", inventoryHandler.GetOutOfOrderPeriods)
+			inventory.POST("/out-of-order", inventoryHandler.ScheduleOutOfOrder)
+			inventory.PUT("/out-of-order/:id", inventoryHandler.UpdateOutOfOrder)
+			inventory.DELETE("/out-of-order/:id", inventoryHandler.EndOutOfOrder)
+
+			// Overbooking and partner hotels for walked guests
+			inventory.GET("/overbooking", inventoryHandler.GetOverbookingPositions)
+			inventory.PUT("/overbooking", inventoryHandler.SetOverbookingLimit)
+			inventory.GET("/partner-hotels", inventoryHandler.GetPartnerHotels)
+			inventory.POST("/partner-hotels", inventoryHandler.CreatePartnerHotel)
+			inventory.PUT("/partner-hotels/:id", inventoryHandler.UpdatePartnerHotel)
 		}
 
 		// Cancellation Policy Management routes (Manager only)
@@ -286,6 +544,7 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			reports.GET("/revenue", reportHandler.GetRevenueReport)
 			reports.GET("/vouchers", reportHandler.GetVoucherReport)
 			reports.GET("/no-shows", reportHandler.GetNoShowReport)
+			reports.GET("/addons", reportHandler.GetAddOnRevenueReport)
 			reports.GET("/summary", reportHandler.GetReportSummary)
 			reports.GET("/comparison", reportHandler.GetComparisonReport)
 
@@ -319,6 +578,22 @@ func Setup(cfg *config.Config, db *database.DB, redisCache *cache.RedisCache, ni
 			// Hold Cleanup endpoints
 			admin.POST("/hold-cleanup/trigger", holdCleanupHandler.TriggerManual)
 			admin.GET("/hold-cleanup/status", holdCleanupHandler.GetStatus)
+
+			// Waitlist Matcher endpoints
+			admin.POST("/waitlist-matcher/trigger", waitlistMatcherHandler.TriggerManual)
+			admin.GET("/waitlist-matcher/status", waitlistMatcherHandler.GetStatus)
+
+			// Dynamic Pricing endpoints
+			admin.POST("/dynamic-pricing/trigger", dynamicPricingHandler.TriggerManual)
+			admin.GET("/dynamic-pricing/status", dynamicPricingHandler.GetStatus)
+
+			// Out-of-Order schedule endpoints
+			admin.POST("/out-of-order/trigger", outOfOrderHandler.TriggerManual)
+			admin.GET("/out-of-order/status", outOfOrderHandler.GetStatus)
+
+			// Room pre-assignment endpoints
+			admin.POST("/room-pre-assignment/trigger", roomPreAssignmentHandler.TriggerManual)
+			admin.GET("/room-pre-assignment/status", roomPreAssignmentHandler.GetStatus)
 		}
 	}
 
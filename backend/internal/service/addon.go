@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// isPerNightAddOn reports whether an add-on is served on every night of the stay
+func isPerNightAddOn(pricingUnit string) bool {
+	return pricingUnit == models.AddOnPerNight || pricingUnit == models.AddOnPerGuestNight
+}
+
+// priceAddOn prices an add-on ordered with a booking detail. Per-night add-ons cover
+// every night of the stay; other add-ons are served once on ServiceDate (check-in by default).
+func priceAddOn(addon *models.AddOn, req models.BookingAddOnRequest, checkIn, checkOut time.Time, numGuests int) (*models.BookingAddOn, error) {
+	if !addon.IsActive {
+		return nil, fmt.Errorf("add-on %s is not available", addon.Name)
+	}
+	if req.Quantity < 0 {
+		return nil, errors.New("add-on quantity must not be negative")
+	}
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+		if addon.PricingUnit == models.AddOnPerGuest || addon.PricingUnit == models.AddOnPerGuestNight {
+			quantity = numGuests
+		}
+	}
+
+	line := &models.BookingAddOn{
+		AddOnID:     addon.AddOnID,
+		Name:        addon.Name,
+		Category:    addon.Category,
+		PricingUnit: addon.PricingUnit,
+		Quantity:    quantity,
+		UnitPrice:   addon.Price,
+	}
+
+	if isPerNightAddOn(addon.PricingUnit) {
+		nights := int(checkOut.Sub(checkIn).Hours() / 24)
+		line.ServiceStartDate = checkIn
+		line.ServiceEndDate = checkOut.AddDate(0, 0, -1)
+		line.TotalPrice = roundAmount(addon.Price * float64(quantity*nights))
+		return line, nil
+	}
+
+	serviceDate := checkIn
+	if req.ServiceDate != nil && *req.ServiceDate != "" {
+		date, err := time.Parse("2006-01-02", *req.ServiceDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service date for add-on %s", addon.Name)
+		}
+		if date.Before(checkIn) || date.After(checkOut) {
+			return nil, fmt.Errorf("service date for add-on %s must be within the stay", addon.Name)
+		}
+		serviceDate = date
+	}
+
+	line.ServiceStartDate = serviceDate
+	line.ServiceEndDate = serviceDate
+	line.TotalPrice = roundAmount(addon.Price * float64(quantity))
+	return line, nil
+}
+
+// includedAddOn prices an add-on that comes with the rate plan at no charge
+func includedAddOn(addon *models.AddOn, checkIn, checkOut time.Time, numGuests int) (*models.BookingAddOn, error) {
+	included := *addon
+	included.IsActive = true
+
+	line, err := priceAddOn(&included, models.BookingAddOnRequest{AddOnID: addon.AddOnID}, checkIn, checkOut, numGuests)
+	if err != nil {
+		return nil, err
+	}
+
+	line.UnitPrice = 0
+	line.TotalPrice = 0
+	line.IsIncluded = true
+	return line, nil
+}
+
+// addOnDates lists the service dates of a booked add-on (YYYY-MM-DD)
+func addOnDates(line *models.BookingAddOn) []string {
+	var dates []string
+	for d := line.ServiceStartDate; !d.After(line.ServiceEndDate); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return dates
+}
+
+// buildFolio itemizes a booking into room nights, paid upgrades, early check-in and late
+// check-out fees, add-ons and discounts.
+// The discount line is whatever the voucher took off the booking total.
+func buildFolio(booking *models.BookingWithDetails) *models.BookingFolio {
+	folio := &models.BookingFolio{
+		BookingID: booking.BookingID,
+		Status:    booking.Status,
+		Lines:     []models.FolioLine{},
+		Total:     booking.TotalAmount,
+	}
+
+	var addOnLines []models.FolioLine
+	for _, detail := range booking.Details {
+		for _, addon := range detail.AddOns {
+			date := addon.ServiceStartDate
+			description := addon.Name
+			if addon.IsIncluded {
+				description += " (included)"
+			}
+			addOnLines = append(addOnLines, models.FolioLine{
+				Date:        &date,
+				Category:    models.FolioCategoryAddOn,
+				Description: description,
+				Quantity:    addon.Quantity,
+				UnitPrice:   addon.UnitPrice,
+				Amount:      addon.TotalPrice,
+			})
+			folio.AddOnTotal += addon.TotalPrice
+		}
+	}
+
+	for _, detail := range booking.Details {
+		for _, night := range detail.NightlyPrices {
+			date := night.Date
+			folio.Lines = append(folio.Lines, models.FolioLine{
+				Date:        &date,
+				Category:    models.FolioCategoryRoom,
+				Description: detail.RoomTypeName,
+				Quantity:    1,
+				UnitPrice:   night.QuotedPrice,
+				Amount:      night.QuotedPrice,
+			})
+			folio.RoomTotal += night.QuotedPrice
+		}
+	}
+
+	var upgradeLines []models.FolioLine
+	for _, detail := range booking.Details {
+		for _, upgrade := range detail.Upgrades {
+			if upgrade.ChargePerNight <= 0 {
+				continue
+			}
+			for night := upgrade.StartDate; night.Before(upgrade.EndDate); night = night.AddDate(0, 0, 1) {
+				date := night
+				upgradeLines = append(upgradeLines, models.FolioLine{
+					Date:        &date,
+					Category:    models.FolioCategoryUpgrade,
+					Description: "Upgrade to " + upgrade.ToRoomTypeName,
+					Quantity:    1,
+					UnitPrice:   upgrade.ChargePerNight,
+					Amount:      upgrade.ChargePerNight,
+				})
+				folio.UpgradeTotal += upgrade.ChargePerNight
+			}
+		}
+	}
+
+	var stayTimeLines []models.FolioLine
+	for _, detail := range booking.Details {
+		for _, request := range detail.StayTimeRequests {
+			if request.Status != models.StayTimeStatusApproved || request.Fee <= 0 {
+				continue
+			}
+			date := request.RequestedAt.Truncate(24 * time.Hour)
+			description := "Early check-in from " + request.RequestedAt.Format("15:04")
+			if request.RequestType == models.StayTimeLateCheckOut {
+				description = "Late check-out until " + request.RequestedAt.Format("15:04")
+			}
+			stayTimeLines = append(stayTimeLines, models.FolioLine{
+				Date:        &date,
+				Category:    models.FolioCategoryStayTime,
+				Description: description,
+				Quantity:    1,
+				UnitPrice:   request.Fee,
+				Amount:      request.Fee,
+			})
+			folio.StayTimeTotal += request.Fee
+		}
+	}
+
+	// Nightly logs are only written on confirmation; until then the room charge is
+	// whatever the booking total holds beyond the add-ons
+	if len(folio.Lines) == 0 {
+		folio.RoomTotal = roundAmount(booking.TotalAmount - folio.AddOnTotal - folio.UpgradeTotal - folio.StayTimeTotal)
+		if folio.RoomTotal < 0 {
+			folio.RoomTotal = 0
+		}
+		folio.Lines = append(folio.Lines, models.FolioLine{
+			Category:    models.FolioCategoryRoom,
+			Description: "Room charges",
+			Quantity:    1,
+			UnitPrice:   folio.RoomTotal,
+			Amount:      folio.RoomTotal,
+		})
+	}
+
+	folio.Lines = append(folio.Lines, upgradeLines...)
+	folio.Lines = append(folio.Lines, stayTimeLines...)
+	folio.Lines = append(folio.Lines, addOnLines...)
+
+	folio.RoomTotal = roundAmount(folio.RoomTotal)
+	folio.AddOnTotal = roundAmount(folio.AddOnTotal)
+	folio.UpgradeTotal = roundAmount(folio.UpgradeTotal)
+	folio.StayTimeTotal = roundAmount(folio.StayTimeTotal)
+	if discount := roundAmount(booking.TotalAmount - folio.RoomTotal - folio.AddOnTotal - folio.UpgradeTotal - folio.StayTimeTotal); discount < 0 {
+		folio.DiscountTotal = discount
+		folio.Lines = append(folio.Lines, models.FolioLine{
+			Category:    models.FolioCategoryDiscount,
+			Description: "Discount",
+			Quantity:    1,
+			UnitPrice:   discount,
+			Amount:      discount,
+		})
+	}
+
+	return folio
+}
+
+// roundAmount rounds a currency amount to two decimals
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/internal/repository"
+)
+
+// AddOnService handles the add-on catalog and package inclusions
+type AddOnService struct {
+	addOnRepo   *repository.AddOnRepository
+	pricingRepo *repository.PricingRepository
+}
+
+// NewAddOnService creates a new add-on service
+func NewAddOnService(addOnRepo *repository.AddOnRepository, pricingRepo *repository.PricingRepository) *AddOnService {
+	return &AddOnService{
+		addOnRepo:   addOnRepo,
+		pricingRepo: pricingRepo,
+	}
+}
+
+// GetAllAddOns retrieves the add-on catalog, optionally only active add-ons
+func (s *AddOnService) GetAllAddOns(ctx context.Context, activeOnly bool) ([]models.AddOn, error) {
+	return s.addOnRepo.GetAllAddOns(ctx, activeOnly)
+}
+
+// GetAddOnByID retrieves an add-on by ID
+func (s *AddOnService) GetAddOnByID(ctx context.Context, id int) (*models.AddOn, error) {
+	addon, err := s.addOnRepo.GetAddOnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if addon == nil {
+		return nil, fmt.Errorf("add-on not found")
+	}
+	return addon, nil
+}
+
+// CreateAddOn creates a new add-on
+func (s *AddOnService) CreateAddOn(ctx context.Context, req *models.CreateAddOnRequest) (*models.AddOn, error) {
+	addon := &models.AddOn{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Category:    strings.TrimSpace(req.Category),
+		PricingUnit: req.PricingUnit,
+		Price:       req.Price,
+		DailyLimit:  req.DailyLimit,
+		IsActive:    true,
+	}
+	if addon.Category == "" {
+		addon.Category = "Other"
+	}
+	if addon.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	return s.addOnRepo.CreateAddOn(ctx, addon)
+}
+
+// UpdateAddOn updates the fields given in the request
+func (s *AddOnService) UpdateAddOn(ctx context.Context, id int, req *models.UpdateAddOnRequest) (*models.AddOn, error) {
+	addon, err := s.GetAddOnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, errors.New("name must not be empty")
+		}
+		addon.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		addon.Description = req.Description
+	}
+	if req.Category != nil && strings.TrimSpace(*req.Category) != "" {
+		addon.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.PricingUnit != nil {
+		addon.PricingUnit = *req.PricingUnit
+	}
+	if req.Price != nil {
+		addon.Price = *req.Price
+	}
+	if req.Unlimited {
+		addon.DailyLimit = nil
+	} else if req.DailyLimit != nil {
+		addon.DailyLimit = req.DailyLimit
+	}
+	if req.IsActive != nil {
+		addon.IsActive = *req.IsActive
+	}
+
+	updated, err := s.addOnRepo.UpdateAddOn(ctx, addon)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, fmt.Errorf("add-on not found")
+	}
+	return updated, nil
+}
+
+// DeactivateAddOn stops selling an add-on; booked add-ons keep referencing it
+func (s *AddOnService) DeactivateAddOn(ctx context.Context, id int) error {
+	inactive := false
+	_, err := s.UpdateAddOn(ctx, id, &models.UpdateAddOnRequest{IsActive: &inactive})
+	return err
+}
+
+// GetRatePlanInclusions retrieves the add-ons included in a rate plan
+func (s *AddOnService) GetRatePlanInclusions(ctx context.Context, ratePlanID int) ([]models.AddOn, error) {
+	if err := s.requireRatePlan(ctx, ratePlanID); err != nil {
+		return nil, err
+	}
+	return s.addOnRepo.GetRatePlanInclusions(ctx, ratePlanID)
+}
+
+// SetRatePlanInclusions replaces the add-ons included in a rate plan
+func (s *AddOnService) SetRatePlanInclusions(ctx context.Context, ratePlanID int, addOnIDs []int) ([]models.AddOn, error) {
+	if err := s.requireRatePlan(ctx, ratePlanID); err != nil {
+		return nil, err
+	}
+
+	for _, id := range addOnIDs {
+		addon, err := s.addOnRepo.GetAddOnByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if addon == nil {
+			return nil, fmt.Errorf("add-on %d not found", id)
+		}
+	}
+
+	if err := s.addOnRepo.SetRatePlanInclusions(ctx, ratePlanID, addOnIDs); err != nil {
+		return nil, err
+	}
+
+	return s.addOnRepo.GetRatePlanInclusions(ctx, ratePlanID)
+}
+
+func (s *AddOnService) requireRatePlan(ctx context.Context, ratePlanID int) error {
+	plan, err := s.pricingRepo.GetRatePlanByID(ctx, ratePlanID)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return fmt.Errorf("rate plan not found")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func addOnDate(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestPriceAddOn(t *testing.T) {
+	checkIn, checkOut := addOnDate("2025-07-04"), addOnDate("2025-07-07")
+	breakfast := &models.AddOn{AddOnID: 1, Name: "Breakfast", PricingUnit: models.AddOnPerGuestNight, Price: 350, IsActive: true}
+	transfer := &models.AddOn{AddOnID: 2, Name: "Airport Transfer", PricingUnit: models.AddOnPerUnit, Price: 1200, IsActive: true}
+
+	// Per guest per night: 2 guests x 3 nights, served every night of the stay
+	line, err := priceAddOn(breakfast, models.BookingAddOnRequest{AddOnID: 1}, checkIn, checkOut, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, line.Quantity)
+	assert.Equal(t, 2100.0, line.TotalPrice)
+	assert.Equal(t, checkIn, line.ServiceStartDate)
+	assert.Equal(t, addOnDate("2025-07-06"), line.ServiceEndDate)
+	assert.Equal(t, []string{"2025-07-04", "2025-07-05", "2025-07-06"}, addOnDates(line))
+
+	// Per unit on the departure day
+	departure := "2025-07-07"
+	line, err = priceAddOn(transfer, models.BookingAddOnRequest{AddOnID: 2, Quantity: 2, ServiceDate: &departure}, checkIn, checkOut, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2400.0, line.TotalPrice)
+	assert.Equal(t, []string{"2025-07-07"}, addOnDates(line))
+
+	outside := "2025-07-10"
+	_, err = priceAddOn(transfer, models.BookingAddOnRequest{AddOnID: 2, ServiceDate: &outside}, checkIn, checkOut, 2)
+	assert.EqualError(t, err, "service date for add-on Airport Transfer must be within the stay")
+
+	transfer.IsActive = false
+	_, err = priceAddOn(transfer, models.BookingAddOnRequest{AddOnID: 2}, checkIn, checkOut, 2)
+	assert.EqualError(t, err, "add-on Airport Transfer is not available")
+}
+
+func TestIncludedAddOn(t *testing.T) {
+	breakfast := &models.AddOn{AddOnID: 1, Name: "Breakfast", PricingUnit: models.AddOnPerGuestNight, Price: 350}
+
+	line, err := includedAddOn(breakfast, addOnDate("2025-07-04"), addOnDate("2025-07-06"), 3)
+	assert.NoError(t, err)
+	assert.True(t, line.IsIncluded)
+	assert.Equal(t, 3, line.Quantity)
+	assert.Zero(t, line.UnitPrice)
+	assert.Zero(t, line.TotalPrice)
+}
+
+func TestBuildFolio(t *testing.T) {
+	booking := &models.BookingWithDetails{
+		Booking: models.Booking{BookingID: 42, Status: "Confirmed", TotalAmount: 6300},
+		Details: []models.BookingDetailWithGuests{{
+			RoomTypeName: "Deluxe Room",
+			NightlyPrices: []models.BookingNightlyLog{
+				{Date: addOnDate("2025-07-04"), QuotedPrice: 2500},
+				{Date: addOnDate("2025-07-05"), QuotedPrice: 2500},
+			},
+			AddOns: []models.BookingAddOn{
+				{Name: "Breakfast", Quantity: 2, TotalPrice: 0, IsIncluded: true, ServiceStartDate: addOnDate("2025-07-04")},
+				{Name: "Airport Transfer", Quantity: 1, UnitPrice: 1200, TotalPrice: 1200, ServiceStartDate: addOnDate("2025-07-04")},
+			},
+		}},
+	}
+
+	folio := buildFolio(booking)
+	assert.Equal(t, 5000.0, folio.RoomTotal)
+	assert.Equal(t, 1200.0, folio.AddOnTotal)
+	assert.Zero(t, folio.DiscountTotal)
+	assert.Len(t, folio.Lines, 4)
+	assert.Equal(t, "Breakfast (included)", folio.Lines[2].Description)
+
+	// A voucher took 620 off the total
+	booking.TotalAmount = 5580
+	folio = buildFolio(booking)
+	assert.Equal(t, -620.0, folio.DiscountTotal)
+	assert.Equal(t, models.FolioCategoryDiscount, folio.Lines[len(folio.Lines)-1].Category)
+
+	// Before confirmation there are no nightly logs; the room charge is the remainder
+	booking.Details[0].NightlyPrices = nil
+	folio = buildFolio(booking)
+	assert.Equal(t, 4380.0, folio.RoomTotal)
+	assert.Zero(t, folio.DiscountTotal)
+	assert.Equal(t, "Room charges", folio.Lines[0].Description)
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// maxAlternativeAttempts bounds the windows priced on each side of the searched dates;
+// a window with free rooms may still be closed by stay restrictions or rate plan rules
+const maxAlternativeAttempts = 5
+
+// maxSplitAttempts bounds the nights at which a split stay is priced
+const maxSplitAttempts = 3
+
+// minFreeRooms returns the rooms of one room type free on every night of a window
+func minFreeRooms(nights []int, start, length int) int {
+	if start < 0 || start+length > len(nights) {
+		return 0
+	}
+	free := nights[start]
+	for _, n := range nights[start+1 : start+length] {
+		if n < free {
+			free = n
+		}
+	}
+	return free
+}
+
+// candidateWindows returns the starts of the windows of the given length, on one side of
+// origin (step -1 before, 1 after), with at least rooms rooms free on every night summed
+// over room types, nearest first
+func candidateWindows(availability map[int][]int, origin, length, rooms, step int) []int {
+	var starts []int
+	for start := origin + step; ; start += step {
+		inRange, free := false, 0
+		for _, nights := range availability {
+			if start >= 0 && start+length <= len(nights) {
+				inRange = true
+				free += minFreeRooms(nights, start, length)
+			}
+		}
+		if !inRange {
+			return starts
+		}
+		if free >= rooms {
+			starts = append(starts, start)
+		}
+	}
+}
+
+// splitCandidates returns the nights, counted from origin, at which a stay of the given
+// length can move to another room type: a room type is free before the move and a
+// different one from the move to the end
+func splitCandidates(availability map[int][]int, origin, length int) []int {
+	var splits []int
+	for night := 1; night < length; night++ {
+		for first, nights := range availability {
+			if minFreeRooms(nights, origin, night) == 0 {
+				continue
+			}
+			found := false
+			for second, rest := range availability {
+				if second != first && minFreeRooms(rest, origin+night, length-night) > 0 {
+					found = true
+					break
+				}
+			}
+			if found {
+				splits = append(splits, night)
+				break
+			}
+		}
+	}
+	return splits
+}
+
+// cheapestSplit pairs the cheapest offers of two parts of a stay in different room types.
+// Returns nil when no pair exists.
+func cheapestSplit(first, second []models.RoomType) []models.AlternativeRoom {
+	var best []models.AlternativeRoom
+	bestPrice := 0.0
+	for i := range first {
+		a, offerA := cheapestRoom(first[i : i+1])
+		if offerA == nil {
+			continue
+		}
+		for j := range second {
+			b, offerB := cheapestRoom(second[j : j+1])
+			if offerB == nil || b.RoomTypeID == a.RoomTypeID {
+				continue
+			}
+			if total := offerA.TotalPrice + offerB.TotalPrice; best == nil || total < bestPrice {
+				bestPrice = total
+				best = []models.AlternativeRoom{
+					alternativeRoom(a, offerA),
+					alternativeRoom(b, offerB),
+				}
+			}
+		}
+	}
+	return best
+}
+
+// alternativeRoom returns a room of an alternative stay; dates and guests are set by the caller
+func alternativeRoom(rt *models.RoomType, offer *models.RatePlanOffer) models.AlternativeRoom {
+	return models.AlternativeRoom{
+		RoomTypeID:   rt.RoomTypeID,
+		RoomTypeName: rt.Name,
+		RatePlanID:   offer.RatePlanID,
+		RatePlanName: offer.Name,
+		TotalPrice:   offer.TotalPrice,
+	}
+}
+
+// newAlternativeStay totals the rooms of an alternative stay
+func newAlternativeStay(kind string, checkIn, checkOut time.Time, rooms []models.AlternativeRoom) models.AlternativeStay {
+	stay := models.AlternativeStay{
+		Kind:     kind,
+		CheckIn:  checkIn.Format("2006-01-02"),
+		CheckOut: checkOut.Format("2006-01-02"),
+		Rooms:    rooms,
+	}
+	for _, room := range rooms {
+		stay.TotalPrice += room.TotalPrice
+	}
+	stay.TotalPrice = roundAmount(stay.TotalPrice)
+	return stay
+}
+
+// findAlternatives suggests bookable stays for a search that found nothing: the nearest
+// available dates of the same length before and after (within AlternativeSearchDays and
+// not in the past), and for a single room the searched dates split across two room
+// types. Candidates are taken from room_inventory and then priced like a search, so
+// stay restrictions and rate plan rules apply.
+func (s *RoomService) findAlternatives(ctx context.Context, req *models.SearchRoomsRequest, checkIn, checkOut time.Time, guests, rooms int) ([]models.AlternativeStay, error) {
+	from := checkIn.AddDate(0, 0, -models.AlternativeSearchDays)
+	if today := time.Now().Truncate(24 * time.Hour); from.Before(today) {
+		from = today
+	}
+	to := checkOut.AddDate(0, 0, models.AlternativeSearchDays)
+
+	// Several rooms share the party, so any room type can take part of it
+	perRoom := guests
+	if rooms > 1 {
+		perRoom = 1
+	}
+	availability, err := s.roomRepo.GetDailyAvailability(ctx, from, to, perRoom, roomSearchFilter(req))
+	if err != nil {
+		return nil, err
+	}
+
+	origin := nightsBetween(from, checkIn)
+	nights := nightsBetween(checkIn, checkOut)
+
+	var alternatives []models.AlternativeStay
+	sides := []struct {
+		kind string
+		step int
+	}{
+		{models.AlternativeEarlier, -1},
+		{models.AlternativeLater, 1},
+	}
+	for _, side := range sides {
+		for i, start := range candidateWindows(availability, origin, nights, rooms, side.step) {
+			if i == maxAlternativeAttempts {
+				break
+			}
+			in := from.AddDate(0, 0, start)
+			out := in.AddDate(0, 0, nights)
+			stay, err := s.priceAlternative(ctx, req, in, out, guests, rooms)
+			if err != nil {
+				return nil, err
+			}
+			if stay != nil {
+				stay.Kind = side.kind
+				alternatives = append(alternatives, *stay)
+				break
+			}
+		}
+	}
+
+	if rooms == 1 {
+		var best *models.AlternativeStay
+		for i, night := range splitCandidates(availability, origin, nights) {
+			if i == maxSplitAttempts {
+				break
+			}
+			stay, err := s.priceSplitStay(ctx, req, checkIn, checkIn.AddDate(0, 0, night), checkOut)
+			if err != nil {
+				return nil, err
+			}
+			if stay != nil && (best == nil || stay.TotalPrice < best.TotalPrice) {
+				best = stay
+			}
+		}
+		if best != nil {
+			alternatives = append(alternatives, *best)
+		}
+	}
+
+	return alternatives, nil
+}
+
+// priceAlternative prices the party on other dates: the cheapest room type for a single
+// room, or the cheapest combination for several. Returns nil when nothing can be sold.
+func (s *RoomService) priceAlternative(ctx context.Context, req *models.SearchRoomsRequest, checkIn, checkOut time.Time, guests, rooms int) (*models.AlternativeStay, error) {
+	in, out := checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02")
+
+	if rooms > 1 {
+		combinations, _, err := s.findRoomCombinations(ctx, req, checkIn, checkOut, guests, rooms)
+		if err != nil {
+			return nil, err
+		}
+		if len(combinations) == 0 {
+			return nil, nil
+		}
+		var stayRooms []models.AlternativeRoom
+		for _, room := range combinations[0].Rooms {
+			stayRooms = append(stayRooms, models.AlternativeRoom{
+				RoomTypeID:   room.RoomTypeID,
+				RoomTypeName: room.RoomTypeName,
+				RatePlanID:   room.RatePlanID,
+				RatePlanName: room.RatePlanName,
+				CheckIn:      in,
+				CheckOut:     out,
+				NumGuests:    room.NumGuests,
+				TotalPrice:   room.TotalPrice,
+			})
+		}
+		stay := newAlternativeStay("", checkIn, checkOut, stayRooms)
+		return &stay, nil
+	}
+
+	roomTypes, _, _, err := s.findRoomTypes(ctx, req, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	rt, offer := cheapestRoom(roomTypes)
+	if offer == nil {
+		return nil, nil
+	}
+	room := alternativeRoom(rt, offer)
+	room.CheckIn, room.CheckOut, room.NumGuests = in, out, guests
+	stay := newAlternativeStay("", checkIn, checkOut, []models.AlternativeRoom{room})
+	return &stay, nil
+}
+
+// priceSplitStay prices the searched stay in one room type until the move date and a
+// different one after it. Returns nil when no pair can be sold.
+func (s *RoomService) priceSplitStay(ctx context.Context, req *models.SearchRoomsRequest, checkIn, move, checkOut time.Time) (*models.AlternativeStay, error) {
+	first, _, _, err := s.findRoomTypes(ctx, req, checkIn, move)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price the first part of a split stay: %w", err)
+	}
+	second, _, _, err := s.findRoomTypes(ctx, req, move, checkOut)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price the second part of a split stay: %w", err)
+	}
+
+	rooms := cheapestSplit(first, second)
+	if rooms == nil {
+		return nil, nil
+	}
+	rooms[0].CheckIn, rooms[0].CheckOut = checkIn.Format("2006-01-02"), move.Format("2006-01-02")
+	rooms[1].CheckIn, rooms[1].CheckOut = move.Format("2006-01-02"), checkOut.Format("2006-01-02")
+	rooms[0].NumGuests, rooms[1].NumGuests = req.Guests, req.Guests
+
+	stay := newAlternativeStay(models.AlternativeSplitStay, checkIn, checkOut, rooms)
+	return &stay, nil
+}
+
+// alternativeCheckIns returns the check-in dates of the earlier and later alternatives
+func alternativeCheckIns(alternatives []models.AlternativeStay) []string {
+	var dates []string
+	for _, stay := range alternatives {
+		if stay.Kind != models.AlternativeSplitStay {
+			dates = append(dates, stay.CheckIn)
+		}
+	}
+	return dates
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinFreeRooms(t *testing.T) {
+	nights := []int{3, 1, 4, 0, 2}
+
+	assert.Equal(t, 1, minFreeRooms(nights, 0, 3))
+	assert.Equal(t, 0, minFreeRooms(nights, 2, 2))
+	assert.Equal(t, 2, minFreeRooms(nights, 4, 1))
+	assert.Equal(t, 0, minFreeRooms(nights, 4, 2), "window past the horizon")
+	assert.Equal(t, 0, minFreeRooms(nights, -1, 2), "window before the horizon")
+}
+
+func TestCandidateWindows(t *testing.T) {
+	// Nights 0-9; the searched stay is nights 4-5 and both room types are sold out on 4
+	availability := map[int][]int{
+		1: {1, 1, 0, 1, 0, 0, 1, 1, 1, 1},
+		2: {1, 1, 1, 1, 0, 1, 0, 1, 1, 0},
+	}
+
+	assert.Equal(t, []int{2, 1, 0}, candidateWindows(availability, 4, 2, 1, -1), "3 spans sold-out night 4")
+	assert.Equal(t, []int{6, 7, 8}, candidateWindows(availability, 4, 2, 1, 1), "5 is sold out on night 6 in each type")
+
+	// Two rooms: the free rooms of both types add up
+	assert.Equal(t, []int{0}, candidateWindows(availability, 4, 2, 2, -1))
+	assert.Equal(t, []int{7}, candidateWindows(availability, 4, 2, 2, 1))
+
+	assert.Empty(t, candidateWindows(availability, 0, 2, 1, -1), "nothing before the horizon")
+}
+
+func TestSplitCandidates(t *testing.T) {
+	// Four nights: type 1 is free the first two, type 2 the last three
+	availability := map[int][]int{
+		1: {1, 1, 0, 0},
+		2: {0, 1, 1, 1},
+	}
+	assert.Equal(t, []int{1, 2}, splitCandidates(availability, 0, 4))
+
+	// One room type alone cannot split a stay
+	assert.Empty(t, splitCandidates(map[int][]int{1: {1, 0, 1}}, 0, 3))
+}
+
+func TestCheapestSplit(t *testing.T) {
+	first := []models.RoomType{pricedRoomType(1, 2000), pricedRoomType(2, 2600, 2700)}
+	second := []models.RoomType{pricedRoomType(1, 1000), pricedRoomType(3, 1500)}
+
+	rooms := cheapestSplit(first, second)
+	require.Len(t, rooms, 2)
+	assert.Equal(t, 1, rooms[0].RoomTypeID)
+	assert.Equal(t, 3, rooms[1].RoomTypeID, "the same room type is not a split")
+	assert.Equal(t, 3500.0, rooms[0].TotalPrice+rooms[1].TotalPrice)
+
+	assert.Nil(t, cheapestSplit(first[:1], second[:1]))
+	assert.Nil(t, cheapestSplit(nil, second))
+}
+
+func TestNewAlternativeStay(t *testing.T) {
+	checkIn := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	stay := newAlternativeStay(models.AlternativeLater, checkIn, checkIn.AddDate(0, 0, 2), []models.AlternativeRoom{
+		{RoomTypeID: 1, TotalPrice: 1000.1},
+		{RoomTypeID: 2, TotalPrice: 2000.2},
+	})
+
+	assert.Equal(t, models.AlternativeLater, stay.Kind)
+	assert.Equal(t, "2025-03-10", stay.CheckIn)
+	assert.Equal(t, "2025-03-12", stay.CheckOut)
+	assert.Equal(t, 3000.3, stay.TotalPrice)
+
+	dates := alternativeCheckIns([]models.AlternativeStay{
+		{Kind: models.AlternativeEarlier, CheckIn: "2025-03-08"},
+		stay,
+		{Kind: models.AlternativeSplitStay, CheckIn: "2025-03-09"},
+	})
+	assert.Equal(t, []string{"2025-03-08", "2025-03-10"}, dates)
+}
@@ -120,7 +120,7 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 		}
 	}
 
-	// Generate token with role code (GUEST, RECEPTIONIST, HOUSEKEEPER, MANAGER)
+	// Generate token with role code (GUEST, RECEPTIONIST, HOUSEKEEPER, ENGINEER, MANAGER)
 	token, err := utils.GenerateToken(user.UserID, user.Email, user.RoleCode, s.jwtSecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
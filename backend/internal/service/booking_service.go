@@ -14,6 +14,9 @@ import (
 type BookingService struct {
 	bookingRepo *repository.BookingRepository
 	roomRepo    *repository.RoomRepository
+	listener    InventoryListener
+	roomEvents  RoomChangeListener
+	calendars   AvailabilityCache
 }
 
 // NewBookingService creates a new booking service
@@ -24,6 +27,37 @@ func NewBookingService(bookingRepo *repository.BookingRepository, roomRepo *repo
 	}
 }
 
+// SetInventoryListener registers a listener notified when cancellations free up inventory
+func (s *BookingService) SetInventoryListener(listener InventoryListener) {
+	s.listener = listener
+}
+
+// SetAvailabilityCache registers the cache of availability calendars dropped when rooms
+// are held or booked
+func (s *BookingService) SetAvailabilityCache(calendars AvailabilityCache) {
+	s.calendars = calendars
+}
+
+// availabilityTaken drops cached availability calendars after rooms were held or booked
+func (s *BookingService) availabilityTaken() {
+	if s.calendars != nil {
+		_ = s.calendars.InvalidateAvailabilityCalendarCache()
+	}
+}
+
+// SetRoomChangeListener registers a listener notified when guests arrive, leave or
+// move rooms
+func (s *BookingService) SetRoomChangeListener(listener RoomChangeListener) {
+	s.roomEvents = listener
+}
+
+// roomsChanged tells the room change listener about rooms guests arrived in or left
+func (s *BookingService) roomsChanged(ctx context.Context, eventType string, roomIDs ...int) {
+	if s.roomEvents != nil {
+		s.roomEvents.RoomsChanged(ctx, eventType, roomIDs...)
+	}
+}
+
 // CreateBookingHold creates a temporary hold on inventory
 func (s *BookingService) CreateBookingHold(ctx context.Context, req *models.CreateBookingHoldRequest) (*models.CreateBookingHoldResponse, error) {
 	// Validate dates
@@ -45,8 +79,161 @@ func (s *BookingService) CreateBookingHold(ctx context.Context, req *models.Crea
 		return nil, errors.New("check-in date cannot be in the past")
 	}
 
+	// Reject stays closed by restrictions before touching inventory
+	violations, err := s.checkStayRestrictions(ctx, req.RoomTypeID, req.RatePlanID, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		restrictionErr := &models.RestrictionError{RoomTypeID: req.RoomTypeID, Violations: violations}
+		return &models.CreateBookingHoldResponse{
+			Success:      false,
+			Message:      restrictionErr.Error(),
+			Restrictions: violations,
+		}, nil
+	}
+
 	// Call repository to create hold
-	return s.bookingRepo.CreateBookingHold(ctx, req)
+	response, err := s.bookingRepo.CreateBookingHold(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if response.Success {
+		s.availabilityTaken()
+	}
+	return response, nil
+}
+
+// checkStayRestrictions returns the restrictions a stay violates for a room type and rate plan
+func (s *BookingService) checkStayRestrictions(ctx context.Context, roomTypeID int, ratePlanID *int, checkIn, checkOut time.Time) ([]models.RestrictionViolation, error) {
+	restrictions, err := s.roomRepo.GetStayRestrictions(ctx, []int{roomTypeID}, checkIn, checkOut)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stay restrictions: %w", err)
+	}
+
+	return evaluateRestrictions(restrictions, ratePlanID, checkIn, checkOut), nil
+}
+
+// resolveDetailOccupancy works out the party of a booking detail and checks the
+// room type has the extra beds it needs for every night
+func (s *BookingService) resolveDetailOccupancy(ctx context.Context, detail models.CreateBookingDetailRequest, checkIn, checkOut time.Time) (models.Occupancy, error) {
+	childAges := detail.ChildAges
+	if len(childAges) == 0 {
+		var err error
+		childAges, err = childAgesFromGuests(detail.Guests)
+		if err != nil {
+			return models.Occupancy{}, err
+		}
+	}
+
+	roomType, err := s.roomRepo.GetRoomTypeByID(ctx, detail.RoomTypeID)
+	if err != nil {
+		return models.Occupancy{}, fmt.Errorf("failed to get room type: %w", err)
+	}
+	if roomType == nil {
+		return models.Occupancy{}, errors.New("room type not found")
+	}
+
+	occupancy, err := resolveOccupancy(roomType, detail.NumGuests, childAges)
+	if err != nil {
+		return models.Occupancy{}, err
+	}
+
+	if occupancy.ExtraBeds > 0 {
+		available, err := s.roomRepo.GetExtraBedsAvailable(ctx, detail.RoomTypeID, checkIn, checkOut)
+		if err != nil {
+			return models.Occupancy{}, err
+		}
+		if available < occupancy.ExtraBeds {
+			return models.Occupancy{}, fmt.Errorf("no extra bed available for %s on the selected dates", roomType.Name)
+		}
+	}
+
+	return occupancy, nil
+}
+
+// priceDetailAddOns prices the add-ons of a booking detail, starting with those included in its rate plan
+func (s *BookingService) priceDetailAddOns(ctx context.Context, detail models.CreateBookingDetailRequest, checkIn, checkOut time.Time) ([]*models.BookingAddOn, error) {
+	inclusions, err := s.bookingRepo.GetRatePlanInclusions(ctx, detail.RatePlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []*models.BookingAddOn
+	for i := range inclusions {
+		line, err := includedAddOn(&inclusions[i], checkIn, checkOut, detail.NumGuests)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	for _, req := range detail.AddOns {
+		addon, err := s.bookingRepo.GetAddOn(ctx, req.AddOnID)
+		if err != nil {
+			return nil, err
+		}
+		if addon == nil {
+			return nil, fmt.Errorf("add-on %d not found", req.AddOnID)
+		}
+
+		line, err := priceAddOn(addon, req, checkIn, checkOut, detail.NumGuests)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// checkAddOnLimits makes sure the add-ons of all details fit the daily limits on every service date.
+// It turns a booking away before anything is created; the booking transaction enforces the limits.
+func (s *BookingService) checkAddOnLimits(ctx context.Context, detailAddOns [][]*models.BookingAddOn) error {
+	requested := make(map[int]map[string]int)
+	for _, lines := range detailAddOns {
+		for _, line := range lines {
+			if requested[line.AddOnID] == nil {
+				requested[line.AddOnID] = make(map[string]int)
+			}
+			for _, date := range addOnDates(line) {
+				requested[line.AddOnID][date] += line.Quantity
+			}
+		}
+	}
+
+	for _, lines := range detailAddOns {
+		for _, line := range lines {
+			dates, ok := requested[line.AddOnID]
+			if !ok {
+				continue
+			}
+			delete(requested, line.AddOnID)
+
+			start, end := line.ServiceStartDate, line.ServiceEndDate
+			for date := range dates {
+				d, _ := time.Parse("2006-01-02", date)
+				if d.Before(start) {
+					start = d
+				}
+				if d.After(end) {
+					end = d
+				}
+			}
+
+			remaining, err := s.bookingRepo.GetAddOnRemaining(ctx, line.AddOnID, start, end)
+			if err != nil {
+				return err
+			}
+			for date, quantity := range dates {
+				if units, limited := remaining[date]; limited && quantity > units {
+					return fmt.Errorf("add-on %s is sold out on %s", line.Name, date)
+				}
+			}
+		}
+	}
+
+	return nil
 }
 
 // CreateBooking creates a new booking with all details
@@ -74,6 +261,8 @@ func (s *BookingService) CreateBooking(ctx context.Context, guestID int, req *mo
 	// Calculate total amount and get policy
 	var totalAmount float64
 	var policyName, policyDescription string
+	occupancies := make([]models.Occupancy, len(req.Details))
+	detailAddOns := make([][]*models.BookingAddOn, len(req.Details))
 
 	for i, detail := range req.Details {
 		// Validate dates
@@ -91,16 +280,44 @@ func (s *BookingService) CreateBooking(ctx context.Context, guestID int, req *mo
 			return nil, fmt.Errorf("check-out must be after check-in for detail %d", i+1)
 		}
 
-		// Get rate plan and policy (use first detail's policy for the booking)
-		if i == 0 {
-			ratePlan, err := s.bookingRepo.GetRatePlan(ctx, detail.RatePlanID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get rate plan: %w", err)
-			}
-			if ratePlan == nil {
-				return nil, errors.New("invalid rate plan")
+		ratePlanID := detail.RatePlanID
+		violations, err := s.checkStayRestrictions(ctx, detail.RoomTypeID, &ratePlanID, checkIn, checkOut)
+		if err != nil {
+			return nil, err
+		}
+		if len(violations) > 0 {
+			return nil, &models.RestrictionError{RoomTypeID: detail.RoomTypeID, Violations: violations}
+		}
+
+		// Get rate plan and check the stay qualifies for it
+		ratePlan, err := s.bookingRepo.GetRatePlan(ctx, detail.RatePlanID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rate plan: %w", err)
+		}
+		if ratePlan == nil {
+			return nil, errors.New("invalid rate plan")
+		}
+		if ratePlan.IsDayUse {
+			return nil, fmt.Errorf("rate plan %s is only sold as day-use", ratePlan.Name)
+		}
+
+		reasons := checkRatePlanEligibility(ratePlan, models.RatePlanEligibility{
+			BookingDate: time.Now(),
+			CheckIn:     checkIn,
+			CheckOut:    checkOut,
+			NumGuests:   detail.NumGuests,
+			IsMember:    guestID > 0,
+		})
+		if len(reasons) > 0 {
+			return nil, &models.RatePlanIneligibleError{
+				RatePlanID:   ratePlan.RatePlanID,
+				RatePlanName: ratePlan.Name,
+				Reasons:      reasons,
 			}
+		}
 
+		// Use first detail's policy for the booking
+		if i == 0 {
 			policy, err := s.bookingRepo.GetCancellationPolicy(ctx, ratePlan.PolicyID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get cancellation policy: %w", err)
@@ -119,112 +336,124 @@ func (s *BookingService) CreateBooking(ctx context.Context, guestID int, req *mo
 			return nil, fmt.Errorf("failed to get pricing for detail %d: %w", i+1, err)
 		}
 
+		// Stays long enough for a long-stay rate are charged it on every night
+		longStayRates, err := s.roomRepo.GetLongStayRates(ctx, []int{detail.RatePlanID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get long-stay rates for detail %d: %w", i+1, err)
+		}
+		longStay := selectLongStayRate(longStayRates, detail.RatePlanID, detail.RoomTypeID, nightsBetween(checkIn, checkOut))
+
 		var detailTotal float64
 		for _, price := range pricing {
-			detailTotal += price.Price
+			detailTotal += longStayRoomRate(longStay, price.Price)
+		}
+
+		// Price the party: extra adults, children by age and extra beds
+		occupancy, err := s.resolveDetailOccupancy(ctx, detail, checkIn, checkOut)
+		if err != nil {
+			return nil, fmt.Errorf("detail %d: %w", i+1, err)
 		}
+		surcharges, err := s.roomRepo.GetOccupancySurcharges(ctx, detail.RoomTypeID, checkIn, checkOut, occupancy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get occupancy surcharges for detail %d: %w", i+1, err)
+		}
+		for _, amount := range surcharges {
+			detailTotal += amount
+		}
+		occupancies[i] = occupancy
+
+		// Price the extras: the plan's inclusions at no charge, then the ordered add-ons
+		addons, err := s.priceDetailAddOns(ctx, detail, checkIn, checkOut)
+		if err != nil {
+			return nil, fmt.Errorf("detail %d: %w", i+1, err)
+		}
+		for _, addon := range addons {
+			detailTotal += addon.TotalPrice
+		}
+		detailAddOns[i] = addons
+
 		totalAmount += detailTotal
 	}
 
+	if err := s.checkAddOnLimits(ctx, detailAddOns); err != nil {
+		return nil, err
+	}
+
 	// Apply voucher discount
 	if voucherID != nil {
 		voucher, _ := s.bookingRepo.GetVoucherByCode(ctx, *req.VoucherCode)
-		if voucher.DiscountType == "Percentage" {
-			discountAmount = totalAmount * (voucher.DiscountValue / 100)
-		} else {
-			discountAmount = voucher.DiscountValue
-		}
+		discountAmount = voucherDiscount(voucher, totalAmount)
 		totalAmount -= discountAmount
 		if totalAmount < 0 {
 			totalAmount = 0
 		}
 	}
 
-	// Create booking
-	booking, err := s.bookingRepo.CreateBooking(ctx, guestID, voucherID, totalAmount, policyName, policyDescription)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create booking: %w", err)
-	}
-
 	// Get guest account info if authenticated (for filling in missing phone/email)
 	var guestAccount *models.Guest
 	if guestID > 0 {
 		guestAccount, _ = s.bookingRepo.GetGuestByID(ctx, guestID)
 	}
 
-	// Create booking details
-	for _, detail := range req.Details {
+	booking := &models.Booking{
+		VoucherID:         voucherID,
+		TotalAmount:       totalAmount,
+		PolicyName:        policyName,
+		PolicyDescription: policyDescription,
+	}
+	if guestID > 0 {
+		booking.GuestID = &guestID
+	}
+
+	// Build booking details and their guests
+	var primaryEmail string
+	details := make([]*models.BookingDetail, len(req.Details))
+	detailGuests := make([][]*models.BookingGuest, len(req.Details))
+	for i, detail := range req.Details {
 		checkIn, _ := time.Parse("2006-01-02", detail.CheckIn)
 		checkOut, _ := time.Parse("2006-01-02", detail.CheckOut)
 
-		bookingDetail := &models.BookingDetail{
-			BookingID:    booking.BookingID,
+		details[i] = &models.BookingDetail{
 			RoomTypeID:   detail.RoomTypeID,
 			RatePlanID:   detail.RatePlanID,
 			CheckInDate:  checkIn,
 			CheckOutDate: checkOut,
 			NumGuests:    detail.NumGuests,
+			NumAdults:    &occupancies[i].Adults,
+			ChildAges:    occupancies[i].ChildAges,
+			ExtraBeds:    occupancies[i].ExtraBeds,
+			StayType:     models.StayTypeOvernight,
 		}
 
-		err = s.bookingRepo.CreateBookingDetail(ctx, bookingDetail)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create booking detail: %w", err)
-		}
-
-		// Create guests
 		for _, guest := range detail.Guests {
-			// For primary guest with guest account: ALWAYS use account data
-			phone := guest.Phone
-			email := guest.Email
-			firstName := guest.FirstName
-			lastName := guest.LastName
-			
-			if guest.IsPrimary && guestAccount != nil {
-				// For signed-in users: ALWAYS use account data (override any form data)
-				phone = &guestAccount.Phone
-				email = &guestAccount.Email
-				firstName = guestAccount.FirstName
-				lastName = guestAccount.LastName
-				
-				fmt.Printf("[CreateBooking] Using guest account data for primary guest: %s %s, email: %s, phone: %s\n", 
-					firstName, lastName, *email, *phone)
-			} else if guest.IsPrimary && guestAccount == nil {
-				// For non-signed-in users: use form data (must be provided)
-				if phone == nil || *phone == "" {
-					return nil, errors.New("phone number is required for primary guest")
-				}
-				if email == nil || *email == "" {
-					return nil, errors.New("email is required for primary guest")
-				}
-				
-				fmt.Printf("[CreateBooking] Using form data for non-signed-in primary guest: %s %s, email: %s, phone: %s\n", 
-					firstName, lastName, *email, *phone)
-			}
-			
-			bookingGuest := &models.BookingGuest{
-				BookingDetailID: bookingDetail.BookingDetailID,
-				FirstName:       firstName,
-				LastName:        lastName,
-				Phone:           phone,
-				Email:           email,
-				Type:            guest.Type,
-				IsPrimary:       guest.IsPrimary,
-			}
-
-			err = s.bookingRepo.CreateBookingGuest(ctx, bookingGuest)
+			bookingGuest, err := buildBookingGuest(guest, guestAccount)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create booking guest: %w", err)
+				return nil, err
+			}
+			if bookingGuest.IsPrimary && bookingGuest.Email != nil && primaryEmail == "" {
+				primaryEmail = *bookingGuest.Email
 			}
+			detailGuests[i] = append(detailGuests[i], bookingGuest)
 		}
+	}
+
+	// Create the booking; the daily add-on limits are enforced again in its transaction.
+	// NOTE: Nightly logs will be created by confirm_booking() function
+	// when the booking is confirmed, not here during creation
+	if err := s.bookingRepo.CreateBookingWithDetails(ctx, booking, details, detailAddOns, detailGuests); err != nil {
+		return nil, err
+	}
 
-		// NOTE: Nightly logs will be created by confirm_booking() function
-		// when the booking is confirmed, not here during creation
+	// Mark a waitlist offer as claimed when the booking uses its hold session and is made
+	// by the waitlisted guest
+	if err := s.bookingRepo.ClaimWaitlistOffer(ctx, req.SessionID, booking.BookingID, guestID, primaryEmail); err != nil {
+		// Log error but don't fail the booking
+		fmt.Printf("Warning: failed to claim waitlist offer: %v\n", err)
 	}
 
 	// Increment voucher usage if used
 	if voucherID != nil {
-		err = s.bookingRepo.IncrementVoucherUsage(ctx, *voucherID)
-		if err != nil {
+		if err := s.bookingRepo.IncrementVoucherUsage(ctx, *voucherID); err != nil {
 			// Log error but don't fail the booking
 			fmt.Printf("Warning: failed to increment voucher usage: %v\n", err)
 		}
@@ -238,6 +467,195 @@ func (s *BookingService) CreateBooking(ctx context.Context, guestID int, req *mo
 	}, nil
 }
 
+// CreateDayUseBooking books a day-use slot. The slot does not consume nightly inventory;
+// a physical room is blocked for it instead.
+func (s *BookingService) CreateDayUseBooking(ctx context.Context, guestID int, req *models.CreateDayUseBookingRequest) (*models.CreateDayUseBookingResponse, error) {
+	start, end, err := parseDayUseSlot(req.Date, req.StartTime, req.DurationHours)
+	if err != nil {
+		return nil, err
+	}
+	if !start.After(hotelNow()) {
+		return nil, errors.New("day-use slot must start in the future")
+	}
+
+	roomType, err := s.roomRepo.GetRoomTypeByID(ctx, req.RoomTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room type: %w", err)
+	}
+	if roomType == nil {
+		return nil, errors.New("invalid room type")
+	}
+
+	childAges, err := childAgesFromGuests(req.Guests)
+	if err != nil {
+		return nil, err
+	}
+	occupancy, err := resolveOccupancy(roomType, req.NumGuests, childAges)
+	if err != nil {
+		return nil, err
+	}
+	if occupancy.ExtraBeds > 0 {
+		return nil, fmt.Errorf("%s allows at most %d guests for day use", roomType.Name, roomType.MaxOccupancy)
+	}
+
+	// Find the rate for the slot length
+	rates, err := s.roomRepo.GetDayUseRates(ctx, &req.RoomTypeID, req.DurationHours)
+	if err != nil {
+		return nil, err
+	}
+	var rate *models.DayUseRate
+	for i := range rates {
+		if req.RatePlanID == 0 || rates[i].RatePlanID == req.RatePlanID {
+			rate = &rates[i]
+			break
+		}
+	}
+	if rate == nil {
+		return nil, fmt.Errorf("no %d-hour day-use rate for %s", req.DurationHours, roomType.Name)
+	}
+
+	ratePlan, err := s.bookingRepo.GetRatePlan(ctx, rate.RatePlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate plan: %w", err)
+	}
+	if ratePlan == nil {
+		return nil, errors.New("invalid rate plan")
+	}
+	policy, err := s.bookingRepo.GetCancellationPolicy(ctx, ratePlan.PolicyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cancellation policy: %w", err)
+	}
+	if policy == nil {
+		return nil, errors.New("cancellation policy not found")
+	}
+
+	// Apply voucher discount
+	totalAmount := rate.Price
+	var voucherID *int
+	if req.VoucherCode != nil && *req.VoucherCode != "" {
+		voucher, err := s.bookingRepo.GetVoucherByCode(ctx, *req.VoucherCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get voucher: %w", err)
+		}
+		if voucher == nil {
+			return nil, errors.New("invalid or expired voucher code")
+		}
+		voucherID = &voucher.VoucherID
+		totalAmount -= voucherDiscount(voucher, totalAmount)
+		if totalAmount < 0 {
+			totalAmount = 0
+		}
+	}
+
+	var guestAccount *models.Guest
+	if guestID > 0 {
+		guestAccount, _ = s.bookingRepo.GetGuestByID(ctx, guestID)
+	}
+
+	guests := make([]models.BookingGuest, 0, len(req.Guests))
+	for _, guest := range req.Guests {
+		bookingGuest, err := buildBookingGuest(guest, guestAccount)
+		if err != nil {
+			return nil, err
+		}
+		guests = append(guests, *bookingGuest)
+	}
+
+	booking := &models.Booking{
+		VoucherID:         voucherID,
+		TotalAmount:       totalAmount,
+		PolicyName:        policy.Name,
+		PolicyDescription: policy.Description,
+	}
+	if guestID > 0 {
+		booking.GuestID = &guestID
+	}
+
+	day := start.Truncate(24 * time.Hour)
+	detail := &models.BookingDetail{
+		RoomTypeID:   req.RoomTypeID,
+		RatePlanID:   rate.RatePlanID,
+		CheckInDate:  day,
+		CheckOutDate: day,
+		NumGuests:    req.NumGuests,
+		NumAdults:    &occupancy.Adults,
+		ChildAges:    occupancy.ChildAges,
+		StayType:     models.StayTypeDayUse,
+	}
+
+	roomNumber, err := s.bookingRepo.CreateDayUseBooking(ctx, booking, detail, guests, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if voucherID != nil {
+		if err := s.bookingRepo.IncrementVoucherUsage(ctx, *voucherID); err != nil {
+			// Log error but don't fail the booking
+			fmt.Printf("Warning: failed to increment voucher usage: %v\n", err)
+		}
+	}
+
+	return &models.CreateDayUseBookingResponse{
+		CreateBookingResponse: models.CreateBookingResponse{
+			BookingID:   booking.BookingID,
+			TotalAmount: totalAmount,
+			Status:      booking.Status,
+			Message:     "Day-use booking created successfully",
+		},
+		RoomNumber: roomNumber,
+		StartAt:    start,
+		EndAt:      end,
+	}, nil
+}
+
+// buildBookingGuest fills a booking guest from the request. A signed-in guest's account
+// details always replace the form data of the primary guest.
+func buildBookingGuest(guest models.CreateGuestRequest, guestAccount *models.Guest) (*models.BookingGuest, error) {
+	phone := guest.Phone
+	email := guest.Email
+	firstName := guest.FirstName
+	lastName := guest.LastName
+
+	if guest.IsPrimary && guestAccount != nil {
+		// For signed-in users: ALWAYS use account data (override any form data)
+		phone = &guestAccount.Phone
+		email = &guestAccount.Email
+		firstName = guestAccount.FirstName
+		lastName = guestAccount.LastName
+
+		fmt.Printf("[CreateBooking] Using guest account data for primary guest: %s %s, email: %s, phone: %s\n",
+			firstName, lastName, *email, *phone)
+	} else if guest.IsPrimary && guestAccount == nil {
+		// For non-signed-in users: use form data (must be provided)
+		if phone == nil || *phone == "" {
+			return nil, errors.New("phone number is required for primary guest")
+		}
+		if email == nil || *email == "" {
+			return nil, errors.New("email is required for primary guest")
+		}
+
+		fmt.Printf("[CreateBooking] Using form data for non-signed-in primary guest: %s %s, email: %s, phone: %s\n",
+			firstName, lastName, *email, *phone)
+	}
+
+	return &models.BookingGuest{
+		FirstName: firstName,
+		LastName:  lastName,
+		Phone:     phone,
+		Email:     email,
+		Type:      guest.Type,
+		IsPrimary: guest.IsPrimary,
+	}, nil
+}
+
+// voucherDiscount returns the discount a voucher gives on an amount
+func voucherDiscount(voucher *models.Voucher, amount float64) float64 {
+	if voucher.DiscountType == "Percentage" {
+		return amount * (voucher.DiscountValue / 100)
+	}
+	return voucher.DiscountValue
+}
+
 // ConfirmBooking confirms a pending booking
 func (s *BookingService) ConfirmBooking(ctx context.Context, bookingID int) (*models.ConfirmBookingResponse, error) {
 	// Verify booking exists and is in correct status
@@ -260,7 +678,14 @@ func (s *BookingService) ConfirmBooking(ctx context.Context, bookingID int) (*mo
 	}
 
 	// Call repository to confirm booking
-	return s.bookingRepo.ConfirmBooking(ctx, bookingID)
+	response, err := s.bookingRepo.ConfirmBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if response.Success {
+		s.availabilityTaken()
+	}
+	return response, nil
 }
 
 // CancelBooking cancels a booking
@@ -294,7 +719,16 @@ func (s *BookingService) CancelBooking(ctx context.Context, bookingID int, guest
 	}
 
 	// Call repository to cancel booking
-	return s.bookingRepo.CancelBooking(ctx, bookingID)
+	response, err := s.bookingRepo.CancelBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Success && s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+
+	return response, nil
 }
 
 // GetBookingByID retrieves a booking by ID
@@ -315,6 +749,47 @@ func (s *BookingService) GetBookingByID(ctx context.Context, bookingID int, gues
 	return booking, nil
 }
 
+// GetBookingFolio itemizes the charges of a booking by revenue category.
+// A guestID of 0 skips the ownership check (staff access).
+func (s *BookingService) GetBookingFolio(ctx context.Context, bookingID int, guestID int) (*models.BookingFolio, error) {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, nil
+	}
+
+	if guestID != 0 && booking.GuestID != nil && *booking.GuestID != guestID {
+		return nil, errors.New("unauthorized to view this booking")
+	}
+
+	return buildFolio(booking), nil
+}
+
+// GetBillingSchedule splits the charges of a booking into invoices by the billing cycle
+// of its first room's rate plan. A guestID of 0 skips the ownership check (staff access).
+func (s *BookingService) GetBillingSchedule(ctx context.Context, bookingID int, guestID int) (*models.BillingSchedule, error) {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, nil
+	}
+
+	if guestID != 0 && booking.GuestID != nil && *booking.GuestID != guestID {
+		return nil, errors.New("unauthorized to view this booking")
+	}
+
+	cycle := models.BillingCyclePerStay
+	if len(booking.Details) > 0 {
+		cycle = booking.Details[0].BillingCycle
+	}
+
+	return buildBillingSchedule(booking, buildFolio(booking), cycle), nil
+}
+
 // GetBookingsByGuestID retrieves all bookings for a guest
 func (s *BookingService) GetBookingsByGuestID(ctx context.Context, guestID int, status string, limit, offset int) (*models.GetBookingsResponse, error) {
 	// Set defaults
@@ -339,15 +814,34 @@ func (s *BookingService) GetBookingsByGuestID(ctx context.Context, guestID int,
 }
 
 // CheckIn performs check-in for a guest
-func (s *BookingService) CheckIn(ctx context.Context, bookingDetailID, roomID int) (*models.CheckInResponse, error) {
+func (s *BookingService) CheckIn(ctx context.Context, bookingDetailID, roomID int, staffID *int) (*models.CheckInResponse, error) {
 	// Validate that the booking detail exists and is in correct status
 	// This is handled by the PostgreSQL function, but we can add additional validation here if needed
 
-	return s.bookingRepo.CheckIn(ctx, bookingDetailID, roomID)
+	// Without a room, the guest is checked in to the room picked ahead of arrival
+	if roomID == 0 {
+		preAssigned, err := s.bookingRepo.GetPreAssignedRoomID(ctx, bookingDetailID)
+		if err != nil {
+			return nil, err
+		}
+		if preAssigned == 0 {
+			return &models.CheckInResponse{
+				Success: false,
+				Message: "No room is pre-assigned to this booking; choose a room",
+			}, nil
+		}
+		roomID = preAssigned
+	}
+
+	response, err := s.bookingRepo.CheckIn(ctx, bookingDetailID, roomID, staffID)
+	if err == nil && response.Success {
+		s.roomsChanged(ctx, models.RoomEventArrival, roomID)
+	}
+	return response, err
 }
 
 // CheckOut performs check-out for a guest
-func (s *BookingService) CheckOut(ctx context.Context, bookingID int) (*models.CheckOutResponse, error) {
+func (s *BookingService) CheckOut(ctx context.Context, bookingID int, staffID *int) (*models.CheckOutResponse, error) {
 	// Validate that the booking exists and is in correct status
 	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
 	if err != nil {
@@ -367,13 +861,32 @@ func (s *BookingService) CheckOut(ctx context.Context, bookingID int) (*models.C
 		}, nil
 	}
 
-	return s.bookingRepo.CheckOut(ctx, bookingID)
+	// The rooms are released by the check-out, so they are looked up first
+	roomIDs, err := s.bookingRepo.GetAssignedRoomIDs(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.bookingRepo.CheckOut(ctx, bookingID, staffID)
+	if err == nil && response.Success {
+		s.roomsChanged(ctx, models.RoomEventDeparture, roomIDs...)
+	}
+	return response, err
 }
 
 // MoveRoom moves a guest to another room
-func (s *BookingService) MoveRoom(ctx context.Context, assignmentID, newRoomID int) (*models.MoveRoomResponse, error) {
+func (s *BookingService) MoveRoom(ctx context.Context, assignmentID, newRoomID int, staffID *int) (*models.MoveRoomResponse, error) {
 	// The PostgreSQL function handles all validation
-	return s.bookingRepo.MoveRoom(ctx, assignmentID, newRoomID)
+	oldRoomID, err := s.bookingRepo.GetAssignmentRoomID(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.bookingRepo.MoveRoom(ctx, assignmentID, newRoomID, staffID)
+	if err == nil && response.Success {
+		s.roomsChanged(ctx, models.RoomEventStatus, oldRoomID, newRoomID)
+	}
+	return response, err
 }
 
 // MarkNoShow marks a booking as no-show
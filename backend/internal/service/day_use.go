@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// parseDayUseSlot works out the start and end of a day-use slot and checks that it
+// fits the day-use window. Times are hotel wall-clock times.
+func parseDayUseSlot(date, startTime string, durationHours int) (time.Time, time.Time, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid day-use date")
+	}
+
+	clock, err := time.Parse("15:04", startTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("start time must use HH:MM")
+	}
+
+	if durationHours <= 0 {
+		return time.Time{}, time.Time{}, errors.New("duration must be at least one hour")
+	}
+
+	start := day.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+	end := start.Add(time.Duration(durationHours) * time.Hour)
+
+	open := day.Add(models.DayUseOpenHour * time.Hour)
+	closing := day.Add(models.DayUseCloseHour * time.Hour)
+	if start.Before(open) || end.After(closing) {
+		return time.Time{}, time.Time{}, fmt.Errorf("day-use slots must be between %02d:00 and %02d:00",
+			models.DayUseOpenHour, models.DayUseCloseHour)
+	}
+
+	return start, end, nil
+}
+
+// dayUseSlotStarts lists the hourly slot starts of a date that still lie ahead of now
+func dayUseSlotStarts(day time.Time, durationHours int, now time.Time) []time.Time {
+	var starts []time.Time
+	for hour := models.DayUseOpenHour; hour+durationHours <= models.DayUseCloseHour; hour++ {
+		start := day.Add(time.Duration(hour) * time.Hour)
+		if start.After(now) {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+// hotelNow returns the current hotel wall-clock time in the same form as parsed dates
+func hotelNow() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+}
+
+// buildDayUseSlots pairs slot starts with the rooms available for each
+func buildDayUseSlots(rate models.DayUseRate, starts []time.Time, available []int) []models.DayUseSlot {
+	slots := make([]models.DayUseSlot, 0, len(starts))
+	for i, start := range starts {
+		rooms := 0
+		if i < len(available) {
+			rooms = available[i]
+		}
+		slots = append(slots, models.DayUseSlot{
+			StartTime:      start.Format("15:04"),
+			EndTime:        start.Add(time.Duration(rate.DurationHours) * time.Hour).Format("15:04"),
+			DurationHours:  rate.DurationHours,
+			Price:          rate.Price,
+			AvailableRooms: rooms,
+		})
+	}
+	return slots
+}
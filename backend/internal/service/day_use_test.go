@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDayUseSlot(t *testing.T) {
+	t.Run("slot within the window", func(t *testing.T) {
+		start, end, err := parseDayUseSlot("2025-03-10", "09:30", 3)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC), end)
+	})
+
+	t.Run("slot ending at closing time", func(t *testing.T) {
+		_, end, err := parseDayUseSlot("2025-03-10", "14:00", 6)
+		require.NoError(t, err)
+		assert.Equal(t, 20, end.Hour())
+	})
+
+	t.Run("slot starting before opening", func(t *testing.T) {
+		_, _, err := parseDayUseSlot("2025-03-10", "07:00", 3)
+		assert.Error(t, err)
+	})
+
+	t.Run("slot running past closing", func(t *testing.T) {
+		_, _, err := parseDayUseSlot("2025-03-10", "18:00", 3)
+		assert.Error(t, err)
+	})
+
+	t.Run("bad start time", func(t *testing.T) {
+		_, _, err := parseDayUseSlot("2025-03-10", "9am", 3)
+		assert.Error(t, err)
+	})
+
+	t.Run("bad date", func(t *testing.T) {
+		_, _, err := parseDayUseSlot("10/03/2025", "09:00", 3)
+		assert.Error(t, err)
+	})
+}
+
+func TestDayUseSlotStarts(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("future date lists every start", func(t *testing.T) {
+		starts := dayUseSlotStarts(day, 3, day.AddDate(0, 0, -1))
+		require.Len(t, starts, 10)
+		assert.Equal(t, 8, starts[0].Hour())
+		assert.Equal(t, 17, starts[len(starts)-1].Hour())
+	})
+
+	t.Run("starts already passed are skipped", func(t *testing.T) {
+		now := day.Add(12*time.Hour + 15*time.Minute)
+		starts := dayUseSlotStarts(day, 3, now)
+		require.Len(t, starts, 5)
+		assert.Equal(t, 13, starts[0].Hour())
+	})
+
+	t.Run("duration longer than the window", func(t *testing.T) {
+		starts := dayUseSlotStarts(day, 13, day.AddDate(0, 0, -1))
+		assert.Empty(t, starts)
+	})
+}
+
+func TestBuildDayUseSlots(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	rate := models.DayUseRate{DurationHours: 3, Price: 800}
+	starts := []time.Time{day.Add(8 * time.Hour), day.Add(9 * time.Hour)}
+
+	slots := buildDayUseSlots(rate, starts, []int{2})
+	require.Len(t, slots, 2)
+	assert.Equal(t, "08:00", slots[0].StartTime)
+	assert.Equal(t, "11:00", slots[0].EndTime)
+	assert.Equal(t, 800.0, slots[0].Price)
+	assert.Equal(t, 2, slots[0].AvailableRooms)
+	assert.Equal(t, 0, slots[1].AvailableRooms)
+}
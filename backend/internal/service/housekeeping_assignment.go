@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// floorChangePenalty is the extra load, in credits, of sending an attendant to a
+// floor they have no rooms on yet
+const floorChangePenalty = 0.5
+
+// taskPriority ranks a housekeeping task, most urgent first: vacant rooms of a type
+// guests arrive in today, other checkouts, stayover services, rooms being cleaned,
+// clean rooms waiting for inspection, then rooms the guest asked not to be disturbed
+func taskPriority(task models.HousekeepingTask) int {
+	switch task.HousekeepingStatus {
+	case "Dirty":
+		switch {
+		case task.DoNotDisturb:
+			return 6
+		case task.TaskType != models.TaskTypeCheckout:
+			return 3
+		case task.ArrivalPending:
+			return 1
+		default:
+			return 2
+		}
+	case "Cleaning":
+		return 4
+	default:
+		return 5
+	}
+}
+
+// prioritizeTasks sets the priority of each task and orders them by priority, floor
+// and room number
+func prioritizeTasks(tasks []models.HousekeepingTask) {
+	for i := range tasks {
+		tasks[i].Priority = taskPriority(tasks[i])
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.Floor != b.Floor {
+			return a.Floor < b.Floor
+		}
+		return a.RoomNumber < b.RoomNumber
+	})
+}
+
+// withProgress fills in the share of assigned credits each attendant has completed
+func withProgress(attendants []models.AttendantProgress) []models.AttendantProgress {
+	for i := range attendants {
+		a := &attendants[i]
+		a.AssignedCredits = math.Round(a.AssignedCredits*100) / 100
+		a.CompletedCredits = math.Round(a.CompletedCredits*100) / 100
+		if a.AssignedCredits > 0 {
+			a.ProgressPercent = math.Round(a.CompletedCredits/a.AssignedCredits*1000) / 10
+		}
+	}
+	return attendants
+}
+
+// attendantLoad is an attendant's workload while rooms are being handed out
+type attendantLoad struct {
+	staffID int
+	credits float64
+	rooms   int
+	floors  map[int]bool
+}
+
+// balanceAssignments hands tasks out to attendants in priority order, each to the
+// attendant with the lightest load in credits. Going to a floor the attendant has no
+// rooms on yet counts floorChangePenalty extra. kept are today's assignments that
+// stay as they are; they count towards the loads and floors of their attendants.
+func balanceAssignments(tasks, kept []models.HousekeepingTask, attendants []models.AttendantProgress) []models.HousekeepingAssignment {
+	if len(attendants) == 0 {
+		return nil
+	}
+
+	loads := make([]*attendantLoad, len(attendants))
+	byStaff := make(map[int]*attendantLoad, len(attendants))
+	for i, a := range attendants {
+		loads[i] = &attendantLoad{staffID: a.StaffID, floors: map[int]bool{}}
+		byStaff[a.StaffID] = loads[i]
+	}
+	for _, task := range kept {
+		if task.AssignedTo == nil {
+			continue
+		}
+		if load, ok := byStaff[*task.AssignedTo]; ok {
+			load.credits += task.Credits
+			load.rooms++
+			load.floors[task.Floor] = true
+		}
+	}
+
+	ordered := append([]models.HousekeepingTask(nil), tasks...)
+	prioritizeTasks(ordered)
+
+	assignments := make([]models.HousekeepingAssignment, 0, len(ordered))
+	for _, task := range ordered {
+		var best *attendantLoad
+		bestScore := 0.0
+		for _, load := range loads {
+			score := load.credits
+			if load.rooms > 0 && !load.floors[task.Floor] {
+				score += floorChangePenalty
+			}
+			if best == nil || score < bestScore || (score == bestScore && load.rooms < best.rooms) {
+				best, bestScore = load, score
+			}
+		}
+
+		best.credits += task.Credits
+		best.rooms++
+		best.floors[task.Floor] = true
+		assignments = append(assignments, models.HousekeepingAssignment{
+			RoomID:   task.RoomID,
+			StaffID:  best.staffID,
+			TaskType: task.TaskType,
+			Credits:  task.Credits,
+		})
+	}
+
+	return assignments
+}
+
+// AssignRoom assigns a room to an attendant for today, or takes it off its attendant
+// when staffID is nil
+func (s *HousekeepingService) AssignRoom(ctx context.Context, roomID int, staffID, assignedBy *int) (*models.HousekeepingTask, error) {
+	task, err := s.housekeepingRepo.GetHousekeepingTask(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("room not found")
+	}
+	if task.CompletedAt != nil {
+		return nil, errors.New("room has already been cleaned today")
+	}
+
+	if staffID == nil {
+		if err := s.housekeepingRepo.UnassignRoom(ctx, roomID); err != nil {
+			return nil, err
+		}
+		return s.housekeepingRepo.GetHousekeepingTask(ctx, roomID)
+	}
+
+	if task.HousekeepingStatus != "Dirty" && task.HousekeepingStatus != "Cleaning" {
+		return nil, errors.New("only rooms waiting to be cleaned can be assigned")
+	}
+
+	attendants, err := s.housekeepingRepo.GetAttendants(ctx, []int{*staffID})
+	if err != nil {
+		return nil, err
+	}
+	if len(attendants) == 0 {
+		return nil, errors.New("attendant not found")
+	}
+
+	err = s.housekeepingRepo.SaveAssignments(ctx, []models.HousekeepingAssignment{{
+		RoomID:   roomID,
+		StaffID:  *staffID,
+		TaskType: task.TaskType,
+		Credits:  task.Credits,
+	}}, assignedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.housekeepingRepo.GetHousekeepingTask(ctx, roomID)
+}
+
+// AutoAssign balances the dirty rooms nobody is cleaning yet across attendants. Rooms
+// with do-not-disturb set are left out until the flag is cleared.
+func (s *HousekeepingService) AutoAssign(ctx context.Context, req *models.AutoAssignRequest, assignedBy *int) (*models.AutoAssignResult, error) {
+	attendants, err := s.housekeepingRepo.GetAttendants(ctx, req.AttendantIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.AttendantIDs) > 0 && len(attendants) != len(req.AttendantIDs) {
+		return nil, errors.New("attendant not found")
+	}
+	if len(attendants) == 0 {
+		return nil, errors.New("no active attendants to assign rooms to")
+	}
+
+	tasks, err := s.housekeepingRepo.GetHousekeepingTasks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var open, kept []models.HousekeepingTask
+	for _, task := range tasks {
+		unstarted := task.HousekeepingStatus == "Dirty" && task.StartedAt == nil
+		switch {
+		case unstarted && !task.DoNotDisturb && (task.AssignedTo == nil || req.Rebalance):
+			open = append(open, task)
+		case task.AssignedTo != nil:
+			kept = append(kept, task)
+		}
+	}
+
+	assignments := balanceAssignments(open, kept, attendants)
+	if len(assignments) > 0 {
+		if err := s.housekeepingRepo.SaveAssignments(ctx, assignments, assignedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reload so the loads include rooms already completed today
+	attendants, err = s.housekeepingRepo.GetAttendants(ctx, req.AttendantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AutoAssignResult{
+		Assignments: assignments,
+		Attendants:  withProgress(attendants),
+	}, nil
+}
+
+// GetHousekeepingCredits retrieves the cleaning credits of every room type
+func (s *HousekeepingService) GetHousekeepingCredits(ctx context.Context) ([]models.HousekeepingCredits, error) {
+	return s.housekeepingRepo.GetHousekeepingCredits(ctx)
+}
+
+// UpdateHousekeepingCredits sets the cleaning credits and estimated minutes of a room
+// type; rooms already assigned keep the credits they were assigned for
+func (s *HousekeepingService) UpdateHousekeepingCredits(ctx context.Context, roomTypeID int, req *models.UpdateHousekeepingCreditsRequest) error {
+	found, err := s.housekeepingRepo.UpsertHousekeepingCredits(ctx, roomTypeID, req)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("room type not found")
+	}
+	return nil
+}
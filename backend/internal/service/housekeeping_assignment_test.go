@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dirtyRoom(roomID, floor int, taskType string, credits float64) models.HousekeepingTask {
+	return models.HousekeepingTask{
+		RoomID:             roomID,
+		RoomNumber:         fmt.Sprintf("%d%02d", floor, roomID),
+		Floor:              floor,
+		HousekeepingStatus: "Dirty",
+		TaskType:           taskType,
+		Credits:            credits,
+	}
+}
+
+func TestPrioritizeTasks(t *testing.T) {
+	arrival := dirtyRoom(1, 3, models.TaskTypeCheckout, 1)
+	arrival.ArrivalPending = true
+	cleaning := dirtyRoom(2, 1, models.TaskTypeCheckout, 1)
+	cleaning.HousekeepingStatus = "Cleaning"
+	clean := dirtyRoom(3, 1, models.TaskTypeCheckout, 1)
+	clean.HousekeepingStatus = "Clean"
+	stayover := dirtyRoom(4, 1, models.TaskTypeStayover, 0.5)
+	checkout := dirtyRoom(5, 2, models.TaskTypeCheckout, 1)
+
+	tasks := []models.HousekeepingTask{clean, stayover, cleaning, checkout, arrival}
+	prioritizeTasks(tasks)
+
+	var order []int
+	for _, task := range tasks {
+		order = append(order, task.RoomID)
+	}
+	assert.Equal(t, []int{1, 5, 4, 2, 3}, order)
+	assert.Equal(t, 1, tasks[0].Priority)
+	assert.Equal(t, 5, tasks[4].Priority)
+}
+
+func TestPrioritizeTasksStayoverServices(t *testing.T) {
+	refresh := dirtyRoom(1, 1, models.TaskTypeRefresh, 0.25)
+	dnd := dirtyRoom(2, 1, models.TaskTypeStayover, 0.5)
+	dnd.DoNotDisturb = true
+	clean := dirtyRoom(3, 1, models.TaskTypeCheckout, 1)
+	clean.HousekeepingStatus = "Clean"
+	stayover := dirtyRoom(4, 1, models.TaskTypeStayover, 0.5)
+
+	tasks := []models.HousekeepingTask{dnd, clean, stayover, refresh}
+	prioritizeTasks(tasks)
+
+	var order []int
+	for _, task := range tasks {
+		order = append(order, task.RoomID)
+	}
+	assert.Equal(t, []int{1, 4, 3, 2}, order)
+	assert.Equal(t, 3, tasks[0].Priority)
+	assert.Equal(t, 6, tasks[3].Priority)
+}
+
+func TestBalanceAssignmentsEvensCredits(t *testing.T) {
+	tasks := []models.HousekeepingTask{
+		dirtyRoom(1, 1, models.TaskTypeCheckout, 2),
+		dirtyRoom(2, 1, models.TaskTypeCheckout, 1),
+		dirtyRoom(3, 1, models.TaskTypeCheckout, 1),
+		dirtyRoom(4, 1, models.TaskTypeStayover, 0.5),
+		dirtyRoom(5, 1, models.TaskTypeStayover, 0.5),
+	}
+	attendants := []models.AttendantProgress{{StaffID: 10}, {StaffID: 20}}
+
+	assignments := balanceAssignments(tasks, nil, attendants)
+	require.Len(t, assignments, 5)
+
+	credits := map[int]float64{}
+	for _, a := range assignments {
+		credits[a.StaffID] += a.Credits
+	}
+	assert.Equal(t, 2.5, credits[10])
+	assert.Equal(t, 2.5, credits[20])
+}
+
+func TestBalanceAssignmentsKeepsAttendantsOnTheirFloor(t *testing.T) {
+	tasks := []models.HousekeepingTask{
+		dirtyRoom(1, 1, models.TaskTypeCheckout, 1),
+		dirtyRoom(2, 2, models.TaskTypeCheckout, 1),
+		dirtyRoom(3, 1, models.TaskTypeStayover, 0.5),
+		dirtyRoom(4, 2, models.TaskTypeStayover, 0.5),
+	}
+	attendants := []models.AttendantProgress{{StaffID: 10}, {StaffID: 20}}
+
+	assignments := balanceAssignments(tasks, nil, attendants)
+
+	floors := map[int]map[int]bool{}
+	for _, a := range assignments {
+		if floors[a.StaffID] == nil {
+			floors[a.StaffID] = map[int]bool{}
+		}
+		for _, task := range tasks {
+			if task.RoomID == a.RoomID {
+				floors[a.StaffID][task.Floor] = true
+			}
+		}
+	}
+	assert.Len(t, floors[10], 1)
+	assert.Len(t, floors[20], 1)
+}
+
+func TestBalanceAssignmentsCountsKeptRooms(t *testing.T) {
+	busy := 10
+	kept := dirtyRoom(9, 1, models.TaskTypeCheckout, 3)
+	kept.AssignedTo = &busy
+
+	assignments := balanceAssignments(
+		[]models.HousekeepingTask{dirtyRoom(1, 1, models.TaskTypeCheckout, 1), dirtyRoom(2, 1, models.TaskTypeCheckout, 1)},
+		[]models.HousekeepingTask{kept},
+		[]models.AttendantProgress{{StaffID: 10}, {StaffID: 20}},
+	)
+
+	require.Len(t, assignments, 2)
+	assert.Equal(t, 20, assignments[0].StaffID)
+	assert.Equal(t, 20, assignments[1].StaffID)
+}
+
+func TestBalanceAssignmentsWithoutAttendants(t *testing.T) {
+	assert.Empty(t, balanceAssignments([]models.HousekeepingTask{dirtyRoom(1, 1, models.TaskTypeCheckout, 1)}, nil, nil))
+}
+
+func TestWithProgress(t *testing.T) {
+	attendants := withProgress([]models.AttendantProgress{
+		{StaffID: 1, AssignedCredits: 3, CompletedCredits: 1},
+		{StaffID: 2},
+	})
+
+	assert.Equal(t, 33.3, attendants[0].ProgressPercent)
+	assert.Equal(t, 0.0, attendants[1].ProgressPercent)
+}
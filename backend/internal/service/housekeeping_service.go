@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/internal/repository"
@@ -11,23 +12,42 @@ import (
 
 // HousekeepingService handles housekeeping business logic
 type HousekeepingService struct {
-	housekeepingRepo *repository.HousekeepingRepository
+	housekeepingRepo   *repository.HousekeepingRepository
+	maintenanceService *MaintenanceService
+	roomEvents         RoomChangeListener
 }
 
 // NewHousekeepingService creates a new housekeeping service
-func NewHousekeepingService(housekeepingRepo *repository.HousekeepingRepository) *HousekeepingService {
+func NewHousekeepingService(housekeepingRepo *repository.HousekeepingRepository, maintenanceService *MaintenanceService) *HousekeepingService {
 	return &HousekeepingService{
-		housekeepingRepo: housekeepingRepo,
+		housekeepingRepo:   housekeepingRepo,
+		maintenanceService: maintenanceService,
 	}
 }
 
-// GetHousekeepingTasks retrieves all housekeeping tasks with summary
-func (s *HousekeepingService) GetHousekeepingTasks(ctx context.Context) (*models.HousekeepingTasksResponse, error) {
+// SetRoomChangeListener registers a listener notified when rooms change housekeeping
+// status
+func (s *HousekeepingService) SetRoomChangeListener(listener RoomChangeListener) {
+	s.roomEvents = listener
+}
+
+// roomChanged tells the room change listener a room's status changed
+func (s *HousekeepingService) roomChanged(ctx context.Context, roomID int) {
+	if s.roomEvents != nil {
+		s.roomEvents.RoomsChanged(ctx, models.RoomEventStatus, roomID)
+	}
+}
+
+// GetHousekeepingTasks retrieves the housekeeping tasks in priority order with a
+// summary and the progress of each attendant. Given an attendant, only the rooms
+// assigned to them today and their own progress are returned.
+func (s *HousekeepingService) GetHousekeepingTasks(ctx context.Context, attendantID *int) (*models.HousekeepingTasksResponse, error) {
 	// Get tasks
-	tasks, err := s.housekeepingRepo.GetHousekeepingTasks(ctx)
+	tasks, err := s.housekeepingRepo.GetHousekeepingTasks(ctx, attendantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
+	prioritizeTasks(tasks)
 
 	// Get summary
 	summary, err := s.housekeepingRepo.GetTaskSummary(ctx)
@@ -35,15 +55,25 @@ func (s *HousekeepingService) GetHousekeepingTasks(ctx context.Context) (*models
 		return nil, fmt.Errorf("failed to get summary: %w", err)
 	}
 
+	var staffIDs []int
+	if attendantID != nil {
+		staffIDs = []int{*attendantID}
+	}
+	attendants, err := s.housekeepingRepo.GetAttendants(ctx, staffIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendants: %w", err)
+	}
+
 	return &models.HousekeepingTasksResponse{
 		Tasks:      tasks,
 		TotalTasks: len(tasks),
 		Summary:    *summary,
+		Attendants: withProgress(attendants),
 	}, nil
 }
 
-// UpdateRoomStatus updates the housekeeping status of a room
-func (s *HousekeepingService) UpdateRoomStatus(ctx context.Context, roomID int, status string) error {
+// UpdateRoomStatus updates the housekeeping status of a room on behalf of a staff member
+func (s *HousekeepingService) UpdateRoomStatus(ctx context.Context, roomID int, status string, staffID *int) error {
 	// Validate status
 	validStatuses := []string{"Dirty", "Cleaning", "Clean", "Inspected", "MaintenanceRequired", "OutOfService"}
 	if !contains(validStatuses, status) {
@@ -61,16 +91,40 @@ func (s *HousekeepingService) UpdateRoomStatus(ctx context.Context, roomID int,
 		return err
 	}
 
+	// A room under repair goes back to cleaning when its last work order is resolved
+	if room.HousekeepingStatus == "MaintenanceRequired" && status == "Dirty" {
+		workOrders, err := s.maintenanceService.GetWorkOrders(ctx, models.WorkOrderFilter{RoomID: &roomID, OpenOnly: true})
+		if err != nil {
+			return fmt.Errorf("failed to get work orders: %w", err)
+		}
+		if len(workOrders) > 0 {
+			return fmt.Errorf("invalid status transition from %s to %s: room has %d unresolved work orders", room.HousekeepingStatus, status, len(workOrders))
+		}
+	}
+
+	// Attendants wait for the front desk to clear do-not-disturb before going in
+	if status == "Cleaning" {
+		task, err := s.housekeepingRepo.GetHousekeepingTask(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get room: %w", err)
+		}
+		if task != nil && task.DoNotDisturb {
+			return errors.New("room is marked do not disturb")
+		}
+	}
+
 	// Update status
-	if err := s.housekeepingRepo.UpdateRoomStatus(ctx, roomID, status); err != nil {
+	if err := s.housekeepingRepo.UpdateRoomStatus(ctx, roomID, status, staffID, ""); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
+	s.roomChanged(ctx, roomID)
 	return nil
 }
 
-// InspectRoom allows supervisor to approve or reject a cleaned room
-func (s *HousekeepingService) InspectRoom(ctx context.Context, roomID int, approved bool, notes string) error {
+// InspectRoom allows supervisor to approve or reject a cleaned room; the notes are kept
+// in the room's status history
+func (s *HousekeepingService) InspectRoom(ctx context.Context, roomID int, approved bool, notes string, inspectedBy *int) error {
 	// Get current room status
 	room, err := s.housekeepingRepo.GetRoomByID(ctx, roomID)
 	if err != nil {
@@ -90,10 +144,11 @@ func (s *HousekeepingService) InspectRoom(ctx context.Context, roomID int, appro
 		newStatus = "Dirty"
 	}
 
-	if err := s.housekeepingRepo.UpdateRoomStatus(ctx, roomID, newStatus); err != nil {
+	if err := s.housekeepingRepo.UpdateRoomStatus(ctx, roomID, newStatus, inspectedBy, strings.TrimSpace(notes)); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
+	s.roomChanged(ctx, roomID)
 	return nil
 }
 
@@ -107,23 +162,66 @@ func (s *HousekeepingService) GetRoomsForInspection(ctx context.Context) ([]mode
 	return tasks, nil
 }
 
-// ReportMaintenance marks a room as requiring maintenance
-func (s *HousekeepingService) ReportMaintenance(ctx context.Context, roomID int, description string) error {
-	// Get current room status
-	_, err := s.housekeepingRepo.GetRoomByID(ctx, roomID)
+// ReportMaintenance opens a maintenance work order for a room and marks the room as
+// requiring maintenance
+func (s *HousekeepingService) ReportMaintenance(ctx context.Context, roomID int, reportedBy *int, req *models.ReportMaintenanceRequest) (*models.WorkOrder, error) {
+	return s.maintenanceService.CreateWorkOrder(ctx, reportedBy, &models.CreateWorkOrderRequest{
+		RoomID:      roomID,
+		Category:    req.Category,
+		Priority:    req.Priority,
+		Description: req.Description,
+	})
+}
+
+// GetRoomServicePreferences retrieves the DND flag of an occupied room and the service
+// preferences of the stay in it
+func (s *HousekeepingService) GetRoomServicePreferences(ctx context.Context, roomID int) (*models.RoomServicePreferences, error) {
+	prefs, err := s.housekeepingRepo.GetRoomServicePreferences(ctx, roomID)
 	if err != nil {
-		return fmt.Errorf("failed to get room: %w", err)
+		return nil, err
 	}
+	if prefs == nil {
+		return nil, s.unoccupiedRoomError(ctx, roomID)
+	}
+	return prefs, nil
+}
 
-	// Update status to MaintenanceRequired
-	if err := s.housekeepingRepo.UpdateRoomStatus(ctx, roomID, "MaintenanceRequired"); err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
+// SetDoNotDisturb sets or clears the DND flag of an occupied room. The night audit
+// clears every flag, so guests ask again each day.
+func (s *HousekeepingService) SetDoNotDisturb(ctx context.Context, roomID int, doNotDisturb bool, staffID *int) (*models.RoomServicePreferences, error) {
+	found, err := s.housekeepingRepo.SetDoNotDisturb(ctx, roomID, doNotDisturb, staffID)
+	if err != nil {
+		return nil, err
 	}
+	if !found {
+		return nil, s.unoccupiedRoomError(ctx, roomID)
+	}
+	return s.GetRoomServicePreferences(ctx, roomID)
+}
 
-	// In a full implementation, we would also create a maintenance ticket here
-	// For now, we just update the status
+// SetGreenOptOut enrols the stay in a room in the green program or takes it out. It
+// applies from the next service the night audit schedules.
+func (s *HousekeepingService) SetGreenOptOut(ctx context.Context, roomID int, optOut bool) (*models.RoomServicePreferences, error) {
+	found, err := s.housekeepingRepo.SetGreenOptOut(ctx, roomID, optOut)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, s.unoccupiedRoomError(ctx, roomID)
+	}
+	return s.GetRoomServicePreferences(ctx, roomID)
+}
 
-	return nil
+// unoccupiedRoomError tells a room that does not exist from one nobody is staying in
+func (s *HousekeepingService) unoccupiedRoomError(ctx context.Context, roomID int) error {
+	task, err := s.housekeepingRepo.GetHousekeepingTask(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return errors.New("room not found")
+	}
+	return errors.New("no guest is staying in this room")
 }
 
 // validateStatusTransition validates if a status transition is allowed
@@ -12,6 +12,8 @@ import (
 type InventoryService struct {
 	inventoryRepo *repository.InventoryRepository
 	roomRepo      *repository.RoomRepository
+	listener      InventoryListener
+	roomEvents    RoomChangeListener
 }
 
 func NewInventoryService(inventoryRepo *repository.InventoryRepository, roomRepo *repository.RoomRepository) *InventoryService {
@@ -21,6 +23,17 @@ func NewInventoryService(inventoryRepo *repository.InventoryRepository, roomRepo
 	}
 }
 
+// SetInventoryListener registers a listener notified after allotment changes
+func (s *InventoryService) SetInventoryListener(listener InventoryListener) {
+	s.listener = listener
+}
+
+// SetRoomChangeListener registers a listener notified when rooms go out of order or
+// come back
+func (s *InventoryService) SetRoomChangeListener(listener RoomChangeListener) {
+	s.roomEvents = listener
+}
+
 // GetInventory retrieves inventory for a specific room type and date range
 func (s *InventoryService) GetInventory(ctx context.Context, roomTypeID int, startDate, endDate string) ([]models.RoomInventoryWithDetails, error) {
 	// Parse dates
@@ -104,7 +117,16 @@ func (s *InventoryService) UpdateInventory(ctx context.Context, req *models.Upda
 		return err
 	}
 
-	return s.inventoryRepo.UpdateInventory(ctx, req.RoomTypeID, date, req.Allotment)
+	if err := s.inventoryRepo.UpdateInventory(ctx, req.RoomTypeID, date, req.Allotment); err != nil {
+		return err
+	}
+
+	// An allotment increase may satisfy waitlisted guests
+	if s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+
+	return nil
 }
 
 // BulkUpdateInventory updates inventory for a date range
@@ -147,6 +169,10 @@ func (s *InventoryService) BulkUpdateInventory(ctx context.Context, req *models.
 		return nil, err
 	}
 
+	if s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+
 	return validationErrors, nil
 }
 
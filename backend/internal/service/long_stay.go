@@ -0,0 +1,172 @@
+package service
+
+import (
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// selectLongStayRate picks the long-stay rate of a stay under a rate plan: the longest
+// threshold the stay reaches, preferring a rate for the room type over one for all
+// room types. Returns nil when no threshold is reached.
+func selectLongStayRate(rates []models.LongStayRate, ratePlanID, roomTypeID, nights int) *models.LongStayRate {
+	var best *models.LongStayRate
+	for i := range rates {
+		rate := &rates[i]
+		if !rate.IsActive || rate.RatePlanID != ratePlanID || rate.MinNights > nights {
+			continue
+		}
+		if rate.RoomTypeID != nil && *rate.RoomTypeID != roomTypeID {
+			continue
+		}
+		if best == nil || rate.MinNights > best.MinNights ||
+			(rate.MinNights == best.MinNights && best.RoomTypeID == nil && rate.RoomTypeID != nil) {
+			best = rate
+		}
+	}
+	return best
+}
+
+// longStayRoomRate returns the room rate of a night under a long-stay rate.
+// A monthly price is charged per night as 1/30 of the month.
+func longStayRoomRate(rate *models.LongStayRate, price float64) float64 {
+	switch {
+	case rate == nil:
+		return price
+	case rate.MonthlyPrice != nil:
+		return roundAmount(*rate.MonthlyPrice / models.LongStayMonthNights)
+	case rate.DiscountPercent != nil:
+		return roundAmount(price * (1 - *rate.DiscountPercent/100))
+	}
+	return price
+}
+
+// applyLongStayRate replaces the room rates of a quote with the long-stay rate and keeps
+// the difference as the night's discount. Occupancy surcharges are added afterwards.
+func applyLongStayRate(prices []models.NightlyPrice, rate *models.LongStayRate) {
+	if rate == nil {
+		return
+	}
+	for i := range prices {
+		roomRate := longStayRoomRate(rate, prices[i].RoomRate)
+		prices[i].Discount = roundAmount(prices[i].RoomRate - roomRate)
+		prices[i].RoomRate = roomRate
+		prices[i].Price = roomRate
+	}
+}
+
+// billingPeriodNights returns the nights one invoice covers; 0 bills the stay at once
+func billingPeriodNights(cycle string) int {
+	switch cycle {
+	case models.BillingCycleWeekly:
+		return 7
+	case models.BillingCycleMonthly:
+		return models.LongStayMonthNights
+	}
+	return 0
+}
+
+// buildBillingSchedule splits a folio into invoices of the billing cycle, each due at the
+// start of its period counted from the earliest check-in. Dated lines go on the invoice
+// of their date. An undated room charge (the nightly log is only written on confirmation)
+// is shared out by nights, and the discount is taken off the first invoice.
+func buildBillingSchedule(booking *models.BookingWithDetails, folio *models.BookingFolio, cycle string) *models.BillingSchedule {
+	if cycle == "" {
+		cycle = models.BillingCyclePerStay
+	}
+	schedule := &models.BillingSchedule{
+		BookingID:    booking.BookingID,
+		BillingCycle: cycle,
+		Invoices:     []models.BillingInvoice{},
+		Total:        folio.Total,
+	}
+	if len(booking.Details) == 0 {
+		return schedule
+	}
+
+	stayStart := booking.Details[0].CheckInDate
+	stayEnd := booking.Details[0].CheckOutDate
+	for _, detail := range booking.Details[1:] {
+		if detail.CheckInDate.Before(stayStart) {
+			stayStart = detail.CheckInDate
+		}
+		if detail.CheckOutDate.After(stayEnd) {
+			stayEnd = detail.CheckOutDate
+		}
+	}
+	stayNights := nightsBetween(stayStart, stayEnd)
+
+	periodNights := billingPeriodNights(cycle)
+	if periodNights == 0 || periodNights >= stayNights {
+		periodNights = stayNights
+	}
+
+	// A same-day (day-use) stay still gets one invoice
+	for start := stayStart; len(schedule.Invoices) == 0 || start.Before(stayEnd); start = start.AddDate(0, 0, periodNights) {
+		end := start.AddDate(0, 0, periodNights)
+		if end.After(stayEnd) {
+			end = stayEnd
+		}
+		schedule.Invoices = append(schedule.Invoices, models.BillingInvoice{
+			InvoiceNumber: len(schedule.Invoices) + 1,
+			PeriodStart:   start,
+			PeriodEnd:     end,
+			DueDate:       start,
+			Nights:        nightsBetween(start, end),
+			Lines:         []models.FolioLine{},
+		})
+		if periodNights == 0 {
+			break
+		}
+	}
+
+	invoices := schedule.Invoices
+	for _, line := range folio.Lines {
+		switch {
+		case line.Date != nil:
+			i := 0
+			if periodNights > 0 {
+				i = nightsBetween(stayStart, *line.Date) / periodNights
+			}
+			if i < 0 {
+				i = 0
+			}
+			if i >= len(invoices) {
+				i = len(invoices) - 1
+			}
+			invoices[i].Lines = append(invoices[i].Lines, line)
+		case line.Category == models.FolioCategoryRoom && len(invoices) > 1:
+			remaining := line.Amount
+			for i := range invoices {
+				amount := remaining
+				if i < len(invoices)-1 {
+					amount = roundAmount(line.Amount * float64(invoices[i].Nights) / float64(stayNights))
+				}
+				remaining = roundAmount(remaining - amount)
+				invoices[i].Lines = append(invoices[i].Lines, models.FolioLine{
+					Category:    line.Category,
+					Description: line.Description,
+					Quantity:    1,
+					UnitPrice:   amount,
+					Amount:      amount,
+				})
+			}
+		default:
+			invoices[0].Lines = append(invoices[0].Lines, line)
+		}
+	}
+
+	for i := range invoices {
+		for _, line := range invoices[i].Lines {
+			invoices[i].Amount += line.Amount
+		}
+		invoices[i].Amount = roundAmount(invoices[i].Amount)
+	}
+
+	return schedule
+}
+
+// nightsBetween counts the nights from one date to another
+func nightsBetween(from, to time.Time) int {
+	return int(dateOnly(to).Sub(dateOnly(from)).Hours() / 24)
+}
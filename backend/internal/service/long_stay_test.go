@@ -0,0 +1,132 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func longStayRates() []models.LongStayRate {
+	return []models.LongStayRate{
+		{LongStayRateID: 1, RatePlanID: 5, MinNights: 7, DiscountPercent: floatPtr(15), IsActive: true},
+		{LongStayRateID: 2, RatePlanID: 5, RoomTypeID: intPtr(2), MinNights: 7, DiscountPercent: floatPtr(20), IsActive: true},
+		{LongStayRateID: 3, RatePlanID: 5, RoomTypeID: intPtr(1), MinNights: 28, MonthlyPrice: floatPtr(36000), IsActive: true},
+		{LongStayRateID: 4, RatePlanID: 5, MinNights: 60, DiscountPercent: floatPtr(40), IsActive: false},
+		{LongStayRateID: 5, RatePlanID: 6, MinNights: 3, DiscountPercent: floatPtr(10), IsActive: true},
+	}
+}
+
+func TestSelectLongStayRate(t *testing.T) {
+	rates := longStayRates()
+
+	assert.Nil(t, selectLongStayRate(rates, 5, 1, 6), "below the lowest threshold")
+	assert.Equal(t, 1, selectLongStayRate(rates, 5, 1, 7).LongStayRateID)
+	assert.Equal(t, 2, selectLongStayRate(rates, 5, 2, 10).LongStayRateID, "room type rate wins at the same threshold")
+	assert.Equal(t, 3, selectLongStayRate(rates, 5, 1, 30).LongStayRateID, "longest threshold reached wins")
+	assert.Equal(t, 2, selectLongStayRate(rates, 5, 2, 30).LongStayRateID, "monthly price is for another room type")
+	assert.Equal(t, 1, selectLongStayRate(rates, 5, 3, 90).LongStayRateID, "inactive rates are ignored")
+	assert.Nil(t, selectLongStayRate(rates, 7, 1, 30), "other rate plan")
+}
+
+func TestLongStayRoomRate(t *testing.T) {
+	rates := longStayRates()
+
+	assert.Equal(t, 2000.0, longStayRoomRate(nil, 2000))
+	assert.Equal(t, 1700.0, longStayRoomRate(&rates[0], 2000))
+	assert.Equal(t, 1200.0, longStayRoomRate(&rates[2], 2000), "1/30 of the monthly price")
+}
+
+func TestApplyLongStayRate(t *testing.T) {
+	rates := longStayRates()
+	prices := []models.NightlyPrice{
+		{Date: "2025-07-01", Price: 2000, RoomRate: 2000},
+		{Date: "2025-07-02", Price: 2400, RoomRate: 2400},
+	}
+
+	applyLongStayRate(prices, &rates[0])
+	total := applySurcharges(prices, map[string]float64{"2025-07-02": 600})
+
+	assert.Equal(t, 1700.0, prices[0].RoomRate)
+	assert.Equal(t, 300.0, prices[0].Discount)
+	assert.Equal(t, 2040.0, prices[1].RoomRate)
+	assert.Equal(t, 2640.0, prices[1].Price, "surcharge is added on top, not discounted")
+	assert.Equal(t, 4340.0, total)
+}
+
+func TestBuildBillingSchedule(t *testing.T) {
+	booking := &models.BookingWithDetails{
+		Booking: models.Booking{BookingID: 7, Status: "Confirmed"},
+		Details: []models.BookingDetailWithGuests{{
+			BookingDetail: models.BookingDetail{
+				CheckInDate:  addOnDate("2025-07-01"),
+				CheckOutDate: addOnDate("2025-08-15"),
+			},
+			RoomTypeName: "Deluxe Room",
+		}},
+	}
+	for d := addOnDate("2025-07-01"); d.Before(addOnDate("2025-08-15")); d = d.AddDate(0, 0, 1) {
+		booking.Details[0].NightlyPrices = append(booking.Details[0].NightlyPrices,
+			models.BookingNightlyLog{Date: d, QuotedPrice: 1200})
+	}
+	booking.TotalAmount = 45*1200 - 500
+
+	t.Run("monthly invoices follow the nightly log", func(t *testing.T) {
+		schedule := buildBillingSchedule(booking, buildFolio(booking), models.BillingCycleMonthly)
+		require.Len(t, schedule.Invoices, 2)
+
+		first, second := schedule.Invoices[0], schedule.Invoices[1]
+		assert.Equal(t, addOnDate("2025-07-01"), first.DueDate)
+		assert.Equal(t, addOnDate("2025-07-31"), first.PeriodEnd)
+		assert.Equal(t, 30, first.Nights)
+		assert.Equal(t, 30*1200.0-500, first.Amount, "discount is taken off the first invoice")
+		assert.Equal(t, addOnDate("2025-08-15"), second.PeriodEnd)
+		assert.Equal(t, 15, second.Nights)
+		assert.Equal(t, 15*1200.0, second.Amount)
+		assert.Equal(t, booking.TotalAmount, first.Amount+second.Amount)
+	})
+
+	t.Run("per-stay billing is a single invoice", func(t *testing.T) {
+		schedule := buildBillingSchedule(booking, buildFolio(booking), "")
+		require.Len(t, schedule.Invoices, 1)
+		assert.Equal(t, models.BillingCyclePerStay, schedule.BillingCycle)
+		assert.Equal(t, 45, schedule.Invoices[0].Nights)
+		assert.Equal(t, booking.TotalAmount, schedule.Invoices[0].Amount)
+	})
+
+	t.Run("unconfirmed room charge is shared out by nights", func(t *testing.T) {
+		pending := *booking
+		pending.Details = []models.BookingDetailWithGuests{booking.Details[0]}
+		pending.Details[0].NightlyPrices = nil
+		pending.TotalAmount = 9000
+
+		schedule := buildBillingSchedule(&pending, buildFolio(&pending), models.BillingCycleWeekly)
+		require.Len(t, schedule.Invoices, 7)
+		assert.Equal(t, 1400.0, schedule.Invoices[0].Amount)
+		assert.Equal(t, 3, schedule.Invoices[6].Nights)
+		assert.Equal(t, 600.0, schedule.Invoices[6].Amount)
+
+		var total float64
+		for _, invoice := range schedule.Invoices {
+			total += invoice.Amount
+		}
+		assert.InDelta(t, 9000.0, total, 0.001)
+	})
+
+	t.Run("day-use stay gets one invoice", func(t *testing.T) {
+		dayUse := &models.BookingWithDetails{
+			Booking: models.Booking{BookingID: 8, TotalAmount: 800},
+			Details: []models.BookingDetailWithGuests{{
+				BookingDetail: models.BookingDetail{CheckInDate: addOnDate("2025-07-01"), CheckOutDate: addOnDate("2025-07-01")},
+			}},
+		}
+		schedule := buildBillingSchedule(dayUse, buildFolio(dayUse), models.BillingCycleMonthly)
+		require.Len(t, schedule.Invoices, 1)
+		assert.Equal(t, 800.0, schedule.Invoices[0].Amount)
+	})
+}
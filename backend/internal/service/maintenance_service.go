@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/internal/repository"
+	"github.com/hotel-booking-system/backend/pkg/storage"
+	"github.com/hotel-booking-system/backend/pkg/utils"
+)
+
+// maxWorkOrderPhotoSize is the largest side of a stored work order photo
+const maxWorkOrderPhotoSize = 1920
+
+// MaintenanceService handles maintenance work order business logic
+type MaintenanceService struct {
+	maintenanceRepo *repository.MaintenanceRepository
+	blobs           storage.BlobStore
+	roomEvents      RoomChangeListener
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(maintenanceRepo *repository.MaintenanceRepository) *MaintenanceService {
+	return &MaintenanceService{
+		maintenanceRepo: maintenanceRepo,
+	}
+}
+
+// SetBlobStore registers the store work order photos are kept in
+func (s *MaintenanceService) SetBlobStore(blobs storage.BlobStore) {
+	s.blobs = blobs
+}
+
+// SetRoomChangeListener registers a listener notified when work orders take rooms out
+// for maintenance or return them
+func (s *MaintenanceService) SetRoomChangeListener(listener RoomChangeListener) {
+	s.roomEvents = listener
+}
+
+// roomChanged tells the room change listener a room's status may have changed
+func (s *MaintenanceService) roomChanged(ctx context.Context, roomID int) {
+	if s.roomEvents != nil {
+		s.roomEvents.RoomsChanged(ctx, models.RoomEventStatus, roomID)
+	}
+}
+
+// workOrderTransitions lists the statuses a work order can move to from each status
+var workOrderTransitions = map[string][]string{
+	models.WorkOrderOpen:       {models.WorkOrderInProgress, models.WorkOrderOnHold, models.WorkOrderResolved},
+	models.WorkOrderInProgress: {models.WorkOrderOpen, models.WorkOrderOnHold, models.WorkOrderResolved},
+	models.WorkOrderOnHold:     {models.WorkOrderOpen, models.WorkOrderInProgress, models.WorkOrderResolved},
+	models.WorkOrderResolved:   {models.WorkOrderOpen},
+}
+
+// validateWorkOrderTransition checks that a work order may move between two statuses
+func validateWorkOrderTransition(currentStatus, newStatus string) error {
+	if _, ok := workOrderTransitions[newStatus]; !ok {
+		return fmt.Errorf("invalid work order status: %s", newStatus)
+	}
+	if currentStatus == newStatus {
+		return fmt.Errorf("work order is already %s", newStatus)
+	}
+	if !contains(workOrderTransitions[currentStatus], newStatus) {
+		return fmt.Errorf("invalid status transition from %s to %s", currentStatus, newStatus)
+	}
+	return nil
+}
+
+// normalizeWorkOrder fills in the default category and priority and checks the fields
+// of a work order
+func normalizeWorkOrder(wo *models.WorkOrder) error {
+	wo.Description = strings.TrimSpace(wo.Description)
+	if wo.Description == "" {
+		return errors.New("description is required")
+	}
+	if wo.Category == "" {
+		wo.Category = "Other"
+	}
+	if wo.Priority == "" {
+		wo.Priority = "Medium"
+	}
+	if !contains(models.WorkOrderCategories, wo.Category) {
+		return fmt.Errorf("invalid category, use one of: %s", strings.Join(models.WorkOrderCategories, ", "))
+	}
+	if !contains(models.WorkOrderPriorities, wo.Priority) {
+		return fmt.Errorf("invalid priority, use one of: %s", strings.Join(models.WorkOrderPriorities, ", "))
+	}
+	return nil
+}
+
+// summarizeRepairTimes works out the mean time to repair overall and per room, with
+// the rooms that take longest to repair first
+func summarizeRepairTimes(times []models.RepairTime) (float64, []models.RoomRepairTimeReport) {
+	rooms := []models.RoomRepairTimeReport{}
+	if len(times) == 0 {
+		return 0, rooms
+	}
+
+	index := make(map[int]int)
+	totals := make(map[int]float64)
+	var total float64
+	for _, t := range times {
+		hours := t.ResolvedAt.Sub(t.ReportedAt).Hours()
+		total += hours
+
+		i, ok := index[t.RoomID]
+		if !ok {
+			i = len(rooms)
+			index[t.RoomID] = i
+			rooms = append(rooms, models.RoomRepairTimeReport{RoomID: t.RoomID, RoomNumber: t.RoomNumber})
+		}
+		rooms[i].ResolvedOrders++
+		totals[t.RoomID] += hours
+		if longest := roundHours(hours); longest > rooms[i].LongestHoursToRepair {
+			rooms[i].LongestHoursToRepair = longest
+		}
+	}
+
+	for i := range rooms {
+		rooms[i].MeanHoursToRepair = roundHours(totals[rooms[i].RoomID] / float64(rooms[i].ResolvedOrders))
+	}
+	sort.SliceStable(rooms, func(a, b int) bool {
+		if rooms[a].MeanHoursToRepair != rooms[b].MeanHoursToRepair {
+			return rooms[a].MeanHoursToRepair > rooms[b].MeanHoursToRepair
+		}
+		return rooms[a].RoomNumber < rooms[b].RoomNumber
+	})
+
+	return roundHours(total / float64(len(times))), rooms
+}
+
+// roundHours rounds a number of hours to two decimals
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// withPhotoURLs fills in the photo URLs of work orders
+func (s *MaintenanceService) withPhotoURLs(workOrders []models.WorkOrder) []models.WorkOrder {
+	for i := range workOrders {
+		workOrders[i].PhotoURLs = make([]string, 0, len(workOrders[i].PhotoKeys))
+		if s.blobs == nil {
+			continue
+		}
+		for _, key := range workOrders[i].PhotoKeys {
+			workOrders[i].PhotoURLs = append(workOrders[i].PhotoURLs, s.blobs.URL(key))
+		}
+	}
+	return workOrders
+}
+
+// withPhotoURL fills in the photo URLs of one work order
+func (s *MaintenanceService) withPhotoURL(wo *models.WorkOrder) *models.WorkOrder {
+	return &s.withPhotoURLs([]models.WorkOrder{*wo})[0]
+}
+
+// CreateWorkOrder opens a work order for a room and marks the room MaintenanceRequired
+func (s *MaintenanceService) CreateWorkOrder(ctx context.Context, reportedBy *int, req *models.CreateWorkOrderRequest) (*models.WorkOrder, error) {
+	wo := &models.WorkOrder{
+		RoomID:      req.RoomID,
+		Category:    req.Category,
+		Priority:    req.Priority,
+		Description: req.Description,
+		ReportedBy:  reportedBy,
+	}
+	if err := normalizeWorkOrder(wo); err != nil {
+		return nil, err
+	}
+
+	created, err := s.maintenanceRepo.CreateWorkOrder(ctx, wo)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("room not found")
+	}
+
+	s.roomChanged(ctx, created.RoomID)
+	return s.withPhotoURL(created), nil
+}
+
+// GetWorkOrder retrieves a work order
+func (s *MaintenanceService) GetWorkOrder(ctx context.Context, workOrderID int) (*models.WorkOrder, error) {
+	wo, err := s.maintenanceRepo.GetWorkOrderByID(ctx, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if wo == nil {
+		return nil, errors.New("work order not found")
+	}
+	return s.withPhotoURL(wo), nil
+}
+
+// GetWorkOrders lists work orders, most urgent first
+func (s *MaintenanceService) GetWorkOrders(ctx context.Context, filter models.WorkOrderFilter) ([]models.WorkOrder, error) {
+	if filter.Status != "" {
+		if _, ok := workOrderTransitions[filter.Status]; !ok {
+			return nil, fmt.Errorf("invalid work order status: %s", filter.Status)
+		}
+	}
+
+	workOrders, err := s.maintenanceRepo.GetWorkOrders(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPhotoURLs(workOrders), nil
+}
+
+// GetEngineerTasks lists an engineer's unresolved work orders and the open ones nobody has taken
+func (s *MaintenanceService) GetEngineerTasks(ctx context.Context, staffID int) (*models.EngineerTaskList, error) {
+	assigned, err := s.maintenanceRepo.GetWorkOrders(ctx, models.WorkOrderFilter{AssignedTo: &staffID, OpenOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	unassigned, err := s.maintenanceRepo.GetWorkOrders(ctx, models.WorkOrderFilter{Status: models.WorkOrderOpen, Unassigned: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EngineerTaskList{
+		Assigned:   s.withPhotoURLs(assigned),
+		Unassigned: s.withPhotoURLs(unassigned),
+	}, nil
+}
+
+// UpdateWorkOrder corrects the category, priority or description of a work order
+func (s *MaintenanceService) UpdateWorkOrder(ctx context.Context, workOrderID int, req *models.UpdateWorkOrderRequest) (*models.WorkOrder, error) {
+	wo, err := s.maintenanceRepo.GetWorkOrderByID(ctx, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if wo == nil {
+		return nil, errors.New("work order not found")
+	}
+
+	if req.Category != nil {
+		wo.Category = *req.Category
+	}
+	if req.Priority != nil {
+		wo.Priority = *req.Priority
+	}
+	if req.Description != nil {
+		wo.Description = *req.Description
+	}
+	if err := normalizeWorkOrder(wo); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.maintenanceRepo.UpdateWorkOrder(ctx, wo); err != nil {
+		return nil, err
+	}
+	return s.GetWorkOrder(ctx, workOrderID)
+}
+
+// AssignWorkOrder assigns an unresolved work order to an engineer or manager
+func (s *MaintenanceService) AssignWorkOrder(ctx context.Context, workOrderID int, req *models.AssignWorkOrderRequest) (*models.WorkOrder, error) {
+	wo, err := s.maintenanceRepo.GetWorkOrderByID(ctx, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if wo == nil {
+		return nil, errors.New("work order not found")
+	}
+	if wo.Status == models.WorkOrderResolved {
+		return nil, errors.New("resolved work orders cannot be reassigned")
+	}
+
+	if req.AssignedTo != nil {
+		role, err := s.maintenanceRepo.GetActiveStaffRole(ctx, *req.AssignedTo)
+		if err != nil {
+			return nil, err
+		}
+		if role != "ENGINEER" && role != "MANAGER" {
+			return nil, errors.New("work orders can only be assigned to active engineers or managers")
+		}
+	}
+
+	wo.AssignedTo = req.AssignedTo
+	if _, err := s.maintenanceRepo.UpdateWorkOrder(ctx, wo); err != nil {
+		return nil, err
+	}
+	return s.GetWorkOrder(ctx, workOrderID)
+}
+
+// UpdateWorkOrderStatus moves a work order to a new status. Engineers may only work on
+// their own or unassigned work orders, and starting an unassigned one takes it.
+// Resolving needs resolution notes and returns the room to Dirty once its last
+// unresolved work order is done.
+func (s *MaintenanceService) UpdateWorkOrderStatus(ctx context.Context, workOrderID, staffID int, role string, req *models.UpdateWorkOrderStatusRequest) (*models.WorkOrder, error) {
+	wo, err := s.maintenanceRepo.GetWorkOrderByID(ctx, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if wo == nil {
+		return nil, errors.New("work order not found")
+	}
+
+	if role != "MANAGER" && wo.AssignedTo != nil && *wo.AssignedTo != staffID {
+		return nil, errors.New("work order is assigned to another engineer")
+	}
+	if err := validateWorkOrderTransition(wo.Status, req.Status); err != nil {
+		return nil, err
+	}
+
+	previousStatus := wo.Status
+	wo.Status = req.Status
+	switch req.Status {
+	case models.WorkOrderResolved:
+		if req.ResolutionNotes == nil || strings.TrimSpace(*req.ResolutionNotes) == "" {
+			return nil, errors.New("resolution notes are required to resolve a work order")
+		}
+		notes := strings.TrimSpace(*req.ResolutionNotes)
+		wo.ResolutionNotes = &notes
+	case models.WorkOrderInProgress:
+		if wo.AssignedTo == nil {
+			wo.AssignedTo = &staffID
+		}
+	}
+
+	updated, err := s.maintenanceRepo.UpdateWorkOrderStatus(ctx, wo, previousStatus, &staffID)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("work order was changed by someone else, reload and try again")
+	}
+	if req.Status == models.WorkOrderResolved || previousStatus == models.WorkOrderResolved {
+		s.roomChanged(ctx, wo.RoomID)
+	}
+	return s.GetWorkOrder(ctx, workOrderID)
+}
+
+// AddWorkOrderPhoto attaches a photo to a work order. The photo is turned upright,
+// stripped of EXIF and stored as a JPEG.
+func (s *MaintenanceService) AddWorkOrderPhoto(ctx context.Context, workOrderID int, data []byte) (*models.WorkOrder, error) {
+	if s.blobs == nil {
+		return nil, errors.New("image storage is not configured")
+	}
+
+	wo, err := s.maintenanceRepo.GetWorkOrderByID(ctx, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if wo == nil {
+		return nil, errors.New("work order not found")
+	}
+	if len(wo.PhotoKeys) >= models.MaxWorkOrderPhotos {
+		return nil, fmt.Errorf("a work order can have at most %d photos", models.MaxWorkOrderPhotos)
+	}
+
+	img, err := utils.DecodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := utils.EncodeJPEG(utils.ResizeImage(img, maxWorkOrderPhotoSize))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	key := fmt.Sprintf("work-orders/%d/%x.jpg", workOrderID, sum[:8])
+	if err := s.blobs.Put(ctx, key, encoded); err != nil {
+		return nil, err
+	}
+	if _, err := s.maintenanceRepo.AddWorkOrderPhoto(ctx, workOrderID, key); err != nil {
+		if !contains(wo.PhotoKeys, key) {
+			_ = s.blobs.Delete(ctx, key)
+		}
+		return nil, err
+	}
+
+	return s.GetWorkOrder(ctx, workOrderID)
+}
+
+// GetRepairTimeReport reports the mean time to repair, overall and per room, of the
+// work orders resolved between two dates
+func (s *MaintenanceService) GetRepairTimeReport(ctx context.Context, startDate, endDate time.Time) (*models.RepairTimeReport, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+
+	times, err := s.maintenanceRepo.GetRepairTimes(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	mean, rooms := summarizeRepairTimes(times)
+	return &models.RepairTimeReport{
+		StartDate:         startDate.Format("2006-01-02"),
+		EndDate:           endDate.Format("2006-01-02"),
+		ResolvedOrders:    len(times),
+		MeanHoursToRepair: mean,
+		Rooms:             rooms,
+	}, nil
+}
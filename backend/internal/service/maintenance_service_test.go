@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWorkOrderTransition(t *testing.T) {
+	assert.NoError(t, validateWorkOrderTransition(models.WorkOrderOpen, models.WorkOrderInProgress))
+	assert.NoError(t, validateWorkOrderTransition(models.WorkOrderOnHold, models.WorkOrderResolved))
+	assert.NoError(t, validateWorkOrderTransition(models.WorkOrderResolved, models.WorkOrderOpen), "reopen")
+
+	assert.Error(t, validateWorkOrderTransition(models.WorkOrderResolved, models.WorkOrderInProgress))
+	assert.Error(t, validateWorkOrderTransition(models.WorkOrderOpen, models.WorkOrderOpen))
+	assert.Error(t, validateWorkOrderTransition(models.WorkOrderOpen, "Closed"))
+}
+
+func TestNormalizeWorkOrder(t *testing.T) {
+	wo := models.WorkOrder{Description: "  Leaking tap  "}
+	require.NoError(t, normalizeWorkOrder(&wo))
+	assert.Equal(t, "Leaking tap", wo.Description)
+	assert.Equal(t, "Other", wo.Category)
+	assert.Equal(t, "Medium", wo.Priority)
+
+	assert.Error(t, normalizeWorkOrder(&models.WorkOrder{Description: " "}))
+	assert.Error(t, normalizeWorkOrder(&models.WorkOrder{Description: "Broken", Category: "Garden"}))
+	assert.Error(t, normalizeWorkOrder(&models.WorkOrder{Description: "Broken", Priority: "Critical"}))
+}
+
+func TestSummarizeRepairTimes(t *testing.T) {
+	reported := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+	times := []models.RepairTime{
+		{RoomID: 1, RoomNumber: "101", ReportedAt: reported, ResolvedAt: reported.Add(2 * time.Hour)},
+		{RoomID: 1, RoomNumber: "101", ReportedAt: reported, ResolvedAt: reported.Add(4 * time.Hour)},
+		{RoomID: 2, RoomNumber: "102", ReportedAt: reported, ResolvedAt: reported.Add(24 * time.Hour)},
+	}
+
+	mean, rooms := summarizeRepairTimes(times)
+	assert.Equal(t, 10.0, mean)
+	require.Len(t, rooms, 2)
+
+	// The slowest room comes first
+	assert.Equal(t, "102", rooms[0].RoomNumber)
+	assert.Equal(t, 24.0, rooms[0].MeanHoursToRepair)
+	assert.Equal(t, "101", rooms[1].RoomNumber)
+	assert.Equal(t, 2, rooms[1].ResolvedOrders)
+	assert.Equal(t, 3.0, rooms[1].MeanHoursToRepair)
+	assert.Equal(t, 4.0, rooms[1].LongestHoursToRepair)
+
+	mean, rooms = summarizeRepairTimes(nil)
+	assert.Zero(t, mean)
+	assert.NotNil(t, rooms)
+	assert.Empty(t, rooms)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// resolveOccupancy splits a party into adults and children and works out the extra beds
+// the room type needs. numGuests includes the children listed in childAges.
+func resolveOccupancy(roomType *models.RoomType, numGuests int, childAges []int) (models.Occupancy, error) {
+	for _, age := range childAges {
+		if age < 0 || age > models.MaxChildAge {
+			return models.Occupancy{}, fmt.Errorf("child age must be between 0 and %d", models.MaxChildAge)
+		}
+	}
+
+	if len(childAges) >= numGuests {
+		return models.Occupancy{}, errors.New("at least one adult is required")
+	}
+
+	extraBeds := 0
+	if numGuests > roomType.MaxOccupancy {
+		extraBeds = numGuests - roomType.MaxOccupancy
+	}
+	if extraBeds > roomType.MaxExtraBeds {
+		return models.Occupancy{}, fmt.Errorf("%s allows at most %d guests", roomType.Name, roomType.MaxOccupancy+roomType.MaxExtraBeds)
+	}
+
+	occupancy := models.Occupancy{
+		Adults:    numGuests - len(childAges),
+		ExtraBeds: extraBeds,
+	}
+	if len(childAges) > 0 {
+		occupancy.ChildAges = childAges
+	}
+
+	return occupancy, nil
+}
+
+// childAgesFromGuests collects the ages of the Child guests of a booking detail
+func childAgesFromGuests(guests []models.CreateGuestRequest) ([]int, error) {
+	var ages []int
+	for _, guest := range guests {
+		if guest.Type != "Child" {
+			continue
+		}
+		if guest.Age == nil {
+			return nil, fmt.Errorf("age is required for child guest %s %s", guest.FirstName, guest.LastName)
+		}
+		ages = append(ages, *guest.Age)
+	}
+	return ages, nil
+}
+
+// applySurcharges adds the nightly occupancy surcharges to the room rates and returns the new total
+func applySurcharges(prices []models.NightlyPrice, surcharges map[string]float64) float64 {
+	var total float64
+	for i := range prices {
+		prices[i].Surcharge = surcharges[prices[i].Date]
+		prices[i].Price = prices[i].RoomRate + prices[i].Surcharge
+		total += prices[i].Price
+	}
+	return total
+}
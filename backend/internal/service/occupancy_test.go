@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveOccupancy(t *testing.T) {
+	deluxe := &models.RoomType{Name: "Deluxe Room", MaxOccupancy: 3, BaseOccupancy: 2, MaxExtraBeds: 1}
+
+	occupancy, err := resolveOccupancy(deluxe, 3, []int{4})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Occupancy{Adults: 2, ChildAges: []int{4}}, occupancy)
+
+	// The fourth guest needs the extra bed
+	occupancy, err = resolveOccupancy(deluxe, 4, []int{8, 13})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Occupancy{Adults: 2, ChildAges: []int{8, 13}, ExtraBeds: 1}, occupancy)
+
+	_, err = resolveOccupancy(deluxe, 5, nil)
+	assert.EqualError(t, err, "Deluxe Room allows at most 4 guests")
+
+	_, err = resolveOccupancy(deluxe, 2, []int{5, 7})
+	assert.EqualError(t, err, "at least one adult is required")
+
+	_, err = resolveOccupancy(deluxe, 2, []int{18})
+	assert.EqualError(t, err, "child age must be between 0 and 17")
+}
+
+func TestChildAgesFromGuests(t *testing.T) {
+	age := 7
+	guests := []models.CreateGuestRequest{
+		{FirstName: "Somchai", LastName: "Jaidee", Type: "Adult", IsPrimary: true},
+		{FirstName: "Ploy", LastName: "Jaidee", Type: "Child", Age: &age},
+	}
+
+	ages, err := childAgesFromGuests(guests)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{7}, ages)
+
+	guests[1].Age = nil
+	_, err = childAgesFromGuests(guests)
+	assert.EqualError(t, err, "age is required for child guest Ploy Jaidee")
+}
+
+func TestApplySurcharges(t *testing.T) {
+	prices := []models.NightlyPrice{
+		{Date: "2025-07-04", RoomRate: 2500},
+		{Date: "2025-07-05", RoomRate: 3000},
+	}
+
+	total := applySurcharges(prices, map[string]float64{"2025-07-04": 300, "2025-07-05": 450})
+
+	assert.Equal(t, 6250.0, total)
+	assert.Equal(t, 2800.0, prices[0].Price)
+	assert.Equal(t, 450.0, prices[1].Surcharge)
+	assert.Equal(t, 3450.0, prices[1].Price)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// outOfOrderConflicts lists the dates on which taking one more room off the allotment
+// would leave fewer rooms than are already booked or held
+func outOfOrderConflicts(inventory []models.RoomInventory) []models.InventoryValidationError {
+	var conflicts []models.InventoryValidationError
+	for _, inv := range inventory {
+		capacity := inv.Allotment - 1
+		if used := inv.BookedCount + inv.TentativeCount; used > capacity {
+			conflicts = append(conflicts, models.InventoryValidationError{
+				Date: inv.Date.Format("2006-01-02"),
+				Message: fmt.Sprintf("Cannot take a room out of order: %d of %d rooms are booked (booked: %d, tentative: %d)",
+					used, inv.Allotment, inv.BookedCount, inv.TentativeCount),
+			})
+		}
+	}
+	return conflicts
+}
+
+// GetOutOfOrderPeriods lists out-of-order periods, optionally of one room
+func (s *InventoryService) GetOutOfOrderPeriods(ctx context.Context, roomID *int, includeEnded bool) ([]models.OutOfOrderPeriod, error) {
+	return s.inventoryRepo.GetOutOfOrderPeriods(ctx, roomID, includeEnded)
+}
+
+// ScheduleOutOfOrder takes a room out of order for a range of nights. When bookings
+// leave no unsold room on some of the nights nothing is changed and the conflicting
+// dates are returned.
+func (s *InventoryService) ScheduleOutOfOrder(ctx context.Context, createdBy *int, req *models.ScheduleOutOfOrderRequest) (*models.OutOfOrderPeriod, []models.InventoryValidationError, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid start date format: %w", err)
+	}
+
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	if start.Before(time.Now().Truncate(24 * time.Hour)) {
+		return nil, nil, errors.New("start date cannot be in the past")
+	}
+
+	if end.Before(start) {
+		return nil, nil, errors.New("end date must be after start date")
+	}
+
+	if end.Sub(start).Hours() > 365*24 {
+		return nil, nil, errors.New("date range cannot exceed 1 year")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, nil, errors.New("reason is required")
+	}
+
+	room, err := s.roomRepo.GetRoomByID(ctx, req.RoomID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if room == nil || !room.IsActive {
+		return nil, nil, errors.New("room not found")
+	}
+
+	inventory, err := s.inventoryRepo.GetInventoryForDates(ctx, room.RoomTypeID, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	if conflicts := outOfOrderConflicts(inventory); len(conflicts) > 0 {
+		return nil, conflicts, nil
+	}
+
+	period, err := s.inventoryRepo.CreateOutOfOrder(ctx, &models.OutOfOrderPeriod{
+		RoomID:      req.RoomID,
+		StartDate:   start,
+		EndDate:     end,
+		Reason:      reason,
+		WorkOrderID: req.WorkOrderID,
+		CreatedBy:   createdBy,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if period == nil {
+		return nil, nil, errors.New("room not found")
+	}
+
+	s.inventoryChanged()
+	if period.Status == models.OutOfOrderActive {
+		s.roomChanged(ctx, period.RoomID)
+	}
+	return period, nil, nil
+}
+
+// UpdateOutOfOrder moves the last night of a scheduled or active period. Nights added
+// are checked against bookings like a new period; nights already passed cannot be
+// removed.
+func (s *InventoryService) UpdateOutOfOrder(ctx context.Context, outOfOrderID int, req *models.UpdateOutOfOrderRequest) (*models.OutOfOrderPeriod, []models.InventoryValidationError, error) {
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	period, err := s.inventoryRepo.GetOutOfOrderByID(ctx, outOfOrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if period == nil {
+		return nil, nil, errors.New("out-of-order period not found")
+	}
+	if period.Status != models.OutOfOrderScheduled && period.Status != models.OutOfOrderActive {
+		return nil, nil, fmt.Errorf("out-of-order period is already %s", strings.ToLower(period.Status))
+	}
+
+	if end.Before(period.StartDate) {
+		return nil, nil, errors.New("end date must be after start date")
+	}
+	if end.Before(time.Now().Truncate(24 * time.Hour)) {
+		return nil, nil, errors.New("end date cannot be in the past")
+	}
+	if end.Sub(period.StartDate).Hours() > 365*24 {
+		return nil, nil, errors.New("date range cannot exceed 1 year")
+	}
+
+	if end.After(period.EndDate) {
+		inventory, err := s.inventoryRepo.GetInventoryForDates(ctx, period.RoomTypeID, period.EndDate.AddDate(0, 0, 1), end)
+		if err != nil {
+			return nil, nil, err
+		}
+		if conflicts := outOfOrderConflicts(inventory); len(conflicts) > 0 {
+			return nil, conflicts, nil
+		}
+	}
+
+	updated, err := s.inventoryRepo.UpdateOutOfOrderEndDate(ctx, outOfOrderID, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !updated {
+		return nil, nil, errors.New("out-of-order period has already ended")
+	}
+
+	s.inventoryChanged()
+	period, err = s.inventoryRepo.GetOutOfOrderByID(ctx, outOfOrderID)
+	return period, nil, err
+}
+
+// EndOutOfOrder cancels a scheduled period, or returns the room of an active period
+// to service today; the remaining nights go back on sale
+func (s *InventoryService) EndOutOfOrder(ctx context.Context, outOfOrderID int, endedBy *int) (*models.OutOfOrderPeriod, error) {
+	ended, err := s.inventoryRepo.EndOutOfOrder(ctx, outOfOrderID, time.Now().Truncate(24*time.Hour), endedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		period, err := s.inventoryRepo.GetOutOfOrderByID(ctx, outOfOrderID)
+		if err != nil {
+			return nil, err
+		}
+		if period == nil {
+			return nil, errors.New("out-of-order period not found")
+		}
+		return nil, errors.New("out-of-order period has already ended")
+	}
+
+	s.inventoryChanged()
+	period, err := s.inventoryRepo.GetOutOfOrderByID(ctx, outOfOrderID)
+	if err == nil && period != nil {
+		s.roomChanged(ctx, period.RoomID)
+	}
+	return period, err
+}
+
+// roomChanged tells the room change listener a room went out of order or came back
+func (s *InventoryService) roomChanged(ctx context.Context, roomID int) {
+	if s.roomEvents != nil {
+		s.roomEvents.RoomsChanged(ctx, models.RoomEventStatus, roomID)
+	}
+}
+
+// inventoryChanged tells the listener that allotments moved
+func (s *InventoryService) inventoryChanged() {
+	if s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+}
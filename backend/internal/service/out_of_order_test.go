@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutOfOrderConflicts(t *testing.T) {
+	day := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	inventory := []models.RoomInventory{
+		{Date: day, Allotment: 10, BookedCount: 8, TentativeCount: 1},
+		{Date: day.AddDate(0, 0, 1), Allotment: 10, BookedCount: 9, TentativeCount: 1},
+		{Date: day.AddDate(0, 0, 2), Allotment: 0},
+	}
+
+	conflicts := outOfOrderConflicts(inventory)
+
+	if assert.Len(t, conflicts, 2) {
+		assert.Equal(t, "2026-11-02", conflicts[0].Date)
+		assert.Contains(t, conflicts[0].Message, "10 of 10 rooms are booked")
+		assert.Equal(t, "2026-11-03", conflicts[1].Date)
+	}
+}
+
+func TestOutOfOrderConflictsNone(t *testing.T) {
+	day := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	inventory := []models.RoomInventory{
+		{Date: day, Allotment: 5, BookedCount: 2, TentativeCount: 2},
+	}
+
+	assert.Empty(t, outOfOrderConflicts(inventory))
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// Largest overbooking limits a room type may be given
+const (
+	maxOverbookingRooms   = 50
+	maxOverbookingPercent = 50
+)
+
+// overbookingLimit checks an overbooking limit and returns the limit type to store, nil
+// when overbooking stops
+func overbookingLimit(limitType string, limitValue float64) (*string, error) {
+	switch limitType {
+	case "":
+		if limitValue != 0 {
+			return nil, errors.New("limit_type is required with a limit value")
+		}
+		return nil, nil
+	case models.OverbookingAbsolute:
+		if limitValue != math.Trunc(limitValue) {
+			return nil, errors.New("an absolute limit is a whole number of rooms")
+		}
+		if limitValue > maxOverbookingRooms {
+			return nil, fmt.Errorf("an absolute limit cannot exceed %d rooms", maxOverbookingRooms)
+		}
+	case models.OverbookingPercentage:
+		if limitValue > maxOverbookingPercent {
+			return nil, fmt.Errorf("a percentage limit cannot exceed %d%%", maxOverbookingPercent)
+		}
+	default:
+		return nil, fmt.Errorf("invalid limit type %q", limitType)
+	}
+	return &limitType, nil
+}
+
+// settleOverbookingPosition works out the capacity, the rooms sold beyond the allotment
+// and the rooms left to sell of a position
+func settleOverbookingPosition(p *models.OverbookingPosition) {
+	sold := p.BookedCount + p.TentativeCount
+	p.Capacity = p.Allotment + p.OverbookingRooms
+	p.Oversold = 0
+	if sold > p.Allotment {
+		p.Oversold = sold - p.Allotment
+	}
+	p.Available = 0
+	if sold < p.Capacity {
+		p.Available = p.Capacity - sold
+	}
+}
+
+// GetOverbookingPositions returns how far each room type (or one) is sold beyond its
+// allotment on every date of a range
+func (s *InventoryService) GetOverbookingPositions(ctx context.Context, roomTypeID *int, startDate, endDate string) ([]models.OverbookingPosition, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date format: %w", err)
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	if end.Before(start) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	if end.Sub(start).Hours() > 365*24 {
+		return nil, errors.New("date range cannot exceed 1 year")
+	}
+
+	positions, err := s.inventoryRepo.GetOverbookingPositions(ctx, roomTypeID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if positions == nil {
+		positions = []models.OverbookingPosition{}
+	}
+	for i := range positions {
+		settleOverbookingPosition(&positions[i])
+	}
+	return positions, nil
+}
+
+// SetOverbookingLimit sets the overbooking limit of a room type for a date range. When
+// rooms are already sold beyond the new limit on some dates nothing is changed and the
+// conflicting dates are returned.
+func (s *InventoryService) SetOverbookingLimit(ctx context.Context, req *models.SetOverbookingLimitRequest) ([]models.InventoryValidationError, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date format: %w", err)
+	}
+
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	if end.Before(start) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	if end.Sub(start).Hours() > 365*24 {
+		return nil, errors.New("date range cannot exceed 1 year")
+	}
+
+	limitType, err := overbookingLimit(req.LimitType, req.LimitValue)
+	if err != nil {
+		return nil, err
+	}
+
+	roomType, err := s.roomRepo.GetRoomTypeByID(ctx, req.RoomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if roomType == nil {
+		return nil, errors.New("room type not found")
+	}
+
+	validationErrors, err := s.inventoryRepo.SetOverbookingLimit(ctx, req.RoomTypeID, start, end, limitType, req.LimitValue)
+	if err != nil || len(validationErrors) > 0 {
+		return validationErrors, err
+	}
+
+	s.inventoryChanged()
+	return nil, nil
+}
+
+// GetPartnerHotels lists the partner hotels guests can be walked to, and the inactive
+// ones when includeInactive is set
+func (s *InventoryService) GetPartnerHotels(ctx context.Context, includeInactive bool) ([]models.PartnerHotel, error) {
+	hotels, err := s.inventoryRepo.GetPartnerHotels(ctx, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+	if hotels == nil {
+		hotels = []models.PartnerHotel{}
+	}
+	return hotels, nil
+}
+
+// CreatePartnerHotel adds a partner hotel
+func (s *InventoryService) CreatePartnerHotel(ctx context.Context, req *models.PartnerHotelRequest) (*models.PartnerHotel, error) {
+	hotel, err := partnerHotel(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.inventoryRepo.CreatePartnerHotel(ctx, hotel)
+}
+
+// UpdatePartnerHotel updates a partner hotel
+func (s *InventoryService) UpdatePartnerHotel(ctx context.Context, partnerHotelID int, req *models.PartnerHotelRequest) (*models.PartnerHotel, error) {
+	hotel, err := partnerHotel(req)
+	if err != nil {
+		return nil, err
+	}
+	hotel.PartnerHotelID = partnerHotelID
+
+	updated, err := s.inventoryRepo.UpdatePartnerHotel(ctx, hotel)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, errors.New("partner hotel not found")
+	}
+	return updated, nil
+}
+
+// partnerHotel builds the partner hotel described by req; it is active unless
+// req says otherwise
+func partnerHotel(req *models.PartnerHotelRequest) (*models.PartnerHotel, error) {
+	hotel := &models.PartnerHotel{
+		Name:        strings.TrimSpace(req.Name),
+		Address:     req.Address,
+		Phone:       req.Phone,
+		ContactName: req.ContactName,
+		Notes:       req.Notes,
+		IsActive:    true,
+	}
+	if hotel.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if req.IsActive != nil {
+		hotel.IsActive = *req.IsActive
+	}
+	return hotel, nil
+}
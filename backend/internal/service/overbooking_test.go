@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverbookingLimit(t *testing.T) {
+	limitType, err := overbookingLimit(models.OverbookingAbsolute, 2)
+	if assert.NoError(t, err) && assert.NotNil(t, limitType) {
+		assert.Equal(t, models.OverbookingAbsolute, *limitType)
+	}
+
+	limitType, err = overbookingLimit(models.OverbookingPercentage, 12.5)
+	assert.NoError(t, err)
+	assert.NotNil(t, limitType)
+
+	limitType, err = overbookingLimit("", 0)
+	assert.NoError(t, err)
+	assert.Nil(t, limitType, "an empty type stops overbooking")
+
+	_, err = overbookingLimit("", 3)
+	assert.Error(t, err)
+	_, err = overbookingLimit(models.OverbookingAbsolute, 1.5)
+	assert.EqualError(t, err, "an absolute limit is a whole number of rooms")
+	_, err = overbookingLimit(models.OverbookingAbsolute, 51)
+	assert.Error(t, err)
+	_, err = overbookingLimit(models.OverbookingPercentage, 60)
+	assert.EqualError(t, err, "a percentage limit cannot exceed 50%")
+}
+
+func TestSettleOverbookingPosition(t *testing.T) {
+	tests := []struct {
+		name      string
+		position  models.OverbookingPosition
+		capacity  int
+		oversold  int
+		available int
+	}{
+		{"within the allotment", models.OverbookingPosition{Allotment: 10, OverbookingRooms: 2, BookedCount: 7, TentativeCount: 1}, 12, 0, 4},
+		{"oversold", models.OverbookingPosition{Allotment: 10, OverbookingRooms: 2, BookedCount: 10, TentativeCount: 1}, 12, 1, 1},
+		{"at the limit", models.OverbookingPosition{Allotment: 10, OverbookingRooms: 2, BookedCount: 12}, 12, 2, 0},
+		{"limit lowered below sales", models.OverbookingPosition{Allotment: 9, BookedCount: 11}, 9, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.position
+			settleOverbookingPosition(&p)
+			assert.Equal(t, tt.capacity, p.Capacity)
+			assert.Equal(t, tt.oversold, p.Oversold)
+			assert.Equal(t, tt.available, p.Available)
+		})
+	}
+}
+
+func TestPartnerHotel(t *testing.T) {
+	hotel, err := partnerHotel(&models.PartnerHotelRequest{Name: "  Riverside Inn "})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Riverside Inn", hotel.Name)
+		assert.True(t, hotel.IsActive)
+	}
+
+	inactive := false
+	hotel, err = partnerHotel(&models.PartnerHotelRequest{Name: "Riverside Inn", IsActive: &inactive})
+	if assert.NoError(t, err) {
+		assert.False(t, hotel.IsActive)
+	}
+
+	_, err = partnerHotel(&models.PartnerHotelRequest{Name: "  "})
+	assert.EqualError(t, err, "name is required")
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// defaultPricingRuleLookahead is how many days ahead a rule is evaluated when not set
+const defaultPricingRuleLookahead = 90
+
+// applyPricingRuleRequest copies a request onto a rule, keeping only the
+// parameters of the rule type
+func applyPricingRuleRequest(rule *models.PricingRule, req *models.CreatePricingRuleRequest) {
+	rule.Name = req.Name
+	rule.RuleType = req.RuleType
+	rule.RoomTypeID = req.RoomTypeID
+	rule.TargetRateTierID = req.TargetRateTierID
+	rule.FloorPrice = req.FloorPrice
+	rule.CeilingPrice = req.CeilingPrice
+
+	rule.LookaheadDays = req.LookaheadDays
+	if rule.LookaheadDays == 0 {
+		rule.LookaheadDays = defaultPricingRuleLookahead
+	}
+
+	rule.OccupancyThreshold = nil
+	rule.PickupDays = nil
+	rule.PickupThreshold = nil
+	if req.RuleType == models.PricingRuleOccupancy {
+		rule.OccupancyThreshold = req.OccupancyThreshold
+	} else {
+		rule.PickupDays = req.PickupDays
+		rule.PickupThreshold = req.PickupThreshold
+	}
+}
+
+// validatePricingRule checks the thresholds and price band of a rule
+func validatePricingRule(rule *models.PricingRule) error {
+	switch rule.RuleType {
+	case models.PricingRuleOccupancy:
+		if rule.OccupancyThreshold == nil {
+			return errors.New("occupancy rules require an occupancy threshold")
+		}
+		if *rule.OccupancyThreshold <= 0 || *rule.OccupancyThreshold > 100 {
+			return errors.New("occupancy threshold must be between 0 and 100")
+		}
+	case models.PricingRulePickup:
+		if rule.PickupDays == nil || rule.PickupThreshold == nil {
+			return errors.New("pickup rules require pickup days and a pickup threshold")
+		}
+		if *rule.PickupDays < 1 {
+			return errors.New("pickup days must be at least 1")
+		}
+		if *rule.PickupThreshold < 1 {
+			return errors.New("pickup threshold must be at least 1")
+		}
+	default:
+		return errors.New("rule type must be 'Occupancy' or 'Pickup'")
+	}
+
+	if rule.LookaheadDays < 1 || rule.LookaheadDays > 365 {
+		return errors.New("lookahead days must be between 1 and 365")
+	}
+	if rule.FloorPrice != nil && *rule.FloorPrice < 0 {
+		return errors.New("floor price cannot be negative")
+	}
+	if rule.CeilingPrice != nil && *rule.CeilingPrice < 0 {
+		return errors.New("ceiling price cannot be negative")
+	}
+	if rule.FloorPrice != nil && rule.CeilingPrice != nil && *rule.CeilingPrice < *rule.FloorPrice {
+		return errors.New("ceiling price cannot be less than floor price")
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPricingRuleRequest(t *testing.T) {
+	threshold := 85.0
+	rule := &models.PricingRule{PickupDays: intPtr(7), PickupThreshold: intPtr(10)}
+
+	applyPricingRuleRequest(rule, &models.CreatePricingRuleRequest{
+		Name:               "Occupancy 85%",
+		RuleType:           models.PricingRuleOccupancy,
+		OccupancyThreshold: &threshold,
+		PickupDays:         intPtr(3),
+		TargetRateTierID:   3,
+	})
+
+	assert.Equal(t, 90, rule.LookaheadDays)
+	assert.Equal(t, 85.0, *rule.OccupancyThreshold)
+	assert.Nil(t, rule.PickupDays)
+	assert.Nil(t, rule.PickupThreshold)
+	assert.NoError(t, validatePricingRule(rule))
+}
+
+func TestValidatePricingRule(t *testing.T) {
+	floor, ceiling := 3000.0, 2500.0
+	rule := &models.PricingRule{
+		RuleType:         models.PricingRulePickup,
+		PickupDays:       intPtr(7),
+		TargetRateTierID: 3,
+		LookaheadDays:    60,
+	}
+	assert.EqualError(t, validatePricingRule(rule), "pickup rules require pickup days and a pickup threshold")
+
+	rule.PickupThreshold = intPtr(10)
+	assert.NoError(t, validatePricingRule(rule))
+
+	rule.FloorPrice = &floor
+	rule.CeilingPrice = &ceiling
+	assert.EqualError(t, validatePricingRule(rule), "ceiling price cannot be less than floor price")
+
+	over := 120.0
+	rule = &models.PricingRule{RuleType: models.PricingRuleOccupancy, OccupancyThreshold: &over, LookaheadDays: 90}
+	assert.EqualError(t, validatePricingRule(rule), "occupancy threshold must be between 0 and 100")
+}
@@ -13,6 +13,7 @@ import (
 type PricingService struct {
 	pricingRepo *repository.PricingRepository
 	cache       *cache.RedisCache
+	onChange    func()
 }
 
 func NewPricingService(pricingRepo *repository.PricingRepository, redisCache *cache.RedisCache) *PricingService {
@@ -22,6 +23,19 @@ func NewPricingService(pricingRepo *repository.PricingRepository, redisCache *ca
 	}
 }
 
+// OnChange registers a callback run after nightly rates, the pricing calendar, rate
+// plans or restrictions change
+func (s *PricingService) OnChange(fn func()) {
+	s.onChange = fn
+}
+
+// notifyChange runs the change callback, if any
+func (s *PricingService) notifyChange() {
+	if s.onChange != nil {
+		s.onChange()
+	}
+}
+
 // ============================================================================
 // Rate Tier Methods
 // ============================================================================
@@ -156,7 +170,11 @@ func (s *PricingService) UpdatePricingCalendar(ctx context.Context, req *models.
 		return fmt.Errorf("invalid rate tier ID: %w", err)
 	}
 
-	return s.pricingRepo.UpdatePricingCalendar(ctx, req)
+	if err := s.pricingRepo.UpdatePricingCalendar(ctx, req); err != nil {
+		return err
+	}
+	s.notifyChange()
+	return nil
 }
 
 // ============================================================================
@@ -180,7 +198,15 @@ func (s *PricingService) UpdateRatePricing(ctx context.Context, req *models.Upda
 		return fmt.Errorf("price cannot be negative")
 	}
 
-	return s.pricingRepo.UpdateRatePricing(ctx, req)
+	if err := s.ensureNotDerived(ctx, req.RatePlanID); err != nil {
+		return err
+	}
+
+	if err := s.pricingRepo.UpdateRatePricing(ctx, req); err != nil {
+		return err
+	}
+	s.notifyChange()
+	return nil
 }
 
 // BulkUpdateRatePricing updates multiple rate pricing entries
@@ -197,7 +223,15 @@ func (s *PricingService) BulkUpdateRatePricing(ctx context.Context, req *models.
 		}
 	}
 
-	return s.pricingRepo.BulkUpdateRatePricing(ctx, req)
+	if err := s.ensureNotDerived(ctx, req.RatePlanID); err != nil {
+		return err
+	}
+
+	if err := s.pricingRepo.BulkUpdateRatePricing(ctx, req); err != nil {
+		return err
+	}
+	s.notifyChange()
+	return nil
 }
 
 // GetAllRatePlans retrieves all rate plans
@@ -205,6 +239,350 @@ func (s *PricingService) GetAllRatePlans(ctx context.Context) ([]models.RatePlan
 	return s.pricingRepo.GetAllRatePlans(ctx)
 }
 
+// GetRatePlanByID retrieves a rate plan by ID
+func (s *PricingService) GetRatePlanByID(ctx context.Context, id int) (*models.RatePlan, error) {
+	plan, err := s.pricingRepo.GetRatePlanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("rate plan not found")
+	}
+
+	return plan, nil
+}
+
+// CreateRatePlan creates a rate plan with optional eligibility rules and derived pricing
+func (s *PricingService) CreateRatePlan(ctx context.Context, req *models.CreateRatePlanRequest) (*models.RatePlan, error) {
+	plan := &models.RatePlan{
+		Name:                  req.Name,
+		Description:           req.Description,
+		PolicyID:              req.PolicyID,
+		IsActive:              true,
+		IsDayUse:              req.IsDayUse,
+		BillingCycle:          req.BillingCycle,
+		HousekeepingFrequency: req.HousekeepingFrequency,
+	}
+	if plan.BillingCycle == "" {
+		plan.BillingCycle = models.BillingCyclePerStay
+	}
+	if plan.HousekeepingFrequency == 0 {
+		plan.HousekeepingFrequency = 1
+	}
+
+	if req.Rules != nil {
+		if err := applyRatePlanRules(plan, req.Rules); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validateRatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	created, err := s.pricingRepo.CreateRatePlan(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyChange()
+	return created, nil
+}
+
+// UpdateRatePlan updates a rate plan; provided rules replace the existing ones
+func (s *PricingService) UpdateRatePlan(ctx context.Context, id int, req *models.UpdateRatePlanRequest) (*models.RatePlan, error) {
+	plan, err := s.GetRatePlanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		plan.Name = *req.Name
+	}
+	if req.Description != nil {
+		plan.Description = req.Description
+	}
+	if req.PolicyID != nil {
+		plan.PolicyID = *req.PolicyID
+	}
+	if req.IsActive != nil {
+		plan.IsActive = *req.IsActive
+	}
+	if req.BillingCycle != nil {
+		plan.BillingCycle = *req.BillingCycle
+	}
+	if req.HousekeepingFrequency != nil {
+		plan.HousekeepingFrequency = *req.HousekeepingFrequency
+	}
+	if req.Rules != nil {
+		if err := applyRatePlanRules(plan, req.Rules); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validateRatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.pricingRepo.UpdateRatePlan(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyChange()
+	return updated, nil
+}
+
+// validateRatePlan validates the rules and that derived pricing is only one level deep
+func (s *PricingService) validateRatePlan(ctx context.Context, plan *models.RatePlan) error {
+	if err := validateRatePlanRules(plan); err != nil {
+		return err
+	}
+
+	if plan.BaseRatePlanID == nil {
+		return nil
+	}
+
+	base, err := s.pricingRepo.GetRatePlanByID(ctx, *plan.BaseRatePlanID)
+	if err != nil {
+		return err
+	}
+	if base == nil {
+		return fmt.Errorf("base rate plan not found")
+	}
+	if base.BaseRatePlanID != nil {
+		return fmt.Errorf("base rate plan %s is itself derived", base.Name)
+	}
+
+	if plan.RatePlanID != 0 {
+		derived, err := s.pricingRepo.CountDerivedRatePlans(ctx, plan.RatePlanID)
+		if err != nil {
+			return err
+		}
+		if derived > 0 {
+			return fmt.Errorf("rate plan is the base of %d other plans and cannot be derived", derived)
+		}
+	}
+
+	return nil
+}
+
+// ensureNotDerived rejects manual price edits of derived plans, which are overwritten from the base plan
+func (s *PricingService) ensureNotDerived(ctx context.Context, ratePlanID int) error {
+	plan, err := s.pricingRepo.GetRatePlanByID(ctx, ratePlanID)
+	if err != nil {
+		return err
+	}
+	if plan != nil && plan.BaseRatePlanID != nil {
+		return fmt.Errorf("prices of derived rate plan %s follow its base plan and cannot be edited", plan.Name)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Rate Restriction Methods
+// ============================================================================
+
+// GetRateRestrictions retrieves stay restrictions for a date range
+func (s *PricingService) GetRateRestrictions(ctx context.Context, startDate, endDate string, roomTypeID *int) ([]models.RateRestriction, error) {
+	start, end, err := parseRestrictionRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pricingRepo.GetRateRestrictions(ctx, start, end, roomTypeID)
+}
+
+// BulkUpdateRestrictions sets or clears stay restrictions for a date range
+func (s *PricingService) BulkUpdateRestrictions(ctx context.Context, req *models.BulkUpdateRestrictionsRequest) ([]models.RestrictionValidationError, error) {
+	start, end, err := parseRestrictionRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dow := range req.DaysOfWeek {
+		if dow < 0 || dow > 6 {
+			return nil, fmt.Errorf("days of week must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+
+	if req.Clear {
+		if _, err := s.pricingRepo.ClearRestrictions(ctx, req, start, end); err != nil {
+			return nil, err
+		}
+		s.notifyChange()
+		return nil, nil
+	}
+
+	if req.MinLOS == nil && req.MaxLOS == nil && req.ClosedToArrival == nil &&
+		req.ClosedToDeparture == nil && req.StopSell == nil && req.Notes == nil {
+		return nil, fmt.Errorf("at least one restriction must be provided")
+	}
+
+	if req.MinLOS != nil && *req.MinLOS < 0 {
+		return nil, fmt.Errorf("minimum stay cannot be negative")
+	}
+	if req.MaxLOS != nil && *req.MaxLOS < 0 {
+		return nil, fmt.Errorf("maximum stay cannot be negative")
+	}
+	if req.MinLOS != nil && req.MaxLOS != nil && *req.MinLOS > 0 && *req.MaxLOS > 0 && *req.MinLOS > *req.MaxLOS {
+		return nil, fmt.Errorf("minimum stay cannot exceed maximum stay")
+	}
+
+	validationErrors, err := s.pricingRepo.BulkUpdateRestrictions(ctx, req, start, end)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyChange()
+	return validationErrors, nil
+}
+
+// parseRestrictionRange parses and validates a restriction date range
+func parseRestrictionRange(startDate, endDate string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date format: %w", err)
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date must be after start date")
+	}
+
+	// Limit to 1 year range
+	if end.Sub(start).Hours() > 365*24 {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range cannot exceed 1 year")
+	}
+
+	return start, end, nil
+}
+
+// ============================================================================
+// Occupancy Surcharge Methods
+// ============================================================================
+
+// GetOccupancySurcharges retrieves occupancy surcharges, optionally for one rate tier
+func (s *PricingService) GetOccupancySurcharges(ctx context.Context, rateTierID *int) ([]models.OccupancySurcharge, error) {
+	return s.pricingRepo.GetOccupancySurcharges(ctx, rateTierID)
+}
+
+// UpsertOccupancySurcharge creates or updates an occupancy surcharge
+func (s *PricingService) UpsertOccupancySurcharge(ctx context.Context, req *models.UpsertOccupancySurchargeRequest) (*models.OccupancySurcharge, error) {
+	if req.ChargeType == models.ChargeTypeExtraChild {
+		if req.MaxAge > models.MaxChildAge {
+			return nil, fmt.Errorf("child age band cannot go above %d", models.MaxChildAge)
+		}
+		if req.MaxAge < req.MinAge {
+			return nil, fmt.Errorf("max age cannot be less than min age")
+		}
+	} else {
+		// Age bands only apply to children
+		req.MinAge = 0
+		req.MaxAge = models.MaxChildAge
+	}
+
+	return s.pricingRepo.UpsertOccupancySurcharge(ctx, req)
+}
+
+// DeleteOccupancySurcharge removes an occupancy surcharge
+func (s *PricingService) DeleteOccupancySurcharge(ctx context.Context, id int) error {
+	deleted, err := s.pricingRepo.DeleteOccupancySurcharge(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("occupancy surcharge not found")
+	}
+	return nil
+}
+
+// UpdateRoomTypeOccupancy updates the base occupancy and extra bed settings of a room type
+func (s *PricingService) UpdateRoomTypeOccupancy(ctx context.Context, roomTypeID int, req *models.UpdateRoomTypeOccupancyRequest) error {
+	updated, err := s.pricingRepo.UpdateRoomTypeOccupancy(ctx, roomTypeID, req)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("room type not found")
+	}
+
+	// Room type details are cached by the room service
+	if s.cache != nil {
+		_ = s.cache.Delete(cache.RoomTypesKey())
+		_ = s.cache.Delete(cache.RoomTypeKey(roomTypeID))
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Dynamic Pricing Rule Methods
+// ============================================================================
+
+// GetAllPricingRules retrieves all dynamic pricing rules
+func (s *PricingService) GetAllPricingRules(ctx context.Context) ([]models.PricingRule, error) {
+	return s.pricingRepo.GetAllPricingRules(ctx)
+}
+
+// CreatePricingRule creates a dynamic pricing rule
+func (s *PricingService) CreatePricingRule(ctx context.Context, req *models.CreatePricingRuleRequest) (*models.PricingRule, error) {
+	rule := &models.PricingRule{IsActive: true}
+	applyPricingRuleRequest(rule, req)
+
+	if err := validatePricingRule(rule); err != nil {
+		return nil, err
+	}
+
+	return s.pricingRepo.CreatePricingRule(ctx, rule)
+}
+
+// UpdatePricingRule replaces the settings of a dynamic pricing rule
+func (s *PricingService) UpdatePricingRule(ctx context.Context, id int, req *models.UpdatePricingRuleRequest) (*models.PricingRule, error) {
+	rule, err := s.pricingRepo.GetPricingRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("pricing rule not found")
+	}
+
+	applyPricingRuleRequest(rule, &req.CreatePricingRuleRequest)
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := validatePricingRule(rule); err != nil {
+		return nil, err
+	}
+
+	return s.pricingRepo.UpdatePricingRule(ctx, rule)
+}
+
+// DeletePricingRule removes a dynamic pricing rule; its change log is kept
+func (s *PricingService) DeletePricingRule(ctx context.Context, id int) error {
+	deleted, err := s.pricingRepo.DeletePricingRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("pricing rule not found")
+	}
+	return nil
+}
+
+// GetPricingRuleChanges retrieves the tier changes made by the engine for a date range
+func (s *PricingService) GetPricingRuleChanges(ctx context.Context, startDate, endDate string) ([]models.PricingRuleChange, error) {
+	start, end, err := parseRestrictionRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pricingRepo.GetPricingRuleChanges(ctx, start, end)
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
@@ -284,3 +662,119 @@ func (s *PricingService) InvalidateAllPricingCache() error {
 	}
 	return nil
 }
+
+// ============================================================================
+// Day-Use Rate Methods
+// ============================================================================
+
+// GetAllDayUseRates retrieves every day-use rate
+func (s *PricingService) GetAllDayUseRates(ctx context.Context) ([]models.DayUseRate, error) {
+	return s.pricingRepo.GetAllDayUseRates(ctx)
+}
+
+// UpsertDayUseRate creates or updates the price of a day-use slot length
+func (s *PricingService) UpsertDayUseRate(ctx context.Context, req *models.UpsertDayUseRateRequest) error {
+	plan, err := s.pricingRepo.GetRatePlanByID(ctx, req.RatePlanID)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return fmt.Errorf("rate plan not found")
+	}
+	if !plan.IsDayUse {
+		return fmt.Errorf("rate plan %s is not a day-use plan", plan.Name)
+	}
+	if req.DurationHours > models.DayUseCloseHour-models.DayUseOpenHour {
+		return fmt.Errorf("duration cannot exceed the %d-hour day-use window", models.DayUseCloseHour-models.DayUseOpenHour)
+	}
+
+	return s.pricingRepo.UpsertDayUseRate(ctx, req)
+}
+
+// DeleteDayUseRate removes a day-use rate
+func (s *PricingService) DeleteDayUseRate(ctx context.Context, id int) error {
+	deleted, err := s.pricingRepo.DeleteDayUseRate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("day-use rate not found")
+	}
+	return nil
+}
+
+// ============================================================================
+// Long-Stay Rate Methods
+// ============================================================================
+
+// GetAllLongStayRates retrieves every long-stay rate
+func (s *PricingService) GetAllLongStayRates(ctx context.Context) ([]models.LongStayRate, error) {
+	return s.pricingRepo.GetAllLongStayRates(ctx)
+}
+
+// UpsertLongStayRate creates or updates the long-stay rate of a threshold
+func (s *PricingService) UpsertLongStayRate(ctx context.Context, req *models.UpsertLongStayRateRequest) error {
+	if (req.DiscountPercent == nil) == (req.MonthlyPrice == nil) {
+		return fmt.Errorf("set either a discount percent or a monthly price")
+	}
+
+	plan, err := s.pricingRepo.GetRatePlanByID(ctx, req.RatePlanID)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return fmt.Errorf("rate plan not found")
+	}
+	if plan.IsDayUse {
+		return fmt.Errorf("rate plan %s is a day-use plan", plan.Name)
+	}
+
+	return s.pricingRepo.UpsertLongStayRate(ctx, req)
+}
+
+// DeleteLongStayRate removes a long-stay rate
+func (s *PricingService) DeleteLongStayRate(ctx context.Context, id int) error {
+	deleted, err := s.pricingRepo.DeleteLongStayRate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("long-stay rate not found")
+	}
+	return nil
+}
+
+// ============================================================================
+// Stay-Time Fee Methods
+// ============================================================================
+
+// GetAllStayTimeFees retrieves every early check-in and late check-out fee tier
+func (s *PricingService) GetAllStayTimeFees(ctx context.Context) ([]models.StayTimeFee, error) {
+	return s.pricingRepo.GetAllStayTimeFees(ctx)
+}
+
+// UpsertStayTimeFee creates or updates the fee of an early check-in or late check-out
+// tier. Early check-in tiers start before the standard check-in hour and late
+// check-out tiers end after the standard check-out hour.
+func (s *PricingService) UpsertStayTimeFee(ctx context.Context, req *models.UpsertStayTimeFeeRequest) error {
+	if req.RequestType == models.StayTimeEarlyCheckIn && req.TierHour >= models.StandardCheckInHour {
+		return fmt.Errorf("an early check-in tier must start before %02d:00", models.StandardCheckInHour)
+	}
+	if req.RequestType == models.StayTimeLateCheckOut && req.TierHour <= models.StandardCheckOutHour {
+		return fmt.Errorf("a late check-out tier must end after %02d:00", models.StandardCheckOutHour)
+	}
+
+	return s.pricingRepo.UpsertStayTimeFee(ctx, req)
+}
+
+// DeleteStayTimeFee removes a stay-time fee tier
+func (s *PricingService) DeleteStayTimeFee(ctx context.Context, id int) error {
+	deleted, err := s.pricingRepo.DeleteStayTimeFee(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("stay-time fee not found")
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// dateOnly drops the time of day so booking dates compare with stay dates
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checkRatePlanEligibility returns the reasons a stay does not qualify for a rate plan.
+// An empty result means the plan can be sold.
+func checkRatePlanEligibility(plan *models.RatePlan, e models.RatePlanEligibility) []string {
+	reasons := []string{}
+
+	if !plan.IsActive {
+		reasons = append(reasons, "rate plan is not active")
+	}
+
+	bookingDate := dateOnly(e.BookingDate)
+	checkIn := dateOnly(e.CheckIn)
+	checkOut := dateOnly(e.CheckOut)
+	advanceDays := int(checkIn.Sub(bookingDate).Hours() / 24)
+
+	if plan.MinAdvanceDays != nil && advanceDays < *plan.MinAdvanceDays {
+		reasons = append(reasons, fmt.Sprintf("must be booked at least %d days before arrival", *plan.MinAdvanceDays))
+	}
+	if plan.MaxAdvanceDays != nil && advanceDays > *plan.MaxAdvanceDays {
+		reasons = append(reasons, fmt.Sprintf("can be booked at most %d days before arrival", *plan.MaxAdvanceDays))
+	}
+
+	if plan.StayStartDate != nil && checkIn.Before(dateOnly(*plan.StayStartDate)) {
+		reasons = append(reasons, fmt.Sprintf("stays must start on or after %s", plan.StayStartDate.Format("2006-01-02")))
+	}
+	if plan.StayEndDate != nil && checkOut.After(dateOnly(*plan.StayEndDate)) {
+		reasons = append(reasons, fmt.Sprintf("stays must end on or before %s", plan.StayEndDate.Format("2006-01-02")))
+	}
+
+	if plan.BookingStartDate != nil && bookingDate.Before(dateOnly(*plan.BookingStartDate)) {
+		reasons = append(reasons, fmt.Sprintf("bookable from %s", plan.BookingStartDate.Format("2006-01-02")))
+	}
+	if plan.BookingEndDate != nil && bookingDate.After(dateOnly(*plan.BookingEndDate)) {
+		reasons = append(reasons, fmt.Sprintf("bookable until %s", plan.BookingEndDate.Format("2006-01-02")))
+	}
+
+	if len(plan.ArrivalDaysOfWeek) > 0 {
+		allowed := false
+		for _, dow := range plan.ArrivalDaysOfWeek {
+			if time.Weekday(dow) == checkIn.Weekday() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			reasons = append(reasons, fmt.Sprintf("arrival on %s is not allowed", checkIn.Weekday()))
+		}
+	}
+
+	if plan.MinGuests != nil && e.NumGuests > 0 && e.NumGuests < *plan.MinGuests {
+		reasons = append(reasons, fmt.Sprintf("requires at least %d guests", *plan.MinGuests))
+	}
+
+	if plan.MinNights != nil && nightsBetween(checkIn, checkOut) < *plan.MinNights {
+		reasons = append(reasons, fmt.Sprintf("requires a stay of at least %d nights", *plan.MinNights))
+	}
+
+	if plan.MembersOnly && !e.IsMember {
+		reasons = append(reasons, "available to signed-in members only")
+	}
+
+	return reasons
+}
+
+// applyRatePlanRules copies the rules of a request onto a rate plan
+func applyRatePlanRules(plan *models.RatePlan, rules *models.RatePlanRulesRequest) error {
+	parseDate := func(value *string, field string) (*time.Time, error) {
+		if value == nil || *value == "" {
+			return nil, nil
+		}
+		t, err := time.Parse("2006-01-02", *value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s format", field)
+		}
+		return &t, nil
+	}
+
+	var err error
+	if plan.StayStartDate, err = parseDate(rules.StayStartDate, "stay start date"); err != nil {
+		return err
+	}
+	if plan.StayEndDate, err = parseDate(rules.StayEndDate, "stay end date"); err != nil {
+		return err
+	}
+	if plan.BookingStartDate, err = parseDate(rules.BookingStartDate, "booking start date"); err != nil {
+		return err
+	}
+	if plan.BookingEndDate, err = parseDate(rules.BookingEndDate, "booking end date"); err != nil {
+		return err
+	}
+
+	plan.MinAdvanceDays = rules.MinAdvanceDays
+	plan.MaxAdvanceDays = rules.MaxAdvanceDays
+	plan.ArrivalDaysOfWeek = nil
+	if len(rules.ArrivalDaysOfWeek) > 0 {
+		plan.ArrivalDaysOfWeek = rules.ArrivalDaysOfWeek
+	}
+	plan.MinGuests = rules.MinGuests
+	plan.MinNights = rules.MinNights
+	plan.MembersOnly = rules.MembersOnly
+	plan.BaseRatePlanID = rules.BaseRatePlanID
+	plan.DerivedAdjustmentType = rules.DerivedAdjustmentType
+	plan.DerivedAdjustmentValue = rules.DerivedAdjustmentValue
+
+	return nil
+}
+
+// validateRatePlanRules checks that the rules of a rate plan are consistent
+func validateRatePlanRules(plan *models.RatePlan) error {
+	if plan.MinAdvanceDays != nil && *plan.MinAdvanceDays < 0 {
+		return errors.New("minimum advance days cannot be negative")
+	}
+	if plan.MaxAdvanceDays != nil && *plan.MaxAdvanceDays < 0 {
+		return errors.New("maximum advance days cannot be negative")
+	}
+	if plan.MinAdvanceDays != nil && plan.MaxAdvanceDays != nil && *plan.MaxAdvanceDays < *plan.MinAdvanceDays {
+		return errors.New("maximum advance days cannot be less than minimum advance days")
+	}
+	if plan.StayStartDate != nil && plan.StayEndDate != nil && plan.StayEndDate.Before(*plan.StayStartDate) {
+		return errors.New("stay end date must be after stay start date")
+	}
+	if plan.BookingStartDate != nil && plan.BookingEndDate != nil && plan.BookingEndDate.Before(*plan.BookingStartDate) {
+		return errors.New("booking end date must be after booking start date")
+	}
+	for _, dow := range plan.ArrivalDaysOfWeek {
+		if dow < 0 || dow > 6 {
+			return errors.New("days of week must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+	if plan.MinGuests != nil && *plan.MinGuests < 1 {
+		return errors.New("minimum guests must be at least 1")
+	}
+	if plan.MinNights != nil && *plan.MinNights < 1 {
+		return errors.New("minimum nights must be at least 1")
+	}
+
+	derivedFields := 0
+	if plan.BaseRatePlanID != nil {
+		derivedFields++
+	}
+	if plan.DerivedAdjustmentType != nil {
+		derivedFields++
+	}
+	if plan.DerivedAdjustmentValue != nil {
+		derivedFields++
+	}
+	if derivedFields != 0 && derivedFields != 3 {
+		return errors.New("derived pricing requires base rate plan, adjustment type and adjustment value")
+	}
+	if plan.BaseRatePlanID != nil {
+		if *plan.BaseRatePlanID == plan.RatePlanID {
+			return errors.New("a rate plan cannot be derived from itself")
+		}
+		if *plan.DerivedAdjustmentType != "percentage" && *plan.DerivedAdjustmentType != "fixed" {
+			return errors.New("adjustment type must be 'percentage' or 'fixed'")
+		}
+		if *plan.DerivedAdjustmentType == "percentage" && *plan.DerivedAdjustmentValue < -100 {
+			return errors.New("percentage adjustment cannot be less than -100")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func planDate(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func eligibilityFor(bookingDate, checkIn time.Time, nights int) models.RatePlanEligibility {
+	return models.RatePlanEligibility{
+		BookingDate: bookingDate,
+		CheckIn:     checkIn,
+		CheckOut:    checkIn.AddDate(0, 0, nights),
+		NumGuests:   2,
+	}
+}
+
+func TestCheckRatePlanEligibility_NoRules(t *testing.T) {
+	plan := &models.RatePlan{Name: "Standard Rate", IsActive: true}
+
+	reasons := checkRatePlanEligibility(plan, eligibilityFor(planDate(7, 1), planDate(7, 1), 1))
+
+	assert.Empty(t, reasons)
+}
+
+func TestCheckRatePlanEligibility_AdvancePurchase(t *testing.T) {
+	plan := &models.RatePlan{Name: "Advance Purchase 14", IsActive: true, MinAdvanceDays: intPtr(14), MaxAdvanceDays: intPtr(90)}
+
+	// Booking time of day does not count against the advance days
+	bookedAt := time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC)
+	assert.Empty(t, checkRatePlanEligibility(plan, eligibilityFor(bookedAt, planDate(7, 15), 2)))
+
+	reasons := checkRatePlanEligibility(plan, eligibilityFor(bookedAt, planDate(7, 14), 2))
+	assert.Equal(t, []string{"must be booked at least 14 days before arrival"}, reasons)
+
+	reasons = checkRatePlanEligibility(plan, eligibilityFor(bookedAt, planDate(10, 1), 2))
+	assert.Equal(t, []string{"can be booked at most 90 days before arrival"}, reasons)
+}
+
+func TestCheckRatePlanEligibility_StayAndBookingWindows(t *testing.T) {
+	stayStart, stayEnd := planDate(12, 1), planDate(12, 31)
+	bookStart, bookEnd := planDate(9, 1), planDate(10, 31)
+	plan := &models.RatePlan{
+		Name:             "Winter Promo",
+		IsActive:         true,
+		StayStartDate:    &stayStart,
+		StayEndDate:      &stayEnd,
+		BookingStartDate: &bookStart,
+		BookingEndDate:   &bookEnd,
+	}
+
+	// Checking out on the last stay date is allowed
+	assert.Empty(t, checkRatePlanEligibility(plan, eligibilityFor(planDate(10, 1), planDate(12, 29), 2)))
+
+	reasons := checkRatePlanEligibility(plan, eligibilityFor(planDate(10, 1), planDate(12, 30), 2))
+	assert.Equal(t, []string{"stays must end on or before 2025-12-31"}, reasons)
+
+	reasons = checkRatePlanEligibility(plan, eligibilityFor(planDate(11, 1), planDate(11, 30), 2))
+	assert.Equal(t, []string{"stays must start on or after 2025-12-01", "bookable until 2025-10-31"}, reasons)
+
+	reasons = checkRatePlanEligibility(plan, eligibilityFor(planDate(8, 31), planDate(12, 5), 2))
+	assert.Equal(t, []string{"bookable from 2025-09-01"}, reasons)
+}
+
+func TestCheckRatePlanEligibility_ArrivalDaysGuestsAndMembers(t *testing.T) {
+	plan := &models.RatePlan{
+		Name:              "Weekend Family",
+		IsActive:          true,
+		ArrivalDaysOfWeek: []int{5, 6}, // Friday, Saturday
+		MinGuests:         intPtr(3),
+		MembersOnly:       true,
+	}
+
+	stay := eligibilityFor(planDate(7, 1), planDate(7, 4), 2) // 2025-07-04 is a Friday
+	stay.NumGuests = 3
+	stay.IsMember = true
+	assert.Empty(t, checkRatePlanEligibility(plan, stay))
+
+	stay = eligibilityFor(planDate(7, 1), planDate(7, 7), 2) // Monday
+	reasons := checkRatePlanEligibility(plan, stay)
+	assert.Equal(t, []string{
+		"arrival on Monday is not allowed",
+		"requires at least 3 guests",
+		"available to signed-in members only",
+	}, reasons)
+
+	// Unknown guest count skips the minimum guests rule
+	stay = eligibilityFor(planDate(7, 1), planDate(7, 5), 2)
+	stay.NumGuests = 0
+	stay.IsMember = true
+	assert.Empty(t, checkRatePlanEligibility(plan, stay))
+}
+
+func TestCheckRatePlanEligibility_MinNights(t *testing.T) {
+	plan := &models.RatePlan{Name: "Long Stay", IsActive: true, MinNights: intPtr(7)}
+
+	assert.Empty(t, checkRatePlanEligibility(plan, eligibilityFor(planDate(7, 1), planDate(7, 10), 7)))
+
+	reasons := checkRatePlanEligibility(plan, eligibilityFor(planDate(7, 1), planDate(7, 10), 6))
+	assert.Equal(t, []string{"requires a stay of at least 7 nights"}, reasons)
+}
+
+func TestCheckRatePlanEligibility_Inactive(t *testing.T) {
+	plan := &models.RatePlan{Name: "Old Promo", IsActive: false}
+
+	reasons := checkRatePlanEligibility(plan, eligibilityFor(planDate(7, 1), planDate(7, 2), 1))
+
+	assert.Equal(t, []string{"rate plan is not active"}, reasons)
+}
+
+func TestApplyAndValidateRatePlanRules(t *testing.T) {
+	adjustment := "percentage"
+	value := -15.0
+	start, end := "2025-12-01", "2025-11-01"
+	plan := &models.RatePlan{RatePlanID: 2, Name: "Non-Refundable Rate", IsActive: true}
+
+	err := applyRatePlanRules(plan, &models.RatePlanRulesRequest{
+		StayStartDate:          &start,
+		StayEndDate:            &end,
+		BaseRatePlanID:         intPtr(1),
+		DerivedAdjustmentType:  &adjustment,
+		DerivedAdjustmentValue: &value,
+	})
+	assert.NoError(t, err)
+	assert.EqualError(t, validateRatePlanRules(plan), "stay end date must be after stay start date")
+
+	plan.StayEndDate = nil
+	assert.NoError(t, validateRatePlanRules(plan))
+
+	plan.DerivedAdjustmentValue = nil
+	assert.EqualError(t, validateRatePlanRules(plan), "derived pricing requires base rate plan, adjustment type and adjustment value")
+
+	plan.DerivedAdjustmentValue = &value
+	plan.BaseRatePlanID = intPtr(2)
+	assert.EqualError(t, validateRatePlanRules(plan), "a rate plan cannot be derived from itself")
+
+	bad := "2025/12/01"
+	err = applyRatePlanRules(plan, &models.RatePlanRulesRequest{BookingStartDate: &bad})
+	assert.EqualError(t, err, "invalid booking start date format")
+}
@@ -78,6 +78,15 @@ func (s *ReportService) GetNoShowReport(ctx context.Context, startDate, endDate
 	return s.reportRepo.GetNoShowReport(ctx, startDate, endDate)
 }
 
+// GetAddOnRevenueReport retrieves add-on revenue per service date
+func (s *ReportService) GetAddOnRevenueReport(ctx context.Context, startDate, endDate time.Time) ([]models.AddOnRevenueReport, error) {
+	if startDate.After(endDate) {
+		return nil, fmt.Errorf("start date must be before end date")
+	}
+
+	return s.reportRepo.GetAddOnRevenueReport(ctx, startDate, endDate)
+}
+
 // GetReportSummary retrieves aggregated statistics
 func (s *ReportService) GetReportSummary(ctx context.Context, startDate, endDate time.Time) (*models.ReportSummary, error) {
 	if startDate.After(endDate) {
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// effectiveRestriction is the combined restriction of one date after merging the
+// all-plans row with the rate plan's own row (the stricter value wins)
+type effectiveRestriction struct {
+	minLOS            int
+	maxLOS            int
+	closedToArrival   bool
+	closedToDeparture bool
+	stopSell          bool
+}
+
+// evaluateRestrictions checks a stay against the restrictions of a single room type.
+// Rows for other rate plans are ignored; a nil ratePlanID only applies the all-plans rows.
+// Stop-sell applies to every night, CTA and min/max LOS to the arrival date and CTD to the
+// departure date.
+func evaluateRestrictions(restrictions []models.RateRestriction, ratePlanID *int, checkIn, checkOut time.Time) []models.RestrictionViolation {
+	byDate := make(map[string]*effectiveRestriction)
+	for _, rr := range restrictions {
+		if rr.RatePlanID != nil && (ratePlanID == nil || *rr.RatePlanID != *ratePlanID) {
+			continue
+		}
+
+		key := rr.Date.Format("2006-01-02")
+		eff, ok := byDate[key]
+		if !ok {
+			eff = &effectiveRestriction{}
+			byDate[key] = eff
+		}
+		if rr.MinLOS != nil && *rr.MinLOS > eff.minLOS {
+			eff.minLOS = *rr.MinLOS
+		}
+		if rr.MaxLOS != nil && (eff.maxLOS == 0 || *rr.MaxLOS < eff.maxLOS) {
+			eff.maxLOS = *rr.MaxLOS
+		}
+		eff.closedToArrival = eff.closedToArrival || rr.ClosedToArrival
+		eff.closedToDeparture = eff.closedToDeparture || rr.ClosedToDeparture
+		eff.stopSell = eff.stopSell || rr.StopSell
+	}
+
+	nights := int(checkOut.Sub(checkIn).Hours() / 24)
+	arrival := checkIn.Format("2006-01-02")
+	departure := checkOut.Format("2006-01-02")
+	violations := []models.RestrictionViolation{}
+
+	if eff, ok := byDate[arrival]; ok {
+		if eff.closedToArrival {
+			violations = append(violations, models.RestrictionViolation{
+				Date:    arrival,
+				Code:    models.RestrictionCTA,
+				Message: fmt.Sprintf("arrival is not allowed on %s", arrival),
+			})
+		}
+		if eff.minLOS > 0 && nights < eff.minLOS {
+			violations = append(violations, models.RestrictionViolation{
+				Date:    arrival,
+				Code:    models.RestrictionMinLOS,
+				Message: fmt.Sprintf("arrivals on %s require a minimum stay of %d nights", arrival, eff.minLOS),
+			})
+		}
+		if eff.maxLOS > 0 && nights > eff.maxLOS {
+			violations = append(violations, models.RestrictionViolation{
+				Date:    arrival,
+				Code:    models.RestrictionMaxLOS,
+				Message: fmt.Sprintf("arrivals on %s allow a maximum stay of %d nights", arrival, eff.maxLOS),
+			})
+		}
+	}
+
+	for d := checkIn; d.Before(checkOut); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		if eff, ok := byDate[key]; ok && eff.stopSell {
+			violations = append(violations, models.RestrictionViolation{
+				Date:    key,
+				Code:    models.RestrictionStopSell,
+				Message: fmt.Sprintf("the night of %s is closed for sale", key),
+			})
+		}
+	}
+
+	if eff, ok := byDate[departure]; ok && eff.closedToDeparture {
+		violations = append(violations, models.RestrictionViolation{
+			Date:    departure,
+			Code:    models.RestrictionCTD,
+			Message: fmt.Sprintf("departure is not allowed on %s", departure),
+		})
+	}
+
+	return violations
+}
+
+// groupRestrictionsByRoomType splits restriction rows by room type
+func groupRestrictionsByRoomType(restrictions []models.RateRestriction) map[int][]models.RateRestriction {
+	grouped := make(map[int][]models.RateRestriction)
+	for _, rr := range restrictions {
+		grouped[rr.RoomTypeID] = append(grouped[rr.RoomTypeID], rr)
+	}
+	return grouped
+}
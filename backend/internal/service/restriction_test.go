@@ -0,0 +1,109 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func restrictionDate(day int) time.Time {
+	return time.Date(2025, 7, day, 0, 0, 0, 0, time.UTC)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func violationCodes(violations []models.RestrictionViolation) []string {
+	codes := make([]string, len(violations))
+	for i, v := range violations {
+		codes[i] = v.Code
+	}
+	return codes
+}
+
+func TestEvaluateRestrictions_NoRestrictions(t *testing.T) {
+	violations := evaluateRestrictions(nil, intPtr(1), restrictionDate(1), restrictionDate(3))
+
+	assert.Empty(t, violations)
+}
+
+func TestEvaluateRestrictions_MinMaxLOSFromArrivalDate(t *testing.T) {
+	restrictions := []models.RateRestriction{
+		{RoomTypeID: 1, Date: restrictionDate(1), MinLOS: intPtr(3)},
+		{RoomTypeID: 1, Date: restrictionDate(2), MaxLOS: intPtr(1)},
+	}
+
+	violations := evaluateRestrictions(restrictions, nil, restrictionDate(1), restrictionDate(3))
+	require.Len(t, violations, 1)
+	assert.Equal(t, models.RestrictionMinLOS, violations[0].Code)
+	assert.Equal(t, "2025-07-01", violations[0].Date)
+
+	// The max stay on the 2nd only applies to arrivals on the 2nd
+	assert.Empty(t, evaluateRestrictions(restrictions, nil, restrictionDate(1), restrictionDate(4)))
+
+	violations = evaluateRestrictions(restrictions, nil, restrictionDate(2), restrictionDate(4))
+	assert.Equal(t, []string{models.RestrictionMaxLOS}, violationCodes(violations))
+}
+
+func TestEvaluateRestrictions_CTAAndCTD(t *testing.T) {
+	restrictions := []models.RateRestriction{
+		{RoomTypeID: 1, Date: restrictionDate(1), ClosedToArrival: true},
+		{RoomTypeID: 1, Date: restrictionDate(3), ClosedToDeparture: true},
+	}
+
+	violations := evaluateRestrictions(restrictions, nil, restrictionDate(1), restrictionDate(3))
+	assert.Equal(t, []string{models.RestrictionCTA, models.RestrictionCTD}, violationCodes(violations))
+
+	// Staying through closed dates is allowed
+	assert.Empty(t, evaluateRestrictions(restrictions, nil, restrictionDate(0), restrictionDate(4)))
+}
+
+func TestEvaluateRestrictions_StopSellAppliesToNightsOnly(t *testing.T) {
+	restrictions := []models.RateRestriction{
+		{RoomTypeID: 1, Date: restrictionDate(2), StopSell: true},
+	}
+
+	violations := evaluateRestrictions(restrictions, nil, restrictionDate(1), restrictionDate(4))
+	require.Len(t, violations, 1)
+	assert.Equal(t, models.RestrictionStopSell, violations[0].Code)
+	assert.Equal(t, "2025-07-02", violations[0].Date)
+
+	// Departing on a stop-sell date is fine
+	assert.Empty(t, evaluateRestrictions(restrictions, nil, restrictionDate(1), restrictionDate(2)))
+}
+
+func TestEvaluateRestrictions_RatePlanRows(t *testing.T) {
+	restrictions := []models.RateRestriction{
+		{RoomTypeID: 1, Date: restrictionDate(1), MinLOS: intPtr(2)},
+		{RoomTypeID: 1, RatePlanID: intPtr(5), Date: restrictionDate(1), MinLOS: intPtr(4)},
+		{RoomTypeID: 1, RatePlanID: intPtr(6), Date: restrictionDate(1), StopSell: true},
+	}
+
+	// Without a rate plan only the all-plans row applies
+	assert.Empty(t, evaluateRestrictions(restrictions, nil, restrictionDate(1), restrictionDate(3)))
+
+	// The stricter min stay of plan 5 wins; plan 6's stop-sell is ignored
+	violations := evaluateRestrictions(restrictions, intPtr(5), restrictionDate(1), restrictionDate(3))
+	require.Len(t, violations, 1)
+	assert.Equal(t, models.RestrictionMinLOS, violations[0].Code)
+	assert.Contains(t, violations[0].Message, "4 nights")
+
+	violations = evaluateRestrictions(restrictions, intPtr(6), restrictionDate(1), restrictionDate(3))
+	assert.Equal(t, []string{models.RestrictionStopSell}, violationCodes(violations))
+}
+
+func TestRestrictionError_Message(t *testing.T) {
+	err := &models.RestrictionError{
+		RoomTypeID: 1,
+		Violations: []models.RestrictionViolation{
+			{Code: models.RestrictionCTA, Message: "arrival is not allowed on 2025-07-01"},
+			{Code: models.RestrictionStopSell, Message: "the night of 2025-07-02 is closed for sale"},
+		},
+	}
+
+	assert.Equal(t, "stay not available: arrival is not allowed on 2025-07-01; the night of 2025-07-02 is closed for sale", err.Error())
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/cache"
+)
+
+// GetAvailabilityCalendar returns the availability and lowest public rate of every day
+// of a month, from the current month up to CalendarMaxMonthsAhead months ahead. Past
+// days are shown as unavailable.
+func (s *RoomService) GetAvailabilityCalendar(ctx context.Context, req *models.AvailabilityCalendarRequest) (*models.AvailabilityCalendar, error) {
+	from, err := time.Parse("2006-01", req.Month)
+	if err != nil {
+		return nil, errors.New("รูปแบบเดือนไม่ถูกต้อง")
+	}
+	today := time.Now().Truncate(24 * time.Hour)
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if from.Before(thisMonth) || from.After(thisMonth.AddDate(0, models.CalendarMaxMonthsAhead, 0)) {
+		return nil, fmt.Errorf("เดือนต้องอยู่ระหว่างเดือนปัจจุบันถึง %d เดือนข้างหน้า", models.CalendarMaxMonthsAhead)
+	}
+
+	// Try cache first
+	cacheKey := cache.AvailabilityCalendarKey(req.Month, req.RoomTypeID)
+	if s.cache != nil {
+		var cached models.AvailabilityCalendar
+		if err := s.cache.Get(cacheKey, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	days, err := s.roomRepo.GetAvailabilityCalendar(ctx, from, from.AddDate(0, 1, 0), req.RoomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range days {
+		if days[i].Date < today.Format("2006-01-02") {
+			days[i].Available = false
+			days[i].LowestRate = nil
+		}
+	}
+
+	calendar := &models.AvailabilityCalendar{
+		Month:      req.Month,
+		RoomTypeID: req.RoomTypeID,
+		Days:       days,
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Set(cacheKey, calendar, cache.AvailabilityCalendarExpiration)
+	}
+
+	return calendar, nil
+}
+
+// AvailabilityCache drops cached availability calendars when inventory changes
+type AvailabilityCache interface {
+	InvalidateAvailabilityCalendarCache() error
+}
+
+// InvalidateAvailabilityCalendarCache drops every cached availability calendar
+func (s *RoomService) InvalidateAvailabilityCalendarCache() error {
+	if s.cache == nil {
+		return nil
+	}
+
+	if err := s.cache.DeletePattern(cache.AvailabilityCalendarPrefix + ":*"); err != nil {
+		return fmt.Errorf("failed to invalidate availability calendar cache: %w", err)
+	}
+
+	return nil
+}
+
+// InventoryReleased drops cached calendars after allotment changes, cancellations and
+// expired holds
+func (s *RoomService) InventoryReleased() {
+	_ = s.InvalidateAvailabilityCalendarCache()
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAvailabilityCalendar_Validation(t *testing.T) {
+	service := &RoomService{}
+	thisMonth := time.Now()
+
+	_, err := service.GetAvailabilityCalendar(context.Background(), &models.AvailabilityCalendarRequest{Month: "2025-13"})
+	assert.EqualError(t, err, "รูปแบบเดือนไม่ถูกต้อง")
+
+	_, err = service.GetAvailabilityCalendar(context.Background(), &models.AvailabilityCalendarRequest{
+		Month: thisMonth.AddDate(0, -1, 0).Format("2006-01"),
+	})
+	assert.Error(t, err, "past months are rejected")
+
+	_, err = service.GetAvailabilityCalendar(context.Background(), &models.AvailabilityCalendarRequest{
+		Month: thisMonth.AddDate(0, models.CalendarMaxMonthsAhead+1, 0).Format("2006-01"),
+	})
+	assert.Error(t, err, "months too far ahead are rejected")
+}
+
+func TestInvalidateAvailabilityCalendarCache_NoCache(t *testing.T) {
+	service := &RoomService{}
+	assert.NoError(t, service.InvalidateAvailabilityCalendarCache())
+	service.InventoryReleased()
+}
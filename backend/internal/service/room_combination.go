@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// maxRoomCombinations is the number of combinations a search returns
+const maxRoomCombinations = 10
+
+// combinationOption is a room type a combination can use: the rooms and extra beds free
+// on every night and the cheapest price of one room for each number of guests it fits
+type combinationOption struct {
+	roomType  models.RoomType
+	available int
+	extraBeds int
+	prices    map[int]models.CombinationRoom // By guests in the room
+}
+
+// buildRoomCombinations lists the room combinations that fit a party, cheapest first,
+// keeping the cheapest split of guests for each set of room types. With occupancy the
+// rooms and their guests are fixed; otherwise guests are split over exactly rooms
+// rooms, or over as many as MaxSearchRooms when rooms is 0.
+func buildRoomCombinations(options []combinationOption, occupancy []int, guests, rooms int) []models.RoomCombination {
+	best := make(map[string]models.RoomCombination)
+	used := make([]int, len(options))
+	bedsUsed := make([]int, len(options))
+	var picked []models.CombinationRoom
+	var pickedOptions []int
+
+	record := func() {
+		keys := make([]string, len(pickedOptions))
+		for i, o := range pickedOptions {
+			keys[i] = strconv.Itoa(o)
+		}
+		sort.Strings(keys)
+		key := strings.Join(keys, ",")
+
+		combination := models.RoomCombination{
+			Rooms:      append([]models.CombinationRoom(nil), picked...),
+			TotalRooms: len(picked),
+		}
+		for _, room := range picked {
+			combination.TotalGuests += room.NumGuests
+			combination.TotalPrice += room.TotalPrice
+		}
+		combination.TotalPrice = roundAmount(combination.TotalPrice)
+
+		if current, ok := best[key]; !ok || combination.TotalPrice < current.TotalPrice {
+			best[key] = combination
+		}
+	}
+
+	// take adds a room of option i for k guests when rooms and extra beds remain
+	take := func(i, k int) bool {
+		price, ok := options[i].prices[k]
+		if !ok || used[i] >= options[i].available || bedsUsed[i]+price.ExtraBeds > options[i].extraBeds {
+			return false
+		}
+		used[i]++
+		bedsUsed[i] += price.ExtraBeds
+		picked = append(picked, price)
+		pickedOptions = append(pickedOptions, i)
+		return true
+	}
+	undo := func(i, k int) {
+		used[i]--
+		bedsUsed[i] -= options[i].prices[k].ExtraBeds
+		picked = picked[:len(picked)-1]
+		pickedOptions = pickedOptions[:len(pickedOptions)-1]
+	}
+
+	if len(occupancy) > 0 {
+		// Fixed rooms: pick a room type for each; rooms of equal size take room types in
+		// order so the same set is not listed twice
+		sorted := append([]int(nil), occupancy...)
+		sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+		var assign func(slot, minOption int)
+		assign = func(slot, minOption int) {
+			if slot == len(sorted) {
+				record()
+				return
+			}
+			start := 0
+			if slot > 0 && sorted[slot] == sorted[slot-1] {
+				start = minOption
+			}
+			for i := start; i < len(options); i++ {
+				if take(i, sorted[slot]) {
+					assign(slot+1, i)
+					undo(i, sorted[slot])
+				}
+			}
+		}
+		assign(0, 0)
+	} else {
+		minRooms, maxRooms := rooms, rooms
+		if rooms == 0 {
+			minRooms, maxRooms = 1, models.MaxSearchRooms
+		}
+		if maxRooms > guests {
+			maxRooms = guests
+		}
+
+		// Rooms take room types in order, and rooms of the same type take guests in
+		// descending order, so each split is generated once
+		var split func(remaining, roomsLeft, minOption, maxGuests int)
+		split = func(remaining, roomsLeft, minOption, maxGuests int) {
+			if roomsLeft == 0 {
+				if remaining == 0 {
+					record()
+				}
+				return
+			}
+			for i := minOption; i < len(options); i++ {
+				limit := remaining - (roomsLeft - 1)
+				if i == minOption && maxGuests > 0 && maxGuests < limit {
+					limit = maxGuests
+				}
+				for k := limit; k >= 1; k-- {
+					if take(i, k) {
+						split(remaining-k, roomsLeft-1, i, k)
+						undo(i, k)
+					}
+				}
+				maxGuests = 0
+			}
+		}
+		for n := minRooms; n <= maxRooms; n++ {
+			split(guests, n, 0, 0)
+		}
+	}
+
+	combinations := make([]models.RoomCombination, 0, len(best))
+	for _, combination := range best {
+		combinations = append(combinations, combination)
+	}
+	sort.Slice(combinations, func(i, j int) bool {
+		if combinations[i].TotalPrice != combinations[j].TotalPrice {
+			return combinations[i].TotalPrice < combinations[j].TotalPrice
+		}
+		return combinations[i].TotalRooms < combinations[j].TotalRooms
+	})
+	if len(combinations) > maxRoomCombinations {
+		combinations = combinations[:maxRoomCombinations]
+	}
+
+	return combinations
+}
+
+// findRoomCombinations prices every available room type matching the filters of the
+// request for each number of guests a room can take and combines them for the party.
+// Each room is priced under its cheapest eligible rate plan with all of its guests as
+// adults; the children's age bands are priced when the combination is booked.
+func (s *RoomService) findRoomCombinations(ctx context.Context, req *models.SearchRoomsRequest, checkIn, checkOut time.Time, guests, rooms int) ([]models.RoomCombination, []models.RoomType, error) {
+	available, err := s.roomRepo.SearchAvailableRooms(ctx, checkIn, checkOut, 1, roomSearchFilter(req))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search rooms from repository: %w", err)
+	}
+	if len(available) == 0 {
+		return nil, available, nil
+	}
+
+	plans, err := s.roomRepo.GetActiveRatePlans(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get rate plans: %w", err)
+	}
+
+	ids := make([]int, len(available))
+	maxGuests := 0
+	for i, rt := range available {
+		ids[i] = rt.RoomTypeID
+		if capacity := rt.MaxOccupancy + rt.MaxExtraBeds; capacity > maxGuests {
+			maxGuests = capacity
+		}
+	}
+	extraBeds, err := s.roomRepo.GetExtraBedAvailability(ctx, ids, checkIn, checkOut)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := make([]combinationOption, len(available))
+	for i, rt := range available {
+		options[i] = combinationOption{
+			roomType:  rt,
+			extraBeds: extraBeds[rt.RoomTypeID],
+			prices:    make(map[int]models.CombinationRoom),
+		}
+		if rt.AvailableRooms != nil {
+			options[i].available = *rt.AvailableRooms
+		}
+	}
+
+	for k := 1; k <= maxGuests && k <= guests; k++ {
+		ratePlans := filterEligibleRatePlans(plans, models.RatePlanEligibility{
+			BookingDate: time.Now(),
+			CheckIn:     checkIn,
+			CheckOut:    checkOut,
+			NumGuests:   k,
+			IsMember:    req.IsMember,
+		})
+		if len(ratePlans) == 0 {
+			continue
+		}
+
+		priced, _, err := s.priceRoomTypes(ctx, available, ratePlans, checkIn, checkOut, k, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, rt := range priced {
+			cheapest := rt.RatePlans[0]
+			for _, offer := range rt.RatePlans[1:] {
+				if offer.TotalPrice < cheapest.TotalPrice {
+					cheapest = offer
+				}
+			}
+			for i := range options {
+				if options[i].roomType.RoomTypeID != rt.RoomTypeID {
+					continue
+				}
+				extra := 0
+				if k > rt.MaxOccupancy {
+					extra = k - rt.MaxOccupancy
+				}
+				options[i].prices[k] = models.CombinationRoom{
+					RoomTypeID:   rt.RoomTypeID,
+					RoomTypeName: rt.Name,
+					RatePlanID:   cheapest.RatePlanID,
+					RatePlanName: cheapest.Name,
+					NumGuests:    k,
+					ExtraBeds:    extra,
+					TotalPrice:   cheapest.TotalPrice,
+				}
+			}
+		}
+	}
+
+	return buildRoomCombinations(options, req.Occupancy, guests, rooms), available, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// combinationOptions prices a standard room (2 guests, 1 extra bed) at 1000 a night plus
+// 300 for the extra bed and a family room (4 guests) at 2500, for one night
+func combinationOptions(standardRooms, familyRooms, extraBeds int) []combinationOption {
+	room := func(id int, name string, guests, extraBeds int, price float64) models.CombinationRoom {
+		return models.CombinationRoom{RoomTypeID: id, RoomTypeName: name, RatePlanID: 1, NumGuests: guests, ExtraBeds: extraBeds, TotalPrice: price}
+	}
+	return []combinationOption{
+		{
+			roomType:  models.RoomType{RoomTypeID: 1, Name: "Standard", MaxOccupancy: 2, MaxExtraBeds: 1},
+			available: standardRooms,
+			extraBeds: extraBeds,
+			prices: map[int]models.CombinationRoom{
+				1: room(1, "Standard", 1, 0, 1000),
+				2: room(1, "Standard", 2, 0, 1000),
+				3: room(1, "Standard", 3, 1, 1300),
+			},
+		},
+		{
+			roomType:  models.RoomType{RoomTypeID: 2, Name: "Family", MaxOccupancy: 4},
+			available: familyRooms,
+			prices: map[int]models.CombinationRoom{
+				1: room(2, "Family", 1, 0, 2500),
+				2: room(2, "Family", 2, 0, 2500),
+				3: room(2, "Family", 3, 0, 2500),
+				4: room(2, "Family", 4, 0, 2500),
+			},
+		},
+	}
+}
+
+func roomTypesOf(combination models.RoomCombination) []int {
+	ids := make([]int, len(combination.Rooms))
+	for i, room := range combination.Rooms {
+		ids[i] = room.RoomTypeID
+	}
+	return ids
+}
+
+func TestBuildRoomCombinations_TotalParty(t *testing.T) {
+	// With one extra bed free, three standard rooms are cheapest for six
+	combinations := buildRoomCombinations(combinationOptions(5, 2, 1), nil, 6, 0)
+	require.NotEmpty(t, combinations)
+
+	cheapest := combinations[0]
+	assert.Equal(t, 3, cheapest.TotalRooms)
+	assert.Equal(t, 6, cheapest.TotalGuests)
+	assert.Equal(t, 3000.0, cheapest.TotalPrice)
+	assert.Equal(t, []int{1, 1, 1}, roomTypesOf(cheapest))
+
+	for i, combination := range combinations {
+		assert.Equal(t, 6, combination.TotalGuests)
+		if i > 0 {
+			assert.GreaterOrEqual(t, combination.TotalPrice, combinations[i-1].TotalPrice, "cheapest first")
+		}
+	}
+
+	// Each set of room types is listed once, with its cheapest split
+	seen := make(map[string]bool)
+	for _, combination := range combinations {
+		key := ""
+		for _, id := range roomTypesOf(combination) {
+			key += string(rune('0' + id))
+		}
+		assert.False(t, seen[key], "duplicate combination %s", key)
+		seen[key] = true
+	}
+
+	// With two, two standard rooms with an extra bed each are cheaper
+	combinations = buildRoomCombinations(combinationOptions(5, 2, 2), nil, 6, 0)
+	require.NotEmpty(t, combinations)
+	assert.Equal(t, []int{1, 1}, roomTypesOf(combinations[0]))
+	assert.Equal(t, 2600.0, combinations[0].TotalPrice)
+	for _, room := range combinations[0].Rooms {
+		assert.Equal(t, 1, room.ExtraBeds)
+	}
+}
+
+func TestBuildRoomCombinations_Availability(t *testing.T) {
+	// Only two standard rooms and no extra beds: six guests need a family room
+	combinations := buildRoomCombinations(combinationOptions(2, 1, 0), nil, 6, 0)
+	require.NotEmpty(t, combinations)
+	assert.Equal(t, []int{1, 2}, roomTypesOf(combinations[0]))
+	assert.Equal(t, 3500.0, combinations[0].TotalPrice)
+
+	for _, combination := range combinations {
+		standard := 0
+		for _, room := range combination.Rooms {
+			assert.Zero(t, room.ExtraBeds, "no extra beds are left")
+			if room.RoomTypeID == 1 {
+				standard++
+			}
+		}
+		assert.LessOrEqual(t, standard, 2)
+	}
+
+	// Nothing fits nine guests in three rooms
+	assert.Empty(t, buildRoomCombinations(combinationOptions(2, 1, 0), nil, 9, 0))
+}
+
+func TestBuildRoomCombinations_RoomCount(t *testing.T) {
+	combinations := buildRoomCombinations(combinationOptions(5, 2, 1), nil, 6, 2)
+	require.NotEmpty(t, combinations)
+	for _, combination := range combinations {
+		assert.Equal(t, 2, combination.TotalRooms)
+	}
+	assert.Equal(t, 3500.0, combinations[0].TotalPrice, "a standard and a family room")
+}
+
+func TestBuildRoomCombinations_Occupancy(t *testing.T) {
+	combinations := buildRoomCombinations(combinationOptions(5, 2, 2), []int{2, 4}, 6, 2)
+	require.NotEmpty(t, combinations)
+
+	cheapest := combinations[0]
+	assert.Equal(t, 3500.0, cheapest.TotalPrice)
+	assert.Equal(t, []int{2, 1}, roomTypesOf(cheapest), "four guests only fit the family room")
+	assert.Equal(t, 4, cheapest.Rooms[0].NumGuests)
+	assert.Equal(t, 2, cheapest.Rooms[1].NumGuests)
+
+	// Two rooms of two: the same set of room types is not repeated in another order
+	combinations = buildRoomCombinations(combinationOptions(5, 2, 2), []int{2, 2}, 4, 2)
+	assert.Len(t, combinations, 3)
+	assert.Equal(t, 2000.0, combinations[0].TotalPrice)
+}
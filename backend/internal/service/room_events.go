@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/internal/repository"
+)
+
+// RoomChangeListener is notified after rooms change status, guests arrive in them or
+// guests leave them
+type RoomChangeListener interface {
+	RoomsChanged(ctx context.Context, eventType string, roomIDs ...int)
+}
+
+// RoomEventPublisher streams room events to the room status board
+type RoomEventPublisher interface {
+	Publish(event models.RoomEvent)
+}
+
+// RoomEventService publishes room changes with the status of each room after the
+// change
+type RoomEventService struct {
+	roomRepo  *repository.RoomRepository
+	publisher RoomEventPublisher
+}
+
+// NewRoomEventService creates a new room event service
+func NewRoomEventService(roomRepo *repository.RoomRepository, publisher RoomEventPublisher) *RoomEventService {
+	return &RoomEventService{
+		roomRepo:  roomRepo,
+		publisher: publisher,
+	}
+}
+
+// RoomsChanged publishes an event for each room. The change has already been made,
+// so a room whose status cannot be read is left for the next change or reload.
+func (s *RoomEventService) RoomsChanged(ctx context.Context, eventType string, roomIDs ...int) {
+	if len(roomIDs) == 0 {
+		return
+	}
+
+	rooms, err := s.roomRepo.GetRoomStatuses(ctx, roomIDs)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, room := range rooms {
+		s.publisher.Publish(models.RoomEvent{
+			Type:       eventType,
+			Room:       room,
+			OccurredAt: now,
+		})
+	}
+}
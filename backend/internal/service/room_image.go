@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/storage"
+	"github.com/hotel-booking-system/backend/pkg/utils"
+)
+
+// SetBlobStore registers the store room type images are kept in
+func (s *RoomService) SetBlobStore(blobs storage.BlobStore) {
+	s.blobs = blobs
+}
+
+// roomTypeImageKey returns the blob key prefix of an uploaded image: the room type and
+// a hash of the file, so the same file always gets the same never-changing URLs
+func roomTypeImageKey(roomTypeID int, data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("room-types/%d/%x", roomTypeID, sum[:8])
+}
+
+// imageVariantKey returns the blob key of one size of an image
+func imageVariantKey(storageKey, variant string) string {
+	return storageKey + "-" + variant + ".jpg"
+}
+
+// isImageOrder reports whether ids lists every image of a gallery exactly once
+func isImageOrder(images []models.RoomTypeImage, ids []int) bool {
+	if len(ids) != len(images) {
+		return false
+	}
+	listed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+	for _, img := range images {
+		if !listed[img.ImageID] {
+			return false
+		}
+	}
+	return true
+}
+
+// withImageURLs fills in the URLs of each size of the images
+func (s *RoomService) withImageURLs(images []models.RoomTypeImage) []models.RoomTypeImage {
+	if s.blobs == nil {
+		return images
+	}
+	for i := range images {
+		images[i].ThumbnailURL = s.blobs.URL(imageVariantKey(images[i].StorageKey, "thumbnail"))
+		images[i].MediumURL = s.blobs.URL(imageVariantKey(images[i].StorageKey, "medium"))
+		images[i].LargeURL = s.blobs.URL(imageVariantKey(images[i].StorageKey, "large"))
+	}
+	return images
+}
+
+// GetRoomTypeImages retrieves the gallery of a room type in display order
+func (s *RoomService) GetRoomTypeImages(ctx context.Context, roomTypeID int) ([]models.RoomTypeImage, error) {
+	images, err := s.roomRepo.GetRoomTypeImages(ctx, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withImageURLs(images), nil
+}
+
+// UploadRoomTypeImage adds an image to the end of a room type's gallery. The upload is
+// turned upright, stripped of EXIF and stored as thumbnail, medium and large JPEGs.
+func (s *RoomService) UploadRoomTypeImage(ctx context.Context, roomTypeID int, data []byte, caption *string) (*models.RoomTypeImage, error) {
+	if s.blobs == nil {
+		return nil, errors.New("image storage is not configured")
+	}
+
+	rt, err := s.roomRepo.GetRoomTypeByID(ctx, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		return nil, errors.New("room type not found")
+	}
+
+	img, err := utils.DecodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key := roomTypeImageKey(roomTypeID, data)
+	var stored []string
+	removeStored := func() {
+		for _, blob := range stored {
+			_ = s.blobs.Delete(ctx, blob)
+		}
+	}
+
+	// Each size is scaled from the one above it
+	resized := img
+	for _, variant := range utils.ImageVariants {
+		resized = utils.ResizeImage(resized, variant.MaxSize)
+		encoded, err := utils.EncodeJPEG(resized)
+		if err != nil {
+			removeStored()
+			return nil, err
+		}
+		blob := imageVariantKey(key, variant.Name)
+		if err := s.blobs.Put(ctx, blob, encoded); err != nil {
+			removeStored()
+			return nil, err
+		}
+		stored = append(stored, blob)
+	}
+
+	created, err := s.roomRepo.CreateRoomTypeImage(ctx, &models.RoomTypeImage{
+		RoomTypeID: roomTypeID,
+		StorageKey: key,
+		Caption:    caption,
+		Width:      img.Bounds().Dx(),
+		Height:     img.Bounds().Dy(),
+	})
+	if err != nil {
+		// The same file is already in the gallery under the same blobs
+		if err.Error() != "image is already in the gallery" {
+			removeStored()
+		}
+		return nil, err
+	}
+
+	_ = s.InvalidateRoomTypeCache(roomTypeID)
+	return &s.withImageURLs([]models.RoomTypeImage{*created})[0], nil
+}
+
+// UpdateRoomTypeImage changes the caption of an image
+func (s *RoomService) UpdateRoomTypeImage(ctx context.Context, roomTypeID, imageID int, req *models.UpdateRoomTypeImageRequest) (*models.RoomTypeImage, error) {
+	updated, err := s.roomRepo.UpdateRoomTypeImageCaption(ctx, roomTypeID, imageID, req.Caption)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, errors.New("image not found")
+	}
+
+	_ = s.InvalidateRoomTypeCache(roomTypeID)
+	return &s.withImageURLs([]models.RoomTypeImage{*updated})[0], nil
+}
+
+// ReorderRoomTypeImages sets the display order of a room type's gallery
+func (s *RoomService) ReorderRoomTypeImages(ctx context.Context, roomTypeID int, imageIDs []int) ([]models.RoomTypeImage, error) {
+	images, err := s.roomRepo.GetRoomTypeImages(ctx, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if !isImageOrder(images, imageIDs) {
+		return nil, errors.New("image order must list every image of the room type once")
+	}
+
+	if err := s.roomRepo.ReorderRoomTypeImages(ctx, roomTypeID, imageIDs); err != nil {
+		return nil, err
+	}
+
+	_ = s.InvalidateRoomTypeCache(roomTypeID)
+	return s.GetRoomTypeImages(ctx, roomTypeID)
+}
+
+// DeleteRoomTypeImage removes an image from a room type's gallery and deletes its files
+func (s *RoomService) DeleteRoomTypeImage(ctx context.Context, roomTypeID, imageID int) error {
+	img, err := s.roomRepo.GetRoomTypeImageByID(ctx, roomTypeID, imageID)
+	if err != nil {
+		return err
+	}
+	if img == nil {
+		return errors.New("image not found")
+	}
+
+	deleted, err := s.roomRepo.DeleteRoomTypeImage(ctx, roomTypeID, imageID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("image not found")
+	}
+
+	s.deleteImageBlobs(ctx, []models.RoomTypeImage{*img})
+	_ = s.InvalidateRoomTypeCache(roomTypeID)
+	return nil
+}
+
+// deleteImageBlobs deletes the files of images whose rows are gone; a file left
+// behind only takes disk space, so failures are ignored
+func (s *RoomService) deleteImageBlobs(ctx context.Context, images []models.RoomTypeImage) {
+	if s.blobs == nil {
+		return
+	}
+	for _, img := range images {
+		for _, variant := range utils.ImageVariants {
+			_ = s.blobs.Delete(ctx, imageVariantKey(img.StorageKey, variant.Name))
+		}
+	}
+}
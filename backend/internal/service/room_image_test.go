@@ -0,0 +1,32 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomTypeImageKey(t *testing.T) {
+	key := roomTypeImageKey(3, []byte("first image"))
+	assert.True(t, strings.HasPrefix(key, "room-types/3/"))
+	assert.Len(t, strings.TrimPrefix(key, "room-types/3/"), 16)
+
+	// The same file keeps its key, another file gets a new one
+	assert.Equal(t, key, roomTypeImageKey(3, []byte("first image")))
+	assert.NotEqual(t, key, roomTypeImageKey(3, []byte("second image")))
+	assert.NotEqual(t, key, roomTypeImageKey(4, []byte("first image")))
+
+	assert.Equal(t, key+"-thumbnail.jpg", imageVariantKey(key, "thumbnail"))
+}
+
+func TestIsImageOrder(t *testing.T) {
+	images := []models.RoomTypeImage{{ImageID: 1}, {ImageID: 2}, {ImageID: 3}}
+
+	assert.True(t, isImageOrder(images, []int{3, 1, 2}))
+	assert.False(t, isImageOrder(images, []int{1, 2}), "missing image")
+	assert.False(t, isImageOrder(images, []int{1, 2, 2}), "repeated image")
+	assert.False(t, isImageOrder(images, []int{1, 2, 4}), "image of another room type")
+	assert.True(t, isImageOrder(nil, []int{}))
+}
@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// inventoryConflicts lists the dates on which the rooms already booked or held exceed
+// capacity, the allotment or number of rooms a change would leave
+func inventoryConflicts(inventory []models.RoomInventory, capacity int) []models.InventoryValidationError {
+	var conflicts []models.InventoryValidationError
+	for _, inv := range inventory {
+		if used := inv.BookedCount + inv.TentativeCount; used > capacity {
+			conflicts = append(conflicts, models.InventoryValidationError{
+				Date: inv.Date.Format("2006-01-02"),
+				Message: fmt.Sprintf("Cannot reduce to %d rooms. Current bookings: %d (booked: %d, tentative: %d)",
+					capacity, used, inv.BookedCount, inv.TentativeCount),
+			})
+		}
+	}
+	return conflicts
+}
+
+// validateRoomType checks the occupancy of a room type
+func validateRoomType(rt *models.RoomType) error {
+	if rt.Name == "" {
+		return errors.New("name is required")
+	}
+	if rt.MaxOccupancy < 1 {
+		return errors.New("max occupancy must be at least 1")
+	}
+	if rt.BaseOccupancy < 1 || rt.BaseOccupancy > rt.MaxOccupancy {
+		return errors.New("base occupancy must be between 1 and max occupancy")
+	}
+	if rt.DefaultAllotment < 0 || rt.MaxExtraBeds < 0 || rt.ExtraBedAllotment < 0 {
+		return errors.New("allotments and extra beds cannot be negative")
+	}
+	return nil
+}
+
+// normalizeRoomFeatures trims room features and drops empty and repeated ones,
+// compared case-insensitively. Never returns nil.
+func normalizeRoomFeatures(features []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, feature := range features {
+		feature = strings.TrimSpace(feature)
+		key := strings.ToLower(feature)
+		if feature == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, feature)
+	}
+	return normalized
+}
+
+// CreateRoomType creates a room type. The default allotment cannot exceed its active
+// rooms, so a new room type is sold once rooms are added and the allotment is raised.
+func (s *RoomService) CreateRoomType(ctx context.Context, req *models.CreateRoomTypeRequest) (*models.RoomType, error) {
+	rt := &models.RoomType{
+		Name:              strings.TrimSpace(req.Name),
+		Description:       req.Description,
+		MaxOccupancy:      req.MaxOccupancy,
+		BaseOccupancy:     req.BaseOccupancy,
+		MaxExtraBeds:      req.MaxExtraBeds,
+		ExtraBedAllotment: req.ExtraBedAllotment,
+		DefaultAllotment:  req.DefaultAllotment,
+		BasePrice:         req.BasePrice,
+		ImageURL:          req.ImageURL,
+		SizeSqm:           req.SizeSqm,
+		BedType:           req.BedType,
+		ViewType:          req.ViewType,
+		IsAccessible:      req.IsAccessible,
+	}
+	if rt.BaseOccupancy == 0 {
+		rt.BaseOccupancy = 2
+		if rt.MaxOccupancy < 2 {
+			rt.BaseOccupancy = rt.MaxOccupancy
+		}
+	}
+	if err := validateRoomType(rt); err != nil {
+		return nil, err
+	}
+	if rt.DefaultAllotment > 0 {
+		return nil, fmt.Errorf("default allotment %d exceeds the 0 active rooms of the room type", rt.DefaultAllotment)
+	}
+
+	created, err := s.roomRepo.CreateRoomType(ctx, rt)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.InvalidateRoomTypeCache(created.RoomTypeID)
+	return created, nil
+}
+
+// UpdateRoomType updates the fields given in the request. A new default allotment must
+// fit the active rooms and, when lowered, the bookings on future dates still at the
+// previous default; otherwise those dates are returned and nothing is changed.
+func (s *RoomService) UpdateRoomType(ctx context.Context, roomTypeID int, req *models.UpdateRoomTypeRequest) (*models.RoomType, []models.InventoryValidationError, error) {
+	rt, err := s.roomRepo.GetRoomTypeByID(ctx, roomTypeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rt == nil {
+		return nil, nil, errors.New("room type not found")
+	}
+	previousAllotment := rt.DefaultAllotment
+
+	if req.Name != nil {
+		rt.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		rt.Description = *req.Description
+	}
+	if req.MaxOccupancy != nil {
+		rt.MaxOccupancy = *req.MaxOccupancy
+	}
+	if req.BaseOccupancy != nil {
+		rt.BaseOccupancy = *req.BaseOccupancy
+	}
+	if req.MaxExtraBeds != nil {
+		rt.MaxExtraBeds = *req.MaxExtraBeds
+	}
+	if req.ExtraBedAllotment != nil {
+		rt.ExtraBedAllotment = *req.ExtraBedAllotment
+	}
+	if req.DefaultAllotment != nil {
+		rt.DefaultAllotment = *req.DefaultAllotment
+	}
+	if req.BasePrice != nil {
+		rt.BasePrice = req.BasePrice
+	}
+	if req.ImageURL != nil {
+		rt.ImageURL = req.ImageURL
+	}
+	if req.SizeSqm != nil {
+		rt.SizeSqm = req.SizeSqm
+	}
+	if req.BedType != nil {
+		rt.BedType = req.BedType
+	}
+	if req.ViewType != nil {
+		rt.ViewType = req.ViewType
+	}
+	if req.IsAccessible != nil {
+		rt.IsAccessible = *req.IsAccessible
+	}
+	if err := validateRoomType(rt); err != nil {
+		return nil, nil, err
+	}
+
+	if rt.DefaultAllotment != previousAllotment {
+		activeRooms, err := s.roomRepo.CountRooms(ctx, roomTypeID, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rt.DefaultAllotment > activeRooms {
+			return nil, nil, fmt.Errorf("default allotment %d exceeds the %d active rooms of the room type", rt.DefaultAllotment, activeRooms)
+		}
+
+		if rt.DefaultAllotment < previousAllotment {
+			inventory, err := s.roomRepo.GetFutureInventory(ctx, roomTypeID)
+			if err != nil {
+				return nil, nil, err
+			}
+			var atDefault []models.RoomInventory
+			for _, inv := range inventory {
+				if inv.Allotment == previousAllotment {
+					atDefault = append(atDefault, inv)
+				}
+			}
+			if conflicts := inventoryConflicts(atDefault, rt.DefaultAllotment); len(conflicts) > 0 {
+				return nil, conflicts, nil
+			}
+		}
+	}
+
+	updated, err := s.roomRepo.UpdateRoomType(ctx, rt, previousAllotment)
+	if err != nil {
+		return nil, nil, err
+	}
+	if updated == nil {
+		return nil, nil, errors.New("room type not found")
+	}
+
+	_ = s.InvalidateRoomTypeCache(roomTypeID)
+	if updated.DefaultAllotment != previousAllotment {
+		_ = s.InvalidateAvailabilityCalendarCache()
+		if updated.DefaultAllotment > previousAllotment && s.listener != nil {
+			s.listener.InventoryReleased()
+		}
+	}
+
+	return updated, nil, nil
+}
+
+// DeleteRoomType deletes a room type that has never had rooms or bookings, with its
+// gallery
+func (s *RoomService) DeleteRoomType(ctx context.Context, roomTypeID int) error {
+	rooms, err := s.roomRepo.CountRooms(ctx, roomTypeID, false)
+	if err != nil {
+		return err
+	}
+	if rooms > 0 {
+		return errors.New("room type has rooms and cannot be deleted")
+	}
+
+	images, err := s.roomRepo.GetRoomTypeImages(ctx, roomTypeID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.roomRepo.DeleteRoomType(ctx, roomTypeID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("room type not found")
+	}
+	s.deleteImageBlobs(ctx, images)
+
+	_ = s.InvalidateRoomTypeCache(roomTypeID)
+	_ = s.InvalidateAvailabilityCalendarCache()
+	return nil
+}
+
+// SetRoomTypeAmenities replaces the amenities of a room type
+func (s *RoomService) SetRoomTypeAmenities(ctx context.Context, roomTypeID int, amenityIDs []int) ([]models.Amenity, error) {
+	rt, err := s.roomRepo.GetRoomTypeByID(ctx, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		return nil, errors.New("room type not found")
+	}
+
+	for _, id := range amenityIDs {
+		amenity, err := s.roomRepo.GetAmenityByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if amenity == nil {
+			return nil, fmt.Errorf("amenity %d not found", id)
+		}
+	}
+
+	if err := s.roomRepo.SetRoomTypeAmenities(ctx, roomTypeID, amenityIDs); err != nil {
+		return nil, err
+	}
+
+	_ = s.InvalidateRoomTypeCache(roomTypeID)
+	return s.roomRepo.GetRoomTypeAmenities(ctx, roomTypeID)
+}
+
+// GetRooms retrieves physical rooms, optionally of one room type and including retired rooms
+func (s *RoomService) GetRooms(ctx context.Context, roomTypeID *int, includeRetired bool) ([]models.Room, error) {
+	return s.roomRepo.GetRooms(ctx, roomTypeID, !includeRetired)
+}
+
+// CreateRoom adds a physical room to a room type. The allotment is not raised; the
+// manager decides when the new room goes on sale.
+func (s *RoomService) CreateRoom(ctx context.Context, req *models.CreateRoomRequest) (*models.Room, error) {
+	rt, err := s.roomRepo.GetRoomTypeByID(ctx, req.RoomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		return nil, errors.New("room type not found")
+	}
+
+	room := &models.Room{
+		RoomTypeID: req.RoomTypeID,
+		RoomNumber: strings.TrimSpace(req.RoomNumber),
+		Floor:      req.Floor,
+		Features:   normalizeRoomFeatures(req.Features),
+		Notes:      req.Notes,
+	}
+	if room.RoomNumber == "" {
+		return nil, errors.New("room number is required")
+	}
+
+	created, err := s.roomRepo.CreateRoom(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.InvalidateRoomTypeCache(created.RoomTypeID)
+	s.roomChanged(ctx, created.RoomID)
+	return created, nil
+}
+
+// UpdateRoom updates the fields given in the request. A room leaving the active rooms
+// of its room type, retired or moved to another type, must not be occupied and must
+// leave enough rooms for the default allotment and the bookings on every future date;
+// otherwise those dates are returned and nothing is changed.
+func (s *RoomService) UpdateRoom(ctx context.Context, roomID int, req *models.UpdateRoomRequest, updatedBy *int) (*models.Room, []models.InventoryValidationError, error) {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if room == nil {
+		return nil, nil, errors.New("room not found")
+	}
+	previous := *room
+
+	if req.RoomTypeID != nil && *req.RoomTypeID != room.RoomTypeID {
+		rt, err := s.roomRepo.GetRoomTypeByID(ctx, *req.RoomTypeID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rt == nil {
+			return nil, nil, errors.New("room type not found")
+		}
+		room.RoomTypeID = *req.RoomTypeID
+	}
+	if req.RoomNumber != nil {
+		room.RoomNumber = strings.TrimSpace(*req.RoomNumber)
+		if room.RoomNumber == "" {
+			return nil, nil, errors.New("room number must not be empty")
+		}
+	}
+	if req.Floor != nil {
+		room.Floor = *req.Floor
+	}
+	if req.Features != nil {
+		room.Features = normalizeRoomFeatures(req.Features)
+	}
+	if req.Notes != nil {
+		room.Notes = req.Notes
+	}
+	if req.ConnectingRoomID != nil {
+		if *req.ConnectingRoomID == 0 {
+			room.ConnectingRoomID = nil
+		} else {
+			if *req.ConnectingRoomID == roomID {
+				return nil, nil, errors.New("a room cannot connect to itself")
+			}
+			partner, err := s.roomRepo.GetRoomByID(ctx, *req.ConnectingRoomID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if partner == nil {
+				return nil, nil, errors.New("connecting room not found")
+			}
+			room.ConnectingRoomID = req.ConnectingRoomID
+		}
+	}
+	if req.IsActive != nil && *req.IsActive != room.IsActive {
+		room.IsActive = *req.IsActive
+		if room.IsActive {
+			room.HousekeepingStatus = "Dirty"
+		} else {
+			room.HousekeepingStatus = "OutOfService"
+		}
+	}
+
+	if previous.IsActive && (!room.IsActive || room.RoomTypeID != previous.RoomTypeID) {
+		if previous.OccupancyStatus == "Occupied" {
+			return nil, nil, errors.New("an occupied room cannot be retired or moved to another room type")
+		}
+		conflicts, err := s.validateRoomRemoval(ctx, previous.RoomTypeID)
+		if err != nil || len(conflicts) > 0 {
+			return nil, conflicts, err
+		}
+	}
+
+	updated, err := s.roomRepo.UpdateRoom(ctx, room, updatedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if updated == nil {
+		return nil, nil, errors.New("room not found")
+	}
+
+	_ = s.InvalidateRoomTypeCache(previous.RoomTypeID)
+	if updated.RoomTypeID != previous.RoomTypeID {
+		_ = s.InvalidateRoomTypeCache(updated.RoomTypeID)
+	}
+	s.roomChanged(ctx, roomID)
+	return updated, nil, nil
+}
+
+// RetireRoom takes a room out of service for good; its history is kept
+func (s *RoomService) RetireRoom(ctx context.Context, roomID int, retiredBy *int) ([]models.InventoryValidationError, error) {
+	inactive := false
+	_, conflicts, err := s.UpdateRoom(ctx, roomID, &models.UpdateRoomRequest{IsActive: &inactive}, retiredBy)
+	return conflicts, err
+}
+
+// validateRoomRemoval checks that a room type keeps enough active rooms with one fewer
+func (s *RoomService) validateRoomRemoval(ctx context.Context, roomTypeID int) ([]models.InventoryValidationError, error) {
+	rt, err := s.roomRepo.GetRoomTypeByID(ctx, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		return nil, errors.New("room type not found")
+	}
+
+	activeRooms, err := s.roomRepo.CountRooms(ctx, roomTypeID, true)
+	if err != nil {
+		return nil, err
+	}
+	remaining := activeRooms - 1
+	if rt.DefaultAllotment > remaining {
+		return nil, fmt.Errorf("default allotment %d exceeds the %d rooms that would remain; lower the allotment first", rt.DefaultAllotment, remaining)
+	}
+
+	inventory, err := s.roomRepo.GetFutureInventory(ctx, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return inventoryConflicts(inventory, remaining), nil
+}
+
+// GetAllAmenities retrieves every amenity
+func (s *RoomService) GetAllAmenities(ctx context.Context) ([]models.Amenity, error) {
+	return s.roomRepo.GetAllAmenities(ctx)
+}
+
+// CreateAmenity creates an amenity
+func (s *RoomService) CreateAmenity(ctx context.Context, req *models.CreateAmenityRequest) (*models.Amenity, error) {
+	amenity := &models.Amenity{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Icon:        req.Icon,
+		Category:    req.Category,
+	}
+	if amenity.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	return s.roomRepo.CreateAmenity(ctx, amenity)
+}
+
+// UpdateAmenity updates the fields given in the request
+func (s *RoomService) UpdateAmenity(ctx context.Context, amenityID int, req *models.UpdateAmenityRequest) (*models.Amenity, error) {
+	amenity, err := s.roomRepo.GetAmenityByID(ctx, amenityID)
+	if err != nil {
+		return nil, err
+	}
+	if amenity == nil {
+		return nil, errors.New("amenity not found")
+	}
+
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, errors.New("name must not be empty")
+		}
+		amenity.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		amenity.Description = req.Description
+	}
+	if req.Icon != nil {
+		amenity.Icon = req.Icon
+	}
+	if req.Category != nil {
+		amenity.Category = req.Category
+	}
+
+	updated, err := s.roomRepo.UpdateAmenity(ctx, amenity)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, errors.New("amenity not found")
+	}
+
+	_ = s.InvalidateAllRoomTypesCache()
+	return updated, nil
+}
+
+// DeleteAmenity deletes an amenity and removes it from every room type
+func (s *RoomService) DeleteAmenity(ctx context.Context, amenityID int) error {
+	deleted, err := s.roomRepo.DeleteAmenity(ctx, amenityID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("amenity not found")
+	}
+
+	_ = s.InvalidateAllRoomTypesCache()
+	return nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventoryConflicts(t *testing.T) {
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	inventory := []models.RoomInventory{
+		{Date: day, Allotment: 10, BookedCount: 6, TentativeCount: 1},
+		{Date: day.AddDate(0, 0, 1), Allotment: 10, BookedCount: 8, TentativeCount: 0},
+		{Date: day.AddDate(0, 0, 2), Allotment: 10, BookedCount: 7, TentativeCount: 2},
+	}
+
+	conflicts := inventoryConflicts(inventory, 7)
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, "2025-05-02", conflicts[0].Date)
+	assert.Equal(t, "2025-05-03", conflicts[1].Date)
+	assert.Contains(t, conflicts[1].Message, "Current bookings: 9 (booked: 7, tentative: 2)")
+
+	assert.Empty(t, inventoryConflicts(inventory, 9))
+	assert.Empty(t, inventoryConflicts(nil, 0))
+}
+
+func TestValidateRoomType(t *testing.T) {
+	valid := models.RoomType{Name: "Family Room", MaxOccupancy: 4, BaseOccupancy: 2}
+	assert.NoError(t, validateRoomType(&valid))
+
+	noName := valid
+	noName.Name = ""
+	assert.Error(t, validateRoomType(&noName))
+
+	overBase := valid
+	overBase.BaseOccupancy = 5
+	assert.Error(t, validateRoomType(&overBase), "base occupancy above max occupancy")
+
+	negative := valid
+	negative.ExtraBedAllotment = -1
+	assert.Error(t, validateRoomType(&negative))
+}
+
+func TestNormalizeRoomFeatures(t *testing.T) {
+	assert.Equal(t, []string{"Corner", "Connecting"}, normalizeRoomFeatures([]string{" Corner", "", "Connecting", "corner "}))
+
+	features := normalizeRoomFeatures(nil)
+	assert.NotNil(t, features, "rooms.features is NOT NULL")
+	assert.Empty(t, features)
+}
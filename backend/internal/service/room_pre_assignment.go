@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// SetPreAssignment picks the room of an arrival by hand. The room must be of the
+// booked room type and free for the whole stay; the pre-assignment job keeps it.
+func (s *BookingService) SetPreAssignment(ctx context.Context, bookingDetailID, roomID int, assignedBy *int) (*models.RoomPreAssignment, error) {
+	stay, err := s.bookingRepo.GetPreAssignmentStay(ctx, bookingDetailID)
+	if err != nil {
+		return nil, err
+	}
+	if stay == nil {
+		return nil, errors.New("booking detail not found")
+	}
+	if stay.CheckedIn {
+		return nil, errors.New("the guest has already checked in")
+	}
+	if stay.BookingStatus != "Confirmed" {
+		return nil, errors.New("only confirmed bookings can be pre-assigned a room")
+	}
+
+	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, errors.New("room not found")
+	}
+	if !room.IsActive {
+		return nil, errors.New("room is retired")
+	}
+	if room.RoomTypeID != stay.RoomTypeID {
+		return nil, errors.New("room is not of the booked room type")
+	}
+
+	conflicts, err := s.bookingRepo.GetRoomStayConflicts(ctx, roomID, bookingDetailID, stay.CheckInDate, stay.CheckOutDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := roomStayConflictError(conflicts); err != nil {
+		return nil, err
+	}
+
+	return s.bookingRepo.SetPreAssignment(ctx, bookingDetailID, roomID, assignedBy)
+}
+
+// ClearPreAssignment removes the pre-assigned room of an arrival, leaving the room to
+// be picked at check-in or by the next run of the pre-assignment job
+func (s *BookingService) ClearPreAssignment(ctx context.Context, bookingDetailID int) error {
+	found, err := s.bookingRepo.DeletePreAssignment(ctx, bookingDetailID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("pre-assignment not found")
+	}
+	return nil
+}
+
+// SetRoomPreferences replaces the room preferences of a stay, used the next time the
+// pre-assignment job runs
+func (s *BookingService) SetRoomPreferences(ctx context.Context, bookingDetailID int, req *models.SetRoomPreferencesRequest) error {
+	req.ViewPreference = strings.TrimSpace(req.ViewPreference)
+
+	found, err := s.bookingRepo.SetRoomPreferences(ctx, bookingDetailID, req)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("booking detail not found")
+	}
+	return nil
+}
+
+// SetGuestVIP sets or clears the VIP status of a guest
+func (s *BookingService) SetGuestVIP(ctx context.Context, guestID int, vip bool) error {
+	found, err := s.bookingRepo.SetGuestVIP(ctx, guestID, vip)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("guest not found")
+	}
+	return nil
+}
+
+// roomStayConflictError explains why a room cannot be held for a stay
+func roomStayConflictError(conflicts *models.RoomStayConflicts) error {
+	switch {
+	case conflicts.OutOfOrder:
+		return errors.New("room is out of order during the stay")
+	case conflicts.Occupied:
+		return errors.New("room is occupied past the arrival date")
+	case conflicts.PreAssigned:
+		return errors.New("room is pre-assigned to another stay")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomStayConflictError(t *testing.T) {
+	tests := []struct {
+		name      string
+		conflicts models.RoomStayConflicts
+		want      string
+	}{
+		{"free", models.RoomStayConflicts{}, ""},
+		{"out of order", models.RoomStayConflicts{OutOfOrder: true, PreAssigned: true}, "room is out of order during the stay"},
+		{"occupied", models.RoomStayConflicts{Occupied: true}, "room is occupied past the arrival date"},
+		{"pre-assigned", models.RoomStayConflicts{PreAssigned: true}, "room is pre-assigned to another stay"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := roomStayConflictError(&tt.conflicts)
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
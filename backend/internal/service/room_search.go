@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// roomSearchFilter returns the room type filter of a search. Prices sort ascending and
+// size and popularity descending unless the request sets the order.
+func roomSearchFilter(req *models.SearchRoomsRequest) *models.RoomSearchFilter {
+	descending := req.SortOrder == "desc"
+	if req.SortOrder == "" {
+		descending = req.SortBy == models.SearchSortSize || req.SortBy == models.SearchSortPopularity
+	}
+	return &models.RoomSearchFilter{
+		AmenityIDs: req.Amenities,
+		BedType:    req.BedType,
+		View:       req.View,
+		Accessible: req.Accessible,
+		SortBy:     req.SortBy,
+		Descending: descending,
+	}
+}
+
+// filterByPrice keeps the rate plan offers within the price range of the request, per
+// night or for the stay, and drops room types left without an offer. The headline price
+// becomes the first offer kept.
+func filterByPrice(roomTypes []models.RoomType, req *models.SearchRoomsRequest) []models.RoomType {
+	if req.MinPrice == nil && req.MaxPrice == nil {
+		return roomTypes
+	}
+
+	var kept []models.RoomType
+	for _, rt := range roomTypes {
+		var offers []models.RatePlanOffer
+		for _, offer := range rt.RatePlans {
+			price := offer.PricePerNight
+			if req.PriceBasis == models.PriceBasisTotal {
+				price = offer.TotalPrice
+			}
+			if (req.MinPrice != nil && price < *req.MinPrice) || (req.MaxPrice != nil && price > *req.MaxPrice) {
+				continue
+			}
+			offers = append(offers, offer)
+		}
+		if len(offers) == 0 {
+			continue
+		}
+
+		headline := offers[0]
+		rt.RatePlans = offers
+		rt.NightlyPrices = headline.NightlyPrices
+		rt.TotalPrice = &headline.TotalPrice
+		rt.PricePerNight = &headline.PricePerNight
+		kept = append(kept, rt)
+	}
+
+	return kept
+}
+
+// sortByPrice orders priced room types by their headline price, keeping the order of
+// room types at the same price
+func sortByPrice(roomTypes []models.RoomType, descending bool) {
+	sort.SliceStable(roomTypes, func(i, j int) bool {
+		if descending {
+			return *roomTypes[i].TotalPrice > *roomTypes[j].TotalPrice
+		}
+		return *roomTypes[i].TotalPrice < *roomTypes[j].TotalPrice
+	})
+}
+
+// cheapestRoom returns the room type and offer of the cheapest offer of a search, or
+// nil when nothing is priced
+func cheapestRoom(roomTypes []models.RoomType) (*models.RoomType, *models.RatePlanOffer) {
+	var roomType *models.RoomType
+	var cheapest *models.RatePlanOffer
+	for i := range roomTypes {
+		for j := range roomTypes[i].RatePlans {
+			if offer := &roomTypes[i].RatePlans[j]; cheapest == nil || offer.TotalPrice < cheapest.TotalPrice {
+				roomType, cheapest = &roomTypes[i], offer
+			}
+		}
+	}
+	return roomType, cheapest
+}
+
+// flexibleDates prices the same stay with check-in up to FlexibleDateRange days either
+// side of the searched dates, with the same party and filters, skipping past dates.
+// The searched dates reuse the rooms already found.
+func (s *RoomService) flexibleDates(ctx context.Context, req *models.SearchRoomsRequest, checkIn, checkOut time.Time, found []models.RoomType) ([]models.FlexibleDatePrice, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	var grid []models.FlexibleDatePrice
+	for offset := -models.FlexibleDateRange; offset <= models.FlexibleDateRange; offset++ {
+		in := checkIn.AddDate(0, 0, offset)
+		out := checkOut.AddDate(0, 0, offset)
+		if in.Before(today) {
+			continue
+		}
+
+		roomTypes := found
+		if offset != 0 {
+			var err error
+			roomTypes, _, _, err = s.findRoomTypes(ctx, req, in, out)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		price := models.FlexibleDatePrice{
+			CheckIn:    in.Format("2006-01-02"),
+			CheckOut:   out.Format("2006-01-02"),
+			IsSelected: offset == 0,
+		}
+		if roomType, offer := cheapestRoom(roomTypes); offer != nil {
+			total := offer.TotalPrice
+			price.CheapestPrice = &total
+			price.RoomTypeID = &roomType.RoomTypeID
+		}
+		grid = append(grid, price)
+	}
+	return grid, nil
+}
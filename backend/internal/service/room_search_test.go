@@ -0,0 +1,110 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pricedRoomType is a two-night room type with one offer per total price
+func pricedRoomType(id int, totals ...float64) models.RoomType {
+	rt := models.RoomType{RoomTypeID: id}
+	for i, total := range totals {
+		rt.RatePlans = append(rt.RatePlans, models.RatePlanOffer{
+			RatePlanID:    i + 1,
+			TotalPrice:    total,
+			PricePerNight: total / 2,
+		})
+	}
+	rt.TotalPrice = &rt.RatePlans[0].TotalPrice
+	rt.PricePerNight = &rt.RatePlans[0].PricePerNight
+	return rt
+}
+
+func TestRoomSearchFilter_SortOrder(t *testing.T) {
+	assert.False(t, roomSearchFilter(&models.SearchRoomsRequest{SortBy: models.SearchSortPrice}).Descending)
+	assert.True(t, roomSearchFilter(&models.SearchRoomsRequest{SortBy: models.SearchSortSize}).Descending)
+	assert.True(t, roomSearchFilter(&models.SearchRoomsRequest{SortBy: models.SearchSortPopularity}).Descending)
+	assert.False(t, roomSearchFilter(&models.SearchRoomsRequest{SortBy: models.SearchSortPopularity, SortOrder: "asc"}).Descending)
+	assert.True(t, roomSearchFilter(&models.SearchRoomsRequest{SortBy: models.SearchSortPrice, SortOrder: "desc"}).Descending)
+
+	filter := roomSearchFilter(&models.SearchRoomsRequest{Amenities: []int{1, 4}, BedType: "King", View: "Pool", Accessible: true})
+	assert.Equal(t, []int{1, 4}, filter.AmenityIDs)
+	assert.Equal(t, "King", filter.BedType)
+	assert.Equal(t, "Pool", filter.View)
+	assert.True(t, filter.Accessible)
+}
+
+func TestFilterByPrice(t *testing.T) {
+	roomTypes := []models.RoomType{
+		pricedRoomType(1, 2000, 1800),
+		pricedRoomType(2, 5000, 4400),
+		pricedRoomType(3, 9000),
+	}
+
+	// No range keeps everything
+	assert.Len(t, filterByPrice(roomTypes, &models.SearchRoomsRequest{}), 3)
+
+	// Per night: 1000 and 900, 2500 and 2200, 4500
+	kept := filterByPrice(roomTypes, &models.SearchRoomsRequest{MinPrice: floatPtr(950), MaxPrice: floatPtr(2300)})
+	require.Len(t, kept, 2)
+	assert.Equal(t, 1, kept[0].RoomTypeID)
+	assert.Len(t, kept[0].RatePlans, 1)
+	assert.Equal(t, 2000.0, *kept[0].TotalPrice)
+	assert.Equal(t, 2, kept[1].RoomTypeID)
+	require.Len(t, kept[1].RatePlans, 1)
+	assert.Equal(t, 2, kept[1].RatePlans[0].RatePlanID)
+	assert.Equal(t, 4400.0, *kept[1].TotalPrice, "headline moves to the first offer kept")
+
+	// For the stay
+	kept = filterByPrice(roomTypes, &models.SearchRoomsRequest{MinPrice: floatPtr(4500), PriceBasis: models.PriceBasisTotal})
+	require.Len(t, kept, 2)
+	assert.Equal(t, 2, kept[0].RoomTypeID)
+	assert.Equal(t, 5000.0, *kept[0].TotalPrice)
+	assert.Equal(t, 3, kept[1].RoomTypeID)
+
+	// The original room types are not changed
+	assert.Len(t, roomTypes[1].RatePlans, 2)
+	assert.Equal(t, 5000.0, *roomTypes[1].TotalPrice)
+}
+
+func TestSortByPrice(t *testing.T) {
+	roomTypes := []models.RoomType{
+		pricedRoomType(1, 3000),
+		pricedRoomType(2, 1500),
+		pricedRoomType(3, 3000),
+		pricedRoomType(4, 2000),
+	}
+
+	sortByPrice(roomTypes, false)
+	assert.Equal(t, []int{2, 4, 1, 3}, roomTypeIDs(roomTypes))
+
+	sortByPrice(roomTypes, true)
+	assert.Equal(t, []int{1, 3, 4, 2}, roomTypeIDs(roomTypes), "ties keep their order")
+}
+
+func TestCheapestRoom(t *testing.T) {
+	roomType, offer := cheapestRoom(nil)
+	assert.Nil(t, roomType)
+	assert.Nil(t, offer)
+
+	roomType, offer = cheapestRoom([]models.RoomType{
+		pricedRoomType(1, 2000, 1800),
+		pricedRoomType(2, 1900, 1700),
+		pricedRoomType(3, 2500),
+	})
+	require.NotNil(t, offer)
+	assert.Equal(t, 2, roomType.RoomTypeID)
+	assert.Equal(t, 2, offer.RatePlanID)
+	assert.Equal(t, 1700.0, offer.TotalPrice)
+}
+
+func roomTypeIDs(roomTypes []models.RoomType) []int {
+	ids := make([]int, len(roomTypes))
+	for i, rt := range roomTypes {
+		ids[i] = rt.RoomTypeID
+	}
+	return ids
+}
@@ -9,12 +9,17 @@ import (
 	"github.com/hotel-booking-system/backend/internal/models"
 	"github.com/hotel-booking-system/backend/internal/repository"
 	"github.com/hotel-booking-system/backend/pkg/cache"
+	"github.com/hotel-booking-system/backend/pkg/storage"
 )
 
 // RoomService handles room business logic
 type RoomService struct {
 	roomRepo *repository.RoomRepository
 	cache    *cache.RedisCache
+	listener InventoryListener
+	blobs    storage.BlobStore // Room type images; nil when uploads are not configured
+
+	roomEvents RoomChangeListener
 }
 
 // NewRoomService creates a new room service
@@ -25,6 +30,23 @@ func NewRoomService(roomRepo *repository.RoomRepository, redisCache *cache.Redis
 	}
 }
 
+// SetInventoryListener registers a listener notified when a room type's allotment is raised
+func (s *RoomService) SetInventoryListener(listener InventoryListener) {
+	s.listener = listener
+}
+
+// SetRoomChangeListener registers a listener notified when rooms are added or changed
+func (s *RoomService) SetRoomChangeListener(listener RoomChangeListener) {
+	s.roomEvents = listener
+}
+
+// roomChanged tells the room change listener a room was added or changed
+func (s *RoomService) roomChanged(ctx context.Context, roomID int) {
+	if s.roomEvents != nil {
+		s.roomEvents.RoomsChanged(ctx, models.RoomEventStatus, roomID)
+	}
+}
+
 // SearchAvailableRooms searches for available rooms and calculates prices
 func (s *RoomService) SearchAvailableRooms(ctx context.Context, req *models.SearchRoomsRequest) (*models.SearchRoomsResponse, error) {
 	// Parse dates
@@ -49,19 +71,84 @@ func (s *RoomService) SearchAvailableRooms(ctx context.Context, req *models.Sear
 		return nil, errors.New("วันที่ check-in ต้องไม่อยู่ในอดีต")
 	}
 
+	// Resolve the party: guests per room, or a total party over a number of rooms
+	guests, rooms := req.Guests, req.Rooms
+	if len(req.Occupancy) > 0 {
+		guests, rooms = 0, len(req.Occupancy)
+		for _, n := range req.Occupancy {
+			guests += n
+		}
+	}
+	if guests == 0 {
+		return nil, errors.New("กรุณาระบุจำนวนผู้เข้าพัก")
+	}
+	if rooms == 0 {
+		rooms = 1
+	}
+	if rooms > guests {
+		return nil, errors.New("จำนวนห้องต้องไม่เกินจำนวนผู้เข้าพัก")
+	}
+	if len(req.Occupancy) == 1 {
+		req.Occupancy = nil
+	}
+	req.Guests = guests
+
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		return nil, errors.New("ราคาต่ำสุดต้องไม่เกินราคาสูงสุด")
+	}
+
+	// Validate the children of the party
+	if len(req.ChildAges) >= guests {
+		return nil, errors.New("at least one adult is required")
+	}
+	if len(req.ChildAges) > guests-rooms {
+		return nil, errors.New("each room requires at least one adult")
+	}
+	for _, age := range req.ChildAges {
+		if age < 0 || age > models.MaxChildAge {
+			return nil, fmt.Errorf("child age must be between 0 and %d", models.MaxChildAge)
+		}
+	}
+
 	// Calculate total nights
 	totalNights := int(checkOut.Sub(checkIn).Hours() / 24)
 
-	// Search available rooms
-	roomTypes, err := s.roomRepo.SearchAvailableRooms(ctx, checkIn, checkOut, req.Guests)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search rooms from repository: %w", err)
+	// Several rooms are searched as combinations of room types
+	if rooms > 1 {
+		combinations, roomTypes, err := s.findRoomCombinations(ctx, req, checkIn, checkOut, guests, rooms)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.enrichAmenities(ctx, roomTypes); err != nil {
+			return nil, err
+		}
+
+		response := &models.SearchRoomsResponse{
+			RoomTypes:    roomTypes,
+			CheckIn:      req.CheckIn,
+			CheckOut:     req.CheckOut,
+			Guests:       guests,
+			Rooms:        rooms,
+			TotalNights:  totalNights,
+			Combinations: combinations,
+		}
+		if len(combinations) == 0 {
+			if response.Alternatives, err = s.findAlternatives(ctx, req, checkIn, checkOut, guests, rooms); err != nil {
+				return nil, err
+			}
+			response.AlternativeDates = alternativeCheckIns(response.Alternatives)
+			response.WaitlistAvailable = true
+		}
+		return response, nil
 	}
 
-	// Get default rate plan
-	ratePlanID, err := s.roomRepo.GetDefaultRatePlanID(ctx)
+	// Search and price available rooms
+	roomTypes, restricted, priced, err := s.findRoomTypes(ctx, req, checkIn, checkOut)
 	if err != nil {
-		// If no rate plan exists, return rooms without pricing
+		return nil, err
+	}
+	if !priced {
+		// If no rate plan can be sold, return rooms without pricing
 		return &models.SearchRoomsResponse{
 			RoomTypes:   roomTypes,
 			CheckIn:     req.CheckIn,
@@ -71,48 +158,284 @@ func (s *RoomService) SearchAvailableRooms(ctx context.Context, req *models.Sear
 		}, nil
 	}
 
-	// Enrich room types with amenities and pricing
+	// Enrich room types with amenities
+	if err := s.enrichAmenities(ctx, roomTypes); err != nil {
+		return nil, err
+	}
+
+	response := &models.SearchRoomsResponse{
+		RoomTypes:           roomTypes,
+		CheckIn:             req.CheckIn,
+		CheckOut:            req.CheckOut,
+		Guests:              req.Guests,
+		TotalNights:         totalNights,
+		RestrictedRoomTypes: restricted,
+	}
+
+	// Cheapest prices of nearby dates
+	if req.Flexible {
+		response.FlexibleDates, err = s.flexibleDates(ctx, req, checkIn, checkOut, roomTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A party no single room fits may still fit in several
+	if len(roomTypes) == 0 && guests > 1 && len(req.ChildAges) <= guests-2 {
+		combinations, _, err := s.findRoomCombinations(ctx, req, checkIn, checkOut, guests, 0)
+		if err != nil {
+			return nil, err
+		}
+		response.Combinations = combinations
+	}
+
+	// If no rooms found, suggest available alternative dates and split stays
+	if len(roomTypes) == 0 && len(response.Combinations) == 0 {
+		if response.Alternatives, err = s.findAlternatives(ctx, req, checkIn, checkOut, guests, 1); err != nil {
+			return nil, err
+		}
+		response.AlternativeDates = alternativeCheckIns(response.Alternatives)
+		response.WaitlistAvailable = true
+	}
+
+	return response, nil
+}
+
+// findRoomTypes searches the room types that fit the party in one room and match the
+// filters of the request, then prices them. Returns priced false, with the rooms
+// unpriced, when no rate plan qualifies for the stay.
+func (s *RoomService) findRoomTypes(ctx context.Context, req *models.SearchRoomsRequest, checkIn, checkOut time.Time) ([]models.RoomType, []models.RestrictedRoomType, bool, error) {
+	filter := roomSearchFilter(req)
+	roomTypes, err := s.roomRepo.SearchAvailableRooms(ctx, checkIn, checkOut, req.Guests, filter)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to search rooms from repository: %w", err)
+	}
+
+	// Get the rate plans this stay qualifies for
+	ratePlans, err := s.eligibleRatePlans(ctx, req, checkIn, checkOut)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if len(ratePlans) == 0 {
+		return roomTypes, nil, false, nil
+	}
+
+	// Price the party in each room type under every plan its stay restrictions allow,
+	// dropping room types no plan can sell and keeping the reasons for the guest
+	roomTypes, restricted, err := s.priceRoomTypes(ctx, roomTypes, ratePlans, checkIn, checkOut, req.Guests, req.ChildAges)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	roomTypes = filterByPrice(roomTypes, req)
+	if filter.SortBy == models.SearchSortPrice {
+		sortByPrice(roomTypes, filter.Descending)
+	}
+
+	return roomTypes, restricted, true, nil
+}
+
+// enrichAmenities loads the amenities of each room type
+func (s *RoomService) enrichAmenities(ctx context.Context, roomTypes []models.RoomType) error {
 	for i := range roomTypes {
-		// Get amenities
 		amenities, err := s.roomRepo.GetRoomTypeAmenities(ctx, roomTypes[i].RoomTypeID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get amenities: %w", err)
+			return fmt.Errorf("failed to get amenities: %w", err)
 		}
 		roomTypes[i].Amenities = amenities
+	}
+	return nil
+}
+
+// eligibleRatePlans returns the active rate plans whose booking rules the stay meets
+func (s *RoomService) eligibleRatePlans(ctx context.Context, req *models.SearchRoomsRequest, checkIn, checkOut time.Time) ([]models.RatePlan, error) {
+	plans, err := s.roomRepo.GetActiveRatePlans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate plans: %w", err)
+	}
+
+	return filterEligibleRatePlans(plans, models.RatePlanEligibility{
+		BookingDate: time.Now(),
+		CheckIn:     checkIn,
+		CheckOut:    checkOut,
+		NumGuests:   req.Guests,
+		IsMember:    req.IsMember,
+	}), nil
+}
+
+// filterEligibleRatePlans keeps the plans whose booking rules a stay meets
+func filterEligibleRatePlans(plans []models.RatePlan, stay models.RatePlanEligibility) []models.RatePlan {
+	var eligible []models.RatePlan
+	for i := range plans {
+		if len(checkRatePlanEligibility(&plans[i], stay)) == 0 {
+			eligible = append(eligible, plans[i])
+		}
+	}
+	return eligible
+}
+
+// priceRoomTypes builds the rate plan offers of each room type. Plans closed by stay
+// restrictions are skipped; room types without any offer are returned as restricted.
+// The headline price is the first offer (the default plan when it qualifies).
+// Prices include the long-stay rate the stay reaches and the occupancy surcharges of
+// the party; room types the party does not fit are dropped.
+func (s *RoomService) priceRoomTypes(ctx context.Context, roomTypes []models.RoomType, ratePlans []models.RatePlan, checkIn, checkOut time.Time, guests int, childAges []int) ([]models.RoomType, []models.RestrictedRoomType, error) {
+	if len(roomTypes) == 0 {
+		return roomTypes, nil, nil
+	}
+
+	ids := make([]int, len(roomTypes))
+	for i, rt := range roomTypes {
+		ids[i] = rt.RoomTypeID
+	}
+
+	restrictions, err := s.roomRepo.GetStayRestrictions(ctx, ids, checkIn, checkOut)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get stay restrictions: %w", err)
+	}
+	grouped := groupRestrictionsByRoomType(restrictions)
 
-		// Get nightly prices
-		nightlyPrices, err := s.roomRepo.GetNightlyPrices(ctx, roomTypes[i].RoomTypeID, ratePlanID, checkIn, checkOut)
+	planIDs := make([]int, len(ratePlans))
+	for i, plan := range ratePlans {
+		planIDs[i] = plan.RatePlanID
+	}
+	longStayRates, err := s.roomRepo.GetLongStayRates(ctx, planIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalNights := int(checkOut.Sub(checkIn).Hours() / 24)
+	var priced []models.RoomType
+	var restricted []models.RestrictedRoomType
+	for _, rt := range roomTypes {
+		occupancy, err := resolveOccupancy(&rt, guests, childAges)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get prices: %w", err)
+			continue
 		}
-		roomTypes[i].NightlyPrices = nightlyPrices
 
-		// Calculate total price
-		var totalPrice float64
-		for _, price := range nightlyPrices {
-			totalPrice += price.Price
+		surcharges, err := s.roomRepo.GetOccupancySurcharges(ctx, rt.RoomTypeID, checkIn, checkOut, occupancy)
+		if err != nil {
+			return nil, nil, err
 		}
-		roomTypes[i].TotalPrice = &totalPrice
 
-		// Calculate average price per night
-		if totalNights > 0 {
-			avgPrice := totalPrice / float64(totalNights)
-			roomTypes[i].PricePerNight = &avgPrice
+		var offers []models.RatePlanOffer
+		var reasons []models.RestrictionViolation
+
+		for _, plan := range ratePlans {
+			ratePlanID := plan.RatePlanID
+			if violations := evaluateRestrictions(grouped[rt.RoomTypeID], &ratePlanID, checkIn, checkOut); len(violations) > 0 {
+				if reasons == nil {
+					reasons = violations
+				}
+				continue
+			}
+
+			nightlyPrices, err := s.roomRepo.GetNightlyPrices(ctx, rt.RoomTypeID, plan.RatePlanID, checkIn, checkOut)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get prices: %w", err)
+			}
+
+			longStay := selectLongStayRate(longStayRates, plan.RatePlanID, rt.RoomTypeID, totalNights)
+			applyLongStayRate(nightlyPrices, longStay)
+			totalPrice := applySurcharges(nightlyPrices, surcharges)
+
+			offer := models.RatePlanOffer{
+				RatePlanID:    plan.RatePlanID,
+				Name:          plan.Name,
+				Description:   plan.Description,
+				PolicyID:      plan.PolicyID,
+				MembersOnly:   plan.MembersOnly,
+				BillingCycle:  plan.BillingCycle,
+				LongStayRate:  longStay,
+				TotalPrice:    totalPrice,
+				NightlyPrices: nightlyPrices,
+			}
+			if totalNights > 0 {
+				offer.PricePerNight = totalPrice / float64(totalNights)
+			}
+			offers = append(offers, offer)
 		}
+
+		if len(offers) == 0 {
+			restricted = append(restricted, models.RestrictedRoomType{
+				RoomTypeID:   rt.RoomTypeID,
+				RoomTypeName: rt.Name,
+				Reasons:      reasons,
+			})
+			continue
+		}
+
+		headline := offers[0]
+		rt.RatePlans = offers
+		rt.NightlyPrices = headline.NightlyPrices
+		rt.TotalPrice = &headline.TotalPrice
+		rt.PricePerNight = &headline.PricePerNight
+		priced = append(priced, rt)
 	}
 
-	response := &models.SearchRoomsResponse{
-		RoomTypes:   roomTypes,
-		CheckIn:     req.CheckIn,
-		CheckOut:    req.CheckOut,
-		Guests:      req.Guests,
-		TotalNights: totalNights,
+	return priced, restricted, nil
+}
+
+// SearchDayUse lists the day-use slots of a date for every room type that fits the party
+func (s *RoomService) SearchDayUse(ctx context.Context, req *models.DayUseSearchRequest) (*models.DayUseSearchResponse, error) {
+	day, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("รูปแบบวันที่ไม่ถูกต้อง")
+	}
+	now := hotelNow()
+	if day.Before(now.Truncate(24 * time.Hour)) {
+		return nil, errors.New("วันที่ต้องไม่อยู่ในอดีต")
 	}
 
-	// If no rooms found, suggest alternative dates
-	if len(roomTypes) == 0 {
-		alternatives := s.generateAlternativeDates(checkIn)
-		response.AlternativeDates = alternatives
+	rates, err := s.roomRepo.GetDayUseRates(ctx, req.RoomTypeID, req.DurationHours)
+	if err != nil {
+		return nil, err
+	}
+
+	roomTypes, err := s.roomRepo.GetAllRoomTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.RoomType, len(roomTypes))
+	for _, rt := range roomTypes {
+		byID[rt.RoomTypeID] = rt
+	}
+
+	response := &models.DayUseSearchResponse{
+		Date:      req.Date,
+		Guests:    req.Guests,
+		RoomTypes: []models.DayUseAvailability{},
+	}
+
+	// Rates are ordered by room type and plan, so consecutive rates share an entry
+	for _, rate := range rates {
+		roomType, ok := byID[rate.RoomTypeID]
+		if !ok || roomType.MaxOccupancy < req.Guests {
+			continue
+		}
+
+		starts := dayUseSlotStarts(day, rate.DurationHours, now)
+		if len(starts) == 0 {
+			continue
+		}
+		available, err := s.roomRepo.GetDayUseAvailability(ctx, rate.RoomTypeID, starts, rate.DurationHours)
+		if err != nil {
+			return nil, err
+		}
+
+		n := len(response.RoomTypes)
+		if n == 0 || response.RoomTypes[n-1].RoomTypeID != rate.RoomTypeID || response.RoomTypes[n-1].RatePlanID != rate.RatePlanID {
+			response.RoomTypes = append(response.RoomTypes, models.DayUseAvailability{
+				RoomTypeID:   rate.RoomTypeID,
+				RoomTypeName: rate.RoomTypeName,
+				MaxOccupancy: roomType.MaxOccupancy,
+				RatePlanID:   rate.RatePlanID,
+				RatePlanName: rate.RatePlanName,
+			})
+			n++
+		}
+		entry := &response.RoomTypes[n-1]
+		entry.Slots = append(entry.Slots, buildDayUseSlots(rate, starts, available)...)
 	}
 
 	return response, nil
@@ -181,6 +504,16 @@ func (s *RoomService) GetRoomTypeByID(ctx context.Context, roomTypeID int) (*mod
 	}
 	roomType.Amenities = amenities
 
+	// Get the gallery; its first image replaces the default image
+	images, err := s.GetRoomTypeImages(ctx, roomTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images: %w", err)
+	}
+	roomType.Images = images
+	if len(images) > 0 && images[0].MediumURL != "" {
+		roomType.ImageURL = &images[0].MediumURL
+	}
+
 	// Get rooms
 	rooms, err := s.roomRepo.GetRoomsByRoomType(ctx, roomTypeID)
 	if err != nil {
@@ -240,6 +573,13 @@ func (s *RoomService) GetRoomTypePricing(ctx context.Context, roomTypeID int, ch
 	if err != nil {
 		return nil, fmt.Errorf("failed to get prices: %w", err)
 	}
+
+	longStayRates, err := s.roomRepo.GetLongStayRates(ctx, []int{ratePlanID})
+	if err != nil {
+		return nil, err
+	}
+	nights := int(checkOutDate.Sub(checkInDate).Hours() / 24)
+	applyLongStayRate(nightlyPrices, selectLongStayRate(longStayRates, ratePlanID, roomTypeID, nights))
 	roomType.NightlyPrices = nightlyPrices
 
 	// Calculate total price
@@ -259,27 +599,6 @@ func (s *RoomService) GetRoomTypePricing(ctx context.Context, roomTypeID int, ch
 	return roomType, nil
 }
 
-// generateAlternativeDates generates alternative date suggestions (±3 days)
-func (s *RoomService) generateAlternativeDates(checkIn time.Time) []string {
-	alternatives := make([]string, 0, 6)
-	
-	// -3 to -1 days
-	for i := -3; i <= -1; i++ {
-		date := checkIn.AddDate(0, 0, i)
-		if date.After(time.Now()) {
-			alternatives = append(alternatives, date.Format("2006-01-02"))
-		}
-	}
-	
-	// +1 to +3 days
-	for i := 1; i <= 3; i++ {
-		date := checkIn.AddDate(0, 0, i)
-		alternatives = append(alternatives, date.Format("2006-01-02"))
-	}
-	
-	return alternatives
-}
-
 // GetAllRoomsWithStatus retrieves all rooms with their current status
 func (s *RoomService) GetAllRoomsWithStatus(ctx context.Context) (*models.RoomStatusDashboardResponse, error) {
 	rooms, err := s.roomRepo.GetAllRoomsWithStatus(ctx)
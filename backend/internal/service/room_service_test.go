@@ -474,23 +474,3 @@ func TestRoomService_GetAllRoomsWithStatus(t *testing.T) {
 		})
 	}
 }
-
-// TestGenerateAlternativeDates tests the alternative date generation
-func TestGenerateAlternativeDates(t *testing.T) {
-	service := NewRoomService(nil)
-	
-	// Test with a future date
-	futureDate := time.Now().AddDate(0, 0, 10)
-	alternatives := service.generateAlternativeDates(futureDate)
-	
-	// Should have 6 alternatives (3 before, 3 after)
-	assert.Equal(t, 6, len(alternatives))
-	
-	// Test with a date close to today
-	nearDate := time.Now().AddDate(0, 0, 2)
-	alternatives = service.generateAlternativeDates(nearDate)
-	
-	// Should have fewer alternatives (only future dates)
-	assert.LessOrEqual(t, len(alternatives), 6)
-	assert.Greater(t, len(alternatives), 0)
-}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// DefaultRoomHistoryLimit is the number of status changes returned for a room when no
+// limit is given
+const DefaultRoomHistoryLimit = 100
+
+// unknownAttendant names the cleaning times that have no staff member recorded
+const unknownAttendant = "Unknown"
+
+// summarizeCleaningTimes works out the mean cleaning time overall and per attendant,
+// attendants by name
+func summarizeCleaningTimes(times []models.CleaningTime) (float64, []models.AttendantCleaningTimes) {
+	attendants := []models.AttendantCleaningTimes{}
+	if len(times) == 0 {
+		return 0, attendants
+	}
+
+	index := make(map[int]int) // staff ID (0 when unknown) to its attendant
+	totals := make(map[int]float64)
+	var total float64
+	for _, t := range times {
+		minutes := t.CompletedAt.Sub(t.StartedAt).Minutes()
+		total += minutes
+
+		key := 0
+		if t.StaffID != nil {
+			key = *t.StaffID
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(attendants)
+			index[key] = i
+			name := unknownAttendant
+			if t.StaffName != nil {
+				name = *t.StaffName
+			}
+			attendants = append(attendants, models.AttendantCleaningTimes{
+				StaffID:         t.StaffID,
+				StaffName:       name,
+				ShortestMinutes: math.Inf(1),
+			})
+		}
+
+		a := &attendants[i]
+		a.RoomsCleaned++
+		totals[key] += minutes
+		a.ShortestMinutes = math.Min(a.ShortestMinutes, roundMinutes(minutes))
+		a.LongestMinutes = math.Max(a.LongestMinutes, roundMinutes(minutes))
+	}
+
+	for key, i := range index {
+		a := &attendants[i]
+		a.MeanMinutes = roundMinutes(totals[key] / float64(a.RoomsCleaned))
+		a.TotalCleaningHours = roundHours(totals[key] / 60)
+	}
+	sort.SliceStable(attendants, func(i, j int) bool {
+		return attendants[i].StaffName < attendants[j].StaffName
+	})
+
+	return roundMinutes(total / float64(len(times))), attendants
+}
+
+// roundMinutes rounds a number of minutes to one decimal
+func roundMinutes(minutes float64) float64 {
+	return math.Round(minutes*10) / 10
+}
+
+// GetRoomStatusHistory retrieves the latest housekeeping and occupancy status changes
+// of a room, newest first
+func (s *HousekeepingService) GetRoomStatusHistory(ctx context.Context, roomID, limit int) ([]models.RoomStatusUpdate, error) {
+	room, err := s.housekeepingRepo.GetHousekeepingTask(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, errors.New("room not found")
+	}
+
+	if limit <= 0 {
+		limit = DefaultRoomHistoryLimit
+	}
+	return s.housekeepingRepo.GetRoomStatusHistory(ctx, roomID, limit)
+}
+
+// GetCleaningTimeReport reports how long rooms took from Cleaning to Clean per
+// attendant for rooms finished between the two dates
+func (s *HousekeepingService) GetCleaningTimeReport(ctx context.Context, startDate, endDate time.Time) (*models.CleaningTimeReport, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+
+	times, err := s.housekeepingRepo.GetCleaningTimes(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	mean, attendants := summarizeCleaningTimes(times)
+	return &models.CleaningTimeReport{
+		StartDate:    startDate.Format("2006-01-02"),
+		EndDate:      endDate.Format("2006-01-02"),
+		RoomsCleaned: len(times),
+		MeanMinutes:  mean,
+		Attendants:   attendants,
+	}, nil
+}
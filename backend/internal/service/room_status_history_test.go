@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeCleaningTimes(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	somchai, malee := 3, 7
+	somchaiName, maleeName := "Somchai K", "Malee P"
+	times := []models.CleaningTime{
+		{StaffID: &somchai, StaffName: &somchaiName, RoomID: 1, StartedAt: start, CompletedAt: start.Add(30 * time.Minute)},
+		{StaffID: &malee, StaffName: &maleeName, RoomID: 2, StartedAt: start, CompletedAt: start.Add(20 * time.Minute)},
+		{StaffID: &somchai, StaffName: &somchaiName, RoomID: 3, StartedAt: start, CompletedAt: start.Add(50 * time.Minute)},
+		{RoomID: 4, StartedAt: start, CompletedAt: start.Add(40 * time.Minute)},
+	}
+
+	mean, attendants := summarizeCleaningTimes(times)
+
+	assert.Equal(t, 35.0, mean)
+	require.Len(t, attendants, 3)
+
+	assert.Equal(t, "Malee P", attendants[0].StaffName)
+	assert.Equal(t, 1, attendants[0].RoomsCleaned)
+	assert.Equal(t, 20.0, attendants[0].ShortestMinutes)
+
+	assert.Equal(t, "Somchai K", attendants[1].StaffName)
+	assert.Equal(t, 2, attendants[1].RoomsCleaned)
+	assert.Equal(t, 40.0, attendants[1].MeanMinutes)
+	assert.Equal(t, 30.0, attendants[1].ShortestMinutes)
+	assert.Equal(t, 50.0, attendants[1].LongestMinutes)
+	assert.Equal(t, 1.33, attendants[1].TotalCleaningHours)
+
+	assert.Equal(t, unknownAttendant, attendants[2].StaffName)
+	assert.Nil(t, attendants[2].StaffID)
+}
+
+func TestSummarizeCleaningTimesEmpty(t *testing.T) {
+	mean, attendants := summarizeCleaningTimes(nil)
+
+	assert.Zero(t, mean)
+	assert.NotNil(t, attendants)
+	assert.Empty(t, attendants)
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// checkUpgrade checks that a booked room can be upgraded on today and returns the first
+// upgraded night, the arrival or today for a guest already in house, and how many
+// nights of the stay are left from it
+func checkUpgrade(stay *models.UpgradeStay, today time.Time) (time.Time, int, error) {
+	if stay.StayType != models.StayTypeOvernight {
+		return time.Time{}, 0, errors.New("only overnight stays can be upgraded")
+	}
+	if stay.BookingStatus != "Confirmed" && stay.BookingStatus != "CheckedIn" {
+		return time.Time{}, 0, errors.New("only confirmed or in-house bookings can be upgraded")
+	}
+	if stay.BookingStatus == "CheckedIn" && !stay.CheckedIn {
+		return time.Time{}, 0, errors.New("the room has already checked out")
+	}
+
+	from := dateOnly(stay.CheckInDate)
+	if today := dateOnly(today); today.After(from) {
+		from = today
+	}
+	nights := nightsBetween(from, stay.CheckOutDate)
+	if nights <= 0 {
+		return time.Time{}, 0, errors.New("no nights of the stay are left to upgrade")
+	}
+	return from, nights, nil
+}
+
+// priceUpgradeOffer prices an upgrade offer at the difference between the nightly
+// rates of the upgraded and the booked room type, averaged per night. ok is false when
+// the upgraded room type has no rate on a night or costs no more than the booked one.
+func priceUpgradeOffer(booked, upgraded []models.NightlyPrice) (perNight, total float64, ok bool) {
+	if len(upgraded) == 0 || len(booked) != len(upgraded) {
+		return 0, 0, false
+	}
+
+	var difference float64
+	for i, night := range upgraded {
+		if night.Price <= 0 {
+			return 0, 0, false
+		}
+		difference += night.Price - booked[i].Price
+	}
+	if difference <= 0 {
+		return 0, 0, false
+	}
+
+	perNight = roundAmount(difference / float64(len(upgraded)))
+	return perNight, roundAmount(perNight * float64(len(upgraded))), true
+}
+
+// higherRoomType reports whether upgraded is a higher category than booked, a dearer
+// base price as for upgrade offers
+func higherRoomType(upgraded, booked *models.RoomType) bool {
+	if booked == nil || booked.BasePrice == nil || upgraded.BasePrice == nil {
+		return false
+	}
+	return *upgraded.BasePrice > *booked.BasePrice
+}
+
+// UpgradeRoom upgrades a booked room to a higher room type for the rest of the stay,
+// free or for a charge per night. The booking keeps its rate. A guest already in house
+// moves to req.RoomID, a room of the new type.
+func (s *BookingService) UpgradeRoom(ctx context.Context, req *models.UpgradeRoomRequest, staffID *int) (*models.RoomUpgrade, error) {
+	if req.UpgradeType == models.UpgradeTypeComplimentary && req.ChargePerNight != 0 {
+		return nil, errors.New("a complimentary upgrade has no charge")
+	}
+	if req.UpgradeType == models.UpgradeTypePaid && req.ChargePerNight <= 0 {
+		return nil, errors.New("a paid upgrade needs a charge per night")
+	}
+
+	upgrade := &models.RoomUpgrade{
+		UpgradeType:    req.UpgradeType,
+		Source:         models.UpgradeSourceStaff,
+		ChargePerNight: roundAmount(req.ChargePerNight),
+		UpgradedBy:     staffID,
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		upgrade.Reason = &reason
+	}
+	return s.upgradeRoom(ctx, req.BookingDetailID, req.RoomTypeID, req.RoomID, upgrade)
+}
+
+// upgradeRoom checks that a booked room can move to roomTypeID and records upgrade.
+// roomID is the room an in-house guest moves to and is ignored before arrival.
+func (s *BookingService) upgradeRoom(ctx context.Context, bookingDetailID, roomTypeID int, roomID *int, upgrade *models.RoomUpgrade) (*models.RoomUpgrade, error) {
+	stay, err := s.bookingRepo.GetUpgradeStay(ctx, bookingDetailID)
+	if err != nil {
+		return nil, err
+	}
+	if stay == nil {
+		return nil, errors.New("booking detail not found")
+	}
+	from, nights, err := checkUpgrade(stay, time.Now().Truncate(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if stay.RoomTypeID == roomTypeID {
+		return nil, errors.New("the room is already of this room type")
+	}
+	if !stay.CheckedIn {
+		roomID = nil
+	} else if roomID == nil {
+		return nil, errors.New("a guest already in house needs a room of the new room type to move to")
+	}
+
+	current, err := s.roomRepo.GetRoomTypeByID(ctx, stay.RoomTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	roomType, err := s.roomRepo.GetRoomTypeByID(ctx, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if roomType == nil {
+		return nil, errors.New("room type not found")
+	}
+	if !higherRoomType(roomType, current) {
+		return nil, fmt.Errorf("%s is not a higher room type than %s", roomType.Name, stay.RoomTypeName)
+	}
+	if roomType.MaxOccupancy < stay.NumGuests {
+		return nil, fmt.Errorf("%s sleeps %d guests but %d are booked", roomType.Name, roomType.MaxOccupancy, stay.NumGuests)
+	}
+
+	upgrade.BookingDetailID = stay.BookingDetailID
+	upgrade.FromRoomTypeID = stay.RoomTypeID
+	upgrade.FromRoomTypeName = stay.RoomTypeName
+	upgrade.ToRoomTypeID = roomType.RoomTypeID
+	upgrade.ToRoomTypeName = roomType.Name
+	upgrade.StartDate = from
+	upgrade.EndDate = stay.CheckOutDate
+	upgrade.Nights = nights
+	upgrade.TotalCharge = roundAmount(upgrade.ChargePerNight * float64(nights))
+
+	if err := s.bookingRepo.UpgradeRoomType(ctx, upgrade, stay.BookingID, roomID); err != nil {
+		return nil, err
+	}
+
+	// The old room type has a room free again on the upgraded nights
+	if s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+	return upgrade, nil
+}
+
+// GetUpgradeOffers lists the upgrades the guest can buy for the rooms of a booking that
+// have not arrived yet. A guestID of 0 skips the ownership check (staff access).
+func (s *BookingService) GetUpgradeOffers(ctx context.Context, bookingID int, guestID int) ([]models.UpgradeOffer, error) {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, nil
+	}
+
+	if guestID != 0 && booking.GuestID != nil && *booking.GuestID != guestID {
+		return nil, errors.New("unauthorized to view this booking")
+	}
+
+	offers := []models.UpgradeOffer{}
+	if booking.Status != "Confirmed" {
+		return offers, nil
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	for _, detail := range booking.Details {
+		if detail.StayType != models.StayTypeOvernight || detail.RoomNumber != nil || detail.CheckInDate.Before(today) {
+			continue
+		}
+		detailOffers, err := s.detailUpgradeOffers(ctx, &detail)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, detailOffers...)
+	}
+	return offers, nil
+}
+
+// detailUpgradeOffers prices the room types a booked room can be upgraded to
+func (s *BookingService) detailUpgradeOffers(ctx context.Context, detail *models.BookingDetailWithGuests) ([]models.UpgradeOffer, error) {
+	roomTypes, err := s.roomRepo.GetUpgradeRoomTypes(ctx, detail.RoomTypeID, detail.NumGuests, detail.CheckInDate, detail.CheckOutDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(roomTypes) == 0 {
+		return nil, nil
+	}
+
+	booked, err := s.roomRepo.GetNightlyPrices(ctx, detail.RoomTypeID, detail.RatePlanID, detail.CheckInDate, detail.CheckOutDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var offers []models.UpgradeOffer
+	for _, rt := range roomTypes {
+		upgraded, err := s.roomRepo.GetNightlyPrices(ctx, rt.RoomTypeID, detail.RatePlanID, detail.CheckInDate, detail.CheckOutDate)
+		if err != nil {
+			return nil, err
+		}
+		perNight, total, ok := priceUpgradeOffer(booked, upgraded)
+		if !ok {
+			continue
+		}
+		offers = append(offers, models.UpgradeOffer{
+			BookingDetailID:  detail.BookingDetailID,
+			FromRoomTypeID:   detail.RoomTypeID,
+			FromRoomTypeName: detail.RoomTypeName,
+			RoomTypeID:       rt.RoomTypeID,
+			RoomTypeName:     rt.Name,
+			Description:      rt.Description,
+			ImageURL:         rt.ImageURL,
+			MaxOccupancy:     rt.MaxOccupancy,
+			AvailableRooms:   *rt.AvailableRooms,
+			Nights:           len(upgraded),
+			PricePerNight:    perNight,
+			TotalPrice:       total,
+		})
+	}
+	return offers, nil
+}
+
+// AcceptUpgradeOffer buys an upgrade offer at its current price. A guestID of 0 skips
+// the ownership check (staff access).
+func (s *BookingService) AcceptUpgradeOffer(ctx context.Context, bookingID int, guestID int, req *models.AcceptUpgradeOfferRequest) (*models.RoomUpgrade, error) {
+	offers, err := s.GetUpgradeOffers(ctx, bookingID, guestID)
+	if err != nil {
+		return nil, err
+	}
+	if offers == nil {
+		return nil, errors.New("booking not found")
+	}
+
+	for _, offer := range offers {
+		if offer.BookingDetailID != req.BookingDetailID || offer.RoomTypeID != req.RoomTypeID {
+			continue
+		}
+		return s.upgradeRoom(ctx, offer.BookingDetailID, offer.RoomTypeID, nil, &models.RoomUpgrade{
+			UpgradeType:    models.UpgradeTypePaid,
+			Source:         models.UpgradeSourceUpsell,
+			ChargePerNight: offer.PricePerNight,
+		})
+	}
+	return nil, errors.New("this upgrade is no longer offered")
+}
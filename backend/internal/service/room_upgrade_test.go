@@ -0,0 +1,112 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckUpgrade(t *testing.T) {
+	stay := func() *models.UpgradeStay {
+		return &models.UpgradeStay{
+			CheckInDate:   addOnDate("2025-07-04"),
+			CheckOutDate:  addOnDate("2025-07-08"),
+			StayType:      models.StayTypeOvernight,
+			BookingStatus: "Confirmed",
+		}
+	}
+
+	from, nights, err := checkUpgrade(stay(), addOnDate("2025-07-01"))
+	assert.NoError(t, err)
+	assert.Equal(t, addOnDate("2025-07-04"), from, "before arrival every night is upgraded")
+	assert.Equal(t, 4, nights)
+
+	inHouse := stay()
+	inHouse.BookingStatus = "CheckedIn"
+	inHouse.CheckedIn = true
+	from, nights, err = checkUpgrade(inHouse, addOnDate("2025-07-06"))
+	assert.NoError(t, err)
+	assert.Equal(t, addOnDate("2025-07-06"), from, "in house the upgrade starts tonight")
+	assert.Equal(t, 2, nights)
+
+	_, _, err = checkUpgrade(inHouse, addOnDate("2025-07-08"))
+	assert.EqualError(t, err, "no nights of the stay are left to upgrade")
+
+	checkedOut := stay()
+	checkedOut.BookingStatus = "CheckedIn"
+	_, _, err = checkUpgrade(checkedOut, addOnDate("2025-07-06"))
+	assert.EqualError(t, err, "the room has already checked out")
+
+	cancelled := stay()
+	cancelled.BookingStatus = "Cancelled"
+	_, _, err = checkUpgrade(cancelled, addOnDate("2025-07-01"))
+	assert.Error(t, err)
+
+	dayUse := stay()
+	dayUse.StayType = models.StayTypeDayUse
+	_, _, err = checkUpgrade(dayUse, addOnDate("2025-07-01"))
+	assert.Error(t, err)
+}
+
+func TestHigherRoomType(t *testing.T) {
+	price := func(p float64) *float64 { return &p }
+	deluxe := &models.RoomType{BasePrice: price(2500)}
+
+	assert.True(t, higherRoomType(&models.RoomType{BasePrice: price(4000)}, deluxe))
+	assert.False(t, higherRoomType(&models.RoomType{BasePrice: price(2500)}, deluxe), "same category")
+	assert.False(t, higherRoomType(&models.RoomType{BasePrice: price(1800)}, deluxe), "a downgrade")
+	assert.False(t, higherRoomType(&models.RoomType{}, deluxe), "no base price")
+}
+
+func TestPriceUpgradeOffer(t *testing.T) {
+	booked := []models.NightlyPrice{{Price: 2000}, {Price: 2500}, {Price: 2000}}
+
+	perNight, total, ok := priceUpgradeOffer(booked, []models.NightlyPrice{{Price: 3000}, {Price: 3500}, {Price: 3100}})
+	assert.True(t, ok)
+	assert.Equal(t, 1033.33, perNight)
+	assert.Equal(t, 3099.99, total)
+
+	_, _, ok = priceUpgradeOffer(booked, []models.NightlyPrice{{Price: 3000}, {Price: 0}, {Price: 3000}})
+	assert.False(t, ok, "a night without a rate")
+
+	_, _, ok = priceUpgradeOffer(booked, []models.NightlyPrice{{Price: 2000}, {Price: 2400}, {Price: 2000}})
+	assert.False(t, ok, "no dearer than the booked room")
+
+	_, _, ok = priceUpgradeOffer(booked, nil)
+	assert.False(t, ok)
+}
+
+func TestBuildFolioUpgrades(t *testing.T) {
+	booking := &models.BookingWithDetails{
+		Booking: models.Booking{BookingID: 7, Status: "Confirmed", TotalAmount: 5800},
+		Details: []models.BookingDetailWithGuests{{
+			RoomTypeName: "Suite",
+			NightlyPrices: []models.BookingNightlyLog{
+				{Date: addOnDate("2025-07-04"), QuotedPrice: 2500},
+				{Date: addOnDate("2025-07-05"), QuotedPrice: 2500},
+			},
+			Upgrades: []models.RoomUpgrade{
+				{ToRoomTypeName: "Deluxe Room", UpgradeType: models.UpgradeTypeComplimentary,
+					StartDate: addOnDate("2025-07-04"), EndDate: addOnDate("2025-07-06")},
+				{ToRoomTypeName: "Suite", UpgradeType: models.UpgradeTypePaid, ChargePerNight: 800,
+					StartDate: addOnDate("2025-07-05"), EndDate: addOnDate("2025-07-06"), TotalCharge: 800},
+			},
+		}},
+	}
+
+	folio := buildFolio(booking)
+	assert.Equal(t, 5000.0, folio.RoomTotal)
+	assert.Equal(t, 800.0, folio.UpgradeTotal)
+	assert.Zero(t, folio.DiscountTotal)
+	if assert.Len(t, folio.Lines, 3) {
+		assert.Equal(t, models.FolioCategoryUpgrade, folio.Lines[2].Category)
+		assert.Equal(t, "Upgrade to Suite", folio.Lines[2].Description)
+		assert.Equal(t, addOnDate("2025-07-05"), *folio.Lines[2].Date)
+	}
+
+	// Before confirmation the room charge is what is left beyond the upgrade
+	booking.Details[0].NightlyPrices = nil
+	folio = buildFolio(booking)
+	assert.Equal(t, 5000.0, folio.RoomTotal)
+}
@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/pkg/utils"
+)
+
+// roomingListColumns maps accepted header spellings to the canonical column name
+var roomingListColumns = map[string]string{
+	"ref":               "ref",
+	"guest_ref":         "ref",
+	"reference":         "ref",
+	"room":              "room",
+	"room_no":           "room",
+	"room_seq":          "room",
+	"booking_detail_id": "booking_detail_id",
+	"detail_id":         "booking_detail_id",
+	"first_name":        "first_name",
+	"firstname":         "first_name",
+	"last_name":         "last_name",
+	"lastname":          "last_name",
+	"surname":           "last_name",
+	"type":              "type",
+	"guest_type":        "type",
+	"is_primary":        "is_primary",
+	"primary":           "is_primary",
+	"phone":             "phone",
+	"email":             "email",
+	"arrival":           "arrival",
+	"arrival_date":      "arrival",
+	"check_in":          "arrival",
+	"departure":         "departure",
+	"departure_date":    "departure",
+	"check_out":         "departure",
+}
+
+// ImportRoomingList validates an uploaded rooming list (CSV/XLSX) against a booking's room blocks
+// and upserts its guests. Nothing is written when any row fails validation.
+func (s *BookingService) ImportRoomingList(ctx context.Context, bookingID int, filename string, data []byte) (*models.RoomingListImportResult, error) {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, errors.New("booking not found")
+	}
+
+	switch booking.Booking.Status {
+	case "Cancelled", "Completed", "NoShow":
+		return nil, fmt.Errorf("cannot import rooming list for %s booking", booking.Booking.Status)
+	}
+
+	table, err := utils.ReadSpreadsheet(filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, rowErrors := parseRoomingList(table)
+
+	// Rows rejected while parsing are not returned, but still count towards the total
+	totalRows := len(rows)
+	rejected := make(map[int]bool)
+	for _, e := range rowErrors {
+		if e.Row > 1 && !rejected[e.Row] {
+			rejected[e.Row] = true
+			totalRows++
+		}
+	}
+
+	// Build the room blocks and the current guest list
+	blocks := make([]models.RoomingListBlock, 0, len(booking.Details))
+	var existing []models.BookingGuest
+	maxOccupancy := make(map[int]int)
+	for _, detail := range booking.Details {
+		if _, ok := maxOccupancy[detail.RoomTypeID]; !ok {
+			roomType, err := s.roomRepo.GetRoomTypeByID(ctx, detail.RoomTypeID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get room type: %w", err)
+			}
+			if roomType != nil {
+				maxOccupancy[detail.RoomTypeID] = roomType.MaxOccupancy
+			}
+		}
+
+		blocks = append(blocks, models.RoomingListBlock{
+			BookingDetailID: detail.BookingDetailID,
+			RoomTypeName:    detail.RoomTypeName,
+			MaxOccupancy:    maxOccupancy[detail.RoomTypeID],
+			CheckInDate:     detail.CheckInDate,
+			CheckOutDate:    detail.CheckOutDate,
+		})
+		existing = append(existing, detail.Guests...)
+	}
+
+	creates, updates, missing, validationErrors := planRoomingList(rows, blocks, existing)
+	rowErrors = append(rowErrors, validationErrors...)
+
+	result := &models.RoomingListImportResult{
+		BookingID:     bookingID,
+		TotalRows:     totalRows,
+		Errors:        rowErrors,
+		MissingGuests: missing,
+	}
+
+	if len(rowErrors) > 0 {
+		return result, nil
+	}
+
+	if err := s.bookingRepo.ApplyRoomingList(ctx, creates, updates); err != nil {
+		return nil, err
+	}
+
+	result.Created = len(creates)
+	result.Updated = len(updates)
+	result.Applied = true
+	result.Errors = []models.RoomingListRowError{}
+
+	return result, nil
+}
+
+// parseRoomingList converts raw spreadsheet rows into rooming list rows.
+// The first row must be a header; row numbers in errors match the spreadsheet.
+func parseRoomingList(table [][]string) ([]models.RoomingListRow, []models.RoomingListRowError) {
+	var errs []models.RoomingListRowError
+
+	if len(table) == 0 {
+		return nil, []models.RoomingListRowError{{Row: 1, Message: "file is empty"}}
+	}
+
+	columns := make(map[string]int)
+	for i, header := range table[0] {
+		key := strings.ToLower(strings.TrimSpace(header))
+		key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+		if canonical, ok := roomingListColumns[key]; ok {
+			if _, dup := columns[canonical]; !dup {
+				columns[canonical] = i
+			}
+		}
+	}
+
+	for _, required := range []string{"first_name", "last_name"} {
+		if _, ok := columns[required]; !ok {
+			errs = append(errs, models.RoomingListRowError{Row: 1, Field: required, Message: "missing required column"})
+		}
+	}
+	_, hasRoom := columns["room"]
+	_, hasDetail := columns["booking_detail_id"]
+	if !hasRoom && !hasDetail {
+		errs = append(errs, models.RoomingListRowError{Row: 1, Field: "room", Message: "missing room or booking_detail_id column"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var rows []models.RoomingListRow
+	for i, record := range table[1:] {
+		rowNumber := i + 2
+		cell := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		empty := true
+		for _, v := range record {
+			if strings.TrimSpace(v) != "" {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			continue
+		}
+
+		row := models.RoomingListRow{
+			RowNumber: rowNumber,
+			Ref:       cell("ref"),
+			FirstName: cell("first_name"),
+			LastName:  cell("last_name"),
+			Type:      "Adult",
+		}
+		rowValid := true
+		addError := func(field, message string) {
+			errs = append(errs, models.RoomingListRowError{Row: rowNumber, Field: field, Message: message})
+			rowValid = false
+		}
+
+		if v := cell("room"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				addError("room", "room must be a positive number")
+			}
+			row.Room = n
+		}
+		if v := cell("booking_detail_id"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				addError("booking_detail_id", "booking_detail_id must be a positive number")
+			}
+			row.BookingDetailID = n
+		}
+		if row.Room == 0 && row.BookingDetailID == 0 && rowValid {
+			addError("room", "room is required")
+		}
+
+		if row.FirstName == "" {
+			addError("first_name", "first name is required")
+		}
+		if row.LastName == "" {
+			addError("last_name", "last name is required")
+		}
+
+		if v := cell("type"); v != "" {
+			switch strings.ToLower(v) {
+			case "adult":
+				row.Type = "Adult"
+			case "child":
+				row.Type = "Child"
+			default:
+				addError("type", "type must be Adult or Child")
+			}
+		}
+
+		switch strings.ToLower(cell("is_primary")) {
+		case "", "false", "no", "n", "0":
+		case "true", "yes", "y", "1":
+			row.IsPrimary = true
+		default:
+			addError("is_primary", "is_primary must be yes or no")
+		}
+
+		if v := cell("phone"); v != "" {
+			row.Phone = &v
+		}
+		if v := cell("email"); v != "" {
+			if !strings.Contains(v, "@") {
+				addError("email", "invalid email address")
+			}
+			row.Email = &v
+		}
+
+		if v := cell("arrival"); v != "" {
+			t, err := utils.ParseSpreadsheetDate(v)
+			if err != nil {
+				addError("arrival", err.Error())
+			} else {
+				row.ArrivalDate = &t
+			}
+		}
+		if v := cell("departure"); v != "" {
+			t, err := utils.ParseSpreadsheetDate(v)
+			if err != nil {
+				addError("departure", err.Error())
+			} else {
+				row.DepartureDate = &t
+			}
+		}
+
+		if rowValid {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, errs
+}
+
+// roomingListKey returns the identity used to match a row against previously imported guests
+func roomingListKey(ref, firstName, lastName string) string {
+	if ref != "" {
+		return "ref:" + strings.ToLower(ref)
+	}
+	return "name:" + roomingListNameKey(firstName, lastName)
+}
+
+func roomingListNameKey(firstName, lastName string) string {
+	return strings.ToLower(strings.Join(strings.Fields(firstName+" "+lastName), " "))
+}
+
+// planRoomingList validates parsed rows against the booking's room blocks and existing guests and
+// splits them into guests to create and guests to update. Guests imported by an earlier upload
+// that no row matches are returned as missing; they are left on the booking.
+func planRoomingList(rows []models.RoomingListRow, blocks []models.RoomingListBlock, existing []models.BookingGuest) ([]models.BookingGuest, []models.BookingGuest, []models.BookingGuest, []models.RoomingListRowError) {
+	var errs []models.RoomingListRowError
+	addError := func(row int, field, message string) {
+		errs = append(errs, models.RoomingListRowError{Row: row, Field: field, Message: message})
+	}
+
+	blockByID := make(map[int]models.RoomingListBlock, len(blocks))
+	for _, block := range blocks {
+		blockByID[block.BookingDetailID] = block
+	}
+
+	// Index existing guests by rooming list reference and by name
+	byRef := make(map[string]int)
+	byName := make(map[string]int)
+	for i, guest := range existing {
+		if guest.RoomingListRef != nil && *guest.RoomingListRef != "" {
+			byRef[*guest.RoomingListRef] = i
+		}
+		name := roomingListNameKey(guest.FirstName, guest.LastName)
+		if _, ok := byName[name]; !ok {
+			byName[name] = i
+		}
+	}
+
+	occupancy := make(map[int]int)
+	for _, guest := range existing {
+		occupancy[guest.BookingDetailID]++
+	}
+
+	var creates, updates []models.BookingGuest
+	seenNames := make(map[string]int)
+	seenRefs := make(map[string]int)
+	matched := make(map[int]bool)
+	primaryRow := make(map[int]int)
+	rowsByBlock := make(map[int][]int)
+
+	for _, row := range rows {
+		var block models.RoomingListBlock
+		var ok bool
+		if row.BookingDetailID != 0 {
+			block, ok = blockByID[row.BookingDetailID]
+			if !ok {
+				addError(row.RowNumber, "booking_detail_id", fmt.Sprintf("booking detail %d does not belong to this booking", row.BookingDetailID))
+				continue
+			}
+		} else {
+			if row.Room > len(blocks) {
+				addError(row.RowNumber, "room", fmt.Sprintf("room %d does not exist, booking has %d room(s)", row.Room, len(blocks)))
+				continue
+			}
+			block = blocks[row.Room-1]
+		}
+
+		rowValid := true
+
+		name := roomingListNameKey(row.FirstName, row.LastName)
+		if first, dup := seenNames[name]; dup {
+			addError(row.RowNumber, "last_name", fmt.Sprintf("duplicate guest name, also on row %d", first))
+			rowValid = false
+		} else {
+			seenNames[name] = row.RowNumber
+		}
+
+		key := roomingListKey(row.Ref, row.FirstName, row.LastName)
+		if row.Ref != "" {
+			if first, dup := seenRefs[key]; dup {
+				addError(row.RowNumber, "ref", fmt.Sprintf("duplicate reference, also on row %d", first))
+				rowValid = false
+			} else {
+				seenRefs[key] = row.RowNumber
+			}
+		}
+
+		// Dates must fall inside the booked block
+		arrival, departure := block.CheckInDate, block.CheckOutDate
+		if row.ArrivalDate != nil {
+			arrival = *row.ArrivalDate
+		}
+		if row.DepartureDate != nil {
+			departure = *row.DepartureDate
+		}
+		if arrival.Before(block.CheckInDate) || !arrival.Before(block.CheckOutDate) {
+			addError(row.RowNumber, "arrival", fmt.Sprintf("arrival must be between %s and %s",
+				block.CheckInDate.Format("2006-01-02"), block.CheckOutDate.AddDate(0, 0, -1).Format("2006-01-02")))
+			rowValid = false
+		}
+		if departure.After(block.CheckOutDate) || !departure.After(block.CheckInDate) {
+			addError(row.RowNumber, "departure", fmt.Sprintf("departure must be between %s and %s",
+				block.CheckInDate.AddDate(0, 0, 1).Format("2006-01-02"), block.CheckOutDate.Format("2006-01-02")))
+			rowValid = false
+		} else if !departure.After(arrival) {
+			addError(row.RowNumber, "departure", "departure must be after arrival")
+			rowValid = false
+		}
+
+		if row.IsPrimary {
+			if first, dup := primaryRow[block.BookingDetailID]; dup {
+				addError(row.RowNumber, "is_primary", fmt.Sprintf("room already has a primary guest on row %d", first))
+				rowValid = false
+			} else {
+				primaryRow[block.BookingDetailID] = row.RowNumber
+			}
+		}
+
+		if !rowValid {
+			continue
+		}
+
+		guest := models.BookingGuest{
+			BookingDetailID: block.BookingDetailID,
+			FirstName:       row.FirstName,
+			LastName:        row.LastName,
+			Phone:           row.Phone,
+			Email:           row.Email,
+			Type:            row.Type,
+			IsPrimary:       row.IsPrimary,
+			RoomingListRef:  &key,
+			ArrivalDate:     row.ArrivalDate,
+			DepartureDate:   row.DepartureDate,
+		}
+
+		// Match an existing guest by reference first, then by name for guests entered by hand or
+		// imported without a reference; a matched guest takes the row's reference
+		idx, found := byRef[key]
+		if !found {
+			if i, ok := byName[name]; ok {
+				ref := existing[i].RoomingListRef
+				if ref == nil || *ref == "" || *ref == "name:"+name {
+					idx, found = i, true
+				}
+			}
+		}
+		if found && matched[idx] {
+			found = false
+		}
+
+		if found {
+			matched[idx] = true
+			previous := existing[idx]
+			guest.BookingGuestID = previous.BookingGuestID
+			if guest.Phone == nil {
+				guest.Phone = previous.Phone
+			}
+			if guest.Email == nil {
+				guest.Email = previous.Email
+			}
+			occupancy[previous.BookingDetailID]--
+			updates = append(updates, guest)
+		} else {
+			creates = append(creates, guest)
+		}
+		occupancy[block.BookingDetailID]++
+		rowsByBlock[block.BookingDetailID] = append(rowsByBlock[block.BookingDetailID], row.RowNumber)
+	}
+
+	// Occupancy is checked once every row has been placed, since guests may move between rooms
+	for _, block := range blocks {
+		excess := occupancy[block.BookingDetailID] - block.MaxOccupancy
+		if block.MaxOccupancy <= 0 || excess <= 0 {
+			continue
+		}
+		placed := rowsByBlock[block.BookingDetailID]
+		if excess > len(placed) {
+			excess = len(placed)
+		}
+		for _, rowNumber := range placed[len(placed)-excess:] {
+			addError(rowNumber, "room", fmt.Sprintf("room would have %d guests, max occupancy for %s is %d",
+				occupancy[block.BookingDetailID], block.RoomTypeName, block.MaxOccupancy))
+		}
+	}
+
+	missing := []models.BookingGuest{}
+	for i, guest := range existing {
+		if !matched[i] && guest.RoomingListRef != nil && *guest.RoomingListRef != "" {
+			missing = append(missing, guest)
+		}
+	}
+
+	return creates, updates, missing, errs
+}
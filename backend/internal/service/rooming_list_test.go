@@ -0,0 +1,147 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roomingListBlocks() []models.RoomingListBlock {
+	return []models.RoomingListBlock{
+		{
+			BookingDetailID: 10,
+			RoomTypeName:    "Deluxe",
+			MaxOccupancy:    2,
+			CheckInDate:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			CheckOutDate:    time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			BookingDetailID: 11,
+			RoomTypeName:    "Deluxe",
+			MaxOccupancy:    2,
+			CheckInDate:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			CheckOutDate:    time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestParseRoomingList_MissingColumns(t *testing.T) {
+	_, errs := parseRoomingList([][]string{{"Name", "Email"}})
+
+	require.Len(t, errs, 3)
+	assert.Equal(t, 1, errs[0].Row)
+}
+
+func TestParseRoomingList_RowErrors(t *testing.T) {
+	table := [][]string{
+		{"Room", "First Name", "Last Name", "Type", "Primary", "Arrival"},
+		{"1", "Somchai", "Jaidee", "adult", "yes", "2025-06-01"},
+		{"x", "", "Doe", "Teen", "maybe", "soon"},
+		{"", "", "", "", "", ""},
+	}
+
+	rows, errs := parseRoomingList(table)
+
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Adult", rows[0].Type)
+	assert.True(t, rows[0].IsPrimary)
+	assert.Equal(t, 1, rows[0].Room)
+
+	fields := map[string]bool{}
+	for _, e := range errs {
+		assert.Equal(t, 3, e.Row)
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"room", "first_name", "type", "is_primary", "arrival"} {
+		assert.True(t, fields[field], "expected error on %s", field)
+	}
+}
+
+func TestPlanRoomingList_Validation(t *testing.T) {
+	early := time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)
+	rows := []models.RoomingListRow{
+		{RowNumber: 2, Room: 1, FirstName: "Somchai", LastName: "Jaidee", Type: "Adult"},
+		{RowNumber: 3, Room: 1, FirstName: "somchai", LastName: " jaidee", Type: "Adult"},
+		{RowNumber: 4, Room: 3, FirstName: "Jane", LastName: "Doe", Type: "Adult"},
+		{RowNumber: 5, Room: 2, FirstName: "Early", LastName: "Bird", Type: "Adult", ArrivalDate: &early},
+		{RowNumber: 6, BookingDetailID: 99, FirstName: "Lost", LastName: "Guest", Type: "Adult"},
+	}
+
+	_, _, _, errs := planRoomingList(rows, roomingListBlocks(), nil)
+
+	byRow := map[int]string{}
+	for _, e := range errs {
+		byRow[e.Row] = e.Field
+	}
+	assert.Equal(t, "last_name", byRow[3], "duplicate name")
+	assert.Equal(t, "room", byRow[4], "unknown room")
+	assert.Equal(t, "arrival", byRow[5], "arrival outside block")
+	assert.Equal(t, "booking_detail_id", byRow[6], "detail from another booking")
+	assert.NotContains(t, byRow, 2)
+}
+
+func TestPlanRoomingList_Occupancy(t *testing.T) {
+	rows := []models.RoomingListRow{
+		{RowNumber: 2, Room: 1, FirstName: "A", LastName: "One", Type: "Adult"},
+		{RowNumber: 3, Room: 1, FirstName: "B", LastName: "Two", Type: "Adult"},
+		{RowNumber: 4, Room: 1, FirstName: "C", LastName: "Three", Type: "Child"},
+	}
+
+	_, _, _, errs := planRoomingList(rows, roomingListBlocks(), nil)
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, 4, errs[0].Row)
+	assert.Equal(t, "room", errs[0].Field)
+}
+
+func TestPlanRoomingList_ReuploadUpdatesExisting(t *testing.T) {
+	ref := "ref:g-1"
+	existing := []models.BookingGuest{
+		{BookingGuestID: 100, BookingDetailID: 10, FirstName: "Somchai", LastName: "Jaidee", Type: "Adult", IsPrimary: true},
+		{BookingGuestID: 101, BookingDetailID: 10, FirstName: "Old", LastName: "Name", Type: "Adult", RoomingListRef: &ref},
+	}
+	rows := []models.RoomingListRow{
+		{RowNumber: 2, Room: 1, FirstName: "Somchai", LastName: "Jaidee", Type: "Adult", IsPrimary: true},
+		{RowNumber: 3, Room: 2, Ref: "G-1", FirstName: "New", LastName: "Name", Type: "Adult"},
+		{RowNumber: 4, Room: 2, FirstName: "Jane", LastName: "Doe", Type: "Adult"},
+	}
+
+	creates, updates, missing, errs := planRoomingList(rows, roomingListBlocks(), existing)
+
+	require.Empty(t, errs)
+	require.Len(t, updates, 2)
+	require.Len(t, creates, 1)
+
+	assert.Equal(t, 100, updates[0].BookingGuestID)
+	assert.Equal(t, 101, updates[1].BookingGuestID)
+	assert.Equal(t, 11, updates[1].BookingDetailID, "guest moved to room 2")
+	assert.Equal(t, "New", updates[1].FirstName)
+	assert.Equal(t, "name:jane doe", *creates[0].RoomingListRef)
+	assert.Empty(t, missing)
+}
+
+func TestPlanRoomingList_ReuploadMatchesByName(t *testing.T) {
+	somchai := "name:somchai jaidee"
+	jane := "name:jane doe"
+	existing := []models.BookingGuest{
+		{BookingGuestID: 100, BookingDetailID: 10, FirstName: "Somchai", LastName: "Jaidee", Type: "Adult", RoomingListRef: &somchai},
+		{BookingGuestID: 101, BookingDetailID: 11, FirstName: "Jane", LastName: "Doe", Type: "Adult", RoomingListRef: &jane},
+	}
+	rows := []models.RoomingListRow{
+		{RowNumber: 2, Room: 1, Ref: "G-1", FirstName: "Somchai", LastName: "Jaidee", Type: "Adult"},
+	}
+
+	creates, updates, missing, errs := planRoomingList(rows, roomingListBlocks(), existing)
+
+	require.Empty(t, errs)
+	assert.Empty(t, creates, "the guest imported without a reference is not added again")
+	require.Len(t, updates, 1)
+	assert.Equal(t, 100, updates[0].BookingGuestID)
+	assert.Equal(t, "ref:g-1", *updates[0].RoomingListRef, "re-keyed on the reference")
+
+	require.Len(t, missing, 1)
+	assert.Equal(t, 101, missing[0].BookingGuestID)
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+const (
+	// endOfDay marks a room that is not free again on the day
+	endOfDay = 24 * 60
+	// dayUseCleaningMinutes matches the hour day_use_block_conflict keeps after a slot
+	dayUseCleaningMinutes = 60
+	standardCheckIn       = models.StandardCheckInHour * 60
+	standardCheckOut      = models.StandardCheckOutHour * 60
+)
+
+// parseStayTime turns an HH:MM time into minutes after midnight
+func parseStayTime(value string) (int, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.New("time must use HH:MM")
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// formatStayTime turns minutes after midnight into HH:MM
+func formatStayTime(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// stayTimeFee picks the fee tier covering minute: the latest early check-in tier that
+// starts by then, or the earliest late check-out tier that lasts until then. ok is
+// false when the time is not after the standard time or no tier covers it.
+func stayTimeFee(tiers []models.StayTimeFee, requestType string, minute int) (fee float64, ok bool) {
+	best := -1
+	for i, tier := range tiers {
+		if tier.RequestType != requestType || !tier.IsActive {
+			continue
+		}
+		hour := tier.TierHour * 60
+		switch requestType {
+		case models.StayTimeEarlyCheckIn:
+			if minute < standardCheckIn && hour <= minute && (best < 0 || tier.TierHour > tiers[best].TierHour) {
+				best = i
+			}
+		case models.StayTimeLateCheckOut:
+			if minute > standardCheckOut && hour >= minute && (best < 0 || tier.TierHour < tiers[best].TierHour) {
+				best = i
+			}
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return tiers[best].Fee, true
+}
+
+// stayTimeRoomsReady lists when each room of a day is ready for an arriving stay: free
+// rooms in the morning, departing rooms once cleaned and rooms held for day-use once
+// the slot is over and cleaned. Stayovers keep their room all day.
+func stayTimeRoomsReady(day *models.StayTimeDay) []int {
+	free := day.Rooms - day.Stayovers - len(day.Departures)
+	var ready []int
+	for _, end := range day.DayUseEnds {
+		if free <= 0 {
+			break
+		}
+		ready = append(ready, end+dayUseCleaningMinutes)
+		free--
+	}
+	for ; free > 0; free-- {
+		ready = append(ready, 0)
+	}
+	for _, departure := range day.Departures {
+		ready = append(ready, departure+day.CleaningMinutes)
+	}
+	return ready
+}
+
+// stayTimeShortfall counts the arrivals that cannot have a ready room when they need
+// it, at the worst time of the day
+func stayTimeShortfall(ready, needed []int) int {
+	ready = append([]int(nil), ready...)
+	needed = append([]int(nil), needed...)
+	sort.Ints(ready)
+	sort.Ints(needed)
+
+	shortfall, r := 0, 0
+	for i, at := range needed {
+		for r < len(ready) && ready[r] <= at {
+			r++
+		}
+		if missing := i + 1 - r; missing > shortfall {
+			shortfall = missing
+		}
+	}
+	return shortfall
+}
+
+// checkStayTimeDay checks that the rooms of the type can take an early check-in or
+// late check-out at minute without leaving more arriving guests waiting for a room
+// than there already are
+func checkStayTimeDay(day *models.StayTimeDay, requestType string, minute int) error {
+	switch requestType {
+	case models.StayTimeEarlyCheckIn:
+		ready := stayTimeRoomsReady(day)
+		readyBy, neededBy := 0, 1
+		for _, at := range ready {
+			if at <= minute {
+				readyBy++
+			}
+		}
+		for _, at := range day.Arrivals {
+			if at <= minute {
+				neededBy++
+			}
+		}
+		before := stayTimeShortfall(ready, append(append([]int(nil), day.Arrivals...), standardCheckIn))
+		after := stayTimeShortfall(ready, append(append([]int(nil), day.Arrivals...), minute))
+		if readyBy < neededBy || after > before {
+			return fmt.Errorf("no room of this type is ready by %s", formatStayTime(minute))
+		}
+	case models.StayTimeLateCheckOut:
+		// The departing room stays out of the pool until it is cleaned
+		readyAfter := func(departure int) []int {
+			withDeparture := *day
+			withDeparture.Departures = append(append([]int(nil), day.Departures...), departure)
+			return stayTimeRoomsReady(&withDeparture)
+		}
+		before := stayTimeShortfall(readyAfter(standardCheckOut), day.Arrivals)
+		after := stayTimeShortfall(readyAfter(minute), day.Arrivals)
+		if after > before {
+			return fmt.Errorf("arriving guests need every room of this type before %s", formatStayTime(minute+day.CleaningMinutes))
+		}
+	}
+	return nil
+}
+
+// checkStayTimeRoom checks the room a stay is checked in to or pre-assigned to: it must
+// be in service and free of day-use slots, cleaned after the previous guest before an
+// early check-in, and cleaned after a late check-out before the next guest arrives
+func checkStayTimeRoom(room *models.StayTimeRoom, requestType string, minute, cleaningMinutes int) error {
+	if room.OutOfOrder {
+		return fmt.Errorf("room %s is out of order on that day", room.RoomNumber)
+	}
+	if room.DayUseConflict {
+		return fmt.Errorf("room %s is held for a day-use slot at that time", room.RoomNumber)
+	}
+
+	switch requestType {
+	case models.StayTimeEarlyCheckIn:
+		if room.PreviousDeparture >= endOfDay {
+			return fmt.Errorf("room %s is still occupied on the arrival date", room.RoomNumber)
+		}
+		if room.PreviousDeparture > 0 && room.PreviousDeparture+cleaningMinutes > minute {
+			return fmt.Errorf("room %s is not ready before %s", room.RoomNumber, formatStayTime(room.PreviousDeparture+cleaningMinutes))
+		}
+	case models.StayTimeLateCheckOut:
+		if minute+cleaningMinutes > room.NextArrival {
+			return fmt.Errorf("room %s is needed for a guest arriving at %s", room.RoomNumber, formatStayTime(room.NextArrival))
+		}
+	}
+	return nil
+}
+
+// RequestStayTime lets a guest ask to check in early or check out late. The request is
+// quoted at the tier fee and waits for staff to approve it. A guestID of 0 skips the
+// ownership check (staff access).
+func (s *BookingService) RequestStayTime(ctx context.Context, bookingID int, guestID int, req *models.CreateStayTimeRequest) (*models.StayTimeRequest, error) {
+	stay, err := s.bookingRepo.GetStayTimeStay(ctx, req.BookingDetailID)
+	if err != nil {
+		return nil, err
+	}
+	if stay == nil || stay.BookingID != bookingID {
+		return nil, errors.New("booking detail not found")
+	}
+	if guestID != 0 && stay.GuestID != nil && *stay.GuestID != guestID {
+		return nil, errors.New("unauthorized to change this booking")
+	}
+
+	request, err := s.newStayTimeRequest(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Status = models.StayTimeStatusPending
+	request.Source = models.StayTimeSourceGuest
+	if err := s.bookingRepo.CreateStayTimeRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GrantStayTime records an early check-in or late check-out arranged by staff. It is
+// approved at once and its fee, the tier fee unless req sets one, is posted to the
+// booking.
+func (s *BookingService) GrantStayTime(ctx context.Context, req *models.StaffStayTimeRequest, staffID *int) (*models.StayTimeRequest, error) {
+	request, err := s.newStayTimeRequest(ctx, &req.CreateStayTimeRequest, req.Fee)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Status = models.StayTimeStatusApproved
+	request.Source = models.StayTimeSourceStaff
+	request.DecidedBy = staffID
+	if err := s.bookingRepo.CreateStayTimeRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// newStayTimeRequest checks a new request and quotes it at fee, or at the tier fee
+// when fee is nil
+func (s *BookingService) newStayTimeRequest(ctx context.Context, req *models.CreateStayTimeRequest, fee *float64) (*models.StayTimeRequest, error) {
+	minute, err := parseStayTime(req.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	stay, requestedAt, err := s.checkStayTime(ctx, req.BookingDetailID, req.RequestType, minute)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := s.bookingRepo.HasOpenStayTimeRequest(ctx, stay.BookingDetailID, req.RequestType)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, errors.New("this stay already has an open request of this type; cancel it first")
+	}
+
+	request := &models.StayTimeRequest{
+		BookingDetailID: stay.BookingDetailID,
+		BookingID:       stay.BookingID,
+		RequestType:     req.RequestType,
+		RequestedAt:     requestedAt,
+	}
+	if fee != nil {
+		request.Fee = roundAmount(*fee)
+	} else {
+		tiers, err := s.bookingRepo.GetStayTimeFees(ctx, req.RequestType)
+		if err != nil {
+			return nil, err
+		}
+		tierFee, ok := stayTimeFee(tiers, req.RequestType, minute)
+		if !ok {
+			return nil, fmt.Errorf("no fee tier covers %s", formatStayTime(minute))
+		}
+		request.Fee = tierFee
+	}
+	if note := strings.TrimSpace(req.Note); note != "" {
+		request.Note = &note
+	}
+	return request, nil
+}
+
+// checkStayTime checks that a stay can check in early or check out late at minute,
+// against the rooms of its type and the room it is checked in to or pre-assigned to.
+// It returns the stay and the requested time on the check-in or check-out date.
+func (s *BookingService) checkStayTime(ctx context.Context, bookingDetailID int, requestType string, minute int) (*models.StayTimeStay, time.Time, error) {
+	stay, err := s.bookingRepo.GetStayTimeStay(ctx, bookingDetailID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if stay == nil {
+		return nil, time.Time{}, errors.New("booking detail not found")
+	}
+	if stay.StayType != models.StayTypeOvernight {
+		return nil, time.Time{}, errors.New("early check-in and late check-out are for overnight stays")
+	}
+	if stay.BookingStatus != "Confirmed" && stay.BookingStatus != "CheckedIn" {
+		return nil, time.Time{}, errors.New("only confirmed or in-house bookings can change their arrival or departure time")
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	var date, from, to time.Time
+	switch requestType {
+	case models.StayTimeEarlyCheckIn:
+		if stay.CheckedIn {
+			return nil, time.Time{}, errors.New("the guest has already checked in")
+		}
+		if stay.CheckInDate.Before(today) {
+			return nil, time.Time{}, errors.New("the arrival date has passed")
+		}
+		if minute >= standardCheckIn {
+			return nil, time.Time{}, fmt.Errorf("an early check-in must be before %s", formatStayTime(standardCheckIn))
+		}
+		date = stay.CheckInDate
+		from = date.Add(time.Duration(minute) * time.Minute)
+		to = date.Add(standardCheckIn * time.Minute)
+	case models.StayTimeLateCheckOut:
+		if stay.CheckOutDate.Before(today) {
+			return nil, time.Time{}, errors.New("the departure date has passed")
+		}
+		if minute <= standardCheckOut {
+			return nil, time.Time{}, fmt.Errorf("a late check-out must be after %s", formatStayTime(standardCheckOut))
+		}
+		date = stay.CheckOutDate
+		from = date.Add(standardCheckOut * time.Minute)
+		to = date.Add(time.Duration(minute) * time.Minute)
+	default:
+		return nil, time.Time{}, errors.New("invalid request type")
+	}
+
+	day, err := s.bookingRepo.GetStayTimeDay(ctx, stay.RoomTypeID, date, stay.BookingDetailID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if err := checkStayTimeDay(day, requestType, minute); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	room, err := s.bookingRepo.GetStayTimeRoom(ctx, stay.BookingDetailID, date, from, to)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if room != nil {
+		if err := checkStayTimeRoom(room, requestType, minute, day.CleaningMinutes); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+
+	requestedAt := date.Add(time.Duration(minute) * time.Minute)
+	return stay, requestedAt, nil
+}
+
+// GetStayTimeRequests lists the early check-ins and late check-outs of a date
+// (default today), optionally of one status
+func (s *BookingService) GetStayTimeRequests(ctx context.Context, dateStr, status string) ([]models.StayTimeRequest, error) {
+	date := time.Now().Truncate(24 * time.Hour)
+	if dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		date = parsed
+	}
+
+	requests, err := s.bookingRepo.GetStayTimeRequests(ctx, date, status)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []models.StayTimeRequest{}
+	}
+	return requests, nil
+}
+
+// ApproveStayTimeRequest approves a guest's request once the room can still take it and
+// posts its fee to the booking. A fee in req overrides the quoted fee.
+func (s *BookingService) ApproveStayTimeRequest(ctx context.Context, requestID int, staffID *int, req *models.ApproveStayTimeRequest) (*models.StayTimeRequest, error) {
+	request, err := s.pendingStayTimeRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	minute := request.RequestedAt.Hour()*60 + request.RequestedAt.Minute()
+	if _, _, err := s.checkStayTime(ctx, request.BookingDetailID, request.RequestType, minute); err != nil {
+		return nil, err
+	}
+
+	if req.Fee != nil {
+		request.Fee = roundAmount(*req.Fee)
+	}
+	request.Status = models.StayTimeStatusApproved
+	if err := s.bookingRepo.DecideStayTimeRequest(ctx, request, staffID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// DeclineStayTimeRequest declines a guest's request
+func (s *BookingService) DeclineStayTimeRequest(ctx context.Context, requestID int, staffID *int, req *models.DeclineStayTimeRequest) (*models.StayTimeRequest, error) {
+	request, err := s.pendingStayTimeRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	request.Status = models.StayTimeStatusDeclined
+	request.DeclineReason = &reason
+	if err := s.bookingRepo.DecideStayTimeRequest(ctx, request, staffID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// pendingStayTimeRequest retrieves a request waiting for staff to decide
+func (s *BookingService) pendingStayTimeRequest(ctx context.Context, requestID int) (*models.StayTimeRequest, error) {
+	request, err := s.bookingRepo.GetStayTimeRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("stay-time request not found")
+	}
+	if request.Status != models.StayTimeStatusPending {
+		return nil, fmt.Errorf("the request is already %s", strings.ToLower(request.Status))
+	}
+	return request, nil
+}
+
+// CancelStayTimeRequest cancels a pending or approved request of a booking. The fee of
+// an approved request is taken off the booking. A guestID of 0 skips the ownership
+// check (staff access).
+func (s *BookingService) CancelStayTimeRequest(ctx context.Context, bookingID int, guestID int, requestID int) (*models.StayTimeRequest, error) {
+	request, err := s.bookingRepo.GetStayTimeRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.BookingID != bookingID {
+		return nil, errors.New("stay-time request not found")
+	}
+
+	if guestID != 0 {
+		stay, err := s.bookingRepo.GetStayTimeStay(ctx, request.BookingDetailID)
+		if err != nil {
+			return nil, err
+		}
+		if stay != nil && stay.GuestID != nil && *stay.GuestID != guestID {
+			return nil, errors.New("unauthorized to change this booking")
+		}
+	}
+
+	if request.Status != models.StayTimeStatusPending && request.Status != models.StayTimeStatusApproved {
+		return nil, fmt.Errorf("the request is already %s", strings.ToLower(request.Status))
+	}
+	if !request.RequestedAt.After(hotelNow()) {
+		return nil, errors.New("the requested time has passed")
+	}
+
+	if err := s.bookingRepo.CancelStayTimeRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
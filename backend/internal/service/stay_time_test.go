@@ -0,0 +1,157 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStayTime(t *testing.T) {
+	minute, err := parseStayTime("10:30")
+	assert.NoError(t, err)
+	assert.Equal(t, 630, minute)
+	assert.Equal(t, "10:30", formatStayTime(minute))
+
+	_, err = parseStayTime("10.30")
+	assert.Error(t, err)
+	_, err = parseStayTime("25:00")
+	assert.Error(t, err)
+}
+
+func TestStayTimeFee(t *testing.T) {
+	tiers := []models.StayTimeFee{
+		{RequestType: models.StayTimeEarlyCheckIn, TierHour: 11, Fee: 500, IsActive: true},
+		{RequestType: models.StayTimeEarlyCheckIn, TierHour: 8, Fee: 1000, IsActive: true},
+		{RequestType: models.StayTimeLateCheckOut, TierHour: 14, Fee: 500, IsActive: true},
+		{RequestType: models.StayTimeLateCheckOut, TierHour: 18, Fee: 1000, IsActive: true},
+		{RequestType: models.StayTimeLateCheckOut, TierHour: 16, Fee: 700, IsActive: false},
+	}
+
+	tests := []struct {
+		name        string
+		requestType string
+		time        string
+		fee         float64
+		ok          bool
+	}{
+		{"early from the latest tier", models.StayTimeEarlyCheckIn, "12:00", 500, true},
+		{"early on a tier hour", models.StayTimeEarlyCheckIn, "11:00", 500, true},
+		{"early before the first tier", models.StayTimeEarlyCheckIn, "10:59", 1000, true},
+		{"earlier than any tier", models.StayTimeEarlyCheckIn, "07:30", 0, false},
+		{"not early", models.StayTimeEarlyCheckIn, "14:00", 0, false},
+		{"late until the first tier", models.StayTimeLateCheckOut, "13:00", 500, true},
+		{"late past the first tier", models.StayTimeLateCheckOut, "15:00", 1000, true},
+		{"later than any tier", models.StayTimeLateCheckOut, "19:00", 0, false},
+		{"not late", models.StayTimeLateCheckOut, "12:00", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minute, err := parseStayTime(tt.time)
+			assert.NoError(t, err)
+			fee, ok := stayTimeFee(tiers, tt.requestType, minute)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.fee, fee)
+		})
+	}
+}
+
+func TestStayTimeShortfall(t *testing.T) {
+	assert.Zero(t, stayTimeShortfall([]int{0, 800}, []int{840, 840}))
+	assert.Equal(t, 1, stayTimeShortfall([]int{0, 800}, []int{600, 700}), "one room is not ready in the morning")
+	assert.Equal(t, 1, stayTimeShortfall([]int{0}, []int{840, 840}), "overbooked")
+	assert.Zero(t, stayTimeShortfall(nil, nil))
+}
+
+func TestCheckStayTimeDay(t *testing.T) {
+	// Three rooms: one free, one departing at 12:00 and one stayover; one other arrival
+	day := &models.StayTimeDay{
+		Rooms:           3,
+		Stayovers:       1,
+		Departures:      []int{720},
+		Arrivals:        []int{840},
+		CleaningMinutes: 30,
+	}
+
+	assert.NoError(t, checkStayTimeDay(day, models.StayTimeEarlyCheckIn, 600),
+		"the free room takes the early arrival and the departing room is ready by 14:00")
+
+	day.Arrivals = []int{540}
+	assert.Error(t, checkStayTimeDay(day, models.StayTimeEarlyCheckIn, 600),
+		"the free room already went to an approved early arrival")
+	assert.NoError(t, checkStayTimeDay(day, models.StayTimeEarlyCheckIn, 750), "ready at 12:30")
+
+	// Late check-out with one other room of the type left for two arrivals
+	late := &models.StayTimeDay{Rooms: 2, Arrivals: []int{840, 840}, CleaningMinutes: 30}
+	assert.NoError(t, checkStayTimeDay(late, models.StayTimeLateCheckOut, 780), "clean by 13:30")
+	assert.Error(t, checkStayTimeDay(late, models.StayTimeLateCheckOut, 900))
+
+	late.Arrivals = []int{840}
+	assert.NoError(t, checkStayTimeDay(late, models.StayTimeLateCheckOut, 1080), "a room is spare")
+
+	// A day-use slot ending at 13:30 leaves its room ready at 14:30
+	dayUse := &models.StayTimeDay{Rooms: 1, DayUseEnds: []int{810}, CleaningMinutes: 30}
+	assert.Error(t, checkStayTimeDay(dayUse, models.StayTimeEarlyCheckIn, 720))
+}
+
+func TestCheckStayTimeRoom(t *testing.T) {
+	room := &models.StayTimeRoom{RoomNumber: "204", NextArrival: endOfDay}
+	assert.NoError(t, checkStayTimeRoom(room, models.StayTimeEarlyCheckIn, 600, 30))
+	assert.NoError(t, checkStayTimeRoom(room, models.StayTimeLateCheckOut, 1080, 30))
+
+	room.PreviousDeparture = 720
+	assert.EqualError(t, checkStayTimeRoom(room, models.StayTimeEarlyCheckIn, 600, 30), "room 204 is not ready before 12:30")
+	assert.NoError(t, checkStayTimeRoom(room, models.StayTimeEarlyCheckIn, 780, 30))
+
+	room.PreviousDeparture = endOfDay
+	assert.EqualError(t, checkStayTimeRoom(room, models.StayTimeEarlyCheckIn, 780, 30), "room 204 is still occupied on the arrival date")
+
+	room.NextArrival = 660
+	assert.EqualError(t, checkStayTimeRoom(room, models.StayTimeLateCheckOut, 780, 30), "room 204 is needed for a guest arriving at 11:00")
+
+	room.NextArrival = endOfDay
+	room.DayUseConflict = true
+	assert.Error(t, checkStayTimeRoom(room, models.StayTimeLateCheckOut, 780, 30))
+
+	room.DayUseConflict = false
+	room.OutOfOrder = true
+	assert.Error(t, checkStayTimeRoom(room, models.StayTimeLateCheckOut, 780, 30))
+}
+
+func TestBuildFolioStayTime(t *testing.T) {
+	booking := &models.BookingWithDetails{
+		Booking: models.Booking{BookingID: 9, Status: "CheckedIn", TotalAmount: 6000},
+		Details: []models.BookingDetailWithGuests{{
+			RoomTypeName: "Deluxe Room",
+			NightlyPrices: []models.BookingNightlyLog{
+				{Date: addOnDate("2025-07-04"), QuotedPrice: 2500},
+				{Date: addOnDate("2025-07-05"), QuotedPrice: 2500},
+			},
+			StayTimeRequests: []models.StayTimeRequest{
+				{RequestType: models.StayTimeEarlyCheckIn, Status: models.StayTimeStatusApproved, Fee: 0,
+					RequestedAt: addOnDate("2025-07-04").Add(11 * time.Hour)},
+				{RequestType: models.StayTimeLateCheckOut, Status: models.StayTimeStatusApproved, Fee: 1000,
+					RequestedAt: addOnDate("2025-07-06").Add(18 * time.Hour)},
+				{RequestType: models.StayTimeEarlyCheckIn, Status: models.StayTimeStatusDeclined, Fee: 500,
+					RequestedAt: addOnDate("2025-07-04").Add(12 * time.Hour)},
+			},
+		}},
+	}
+
+	folio := buildFolio(booking)
+	assert.Equal(t, 5000.0, folio.RoomTotal)
+	assert.Equal(t, 1000.0, folio.StayTimeTotal)
+	assert.Zero(t, folio.DiscountTotal)
+	if assert.Len(t, folio.Lines, 3) {
+		assert.Equal(t, models.FolioCategoryStayTime, folio.Lines[2].Category)
+		assert.Equal(t, "Late check-out until 18:00", folio.Lines[2].Description)
+		assert.Equal(t, addOnDate("2025-07-06"), *folio.Lines[2].Date)
+	}
+
+	// Before confirmation the room charge is what is left beyond the fee
+	booking.Details[0].NightlyPrices = nil
+	folio = buildFolio(booking)
+	assert.Equal(t, 5000.0, folio.RoomTotal)
+}
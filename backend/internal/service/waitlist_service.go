@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/hotel-booking-system/backend/internal/repository"
+)
+
+// InventoryListener is notified when room inventory may have become available
+type InventoryListener interface {
+	InventoryReleased()
+}
+
+// InventoryListeners notifies each of several listeners
+type InventoryListeners []InventoryListener
+
+// InventoryReleased notifies every listener
+func (l InventoryListeners) InventoryReleased() {
+	for _, listener := range l {
+		listener.InventoryReleased()
+	}
+}
+
+// WaitlistService handles waitlist business logic
+type WaitlistService struct {
+	waitlistRepo *repository.WaitlistRepository
+	roomRepo     *repository.RoomRepository
+	listener     InventoryListener
+}
+
+// NewWaitlistService creates a new waitlist service
+func NewWaitlistService(waitlistRepo *repository.WaitlistRepository, roomRepo *repository.RoomRepository) *WaitlistService {
+	return &WaitlistService{
+		waitlistRepo: waitlistRepo,
+		roomRepo:     roomRepo,
+	}
+}
+
+// SetInventoryListener registers a listener notified when an offered hold is given up
+func (s *WaitlistService) SetInventoryListener(listener InventoryListener) {
+	s.listener = listener
+}
+
+// JoinWaitlist adds a guest to the waitlist for a room type and date range.
+// Returns the entry and the number of guests queued ahead for overlapping dates.
+func (s *WaitlistService) JoinWaitlist(ctx context.Context, guestID *int, req *models.JoinWaitlistRequest) (*models.WaitlistEntry, int, error) {
+	checkIn, err := time.Parse("2006-01-02", req.CheckIn)
+	if err != nil {
+		return nil, 0, errors.New("invalid check-in date format")
+	}
+
+	checkOut, err := time.Parse("2006-01-02", req.CheckOut)
+	if err != nil {
+		return nil, 0, errors.New("invalid check-out date format")
+	}
+
+	if !checkOut.After(checkIn) {
+		return nil, 0, errors.New("check-out date must be after check-in date")
+	}
+
+	if checkIn.Before(time.Now().Truncate(24 * time.Hour)) {
+		return nil, 0, errors.New("check-in date cannot be in the past")
+	}
+
+	if checkOut.Sub(checkIn).Hours() > 30*24 {
+		return nil, 0, errors.New("waitlist stay cannot exceed 30 nights")
+	}
+
+	roomType, err := s.roomRepo.GetRoomTypeByID(ctx, req.RoomTypeID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get room type: %w", err)
+	}
+	if roomType == nil {
+		return nil, 0, errors.New("room type not found")
+	}
+	if req.NumGuests > roomType.MaxOccupancy {
+		return nil, 0, fmt.Errorf("room type allows at most %d guests", roomType.MaxOccupancy)
+	}
+
+	entry := &models.WaitlistEntry{
+		GuestID:      guestID,
+		FirstName:    strings.TrimSpace(req.FirstName),
+		LastName:     strings.TrimSpace(req.LastName),
+		Email:        strings.TrimSpace(req.Email),
+		Phone:        req.Phone,
+		RoomTypeID:   req.RoomTypeID,
+		RoomTypeName: roomType.Name,
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		NumGuests:    req.NumGuests,
+	}
+
+	if err := s.waitlistRepo.CreateEntry(ctx, entry); err != nil {
+		return nil, 0, err
+	}
+
+	ahead, err := s.waitlistRepo.CountAhead(ctx, entry)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Inventory may already be free (e.g. search was run before a cancellation)
+	if s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+
+	return entry, ahead, nil
+}
+
+// GetWaitlistEntry retrieves a waitlist entry; guests may only view their own entries
+func (s *WaitlistService) GetWaitlistEntry(ctx context.Context, waitlistID int, guestID *int) (*models.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, waitlistID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	if guestID != nil && (entry.GuestID == nil || *entry.GuestID != *guestID) {
+		return nil, errors.New("unauthorized to view this waitlist entry")
+	}
+
+	return entry, nil
+}
+
+// GetGuestWaitlist retrieves all waitlist entries of a guest
+func (s *WaitlistService) GetGuestWaitlist(ctx context.Context, guestID int) ([]models.WaitlistEntry, error) {
+	return s.waitlistRepo.GetEntries(ctx, "", &guestID)
+}
+
+// GetWaitlist retrieves the waitlist for staff, optionally filtered by status
+func (s *WaitlistService) GetWaitlist(ctx context.Context, status string) ([]models.WaitlistEntry, error) {
+	switch status {
+	case "", models.WaitlistStatusWaiting, models.WaitlistStatusOffered, models.WaitlistStatusClaimed,
+		models.WaitlistStatusExpired, models.WaitlistStatusCancelled:
+	default:
+		return nil, fmt.Errorf("invalid waitlist status: %s", status)
+	}
+
+	return s.waitlistRepo.GetEntries(ctx, status, nil)
+}
+
+// CancelWaitlistEntry removes a guest from the waitlist. A pending offer's hold is released
+// and passed on to the next guest in the queue. Pass a nil guestID for staff cancellations.
+func (s *WaitlistService) CancelWaitlistEntry(ctx context.Context, waitlistID int, guestID *int) error {
+	entry, err := s.GetWaitlistEntry(ctx, waitlistID, guestID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return errors.New("waitlist entry not found")
+	}
+
+	if err := s.waitlistRepo.CancelEntry(ctx, waitlistID); err != nil {
+		return err
+	}
+
+	if entry.Status == models.WaitlistStatusOffered && s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+
+	return nil
+}
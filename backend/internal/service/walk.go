@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+)
+
+// checkWalk checks that the guest of a booked room can be walked for nights nights on
+// today and reports whether the walk covers the whole stay. Only confirmed overnight
+// guests who have not checked in are walked, on their arrival date; a room of a
+// multi-room booking keeps at least one night.
+func checkWalk(stay *models.WalkStay, nights int, today time.Time) (bool, error) {
+	if stay.StayType != models.StayTypeOvernight {
+		return false, errors.New("only overnight stays can be walked")
+	}
+	if stay.BookingStatus != "Confirmed" {
+		return false, errors.New("only confirmed bookings can be walked")
+	}
+	if stay.CheckedIn {
+		return false, errors.New("the guest has already checked in")
+	}
+	if !dateOnly(stay.CheckInDate).Equal(dateOnly(today)) {
+		return false, errors.New("guests can only be walked on their arrival date")
+	}
+
+	stayNights := nightsBetween(stay.CheckInDate, stay.CheckOutDate)
+	if nights > stayNights {
+		return false, fmt.Errorf("the stay has only %d nights", stayNights)
+	}
+	fullStay := nights == stayNights
+	if fullStay && stay.BookedRooms > 1 {
+		return false, errors.New("a room of a multi-room booking can only be walked for part of its stay")
+	}
+	return fullStay, nil
+}
+
+// WalkGuest relocates an arriving guest to a partner hotel for the first nights of the
+// stay and records the compensation given. The walked nights go back to inventory and
+// their room revenue is taken off the booking; walking the whole stay cancels the
+// booking.
+func (s *BookingService) WalkGuest(ctx context.Context, req *models.WalkGuestRequest, staffID *int) (*models.BookingWalk, error) {
+	stay, err := s.bookingRepo.GetWalkStay(ctx, req.BookingDetailID)
+	if err != nil {
+		return nil, err
+	}
+	if stay == nil {
+		return nil, errors.New("booking detail not found")
+	}
+
+	fullStay, err := checkWalk(stay, req.Nights, time.Now().Truncate(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	walk := &models.BookingWalk{
+		BookingDetailID:    stay.BookingDetailID,
+		BookingID:          stay.BookingID,
+		PartnerHotelID:     req.PartnerHotelID,
+		WalkDate:           stay.CheckInDate,
+		Nights:             req.Nights,
+		FullStay:           fullStay,
+		CompensationAmount: roundAmount(req.CompensationAmount),
+		TransportProvided:  req.TransportProvided,
+		WalkedBy:           staffID,
+	}
+	if notes := strings.TrimSpace(req.CompensationNotes); notes != "" {
+		walk.CompensationNotes = &notes
+	}
+	if notes := strings.TrimSpace(req.Notes); notes != "" {
+		walk.Notes = &notes
+	}
+
+	if err := s.bookingRepo.WalkGuest(ctx, walk); err != nil {
+		return nil, err
+	}
+
+	// The walked nights are free again, which may bring an oversold night back in line
+	if s.listener != nil {
+		s.listener.InventoryReleased()
+	}
+	return walk, nil
+}
+
+// GetWalks lists the guests walked on a date, today by default
+func (s *BookingService) GetWalks(ctx context.Context, dateStr string) ([]models.BookingWalk, error) {
+	date := time.Now().Truncate(24 * time.Hour)
+	if dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		date = parsed
+	}
+
+	walks, err := s.bookingRepo.GetWalks(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	if walks == nil {
+		walks = []models.BookingWalk{}
+	}
+	return walks, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hotel-booking-system/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWalk(t *testing.T) {
+	today := addOnDate("2025-07-04")
+	stay := func() *models.WalkStay {
+		return &models.WalkStay{
+			CheckInDate:   today,
+			CheckOutDate:  addOnDate("2025-07-07"),
+			StayType:      models.StayTypeOvernight,
+			BookingStatus: "Confirmed",
+			BookedRooms:   1,
+		}
+	}
+
+	fullStay, err := checkWalk(stay(), 1, today)
+	assert.NoError(t, err)
+	assert.False(t, fullStay)
+
+	fullStay, err = checkWalk(stay(), 3, today)
+	assert.NoError(t, err)
+	assert.True(t, fullStay)
+
+	_, err = checkWalk(stay(), 4, today)
+	assert.EqualError(t, err, "the stay has only 3 nights")
+
+	multiRoom := stay()
+	multiRoom.BookedRooms = 2
+	_, err = checkWalk(multiRoom, 3, today)
+	assert.Error(t, err, "a room of a multi-room booking keeps a night")
+	_, err = checkWalk(multiRoom, 2, today)
+	assert.NoError(t, err)
+
+	_, err = checkWalk(stay(), 1, addOnDate("2025-07-03"))
+	assert.EqualError(t, err, "guests can only be walked on their arrival date")
+
+	checkedIn := stay()
+	checkedIn.CheckedIn = true
+	_, err = checkWalk(checkedIn, 1, today)
+	assert.Error(t, err)
+
+	pending := stay()
+	pending.BookingStatus = "PendingPayment"
+	_, err = checkWalk(pending, 1, today)
+	assert.Error(t, err)
+
+	dayUse := stay()
+	dayUse.StayType = models.StayTypeDayUse
+	_, err = checkWalk(dayUse, 1, today)
+	assert.Error(t, err)
+}
@@ -12,6 +12,7 @@ const (
 	PricingCalendarPrefix = "pricing_calendar"
 	RateTiersPrefix      = "rate_tiers"
 	RatePricingPrefix    = "rate_pricing"
+	AvailabilityCalendarPrefix = "availability_calendar"
 )
 
 // Cache expiration times
@@ -20,6 +21,7 @@ const (
 	PricingCalendarExpiration = 6 * time.Hour   // Pricing changes occasionally
 	RateTiersExpiration      = 24 * time.Hour  // Rate tiers rarely change
 	RatePricingExpiration    = 12 * time.Hour  // Rate pricing changes occasionally
+	AvailabilityCalendarExpiration = 15 * time.Minute // Dropped whenever inventory changes
 )
 
 // Key generators
@@ -46,3 +48,11 @@ func RatePricingKey(ratePlanID, roomTypeID int) string {
 func RatePricingMatrixKey() string {
 	return fmt.Sprintf("%s:matrix", RatePricingPrefix)
 }
+
+// AvailabilityCalendarKey is the key of a month's calendar, for one room type or all
+func AvailabilityCalendarKey(month string, roomTypeID *int) string {
+	if roomTypeID == nil {
+		return fmt.Sprintf("%s:%s:all", AvailabilityCalendarPrefix, month)
+	}
+	return fmt.Sprintf("%s:%s:%d", AvailabilityCalendarPrefix, month, *roomTypeID)
+}
@@ -97,6 +97,45 @@ func (c *RedisCache) Exists(key string) (bool, error) {
 	return result > 0, nil
 }
 
+// Publish sends a value to every subscriber of a channel
+func (c *RedisCache) Publish(channel string, value interface{}) error {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	if err := c.client.Publish(c.ctx, channel, jsonData).Err(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", channel, err)
+	}
+
+	return nil
+}
+
+// Subscribe calls handle with the payload of every message published on a channel
+// until ctx is done
+func (c *RedisCache) Subscribe(ctx context.Context, channel string, handle func(payload []byte)) error {
+	pubsub := c.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Wait for the subscription so messages published from now on are received
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			handle([]byte(msg.Payload))
+		}
+	}
+}
+
 // Close closes the Redis connection
 func (c *RedisCache) Close() error {
 	return c.client.Close()
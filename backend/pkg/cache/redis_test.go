@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -106,6 +107,30 @@ func TestRedisCache(t *testing.T) {
 		assert.False(t, exists)
 	})
 
+	t.Run("Publish and Subscribe", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		received := make(chan string, 1)
+		go func() {
+			_ = cache.Subscribe(ctx, "test:channel", func(payload []byte) {
+				received <- string(payload)
+			})
+		}()
+
+		// Publish until the subscription is up
+		require.Eventually(t, func() bool {
+			require.NoError(t, cache.Publish("test:channel", map[string]int{"room_id": 101}))
+			select {
+			case payload := <-received:
+				assert.JSONEq(t, `{"room_id":101}`, payload)
+				return true
+			case <-time.After(50 * time.Millisecond):
+				return false
+			}
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
 	t.Run("Complex Data Structure", func(t *testing.T) {
 		type RoomType struct {
 			ID          int
@@ -163,4 +188,10 @@ func TestCacheKeys(t *testing.T) {
 		key := RatePricingMatrixKey()
 		assert.Equal(t, "rate_pricing:matrix", key)
 	})
+
+	t.Run("AvailabilityCalendarKey", func(t *testing.T) {
+		roomTypeID := 2
+		assert.Equal(t, "availability_calendar:2024-01:all", AvailabilityCalendarKey("2024-01", nil))
+		assert.Equal(t, "availability_calendar:2024-01:2", AvailabilityCalendarKey("2024-01", &roomTypeID))
+	})
 }
@@ -13,6 +13,7 @@ type Config struct {
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	Storage  StorageConfig
 }
 
 // ServerConfig holds server configuration
@@ -47,6 +48,13 @@ type JWTConfig struct {
 	Secret string
 }
 
+// StorageConfig holds the local blob store configuration for uploads
+type StorageConfig struct {
+	UploadDir string // Directory uploads are stored in
+	ServePath string // Route the API serves uploads at
+	URLPrefix string // Path or URL public upload URLs start with, such as a CDN origin
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if it exists (for development)
@@ -88,6 +96,11 @@ func Load() (*Config, error) {
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
 		},
+		Storage: StorageConfig{
+			UploadDir: getEnv("UPLOAD_DIR", "./uploads"),
+			ServePath: getEnv("UPLOAD_SERVE_PATH", "/uploads"),
+			URLPrefix: getEnv("UPLOAD_URL_PREFIX", "/uploads"),
+		},
 	}
 
 	// Validate required fields
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore stores uploaded files by key. Keys are slash-separated paths such as
+// "room-types/3/ab12cd34-large.jpg".
+type BlobStore interface {
+	// Put stores data under key, replacing any existing blob
+	Put(ctx context.Context, key string, data []byte) error
+	// Delete removes the blob under key; a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the blob under key
+	URL(key string) string
+}
+
+// ErrInvalidKey is returned for keys that are empty or leave the store
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// LocalBlobStore stores blobs as files under a directory on local disk
+type LocalBlobStore struct {
+	dir       string
+	urlPrefix string
+}
+
+// NewLocalBlobStore creates a blob store in dir, creating it if needed.
+// Blob URLs are urlPrefix, a path or an origin such as a CDN, followed by their key.
+func NewLocalBlobStore(dir, urlPrefix string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob store directory: %w", err)
+	}
+	return &LocalBlobStore{
+		dir:       dir,
+		urlPrefix: strings.TrimSuffix(urlPrefix, "/"),
+	}, nil
+}
+
+// Dir returns the directory the blobs are stored in
+func (s *LocalBlobStore) Dir() string {
+	return s.dir
+}
+
+// Put writes data to a temporary file and renames it into place, so a blob is never
+// served half written
+func (s *LocalBlobStore) Put(ctx context.Context, key string, data []byte) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the file of a blob
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// URL returns the URL the blob is served at
+func (s *LocalBlobStore) URL(key string) string {
+	return s.urlPrefix + "/" + key
+}
+
+// path returns the file of a key, rejecting keys outside the store directory
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
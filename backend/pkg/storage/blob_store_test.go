@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := NewLocalBlobStore(filepath.Join(dir, "uploads"), "/uploads/")
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+
+	key := "room-types/3/ab12cd34-large.jpg"
+	if err := store.Put(ctx, key, []byte("image")); err != nil {
+		t.Fatalf("Failed to put blob: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "uploads", "room-types", "3", "ab12cd34-large.jpg"))
+	if err != nil || string(data) != "image" {
+		t.Fatalf("Expected the blob on disk, got %q (%v)", data, err)
+	}
+
+	if url := store.URL(key); url != "/uploads/room-types/3/ab12cd34-large.jpg" {
+		t.Errorf("Unexpected URL %q", url)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Failed to delete blob: %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Expected deleting a missing blob to succeed, got %v", err)
+	}
+}
+
+func TestLocalBlobStoreRejectsKeysOutsideTheStore(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir(), "/uploads")
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+
+	for _, key := range []string{"", "../secret", "/etc/passwd", "room-types/../../secret", "room-types//3"} {
+		if err := store.Put(context.Background(), key, []byte("x")); err != ErrInvalidKey {
+			t.Errorf("Put(%q): expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Decoders for uploaded images
+	_ "image/gif"
+	_ "image/png"
+)
+
+// MaxImagePixels limits the size of a decoded upload to keep memory use bounded
+const MaxImagePixels = 50_000_000
+
+// ImageQuality is the JPEG quality of resized images
+const ImageQuality = 85
+
+// ImageVariant is a resized copy of an uploaded image that fits in a MaxSize square
+type ImageVariant struct {
+	Name    string
+	MaxSize int
+}
+
+// ImageVariants are the sizes stored for every uploaded image, largest first
+var ImageVariants = []ImageVariant{
+	{Name: "large", MaxSize: 1920},
+	{Name: "medium", MaxSize: 960},
+	{Name: "thumbnail", MaxSize: 320},
+}
+
+// ErrUnsupportedImage is returned when an upload is not a JPEG, PNG or GIF image
+var ErrUnsupportedImage = errors.New("unsupported image type, use JPEG, PNG or GIF")
+
+// DecodeImage decodes an uploaded image. It is turned upright by its EXIF orientation
+// and flattened onto white. No metadata is kept, so encoding the result strips EXIF.
+func DecodeImage(data []byte) (*image.RGBA, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > MaxImagePixels {
+		return nil, fmt.Errorf("image is too large: %dx%d", config.Width, config.Height)
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Over)
+
+	if format == "jpeg" {
+		img = orientImage(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// ResizeImage scales an image down to fit in a maxSize square, keeping its aspect
+// ratio, by averaging the source pixels under each target pixel. Smaller images are
+// returned as they are.
+func ResizeImage(src *image.RGBA, maxSize int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= maxSize && h <= maxSize {
+		return src
+	}
+
+	nw, nh := maxSize, maxSize
+	if w > h {
+		nh = (h*maxSize + w/2) / w
+	} else {
+		nw = (w*maxSize + h/2) / h
+	}
+	if nw < 1 {
+		nw = 1
+	}
+	if nh < 1 {
+		nh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, nw, nh))
+	for dy := 0; dy < nh; dy++ {
+		sy0, sy1 := dy*h/nh, (dy+1)*h/nh
+		if sy1 == sy0 {
+			sy1 = sy0 + 1
+		}
+		for dx := 0; dx < nw; dx++ {
+			sx0, sx1 := dx*w/nw, (dx+1)*w/nw
+			if sx1 == sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, b, a, n int
+			for sy := sy0; sy < sy1; sy++ {
+				i := src.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+
+			j := dst.PixOffset(dx, dy)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG encodes an image as a JPEG without metadata
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: ImageQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// jpegOrientation reads the EXIF orientation (1-8) of a JPEG file, or 1 when it has none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // Image data starts; no EXIF before it
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		if segment := data[i+4 : end]; marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i = end
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of EXIF TIFF data
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for k := 0; k < entries; k++ {
+		entry := ifd + 2 + k*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orientImage turns an image stored with an EXIF orientation upright
+func orientImage(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // Rotated a quarter turn
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		for dx := 0; dx < dw; dx++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // Mirrored
+				sx, sy = w-1-dx, dy
+			case 3: // Upside down
+				sx, sy = w-1-dx, h-1-dy
+			case 4: // Mirrored upside down
+				sx, sy = dx, h-1-dy
+			case 5: // Mirrored, turned left
+				sx, sy = dy, dx
+			case 6: // Turned left, needs a right turn
+				sx, sy = dy, h-1-dx
+			case 7: // Mirrored, turned right
+				sx, sy = w-1-dy, h-1-dx
+			case 8: // Turned right, needs a left turn
+				sx, sy = w-1-dy, dx
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return dst
+}
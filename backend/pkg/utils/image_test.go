@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// testJPEG encodes a w x h JPEG, red on its left half and blue on the right, with an
+// EXIF segment holding the orientation when orientation is not 0
+func testJPEG(t *testing.T, w, h, orientation int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("Failed to encode jpeg: %v", err)
+	}
+	data := buf.Bytes()
+	if orientation == 0 {
+		return data
+	}
+
+	// Big-endian TIFF with one IFD entry: orientation (0x0112), SHORT, count 1
+	tiff := []byte("MM\x00\x2A\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00")
+	binary.BigEndian.PutUint16(tiff[18:], uint16(orientation))
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	if got := jpegOrientation(testJPEG(t, 8, 4, 6)); got != 6 {
+		t.Errorf("Expected orientation 6, got %d", got)
+	}
+	if got := jpegOrientation(testJPEG(t, 8, 4, 0)); got != 1 {
+		t.Errorf("Expected orientation 1 without EXIF, got %d", got)
+	}
+	if got := jpegOrientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("Expected orientation 1 for other data, got %d", got)
+	}
+}
+
+func TestDecodeImageOrientsAndStripsEXIF(t *testing.T) {
+	img, err := DecodeImage(testJPEG(t, 80, 40, 6))
+	if err != nil {
+		t.Fatalf("Failed to decode image: %v", err)
+	}
+
+	// A right turn makes the landscape image portrait with the red half on top
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 80 {
+		t.Fatalf("Expected 40x80, got %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	if top := img.RGBAAt(20, 10); top.R < 200 || top.B > 60 {
+		t.Errorf("Expected red at the top, got %v", top)
+	}
+	if bottom := img.RGBAAt(20, 70); bottom.B < 200 || bottom.R > 60 {
+		t.Errorf("Expected blue at the bottom, got %v", bottom)
+	}
+
+	encoded, err := EncodeJPEG(img)
+	if err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+	if bytes.Contains(encoded, []byte("Exif\x00\x00")) {
+		t.Error("Expected EXIF to be stripped")
+	}
+}
+
+func TestDecodeImageRejectsOtherFiles(t *testing.T) {
+	if _, err := DecodeImage([]byte("room,first_name\n")); err != ErrUnsupportedImage {
+		t.Errorf("Expected ErrUnsupportedImage, got %v", err)
+	}
+}
+
+func TestResizeImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4000, 3000))
+
+	tests := []struct {
+		maxSize int
+		w, h    int
+	}{
+		{1920, 1920, 1440},
+		{320, 320, 240},
+		{5000, 4000, 3000}, // Never enlarged
+	}
+	for _, tt := range tests {
+		got := ResizeImage(src, tt.maxSize).Bounds()
+		if got.Dx() != tt.w || got.Dy() != tt.h {
+			t.Errorf("ResizeImage(%d): expected %dx%d, got %dx%d", tt.maxSize, tt.w, tt.h, got.Dx(), got.Dy())
+		}
+	}
+
+	portrait := ResizeImage(image.NewRGBA(image.Rect(0, 0, 300, 900)), 320).Bounds()
+	if portrait.Dx() != 107 || portrait.Dy() != 320 {
+		t.Errorf("Expected 107x320, got %dx%d", portrait.Dx(), portrait.Dy())
+	}
+}
+
+func TestResizeImageAveragesPixels(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		src.SetRGBA(x, 0, color.RGBA{R: 200, A: 255})
+		src.SetRGBA(x, 1, color.RGBA{R: 100, A: 255})
+	}
+
+	dst := ResizeImage(src, 2)
+	if got := dst.RGBAAt(0, 0); got.R != 150 {
+		t.Errorf("Expected the average of the block, got %v", got)
+	}
+}
@@ -11,7 +11,7 @@ import (
 type Claims struct {
 	UserID   int    `json:"user_id"`
 	Email    string `json:"email"`
-	Role     string `json:"role"`      // Role code (GUEST, RECEPTIONIST, HOUSEKEEPER, MANAGER)
+	Role     string `json:"role"`      // Role code (GUEST, RECEPTIONIST, HOUSEKEEPER, ENGINEER, MANAGER)
 	UserType string `json:"user_type"` // User type (guest, staff)
 	jwt.RegisteredClaims
 }
@@ -111,7 +111,7 @@ func (c *Claims) IsGuest() bool {
 
 // IsStaff checks if the token belongs to staff
 func (c *Claims) IsStaff() bool {
-	return c.Role == "RECEPTIONIST" || c.Role == "HOUSEKEEPER" || c.Role == "MANAGER"
+	return c.Role == "RECEPTIONIST" || c.Role == "HOUSEKEEPER" || c.Role == "ENGINEER" || c.Role == "MANAGER"
 }
 
 // IsManager checks if the token belongs to a manager
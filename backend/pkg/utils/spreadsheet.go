@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedSpreadsheet is returned when the file is neither CSV nor XLSX
+var ErrUnsupportedSpreadsheet = errors.New("unsupported file type, use .csv or .xlsx")
+
+// ReadSpreadsheet reads a CSV or XLSX file into rows of cell values.
+// The format is chosen from the file extension. For XLSX only the first
+// worksheet is read; empty trailing rows are dropped.
+func ReadSpreadsheet(filename string, data []byte) ([][]string, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return readCSV(data)
+	case ".xlsx":
+		return readXLSX(data)
+	default:
+		return nil, ErrUnsupportedSpreadsheet
+	}
+}
+
+// ParseSpreadsheetDate parses a date cell as written by a person (YYYY-MM-DD,
+// DD/MM/YYYY) or as stored by Excel (serial day number)
+func ParseSpreadsheetDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		// Excel day 0 is 1899-12-30 (accounting for the 1900 leap year bug)
+		base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		return base.AddDate(0, 0, int(serial)), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", value)
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	// Strip UTF-8 BOM written by Excel "CSV UTF-8" exports
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	return trimEmptyRows(rows), nil
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxStringItem `xml:"si"`
+}
+
+type xlsxStringItem struct {
+	Text string        `xml:"t"`
+	Runs []xlsxTextRun `xml:"r"`
+}
+
+type xlsxTextRun struct {
+	Text string `xml:"t"`
+}
+
+func (i xlsxStringItem) value() string {
+	if len(i.Runs) == 0 {
+		return i.Text
+	}
+	var b strings.Builder
+	for _, run := range i.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxWorksheet struct {
+	Rows []xlsxRow `xml:"sheetData>row"`
+}
+
+type xlsxRow struct {
+	Index int        `xml:"r,attr"`
+	Cells []xlsxCell `xml:"c"`
+}
+
+type xlsxCell struct {
+	Ref    string         `xml:"r,attr"`
+	Type   string         `xml:"t,attr"`
+	Value  string         `xml:"v"`
+	Inline xlsxStringItem `xml:"is"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xlsx: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	var sheets []string
+	for _, f := range archive.File {
+		files[f.Name] = f
+		if strings.HasPrefix(f.Name, "xl/worksheets/") && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f.Name)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, errors.New("xlsx file has no worksheets")
+	}
+
+	sheetName := "xl/worksheets/sheet1.xml"
+	if _, ok := files[sheetName]; !ok {
+		sort.Strings(sheets)
+		sheetName = sheets[0]
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, fmt.Errorf("failed to read shared strings: %w", err)
+		}
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(files[sheetName], &sheet); err != nil {
+		return nil, fmt.Errorf("failed to read worksheet: %w", err)
+	}
+
+	var rows [][]string
+	for i, row := range sheet.Rows {
+		rowIndex := row.Index
+		if rowIndex == 0 {
+			rowIndex = i + 1
+		}
+		// Pad skipped rows so row numbers match what the user sees in Excel
+		for len(rows) < rowIndex-1 {
+			rows = append(rows, []string{})
+		}
+
+		var values []string
+		for j, cell := range row.Cells {
+			col := j
+			if cell.Ref != "" {
+				col = xlsxColumnIndex(cell.Ref)
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, fmt.Errorf("invalid shared string reference in cell %s", cell.Ref)
+				}
+				value = shared.Items[idx].value()
+			case "inlineStr":
+				value = cell.Inline.value()
+			case "b":
+				if value == "1" {
+					value = "TRUE"
+				} else {
+					value = "FALSE"
+				}
+			}
+			values[col] = strings.TrimSpace(value)
+		}
+		rows = append(rows, values)
+	}
+
+	return trimEmptyRows(rows), nil
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(content, v)
+}
+
+// xlsxColumnIndex converts a cell reference such as "C12" into a zero-based column index
+func xlsxColumnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
+
+func trimEmptyRows(rows [][]string) [][]string {
+	for len(rows) > 0 && isEmptyRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+func isEmptyRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestReadSpreadsheetCSV(t *testing.T) {
+	data := []byte("\xEF\xBB\xBFroom,first_name,last_name\n1,Somchai,Jaidee\n2, Jane ,Doe\n\n")
+
+	rows, err := ReadSpreadsheet("rooming.CSV", data)
+	if err != nil {
+		t.Fatalf("Failed to read csv: %v", err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	if rows[0][0] != "room" {
+		t.Errorf("Expected BOM to be stripped, got %q", rows[0][0])
+	}
+	if rows[2][1] != "Jane " {
+		t.Errorf("Expected leading space to be trimmed, got %q", rows[2][1])
+	}
+}
+
+func TestReadSpreadsheetXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	files := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>room</t></si><si><t>first_name</t></si><si><r><t>Som</t></r><r><t>chai</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+			`<row r="3"><c r="A3"><v>1</v></c><c r="C3" t="inlineStr"><is><t>extra</t></is></c><c r="B3" t="s"><v>2</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	rows, err := ReadSpreadsheet("rooming.xlsx", buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to read xlsx: %v", err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows (including skipped row 2), got %d", len(rows))
+	}
+	if rows[0][1] != "first_name" {
+		t.Errorf("Expected shared string header, got %q", rows[0][1])
+	}
+	if len(rows[1]) != 0 {
+		t.Errorf("Expected empty padding row, got %v", rows[1])
+	}
+	if rows[2][0] != "1" || rows[2][1] != "Somchai" || rows[2][2] != "extra" {
+		t.Errorf("Unexpected third row: %v", rows[2])
+	}
+}
+
+func TestReadSpreadsheetUnsupported(t *testing.T) {
+	if _, err := ReadSpreadsheet("rooming.pdf", []byte("x")); err != ErrUnsupportedSpreadsheet {
+		t.Errorf("Expected ErrUnsupportedSpreadsheet, got %v", err)
+	}
+}
+
+func TestParseSpreadsheetDate(t *testing.T) {
+	want := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	for _, input := range []string{"2025-03-14", "14/03/2025", "45730"} {
+		got, err := ParseSpreadsheetDate(input)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %v", input, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("Parse %q: expected %s, got %s", input, want.Format("2006-01-02"), got.Format("2006-01-02"))
+		}
+	}
+
+	if _, err := ParseSpreadsheetDate("next tuesday"); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+}
@@ -0,0 +1,46 @@
+-- ============================================================================
+-- Migration 022: Rooming List Import
+-- ============================================================================
+-- Description: Adds a rooming list reference to booking_guests so that a
+--              group's rooming list (CSV/XLSX) can be uploaded repeatedly.
+--              Rows carrying the same reference update the existing guest
+--              instead of inserting a duplicate.
+-- ============================================================================
+
+-- Reference supplied by the rooming list (or derived from the guest name)
+ALTER TABLE booking_guests
+ADD COLUMN IF NOT EXISTS rooming_list_ref VARCHAR(100);
+
+-- Requested arrival/departure for the guest inside the booked block
+ALTER TABLE booking_guests
+ADD COLUMN IF NOT EXISTS arrival_date DATE;
+
+ALTER TABLE booking_guests
+ADD COLUMN IF NOT EXISTS departure_date DATE;
+
+ALTER TABLE booking_guests
+DROP CONSTRAINT IF EXISTS chk_booking_guests_stay_dates;
+
+ALTER TABLE booking_guests
+ADD CONSTRAINT chk_booking_guests_stay_dates
+CHECK (arrival_date IS NULL OR departure_date IS NULL OR departure_date > arrival_date);
+
+-- Index for re-upload lookups
+CREATE INDEX IF NOT EXISTS idx_booking_guests_rooming_list_ref
+ON booking_guests(booking_detail_id, rooming_list_ref)
+WHERE rooming_list_ref IS NOT NULL;
+
+-- Comments
+COMMENT ON COLUMN booking_guests.rooming_list_ref IS 'Stable row reference from an imported rooming list, used to update instead of duplicate on re-upload';
+COMMENT ON COLUMN booking_guests.arrival_date IS 'Guest arrival date from the rooming list (must fall inside the booking detail dates)';
+COMMENT ON COLUMN booking_guests.departure_date IS 'Guest departure date from the rooming list (must fall inside the booking detail dates)';
+
+-- Verification query
+SELECT
+    column_name,
+    data_type,
+    is_nullable
+FROM information_schema.columns
+WHERE table_name = 'booking_guests'
+AND column_name IN ('rooming_list_ref', 'arrival_date', 'departure_date')
+ORDER BY ordinal_position;
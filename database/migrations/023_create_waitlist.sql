@@ -0,0 +1,183 @@
+-- ============================================================================
+-- Migration 023: Waitlist for Sold-Out Dates
+-- ============================================================================
+-- Description: Guests can join a waitlist for a room type and date range when
+--              search returns nothing. When inventory frees up (cancellation,
+--              expired holds, allotment increase) match_waitlist() creates a
+--              time-limited hold for the next waitlisted guest. Unclaimed
+--              offers expire and the inventory goes back to the queue.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 023_create_waitlist.sql
+-- ============================================================================
+
+-- ============================================================================
+-- SECTION 1: WAITLIST TABLE
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    waitlist_id SERIAL PRIMARY KEY,
+    guest_id INT REFERENCES guests(guest_id) ON DELETE SET NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(20),
+    room_type_id INT NOT NULL REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    check_in_date DATE NOT NULL,
+    check_out_date DATE NOT NULL,
+    num_guests INT NOT NULL DEFAULT 1 CHECK (num_guests > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'Waiting'
+        CHECK (status IN ('Waiting', 'Offered', 'Claimed', 'Expired', 'Cancelled')),
+    session_id VARCHAR(255),
+    offered_at TIMESTAMP,
+    offer_expires_at TIMESTAMP,
+    offer_count INT NOT NULL DEFAULT 0,
+    booking_id INT REFERENCES bookings(booking_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_waitlist_dates CHECK (check_out_date > check_in_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_queue
+    ON waitlist_entries(room_type_id, created_at)
+    WHERE status = 'Waiting';
+CREATE INDEX IF NOT EXISTS idx_waitlist_offer_expiry
+    ON waitlist_entries(offer_expires_at)
+    WHERE status = 'Offered';
+CREATE INDEX IF NOT EXISTS idx_waitlist_session ON waitlist_entries(session_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_guest ON waitlist_entries(guest_id);
+
+DROP TRIGGER IF EXISTS update_waitlist_entries_updated_at ON waitlist_entries;
+CREATE TRIGGER update_waitlist_entries_updated_at
+    BEFORE UPDATE ON waitlist_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE waitlist_entries IS 'คิวรอห้องว่างสำหรับวันที่ห้องเต็ม';
+COMMENT ON COLUMN waitlist_entries.session_id IS 'Session ID (token สุ่ม) ของ hold ที่เสนอให้ผู้เข้าพัก (ใช้เป็น session_id ตอนสร้างการจอง)';
+COMMENT ON COLUMN waitlist_entries.offer_expires_at IS 'เวลาหมดอายุของข้อเสนอ (เท่ากับ hold_expiry)';
+
+-- ============================================================================
+-- SECTION 2: MATCH FUNCTION
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION match_waitlist(
+    p_offer_minutes INT DEFAULT 60
+) RETURNS TABLE(
+    event VARCHAR(20),
+    waitlist_id INT,
+    session_id VARCHAR(255),
+    offer_expires_at TIMESTAMP
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_entry RECORD;
+    v_session VARCHAR(255);
+    v_success BOOLEAN;
+    v_message TEXT;
+    v_expiry TIMESTAMP;
+BEGIN
+    -- ------------------------------------------------------------------------
+    -- STEP 1: หมดอายุข้อเสนอที่ไม่ได้ใช้ และคืน tentative_count
+    -- ------------------------------------------------------------------------
+    FOR v_entry IN
+        UPDATE waitlist_entries w
+        SET status = 'Expired'
+        WHERE w.status = 'Offered'
+          AND w.offer_expires_at < NOW()
+        RETURNING w.waitlist_id, w.session_id
+    LOOP
+        UPDATE room_inventory ri
+        SET tentative_count = GREATEST(0, ri.tentative_count - h.hold_count),
+            updated_at = NOW()
+        FROM (
+            SELECT bh.room_type_id, bh.date, COUNT(*) AS hold_count
+            FROM booking_holds bh
+            WHERE bh.session_id = v_entry.session_id
+            GROUP BY bh.room_type_id, bh.date
+        ) h
+        WHERE ri.room_type_id = h.room_type_id
+          AND ri.date = h.date;
+
+        DELETE FROM booking_holds bh WHERE bh.session_id = v_entry.session_id;
+
+        RETURN QUERY SELECT 'Expired'::VARCHAR(20), v_entry.waitlist_id, v_entry.session_id, NULL::TIMESTAMP;
+    END LOOP;
+
+    -- ------------------------------------------------------------------------
+    -- STEP 2: รายการที่วันเช็คอินผ่านไปแล้ว
+    -- ------------------------------------------------------------------------
+    FOR v_entry IN
+        UPDATE waitlist_entries w
+        SET status = 'Expired'
+        WHERE w.status = 'Waiting'
+          AND w.check_in_date < CURRENT_DATE
+        RETURNING w.waitlist_id
+    LOOP
+        RETURN QUERY SELECT 'Lapsed'::VARCHAR(20), v_entry.waitlist_id, NULL::VARCHAR(255), NULL::TIMESTAMP;
+    END LOOP;
+
+    -- ------------------------------------------------------------------------
+    -- STEP 3: เสนอห้องให้ผู้รอตามลำดับ (FIFO) เมื่อทุกคืนมีห้องว่าง
+    -- ------------------------------------------------------------------------
+    FOR v_entry IN
+        SELECT w.waitlist_id, w.room_type_id, w.check_in_date, w.check_out_date, w.offer_count
+        FROM waitlist_entries w
+        WHERE w.status = 'Waiting'
+          AND NOT EXISTS (
+              SELECT 1
+              FROM generate_series(w.check_in_date, w.check_out_date - 1, INTERVAL '1 day') AS d(day)
+              LEFT JOIN room_inventory ri
+                ON ri.room_type_id = w.room_type_id AND ri.date = d.day::DATE
+              WHERE ri.date IS NULL
+                 OR ri.allotment - ri.booked_count - ri.tentative_count <= 0
+          )
+        ORDER BY w.created_at, w.waitlist_id
+    LOOP
+        -- session ของข้อเสนอเป็น token สุ่ม เดาจาก waitlist_id ไม่ได้
+        v_session := 'waitlist-' || gen_random_uuid()::TEXT;
+
+        -- create_booking_hold ไม่ rollback tentative_count ของคืนก่อนหน้าเมื่อคืนใดเต็ม
+        -- จึงรันใน sub-transaction และ rollback เองเมื่อไม่สำเร็จ
+        BEGIN
+            SELECT h.success, h.message, h.expiry_time
+            INTO v_success, v_message, v_expiry
+            FROM create_booking_hold(v_session, NULL, v_entry.room_type_id,
+                                     v_entry.check_in_date, v_entry.check_out_date) h;
+
+            IF NOT v_success THEN
+                RAISE EXCEPTION 'waitlist hold failed: %', v_message;
+            END IF;
+        EXCEPTION
+            WHEN OTHERS THEN
+                v_success := FALSE;
+        END;
+
+        -- ห้องอาจถูกเสนอให้ผู้รอก่อนหน้าไปแล้วในรอบนี้
+        CONTINUE WHEN NOT v_success;
+
+        v_expiry := NOW() + (p_offer_minutes || ' minutes')::INTERVAL;
+
+        UPDATE booking_holds bh
+        SET hold_expiry = v_expiry
+        WHERE bh.session_id = v_session;
+
+        UPDATE waitlist_entries w
+        SET status = 'Offered',
+            session_id = v_session,
+            offered_at = NOW(),
+            offer_expires_at = v_expiry,
+            offer_count = w.offer_count + 1
+        WHERE w.waitlist_id = v_entry.waitlist_id;
+
+        RETURN QUERY SELECT 'Offered'::VARCHAR(20), v_entry.waitlist_id, v_session, v_expiry;
+    END LOOP;
+END;
+$$;
+
+COMMENT ON FUNCTION match_waitlist IS
+'จับคู่ waitlist กับ inventory ที่ว่าง
+- หมดอายุข้อเสนอที่ไม่ได้ใช้และคืนสต็อก
+- สร้าง hold ให้ผู้รอคนถัดไป (FIFO) พร้อมกำหนดเวลาหมดอายุของข้อเสนอ
+- ควรถูกเรียกโดย background job เมื่อมีการยกเลิก, hold หมดอายุ หรือเพิ่ม allotment';
+
+\echo 'Migration 023 completed: waitlist_entries table and match_waitlist() function created'
@@ -0,0 +1,52 @@
+-- ============================================================================
+-- Migration 024: Length-of-Stay and Arrival Restrictions
+-- ============================================================================
+-- Description: Per-date, per-room-type, per-rate-plan stay restrictions:
+--   - min_los / max_los      : minimum / maximum nights, evaluated on the arrival date
+--   - closed_to_arrival      : no check-in on this date
+--   - closed_to_departure    : no check-out on this date
+--   - stop_sell              : the date cannot be sold at all
+--   A NULL rate_plan_id applies to every rate plan of the room type.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 024_create_rate_restrictions.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS rate_restrictions (
+    restriction_id SERIAL PRIMARY KEY,
+    room_type_id INT NOT NULL REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    rate_plan_id INT REFERENCES rate_plans(rate_plan_id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    min_los INT CHECK (min_los IS NULL OR min_los >= 1),
+    max_los INT CHECK (max_los IS NULL OR max_los >= 1),
+    closed_to_arrival BOOLEAN NOT NULL DEFAULT FALSE,
+    closed_to_departure BOOLEAN NOT NULL DEFAULT FALSE,
+    stop_sell BOOLEAN NOT NULL DEFAULT FALSE,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_rate_restrictions_los CHECK (min_los IS NULL OR max_los IS NULL OR max_los >= min_los)
+);
+
+-- One row per room type / rate plan (or all plans) / date
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rate_restrictions_unique
+    ON rate_restrictions(room_type_id, (COALESCE(rate_plan_id, 0)), date);
+
+CREATE INDEX IF NOT EXISTS idx_rate_restrictions_date
+    ON rate_restrictions(date, room_type_id);
+
+DROP TRIGGER IF EXISTS update_rate_restrictions_updated_at ON rate_restrictions;
+CREATE TRIGGER update_rate_restrictions_updated_at
+    BEFORE UPDATE ON rate_restrictions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE rate_restrictions IS 'ข้อจำกัดการขายรายวัน (MinLOS, MaxLOS, CTA, CTD, Stop-sell) ต่อประเภทห้องและ rate plan';
+COMMENT ON COLUMN rate_restrictions.rate_plan_id IS 'NULL = ใช้กับทุก rate plan';
+COMMENT ON COLUMN rate_restrictions.min_los IS 'จำนวนคืนขั้นต่ำ (ตรวจสอบจากวันเช็คอิน)';
+COMMENT ON COLUMN rate_restrictions.max_los IS 'จำนวนคืนสูงสุด (ตรวจสอบจากวันเช็คอิน)';
+COMMENT ON COLUMN rate_restrictions.closed_to_arrival IS 'ห้ามเช็คอินในวันนี้';
+COMMENT ON COLUMN rate_restrictions.closed_to_departure IS 'ห้ามเช็คเอาท์ในวันนี้';
+COMMENT ON COLUMN rate_restrictions.stop_sell IS 'ปิดการขายในคืนนี้';
+
+\echo 'Migration 024 completed: rate_restrictions table created'
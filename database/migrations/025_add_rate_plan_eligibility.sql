@@ -0,0 +1,194 @@
+-- ============================================================================
+-- Migration 025: Rate Plan Eligibility Rules and Derived Pricing
+-- ============================================================================
+-- Description: Adds booking rules to rate_plans so advance-purchase and
+--   non-refundable plans are only sold when a stay qualifies:
+--   - min/max days between booking and arrival
+--   - stay window (arrival and departure must fall inside)
+--   - booking window (booking date must fall inside)
+--   - allowed arrival days of week
+--   - minimum guests and members-only
+--   Derived plans price from a base plan (e.g. -15% of Standard Rate); their
+--   rate_pricing rows are kept in sync by trigger so confirm_booking and the
+--   nightly log keep reading rate_pricing directly.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 025_add_rate_plan_eligibility.sql
+-- ============================================================================
+
+ALTER TABLE rate_plans
+    ADD COLUMN IF NOT EXISTS min_advance_days INT CHECK (min_advance_days IS NULL OR min_advance_days >= 0),
+    ADD COLUMN IF NOT EXISTS max_advance_days INT CHECK (max_advance_days IS NULL OR max_advance_days >= 0),
+    ADD COLUMN IF NOT EXISTS stay_start_date DATE,
+    ADD COLUMN IF NOT EXISTS stay_end_date DATE,
+    ADD COLUMN IF NOT EXISTS booking_start_date DATE,
+    ADD COLUMN IF NOT EXISTS booking_end_date DATE,
+    ADD COLUMN IF NOT EXISTS arrival_days_of_week INT[],
+    ADD COLUMN IF NOT EXISTS min_guests INT CHECK (min_guests IS NULL OR min_guests >= 1),
+    ADD COLUMN IF NOT EXISTS members_only BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS base_rate_plan_id INT REFERENCES rate_plans(rate_plan_id) ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS derived_adjustment_type VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS derived_adjustment_value DECIMAL(10, 2);
+
+ALTER TABLE rate_plans DROP CONSTRAINT IF EXISTS chk_rate_plans_advance_days;
+ALTER TABLE rate_plans ADD CONSTRAINT chk_rate_plans_advance_days
+    CHECK (min_advance_days IS NULL OR max_advance_days IS NULL OR max_advance_days >= min_advance_days);
+
+ALTER TABLE rate_plans DROP CONSTRAINT IF EXISTS chk_rate_plans_stay_window;
+ALTER TABLE rate_plans ADD CONSTRAINT chk_rate_plans_stay_window
+    CHECK (stay_start_date IS NULL OR stay_end_date IS NULL OR stay_end_date >= stay_start_date);
+
+ALTER TABLE rate_plans DROP CONSTRAINT IF EXISTS chk_rate_plans_booking_window;
+ALTER TABLE rate_plans ADD CONSTRAINT chk_rate_plans_booking_window
+    CHECK (booking_start_date IS NULL OR booking_end_date IS NULL OR booking_end_date >= booking_start_date);
+
+ALTER TABLE rate_plans DROP CONSTRAINT IF EXISTS chk_rate_plans_derived;
+ALTER TABLE rate_plans ADD CONSTRAINT chk_rate_plans_derived
+    CHECK (
+        (base_rate_plan_id IS NULL AND derived_adjustment_type IS NULL AND derived_adjustment_value IS NULL)
+        OR (base_rate_plan_id IS NOT NULL AND base_rate_plan_id <> rate_plan_id
+            AND derived_adjustment_type IN ('percentage', 'fixed')
+            AND derived_adjustment_value IS NOT NULL)
+    );
+
+COMMENT ON COLUMN rate_plans.min_advance_days IS 'จองล่วงหน้าอย่างน้อยกี่วันก่อนเช็คอิน';
+COMMENT ON COLUMN rate_plans.max_advance_days IS 'จองล่วงหน้าได้ไม่เกินกี่วันก่อนเช็คอิน';
+COMMENT ON COLUMN rate_plans.stay_start_date IS 'ช่วงวันเข้าพักที่ใช้ได้ (เริ่ม)';
+COMMENT ON COLUMN rate_plans.stay_end_date IS 'ช่วงวันเข้าพักที่ใช้ได้ (สิ้นสุด, วันเช็คเอาท์สุดท้าย)';
+COMMENT ON COLUMN rate_plans.booking_start_date IS 'ช่วงวันที่ทำการจองที่ใช้ได้ (เริ่ม)';
+COMMENT ON COLUMN rate_plans.booking_end_date IS 'ช่วงวันที่ทำการจองที่ใช้ได้ (สิ้นสุด)';
+COMMENT ON COLUMN rate_plans.arrival_days_of_week IS 'วันเช็คอินที่อนุญาต 0=อาทิตย์ ... 6=เสาร์ (NULL = ทุกวัน)';
+COMMENT ON COLUMN rate_plans.min_guests IS 'จำนวนผู้เข้าพักขั้นต่ำ';
+COMMENT ON COLUMN rate_plans.members_only IS 'เฉพาะสมาชิกที่เข้าสู่ระบบ';
+COMMENT ON COLUMN rate_plans.base_rate_plan_id IS 'แผนราคาฐานสำหรับราคาแบบอ้างอิง (derived)';
+COMMENT ON COLUMN rate_plans.derived_adjustment_type IS 'percentage หรือ fixed';
+COMMENT ON COLUMN rate_plans.derived_adjustment_value IS 'ค่าปรับจากแผนฐาน เช่น -15 = ลด 15%';
+
+-- ============================================================================
+-- Derived pricing: materialize derived plan prices into rate_pricing
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION derived_rate_price(
+    p_base_price DECIMAL,
+    p_adjustment_type VARCHAR,
+    p_adjustment_value DECIMAL
+) RETURNS DECIMAL AS $$
+BEGIN
+    IF p_adjustment_type = 'percentage' THEN
+        RETURN GREATEST(0, ROUND(p_base_price * (1 + p_adjustment_value / 100.0), 2));
+    END IF;
+    RETURN GREATEST(0, p_base_price + p_adjustment_value);
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Recompute all prices of one derived plan (or every derived plan when NULL)
+CREATE OR REPLACE FUNCTION refresh_derived_rate_pricing(p_rate_plan_id INT DEFAULT NULL)
+RETURNS INT AS $$
+DECLARE
+    v_count INT;
+BEGIN
+    INSERT INTO rate_pricing (rate_plan_id, room_type_id, rate_tier_id, price)
+    SELECT d.rate_plan_id, b.room_type_id, b.rate_tier_id,
+           derived_rate_price(b.price, d.derived_adjustment_type, d.derived_adjustment_value)
+    FROM rate_plans d
+    JOIN rate_pricing b ON b.rate_plan_id = d.base_rate_plan_id
+    WHERE d.base_rate_plan_id IS NOT NULL
+      AND (p_rate_plan_id IS NULL OR d.rate_plan_id = p_rate_plan_id)
+    ON CONFLICT (rate_plan_id, room_type_id, rate_tier_id)
+    DO UPDATE SET
+        price = EXCLUDED.price,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE rate_pricing.price IS DISTINCT FROM EXCLUDED.price;
+
+    GET DIAGNOSTICS v_count = ROW_COUNT;
+    RETURN v_count;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Base price changed: push it to every plan derived from it
+CREATE OR REPLACE FUNCTION sync_derived_rate_pricing()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO rate_pricing (rate_plan_id, room_type_id, rate_tier_id, price)
+    SELECT d.rate_plan_id, NEW.room_type_id, NEW.rate_tier_id,
+           derived_rate_price(NEW.price, d.derived_adjustment_type, d.derived_adjustment_value)
+    FROM rate_plans d
+    WHERE d.base_rate_plan_id = NEW.rate_plan_id
+    ON CONFLICT (rate_plan_id, room_type_id, rate_tier_id)
+    DO UPDATE SET
+        price = EXCLUDED.price,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE rate_pricing.price IS DISTINCT FROM EXCLUDED.price;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_sync_derived_rate_pricing ON rate_pricing;
+CREATE TRIGGER trg_sync_derived_rate_pricing
+    AFTER INSERT OR UPDATE OF price ON rate_pricing
+    FOR EACH ROW
+    EXECUTE FUNCTION sync_derived_rate_pricing();
+
+-- Derived plan configured or changed: only one level of derivation is allowed
+CREATE OR REPLACE FUNCTION refresh_derived_rate_plan()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.base_rate_plan_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM rate_plans
+        WHERE rate_plan_id = NEW.base_rate_plan_id AND base_rate_plan_id IS NOT NULL
+    ) THEN
+        RAISE EXCEPTION 'Rate plan % is itself derived and cannot be used as a base plan', NEW.base_rate_plan_id;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM rate_plans WHERE base_rate_plan_id = NEW.rate_plan_id) THEN
+        RAISE EXCEPTION 'Rate plan % is the base of other plans and cannot be derived', NEW.rate_plan_id;
+    END IF;
+
+    PERFORM refresh_derived_rate_pricing(NEW.rate_plan_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_refresh_derived_rate_plan ON rate_plans;
+CREATE TRIGGER trg_refresh_derived_rate_plan
+    AFTER INSERT OR UPDATE OF base_rate_plan_id, derived_adjustment_type, derived_adjustment_value ON rate_plans
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_derived_rate_plan();
+
+COMMENT ON FUNCTION refresh_derived_rate_pricing(INT) IS 'คำนวณราคาของแผนราคาแบบอ้างอิงจากแผนฐานใหม่';
+
+-- ============================================================================
+-- SEED: Non-Refundable is -15% of Standard; add a 14-day Advance Purchase plan
+-- ============================================================================
+
+UPDATE rate_plans nr
+SET base_rate_plan_id = std.rate_plan_id,
+    derived_adjustment_type = 'percentage',
+    derived_adjustment_value = -15
+FROM rate_plans std
+WHERE nr.name = 'Non-Refundable Rate'
+  AND std.name = 'Standard Rate'
+  AND nr.base_rate_plan_id IS NULL;
+
+INSERT INTO rate_plans (name, description, policy_id, min_advance_days,
+                        base_rate_plan_id, derived_adjustment_type, derived_adjustment_value)
+SELECT
+    'Advance Purchase 14',
+    'จองล่วงหน้าอย่างน้อย 14 วัน - ลด 20% จากราคามาตรฐาน ไม่สามารถยกเลิกได้',
+    cp.policy_id,
+    14,
+    std.rate_plan_id,
+    'percentage',
+    -20
+FROM cancellation_policies cp
+CROSS JOIN rate_plans std
+WHERE cp.name = 'Non-Refundable'
+  AND std.name = 'Standard Rate'
+ON CONFLICT (name) DO NOTHING;
+
+\echo 'Migration 025 completed: rate plan eligibility rules and derived pricing added'
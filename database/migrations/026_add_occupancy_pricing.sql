@@ -0,0 +1,227 @@
+-- ============================================================================
+-- Migration 026: Occupancy-Based Pricing
+-- ============================================================================
+-- Description: Prices the actual party instead of one price per room:
+--   - room_types.base_occupancy        : guests included in the room rate
+--   - room_types.max_extra_beds        : extra beds that fit in one room
+--   - room_types.extra_bed_allotment   : extra beds available per night for the room type
+--   - occupancy_surcharges             : per-tier extra adult, extra child (by age band)
+--                                        and extra bed charges
+--   - booking_details.num_adults / child_ages / extra_beds : the party that was priced
+--   calculate_occupancy_surcharge() is used by search/booking pricing and by a
+--   trigger on booking_nightly_log so confirm_booking() logs the same price.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 026_add_occupancy_pricing.sql
+-- ============================================================================
+
+ALTER TABLE room_types
+    ADD COLUMN IF NOT EXISTS base_occupancy INT NOT NULL DEFAULT 2 CHECK (base_occupancy > 0),
+    ADD COLUMN IF NOT EXISTS max_extra_beds INT NOT NULL DEFAULT 0 CHECK (max_extra_beds >= 0),
+    ADD COLUMN IF NOT EXISTS extra_bed_allotment INT NOT NULL DEFAULT 0 CHECK (extra_bed_allotment >= 0);
+
+COMMENT ON COLUMN room_types.base_occupancy IS 'จำนวนผู้เข้าพักที่รวมอยู่ในราคาห้อง';
+COMMENT ON COLUMN room_types.max_extra_beds IS 'จำนวนเตียงเสริมสูงสุดต่อห้อง';
+COMMENT ON COLUMN room_types.extra_bed_allotment IS 'จำนวนเตียงเสริมที่มีให้ต่อคืนสำหรับประเภทห้องนี้';
+
+ALTER TABLE booking_details
+    ADD COLUMN IF NOT EXISTS num_adults INT CHECK (num_adults IS NULL OR num_adults >= 1),
+    ADD COLUMN IF NOT EXISTS child_ages INT[],
+    ADD COLUMN IF NOT EXISTS extra_beds INT NOT NULL DEFAULT 0 CHECK (extra_beds >= 0);
+
+COMMENT ON COLUMN booking_details.num_adults IS 'จำนวนผู้ใหญ่ (NULL = ข้อมูลเก่า ถือว่าทุกคนเป็นผู้ใหญ่)';
+COMMENT ON COLUMN booking_details.child_ages IS 'อายุของเด็กแต่ละคน';
+COMMENT ON COLUMN booking_details.extra_beds IS 'จำนวนเตียงเสริมที่จอง';
+
+-- ============================================================================
+-- occupancy_surcharges
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS occupancy_surcharges (
+    surcharge_id SERIAL PRIMARY KEY,
+    room_type_id INT REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    rate_tier_id INT NOT NULL REFERENCES rate_tiers(rate_tier_id) ON DELETE CASCADE,
+    charge_type VARCHAR(20) NOT NULL CHECK (charge_type IN ('ExtraAdult', 'ExtraChild', 'ExtraBed')),
+    min_age INT NOT NULL DEFAULT 0 CHECK (min_age >= 0),
+    max_age INT NOT NULL DEFAULT 17 CHECK (max_age >= 0),
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_occupancy_surcharges_age_band CHECK (max_age >= min_age)
+);
+
+-- Age bands only matter for ExtraChild; other charge types keep the defaults
+CREATE UNIQUE INDEX IF NOT EXISTS idx_occupancy_surcharges_unique
+    ON occupancy_surcharges(rate_tier_id, (COALESCE(room_type_id, 0)), charge_type, min_age);
+
+DROP TRIGGER IF EXISTS update_occupancy_surcharges_updated_at ON occupancy_surcharges;
+CREATE TRIGGER update_occupancy_surcharges_updated_at
+    BEFORE UPDATE ON occupancy_surcharges
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE occupancy_surcharges IS 'ค่าบริการเพิ่มตามจำนวนผู้เข้าพัก ต่อ rate tier (ผู้ใหญ่เพิ่ม, เด็กตามช่วงอายุ, เตียงเสริม)';
+COMMENT ON COLUMN occupancy_surcharges.room_type_id IS 'NULL = ใช้กับทุกประเภทห้อง (แถวที่ระบุประเภทห้องมีลำดับความสำคัญกว่า)';
+
+-- ============================================================================
+-- calculate_occupancy_surcharge: nightly surcharge for a party
+-- Adults fill the base occupancy first, then children from the oldest.
+-- Guests left over pay the extra adult / child age band charge.
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION calculate_occupancy_surcharge(
+    p_room_type_id INT,
+    p_date DATE,
+    p_num_adults INT,
+    p_child_ages INT[],
+    p_extra_beds INT
+) RETURNS DECIMAL AS $$
+DECLARE
+    v_base_occupancy INT;
+    v_rate_tier_id INT;
+    v_slots_left INT;
+    v_age INT;
+    v_amount DECIMAL(10, 2);
+    v_total DECIMAL(10, 2) := 0;
+BEGIN
+    SELECT base_occupancy INTO v_base_occupancy
+    FROM room_types
+    WHERE room_type_id = p_room_type_id;
+
+    IF v_base_occupancy IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    -- Same tier lookup as confirm_booking()
+    SELECT rate_tier_id INTO v_rate_tier_id
+    FROM pricing_calendar
+    WHERE date = p_date;
+
+    IF v_rate_tier_id IS NULL THEN
+        SELECT rate_tier_id INTO v_rate_tier_id
+        FROM rate_tiers
+        ORDER BY rate_tier_id
+        LIMIT 1;
+    END IF;
+
+    -- Extra adults
+    IF COALESCE(p_num_adults, 0) > v_base_occupancy THEN
+        SELECT amount INTO v_amount
+        FROM occupancy_surcharges
+        WHERE charge_type = 'ExtraAdult'
+          AND rate_tier_id = v_rate_tier_id
+          AND (room_type_id = p_room_type_id OR room_type_id IS NULL)
+        ORDER BY room_type_id NULLS LAST
+        LIMIT 1;
+
+        v_total := v_total + COALESCE(v_amount, 0) * (p_num_adults - v_base_occupancy);
+    END IF;
+
+    -- Children: oldest take the remaining included places first
+    v_slots_left := GREATEST(0, v_base_occupancy - COALESCE(p_num_adults, 0));
+    FOR v_age IN
+        SELECT age FROM unnest(COALESCE(p_child_ages, '{}'::INT[])) AS age ORDER BY age DESC
+    LOOP
+        IF v_slots_left > 0 THEN
+            v_slots_left := v_slots_left - 1;
+            CONTINUE;
+        END IF;
+
+        v_amount := NULL;
+        SELECT amount INTO v_amount
+        FROM occupancy_surcharges
+        WHERE charge_type = 'ExtraChild'
+          AND rate_tier_id = v_rate_tier_id
+          AND (room_type_id = p_room_type_id OR room_type_id IS NULL)
+          AND v_age BETWEEN min_age AND max_age
+        ORDER BY room_type_id NULLS LAST
+        LIMIT 1;
+
+        v_total := v_total + COALESCE(v_amount, 0);
+    END LOOP;
+
+    -- Extra beds
+    IF COALESCE(p_extra_beds, 0) > 0 THEN
+        v_amount := NULL;
+        SELECT amount INTO v_amount
+        FROM occupancy_surcharges
+        WHERE charge_type = 'ExtraBed'
+          AND rate_tier_id = v_rate_tier_id
+          AND (room_type_id = p_room_type_id OR room_type_id IS NULL)
+        ORDER BY room_type_id NULLS LAST
+        LIMIT 1;
+
+        v_total := v_total + COALESCE(v_amount, 0) * p_extra_beds;
+    END IF;
+
+    RETURN v_total;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+COMMENT ON FUNCTION calculate_occupancy_surcharge(INT, DATE, INT, INT[], INT) IS 'คำนวณค่าบริการเพิ่มต่อคืนตามจำนวนผู้ใหญ่ อายุเด็ก และเตียงเสริม';
+
+-- ============================================================================
+-- extra_beds_booked: extra beds held by active bookings on a date
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION extra_beds_booked(p_room_type_id INT, p_date DATE)
+RETURNS INT AS $$
+    SELECT COALESCE(SUM(bd.extra_beds), 0)::INT
+    FROM booking_details bd
+    JOIN bookings b ON bd.booking_id = b.booking_id
+    WHERE bd.room_type_id = p_room_type_id
+      AND bd.extra_beds > 0
+      AND p_date >= bd.check_in_date
+      AND p_date < bd.check_out_date
+      AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn');
+$$ LANGUAGE sql STABLE;
+
+-- ============================================================================
+-- Nightly log: add the party's surcharge to the room rate written by confirm_booking()
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION apply_occupancy_surcharge_to_nightly_log()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_detail RECORD;
+BEGIN
+    SELECT room_type_id, COALESCE(num_adults, num_guests) AS num_adults, child_ages, extra_beds
+    INTO v_detail
+    FROM booking_details
+    WHERE booking_detail_id = NEW.booking_detail_id;
+
+    IF FOUND THEN
+        NEW.quoted_price := NEW.quoted_price + calculate_occupancy_surcharge(
+            v_detail.room_type_id, NEW.date, v_detail.num_adults, v_detail.child_ages, v_detail.extra_beds);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_nightly_log_occupancy_surcharge ON booking_nightly_log;
+CREATE TRIGGER trg_nightly_log_occupancy_surcharge
+    BEFORE INSERT ON booking_nightly_log
+    FOR EACH ROW
+    EXECUTE FUNCTION apply_occupancy_surcharge_to_nightly_log();
+
+-- ============================================================================
+-- SEED: default surcharges for every tier (all room types)
+-- ============================================================================
+
+INSERT INTO occupancy_surcharges (room_type_id, rate_tier_id, charge_type, min_age, max_age, amount)
+SELECT NULL, rt.rate_tier_id, s.charge_type, s.min_age, s.max_age, s.amount
+FROM rate_tiers rt
+CROSS JOIN (VALUES
+    ('ExtraAdult', 0, 17, 600.00),
+    ('ExtraChild', 0, 5, 0.00),
+    ('ExtraChild', 6, 11, 300.00),
+    ('ExtraChild', 12, 17, 450.00),
+    ('ExtraBed', 0, 17, 500.00)
+) AS s(charge_type, min_age, max_age, amount)
+ON CONFLICT DO NOTHING;
+
+UPDATE room_types SET max_extra_beds = 1, extra_bed_allotment = 5
+WHERE name IN ('Deluxe Room', 'Suite Room') AND max_extra_beds = 0;
+
+\echo 'Migration 026 completed: occupancy-based pricing added'
@@ -0,0 +1,100 @@
+-- ============================================================================
+-- Migration 027: Dynamic Pricing Rules
+-- ============================================================================
+-- Description: Rule-based yield management on top of pricing_calendar:
+--   - pricing_rules        : move a date to a higher rate tier when forecast
+--                            occupancy crosses a threshold (Occupancy) or when
+--                            rooms picked up in the last N days exceed a
+--                            threshold (Pickup), within floor/ceiling prices
+--   - pricing_rule_changes : every tier change made by the engine
+--   The engine runs as a scheduled job; rules only ever raise a date's tier.
+--   Tiers are ranked by rate_tiers.display_order (Low Season < ... < Peak Season).
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 027_create_dynamic_pricing.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    rule_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    rule_type VARCHAR(20) NOT NULL CHECK (rule_type IN ('Occupancy', 'Pickup')),
+    room_type_id INT REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    occupancy_threshold DECIMAL(5, 2) CHECK (occupancy_threshold IS NULL OR (occupancy_threshold > 0 AND occupancy_threshold <= 100)),
+    pickup_days INT CHECK (pickup_days IS NULL OR pickup_days > 0),
+    pickup_threshold INT CHECK (pickup_threshold IS NULL OR pickup_threshold > 0),
+    target_rate_tier_id INT NOT NULL REFERENCES rate_tiers(rate_tier_id) ON DELETE CASCADE,
+    floor_price DECIMAL(10, 2) CHECK (floor_price IS NULL OR floor_price >= 0),
+    ceiling_price DECIMAL(10, 2) CHECK (ceiling_price IS NULL OR ceiling_price >= 0),
+    lookahead_days INT NOT NULL DEFAULT 90 CHECK (lookahead_days > 0 AND lookahead_days <= 365),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_pricing_rules_params CHECK (
+        (rule_type = 'Occupancy' AND occupancy_threshold IS NOT NULL)
+        OR (rule_type = 'Pickup' AND pickup_days IS NOT NULL AND pickup_threshold IS NOT NULL)
+    ),
+    CONSTRAINT chk_pricing_rules_price_band CHECK (
+        floor_price IS NULL OR ceiling_price IS NULL OR ceiling_price >= floor_price
+    )
+);
+
+DROP TRIGGER IF EXISTS update_pricing_rules_updated_at ON pricing_rules;
+CREATE TRIGGER update_pricing_rules_updated_at
+    BEFORE UPDATE ON pricing_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE pricing_rules IS 'กฎการปรับระดับราคาอัตโนมัติตามอัตราการเข้าพักและยอดจองล่าสุด';
+COMMENT ON COLUMN pricing_rules.room_type_id IS 'วัดเฉพาะประเภทห้องนี้ (NULL = ทั้งโรงแรม)';
+COMMENT ON COLUMN pricing_rules.occupancy_threshold IS 'Occupancy: เปอร์เซ็นต์การเข้าพักที่คาดการณ์ที่ทำให้กฎทำงาน';
+COMMENT ON COLUMN pricing_rules.pickup_days IS 'Pickup: นับยอดจองย้อนหลังกี่วัน';
+COMMENT ON COLUMN pricing_rules.pickup_threshold IS 'Pickup: จำนวนห้องที่ถูกจองในช่วงเวลาที่ทำให้กฎทำงาน';
+COMMENT ON COLUMN pricing_rules.target_rate_tier_id IS 'ระดับราคาที่จะปรับวันนั้นขึ้นไป';
+COMMENT ON COLUMN pricing_rules.floor_price IS 'ราคาต่ำสุดที่ยอมรับ (ราคาแผนมาตรฐานของประเภทห้อง หรือราคาห้องถูกสุดเมื่อใช้ทั้งโรงแรม)';
+COMMENT ON COLUMN pricing_rules.ceiling_price IS 'ราคาสูงสุดที่ยอมรับ (เทียบแบบเดียวกับ floor_price)';
+COMMENT ON COLUMN pricing_rules.lookahead_days IS 'ประเมินวันล่วงหน้ากี่วัน';
+
+-- ============================================================================
+-- pricing_rule_changes: change log of the engine
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS pricing_rule_changes (
+    change_id SERIAL PRIMARY KEY,
+    date DATE NOT NULL,
+    rule_id INT REFERENCES pricing_rules(rule_id) ON DELETE SET NULL,
+    rule_name VARCHAR(100) NOT NULL,
+    old_rate_tier_id INT REFERENCES rate_tiers(rate_tier_id) ON DELETE SET NULL,
+    new_rate_tier_id INT NOT NULL REFERENCES rate_tiers(rate_tier_id) ON DELETE CASCADE,
+    occupancy_percent DECIMAL(5, 2) NOT NULL,
+    pickup INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pricing_rule_changes_date ON pricing_rule_changes(date);
+CREATE INDEX IF NOT EXISTS idx_pricing_rule_changes_created_at ON pricing_rule_changes(created_at);
+
+COMMENT ON TABLE pricing_rule_changes IS 'ประวัติการปรับระดับราคาโดยกฎอัตโนมัติ';
+COMMENT ON COLUMN pricing_rule_changes.occupancy_percent IS 'อัตราการเข้าพักที่คาดการณ์ ณ เวลาที่ปรับ';
+COMMENT ON COLUMN pricing_rule_changes.pickup IS 'จำนวนห้องที่ถูกจองในช่วง pickup ณ เวลาที่ปรับ';
+
+-- ============================================================================
+-- SEED: occupancy ladder 70/85/95% and a 7-day pickup rule
+-- ============================================================================
+
+INSERT INTO pricing_rules (name, rule_type, occupancy_threshold, target_rate_tier_id)
+SELECT r.name, 'Occupancy', r.threshold, t.rate_tier_id
+FROM (VALUES
+    ('Occupancy 70%', 70.00, 'Standard'),
+    ('Occupancy 85%', 85.00, 'High Season'),
+    ('Occupancy 95%', 95.00, 'Peak Season')
+) AS r(name, threshold, tier_name)
+JOIN rate_tiers t ON t.name = r.tier_name
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO pricing_rules (name, rule_type, pickup_days, pickup_threshold, target_rate_tier_id, lookahead_days)
+SELECT 'Pickup 7 days', 'Pickup', 7, 10, t.rate_tier_id, 60
+FROM rate_tiers t
+WHERE t.name = 'High Season'
+ON CONFLICT (name) DO NOTHING;
+
+\echo 'Migration 027 completed: dynamic pricing rules created'
@@ -0,0 +1,132 @@
+-- ============================================================================
+-- Migration 028: Packages and Add-ons
+-- ============================================================================
+-- Description: Extras sold with a booking (breakfast, airport transfer, spa):
+--   - addons              : catalog with a pricing unit and an optional daily limit
+--   - rate_plan_addons    : add-ons included in a rate plan (packages)
+--   - booking_addons      : add-ons booked on a booking detail; their total is
+--                           part of bookings.total_amount and reported as a
+--                           separate revenue category
+--   Daily limits count the quantity of active bookings on each service date
+--   (every night of the stay for per-night add-ons).
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 028_create_addons.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS addons (
+    addon_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    category VARCHAR(50) NOT NULL DEFAULT 'Other',
+    pricing_unit VARCHAR(20) NOT NULL CHECK (pricing_unit IN ('PerStay', 'PerNight', 'PerGuest', 'PerGuestNight', 'PerUnit')),
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    daily_limit INT CHECK (daily_limit IS NULL OR daily_limit >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_addons_updated_at ON addons;
+CREATE TRIGGER update_addons_updated_at
+    BEFORE UPDATE ON addons
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE addons IS 'บริการเสริมที่ขายพร้อมการจอง เช่น อาหารเช้า รถรับส่งสนามบิน สปา';
+COMMENT ON COLUMN addons.category IS 'หมวดหมู่ เช่น Food, Transfer, Spa (ใช้แยกรายได้ในรายงาน)';
+COMMENT ON COLUMN addons.pricing_unit IS 'PerStay=ต่อการเข้าพัก, PerNight=ต่อคืน, PerGuest=ต่อคน, PerGuestNight=ต่อคนต่อคืน, PerUnit=ต่อหน่วย';
+COMMENT ON COLUMN addons.daily_limit IS 'จำนวนสูงสุดที่ขายได้ต่อวัน (NULL = ไม่จำกัด)';
+
+-- ============================================================================
+-- rate_plan_addons: package inclusions
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS rate_plan_addons (
+    rate_plan_id INT NOT NULL REFERENCES rate_plans(rate_plan_id) ON DELETE CASCADE,
+    addon_id INT NOT NULL REFERENCES addons(addon_id) ON DELETE CASCADE,
+    PRIMARY KEY (rate_plan_id, addon_id)
+);
+
+COMMENT ON TABLE rate_plan_addons IS 'บริการเสริมที่รวมอยู่ในแผนราคา (แพ็กเกจ) ไม่คิดเงินเพิ่ม';
+
+-- ============================================================================
+-- booking_addons
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS booking_addons (
+    booking_addon_id SERIAL PRIMARY KEY,
+    booking_detail_id INT NOT NULL REFERENCES booking_details(booking_detail_id) ON DELETE CASCADE,
+    addon_id INT NOT NULL REFERENCES addons(addon_id) ON DELETE RESTRICT,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_price DECIMAL(10, 2) NOT NULL CHECK (unit_price >= 0),
+    total_price DECIMAL(10, 2) NOT NULL CHECK (total_price >= 0),
+    service_start_date DATE NOT NULL,
+    service_end_date DATE NOT NULL,
+    is_included BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_booking_addons_service_dates CHECK (service_end_date >= service_start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_addons_detail ON booking_addons(booking_detail_id);
+CREATE INDEX IF NOT EXISTS idx_booking_addons_service ON booking_addons(addon_id, service_start_date, service_end_date);
+
+COMMENT ON TABLE booking_addons IS 'บริการเสริมที่จองในแต่ละรายการจอง';
+COMMENT ON COLUMN booking_addons.service_start_date IS 'วันที่ใช้บริการวันแรก';
+COMMENT ON COLUMN booking_addons.service_end_date IS 'วันที่ใช้บริการวันสุดท้าย (ต่อคืน = คืนสุดท้ายของการเข้าพัก)';
+COMMENT ON COLUMN booking_addons.is_included IS 'รวมอยู่ในแผนราคา (ราคา 0)';
+
+-- ============================================================================
+-- addon_quantity_booked: units held by active bookings on a date
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION addon_quantity_booked(p_addon_id INT, p_date DATE)
+RETURNS INT AS $$
+    SELECT COALESCE(SUM(ba.quantity), 0)::INT
+    FROM booking_addons ba
+    JOIN booking_details bd ON ba.booking_detail_id = bd.booking_detail_id
+    JOIN bookings b ON bd.booking_id = b.booking_id
+    WHERE ba.addon_id = p_addon_id
+      AND p_date BETWEEN ba.service_start_date AND ba.service_end_date
+      AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn');
+$$ LANGUAGE sql STABLE;
+
+-- ============================================================================
+-- SEED: common add-ons; Breakfast package includes breakfast
+-- ============================================================================
+
+INSERT INTO addons (name, description, category, pricing_unit, price, daily_limit)
+VALUES
+    ('Breakfast', 'อาหารเช้าแบบบุฟเฟต์', 'Food', 'PerGuestNight', 350.00, NULL),
+    ('Airport Transfer', 'รถรับส่งสนามบิน (เที่ยวเดียว)', 'Transfer', 'PerUnit', 1200.00, 4),
+    ('Spa Massage 60 min', 'นวดสปา 60 นาที', 'Spa', 'PerUnit', 1500.00, 8),
+    ('Late Dinner Set', 'ชุดอาหารค่ำสำหรับสองท่าน', 'Food', 'PerStay', 1800.00, NULL)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO rate_plans (name, description, policy_id)
+SELECT
+    'Bed and Breakfast',
+    'แผนราคามาตรฐานรวมอาหารเช้า - ยกเลิกฟรีได้จนถึง 24 ชั่วโมงก่อนเช็คอิน',
+    cp.policy_id
+FROM cancellation_policies cp
+WHERE cp.name = 'Flexible'
+ON CONFLICT (name) DO NOTHING;
+
+-- Priced as Standard + 15%
+UPDATE rate_plans bb
+SET base_rate_plan_id = std.rate_plan_id,
+    derived_adjustment_type = 'percentage',
+    derived_adjustment_value = 15
+FROM rate_plans std
+WHERE bb.name = 'Bed and Breakfast'
+  AND std.name = 'Standard Rate'
+  AND bb.base_rate_plan_id IS NULL;
+
+INSERT INTO rate_plan_addons (rate_plan_id, addon_id)
+SELECT rp.rate_plan_id, a.addon_id
+FROM rate_plans rp
+JOIN addons a ON a.name = 'Breakfast'
+WHERE rp.name = 'Bed and Breakfast'
+ON CONFLICT DO NOTHING;
+
+\echo 'Migration 028 completed: add-ons and packages created'
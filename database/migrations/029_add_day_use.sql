@@ -0,0 +1,495 @@
+-- ============================================================================
+-- Migration 029: Day-use and Hourly Bookings
+-- ============================================================================
+-- Description: Sell 3-6 hour day-use slots on rooms that are vacant during the day:
+--   - rate_plans.is_day_use      : day-use rate type, kept out of overnight search
+--   - day_use_rates              : price per room type and slot length (hours)
+--   - booking_details.stay_type  : 'Overnight' or 'DayUse'; day-use details check
+--                                  in and out on the same date
+--   - day_use_blocks             : the physical room held for a day-use slot
+--   Day-use stays do not touch room_inventory (confirm_booking and cancel_booking
+--   only walk the nights of a stay, and a same-day stay has none). Instead each
+--   slot blocks one physical room, after checking that enough rooms of the type
+--   remain for overnight guests: stayovers all day, departures until 12:00 and
+--   arrivals (including tentative holds) from 14:00. Rooms need one hour of
+--   cleaning after a day-use slot.
+--   check_in is extended so a room cannot be given to another guest while a
+--   day-use slot holds it; check_out already handles same-day stays.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 029_add_day_use.sql
+-- ============================================================================
+
+ALTER TABLE rate_plans
+ADD COLUMN IF NOT EXISTS is_day_use BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN rate_plans.is_day_use IS 'แผนราคาสำหรับการใช้ห้องรายชั่วโมง (Day-use) ราคาอยู่ใน day_use_rates';
+
+-- ============================================================================
+-- day_use_rates
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS day_use_rates (
+    day_use_rate_id SERIAL PRIMARY KEY,
+    rate_plan_id INT NOT NULL REFERENCES rate_plans(rate_plan_id) ON DELETE CASCADE,
+    room_type_id INT NOT NULL REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    duration_hours INT NOT NULL CHECK (duration_hours BETWEEN 1 AND 12),
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_day_use_rates UNIQUE (rate_plan_id, room_type_id, duration_hours)
+);
+
+DROP TRIGGER IF EXISTS update_day_use_rates_updated_at ON day_use_rates;
+CREATE TRIGGER update_day_use_rates_updated_at
+    BEFORE UPDATE ON day_use_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE day_use_rates IS 'ราคาห้องรายชั่วโมงตามประเภทห้องและความยาวของช่วงเวลา';
+COMMENT ON COLUMN day_use_rates.duration_hours IS 'ความยาวของช่วงเวลา (ชั่วโมง)';
+
+-- ============================================================================
+-- booking_details.stay_type
+-- ============================================================================
+
+ALTER TABLE booking_details
+ADD COLUMN IF NOT EXISTS stay_type VARCHAR(20) NOT NULL DEFAULT 'Overnight';
+
+ALTER TABLE booking_details
+DROP CONSTRAINT IF EXISTS chk_booking_details_stay_type;
+
+ALTER TABLE booking_details
+ADD CONSTRAINT chk_booking_details_stay_type CHECK (stay_type IN ('Overnight', 'DayUse'));
+
+ALTER TABLE booking_details
+DROP CONSTRAINT IF EXISTS chk_date_order;
+
+ALTER TABLE booking_details
+ADD CONSTRAINT chk_date_order CHECK (
+    (stay_type = 'Overnight' AND check_out_date > check_in_date)
+    OR (stay_type = 'DayUse' AND check_out_date = check_in_date)
+);
+
+COMMENT ON COLUMN booking_details.stay_type IS 'Overnight=ค้างคืน, DayUse=ใช้ห้องรายชั่วโมง (เช็คอินและเช็คเอาท์วันเดียวกัน)';
+
+-- ============================================================================
+-- day_use_blocks
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS day_use_blocks (
+    block_id SERIAL PRIMARY KEY,
+    booking_detail_id INT NOT NULL UNIQUE REFERENCES booking_details(booking_detail_id) ON DELETE CASCADE,
+    room_id INT NOT NULL REFERENCES rooms(room_id) ON DELETE RESTRICT,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_day_use_blocks_times CHECK (end_at > start_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_day_use_blocks_room ON day_use_blocks(room_id, start_at);
+
+COMMENT ON TABLE day_use_blocks IS 'ห้องพักจริงที่ถูกกันไว้สำหรับการใช้ห้องรายชั่วโมง';
+COMMENT ON COLUMN day_use_blocks.room_id IS 'ห้องที่กันไว้ (ย้ายตามห้องที่เช็คอินจริง)';
+
+-- ============================================================================
+-- day_use_block_conflict: another active slot holds the room in [p_from, p_to)
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION day_use_block_conflict(
+    p_room_id INT,
+    p_from TIMESTAMP,
+    p_to TIMESTAMP,
+    p_exclude_detail_id INT DEFAULT NULL
+) RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM day_use_blocks dub
+        JOIN booking_details bd ON dub.booking_detail_id = bd.booking_detail_id
+        JOIN bookings b ON bd.booking_id = b.booking_id
+        WHERE dub.room_id = p_room_id
+          AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn')
+          AND dub.booking_detail_id IS DISTINCT FROM p_exclude_detail_id
+          -- one hour of cleaning after each slot
+          AND dub.start_at < p_to + INTERVAL '1 hour'
+          AND dub.end_at + INTERVAL '1 hour' > p_from
+    );
+$$ LANGUAGE sql STABLE;
+
+-- ============================================================================
+-- day_use_rooms_available: rooms of a type that can take a slot
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION day_use_rooms_available(
+    p_room_type_id INT,
+    p_start TIMESTAMP,
+    p_end TIMESTAMP
+) RETURNS INT AS $$
+DECLARE
+    v_date DATE := p_start::DATE;
+    v_rooms INT;
+    v_stayovers INT;
+    v_departures INT;
+    v_arrivals INT;
+    v_tentative INT;
+    v_demand INT;
+    v_blocked INT;
+    v_free_rooms INT;
+BEGIN
+    SELECT COUNT(*) INTO v_rooms
+    FROM rooms
+    WHERE room_type_id = p_room_type_id
+      AND housekeeping_status NOT IN ('MaintenanceRequired', 'OutOfService');
+
+    -- Overnight guests needing a room of this type on the date
+    SELECT
+        COUNT(*) FILTER (WHERE bd.check_in_date < v_date AND bd.check_out_date > v_date),
+        COUNT(*) FILTER (WHERE bd.check_out_date = v_date),
+        COUNT(*) FILTER (WHERE bd.check_in_date = v_date)
+    INTO v_stayovers, v_departures, v_arrivals
+    FROM booking_details bd
+    JOIN bookings b ON bd.booking_id = b.booking_id
+    WHERE bd.room_type_id = p_room_type_id
+      AND bd.stay_type = 'Overnight'
+      AND b.status IN ('PendingPayment', 'Confirmed', 'CheckedIn')
+      AND bd.check_in_date <= v_date
+      AND bd.check_out_date >= v_date;
+
+    SELECT COALESCE(tentative_count, 0) INTO v_tentative
+    FROM room_inventory
+    WHERE room_type_id = p_room_type_id AND date = v_date;
+
+    v_demand := v_stayovers;
+    IF p_start::TIME < TIME '12:00' THEN
+        v_demand := v_demand + v_departures;
+    END IF;
+    IF p_end::TIME + INTERVAL '1 hour' > TIME '14:00' THEN
+        v_demand := v_demand + v_arrivals + COALESCE(v_tentative, 0);
+    END IF;
+
+    SELECT COUNT(DISTINCT r.room_id) INTO v_blocked
+    FROM rooms r
+    WHERE r.room_type_id = p_room_type_id
+      AND day_use_block_conflict(r.room_id, p_start, p_end);
+
+    -- Rooms physically free for the slot right now (matters for same-day sales)
+    SELECT COUNT(*) INTO v_free_rooms
+    FROM rooms r
+    WHERE r.room_type_id = p_room_type_id
+      AND r.housekeeping_status NOT IN ('MaintenanceRequired', 'OutOfService')
+      AND NOT day_use_block_conflict(r.room_id, p_start, p_end)
+      AND NOT EXISTS (
+          SELECT 1
+          FROM room_assignments ra
+          JOIN booking_details bd ON ra.booking_detail_id = bd.booking_detail_id
+          WHERE ra.room_id = r.room_id
+            AND ra.status = 'Active'
+            AND bd.stay_type = 'Overnight'
+            AND (bd.check_out_date > v_date
+                 OR (bd.check_out_date = v_date AND p_start::TIME < TIME '12:00'))
+      );
+
+    RETURN GREATEST(LEAST(v_rooms - v_demand - v_blocked, v_free_rooms), 0);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+COMMENT ON FUNCTION day_use_rooms_available IS
+'จำนวนห้องที่ขายแบบรายชั่วโมงได้ในช่วงเวลา โดยกันห้องไว้สำหรับแขกค้างคืน
+(แขกพักต่อทั้งวัน, แขกเช็คเอาท์ถึง 12:00, แขกเช็คอินตั้งแต่ 14:00)';
+
+-- ============================================================================
+-- block_day_use_room: hold a physical room for a day-use booking detail
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION block_day_use_room(
+    p_booking_detail_id INT,
+    p_room_type_id INT,
+    p_start TIMESTAMP,
+    p_end TIMESTAMP
+) RETURNS INT AS $$
+DECLARE
+    v_room_id INT;
+BEGIN
+    -- Serialize slot sales per room type
+    PERFORM pg_advisory_xact_lock(hashtext('day_use'), p_room_type_id);
+
+    IF day_use_rooms_available(p_room_type_id, p_start, p_end) <= 0 THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT r.room_id INTO v_room_id
+    FROM rooms r
+    WHERE r.room_type_id = p_room_type_id
+      AND r.housekeeping_status NOT IN ('MaintenanceRequired', 'OutOfService')
+      AND NOT day_use_block_conflict(r.room_id, p_start, p_end)
+      AND NOT EXISTS (
+          SELECT 1
+          FROM room_assignments ra
+          JOIN booking_details bd ON ra.booking_detail_id = bd.booking_detail_id
+          WHERE ra.room_id = r.room_id
+            AND ra.status = 'Active'
+            AND bd.stay_type = 'Overnight'
+            AND bd.check_out_date >= p_start::DATE
+      )
+    ORDER BY
+        CASE WHEN r.housekeeping_status IN ('Clean', 'Inspected') THEN 0 ELSE 1 END,
+        r.room_number
+    LIMIT 1;
+
+    IF v_room_id IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO day_use_blocks (booking_detail_id, room_id, start_at, end_at)
+    VALUES (p_booking_detail_id, v_room_id, p_start, p_end);
+
+    RETURN v_room_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- ============================================================================
+-- Keep the block on the room the guest actually checked into
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION sync_day_use_block_room()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE day_use_blocks
+    SET room_id = NEW.room_id
+    WHERE booking_detail_id = NEW.booking_detail_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_sync_day_use_block_room ON room_assignments;
+CREATE TRIGGER trg_sync_day_use_block_room
+    AFTER INSERT ON room_assignments
+    FOR EACH ROW
+    WHEN (NEW.status = 'Active')
+    EXECUTE FUNCTION sync_day_use_block_room();
+
+-- ============================================================================
+-- check_in: same-day stays and rooms held by day-use slots
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION check_in(
+    p_booking_detail_id INT,
+    p_room_id INT
+) RETURNS TABLE(
+    success BOOLEAN,
+    message TEXT,
+    room_assignment_id BIGINT
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_occupancy VARCHAR(20);
+    v_housekeeping VARCHAR(50);
+    v_booking_id INT;
+    v_booking_status VARCHAR(50);
+    v_room_type_id INT;
+    v_booking_room_type_id INT;
+    v_room_number VARCHAR(10);
+    v_assignment_id BIGINT;
+    v_existing_assignment_count INT;
+    v_stay_type VARCHAR(20);
+    v_check_out_date DATE;
+    v_stay_end TIMESTAMP;
+BEGIN
+    -- ============================================================================
+    -- STEP 1: ตรวจสอบว่า booking_detail_id มีอยู่จริง
+    -- ============================================================================
+    SELECT bd.booking_id, bd.room_type_id, bd.stay_type, bd.check_out_date
+    INTO v_booking_id, v_booking_room_type_id, v_stay_type, v_check_out_date
+    FROM booking_details bd
+    WHERE bd.booking_detail_id = p_booking_detail_id;
+    
+    IF v_booking_id IS NULL THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            'ไม่พบข้อมูลการจองนี้'::TEXT,
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 2: ตรวจสอบว่ามี assignment active อยู่แล้วหรือไม่
+    -- ============================================================================
+    SELECT COUNT(*) INTO v_existing_assignment_count
+    FROM room_assignments
+    WHERE booking_detail_id = p_booking_detail_id
+      AND status = 'Active';
+    
+    IF v_existing_assignment_count > 0 THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            'การจองนี้ได้ทำการเช็คอินแล้ว'::TEXT,
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 3: ตรวจสอบสถานะการจอง
+    -- ============================================================================
+    SELECT b.status INTO v_booking_status
+    FROM bookings b
+    WHERE b.booking_id = v_booking_id
+    FOR UPDATE; -- Lock booking record
+    
+    IF v_booking_status != 'Confirmed' AND v_booking_status != 'CheckedIn' THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            FORMAT('ไม่สามารถเช็คอินได้ สถานะการจองปัจจุบัน: %s (ต้องเป็น Confirmed)', v_booking_status),
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 4: ตรวจสอบสถานะห้องและ room_type
+    -- ============================================================================
+    SELECT 
+        r.occupancy_status, 
+        r.housekeeping_status,
+        r.room_type_id,
+        r.room_number
+    INTO v_occupancy, v_housekeeping, v_room_type_id, v_room_number
+    FROM rooms r
+    WHERE r.room_id = p_room_id
+    FOR UPDATE; -- Lock room record
+    
+    IF v_occupancy IS NULL THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            'ไม่พบห้องนี้ในระบบ'::TEXT,
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ตรวจสอบว่าห้องตรงกับ room_type ที่จองหรือไม่
+    IF v_room_type_id != v_booking_room_type_id THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            FORMAT('ห้องนี้ไม่ตรงกับประเภทห้องที่จอง (ห้อง: %s, จอง: %s)', 
+                   v_room_type_id, v_booking_room_type_id),
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ตรวจสอบว่าห้องว่างหรือไม่
+    IF v_occupancy != 'Vacant' THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            FORMAT('ห้อง %s ไม่ว่าง (สถานะ: %s)', v_room_number, v_occupancy),
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ตรวจสอบว่าห้องสะอาดหรือไม่
+    IF v_housekeeping NOT IN ('Clean', 'Inspected') THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            FORMAT('ห้อง %s ยังไม่พร้อมสำหรับเช็คอิน (สถานะการทำความสะอาด: %s)', 
+                   v_room_number, v_housekeeping),
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ตรวจสอบว่าห้องไม่ได้ถูกกันไว้สำหรับแขกรายชั่วโมงคนอื่นระหว่างการเข้าพัก
+    IF v_stay_type = 'DayUse' THEN
+        SELECT dub.end_at INTO v_stay_end
+        FROM day_use_blocks dub
+        WHERE dub.booking_detail_id = p_booking_detail_id;
+    END IF;
+    v_stay_end := COALESCE(v_stay_end, v_check_out_date + TIME '12:00');
+    
+    IF day_use_block_conflict(p_room_id, NOW()::TIMESTAMP, v_stay_end, p_booking_detail_id) THEN
+        RETURN QUERY SELECT 
+            FALSE, 
+            FORMAT('ห้อง %s ถูกกันไว้สำหรับการใช้ห้องรายชั่วโมง', v_room_number),
+            NULL::BIGINT;
+        RETURN;
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 5: สร้าง room_assignment
+    -- ============================================================================
+    INSERT INTO room_assignments (
+        booking_detail_id,
+        room_id,
+        check_in_datetime,
+        status
+    ) VALUES (
+        p_booking_detail_id,
+        p_room_id,
+        NOW(),
+        'Active'
+    ) RETURNING room_assignments.room_assignment_id INTO v_assignment_id;
+    
+    -- ============================================================================
+    -- STEP 6: อัปเดตสถานะการจองเป็น 'CheckedIn'
+    -- ============================================================================
+    UPDATE bookings
+    SET status = 'CheckedIn',
+        updated_at = NOW()
+    WHERE booking_id = v_booking_id;
+    
+    -- ============================================================================
+    -- STEP 7: อัปเดตสถานะห้องเป็น 'Occupied'
+    -- ============================================================================
+    UPDATE rooms
+    SET occupancy_status = 'Occupied'
+    WHERE room_id = p_room_id;
+    
+    -- ============================================================================
+    -- STEP 8: Return success
+    -- ============================================================================
+    RETURN QUERY SELECT 
+        TRUE, 
+        FORMAT('เช็คอินสำเร็จ - ห้อง %s (Assignment ID: %s)', v_room_number, v_assignment_id),
+        v_assignment_id;
+    
+EXCEPTION
+    WHEN OTHERS THEN
+        -- จัดการ error ที่ไม่คาดคิด
+        RETURN QUERY SELECT 
+            FALSE, 
+            FORMAT('เกิดข้อผิดพลาดในการเช็คอิน: %s', SQLERRM),
+            NULL::BIGINT;
+END;
+$$;
+
+COMMENT ON FUNCTION check_in IS
+'ทำการเช็คอินแขกเข้าห้องพัก (รองรับการใช้ห้องรายชั่วโมงแบบวันเดียวกัน)
+- ตรวจสอบสถานะห้อง (Vacant + Clean/Inspected)
+- ตรวจสอบสถานะการจอง (Confirmed)
+- ตรวจสอบว่า room_type ตรงกับที่จองหรือไม่
+- ตรวจสอบว่าห้องไม่ได้ถูกกันไว้สำหรับแขกรายชั่วโมงคนอื่น
+- สร้าง room_assignment พร้อม status Active
+- อัปเดตสถานะการจองเป็น CheckedIn
+- อัปเดตสถานะห้องเป็น Occupied
+- Rollback ทั้งหมดถ้ามีข้อผิดพลาด';
+
+-- ============================================================================
+-- SEED: Day Use plan, 3h and 6h slots priced from the standard nightly rate
+-- ============================================================================
+
+INSERT INTO rate_plans (name, description, policy_id, is_day_use)
+SELECT
+    'Day Use',
+    'ใช้ห้องรายชั่วโมงระหว่างวัน 3-6 ชั่วโมง - ยกเลิกฟรีได้จนถึง 24 ชั่วโมงก่อนเข้าใช้',
+    cp.policy_id,
+    TRUE
+FROM cancellation_policies cp
+WHERE cp.name = 'Flexible'
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO day_use_rates (rate_plan_id, room_type_id, duration_hours, price)
+SELECT du.rate_plan_id, rp.room_type_id, slot.hours, ROUND(rp.price * slot.share, -1)
+FROM rate_plans du
+CROSS JOIN (VALUES (3, 0.40), (6, 0.60)) AS slot(hours, share)
+JOIN rate_plans std ON std.name = 'Standard Rate'
+JOIN rate_tiers t ON t.name = 'Standard'
+JOIN rate_pricing rp ON rp.rate_plan_id = std.rate_plan_id AND rp.rate_tier_id = t.rate_tier_id
+WHERE du.name = 'Day Use'
+ON CONFLICT (rate_plan_id, room_type_id, duration_hours) DO NOTHING;
+
+\echo 'Migration 029 completed: day-use bookings created'
@@ -0,0 +1,339 @@
+-- ============================================================================
+-- Migration 030: Long-stay Weekly and Monthly Rates
+-- ============================================================================
+-- Description: Price long stays below the sum of their nightly rates:
+--   - rate_plans.min_nights                  : eligibility rule, shortest stay the plan sells
+--   - rate_plans.billing_cycle               : 'PerStay', 'Weekly' or 'Monthly' invoices
+--   - rate_plans.housekeeping_frequency_days : days between room services of a stay
+--   - long_stay_rates                        : per plan (and optionally room type) thresholds,
+--                                              e.g. 7+ nights at -15% or 28+ nights at a
+--                                              fixed monthly price
+--   The longest threshold a stay reaches applies to every night of the stay. A
+--   monthly price is charged per night as 1/30 of the monthly price.
+--   A trigger on booking_nightly_log rewrites the room rate confirm_booking()
+--   reads from rate_pricing, so the nightly log holds the long-stay rate. It fires
+--   before trg_nightly_log_occupancy_surcharge (triggers run in name order), so
+--   occupancy surcharges are added on top of the long-stay rate, not discounted.
+--   create_booking_hold is re-created to hold stays of up to 365 nights instead of 30.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 030_add_long_stay_rates.sql
+-- ============================================================================
+
+ALTER TABLE rate_plans
+    ADD COLUMN IF NOT EXISTS min_nights INT CHECK (min_nights IS NULL OR min_nights >= 1),
+    ADD COLUMN IF NOT EXISTS billing_cycle VARCHAR(10) NOT NULL DEFAULT 'PerStay'
+        CHECK (billing_cycle IN ('PerStay', 'Weekly', 'Monthly')),
+    ADD COLUMN IF NOT EXISTS housekeeping_frequency_days INT NOT NULL DEFAULT 1
+        CHECK (housekeeping_frequency_days BETWEEN 1 AND 30);
+
+COMMENT ON COLUMN rate_plans.min_nights IS 'จำนวนคืนขั้นต่ำที่แผนราคานี้ขายได้';
+COMMENT ON COLUMN rate_plans.billing_cycle IS 'รอบการออกใบแจ้งหนี้: PerStay (ครั้งเดียว), Weekly หรือ Monthly';
+COMMENT ON COLUMN rate_plans.housekeeping_frequency_days IS 'ทำความสะอาดห้องทุกกี่วันระหว่างการเข้าพัก';
+
+-- ============================================================================
+-- long_stay_rates
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS long_stay_rates (
+    long_stay_rate_id SERIAL PRIMARY KEY,
+    rate_plan_id INT NOT NULL REFERENCES rate_plans(rate_plan_id) ON DELETE CASCADE,
+    room_type_id INT REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    min_nights INT NOT NULL CHECK (min_nights >= 2),
+    discount_percent DECIMAL(5, 2) CHECK (discount_percent IS NULL OR (discount_percent > 0 AND discount_percent < 100)),
+    monthly_price DECIMAL(10, 2) CHECK (monthly_price IS NULL OR monthly_price > 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_long_stay_rates_price CHECK ((discount_percent IS NULL) <> (monthly_price IS NULL))
+);
+
+-- One rate per plan, room type (NULL = all room types) and threshold
+CREATE UNIQUE INDEX IF NOT EXISTS uq_long_stay_rates
+    ON long_stay_rates (rate_plan_id, COALESCE(room_type_id, 0), min_nights);
+
+DROP TRIGGER IF EXISTS update_long_stay_rates_updated_at ON long_stay_rates;
+CREATE TRIGGER update_long_stay_rates_updated_at
+    BEFORE UPDATE ON long_stay_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE long_stay_rates IS 'ราคาพักระยะยาว ใช้เมื่อจำนวนคืนถึงเกณฑ์ (ส่วนลดเป็นเปอร์เซ็นต์หรือราคาเหมารายเดือน)';
+COMMENT ON COLUMN long_stay_rates.room_type_id IS 'ประเภทห้อง (NULL = ทุกประเภทห้อง)';
+COMMENT ON COLUMN long_stay_rates.min_nights IS 'จำนวนคืนขั้นต่ำที่ใช้ราคานี้';
+COMMENT ON COLUMN long_stay_rates.discount_percent IS 'ส่วนลดจากราคาต่อคืน (%)';
+COMMENT ON COLUMN long_stay_rates.monthly_price IS 'ราคาเหมาต่อ 30 คืน คิดต่อคืนเป็น 1/30';
+
+-- ============================================================================
+-- long_stay_room_rate: the room rate of a night under the long-stay rate of the stay
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION long_stay_room_rate(
+    p_rate_plan_id INT,
+    p_room_type_id INT,
+    p_nights INT,
+    p_price DECIMAL
+)
+RETURNS DECIMAL AS $$
+DECLARE
+    v_rate RECORD;
+BEGIN
+    SELECT discount_percent, monthly_price
+    INTO v_rate
+    FROM long_stay_rates
+    WHERE rate_plan_id = p_rate_plan_id
+      AND (room_type_id = p_room_type_id OR room_type_id IS NULL)
+      AND min_nights <= p_nights
+      AND is_active = TRUE
+    ORDER BY min_nights DESC, room_type_id NULLS LAST
+    LIMIT 1;
+
+    IF NOT FOUND THEN
+        RETURN p_price;
+    END IF;
+
+    IF v_rate.monthly_price IS NOT NULL THEN
+        RETURN ROUND(v_rate.monthly_price / 30, 2);
+    END IF;
+
+    RETURN ROUND(p_price * (1 - v_rate.discount_percent / 100), 2);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+COMMENT ON FUNCTION long_stay_room_rate(INT, INT, INT, DECIMAL) IS 'คำนวณราคาห้องต่อคืนตามราคาพักระยะยาวของจำนวนคืนที่เข้าพัก';
+
+-- ============================================================================
+-- Nightly log: replace the room rate written by confirm_booking() with the long-stay rate
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION apply_long_stay_rate_to_nightly_log()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_detail RECORD;
+BEGIN
+    SELECT rate_plan_id, room_type_id, (check_out_date - check_in_date) AS nights
+    INTO v_detail
+    FROM booking_details
+    WHERE booking_detail_id = NEW.booking_detail_id;
+
+    IF FOUND THEN
+        NEW.quoted_price := long_stay_room_rate(
+            v_detail.rate_plan_id, v_detail.room_type_id, v_detail.nights, NEW.quoted_price);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_nightly_log_long_stay_rate ON booking_nightly_log;
+CREATE TRIGGER trg_nightly_log_long_stay_rate
+    BEFORE INSERT ON booking_nightly_log
+    FOR EACH ROW
+    EXECUTE FUNCTION apply_long_stay_rate_to_nightly_log();
+
+-- ============================================================================
+-- create_booking_hold: allow holds for long stays (was capped at 30 nights)
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION create_booking_hold(
+    p_session_id VARCHAR(255),
+    p_guest_account_id INT,
+    p_room_type_id INT,
+    p_check_in DATE,
+    p_check_out DATE
+) RETURNS TABLE(
+    success BOOLEAN,
+    message TEXT,
+    expiry_time TIMESTAMP
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_date DATE;
+    v_available INT;
+    v_hold_expiry TIMESTAMP;
+    v_nights INT;
+    v_room_type_name VARCHAR(100);
+BEGIN
+    -- ตรวจสอบ input parameters
+    IF p_check_in IS NULL OR p_check_out IS NULL THEN
+        RETURN QUERY SELECT FALSE AS success, 'วันที่เช็คอินและเช็คเอาท์ต้องไม่เป็น NULL'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_check_out <= p_check_in THEN
+        RETURN QUERY SELECT FALSE AS success, 'วันเช็คเอาท์ต้องอยู่หลังวันเช็คอิน'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_check_in < CURRENT_DATE THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่สามารถจองย้อนหลังได้'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- คำนวณจำนวนคืน
+    v_nights := p_check_out - p_check_in;
+    
+    IF v_nights > 365 THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่สามารถจองเกิน 365 คืนได้'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- ดึงชื่อประเภทห้องสำหรับ error message
+    SELECT name INTO v_room_type_name
+    FROM room_types
+    WHERE room_type_id = p_room_type_id;
+    
+    IF v_room_type_name IS NULL THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่พบประเภทห้องที่เลือก'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- กำหนดเวลาหมดอายุ (15 นาที)
+    v_hold_expiry := NOW() + INTERVAL '15 minutes';
+    
+    -- ============================================================================
+    -- STEP 1: ปล่อย hold เก่าของ guest นี้ที่ซ้ำกับวันที่ใหม่
+    -- ============================================================================
+    -- ลด tentative_count สำหรับ hold เก่าที่จะถูกแทนที่
+    UPDATE room_inventory ri
+    SET tentative_count = GREATEST(0, tentative_count - 1),
+        updated_at = NOW()
+    WHERE EXISTS (
+        SELECT 1
+        FROM booking_holds bh
+        WHERE bh.guest_account_id = p_guest_account_id
+          AND bh.room_type_id = ri.room_type_id
+          AND bh.date = ri.date
+          AND bh.date >= p_check_in
+          AND bh.date < p_check_out
+          AND bh.hold_expiry > NOW()
+    );
+    
+    -- ลบ hold เก่าที่ซ้ำกับวันที่ใหม่
+    DELETE FROM booking_holds
+    WHERE guest_account_id = p_guest_account_id
+      AND date >= p_check_in
+      AND date < p_check_out
+      AND hold_expiry > NOW();
+    
+    -- ============================================================================
+    -- STEP 2: ตรวจสอบห้องว่างและอัปเดต tentative_count แบบ atomic
+    -- ============================================================================
+    v_date := p_check_in;
+    
+    WHILE v_date < p_check_out LOOP
+        -- ตรวจสอบและ lock row สำหรับวันนี้
+        SELECT (allotment - booked_count - tentative_count) INTO v_available
+        FROM room_inventory
+        WHERE room_type_id = p_room_type_id 
+          AND date = v_date
+        FOR UPDATE; -- Lock row เพื่อป้องกัน race condition
+        
+        -- ถ้าไม่มี inventory record สำหรับวันนี้
+        IF v_available IS NULL THEN
+            RETURN QUERY SELECT 
+                FALSE AS success, 
+                FORMAT('ไม่พบข้อมูล inventory สำหรับ %s วันที่ %s', v_room_type_name, v_date::TEXT)::TEXT AS message,
+                NULL::TIMESTAMP AS expiry_time;
+            RETURN;
+        END IF;
+        
+        -- ถ้าห้องไม่ว่าง
+        IF v_available <= 0 THEN
+            RETURN QUERY SELECT 
+                FALSE AS success, 
+                FORMAT('ห้อง %s ไม่ว่างสำหรับวันที่ %s กรุณาเลือกห้องอื่นหรือวันที่อื่น', 
+                       v_room_type_name, v_date::TEXT)::TEXT AS message,
+                NULL::TIMESTAMP AS expiry_time;
+            RETURN;
+        END IF;
+        
+        -- อัปเดต tentative_count (เพิ่ม 1)
+        UPDATE room_inventory
+        SET tentative_count = tentative_count + 1,
+            updated_at = NOW()
+        WHERE room_type_id = p_room_type_id 
+          AND date = v_date;
+        
+        v_date := v_date + INTERVAL '1 day';
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 3: สร้าง booking hold records
+    -- ============================================================================
+    v_date := p_check_in;
+    
+    WHILE v_date < p_check_out LOOP
+        INSERT INTO booking_holds (
+            session_id,
+            guest_account_id,
+            room_type_id,
+            date,
+            hold_expiry
+        ) VALUES (
+            p_session_id,
+            p_guest_account_id,
+            p_room_type_id,
+            v_date,
+            v_hold_expiry
+        );
+        
+        v_date := v_date + INTERVAL '1 day';
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 4: Return success
+    -- ============================================================================
+    RETURN QUERY SELECT 
+        TRUE AS success, 
+        FORMAT('สร้าง hold สำเร็จสำหรับ %s (%s คืน) หมดอายุเวลา %s', 
+               v_room_type_name, v_nights, TO_CHAR(v_hold_expiry, 'HH24:MI:SS'))::TEXT AS message,
+        v_hold_expiry AS expiry_time;
+    
+EXCEPTION
+    WHEN OTHERS THEN
+        -- จัดการ error ที่ไม่คาดคิด
+        RETURN QUERY SELECT 
+            FALSE AS success, 
+            FORMAT('เกิดข้อผิดพลาด: %s', SQLERRM)::TEXT AS message,
+            NULL::TIMESTAMP AS expiry_time;
+END;
+$$;
+
+-- ============================================================================
+-- SEED: Long Stay plan, 7+ nights at -15% and 28+ nights at a monthly price
+-- ============================================================================
+
+INSERT INTO rate_plans (name, description, policy_id, min_nights, billing_cycle, housekeeping_frequency_days,
+                        base_rate_plan_id, derived_adjustment_type, derived_adjustment_value)
+SELECT
+    'Long Stay',
+    'พักตั้งแต่ 7 คืน ลด 15% / พักตั้งแต่ 28 คืน ราคาเหมารายเดือน - ออกใบแจ้งหนี้รายเดือน ทำความสะอาดสัปดาห์ละครั้ง',
+    cp.policy_id,
+    7,
+    'Monthly',
+    7,
+    std.rate_plan_id,
+    'percentage',
+    0
+FROM cancellation_policies cp
+CROSS JOIN rate_plans std
+WHERE cp.name = 'Flexible'
+  AND std.name = 'Standard Rate'
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO long_stay_rates (rate_plan_id, room_type_id, min_nights, discount_percent)
+SELECT rate_plan_id, NULL, 7, 15
+FROM rate_plans
+WHERE name = 'Long Stay'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO long_stay_rates (rate_plan_id, room_type_id, min_nights, monthly_price)
+SELECT ls.rate_plan_id, rp.room_type_id, 28, ROUND(rp.price * 30 * 0.55, -2)
+FROM rate_plans ls
+JOIN rate_plans std ON std.name = 'Standard Rate'
+JOIN rate_tiers t ON t.name = 'Standard'
+JOIN rate_pricing rp ON rp.rate_plan_id = std.rate_plan_id AND rp.rate_tier_id = t.rate_tier_id
+WHERE ls.name = 'Long Stay'
+ON CONFLICT DO NOTHING;
+
+\echo 'Migration 030 completed: long-stay rates, billing cycles and housekeeping frequency added'
@@ -0,0 +1,480 @@
+-- ============================================================================
+-- Migration 031: Multi-room Holds
+-- ============================================================================
+-- Description: Lets one booking session hold several rooms so a party split
+--   across room types (multi-room search combinations) can be booked together:
+--   - create_booking_hold takes p_rooms, the number of rooms of the room type
+--     to hold, so a combination with several rooms of one type is held at once
+--   - it releases a signed-in guest's overlapping holds from other sessions and
+--     the session's own holds of the same room type; holds of other room types
+--     in the same session are kept
+--   - replaced holds give back one tentative room per hold, not one per date
+--   - confirm_booking gives back the tentative rooms of every hold it deletes
+--     that the booking did not use, such as a room held and left out
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 031_multi_room_holds.sql
+-- ============================================================================
+
+-- p_rooms เป็น parameter ใหม่ ต้องลบ function เดิม (5 parameters) ก่อน
+DROP FUNCTION IF EXISTS create_booking_hold(VARCHAR, INT, INT, DATE, DATE);
+
+CREATE OR REPLACE FUNCTION create_booking_hold(
+    p_session_id VARCHAR(255),
+    p_guest_account_id INT,
+    p_room_type_id INT,
+    p_check_in DATE,
+    p_check_out DATE,
+    p_rooms INT DEFAULT 1
+) RETURNS TABLE(
+    success BOOLEAN,
+    message TEXT,
+    expiry_time TIMESTAMP
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_date DATE;
+    v_available INT;
+    v_hold_expiry TIMESTAMP;
+    v_nights INT;
+    v_room_type_name VARCHAR(100);
+BEGIN
+    -- ตรวจสอบ input parameters
+    IF p_check_in IS NULL OR p_check_out IS NULL THEN
+        RETURN QUERY SELECT FALSE AS success, 'วันที่เช็คอินและเช็คเอาท์ต้องไม่เป็น NULL'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_check_out <= p_check_in THEN
+        RETURN QUERY SELECT FALSE AS success, 'วันเช็คเอาท์ต้องอยู่หลังวันเช็คอิน'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_check_in < CURRENT_DATE THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่สามารถจองย้อนหลังได้'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_rooms IS NULL OR p_rooms < 1 THEN
+        RETURN QUERY SELECT FALSE AS success, 'จำนวนห้องต้องมีอย่างน้อย 1 ห้อง'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- คำนวณจำนวนคืน
+    v_nights := p_check_out - p_check_in;
+    
+    IF v_nights > 365 THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่สามารถจองเกิน 365 คืนได้'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- ดึงชื่อประเภทห้องสำหรับ error message
+    SELECT name INTO v_room_type_name
+    FROM room_types
+    WHERE room_type_id = p_room_type_id;
+    
+    IF v_room_type_name IS NULL THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่พบประเภทห้องที่เลือก'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- กำหนดเวลาหมดอายุ (15 นาที)
+    v_hold_expiry := NOW() + INTERVAL '15 minutes';
+    
+    -- ============================================================================
+    -- STEP 1: ปล่อย hold เก่าของ guest นี้ที่ซ้ำกับวันที่ใหม่
+    -- ============================================================================
+    -- hold ประเภทห้องอื่นของ session เดียวกันถูกเก็บไว้ เพื่อให้จองหลายห้องในการจองเดียวได้
+    -- hold ประเภทห้องเดียวกันถูกแทนที่ เพราะ session เดิมถูกใช้ซ้ำเมื่อเลือกห้องใหม่
+    -- (หลายห้องประเภทเดียวกัน hold ในครั้งเดียวด้วย p_rooms)
+    -- ลด tentative_count ตามจำนวน hold ที่ถูกแทนที่ในแต่ละ (room_type_id, date)
+    WITH replaced_holds AS (
+        SELECT
+            room_type_id,
+            date,
+            COUNT(*) as hold_count
+        FROM booking_holds
+        WHERE ((session_id = p_session_id AND room_type_id = p_room_type_id)
+               OR (guest_account_id = p_guest_account_id AND session_id <> p_session_id))
+          AND date >= p_check_in
+          AND date < p_check_out
+          AND hold_expiry > NOW()
+        GROUP BY room_type_id, date
+    )
+    UPDATE room_inventory ri
+    SET tentative_count = GREATEST(0, tentative_count - rh.hold_count),
+        updated_at = NOW()
+    FROM replaced_holds rh
+    WHERE ri.room_type_id = rh.room_type_id
+      AND ri.date = rh.date;
+    
+    -- ลบ hold เก่าที่ซ้ำกับวันที่ใหม่
+    DELETE FROM booking_holds
+    WHERE ((session_id = p_session_id AND room_type_id = p_room_type_id)
+           OR (guest_account_id = p_guest_account_id AND session_id <> p_session_id))
+      AND date >= p_check_in
+      AND date < p_check_out
+      AND hold_expiry > NOW();
+    
+    -- ============================================================================
+    -- STEP 2: ตรวจสอบห้องว่างและอัปเดต tentative_count แบบ atomic
+    -- ============================================================================
+    v_date := p_check_in;
+    
+    WHILE v_date < p_check_out LOOP
+        -- ตรวจสอบและ lock row สำหรับวันนี้
+        SELECT (allotment - booked_count - tentative_count) INTO v_available
+        FROM room_inventory
+        WHERE room_type_id = p_room_type_id 
+          AND date = v_date
+        FOR UPDATE; -- Lock row เพื่อป้องกัน race condition
+        
+        -- ถ้าไม่มี inventory record สำหรับวันนี้
+        IF v_available IS NULL THEN
+            RETURN QUERY SELECT 
+                FALSE AS success, 
+                FORMAT('ไม่พบข้อมูล inventory สำหรับ %s วันที่ %s', v_room_type_name, v_date::TEXT)::TEXT AS message,
+                NULL::TIMESTAMP AS expiry_time;
+            RETURN;
+        END IF;
+        
+        -- ถ้าห้องว่างไม่พอ
+        IF v_available < p_rooms THEN
+            RETURN QUERY SELECT 
+                FALSE AS success, 
+                FORMAT('ห้อง %s ไม่ว่างสำหรับวันที่ %s กรุณาเลือกห้องอื่นหรือวันที่อื่น', 
+                       v_room_type_name, v_date::TEXT)::TEXT AS message,
+                NULL::TIMESTAMP AS expiry_time;
+            RETURN;
+        END IF;
+        
+        -- อัปเดต tentative_count (เพิ่มตามจำนวนห้อง)
+        UPDATE room_inventory
+        SET tentative_count = tentative_count + p_rooms,
+            updated_at = NOW()
+        WHERE room_type_id = p_room_type_id 
+          AND date = v_date;
+        
+        v_date := v_date + INTERVAL '1 day';
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 3: สร้าง booking hold records
+    -- ============================================================================
+    v_date := p_check_in;
+    
+    WHILE v_date < p_check_out LOOP
+        -- หนึ่ง record ต่อห้องต่อคืน
+        INSERT INTO booking_holds (
+            session_id,
+            guest_account_id,
+            room_type_id,
+            date,
+            hold_expiry
+        )
+        SELECT
+            p_session_id,
+            p_guest_account_id,
+            p_room_type_id,
+            v_date,
+            v_hold_expiry
+        FROM generate_series(1, p_rooms);
+        
+        v_date := v_date + INTERVAL '1 day';
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 4: Return success
+    -- ============================================================================
+    RETURN QUERY SELECT 
+        TRUE AS success, 
+        FORMAT('สร้าง hold สำเร็จสำหรับ %s %s ห้อง (%s คืน) หมดอายุเวลา %s', 
+               v_room_type_name, p_rooms, v_nights, TO_CHAR(v_hold_expiry, 'HH24:MI:SS'))::TEXT AS message,
+        v_hold_expiry AS expiry_time;
+    
+EXCEPTION
+    WHEN OTHERS THEN
+        -- จัดการ error ที่ไม่คาดคิด
+        RETURN QUERY SELECT 
+            FALSE AS success, 
+            FORMAT('เกิดข้อผิดพลาด: %s', SQLERRM)::TEXT AS message,
+            NULL::TIMESTAMP AS expiry_time;
+END;
+$$;
+
+COMMENT ON FUNCTION create_booking_hold IS 
+'สร้างการจองห้องชั่วคราว (hold) สำหรับ 15 นาที
+- ตรวจสอบห้องว่างแบบ atomic ด้วย FOR UPDATE
+- ปล่อย hold เก่าจาก session อื่นที่ซ้ำกับวันที่ใหม่อัตโนมัติ
+- session เดียวกัน hold ได้หลายห้อง (จองหลายห้องในการจองเดียว)
+- p_rooms: จำนวนห้องประเภทเดียวกันที่ hold พร้อมกัน hold ใหม่ของประเภทห้องเดิมใน session เดียวกันแทนที่ของเก่า
+- Rollback ทั้งหมดถ้าห้องไม่ว่างในวันใดวันหนึ่ง
+- ป้องกัน race condition ด้วย row-level locking';
+
+-- ============================================================================
+-- confirm_booking: give back the tentative rooms of unused holds
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION confirm_booking(
+    p_booking_id INT
+) RETURNS TABLE(
+    success BOOLEAN,
+    message TEXT,
+    booking_id INT
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_status VARCHAR(50);
+    v_guest_id INT;
+    v_guest_account_id INT;
+    v_detail RECORD;
+    v_date DATE;
+    v_available INT;
+    v_allotment INT;
+    v_booked_count INT;
+    v_tentative_count INT;
+    v_total_nights INT := 0;
+    v_policy_name VARCHAR(100);
+    v_policy_description TEXT;
+    v_rate_tier_id INT;
+    v_price DECIMAL(10, 2);
+    v_existing_log_count INT;
+    v_held_room_types INT[] := '{}';
+    v_held_dates DATE[] := '{}';
+BEGIN
+    -- ============================================================================
+    -- STEP 1: ตรวจสอบสถานะการจอง
+    -- ============================================================================
+    SELECT b.status, b.guest_id INTO v_status, v_guest_id
+    FROM bookings b
+    WHERE b.booking_id = p_booking_id
+    FOR UPDATE;
+    
+    IF v_status IS NULL THEN
+        RETURN QUERY SELECT 
+            FALSE::BOOLEAN, 
+            'ไม่พบการจองนี้'::TEXT,
+            NULL::INT;
+        RETURN;
+    END IF;
+    
+    IF v_status != 'PendingPayment' THEN
+        RETURN QUERY SELECT 
+            FALSE::BOOLEAN, 
+            FORMAT('ไม่สามารถยืนยันการจองได้ สถานะปัจจุบัน: %s (ต้องเป็น PendingPayment)', v_status)::TEXT,
+            NULL::INT;
+        RETURN;
+    END IF;
+    
+    SELECT ga.guest_account_id INTO v_guest_account_id
+    FROM guest_accounts ga
+    WHERE ga.guest_id = v_guest_id;
+    
+    -- ============================================================================
+    -- STEP 2: ตรวจสอบห้องว่างและอัปเดต inventory
+    -- ============================================================================
+    FOR v_detail IN 
+        SELECT 
+            bd.booking_detail_id,
+            bd.room_type_id,
+            bd.rate_plan_id,
+            bd.check_in_date,
+            bd.check_out_date,
+            rt.name as room_type_name
+        FROM booking_details bd
+        JOIN room_types rt ON bd.room_type_id = rt.room_type_id
+        WHERE bd.booking_id = p_booking_id
+        ORDER BY bd.booking_detail_id
+    LOOP
+        v_date := v_detail.check_in_date;
+        
+        WHILE v_date < v_detail.check_out_date LOOP
+            -- ดึงข้อมูล inventory พร้อม lock
+            SELECT 
+                allotment,
+                booked_count,
+                tentative_count,
+                (allotment - booked_count - tentative_count)
+            INTO 
+                v_allotment,
+                v_booked_count,
+                v_tentative_count,
+                v_available
+            FROM room_inventory
+            WHERE room_type_id = v_detail.room_type_id 
+              AND date = v_date
+            FOR UPDATE;
+            
+            IF v_allotment IS NULL THEN
+                RETURN QUERY SELECT 
+                    FALSE::BOOLEAN, 
+                    FORMAT('ไม่พบข้อมูล inventory สำหรับ %s วันที่ %s', 
+                           v_detail.room_type_name, v_date::TEXT)::TEXT,
+                    NULL::INT;
+                RETURN;
+            END IF;
+            
+            -- ตรวจสอบว่ามีที่ว่างพอหรือไม่ (หลังจาก confirm แล้ว)
+            -- ถ้า tentative_count > 0 แสดงว่ามี hold อยู่ ให้ลด tentative และเพิ่ม booked
+            -- ถ้า tentative_count = 0 แสดงว่าไม่มี hold ต้องตรวจสอบว่ามีที่ว่างพอ
+            IF v_tentative_count > 0 THEN
+                -- มี hold อยู่ ให้ย้ายจาก tentative ไป booked
+                UPDATE room_inventory
+                SET booked_count = booked_count + 1,
+                    tentative_count = tentative_count - 1,
+                    updated_at = NOW()
+                WHERE room_type_id = v_detail.room_type_id 
+                  AND date = v_date;
+                
+                -- จำคืนที่ใช้ห้องจาก tentative ไว้ ลบ hold ใน STEP 6 จะได้ไม่ลดซ้ำ
+                v_held_room_types := v_held_room_types || v_detail.room_type_id;
+                v_held_dates := v_held_dates || v_date;
+            ELSE
+                -- ไม่มี hold ต้องตรวจสอบว่ามีที่ว่างพอ
+                IF v_booked_count >= v_allotment THEN
+                    RETURN QUERY SELECT 
+                        FALSE::BOOLEAN, 
+                        FORMAT('ห้อง %s เต็มแล้วสำหรับวันที่ %s (Booked: %s/%s)', 
+                               v_detail.room_type_name, v_date::TEXT, v_booked_count, v_allotment)::TEXT,
+                        NULL::INT;
+                    RETURN;
+                END IF;
+                
+                -- มีที่ว่างพอ ให้เพิ่ม booked_count
+                UPDATE room_inventory
+                SET booked_count = booked_count + 1,
+                    updated_at = NOW()
+                WHERE room_type_id = v_detail.room_type_id 
+                  AND date = v_date;
+            END IF;
+            
+            -- ============================================================================
+            -- STEP 3: บันทึก nightly log (ถ้ายังไม่มี)
+            -- ============================================================================
+            SELECT COUNT(*) INTO v_existing_log_count
+            FROM booking_nightly_log
+            WHERE booking_detail_id = v_detail.booking_detail_id
+              AND date = v_date;
+            
+            IF v_existing_log_count = 0 THEN
+                SELECT pc.rate_tier_id INTO v_rate_tier_id
+                FROM pricing_calendar pc
+                WHERE pc.date = v_date;
+                
+                IF v_rate_tier_id IS NULL THEN
+                    SELECT rate_tier_id INTO v_rate_tier_id
+                    FROM rate_tiers
+                    ORDER BY rate_tier_id
+                    LIMIT 1;
+                END IF;
+                
+                SELECT rp.price INTO v_price
+                FROM rate_pricing rp
+                WHERE rp.rate_plan_id = v_detail.rate_plan_id
+                  AND rp.room_type_id = v_detail.room_type_id
+                  AND rp.rate_tier_id = v_rate_tier_id;
+                
+                IF v_price IS NULL THEN
+                    v_price := 0;
+                END IF;
+                
+                INSERT INTO booking_nightly_log (
+                    booking_detail_id,
+                    date,
+                    quoted_price
+                ) VALUES (
+                    v_detail.booking_detail_id,
+                    v_date,
+                    v_price
+                );
+            END IF;
+            
+            v_total_nights := v_total_nights + 1;
+            v_date := v_date + INTERVAL '1 day';
+        END LOOP;
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 4: บันทึก policy snapshot
+    -- ============================================================================
+    SELECT cp.name, cp.description 
+    INTO v_policy_name, v_policy_description
+    FROM booking_details bd
+    JOIN rate_plans rp ON bd.rate_plan_id = rp.rate_plan_id
+    JOIN cancellation_policies cp ON rp.policy_id = cp.policy_id
+    WHERE bd.booking_id = p_booking_id
+    LIMIT 1;
+    
+    IF v_policy_name IS NULL THEN
+        v_policy_name := 'No Refund';
+        v_policy_description := 'ไม่สามารถยกเลิกหรือคืนเงินได้';
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 5: อัปเดตสถานะเป็น Confirmed
+    -- ============================================================================
+    UPDATE bookings
+    SET status = 'Confirmed',
+        policy_name = v_policy_name,
+        policy_description = v_policy_description,
+        updated_at = NOW()
+    WHERE booking_id = p_booking_id;
+    
+    -- ============================================================================
+    -- STEP 6: ลบ booking holds
+    -- ============================================================================
+    -- hold ที่ไม่ได้ถูกใช้ใน STEP 2 (เช่น ห้องที่ hold ไว้แต่ไม่ได้จอง) ต้องคืน
+    -- tentative_count ด้วย ทุกแถวที่ลบ ไม่ใช่เฉพาะห้องที่ยืนยัน
+    IF v_guest_account_id IS NOT NULL THEN
+        WITH deleted_holds AS (
+            SELECT
+                room_type_id,
+                date,
+                COUNT(*) as hold_count
+            FROM booking_holds
+            WHERE guest_account_id = v_guest_account_id
+            GROUP BY room_type_id, date
+        ),
+        converted_holds AS (
+            SELECT
+                h.room_type_id,
+                h.date,
+                COUNT(*) as converted_count
+            FROM unnest(v_held_room_types, v_held_dates) AS h(room_type_id, date)
+            GROUP BY h.room_type_id, h.date
+        )
+        UPDATE room_inventory ri
+        SET tentative_count = GREATEST(0, ri.tentative_count - (dh.hold_count - COALESCE(ch.converted_count, 0))),
+            updated_at = NOW()
+        FROM deleted_holds dh
+        LEFT JOIN converted_holds ch
+          ON ch.room_type_id = dh.room_type_id
+         AND ch.date = dh.date
+        WHERE ri.room_type_id = dh.room_type_id
+          AND ri.date = dh.date
+          AND dh.hold_count > COALESCE(ch.converted_count, 0);
+        
+        DELETE FROM booking_holds
+        WHERE guest_account_id = v_guest_account_id;
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 7: Return success
+    -- ============================================================================
+    RETURN QUERY SELECT 
+        TRUE::BOOLEAN, 
+        FORMAT('ยืนยันการจองสำเร็จ (Booking ID: %s, %s คืน)', 
+               p_booking_id, v_total_nights)::TEXT,
+        p_booking_id::INT;
+    
+EXCEPTION
+    WHEN OTHERS THEN
+        RETURN QUERY SELECT 
+            FALSE::BOOLEAN, 
+            FORMAT('เกิดข้อผิดพลาดในการยืนยันการจอง: %s', SQLERRM)::TEXT,
+            NULL::INT;
+END;
+$$;
+
+COMMENT ON FUNCTION confirm_booking IS 
+'ยืนยันการจองและอัปเดตสถานะเป็น Confirmed (คืน tentative_count ของ hold ที่ไม่ได้ใช้)';
+
+\echo 'Migration 031 completed: a booking session can hold several rooms'
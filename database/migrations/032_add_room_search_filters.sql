@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Migration 032: Room Search Filters
+-- ============================================================================
+-- Description: Room type attributes the guest search filters and sorts on:
+--   - room_types.view_type     : the view of the room type, e.g. 'City', 'Garden', 'Pool'
+--   - room_types.is_accessible : wheelchair accessible rooms
+--   Bed type and size already exist (room_types.bed_type, room_types.size_sqm) and
+--   amenities come from room_type_amenities. Popularity is counted from the
+--   booked rooms of recent bookings, served by the index below.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 032_add_room_search_filters.sql
+-- ============================================================================
+
+ALTER TABLE room_types
+    ADD COLUMN IF NOT EXISTS view_type VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS is_accessible BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN room_types.view_type IS 'วิวของห้อง เช่น City, Garden, Pool';
+COMMENT ON COLUMN room_types.is_accessible IS 'ห้องรองรับผู้ใช้รถเข็น';
+
+-- Popularity: booked rooms per room type over recent bookings
+CREATE INDEX IF NOT EXISTS idx_booking_details_room_type
+    ON booking_details(room_type_id);
+
+-- ============================================================================
+-- SEED DATA
+-- ============================================================================
+
+UPDATE room_types SET view_type = 'City', is_accessible = TRUE
+WHERE name = 'Standard Room' AND view_type IS NULL;
+
+UPDATE room_types SET view_type = 'Garden'
+WHERE name = 'Deluxe Room' AND view_type IS NULL;
+
+UPDATE room_types SET view_type = 'Pool'
+WHERE name = 'Suite Room' AND view_type IS NULL;
+
+\echo 'Migration 032 completed: room view and accessibility added for search filters'
@@ -0,0 +1,31 @@
+-- ============================================================================
+-- Migration 033: Room Management
+-- ============================================================================
+-- Description: Columns for managing room types, rooms and amenities from the
+-- manager API instead of seed migrations:
+--   - room_types.image_url : the main image of the room type
+--   - rooms.features       : features of the individual room, e.g. 'Corner', 'Connecting'
+--   - rooms.is_active      : FALSE once a room is retired; retired rooms keep their
+--                            history and are set OutOfService so they are never assigned
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 033_add_room_management.sql
+-- ============================================================================
+
+ALTER TABLE room_types
+    ADD COLUMN IF NOT EXISTS image_url TEXT;
+
+COMMENT ON COLUMN room_types.image_url IS 'รูปภาพหลักของประเภทห้อง';
+
+ALTER TABLE rooms
+    ADD COLUMN IF NOT EXISTS features TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN rooms.features IS 'คุณสมบัติเฉพาะของห้อง เช่น Corner, Connecting';
+COMMENT ON COLUMN rooms.is_active IS 'FALSE = ห้องถูกปลดออกจากการใช้งาน';
+
+-- Active rooms per room type, counted when allotments or rooms change
+CREATE INDEX IF NOT EXISTS idx_rooms_active_room_type
+    ON rooms(room_type_id) WHERE is_active = TRUE;
+
+\echo 'Migration 033 completed: room type images, room features and room retirement added'
@@ -0,0 +1,43 @@
+-- ============================================================================
+-- Migration 034: Room Type Image Gallery
+-- ============================================================================
+-- Description: Several images per room type, uploaded by managers:
+--   - room_type_images : one row per image with its caption and display order
+--   storage_key is the blob store prefix of the image; the thumbnail, medium and
+--   large JPEGs are stored under it. The key contains a hash of the uploaded file,
+--   so image URLs never change and can be cached for good.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 034_create_room_type_images.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS room_type_images (
+    image_id SERIAL PRIMARY KEY,
+    room_type_id INT NOT NULL REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL,
+    caption VARCHAR(255),
+    sort_order INT NOT NULL DEFAULT 0,
+    width INT NOT NULL CHECK (width > 0),
+    height INT NOT NULL CHECK (height > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_room_type_images_key UNIQUE (room_type_id, storage_key)
+);
+
+COMMENT ON TABLE room_type_images IS 'รูปภาพของประเภทห้อง (แกลเลอรี)';
+COMMENT ON COLUMN room_type_images.storage_key IS 'คีย์ใน blob store ของรูป (ไฟล์ thumbnail, medium และ large อยู่ใต้คีย์นี้)';
+COMMENT ON COLUMN room_type_images.caption IS 'คำบรรยายรูป';
+COMMENT ON COLUMN room_type_images.sort_order IS 'ลำดับการแสดงผล (น้อยแสดงก่อน)';
+COMMENT ON COLUMN room_type_images.width IS 'ความกว้างของรูปต้นฉบับ (พิกเซล)';
+COMMENT ON COLUMN room_type_images.height IS 'ความสูงของรูปต้นฉบับ (พิกเซล)';
+
+CREATE INDEX IF NOT EXISTS idx_room_type_images_order
+    ON room_type_images(room_type_id, sort_order);
+
+DROP TRIGGER IF EXISTS update_room_type_images_updated_at ON room_type_images;
+CREATE TRIGGER update_room_type_images_updated_at
+    BEFORE UPDATE ON room_type_images
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+\echo 'Migration 034 completed: room type image gallery created'
@@ -0,0 +1,64 @@
+-- ============================================================================
+-- Migration 035: Maintenance Work Orders
+-- ============================================================================
+-- Description: Maintenance issues reported for rooms become work orders:
+--   - roles                   : new ENGINEER role for the maintenance team
+--   - maintenance_work_orders : one row per issue with its category, priority,
+--                               photos, assignee, status and resolution
+--   A room stays MaintenanceRequired while it has unresolved work orders and goes
+--   back to Dirty for cleaning when the last one is resolved.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 035_create_maintenance_work_orders.sql
+-- ============================================================================
+
+INSERT INTO roles (role_id, role_name, role_code, description) VALUES
+(5, 'Engineer', 'ENGINEER', 'ช่างซ่อมบำรุง - รับและดำเนินการใบสั่งงานซ่อมบำรุง')
+ON CONFLICT (role_id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS maintenance_work_orders (
+    work_order_id SERIAL PRIMARY KEY,
+    room_id INT NOT NULL REFERENCES rooms(room_id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL DEFAULT 'Other'
+        CHECK (category IN ('Plumbing', 'Electrical', 'HVAC', 'Furniture', 'Appliance', 'Structural', 'Other')),
+    priority VARCHAR(10) NOT NULL DEFAULT 'Medium'
+        CHECK (priority IN ('Low', 'Medium', 'High', 'Urgent')),
+    status VARCHAR(20) NOT NULL DEFAULT 'Open'
+        CHECK (status IN ('Open', 'InProgress', 'OnHold', 'Resolved')),
+    description TEXT NOT NULL,
+    photo_keys TEXT[] NOT NULL DEFAULT '{}',
+    reported_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    assigned_to INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    resolution_notes TEXT,
+    reported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    resolved_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_work_order_resolution CHECK (
+        (status = 'Resolved') = (resolved_at IS NOT NULL)
+    )
+);
+
+COMMENT ON TABLE maintenance_work_orders IS 'ใบสั่งงานซ่อมบำรุงห้องพัก';
+COMMENT ON COLUMN maintenance_work_orders.category IS 'หมวดงาน: Plumbing, Electrical, HVAC, Furniture, Appliance, Structural, Other';
+COMMENT ON COLUMN maintenance_work_orders.priority IS 'ความเร่งด่วน: Low, Medium, High, Urgent';
+COMMENT ON COLUMN maintenance_work_orders.status IS 'สถานะ: Open, InProgress, OnHold, Resolved';
+COMMENT ON COLUMN maintenance_work_orders.photo_keys IS 'คีย์ใน blob store ของรูปประกอบ';
+COMMENT ON COLUMN maintenance_work_orders.assigned_to IS 'ช่างผู้รับผิดชอบ';
+COMMENT ON COLUMN maintenance_work_orders.started_at IS 'เวลาที่เริ่มซ่อมครั้งแรก';
+COMMENT ON COLUMN maintenance_work_orders.resolved_at IS 'เวลาที่ซ่อมเสร็จ (ใช้คำนวณเวลาซ่อมเฉลี่ย)';
+
+CREATE INDEX IF NOT EXISTS idx_work_orders_room_unresolved
+    ON maintenance_work_orders(room_id) WHERE status <> 'Resolved';
+CREATE INDEX IF NOT EXISTS idx_work_orders_assignee_unresolved
+    ON maintenance_work_orders(assigned_to) WHERE status <> 'Resolved';
+CREATE INDEX IF NOT EXISTS idx_work_orders_resolved_at
+    ON maintenance_work_orders(resolved_at) WHERE status = 'Resolved';
+
+DROP TRIGGER IF EXISTS update_maintenance_work_orders_updated_at ON maintenance_work_orders;
+CREATE TRIGGER update_maintenance_work_orders_updated_at
+    BEFORE UPDATE ON maintenance_work_orders
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+\echo 'Migration 035 completed: ENGINEER role and maintenance work orders created'
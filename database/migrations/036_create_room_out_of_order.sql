@@ -0,0 +1,112 @@
+-- ============================================================================
+-- Migration 036: Out-of-Order Room Scheduling
+-- ============================================================================
+-- Description: Date ranges in which a physical room cannot be sold:
+--   - room_out_of_order                : one row per period with its reason
+--   - return_room_from_out_of_order()  : puts a room back in service
+--   - apply_out_of_order_schedule()    : starts and ends periods by date (run hourly)
+--   Each night of a period takes one room off the room type's allotment. The room
+--   is OutOfService while a period is Active and goes back to Dirty for cleaning
+--   (or MaintenanceRequired while it has unresolved work orders) when it ends.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 036_create_room_out_of_order.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS room_out_of_order (
+    out_of_order_id SERIAL PRIMARY KEY,
+    room_id INT NOT NULL REFERENCES rooms(room_id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'Scheduled'
+        CHECK (status IN ('Scheduled', 'Active', 'Completed', 'Cancelled')),
+    work_order_id INT REFERENCES maintenance_work_orders(work_order_id) ON DELETE SET NULL,
+    created_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    ended_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_out_of_order_dates CHECK (end_date >= start_date)
+);
+
+COMMENT ON TABLE room_out_of_order IS 'ช่วงวันที่ห้องปิดปรับปรุง (ไม่เปิดขาย)';
+COMMENT ON COLUMN room_out_of_order.start_date IS 'คืนแรกที่ปิดห้อง';
+COMMENT ON COLUMN room_out_of_order.end_date IS 'คืนสุดท้ายที่ปิดห้อง (ห้องกลับมาใช้ได้วันถัดไป)';
+COMMENT ON COLUMN room_out_of_order.status IS 'สถานะ: Scheduled, Active, Completed, Cancelled';
+COMMENT ON COLUMN room_out_of_order.work_order_id IS 'ใบสั่งงานซ่อมที่เกี่ยวข้อง (ถ้ามี)';
+COMMENT ON COLUMN room_out_of_order.ended_at IS 'เวลาที่ห้องกลับมาให้บริการหรือยกเลิกการปิดห้อง';
+
+CREATE INDEX IF NOT EXISTS idx_room_out_of_order_current
+    ON room_out_of_order(room_id, start_date) WHERE status IN ('Scheduled', 'Active');
+
+DROP TRIGGER IF EXISTS update_room_out_of_order_updated_at ON room_out_of_order;
+CREATE TRIGGER update_room_out_of_order_updated_at
+    BEFORE UPDATE ON room_out_of_order
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Puts an out-of-service room back in service once none of its periods is active
+CREATE OR REPLACE FUNCTION return_room_from_out_of_order(p_room_id INT)
+RETURNS BOOLEAN AS $$
+BEGIN
+    UPDATE rooms r
+    SET housekeeping_status = CASE
+            WHEN EXISTS (
+                SELECT 1 FROM maintenance_work_orders wo
+                WHERE wo.room_id = r.room_id AND wo.status <> 'Resolved'
+            ) THEN 'MaintenanceRequired'
+            ELSE 'Dirty'
+        END
+    WHERE r.room_id = p_room_id
+      AND r.housekeeping_status = 'OutOfService'
+      AND NOT EXISTS (
+          SELECT 1 FROM room_out_of_order o
+          WHERE o.room_id = r.room_id AND o.status = 'Active'
+      );
+
+    RETURN FOUND;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION return_room_from_out_of_order IS 'คืนห้องที่ปิดปรับปรุงกลับมาให้บริการ';
+
+-- Starts periods whose first night has come and ends those whose last night has passed
+CREATE OR REPLACE FUNCTION apply_out_of_order_schedule()
+RETURNS TABLE(rooms_taken_out INT, rooms_returned INT) AS $$
+DECLARE
+    v_room_id INT;
+BEGIN
+    rooms_taken_out := 0;
+    rooms_returned := 0;
+
+    UPDATE room_out_of_order
+    SET status = 'Active'
+    WHERE status = 'Scheduled'
+      AND start_date <= CURRENT_DATE
+      AND end_date >= CURRENT_DATE;
+
+    FOR v_room_id IN
+        UPDATE room_out_of_order
+        SET status = 'Completed', ended_at = CURRENT_TIMESTAMP
+        WHERE status IN ('Scheduled', 'Active')
+          AND end_date < CURRENT_DATE
+        RETURNING room_id
+    LOOP
+        IF return_room_from_out_of_order(v_room_id) THEN
+            rooms_returned := rooms_returned + 1;
+        END IF;
+    END LOOP;
+
+    UPDATE rooms
+    SET housekeeping_status = 'OutOfService'
+    WHERE housekeeping_status <> 'OutOfService'
+      AND room_id IN (SELECT room_id FROM room_out_of_order WHERE status = 'Active');
+    GET DIAGNOSTICS rooms_taken_out = ROW_COUNT;
+
+    RETURN NEXT;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION apply_out_of_order_schedule IS 'เริ่มและสิ้นสุดช่วงปิดห้องตามวันที่ (เรียกทุกชั่วโมง)';
+
+\echo 'Migration 036 completed: out-of-order room scheduling created'
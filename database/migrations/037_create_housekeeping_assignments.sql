@@ -0,0 +1,63 @@
+-- ============================================================================
+-- Migration 037: Housekeeping Assignments
+-- ============================================================================
+-- Description: Rooms to clean are handed out to room attendants:
+--   - housekeeping_credits     : workload credits of a room type for a checkout
+--                                clean and for a stayover service
+--   - housekeeping_assignments : the attendant cleaning a room on a given day,
+--                                with the credits it counts for and its progress
+--   Assignments are made by a supervisor or by the auto-balancer; a room is
+--   started when it goes to Cleaning and completed when it goes to Clean.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 037_create_housekeeping_assignments.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS housekeeping_credits (
+    room_type_id INT PRIMARY KEY REFERENCES room_types(room_type_id) ON DELETE CASCADE,
+    checkout_credits NUMERIC(4, 2) NOT NULL DEFAULT 1.0 CHECK (checkout_credits > 0),
+    stayover_credits NUMERIC(4, 2) NOT NULL DEFAULT 0.5 CHECK (stayover_credits > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE housekeeping_credits IS 'หน่วยภาระงานทำความสะอาดของแต่ละประเภทห้อง';
+COMMENT ON COLUMN housekeeping_credits.checkout_credits IS 'หน่วยงานเมื่อทำความสะอาดห้องหลังแขกเช็คเอาท์';
+COMMENT ON COLUMN housekeeping_credits.stayover_credits IS 'หน่วยงานเมื่อทำความสะอาดห้องที่แขกยังพักอยู่';
+
+DROP TRIGGER IF EXISTS update_housekeeping_credits_updated_at ON housekeeping_credits;
+CREATE TRIGGER update_housekeeping_credits_updated_at
+    BEFORE UPDATE ON housekeeping_credits
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Larger rooms take longer to turn around
+INSERT INTO housekeeping_credits (room_type_id, checkout_credits, stayover_credits)
+SELECT room_type_id,
+       CASE WHEN name ILIKE '%suite%' THEN 2.0 WHEN name ILIKE '%deluxe%' THEN 1.5 ELSE 1.0 END,
+       CASE WHEN name ILIKE '%suite%' THEN 1.0 WHEN name ILIKE '%deluxe%' THEN 0.75 ELSE 0.5 END
+FROM room_types
+ON CONFLICT (room_type_id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS housekeeping_assignments (
+    assignment_id SERIAL PRIMARY KEY,
+    room_id INT NOT NULL REFERENCES rooms(room_id) ON DELETE CASCADE,
+    staff_id INT NOT NULL REFERENCES staff(staff_id) ON DELETE CASCADE,
+    task_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    task_type VARCHAR(20) NOT NULL CHECK (task_type IN ('Checkout', 'Stayover')),
+    credits NUMERIC(4, 2) NOT NULL CHECK (credits > 0),
+    assigned_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    CONSTRAINT uq_housekeeping_assignment_room_date UNIQUE (room_id, task_date)
+);
+
+COMMENT ON TABLE housekeeping_assignments IS 'การมอบหมายห้องให้พนักงานทำความสะอาดในแต่ละวัน';
+COMMENT ON COLUMN housekeeping_assignments.task_type IS 'ประเภทงาน: Checkout (ห้องว่าง), Stayover (แขกยังพักอยู่)';
+COMMENT ON COLUMN housekeeping_assignments.credits IS 'หน่วยภาระงานของห้องนี้ ณ เวลาที่มอบหมาย';
+COMMENT ON COLUMN housekeeping_assignments.completed_at IS 'เวลาที่ห้องเปลี่ยนเป็นสถานะ Clean';
+
+CREATE INDEX IF NOT EXISTS idx_housekeeping_assignments_staff_date
+    ON housekeeping_assignments(staff_id, task_date);
+
+\echo 'Migration 037 completed: housekeeping assignments created'
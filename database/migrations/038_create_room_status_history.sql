@@ -0,0 +1,64 @@
+-- ============================================================================
+-- Migration 038: Room Status History
+-- ============================================================================
+-- Description: Audit trail of room status changes:
+--   - room_status_history     : one row per housekeeping or occupancy transition
+--   - log_room_status_change() : trigger recording every change made to rooms
+--   The acting staff member and notes are read from the transaction settings
+--   app.staff_id and app.status_notes, which the backend sets with set_config()
+--   before changing a room. Changes made by scheduled jobs have no staff member.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 038_create_room_status_history.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS room_status_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    room_id INT NOT NULL REFERENCES rooms(room_id) ON DELETE CASCADE,
+    status_type VARCHAR(20) NOT NULL CHECK (status_type IN ('Housekeeping', 'Occupancy')),
+    old_status VARCHAR(50) NOT NULL,
+    new_status VARCHAR(50) NOT NULL,
+    updated_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    notes TEXT,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE room_status_history IS 'ประวัติการเปลี่ยนสถานะห้อง';
+COMMENT ON COLUMN room_status_history.status_type IS 'ประเภทสถานะ: Housekeeping หรือ Occupancy';
+COMMENT ON COLUMN room_status_history.updated_by IS 'พนักงานที่เปลี่ยนสถานะ (NULL เมื่อเปลี่ยนโดยระบบ)';
+COMMENT ON COLUMN room_status_history.notes IS 'หมายเหตุ เช่น เหตุผลที่ตรวจห้องไม่ผ่าน';
+
+CREATE INDEX IF NOT EXISTS idx_room_status_history_room
+    ON room_status_history(room_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_room_status_history_updated_at
+    ON room_status_history(updated_at) WHERE status_type = 'Housekeeping';
+
+CREATE OR REPLACE FUNCTION log_room_status_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_staff_id INT := NULLIF(current_setting('app.staff_id', true), '')::INT;
+    v_notes TEXT := NULLIF(current_setting('app.status_notes', true), '');
+BEGIN
+    IF NEW.housekeeping_status IS DISTINCT FROM OLD.housekeeping_status THEN
+        INSERT INTO room_status_history (room_id, status_type, old_status, new_status, updated_by, notes)
+        VALUES (NEW.room_id, 'Housekeeping', OLD.housekeeping_status, NEW.housekeeping_status, v_staff_id, v_notes);
+    END IF;
+
+    IF NEW.occupancy_status IS DISTINCT FROM OLD.occupancy_status THEN
+        INSERT INTO room_status_history (room_id, status_type, old_status, new_status, updated_by, notes)
+        VALUES (NEW.room_id, 'Occupancy', OLD.occupancy_status, NEW.occupancy_status, v_staff_id, v_notes);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION log_room_status_change IS 'บันทึกประวัติเมื่อสถานะห้องเปลี่ยน';
+
+DROP TRIGGER IF EXISTS log_room_status_change ON rooms;
+CREATE TRIGGER log_room_status_change
+    AFTER UPDATE OF housekeeping_status, occupancy_status ON rooms
+    FOR EACH ROW
+    EXECUTE FUNCTION log_room_status_change();
+
+\echo 'Migration 038 completed: room status history created'
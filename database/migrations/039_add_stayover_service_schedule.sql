@@ -0,0 +1,127 @@
+-- ============================================================================
+-- Migration 039: Stayover Service Scheduling and Do-Not-Disturb
+-- ============================================================================
+-- Description: Occupied rooms are serviced on a schedule instead of every night:
+--   - long_stay_rates.housekeeping_frequency_days : overrides the plan's frequency
+--                                                   for stays reaching the threshold
+--   - booking_details.green_opt_out               : guest declined full stayover
+--                                                   service (green program)
+--   - rooms.stayover_service                      : service due in an occupied room,
+--                                                   'Stayover' (full) or 'Refresh'
+--                                                   (towels and trash only)
+--   - rooms.do_not_disturb                        : DND flag set by the front desk
+--   - housekeeping_credits                        : credits of a refresh and the
+--                                                   estimated minutes of each service
+--   - stay_housekeeping_frequency()               : days between services of a stay
+--   The night audit marks an occupied room Dirty when a service is due: every
+--   housekeeping_frequency_days nights of the stay, as a Refresh for green program
+--   guests. DND flags are cleared by the night audit and when the guest departs.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 039_add_stayover_service_schedule.sql
+-- ============================================================================
+
+ALTER TABLE long_stay_rates
+    ADD COLUMN IF NOT EXISTS housekeeping_frequency_days INT
+        CHECK (housekeeping_frequency_days IS NULL OR housekeeping_frequency_days BETWEEN 1 AND 30);
+
+COMMENT ON COLUMN long_stay_rates.housekeeping_frequency_days IS 'ทำความสะอาดห้องทุกกี่วันเมื่อเข้าพักถึงเกณฑ์นี้ (NULL = ตามแผนราคา)';
+
+ALTER TABLE booking_details
+    ADD COLUMN IF NOT EXISTS green_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN booking_details.green_opt_out IS 'แขกขอไม่รับบริการทำความสะอาดเต็มรูปแบบระหว่างพัก (Green program)';
+
+ALTER TABLE rooms
+    ADD COLUMN IF NOT EXISTS stayover_service VARCHAR(20)
+        CHECK (stayover_service IS NULL OR stayover_service IN ('Stayover', 'Refresh')),
+    ADD COLUMN IF NOT EXISTS do_not_disturb BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS dnd_set_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS dnd_set_by INT REFERENCES staff(staff_id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN rooms.stayover_service IS 'บริการที่ต้องทำในห้องที่มีแขกพัก: Stayover (เต็มรูปแบบ) หรือ Refresh (เปลี่ยนผ้าเช็ดตัวและเก็บขยะ)';
+COMMENT ON COLUMN rooms.do_not_disturb IS 'แขกแจ้งห้ามรบกวน';
+COMMENT ON COLUMN rooms.dnd_set_by IS 'พนักงานที่ตั้งสถานะห้ามรบกวน';
+
+-- ============================================================================
+-- stay_housekeeping_frequency: the long-stay rate the stay reaches, else the plan
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION stay_housekeeping_frequency(p_booking_detail_id INT)
+RETURNS INT AS $$
+    SELECT COALESCE((
+        SELECT l.housekeeping_frequency_days
+        FROM long_stay_rates l
+        WHERE l.rate_plan_id = bd.rate_plan_id
+          AND (l.room_type_id = bd.room_type_id OR l.room_type_id IS NULL)
+          AND l.min_nights <= bd.check_out_date - bd.check_in_date
+          AND l.is_active = TRUE
+        ORDER BY l.min_nights DESC, l.room_type_id NULLS LAST
+        LIMIT 1
+    ), rp.housekeeping_frequency_days, 1)
+    FROM booking_details bd
+    JOIN rate_plans rp ON rp.rate_plan_id = bd.rate_plan_id
+    WHERE bd.booking_detail_id = p_booking_detail_id;
+$$ LANGUAGE sql STABLE;
+
+COMMENT ON FUNCTION stay_housekeeping_frequency(INT) IS 'ทำความสะอาดห้องทุกกี่วันสำหรับการเข้าพักนี้ (ราคาพักระยะยาวก่อน แล้วจึงแผนราคา)';
+
+-- ============================================================================
+-- housekeeping_credits: refresh credits and estimated minutes per service
+-- ============================================================================
+
+ALTER TABLE housekeeping_credits
+    ADD COLUMN IF NOT EXISTS refresh_credits NUMERIC(4, 2) NOT NULL DEFAULT 0.25 CHECK (refresh_credits > 0),
+    ADD COLUMN IF NOT EXISTS checkout_minutes INT NOT NULL DEFAULT 25 CHECK (checkout_minutes > 0),
+    ADD COLUMN IF NOT EXISTS stayover_minutes INT NOT NULL DEFAULT 15 CHECK (stayover_minutes > 0),
+    ADD COLUMN IF NOT EXISTS refresh_minutes INT NOT NULL DEFAULT 5 CHECK (refresh_minutes > 0);
+
+COMMENT ON COLUMN housekeeping_credits.refresh_credits IS 'หน่วยงานเมื่อเปลี่ยนผ้าเช็ดตัวและเก็บขยะ (Refresh)';
+COMMENT ON COLUMN housekeeping_credits.checkout_minutes IS 'เวลาโดยประมาณ (นาที) ทำความสะอาดห้องหลังเช็คเอาท์';
+COMMENT ON COLUMN housekeeping_credits.stayover_minutes IS 'เวลาโดยประมาณ (นาที) ทำความสะอาดห้องที่แขกยังพักอยู่';
+COMMENT ON COLUMN housekeeping_credits.refresh_minutes IS 'เวลาโดยประมาณ (นาที) สำหรับ Refresh';
+
+-- Keep the times the task list used to estimate for a checkout clean
+UPDATE housekeeping_credits hc
+SET checkout_minutes = CASE WHEN rt.name ILIKE '%suite%' THEN 45 WHEN rt.name ILIKE '%deluxe%' THEN 35 ELSE 25 END,
+    stayover_minutes = CASE WHEN rt.name ILIKE '%suite%' THEN 25 WHEN rt.name ILIKE '%deluxe%' THEN 20 ELSE 15 END,
+    refresh_minutes = CASE WHEN rt.name ILIKE '%suite%' THEN 10 WHEN rt.name ILIKE '%deluxe%' THEN 8 ELSE 5 END,
+    refresh_credits = CASE WHEN rt.name ILIKE '%suite%' THEN 0.5 ELSE 0.25 END
+FROM room_types rt
+WHERE rt.room_type_id = hc.room_type_id;
+
+ALTER TABLE housekeeping_assignments
+    DROP CONSTRAINT IF EXISTS housekeeping_assignments_task_type_check;
+ALTER TABLE housekeeping_assignments
+    ADD CONSTRAINT housekeeping_assignments_task_type_check
+        CHECK (task_type IN ('Checkout', 'Stayover', 'Refresh'));
+
+COMMENT ON COLUMN housekeeping_assignments.task_type IS 'ประเภทงาน: Checkout (ห้องว่าง), Stayover (แขกยังพักอยู่), Refresh (Green program)';
+
+-- ============================================================================
+-- Departures: the room needs a checkout clean and the DND flag no longer applies
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION clear_room_service_on_departure()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.occupancy_status = 'Occupied' AND NEW.occupancy_status <> 'Occupied' THEN
+        NEW.stayover_service := NULL;
+        NEW.do_not_disturb := FALSE;
+        NEW.dnd_set_at := NULL;
+        NEW.dnd_set_by := NULL;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION clear_room_service_on_departure IS 'ล้างบริการระหว่างพักและสถานะห้ามรบกวนเมื่อแขกออกจากห้อง';
+
+DROP TRIGGER IF EXISTS clear_room_service_on_departure ON rooms;
+CREATE TRIGGER clear_room_service_on_departure
+    BEFORE UPDATE OF occupancy_status ON rooms
+    FOR EACH ROW
+    EXECUTE FUNCTION clear_room_service_on_departure();
+
+\echo 'Migration 039 completed: stayover service scheduling and do-not-disturb added'
@@ -0,0 +1,133 @@
+-- ============================================================================
+-- Migration 040: Room Pre-Assignment
+-- ============================================================================
+-- Description: Rooms picked for arrivals ahead of check-in:
+--   - guests.is_vip                  : VIP guests get the first pick of rooms
+--   - booking_details preferences    : floor ('High' or 'Low'), view, an accessible
+--                                      room and a room connecting to the other rooms
+--                                      of the same booking
+--   - rooms.connecting_room_id       : the room behind the connecting door; both
+--                                      rooms point at each other (sync_connecting_rooms)
+--   - room_pre_assignments           : the room picked for each arrival with the
+--                                      preferences it does not meet. The pre-assignment
+--                                      job runs every evening for the next day and
+--                                      keeps the rooms staff picked by hand.
+--   A pre-assignment is removed when the guest checks in, whichever room they are
+--   given, and when the booking is cancelled or marked no-show.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 040_create_room_pre_assignments.sql
+-- ============================================================================
+
+ALTER TABLE guests
+    ADD COLUMN IF NOT EXISTS is_vip BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN guests.is_vip IS 'แขก VIP ได้รับการจัดห้องก่อน';
+
+ALTER TABLE booking_details
+    ADD COLUMN IF NOT EXISTS floor_preference VARCHAR(10)
+        CHECK (floor_preference IS NULL OR floor_preference IN ('High', 'Low')),
+    ADD COLUMN IF NOT EXISTS view_preference VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS accessible_room BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS connecting_room BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN booking_details.floor_preference IS 'ชั้นที่แขกต้องการ: High (ชั้นสูง) หรือ Low (ชั้นต่ำ)';
+COMMENT ON COLUMN booking_details.view_preference IS 'วิวที่แขกต้องการ เช่น Pool, City';
+COMMENT ON COLUMN booking_details.accessible_room IS 'แขกต้องการห้องสำหรับผู้ใช้รถเข็น';
+COMMENT ON COLUMN booking_details.connecting_room IS 'ต้องการห้องที่มีประตูเชื่อมกับห้องอื่นในการจองเดียวกัน';
+
+ALTER TABLE rooms
+    ADD COLUMN IF NOT EXISTS connecting_room_id INT REFERENCES rooms(room_id) ON DELETE SET NULL;
+
+ALTER TABLE rooms
+    DROP CONSTRAINT IF EXISTS chk_rooms_connecting_room;
+ALTER TABLE rooms
+    ADD CONSTRAINT chk_rooms_connecting_room CHECK (connecting_room_id <> room_id);
+
+COMMENT ON COLUMN rooms.connecting_room_id IS 'ห้องที่มีประตูเชื่อมกับห้องนี้';
+
+-- Keeps both rooms of a connecting pair pointing at each other: the partner connects
+-- back and any room the partner or this room connected to before is released
+CREATE OR REPLACE FUNCTION sync_connecting_rooms()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.connecting_room_id IS NOT NULL THEN
+        UPDATE rooms SET connecting_room_id = NULL
+        WHERE room_id = OLD.connecting_room_id AND connecting_room_id = NEW.room_id;
+    END IF;
+
+    IF NEW.connecting_room_id IS NOT NULL THEN
+        UPDATE rooms SET connecting_room_id = NULL
+        WHERE connecting_room_id = NEW.connecting_room_id AND room_id <> NEW.room_id;
+
+        UPDATE rooms SET connecting_room_id = NEW.room_id
+        WHERE room_id = NEW.connecting_room_id
+          AND connecting_room_id IS DISTINCT FROM NEW.room_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS sync_connecting_rooms ON rooms;
+CREATE TRIGGER sync_connecting_rooms
+    AFTER UPDATE OF connecting_room_id ON rooms
+    FOR EACH ROW
+    WHEN (OLD.connecting_room_id IS DISTINCT FROM NEW.connecting_room_id)
+    EXECUTE FUNCTION sync_connecting_rooms();
+
+CREATE TABLE IF NOT EXISTS room_pre_assignments (
+    booking_detail_id INT PRIMARY KEY REFERENCES booking_details(booking_detail_id) ON DELETE CASCADE,
+    room_id INT NOT NULL REFERENCES rooms(room_id) ON DELETE CASCADE,
+    unmet_preferences TEXT[] NOT NULL DEFAULT '{}',
+    is_manual BOOLEAN NOT NULL DEFAULT FALSE,
+    assigned_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE room_pre_assignments IS 'ห้องที่จัดไว้ล่วงหน้าสำหรับแขกที่จะเข้าพัก';
+COMMENT ON COLUMN room_pre_assignments.unmet_preferences IS 'ความต้องการที่ห้องนี้ไม่ตรง: floor, view, connecting, room_move';
+COMMENT ON COLUMN room_pre_assignments.is_manual IS 'TRUE = พนักงานเลือกห้องเอง (งานจัดห้องอัตโนมัติจะไม่เปลี่ยน)';
+COMMENT ON COLUMN room_pre_assignments.assigned_by IS 'พนักงานที่เลือกห้อง (NULL เมื่อจัดโดยระบบ)';
+
+CREATE INDEX IF NOT EXISTS idx_room_pre_assignments_room
+    ON room_pre_assignments(room_id);
+
+DROP TRIGGER IF EXISTS update_room_pre_assignments_updated_at ON room_pre_assignments;
+CREATE TRIGGER update_room_pre_assignments_updated_at
+    BEFORE UPDATE ON room_pre_assignments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Removes the pre-assignment of a stay once it is checked in, cancelled or a no-show
+CREATE OR REPLACE FUNCTION release_room_pre_assignment()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'room_assignments' THEN
+        DELETE FROM room_pre_assignments WHERE booking_detail_id = NEW.booking_detail_id;
+    ELSE
+        DELETE FROM room_pre_assignments pa
+        USING booking_details bd
+        WHERE pa.booking_detail_id = bd.booking_detail_id
+          AND bd.booking_id = NEW.booking_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS release_room_pre_assignment ON room_assignments;
+CREATE TRIGGER release_room_pre_assignment
+    AFTER INSERT ON room_assignments
+    FOR EACH ROW
+    EXECUTE FUNCTION release_room_pre_assignment();
+
+DROP TRIGGER IF EXISTS release_room_pre_assignment ON bookings;
+CREATE TRIGGER release_room_pre_assignment
+    AFTER UPDATE OF status ON bookings
+    FOR EACH ROW
+    WHEN (NEW.status IN ('Cancelled', 'NoShow') AND OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION release_room_pre_assignment();
+
+\echo 'Migration 040 completed: room pre-assignment, guest room preferences and connecting rooms added'
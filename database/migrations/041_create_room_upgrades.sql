@@ -0,0 +1,49 @@
+-- ============================================================================
+-- Migration 041: Room Upgrades
+-- ============================================================================
+-- Description: Upgrades of a booked room to another room type:
+--   - room_upgrades : every upgrade of a booking detail, complimentary or paid
+--                     per night, made by staff or accepted by the guest from an
+--                     upsell offer before arrival
+--   The booking detail takes the new room type and keeps its rate plan and
+--   nightly prices; a paid upgrade adds its charge to bookings.total_amount.
+--   Upgrades go to a room type with a higher base price. One booked room moves
+--   from the old type to the new type in room_inventory for every remaining
+--   night (from today for guests already in house, who move to a room of the
+--   new type with move_room in the same transaction).
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 041_create_room_upgrades.sql
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS room_upgrades (
+    upgrade_id SERIAL PRIMARY KEY,
+    booking_detail_id INT NOT NULL REFERENCES booking_details(booking_detail_id) ON DELETE CASCADE,
+    from_room_type_id INT NOT NULL REFERENCES room_types(room_type_id) ON DELETE RESTRICT,
+    to_room_type_id INT NOT NULL REFERENCES room_types(room_type_id) ON DELETE RESTRICT,
+    upgrade_type VARCHAR(20) NOT NULL CHECK (upgrade_type IN ('Complimentary', 'Paid')),
+    source VARCHAR(20) NOT NULL DEFAULT 'Staff' CHECK (source IN ('Staff', 'Upsell')),
+    charge_per_night DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (charge_per_night >= 0),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    total_charge DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (total_charge >= 0),
+    reason TEXT,
+    upgraded_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_room_upgrades_types CHECK (to_room_type_id <> from_room_type_id),
+    CONSTRAINT chk_room_upgrades_dates CHECK (end_date > start_date),
+    CONSTRAINT chk_room_upgrades_charge CHECK (upgrade_type = 'Paid' OR charge_per_night = 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_upgrades_detail ON room_upgrades(booking_detail_id);
+CREATE INDEX IF NOT EXISTS idx_room_upgrades_created ON room_upgrades(created_at);
+
+COMMENT ON TABLE room_upgrades IS 'การอัปเกรดประเภทห้องของรายการจอง (ฟรีหรือคิดเงินเพิ่มต่อคืน) โดยคงราคาห้องเดิม';
+COMMENT ON COLUMN room_upgrades.upgrade_type IS 'Complimentary = อัปเกรดฟรี, Paid = คิดเงินเพิ่มต่อคืน';
+COMMENT ON COLUMN room_upgrades.source IS 'Staff = พนักงานอัปเกรด, Upsell = แขกตอบรับข้อเสนออัปเกรดก่อนเข้าพัก';
+COMMENT ON COLUMN room_upgrades.start_date IS 'คืนแรกที่อัปเกรด (วันนี้ สำหรับแขกที่เข้าพักอยู่แล้ว)';
+COMMENT ON COLUMN room_upgrades.end_date IS 'วันเช็คเอาท์ ณ เวลาที่อัปเกรด';
+COMMENT ON COLUMN room_upgrades.total_charge IS 'ค่าอัปเกรดรวม (รวมอยู่ใน bookings.total_amount)';
+COMMENT ON COLUMN room_upgrades.upgraded_by IS 'พนักงานที่อัปเกรด (NULL เมื่อแขกตอบรับข้อเสนอเอง)';
+
+\echo 'Migration 041 completed: room upgrades added'
@@ -0,0 +1,99 @@
+-- ============================================================================
+-- Migration 042: Early Check-In and Late Check-Out
+-- ============================================================================
+-- Description: Requests to arrive before or leave after the standard times
+--   (check-in 14:00, check-out 12:00) of an overnight stay:
+--   - stay_time_fees     : fee tiers per request type. An early check-in tier
+--                          covers arrivals from its hour, a late check-out tier
+--                          covers departures until its hour.
+--   - stay_time_requests : requests made by guests (Pending until staff decide)
+--                          or by staff (Approved at once). An approved request
+--                          adds its fee to bookings.total_amount; cancelling it
+--                          takes the fee off again.
+--   Requests are checked against the rooms of the type on the day (stayovers,
+--   departures and the minutes housekeeping needs to turn a room around,
+--   day-use slots, rooms out of order) and against the room itself when one is
+--   assigned or pre-assigned.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 042_create_stay_time_requests.sql
+-- ============================================================================
+
+-- ============================================================================
+-- stay_time_fees
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS stay_time_fees (
+    fee_id SERIAL PRIMARY KEY,
+    request_type VARCHAR(20) NOT NULL CHECK (request_type IN ('EarlyCheckIn', 'LateCheckOut')),
+    tier_hour INT NOT NULL CHECK (tier_hour BETWEEN 0 AND 23),
+    fee DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_stay_time_fees UNIQUE (request_type, tier_hour),
+    CONSTRAINT chk_stay_time_fees_hour CHECK (
+        (request_type = 'EarlyCheckIn' AND tier_hour < 14)
+        OR (request_type = 'LateCheckOut' AND tier_hour > 12)
+    )
+);
+
+COMMENT ON TABLE stay_time_fees IS 'ค่าธรรมเนียมเช็คอินก่อนเวลาและเช็คเอาท์หลังเวลาแบบขั้นบันได';
+COMMENT ON COLUMN stay_time_fees.tier_hour IS 'EarlyCheckIn = เช็คอินได้ตั้งแต่ชั่วโมงนี้, LateCheckOut = เช็คเอาท์ได้ถึงชั่วโมงนี้';
+
+DROP TRIGGER IF EXISTS update_stay_time_fees_updated_at ON stay_time_fees;
+CREATE TRIGGER update_stay_time_fees_updated_at
+    BEFORE UPDATE ON stay_time_fees
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO stay_time_fees (request_type, tier_hour, fee) VALUES
+    ('EarlyCheckIn', 11, 500),
+    ('EarlyCheckIn', 8, 1000),
+    ('LateCheckOut', 14, 500),
+    ('LateCheckOut', 18, 1000)
+ON CONFLICT (request_type, tier_hour) DO NOTHING;
+
+-- ============================================================================
+-- stay_time_requests
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS stay_time_requests (
+    request_id SERIAL PRIMARY KEY,
+    booking_detail_id INT NOT NULL REFERENCES booking_details(booking_detail_id) ON DELETE CASCADE,
+    request_type VARCHAR(20) NOT NULL CHECK (request_type IN ('EarlyCheckIn', 'LateCheckOut')),
+    requested_at TIMESTAMP NOT NULL,
+    fee DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'Pending'
+        CHECK (status IN ('Pending', 'Approved', 'Declined', 'Cancelled')),
+    source VARCHAR(20) NOT NULL DEFAULT 'Guest' CHECK (source IN ('Guest', 'Staff')),
+    note TEXT,
+    decline_reason TEXT,
+    decided_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE stay_time_requests IS 'คำขอเช็คอินก่อนเวลาหรือเช็คเอาท์หลังเวลาของรายการจอง';
+COMMENT ON COLUMN stay_time_requests.requested_at IS 'เวลาที่ขอเช็คอิน (วันเช็คอิน) หรือเช็คเอาท์ (วันเช็คเอาท์)';
+COMMENT ON COLUMN stay_time_requests.fee IS 'ค่าธรรมเนียม (รวมอยู่ใน bookings.total_amount เมื่ออนุมัติ)';
+COMMENT ON COLUMN stay_time_requests.status IS 'สถานะ: Pending, Approved, Declined, Cancelled';
+COMMENT ON COLUMN stay_time_requests.source IS 'Guest = แขกขอเอง (รอพนักงานอนุมัติ), Staff = พนักงานบันทึก (อนุมัติทันที)';
+COMMENT ON COLUMN stay_time_requests.decided_by IS 'พนักงานที่อนุมัติหรือปฏิเสธคำขอ';
+
+-- One open request of each type per booked room
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stay_time_requests_open
+    ON stay_time_requests(booking_detail_id, request_type)
+    WHERE status IN ('Pending', 'Approved');
+
+CREATE INDEX IF NOT EXISTS idx_stay_time_requests_date
+    ON stay_time_requests((requested_at::DATE), status);
+
+DROP TRIGGER IF EXISTS update_stay_time_requests_updated_at ON stay_time_requests;
+CREATE TRIGGER update_stay_time_requests_updated_at
+    BEFORE UPDATE ON stay_time_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+\echo 'Migration 042 completed: early check-in and late check-out requests added'
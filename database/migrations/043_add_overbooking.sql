@@ -0,0 +1,722 @@
+-- ============================================================================
+-- Migration 043: Overbooking and Walked Guests
+-- ============================================================================
+-- Description: Lets revenue managers sell a few rooms beyond the allotment on
+--   dates with many no-shows, and lets the front desk relocate ("walk") guests
+--   who arrive to an oversold night:
+--   - room_inventory.overbooking_type/value : overbooking limit per room type and
+--                                             date, a number of rooms (Absolute)
+--                                             or a share of the allotment
+--                                             (Percentage, rounded down)
+--   - overbooking_rooms()                   : rooms that may be sold beyond the
+--                                             allotment
+--   - chk_inventory_capacity, create_booking_hold() and confirm_booking() allow
+--     booked + tentative rooms up to allotment + overbooking_rooms()
+--   - match_waitlist() still offers rooms within the allotment only; waitlisted
+--     guests are never sold overbooked rooms
+--   - partner_hotels : hotels guests are walked to
+--   - booking_walks  : every walk of a booked room with the nights walked, the
+--                      room revenue waived and the compensation given
+--   A walk releases the walked nights in room_inventory and removes them from
+--   the stay. Room upgrades keep to the allotment and never oversell.
+--
+-- Usage:
+--   psql -U postgres -d hotel_booking -f 043_add_overbooking.sql
+-- ============================================================================
+
+-- ============================================================================
+-- Overbooking limits
+-- ============================================================================
+
+ALTER TABLE room_inventory
+    ADD COLUMN IF NOT EXISTS overbooking_type VARCHAR(20)
+        CHECK (overbooking_type IN ('Absolute', 'Percentage')),
+    ADD COLUMN IF NOT EXISTS overbooking_value DECIMAL(6, 2) NOT NULL DEFAULT 0
+        CHECK (overbooking_value >= 0);
+
+COMMENT ON COLUMN room_inventory.overbooking_type IS 'ประเภทการขายเกิน: Absolute = จำนวนห้อง, Percentage = ร้อยละของ allotment (NULL = ไม่ขายเกิน)';
+COMMENT ON COLUMN room_inventory.overbooking_value IS 'จำนวนห้องหรือร้อยละที่ขายเกิน allotment ได้';
+
+CREATE OR REPLACE FUNCTION overbooking_rooms(
+    p_allotment INT,
+    p_type VARCHAR,
+    p_value DECIMAL
+) RETURNS INT AS $$
+    SELECT CASE p_type
+        WHEN 'Absolute' THEN FLOOR(p_value)::INT
+        WHEN 'Percentage' THEN FLOOR(GREATEST(p_allotment, 0) * p_value / 100)::INT
+        ELSE 0
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+COMMENT ON FUNCTION overbooking_rooms(INT, VARCHAR, DECIMAL) IS 'จำนวนห้องที่ขายเกิน allotment ได้ตาม overbooking limit';
+
+ALTER TABLE room_inventory DROP CONSTRAINT IF EXISTS chk_inventory_capacity;
+ALTER TABLE room_inventory ADD CONSTRAINT chk_inventory_capacity CHECK (
+    booked_count + tentative_count <= allotment + overbooking_rooms(allotment, overbooking_type, overbooking_value)
+);
+
+-- ============================================================================
+-- create_booking_hold: hold rooms up to the overbooking limit
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION create_booking_hold(
+    p_session_id VARCHAR(255),
+    p_guest_account_id INT,
+    p_room_type_id INT,
+    p_check_in DATE,
+    p_check_out DATE,
+    p_rooms INT DEFAULT 1
+) RETURNS TABLE(
+    success BOOLEAN,
+    message TEXT,
+    expiry_time TIMESTAMP
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_date DATE;
+    v_available INT;
+    v_hold_expiry TIMESTAMP;
+    v_nights INT;
+    v_room_type_name VARCHAR(100);
+BEGIN
+    -- ตรวจสอบ input parameters
+    IF p_check_in IS NULL OR p_check_out IS NULL THEN
+        RETURN QUERY SELECT FALSE AS success, 'วันที่เช็คอินและเช็คเอาท์ต้องไม่เป็น NULL'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_check_out <= p_check_in THEN
+        RETURN QUERY SELECT FALSE AS success, 'วันเช็คเอาท์ต้องอยู่หลังวันเช็คอิน'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_check_in < CURRENT_DATE THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่สามารถจองย้อนหลังได้'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    IF p_rooms IS NULL OR p_rooms < 1 THEN
+        RETURN QUERY SELECT FALSE AS success, 'จำนวนห้องต้องมีอย่างน้อย 1 ห้อง'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- คำนวณจำนวนคืน
+    v_nights := p_check_out - p_check_in;
+    
+    IF v_nights > 365 THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่สามารถจองเกิน 365 คืนได้'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- ดึงชื่อประเภทห้องสำหรับ error message
+    SELECT name INTO v_room_type_name
+    FROM room_types
+    WHERE room_type_id = p_room_type_id;
+    
+    IF v_room_type_name IS NULL THEN
+        RETURN QUERY SELECT FALSE AS success, 'ไม่พบประเภทห้องที่เลือก'::TEXT AS message, NULL::TIMESTAMP AS expiry_time;
+        RETURN;
+    END IF;
+    
+    -- กำหนดเวลาหมดอายุ (15 นาที)
+    v_hold_expiry := NOW() + INTERVAL '15 minutes';
+    
+    -- ============================================================================
+    -- STEP 1: ปล่อย hold เก่าของ guest นี้ที่ซ้ำกับวันที่ใหม่
+    -- ============================================================================
+    -- hold ประเภทห้องอื่นของ session เดียวกันถูกเก็บไว้ เพื่อให้จองหลายห้องในการจองเดียวได้
+    -- hold ประเภทห้องเดียวกันถูกแทนที่ เพราะ session เดิมถูกใช้ซ้ำเมื่อเลือกห้องใหม่
+    -- (หลายห้องประเภทเดียวกัน hold ในครั้งเดียวด้วย p_rooms)
+    -- ลด tentative_count ตามจำนวน hold ที่ถูกแทนที่ในแต่ละ (room_type_id, date)
+    WITH replaced_holds AS (
+        SELECT
+            room_type_id,
+            date,
+            COUNT(*) as hold_count
+        FROM booking_holds
+        WHERE ((session_id = p_session_id AND room_type_id = p_room_type_id)
+               OR (guest_account_id = p_guest_account_id AND session_id <> p_session_id))
+          AND date >= p_check_in
+          AND date < p_check_out
+          AND hold_expiry > NOW()
+        GROUP BY room_type_id, date
+    )
+    UPDATE room_inventory ri
+    SET tentative_count = GREATEST(0, tentative_count - rh.hold_count),
+        updated_at = NOW()
+    FROM replaced_holds rh
+    WHERE ri.room_type_id = rh.room_type_id
+      AND ri.date = rh.date;
+    
+    -- ลบ hold เก่าที่ซ้ำกับวันที่ใหม่
+    DELETE FROM booking_holds
+    WHERE ((session_id = p_session_id AND room_type_id = p_room_type_id)
+           OR (guest_account_id = p_guest_account_id AND session_id <> p_session_id))
+      AND date >= p_check_in
+      AND date < p_check_out
+      AND hold_expiry > NOW();
+    
+    -- ============================================================================
+    -- STEP 2: ตรวจสอบห้องว่างและอัปเดต tentative_count แบบ atomic
+    -- ============================================================================
+    v_date := p_check_in;
+    
+    WHILE v_date < p_check_out LOOP
+        -- ตรวจสอบและ lock row สำหรับวันนี้
+        SELECT (allotment + overbooking_rooms(allotment, overbooking_type, overbooking_value)
+                - booked_count - tentative_count) INTO v_available
+        FROM room_inventory
+        WHERE room_type_id = p_room_type_id 
+          AND date = v_date
+        FOR UPDATE; -- Lock row เพื่อป้องกัน race condition
+        
+        -- ถ้าไม่มี inventory record สำหรับวันนี้
+        IF v_available IS NULL THEN
+            RETURN QUERY SELECT 
+                FALSE AS success, 
+                FORMAT('ไม่พบข้อมูล inventory สำหรับ %s วันที่ %s', v_room_type_name, v_date::TEXT)::TEXT AS message,
+                NULL::TIMESTAMP AS expiry_time;
+            RETURN;
+        END IF;
+        
+        -- ถ้าห้องว่างไม่พอ
+        IF v_available < p_rooms THEN
+            RETURN QUERY SELECT 
+                FALSE AS success, 
+                FORMAT('ห้อง %s ไม่ว่างสำหรับวันที่ %s กรุณาเลือกห้องอื่นหรือวันที่อื่น', 
+                       v_room_type_name, v_date::TEXT)::TEXT AS message,
+                NULL::TIMESTAMP AS expiry_time;
+            RETURN;
+        END IF;
+        
+        -- อัปเดต tentative_count (เพิ่มตามจำนวนห้อง)
+        UPDATE room_inventory
+        SET tentative_count = tentative_count + p_rooms,
+            updated_at = NOW()
+        WHERE room_type_id = p_room_type_id 
+          AND date = v_date;
+        
+        v_date := v_date + INTERVAL '1 day';
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 3: สร้าง booking hold records
+    -- ============================================================================
+    v_date := p_check_in;
+    
+    WHILE v_date < p_check_out LOOP
+        -- หนึ่ง record ต่อห้องต่อคืน
+        INSERT INTO booking_holds (
+            session_id,
+            guest_account_id,
+            room_type_id,
+            date,
+            hold_expiry
+        )
+        SELECT
+            p_session_id,
+            p_guest_account_id,
+            p_room_type_id,
+            v_date,
+            v_hold_expiry
+        FROM generate_series(1, p_rooms);
+        
+        v_date := v_date + INTERVAL '1 day';
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 4: Return success
+    -- ============================================================================
+    RETURN QUERY SELECT 
+        TRUE AS success, 
+        FORMAT('สร้าง hold สำเร็จสำหรับ %s %s ห้อง (%s คืน) หมดอายุเวลา %s', 
+               v_room_type_name, p_rooms, v_nights, TO_CHAR(v_hold_expiry, 'HH24:MI:SS'))::TEXT AS message,
+        v_hold_expiry AS expiry_time;
+    
+EXCEPTION
+    WHEN OTHERS THEN
+        -- จัดการ error ที่ไม่คาดคิด
+        RETURN QUERY SELECT 
+            FALSE AS success, 
+            FORMAT('เกิดข้อผิดพลาด: %s', SQLERRM)::TEXT AS message,
+            NULL::TIMESTAMP AS expiry_time;
+END;
+$$;
+
+COMMENT ON FUNCTION create_booking_hold IS 
+'สร้างการจองห้องชั่วคราว (hold) สำหรับ 15 นาที
+- ตรวจสอบห้องว่างแบบ atomic ด้วย FOR UPDATE (รวมจำนวนห้องที่ขายเกินได้)
+- ปล่อย hold เก่าจาก session อื่นที่ซ้ำกับวันที่ใหม่อัตโนมัติ
+- session เดียวกัน hold ได้หลายห้อง (จองหลายห้องในการจองเดียว)
+- p_rooms: จำนวนห้องประเภทเดียวกันที่ hold พร้อมกัน hold ใหม่ของประเภทห้องเดิมใน session เดียวกันแทนที่ของเก่า
+- Rollback ทั้งหมดถ้าห้องไม่ว่างในวันใดวันหนึ่ง
+- ป้องกัน race condition ด้วย row-level locking';
+
+-- ============================================================================
+-- confirm_booking: confirm rooms up to the overbooking limit
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION confirm_booking(
+    p_booking_id INT
+) RETURNS TABLE(
+    success BOOLEAN,
+    message TEXT,
+    booking_id INT
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_status VARCHAR(50);
+    v_guest_id INT;
+    v_guest_account_id INT;
+    v_detail RECORD;
+    v_date DATE;
+    v_available INT;
+    v_allotment INT;
+    v_capacity INT;
+    v_booked_count INT;
+    v_tentative_count INT;
+    v_total_nights INT := 0;
+    v_policy_name VARCHAR(100);
+    v_policy_description TEXT;
+    v_rate_tier_id INT;
+    v_price DECIMAL(10, 2);
+    v_existing_log_count INT;
+    v_held_room_types INT[] := '{}';
+    v_held_dates DATE[] := '{}';
+BEGIN
+    -- ============================================================================
+    -- STEP 1: ตรวจสอบสถานะการจอง
+    -- ============================================================================
+    SELECT b.status, b.guest_id INTO v_status, v_guest_id
+    FROM bookings b
+    WHERE b.booking_id = p_booking_id
+    FOR UPDATE;
+    
+    IF v_status IS NULL THEN
+        RETURN QUERY SELECT 
+            FALSE::BOOLEAN, 
+            'ไม่พบการจองนี้'::TEXT,
+            NULL::INT;
+        RETURN;
+    END IF;
+    
+    IF v_status != 'PendingPayment' THEN
+        RETURN QUERY SELECT 
+            FALSE::BOOLEAN, 
+            FORMAT('ไม่สามารถยืนยันการจองได้ สถานะปัจจุบัน: %s (ต้องเป็น PendingPayment)', v_status)::TEXT,
+            NULL::INT;
+        RETURN;
+    END IF;
+    
+    SELECT ga.guest_account_id INTO v_guest_account_id
+    FROM guest_accounts ga
+    WHERE ga.guest_id = v_guest_id;
+    
+    -- ============================================================================
+    -- STEP 2: ตรวจสอบห้องว่างและอัปเดต inventory
+    -- ============================================================================
+    FOR v_detail IN 
+        SELECT 
+            bd.booking_detail_id,
+            bd.room_type_id,
+            bd.rate_plan_id,
+            bd.check_in_date,
+            bd.check_out_date,
+            rt.name as room_type_name
+        FROM booking_details bd
+        JOIN room_types rt ON bd.room_type_id = rt.room_type_id
+        WHERE bd.booking_id = p_booking_id
+        ORDER BY bd.booking_detail_id
+    LOOP
+        v_date := v_detail.check_in_date;
+        
+        WHILE v_date < v_detail.check_out_date LOOP
+            -- ดึงข้อมูล inventory พร้อม lock
+            SELECT 
+                allotment,
+                allotment + overbooking_rooms(allotment, overbooking_type, overbooking_value),
+                booked_count,
+                tentative_count,
+                (allotment - booked_count - tentative_count)
+            INTO 
+                v_allotment,
+                v_capacity,
+                v_booked_count,
+                v_tentative_count,
+                v_available
+            FROM room_inventory
+            WHERE room_type_id = v_detail.room_type_id 
+              AND date = v_date
+            FOR UPDATE;
+            
+            IF v_allotment IS NULL THEN
+                RETURN QUERY SELECT 
+                    FALSE::BOOLEAN, 
+                    FORMAT('ไม่พบข้อมูล inventory สำหรับ %s วันที่ %s', 
+                           v_detail.room_type_name, v_date::TEXT)::TEXT,
+                    NULL::INT;
+                RETURN;
+            END IF;
+            
+            -- ตรวจสอบว่ามีที่ว่างพอหรือไม่ (หลังจาก confirm แล้ว)
+            -- ถ้า tentative_count > 0 แสดงว่ามี hold อยู่ ให้ลด tentative และเพิ่ม booked
+            -- ถ้า tentative_count = 0 แสดงว่าไม่มี hold ต้องตรวจสอบว่ามีที่ว่างพอ
+            IF v_tentative_count > 0 THEN
+                -- มี hold อยู่ ให้ย้ายจาก tentative ไป booked
+                UPDATE room_inventory
+                SET booked_count = booked_count + 1,
+                    tentative_count = tentative_count - 1,
+                    updated_at = NOW()
+                WHERE room_type_id = v_detail.room_type_id 
+                  AND date = v_date;
+                
+                -- จำคืนที่ใช้ห้องจาก tentative ไว้ ลบ hold ใน STEP 6 จะได้ไม่ลดซ้ำ
+                v_held_room_types := v_held_room_types || v_detail.room_type_id;
+                v_held_dates := v_held_dates || v_date;
+            ELSE
+                -- ไม่มี hold ต้องตรวจสอบว่ามีที่ว่างพอ (รวมจำนวนห้องที่ขายเกินได้)
+                IF v_booked_count >= v_capacity THEN
+                    RETURN QUERY SELECT 
+                        FALSE::BOOLEAN, 
+                        FORMAT('ห้อง %s เต็มแล้วสำหรับวันที่ %s (Booked: %s/%s)', 
+                               v_detail.room_type_name, v_date::TEXT, v_booked_count, v_capacity)::TEXT,
+                        NULL::INT;
+                    RETURN;
+                END IF;
+                
+                -- มีที่ว่างพอ ให้เพิ่ม booked_count
+                UPDATE room_inventory
+                SET booked_count = booked_count + 1,
+                    updated_at = NOW()
+                WHERE room_type_id = v_detail.room_type_id 
+                  AND date = v_date;
+            END IF;
+            
+            -- ============================================================================
+            -- STEP 3: บันทึก nightly log (ถ้ายังไม่มี)
+            -- ============================================================================
+            SELECT COUNT(*) INTO v_existing_log_count
+            FROM booking_nightly_log
+            WHERE booking_detail_id = v_detail.booking_detail_id
+              AND date = v_date;
+            
+            IF v_existing_log_count = 0 THEN
+                SELECT pc.rate_tier_id INTO v_rate_tier_id
+                FROM pricing_calendar pc
+                WHERE pc.date = v_date;
+                
+                IF v_rate_tier_id IS NULL THEN
+                    SELECT rate_tier_id INTO v_rate_tier_id
+                    FROM rate_tiers
+                    ORDER BY rate_tier_id
+                    LIMIT 1;
+                END IF;
+                
+                SELECT rp.price INTO v_price
+                FROM rate_pricing rp
+                WHERE rp.rate_plan_id = v_detail.rate_plan_id
+                  AND rp.room_type_id = v_detail.room_type_id
+                  AND rp.rate_tier_id = v_rate_tier_id;
+                
+                IF v_price IS NULL THEN
+                    v_price := 0;
+                END IF;
+                
+                INSERT INTO booking_nightly_log (
+                    booking_detail_id,
+                    date,
+                    quoted_price
+                ) VALUES (
+                    v_detail.booking_detail_id,
+                    v_date,
+                    v_price
+                );
+            END IF;
+            
+            v_total_nights := v_total_nights + 1;
+            v_date := v_date + INTERVAL '1 day';
+        END LOOP;
+    END LOOP;
+    
+    -- ============================================================================
+    -- STEP 4: บันทึก policy snapshot
+    -- ============================================================================
+    SELECT cp.name, cp.description 
+    INTO v_policy_name, v_policy_description
+    FROM booking_details bd
+    JOIN rate_plans rp ON bd.rate_plan_id = rp.rate_plan_id
+    JOIN cancellation_policies cp ON rp.policy_id = cp.policy_id
+    WHERE bd.booking_id = p_booking_id
+    LIMIT 1;
+    
+    IF v_policy_name IS NULL THEN
+        v_policy_name := 'No Refund';
+        v_policy_description := 'ไม่สามารถยกเลิกหรือคืนเงินได้';
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 5: อัปเดตสถานะเป็น Confirmed
+    -- ============================================================================
+    UPDATE bookings
+    SET status = 'Confirmed',
+        policy_name = v_policy_name,
+        policy_description = v_policy_description,
+        updated_at = NOW()
+    WHERE booking_id = p_booking_id;
+    
+    -- ============================================================================
+    -- STEP 6: ลบ booking holds
+    -- ============================================================================
+    -- hold ที่ไม่ได้ถูกใช้ใน STEP 2 (เช่น ห้องที่ hold ไว้แต่ไม่ได้จอง) ต้องคืน
+    -- tentative_count ด้วย ทุกแถวที่ลบ ไม่ใช่เฉพาะห้องที่ยืนยัน
+    IF v_guest_account_id IS NOT NULL THEN
+        WITH deleted_holds AS (
+            SELECT
+                room_type_id,
+                date,
+                COUNT(*) as hold_count
+            FROM booking_holds
+            WHERE guest_account_id = v_guest_account_id
+            GROUP BY room_type_id, date
+        ),
+        converted_holds AS (
+            SELECT
+                h.room_type_id,
+                h.date,
+                COUNT(*) as converted_count
+            FROM unnest(v_held_room_types, v_held_dates) AS h(room_type_id, date)
+            GROUP BY h.room_type_id, h.date
+        )
+        UPDATE room_inventory ri
+        SET tentative_count = GREATEST(0, ri.tentative_count - (dh.hold_count - COALESCE(ch.converted_count, 0))),
+            updated_at = NOW()
+        FROM deleted_holds dh
+        LEFT JOIN converted_holds ch
+          ON ch.room_type_id = dh.room_type_id
+         AND ch.date = dh.date
+        WHERE ri.room_type_id = dh.room_type_id
+          AND ri.date = dh.date
+          AND dh.hold_count > COALESCE(ch.converted_count, 0);
+        
+        DELETE FROM booking_holds
+        WHERE guest_account_id = v_guest_account_id;
+    END IF;
+    
+    -- ============================================================================
+    -- STEP 7: Return success
+    -- ============================================================================
+    RETURN QUERY SELECT 
+        TRUE::BOOLEAN, 
+        FORMAT('ยืนยันการจองสำเร็จ (Booking ID: %s, %s คืน)', 
+               p_booking_id, v_total_nights)::TEXT,
+        p_booking_id::INT;
+    
+EXCEPTION
+    WHEN OTHERS THEN
+        RETURN QUERY SELECT 
+            FALSE::BOOLEAN, 
+            FORMAT('เกิดข้อผิดพลาดในการยืนยันการจอง: %s', SQLERRM)::TEXT,
+            NULL::INT;
+END;
+$$;
+
+COMMENT ON FUNCTION confirm_booking IS 
+'ยืนยันการจองและอัปเดตสถานะเป็น Confirmed (ตรวจสอบห้องว่างรวมจำนวนห้องที่ขายเกินได้)';
+
+-- ============================================================================
+-- match_waitlist: offer waitlisted guests rooms within the allotment only
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION match_waitlist(
+    p_offer_minutes INT DEFAULT 60
+) RETURNS TABLE(
+    event VARCHAR(20),
+    waitlist_id INT,
+    session_id VARCHAR(255),
+    offer_expires_at TIMESTAMP
+) LANGUAGE plpgsql AS $$
+DECLARE
+    v_entry RECORD;
+    v_session VARCHAR(255);
+    v_success BOOLEAN;
+    v_message TEXT;
+    v_expiry TIMESTAMP;
+    v_free_nights INT;
+BEGIN
+    -- ------------------------------------------------------------------------
+    -- STEP 1: หมดอายุข้อเสนอที่ไม่ได้ใช้ และคืน tentative_count
+    -- ------------------------------------------------------------------------
+    FOR v_entry IN
+        UPDATE waitlist_entries w
+        SET status = 'Expired'
+        WHERE w.status = 'Offered'
+          AND w.offer_expires_at < NOW()
+        RETURNING w.waitlist_id, w.session_id
+    LOOP
+        UPDATE room_inventory ri
+        SET tentative_count = GREATEST(0, ri.tentative_count - h.hold_count),
+            updated_at = NOW()
+        FROM (
+            SELECT bh.room_type_id, bh.date, COUNT(*) AS hold_count
+            FROM booking_holds bh
+            WHERE bh.session_id = v_entry.session_id
+            GROUP BY bh.room_type_id, bh.date
+        ) h
+        WHERE ri.room_type_id = h.room_type_id
+          AND ri.date = h.date;
+
+        DELETE FROM booking_holds bh WHERE bh.session_id = v_entry.session_id;
+
+        RETURN QUERY SELECT 'Expired'::VARCHAR(20), v_entry.waitlist_id, v_entry.session_id, NULL::TIMESTAMP;
+    END LOOP;
+
+    -- ------------------------------------------------------------------------
+    -- STEP 2: รายการที่วันเช็คอินผ่านไปแล้ว
+    -- ------------------------------------------------------------------------
+    FOR v_entry IN
+        UPDATE waitlist_entries w
+        SET status = 'Expired'
+        WHERE w.status = 'Waiting'
+          AND w.check_in_date < CURRENT_DATE
+        RETURNING w.waitlist_id
+    LOOP
+        RETURN QUERY SELECT 'Lapsed'::VARCHAR(20), v_entry.waitlist_id, NULL::VARCHAR(255), NULL::TIMESTAMP;
+    END LOOP;
+
+    -- ------------------------------------------------------------------------
+    -- STEP 3: เสนอห้องให้ผู้รอตามลำดับ (FIFO) เมื่อทุกคืนมีห้องว่าง
+    -- ------------------------------------------------------------------------
+    FOR v_entry IN
+        SELECT w.waitlist_id, w.room_type_id, w.check_in_date, w.check_out_date, w.offer_count
+        FROM waitlist_entries w
+        WHERE w.status = 'Waiting'
+          AND NOT EXISTS (
+              SELECT 1
+              FROM generate_series(w.check_in_date, w.check_out_date - 1, INTERVAL '1 day') AS d(day)
+              LEFT JOIN room_inventory ri
+                ON ri.room_type_id = w.room_type_id AND ri.date = d.day::DATE
+              WHERE ri.date IS NULL
+                 OR ri.allotment - ri.booked_count - ri.tentative_count <= 0
+          )
+        ORDER BY w.created_at, w.waitlist_id
+    LOOP
+        -- session ของข้อเสนอเป็น token สุ่ม เดาจาก waitlist_id ไม่ได้
+        v_session := 'waitlist-' || gen_random_uuid()::TEXT;
+
+        -- create_booking_hold ไม่ rollback tentative_count ของคืนก่อนหน้าเมื่อคืนใดเต็ม
+        -- จึงรันใน sub-transaction และ rollback เองเมื่อไม่สำเร็จ
+        BEGIN
+            -- ผู้รอได้เฉพาะห้องที่ว่างใน allotment: create_booking_hold ขายเกิน allotment
+            -- ได้ถึง overbooking_rooms() จึงตรวจซ้ำทุกคืนพร้อม lock ก่อนสร้าง hold
+            SELECT COUNT(*) INTO v_free_nights
+            FROM (
+                SELECT ri.allotment - ri.booked_count - ri.tentative_count AS free_rooms
+                FROM room_inventory ri
+                WHERE ri.room_type_id = v_entry.room_type_id
+                  AND ri.date >= v_entry.check_in_date
+                  AND ri.date < v_entry.check_out_date
+                ORDER BY ri.date
+                FOR UPDATE
+            ) n
+            WHERE n.free_rooms > 0;
+
+            IF v_free_nights < v_entry.check_out_date - v_entry.check_in_date THEN
+                RAISE EXCEPTION 'waitlist hold failed: no room left in the allotment';
+            END IF;
+
+            SELECT h.success, h.message, h.expiry_time
+            INTO v_success, v_message, v_expiry
+            FROM create_booking_hold(v_session, NULL, v_entry.room_type_id,
+                                     v_entry.check_in_date, v_entry.check_out_date) h;
+
+            IF NOT v_success THEN
+                RAISE EXCEPTION 'waitlist hold failed: %', v_message;
+            END IF;
+        EXCEPTION
+            WHEN OTHERS THEN
+                v_success := FALSE;
+        END;
+
+        -- ห้องอาจถูกเสนอให้ผู้รอก่อนหน้าไปแล้วในรอบนี้
+        CONTINUE WHEN NOT v_success;
+
+        v_expiry := NOW() + (p_offer_minutes || ' minutes')::INTERVAL;
+
+        UPDATE booking_holds bh
+        SET hold_expiry = v_expiry
+        WHERE bh.session_id = v_session;
+
+        UPDATE waitlist_entries w
+        SET status = 'Offered',
+            session_id = v_session,
+            offered_at = NOW(),
+            offer_expires_at = v_expiry,
+            offer_count = w.offer_count + 1
+        WHERE w.waitlist_id = v_entry.waitlist_id;
+
+        RETURN QUERY SELECT 'Offered'::VARCHAR(20), v_entry.waitlist_id, v_session, v_expiry;
+    END LOOP;
+END;
+$$;
+
+COMMENT ON FUNCTION match_waitlist IS
+'จับคู่ waitlist กับ inventory ที่ว่าง
+- หมดอายุข้อเสนอที่ไม่ได้ใช้และคืนสต็อก
+- สร้าง hold ให้ผู้รอคนถัดไป (FIFO) พร้อมกำหนดเวลาหมดอายุของข้อเสนอ
+- เสนอเฉพาะห้องที่ว่างใน allotment ไม่ขายเกินตาม overbooking limit
+- ควรถูกเรียกโดย background job เมื่อมีการยกเลิก, hold หมดอายุ หรือเพิ่ม allotment';
+
+-- ============================================================================
+-- partner_hotels
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS partner_hotels (
+    partner_hotel_id SERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    address TEXT,
+    phone VARCHAR(50),
+    contact_name VARCHAR(100),
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE partner_hotels IS 'โรงแรมพันธมิตรที่รับแขกเมื่อห้องถูกขายเกิน';
+
+DROP TRIGGER IF EXISTS update_partner_hotels_updated_at ON partner_hotels;
+CREATE TRIGGER update_partner_hotels_updated_at
+    BEFORE UPDATE ON partner_hotels
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- booking_walks
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS booking_walks (
+    walk_id SERIAL PRIMARY KEY,
+    booking_detail_id INT NOT NULL REFERENCES booking_details(booking_detail_id) ON DELETE CASCADE,
+    partner_hotel_id INT NOT NULL REFERENCES partner_hotels(partner_hotel_id) ON DELETE RESTRICT,
+    walk_date DATE NOT NULL,
+    nights INT NOT NULL CHECK (nights > 0),
+    full_stay BOOLEAN NOT NULL DEFAULT FALSE,
+    waived_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (waived_amount >= 0),
+    compensation_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (compensation_amount >= 0),
+    compensation_notes TEXT,
+    transport_provided BOOLEAN NOT NULL DEFAULT FALSE,
+    notes TEXT,
+    walked_by INT REFERENCES staff(staff_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_walks_detail ON booking_walks(booking_detail_id);
+CREATE INDEX IF NOT EXISTS idx_booking_walks_date ON booking_walks(walk_date);
+
+COMMENT ON TABLE booking_walks IS 'การย้ายแขกไปพักโรงแรมพันธมิตรเมื่อห้องถูกขายเกิน';
+COMMENT ON COLUMN booking_walks.walk_date IS 'คืนแรกที่แขกไปพักโรงแรมพันธมิตร (วันเช็คอินเดิม)';
+COMMENT ON COLUMN booking_walks.nights IS 'จำนวนคืนที่แขกพักโรงแรมพันธมิตร';
+COMMENT ON COLUMN booking_walks.full_stay IS 'แขกพักโรงแรมพันธมิตรตลอดการเข้าพัก (การจองถูกยกเลิก)';
+COMMENT ON COLUMN booking_walks.waived_amount IS 'ค่าห้องของคืนที่ย้ายไปซึ่งหักออกจาก bookings.total_amount';
+COMMENT ON COLUMN booking_walks.compensation_amount IS 'ค่าชดเชยที่โรงแรมจ่าย (ค่าห้องโรงแรมพันธมิตร ค่าเดินทาง ฯลฯ)';
+
+\echo 'Migration 043 completed: overbooking limits and walked guests added'